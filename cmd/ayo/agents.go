@@ -1,19 +1,33 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
 	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/builtin"
 	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
 	"github.com/alexcabrera/ayo/internal/skills"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 func newAgentsCmd(cfgPath *string) *cobra.Command {
@@ -46,6 +60,9 @@ For help designing agents, chat with @ayo:
 	cmd.AddCommand(createAgentCmd(cfgPath))
 	cmd.AddCommand(showAgentCmd(cfgPath))
 	cmd.AddCommand(updateAgentsCmd(cfgPath))
+	cmd.AddCommand(renameAgentCmd(cfgPath))
+	cmd.AddCommand(agentSchemaCmd(cfgPath))
+	cmd.AddCommand(runBatchAgentCmd(cfgPath))
 
 	return cmd
 }
@@ -67,17 +84,17 @@ func listAgentsCmd(cfgPath *string) *cobra.Command {
 				}
 
 				// Color palette
-				purple := lipgloss.Color("#a78bfa")
-				cyan := lipgloss.Color("#67e8f9")
-				muted := lipgloss.Color("#6b7280")
-				text := lipgloss.Color("#e5e7eb")
-				subtle := lipgloss.Color("#374151")
+				primary := shared.ColorPrimary
+				secondary := shared.ColorSecondary
+				muted := shared.ColorMuted
+				text := shared.ColorText
+				subtle := shared.ColorSubtle
 
 				// Styles
-				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
+				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
 				sectionStyle := lipgloss.NewStyle().Foreground(muted).Bold(true)
-				iconStyle := lipgloss.NewStyle().Foreground(cyan)
-				handleStyle := lipgloss.NewStyle().Foreground(cyan).Bold(true)
+				iconStyle := lipgloss.NewStyle().Foreground(secondary)
+				handleStyle := lipgloss.NewStyle().Foreground(secondary).Bold(true)
 				descStyle := lipgloss.NewStyle().Foreground(text)
 				countStyle := lipgloss.NewStyle().Foreground(muted)
 				dividerStyle := lipgloss.NewStyle().Foreground(subtle)
@@ -189,11 +206,15 @@ func createAgentCmd(cfgPath *string) *cobra.Command {
 		ignoreSharedSkills  bool
 
 		// Chaining
-		inputSchema  string
-		outputSchema string
+		inputSchema       string
+		outputSchema      string
+		interactiveSchema bool
 
 		// Guardrails
 		noGuardrails bool
+
+		// Start from an existing agent
+		clone string
 	)
 
 	cmd := &cobra.Command{
@@ -228,7 +249,17 @@ Examples:
     -m gpt-5.2 \
     -f system.md \
     --input-schema input.jsonschema \
-    --output-schema output.jsonschema`,
+    --output-schema output.jsonschema
+
+  # Build the output schema field by field
+  ayo agents create @triage -m gpt-5.2 --interactive-schema
+
+  # Copy an existing agent, changing only its model
+  ayo agents create @reviewer-fast --clone @reviewer -m gpt-5.2-mini
+
+With --clone, the new agent starts as a copy of the source agent: its config,
+system prompt, schemas, agent-local skills, and other files are copied into
+the new agent's directory. Any other flags override the copied values.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
@@ -255,6 +286,42 @@ Examples:
 					return fmt.Errorf("agent already exists: %s", handle)
 				}
 
+				// Pre-populate from the cloned agent where flags are unset
+				var src *agent.CloneSource
+				if clone != "" {
+					s, err := agent.LoadCloneSource(cfg, clone)
+					if err != nil {
+						return fmt.Errorf("clone %s: %w", clone, err)
+					}
+					src = &s
+
+					flags := cmd.Flags()
+					if model == "" {
+						model = src.Config.Model
+					}
+					if system == "" && systemFile == "" {
+						system = src.System
+					}
+					if !flags.Changed("description") {
+						description = src.Config.Description
+					}
+					if !flags.Changed("tools") {
+						tools = src.Config.AllowedTools
+					}
+					if !flags.Changed("skills") {
+						skills_ = src.Config.Skills
+					}
+					if !flags.Changed("exclude-skills") {
+						excludeSkills = src.Config.ExcludeSkills
+					}
+					if !flags.Changed("ignore-builtin-skills") {
+						ignoreBuiltinSkills = src.Config.IgnoreBuiltinSkills
+					}
+					if !flags.Changed("ignore-shared-skills") {
+						ignoreSharedSkills = src.Config.IgnoreSharedSkills
+					}
+				}
+
 				// Load system from file if specified
 				if system == "" && systemFile != "" {
 					expanded := expandPath(systemFile)
@@ -280,21 +347,44 @@ Examples:
 					return fmt.Errorf("model is required (use -m or configure default_model in ayo.json)")
 				}
 
-				// Validate model if we have a configured set
-				if len(modelSet) > 0 {
+				// Validate model if we have a configured set. A cloned
+				// model may be an unexpanded ${VAR} reference.
+				if len(modelSet) > 0 && (src == nil || model != src.Config.Model) {
 					if _, ok := modelSet[model]; !ok {
 						return fmt.Errorf("model %s is not configured", model)
 					}
 				}
 
+				// Build the output schema before anything is written, so
+				// cancelling leaves no agent behind
+				if interactiveSchema {
+					if outputSchema != "" {
+						return fmt.Errorf("cannot use --interactive-schema with --output-schema")
+					}
+					built, err := buildSchemaInteractively()
+					if err != nil {
+						return err
+					}
+					if built == nil {
+						fmt.Println("Cancelled.")
+						return nil
+					}
+					outputSchema, err = writeBuiltSchema(built)
+					if err != nil {
+						return err
+					}
+					defer os.Remove(outputSchema)
+				}
+
 				// Default tools
-				if len(tools) == 0 {
+				if len(tools) == 0 && src == nil {
 					tools = []string{"bash"}
 				}
 
-				// Merge required skills based on selected tools
+				// Merge required skills based on selected tools. A clone
+				// keeps its source's skills unless the tools change.
 				requiredSkills := skills.GetRequiredSkillsForTools(tools)
-				if len(requiredSkills) > 0 {
+				if len(requiredSkills) > 0 && (src == nil || cmd.Flags().Changed("tools")) {
 					skillSet := make(map[string]struct{}, len(skills_))
 					for _, s := range skills_ {
 						skillSet[s] = struct{}{}
@@ -306,24 +396,45 @@ Examples:
 					}
 				}
 
-				agCfg := agent.Config{
-					Model:               model,
-					Description:         description,
-					AllowedTools:        tools,
-					Guardrails:          boolPtr(!noGuardrails),
-					Skills:              skills_,
-					ExcludeSkills:       excludeSkills,
-					IgnoreBuiltinSkills: ignoreBuiltinSkills,
-					IgnoreSharedSkills:  ignoreSharedSkills,
+				agCfg := agent.Config{Guardrails: boolPtr(!noGuardrails)}
+				if src != nil {
+					// Keep the source's memory, delegation, and other settings
+					agCfg = src.Config
+					if cmd.Flags().Changed("no-guardrails") {
+						agCfg.Guardrails = boolPtr(!noGuardrails)
+					}
+				}
+				agCfg.Model = model
+				agCfg.Description = description
+				agCfg.AllowedTools = tools
+				agCfg.Skills = skills_
+				agCfg.ExcludeSkills = excludeSkills
+				agCfg.IgnoreBuiltinSkills = ignoreBuiltinSkills
+				agCfg.IgnoreSharedSkills = ignoreSharedSkills
+
+				// Copy the source's files first; config, prompt, and any
+				// schema flags are written over them
+				if src != nil {
+					if err := src.CopyFiles(agentDir); err != nil {
+						os.RemoveAll(agentDir)
+						return fmt.Errorf("clone %s: %w", src.Handle, err)
+					}
 				}
 
 				ag, err := agent.SaveWithSchemas(cfg, handle, agCfg, system, inputSchema, outputSchema)
 				if err != nil {
+					if src != nil {
+						os.RemoveAll(agentDir)
+					}
 					return err
 				}
 
 				successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-				fmt.Println(successStyle.Render("Created agent: " + ag.Handle))
+				created := "Created agent: " + ag.Handle
+				if src != nil {
+					created += " (cloned from " + src.Handle + ")"
+				}
+				fmt.Println(successStyle.Render(created))
 				fmt.Printf("  Location: %s\n", ag.Dir)
 
 				// Show what was configured (from config, not resolved)
@@ -360,10 +471,14 @@ Examples:
 	// Schema flags for chaining
 	cmd.Flags().StringVar(&inputSchema, "input-schema", "", "JSON schema file for validating stdin input")
 	cmd.Flags().StringVar(&outputSchema, "output-schema", "", "JSON schema file for structuring stdout output")
+	cmd.Flags().BoolVar(&interactiveSchema, "interactive-schema", false, "build the output schema field by field in a form")
 
 	// Guardrails
 	cmd.Flags().BoolVar(&noGuardrails, "no-guardrails", false, "disable safety guardrails (dangerous - use with caution)")
 
+	// Cloning
+	cmd.Flags().StringVar(&clone, "clone", "", "copy an existing agent's config, prompt, schemas, and skills")
+
 	return cmd
 }
 
@@ -384,14 +499,114 @@ func expandPath(path string) string {
 	return path
 }
 
+// showAgentViews are the flags that select each alternative view of agents
+// show, in the order the view's flags are reported.
+var showAgentViews = [][]string{
+	{"resolved", "with-memory", "with-skills"},
+	{"skills"},
+	{"chain"},
+	{"cost-estimate", "sample"},
+	{"usage"},
+}
+
+// checkShowAgentViews rejects flags that select more than one view, since
+// only one is printed.
+func checkShowAgentViews(cmd *cobra.Command) error {
+	var set []string
+	for _, flags := range showAgentViews {
+		for _, name := range flags {
+			if cmd.Flags().Changed(name) {
+				set = append(set, "--"+name)
+				break
+			}
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("the %s flags can't be used together", strings.Join(set, " and "))
+	}
+	return nil
+}
+
 func showAgentCmd(cfgPath *string) *cobra.Command {
+	var resolved bool
+	var memoryQuery string
+	var skillsQuery string
+	var showUsage bool
+	var showSkills bool
+	var showChain bool
+	var costEstimate bool
+	var sample string
+	var jsonOutput bool
+
 	cmd := &cobra.Command{
 		Use:   "show <handle>",
 		Short: "Show agent details",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show agent details.
+
+With --json, print the agent as it is run: the resolved model (the agent's
+or the default model), allowed tools, active skills with their sources,
+input and output schemas, delegates merged with the ayo config's, memory
+settings, whether it is built in, and any warnings.
+
+With --resolved, print the system messages sent to the model, in order:
+the combined system prompt (environment, guardrails, prefix, agent system,
+suffix), followed by the tools, skills, delegate, and model context prompts.
+
+With --with-memory, also inject the memories that would be retrieved for
+the given query.
+
+With --with-skills, list only the skills that would be selected for the
+given query when the agent has skill_selection enabled.
+
+With --usage, report the agent's token usage and cost per model over the
+last 30 days. Use "ayo usage --agent" for other time ranges.
+
+With --skills, list every skill the agent could use with its source
+(agent, user, built-in, or plugin), whether it is active, and why: listed
+in skills, excluded, hidden by an ignore flag, or not found. Tools that
+require a skill are shown, and skill warnings are printed at the end.
+Add --json for machine-readable output.
+
+With --chain, list the agents whose output this agent can receive and the
+agents that can receive its output, grouped by compatibility tier (exact,
+structural, freeform), followed by an example pipe command for the best
+match. Add --json for machine-readable output.
+
+With --cost-estimate, estimate the cost of one call from the tokens of the
+system prompts, the agent's context files, and a sample prompt (--sample,
+or a default), priced with the model's published rates for a typical
+completion of 200 to 1,500 tokens. Tool calls, memories, and follow-up
+turns add to the real cost. Add --json for machine-readable output.
+
+Only one of --resolved, --skills, --chain, --cost-estimate, and --usage can
+be used at a time.`,
+		Example: `  ayo agents show @ayo
+  ayo agents show @ayo --json
+  ayo agents show @ayo --skills
+  ayo agents show @ayo --chain
+  ayo agents show @ayo --usage
+  ayo agents show @ayo --cost-estimate
+  ayo agents show @ayo --cost-estimate --sample "Review this diff for bugs"
+  ayo agents show @ayo --resolved
+  ayo agents show @ayo --resolved --with-memory "deploy the app"
+  ayo agents show @ayo --resolved --with-skills "deploy the app"`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			handle := agent.NormalizeHandle(args[0])
 
+			if err := checkShowAgentViews(cmd); err != nil {
+				return err
+			}
+			if memoryQuery != "" || skillsQuery != "" {
+				resolved = true
+			}
+			if sample != "" {
+				costEstimate = true
+			}
+			if jsonOutput && (resolved || showUsage) {
+				return fmt.Errorf("the --json flag can't be used with --resolved or --usage")
+			}
+
 			return withConfig(cfgPath, func(cfg config.Config) error {
 				// Ensure builtins are installed
 				if err := builtin.Install(); err != nil {
@@ -403,16 +618,43 @@ func showAgentCmd(cfgPath *string) *cobra.Command {
 					return fmt.Errorf("agent not found: %s", handle)
 				}
 
+				if resolved {
+					return printResolvedPrompt(cmd.Context(), ag, memoryQuery, skillsQuery)
+				}
+
+				if showSkills {
+					return printSkillsReport(ag, jsonOutput)
+				}
+
+				if showChain {
+					return printAgentChain(cfg, ag, jsonOutput)
+				}
+
+				if costEstimate {
+					return printCostEstimate(cfg, ag, sample, jsonOutput)
+				}
+
+				if showUsage {
+					return runUsageReport(cmd.Context(), session.UsageFilter{
+						Since:       time.Now().AddDate(0, 0, -30),
+						AgentHandle: ag.Handle,
+					}, false)
+				}
+
+				if jsonOutput {
+					return writeJSON(agentToJSON(cfg, ag))
+				}
+
 				// Color palette
-				purple := lipgloss.Color("#a78bfa")
-				cyan := lipgloss.Color("#67e8f9")
-				muted := lipgloss.Color("#6b7280")
-				text := lipgloss.Color("#e5e7eb")
-				subtle := lipgloss.Color("#374151")
+				primary := shared.ColorPrimary
+				secondary := shared.ColorSecondary
+				muted := shared.ColorMuted
+				text := shared.ColorText
+				subtle := shared.ColorSubtle
 
 				// Styles
-				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
-				iconStyle := lipgloss.NewStyle().Foreground(cyan)
+				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
+				iconStyle := lipgloss.NewStyle().Foreground(secondary)
 				labelStyle := lipgloss.NewStyle().Foreground(muted)
 				valueStyle := lipgloss.NewStyle().Foreground(text)
 				dividerStyle := lipgloss.NewStyle().Foreground(subtle)
@@ -448,6 +690,13 @@ func showAgentCmd(cfgPath *string) *cobra.Command {
 					fmt.Printf("  %s  %s\n", labelStyle.Render("Tools:"), valueStyle.Render(strings.Join(ag.Config.AllowedTools, ", ")))
 				}
 
+				if len(ag.Config.ContextFiles) > 0 {
+					fmt.Printf("  %s %s\n", labelStyle.Render("Context:"), valueStyle.Render(strings.Join(ag.Config.ContextFiles, ", ")))
+				}
+				for _, w := range ag.ContextWarnings {
+					fmt.Printf("  %s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("Warning: "+w))
+				}
+
 				fmt.Println()
 
 				return nil
@@ -455,19 +704,607 @@ func showAgentCmd(cfgPath *string) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&resolved, "resolved", false, "Print the fully assembled prompts sent to the model")
+	cmd.Flags().StringVar(&memoryQuery, "with-memory", "", "Include memories retrieved for this query (implies --resolved)")
+	cmd.Flags().StringVar(&skillsQuery, "with-skills", "", "Include only the skills selected for this query (implies --resolved)")
+	cmd.Flags().BoolVar(&showUsage, "usage", false, "Report token usage and cost over the last 30 days")
+	cmd.Flags().BoolVar(&showSkills, "skills", false, "List every skill with its source and why it is or isn't active")
+	cmd.Flags().BoolVar(&showChain, "chain", false, "List agents that can feed into or receive output from this agent")
+	cmd.Flags().BoolVar(&costEstimate, "cost-estimate", false, "Estimate the cost of one call for a sample prompt")
+	cmd.Flags().StringVar(&sample, "sample", "", "Sample prompt for the cost estimate (implies --cost-estimate)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
 	return cmd
 }
 
+// printSkillsReport prints how each of ag's skills was resolved.
+func printSkillsReport(ag agent.Agent, jsonOutput bool) error {
+	report := ag.ResolveSkills()
+	if jsonOutput {
+		return writeJSON(report)
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	iconStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	activeStyle := lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+	nameStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	dividerStyle := lipgloss.NewStyle().Foreground(shared.ColorSubtle)
+	warnStyle := lipgloss.NewStyle().Foreground(shared.ColorTertiary)
+
+	fmt.Println()
+	fmt.Println("  " + iconStyle.Render("◆") + " " + headerStyle.Render(ag.Handle+" skills"))
+	fmt.Println(dividerStyle.Render("  " + strings.Repeat("─", 58)))
+
+	if len(report.Skills) == 0 {
+		fmt.Println("  " + mutedStyle.Render("No skills found."))
+	}
+	nameWidth := 0
+	for _, s := range report.Skills {
+		nameWidth = max(nameWidth, len(s.Name))
+	}
+	for _, s := range report.Skills {
+		marker := mutedStyle.Render("○")
+		if s.Active {
+			marker = activeStyle.Render("●")
+		}
+		source := s.Source
+		if source == "" {
+			source = "-"
+		}
+		detail := s.Reason
+		if len(s.RequiredBy) > 0 {
+			detail += "; required by " + strings.Join(s.RequiredBy, ", ")
+		}
+		fmt.Printf("  %s %s  %s  %s\n", marker,
+			nameStyle.Render(fmt.Sprintf("%-*s", nameWidth, s.Name)),
+			mutedStyle.Render(fmt.Sprintf("%-8s", source)),
+			mutedStyle.Render(detail))
+	}
+
+	if len(report.Warnings) > 0 {
+		fmt.Println()
+		for _, w := range report.Warnings {
+			fmt.Printf("  %s\n", warnStyle.Render("Warning: "+w))
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// costEstimateJSON is the --cost-estimate --json output.
+type costEstimateJSON struct {
+	Handle           string      `json:"handle"`
+	Model            string      `json:"model"`
+	Sample           string      `json:"sample"`
+	SystemTokens     int64       `json:"system_tokens"`
+	PromptTokens     int64       `json:"prompt_tokens"`
+	CompletionTokens [2]int64    `json:"completion_tokens"`
+	CostUSD          *[2]float64 `json:"cost_usd"` // null when the model has no published pricing
+	Estimate         bool        `json:"estimate"`
+}
+
+// printCostEstimate prints the estimated cost of one call to ag.
+func printCostEstimate(cfg config.Config, ag agent.Agent, sample string, jsonOutput bool) error {
+	if sample == "" {
+		sample = run.DefaultEstimatePrompt
+	}
+	est := run.EstimateCost(cfg.Provider, ag, sample)
+
+	if jsonOutput {
+		out := costEstimateJSON{
+			Handle:           ag.Handle,
+			Model:            est.Model,
+			Sample:           sample,
+			SystemTokens:     est.SystemTokens,
+			PromptTokens:     est.PromptTokens,
+			CompletionTokens: [2]int64{est.CompletionLow, est.CompletionHigh},
+			Estimate:         true,
+		}
+		if est.Priced {
+			out.CostUSD = &[2]float64{est.CostLowUSD, est.CostHighUSD}
+		}
+		return writeJSON(out)
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	iconStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	labelStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	valueStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	dividerStyle := lipgloss.NewStyle().Foreground(shared.ColorSubtle)
+
+	cost := "no published pricing for this model"
+	if est.Priced {
+		cost = fmt.Sprintf("$%.4f to $%.4f per call", est.CostLowUSD, est.CostHighUSD)
+	}
+
+	fmt.Println()
+	fmt.Println("  " + iconStyle.Render("◆") + " " + headerStyle.Render(ag.Handle) + labelStyle.Render("  estimated cost"))
+	fmt.Println(dividerStyle.Render("  " + strings.Repeat("─", 58)))
+	fmt.Printf("  %s      %s\n", labelStyle.Render("Model:"), valueStyle.Render(est.Model))
+	fmt.Printf("  %s     %s\n", labelStyle.Render("Prompt:"), valueStyle.Render(fmt.Sprintf("~%s tokens (%s system, %s sample)",
+		formatUsageCount(est.InputTokens()), formatUsageCount(est.SystemTokens), formatUsageCount(est.PromptTokens))))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Completion:"), valueStyle.Render(fmt.Sprintf("%s to %s tokens (typical)",
+		formatUsageCount(est.CompletionLow), formatUsageCount(est.CompletionHigh))))
+	fmt.Printf("  %s       %s\n", labelStyle.Render("Cost:"), valueStyle.Render(cost))
+	fmt.Println(dividerStyle.Render("  " + strings.Repeat("─", 58)))
+	fmt.Println("  " + labelStyle.Render("Estimate only: tokens are counted at 4 characters each. Tool calls,"))
+	fmt.Println("  " + labelStyle.Render("memories, and follow-up turns add to the real cost."))
+	fmt.Println()
+	return nil
+}
+
+// printResolvedPrompt prints each system message sent to the model for ag,
+// with a header per section. Section bodies are printed verbatim.
+func printResolvedPrompt(ctx context.Context, ag agent.Agent, memoryQuery, skillsQuery string) error {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+
+	systemPrompt := ag.CombinedSystem
+	var memoryNote string
+	if memoryQuery != "" {
+		section, note, err := resolveMemorySection(ctx, ag, memoryQuery)
+		if err != nil {
+			return err
+		}
+		memoryNote = note
+		if section != nil {
+			systemPrompt = agent.InjectMemoryContext(systemPrompt, section)
+		}
+	}
+
+	ag, skillsNote := resolveSkillSelection(ctx, ag, skillsQuery)
+
+	type section struct {
+		title string
+		body  string
+	}
+	sections := []section{
+		{"System", systemPrompt},
+		{"Tools", ag.ToolsPrompt},
+		{"Skills", ag.SkillsPrompt},
+		{"Delegates", ag.DelegateContext},
+	}
+	if ag.Model != "" {
+		sections = append(sections, section{"Model context", run.ModelContext(ag.Model)})
+	}
+
+	first := true
+	for _, s := range sections {
+		if strings.TrimSpace(s.body) == "" {
+			continue
+		}
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		fmt.Println(headerStyle.Render(fmt.Sprintf("===== %s =====", s.title)))
+		fmt.Println(s.body)
+	}
+
+	if len(ag.ContextFiles) > 0 {
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		fmt.Println(headerStyle.Render("===== Context files ====="))
+		for _, path := range ag.ContextFiles {
+			if info, err := os.Stat(path); err == nil {
+				fmt.Printf("%s (%d bytes)\n", path, info.Size())
+			} else {
+				fmt.Println(path)
+			}
+		}
+	}
+
+	if skillsNote != "" {
+		fmt.Println()
+		fmt.Println(mutedStyle.Render(skillsNote))
+	}
+
+	if memoryNote != "" {
+		fmt.Println()
+		fmt.Println(mutedStyle.Render(memoryNote))
+	}
+
+	return nil
+}
+
+// resolveSkillSelection applies the agent's skill selection to query and
+// returns the agent with the selected skills, plus a note describing the
+// selection. Without a query, the note only says whether selection is on.
+func resolveSkillSelection(ctx context.Context, ag agent.Agent, query string) (agent.Agent, string) {
+	if !ag.Config.SkillSelection.Enabled {
+		if query != "" {
+			return ag, fmt.Sprintf("Skill selection is disabled for %s; all %d skills are included.", ag.Handle, len(ag.Skills))
+		}
+		return ag, ""
+	}
+	if query == "" {
+		return ag, "Skill selection is enabled; use --with-skills to see the skills selected for a query."
+	}
+
+	// Fall back to all skills when Ollama is unavailable, as at run time
+	embedder, err := createEmbedder()
+	if err != nil {
+		embedder = nil
+	}
+	if embedder != nil {
+		defer embedder.Close()
+	}
+
+	selected, sel := ag.SelectSkills(ctx, embedder, query)
+	if !sel.Selected {
+		return selected, fmt.Sprintf("Including all %d skills: %s.", sel.Total, sel.Reason)
+	}
+	names := make([]string, len(sel.Skills))
+	for i, m := range sel.Skills {
+		names[i] = m.Name
+	}
+	return selected, fmt.Sprintf("Selected %d of %d skills for %q: %s", len(sel.Skills), sel.Total, query, strings.Join(names, ", "))
+}
+
+// resolveMemorySection returns the memory context that would be injected for
+// query, plus a note explaining why nothing was injected when applicable.
+func resolveMemorySection(ctx context.Context, ag agent.Agent, query string) (*agent.MemoryContext, string, error) {
+	if !ag.Config.Memory.Enabled {
+		return nil, fmt.Sprintf("Memory is disabled for %s; no memories would be injected.", ag.Handle), nil
+	}
+
+	dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer dbConn.Close()
+
+	// Fall back to keyword search when Ollama is unavailable
+	embedder, err := createEmbedder()
+	if err != nil {
+		embedder = nil
+	}
+	if embedder != nil {
+		defer embedder.Close()
+	}
+
+	cwd, _ := os.Getwd()
+	memCtx, err := agent.BuildMemoryContext(ctx, memory.NewService(queries, embedder), ag.Handle, cwd, query, ag.Config.Memory)
+	if err != nil {
+		return nil, "", fmt.Errorf("build memory context: %w", err)
+	}
+	if memCtx == nil {
+		return nil, fmt.Sprintf("No memories matched %q.", query), nil
+	}
+	return memCtx, "", nil
+}
+
+func renameAgentCmd(cfgPath *string) *cobra.Command {
+	var dryRun bool
+	var scanFlows bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename an agent",
+		Long: `Rename a user agent and update references to it.
+
+Moves the agent directory and updates delegate mappings that point at the
+old handle, in both the global config and other agents' configs.
+
+Flows are shell scripts, so they are not rewritten. Use --flows to list the
+lines that call the old handle and need manual fixing.
+
+Past sessions keep the old handle as a historical record.
+
+Examples:
+  # Preview the rename
+  ayo agents rename @helper @assistant --dry-run
+
+  # Rename and report flows that call the old handle
+  ayo agents rename @helper @assistant --flows`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				plan, err := agent.PlanRename(cfg, args[0], args[1])
+				if err != nil {
+					return err
+				}
+
+				var refs []flows.AgentReference
+				if scanFlows {
+					discovered, err := flows.Discover(paths.FlowsDirs())
+					if err != nil {
+						return fmt.Errorf("discover flows: %w", err)
+					}
+					refs, err = flows.FindAgentReferences(discovered, plan.OldHandle)
+					if err != nil {
+						return err
+					}
+				}
+
+				mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+				warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+				if dryRun {
+					fmt.Println(mutedStyle.Render("Dry run - no changes made"))
+					fmt.Println()
+					fmt.Printf("Would move %s to %s\n", plan.OldDir, plan.NewDir)
+				} else {
+					if err := agent.ApplyRename(*cfgPath, cfg, plan); err != nil {
+						return err
+					}
+					successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+					fmt.Println(successStyle.Render(fmt.Sprintf("Renamed %s to %s", plan.OldHandle, plan.NewHandle)))
+					fmt.Printf("  Location: %s\n", plan.NewDir)
+				}
+
+				verb := "Updated"
+				if dryRun {
+					verb = "Would update"
+				}
+				for _, taskType := range plan.GlobalDelegates {
+					fmt.Printf("  %s global delegate %s\n", verb, taskType)
+				}
+				agentHandles := make([]string, 0, len(plan.AgentDelegates))
+				for h := range plan.AgentDelegates {
+					agentHandles = append(agentHandles, h)
+				}
+				sort.Strings(agentHandles)
+				for _, h := range agentHandles {
+					fmt.Printf("  %s %s delegates: %s\n", verb, h, strings.Join(plan.AgentDelegates[h], ", "))
+				}
+
+				if len(refs) > 0 {
+					fmt.Println()
+					fmt.Println(warnStyle.Render(fmt.Sprintf("Flows that call %s (update manually):", plan.OldHandle)))
+					for _, ref := range refs {
+						fmt.Printf("  %s:%d: %s\n", ref.Path, ref.Line, ref.Text)
+					}
+				}
+
+				if note := historicalRecordsNote(cmd.Context(), plan.OldHandle); note != "" {
+					fmt.Println()
+					fmt.Println(mutedStyle.Render(note))
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would change without making changes")
+	cmd.Flags().BoolVar(&scanFlows, "flows", false, "report flows that call the old handle")
+
+	return cmd
+}
+
+// historicalRecordsNote describes sessions and memories recorded under
+// handle, which keep the old handle after a rename. Returns "" when there
+// are none or the database is unavailable.
+func historicalRecordsNote(ctx context.Context, handle string) string {
+	dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
+	if err != nil {
+		return ""
+	}
+	defer dbConn.Close()
+
+	sessionCount, _ := queries.CountSessionsByAgent(ctx, handle)
+	memoryCount, _ := queries.CountMemoriesByAgent(ctx, db.CountMemoriesByAgentParams{
+		AgentHandle: sql.NullString{String: handle, Valid: true},
+	})
+
+	var notes []string
+	switch sessionCount {
+	case 0:
+	case 1:
+		notes = append(notes, fmt.Sprintf("1 session references %s and keeps the old handle as a historical record.", handle))
+	default:
+		notes = append(notes, fmt.Sprintf("%d sessions reference %s and keep the old handle as historical records.", sessionCount, handle))
+	}
+	switch memoryCount {
+	case 0:
+	case 1:
+		notes = append(notes, fmt.Sprintf("1 memory is scoped to %s and will not be loaded for the new handle.", handle))
+	default:
+		notes = append(notes, fmt.Sprintf("%d memories are scoped to %s and will not be loaded for the new handle.", memoryCount, handle))
+	}
+	return strings.Join(notes, "\n")
+}
+
+func agentSchemaCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage agent schemas",
+	}
+
+	cmd.AddCommand(inferSchemaCmd(cfgPath))
+
+	return cmd
+}
+
+func inferSchemaCmd(cfgPath *string) *cobra.Command {
+	var (
+		from   []string
+		accept bool
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "infer <handle> --from <example.json>",
+		Short: "Infer an output schema from example outputs",
+		Long: `Generate a draft-07 output schema for an agent from example JSON outputs.
+
+Types are inferred from the values seen. Fields present in every example are
+required, and string fields with a few repeated values become enums. The
+schema is checked against every example before it is written to the agent's
+output.jsonschema.
+
+In a terminal, you can choose which fields are required and restrict string
+fields to the values seen. Use --yes to accept the inferred draft as is.`,
+		Example: `  ayo agents schema infer @summarizer --from example.json
+  ayo agents schema infer @summarizer --from a.json --from b.json --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(from) == 0 {
+				return fmt.Errorf("at least one --from example is required")
+			}
+
+			examples := make([]any, 0, len(from))
+			for _, path := range from {
+				data, err := os.ReadFile(expandPath(path))
+				if err != nil {
+					return fmt.Errorf("read example: %w", err)
+				}
+				var v any
+				if err := json.Unmarshal(data, &v); err != nil {
+					return fmt.Errorf("parse example %s: %w", path, err)
+				}
+				examples = append(examples, v)
+			}
+
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				ag, err := agent.Load(cfg, args[0])
+				if err != nil {
+					return err
+				}
+				if ag.BuiltIn {
+					return fmt.Errorf("cannot modify built-in agent %s", ag.Handle)
+				}
+				schemaPath := filepath.Join(ag.Dir, "output.jsonschema")
+				if _, err := os.Stat(schemaPath); err == nil && !force {
+					return fmt.Errorf("%s already has an output schema (use --force to replace it)", ag.Handle)
+				}
+
+				inf, err := agent.InferSchema(examples)
+				if err != nil {
+					return err
+				}
+
+				if !accept && term.IsTerminal(int(os.Stdin.Fd())) {
+					ok, err := adjustInferredSchema(inf)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						fmt.Println("Cancelled")
+						return nil
+					}
+				}
+
+				data, err := agent.MarshalSchema(inf.Schema)
+				if err != nil {
+					return fmt.Errorf("marshal schema: %w", err)
+				}
+				if err := agent.VerifyInferredSchema(data, inf.Schema, examples); err != nil {
+					return fmt.Errorf("inferred schema is invalid: %w", err)
+				}
+				if err := os.WriteFile(schemaPath, append(data, '\n'), 0o644); err != nil {
+					return fmt.Errorf("write schema: %w", err)
+				}
+
+				successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+				fmt.Println(successStyle.Render("Wrote output schema for " + ag.Handle))
+				fmt.Printf("  Location: %s\n", schemaPath)
+				fmt.Printf("  Fields: %d (%d required at top level)\n", len(inf.Fields), len(inf.Schema.Required))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&from, "from", nil, "example JSON output file (repeatable)")
+	cmd.Flags().BoolVarP(&accept, "yes", "y", false, "accept the inferred schema without prompting")
+	cmd.Flags().BoolVar(&force, "force", false, "replace an existing output schema")
+
+	return cmd
+}
+
+// adjustInferredSchema lets the user choose required fields and restrict
+// string fields to their observed values. Returns false if the user
+// declines to save.
+func adjustInferredSchema(inf *agent.SchemaInference) (bool, error) {
+	var (
+		requiredOpts []huh.Option[string]
+		enumOpts     []huh.Option[string]
+		required     []string
+		restricted   []string
+	)
+	fields := make(map[string]agent.InferredField, len(inf.Fields))
+	for _, f := range inf.Fields {
+		fields[f.Path] = f
+		requiredOpts = append(requiredOpts, huh.NewOption(f.Path, f.Path).Selected(f.Required))
+		if len(f.Values) > 0 && len(f.Values) <= 20 {
+			label := fmt.Sprintf("%s (%s)", f.Path, strings.Join(f.Values, ", "))
+			enumOpts = append(enumOpts, huh.NewOption(label, f.Path))
+		}
+	}
+
+	var groups []*huh.Group
+	if len(requiredOpts) > 0 {
+		groups = append(groups, huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Required fields").
+				Description("Unselected fields are optional").
+				Options(requiredOpts...).
+				Value(&required),
+		))
+	}
+	if len(enumOpts) > 0 {
+		groups = append(groups, huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Restrict to observed values").
+				Description("Selected fields only accept the values in the examples").
+				Options(enumOpts...).
+				Value(&restricted),
+		))
+	}
+
+	save := true
+	groups = append(groups, huh.NewGroup(
+		huh.NewConfirm().
+			Title("Save output schema?").
+			Affirmative("Save").
+			Negative("Cancel").
+			Value(&save),
+	))
+
+	if err := huh.NewForm(groups...).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+		return false, err
+	}
+	if !save {
+		return false, nil
+	}
+
+	for _, f := range inf.Fields {
+		agent.SetFieldRequired(f, slices.Contains(required, f.Path))
+	}
+	for _, path := range restricted {
+		agent.RestrictToValues(fields[path])
+	}
+	return true, nil
+}
+
 func updateAgentsCmd(cfgPath *string) *cobra.Command {
 	var force bool
+	var check bool
 
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update built-in agents to latest version",
+		Long: `Update built-in agents to the versions shipped with this release.
+
+Agents with local modifications are not replaced without confirmation, or
+--force. With --check, nothing is changed: the command reports which agents
+have updates available and which have local modifications that would block
+the update.`,
+		Example: `  ayo agents update --check
+  ayo agents update
+  ayo agents update --force`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return withConfig(cfgPath, func(cfg config.Config) error {
 				sui := newSetupUI(cmd.OutOrStdout())
 
+				if check {
+					return printAgentUpdates(sui, cfg)
+				}
+
 				if !force {
 					// Check for modified agents
 					modified, err := builtin.CheckModifiedAgents()
@@ -481,9 +1318,31 @@ func updateAgentsCmd(cfgPath *string) *cobra.Command {
 							sui.Info(fmt.Sprintf("  %s: %v", m.Handle, m.ModifiedFiles))
 						}
 						sui.Blank()
-						sui.Info("Use --force to overwrite, or copy modifications to user directory first:")
-						sui.Info(fmt.Sprintf("  %s", cfg.AgentsDir))
-						return fmt.Errorf("agents have local modifications")
+
+						if !term.IsTerminal(int(os.Stdin.Fd())) {
+							sui.Info("Use --force to overwrite, or copy modifications to user directory first:")
+							sui.Info(fmt.Sprintf("  %s", cfg.AgentsDir))
+							return fmt.Errorf("agents have local modifications")
+						}
+
+						var confirm bool
+						form := huh.NewForm(
+							huh.NewGroup(
+								huh.NewConfirm().
+									Title("Overwrite modifications with fresh copies?").
+									Description("Your changes will be lost.").
+									Value(&confirm),
+							),
+						).WithTheme(huh.ThemeCharm())
+						if err := form.Run(); err != nil {
+							return err
+						}
+						if !confirm {
+							sui.Cancelled("Update cancelled.")
+							sui.Info("To keep your modifications, copy them to the user directory first:")
+							sui.Info(fmt.Sprintf("  %s", cfg.AgentsDir))
+							return nil
+						}
 					}
 				}
 
@@ -499,6 +1358,53 @@ func updateAgentsCmd(cfgPath *string) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&force, "force", false, "overwrite without checking for modifications")
+	cmd.Flags().BoolVar(&check, "check", false, "report available updates and local modifications without changing anything")
 
 	return cmd
 }
+
+// printAgentUpdates reports how installed built-in agents differ from this
+// release.
+func printAgentUpdates(sui *setupUI, cfg config.Config) error {
+	updates, err := builtin.CheckAgentUpdates()
+	if err != nil {
+		return fmt.Errorf("check agent updates: %w", err)
+	}
+
+	pending, blocked := 0, 0
+	for _, u := range updates {
+		switch {
+		case !u.Installed:
+			sui.Step(fmt.Sprintf("%s: not installed", u.Handle))
+		case len(u.Changed) == 0 && len(u.Modified) == 0:
+			sui.Info(fmt.Sprintf("%s: up to date", u.Handle))
+		case len(u.Changed) == 0:
+			sui.Info(fmt.Sprintf("%s: up to date, with local modifications", u.Handle))
+		default:
+			sui.Step(fmt.Sprintf("%s: update available", u.Handle))
+			sui.Info(fmt.Sprintf("  changed: %s", strings.Join(u.Changed, ", ")))
+		}
+		if len(u.Modified) > 0 {
+			sui.Info(fmt.Sprintf("  modified locally: %s", strings.Join(u.Modified, ", ")))
+		}
+		if u.UpdateAvailable() {
+			pending++
+			if len(u.Modified) > 0 {
+				blocked++
+			}
+		}
+	}
+
+	sui.Blank()
+	switch {
+	case pending == 0:
+		sui.Complete("All built-in agents are up to date.")
+	case blocked > 0:
+		sui.Warning(fmt.Sprintf("%d of %d updates would replace local modifications.", blocked, pending))
+		sui.Info("Copy your changes to the user directory first, then run 'ayo agents update':")
+		sui.Info(fmt.Sprintf("  %s", cfg.AgentsDir))
+	default:
+		sui.Info("Run 'ayo agents update' to install the updates.")
+	}
+	return nil
+}
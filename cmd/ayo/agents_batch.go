@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// batchManifestName is the manifest file written to the output directory.
+const batchManifestName = "manifest.json"
+
+// batchInputTimeout bounds the agent run for a single input.
+const batchInputTimeout = 5 * time.Minute
+
+func runBatchAgentCmd(cfgPath *string) *cobra.Command {
+	var inputsDir string
+	var outDir string
+	var concurrency int
+	var continueOnError bool
+	var force bool
+	var noCache bool
+	var seed int64
+
+	cmd := &cobra.Command{
+		Use:   "run-batch <handle> --inputs <dir> --out <dir>",
+		Short: "Run an agent on every file in a directory",
+		Long: `Run an agent once per input file and write each response to the output
+directory.
+
+Every regular file in --inputs (not subdirectories or hidden files) is sent
+to the agent as the prompt. Agents with an input schema must be given JSON
+that matches it. Responses of agents with an output schema are validated and
+written as <name>.json; other responses are written as <name>.txt, where
+<name> is the input file name without its extension.
+
+Inputs whose output file already exists are skipped, so an interrupted batch
+can be resumed by running the same command again. Use --force to run them
+anyway.
+
+The first failure stops the batch unless --continue-on-error is set; inputs
+not finished by then are recorded as not run. Either way, manifest.json in
+the output directory records the result of every input, and the command
+fails if any input failed.
+
+Examples:
+  # Summarize every document, four at a time
+  ayo agents run-batch @summarizer --inputs ./docs --out ./summaries -j 4
+
+  # Keep going past failures, then retry only the failed inputs
+  ayo agents run-batch @extractor --inputs ./in --out ./out --continue-on-error
+  ayo agents run-batch @extractor --inputs ./in --out ./out`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if inputsDir == "" || outDir == "" {
+				return fmt.Errorf("both --inputs and --out are required")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("invalid --concurrency %d: must be 1 or more", concurrency)
+			}
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				ag, err := agent.Load(cfg, args[0])
+				if err != nil {
+					return err
+				}
+				printAgentWarnings(ag)
+
+				ext := ".txt"
+				if ag.HasOutputSchema() {
+					ext = ".json"
+				}
+				items, err := batchItems(inputsDir, outDir, ext)
+				if err != nil {
+					return err
+				}
+				if len(items) == 0 {
+					return fmt.Errorf("no input files in %s", inputsDir)
+				}
+				if err := os.MkdirAll(outDir, 0755); err != nil {
+					return fmt.Errorf("create output directory: %w", err)
+				}
+
+				services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+				if err != nil {
+					warnNoPersistence("session persistence", err, false)
+					services = nil
+				} else {
+					defer services.Close()
+				}
+
+				runner, err := run.NewRunner(cfg, false, run.RunnerOptions{
+					Services:  services,
+					RawOutput: true,
+					NoCache:   noCache,
+					Seed:      seedOption(cmd, seed, cfg.Provider),
+				})
+				if err != nil {
+					return err
+				}
+				// Tool activity of concurrent runs would interleave, so only
+				// the per-input results are shown
+				runner.SetStreamWriter(run.NullWriter{})
+
+				process := func(ctx context.Context, input string) (string, error) {
+					if err := ag.ValidateInput(input); err != nil {
+						return "", err
+					}
+					ctx, cancel := context.WithTimeout(ctx, batchInputTimeout)
+					defer cancel()
+					resp, err := runner.Text(ctx, ag, input, nil)
+					if err != nil {
+						return "", err
+					}
+					if err := ag.ValidateOutput(resp); err != nil {
+						return "", err
+					}
+					return resp, nil
+				}
+
+				successStyle := lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+				errorStyle := lipgloss.NewStyle().Foreground(shared.ColorError)
+				mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+				report := func(res batchResult) {
+					switch res.Status {
+					case batchStatusOK:
+						fmt.Fprintf(os.Stderr, "%s %s %s\n", successStyle.Render("ok"), res.Input, mutedStyle.Render("-> "+res.Output))
+					case batchStatusSkipped:
+						fmt.Fprintf(os.Stderr, "%s %s\n", mutedStyle.Render("skipped"), mutedStyle.Render(res.Input))
+					case batchStatusNotRun:
+						fmt.Fprintf(os.Stderr, "%s %s\n", mutedStyle.Render("not run"), mutedStyle.Render(res.Input))
+					default:
+						fmt.Fprintf(os.Stderr, "%s %s: %s\n", errorStyle.Render("failed"), res.Input, res.Error)
+					}
+				}
+
+				manifest := runBatch(cmd.Context(), items, batchOptions{
+					Concurrency:     concurrency,
+					ContinueOnError: continueOnError,
+					Force:           force,
+				}, process, report)
+				manifest.Agent = ag.Handle
+
+				manifestPath := filepath.Join(outDir, batchManifestName)
+				data, err := json.MarshalIndent(manifest, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := writeFileAtomic(manifestPath, append(data, '\n')); err != nil {
+					return fmt.Errorf("write manifest: %w", err)
+				}
+
+				fmt.Fprintln(os.Stderr)
+				summary := fmt.Sprintf("%d succeeded, %d failed, %d skipped", manifest.Succeeded, manifest.Failed, manifest.Skipped)
+				if manifest.NotRun > 0 {
+					summary += fmt.Sprintf(", %d not run", manifest.NotRun)
+				}
+				fmt.Fprintln(os.Stderr, summary)
+				fmt.Fprintln(os.Stderr, mutedStyle.Render("Manifest: "+manifestPath))
+				printRedactionSummary(runner)
+
+				if manifest.Failed > 0 {
+					return fmt.Errorf("%d of %d inputs failed", manifest.Failed, len(items))
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&inputsDir, "inputs", "", "Directory of input files (required)")
+	cmd.Flags().StringVar(&outDir, "out", "", "Directory to write results to (required)")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "j", 1, "Number of inputs to run at once")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Keep running the remaining inputs after a failure")
+	cmd.Flags().BoolVar(&force, "force", false, "Run inputs whose output file already exists")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the response cache")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Sampling seed for reproducible runs, where the provider supports it")
+
+	return cmd
+}
+
+// batchItem pairs an input file with the output file its result goes to.
+type batchItem struct {
+	Input  string
+	Output string
+}
+
+// batchItems lists the regular, non-hidden files in inputsDir, sorted by
+// name, with their output files in outDir. Output names replace the input
+// extension with ext.
+func batchItems(inputsDir, outDir, ext string) ([]batchItem, error) {
+	inAbs, err := filepath.Abs(inputsDir)
+	if err != nil {
+		return nil, err
+	}
+	outAbs, err := filepath.Abs(outDir)
+	if err != nil {
+		return nil, err
+	}
+	if inAbs == outAbs {
+		return nil, fmt.Errorf("the output directory must differ from the inputs directory")
+	}
+
+	entries, err := os.ReadDir(inputsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read inputs: %w", err)
+	}
+	var items []batchItem
+	outputs := make(map[string]string)
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())) + ext
+		if name == batchManifestName {
+			return nil, fmt.Errorf("input %s would overwrite %s", e.Name(), batchManifestName)
+		}
+		if prev, ok := outputs[name]; ok {
+			return nil, fmt.Errorf("inputs %s and %s would both write %s", prev, e.Name(), name)
+		}
+		outputs[name] = e.Name()
+		items = append(items, batchItem{
+			Input:  filepath.Join(inputsDir, e.Name()),
+			Output: filepath.Join(outDir, name),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Input < items[j].Input })
+	return items, nil
+}
+
+// Statuses of a batch input in the manifest.
+const (
+	batchStatusOK      = "ok"
+	batchStatusFailed  = "failed"
+	batchStatusSkipped = "skipped"
+	batchStatusNotRun  = "not_run" // Stopped by a failure or interrupted
+)
+
+// batchResult is the outcome of one input in the manifest.
+type batchResult struct {
+	Input      string `json:"input"`
+	Output     string `json:"output,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+}
+
+// batchManifest summarizes a batch run.
+type batchManifest struct {
+	Agent      string        `json:"agent"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Succeeded  int           `json:"succeeded"`
+	Failed     int           `json:"failed"`
+	Skipped    int           `json:"skipped"`
+	NotRun     int           `json:"not_run"`
+	Results    []batchResult `json:"results"`
+}
+
+type batchOptions struct {
+	Concurrency     int
+	ContinueOnError bool
+	Force           bool // Run inputs whose output already exists
+}
+
+// runBatch runs process on the contents of each input and writes the
+// response to its output file, up to opts.Concurrency inputs at a time.
+// report is called as each input finishes. The results in the returned
+// manifest are in the order of items.
+func runBatch(ctx context.Context, items []batchItem, opts batchOptions, process func(ctx context.Context, input string) (string, error), report func(batchResult)) batchManifest {
+	manifest := batchManifest{
+		StartedAt: time.Now().UTC(),
+		Results:   make([]batchResult, len(items)),
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var mu sync.Mutex
+	finish := func(i int, res batchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		manifest.Results[i] = res
+		if res.Status == batchStatusFailed && !opts.ContinueOnError {
+			stop()
+		}
+		if report != nil {
+			report(res)
+		}
+	}
+
+	runOne := func(item batchItem) batchResult {
+		res := batchResult{Input: item.Input, Output: item.Output}
+		if !opts.Force {
+			if _, err := os.Stat(item.Output); err == nil {
+				res.Status = batchStatusSkipped
+				return res
+			}
+		}
+		fail := func(err error) batchResult {
+			res.Status = batchStatusFailed
+			res.Output = ""
+			res.Error = err.Error()
+			return res
+		}
+		if ctx.Err() != nil {
+			res.Status = batchStatusNotRun
+			res.Output = ""
+			return res
+		}
+
+		data, err := os.ReadFile(item.Input)
+		if err != nil {
+			return fail(err)
+		}
+		start := time.Now()
+		resp, err := process(ctx, strings.TrimSpace(string(data)))
+		res.DurationMS = time.Since(start).Milliseconds()
+		if err != nil && ctx.Err() != nil && errors.Is(err, context.Canceled) {
+			// Interrupted by another input's failure or by the user
+			res.Status = batchStatusNotRun
+			res.Output = ""
+			res.DurationMS = 0
+			return res
+		}
+		if err != nil {
+			return fail(err)
+		}
+		if err := writeFileAtomic(item.Output, []byte(strings.TrimSpace(resp)+"\n")); err != nil {
+			return fail(fmt.Errorf("write output: %w", err))
+		}
+		res.Status = batchStatusOK
+		return res
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(opts.Concurrency, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				finish(i, runOne(items[i]))
+			}
+		}()
+	}
+	for i := range items {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for _, res := range manifest.Results {
+		switch res.Status {
+		case batchStatusOK:
+			manifest.Succeeded++
+		case batchStatusSkipped:
+			manifest.Skipped++
+		case batchStatusNotRun:
+			manifest.NotRun++
+		default:
+			manifest.Failed++
+		}
+	}
+	manifest.FinishedAt = time.Now().UTC()
+	return manifest
+}
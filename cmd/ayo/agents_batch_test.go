@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func writeBatchInputs(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBatchItems(t *testing.T) {
+	in := writeBatchInputs(t, map[string]string{"b.md": "", "a.json": "", ".hidden": ""})
+	if err := os.Mkdir(filepath.Join(in, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+
+	items, err := batchItems(in, out, ".json")
+	if err != nil {
+		t.Fatalf("batchItems: %v", err)
+	}
+	want := []batchItem{
+		{filepath.Join(in, "a.json"), filepath.Join(out, "a.json")},
+		{filepath.Join(in, "b.md"), filepath.Join(out, "b.json")},
+	}
+	if len(items) != len(want) || items[0] != want[0] || items[1] != want[1] {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+
+	if _, err := batchItems(in, in, ".json"); err == nil {
+		t.Error("batchItems with --out equal to --inputs succeeded")
+	}
+	clash := writeBatchInputs(t, map[string]string{"a.md": "", "a.txt": ""})
+	if _, err := batchItems(clash, out, ".txt"); err == nil || !strings.Contains(err.Error(), "both write a.txt") {
+		t.Errorf("batchItems with clashing outputs error = %v", err)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	in := writeBatchInputs(t, map[string]string{"a.txt": "alpha", "b.txt": "bad", "c.txt": "gamma", "d.txt": "delta"})
+	out := t.TempDir()
+	if err := os.WriteFile(filepath.Join(out, "d.out"), []byte("earlier\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := batchItems(in, out, ".out")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	process := func(ctx context.Context, input string) (string, error) {
+		calls.Add(1)
+		if input == "bad" {
+			return "", errors.New("boom")
+		}
+		return strings.ToUpper(input), nil
+	}
+
+	m := runBatch(context.Background(), items, batchOptions{Concurrency: 2, ContinueOnError: true}, process, nil)
+	if m.Succeeded != 2 || m.Failed != 1 || m.Skipped != 1 || calls.Load() != 3 {
+		t.Fatalf("manifest = %+v after %d calls, want 2 ok, 1 failed, 1 skipped", m, calls.Load())
+	}
+	if m.Results[1].Status != batchStatusFailed || m.Results[1].Error != "boom" || m.Results[1].Output != "" {
+		t.Errorf("result for b.txt = %+v", m.Results[1])
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "c.out")); string(data) != "GAMMA\n" {
+		t.Errorf("c.out = %q, want GAMMA", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "d.out")); string(data) != "earlier\n" {
+		t.Errorf("existing d.out was rewritten: %q", data)
+	}
+
+	// Resuming runs only the input that failed
+	calls.Store(0)
+	m = runBatch(context.Background(), items, batchOptions{Concurrency: 1, ContinueOnError: true}, process, nil)
+	if calls.Load() != 1 || m.Skipped != 3 || m.Failed != 1 {
+		t.Errorf("resumed manifest = %+v after %d calls, want only b.txt run", m, calls.Load())
+	}
+
+	// --force reruns everything
+	calls.Store(0)
+	runBatch(context.Background(), items, batchOptions{Concurrency: 4, ContinueOnError: true, Force: true}, process, nil)
+	if calls.Load() != 4 {
+		t.Errorf("forced batch made %d calls, want 4", calls.Load())
+	}
+}
+
+func TestRunBatchStopsOnError(t *testing.T) {
+	in := writeBatchInputs(t, map[string]string{"a.txt": "bad", "b.txt": "ok", "c.txt": "ok"})
+	items, err := batchItems(in, t.TempDir(), ".out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	process := func(ctx context.Context, input string) (string, error) {
+		if input == "bad" {
+			return "", errors.New("boom")
+		}
+		return input, nil
+	}
+
+	m := runBatch(context.Background(), items, batchOptions{Concurrency: 1}, process, nil)
+	if m.Failed != 1 || m.NotRun != 2 || m.Succeeded != 0 {
+		t.Errorf("manifest = %+v, want the batch to stop after a.txt", m)
+	}
+	if m.Results[2].Status != batchStatusNotRun {
+		t.Errorf("result for c.txt = %+v, want not run", m.Results[2])
+	}
+}
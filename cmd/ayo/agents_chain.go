@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// chainTiers are the compatibility tiers in the order they are listed.
+var chainTiers = []agent.CompatibilityTier{
+	agent.CompatibilityExact,
+	agent.CompatibilityStructural,
+	agent.CompatibilityFreeform,
+}
+
+// agentChainJSON is the --chain --json output of "ayo agents show".
+type agentChainJSON struct {
+	Handle     string                `json:"handle"`
+	Upstream   []compatibleAgentJSON `json:"upstream"`
+	Downstream []compatibleAgentJSON `json:"downstream"`
+	Example    string                `json:"example,omitempty"`
+}
+
+// printAgentChain lists the agents that can feed into ag and the agents ag
+// can feed, grouped by compatibility tier, with an example pipe command for
+// the best match.
+func printAgentChain(cfg config.Config, ag agent.Agent, jsonOutput bool) error {
+	upstream, err := agent.FindUpstreamAgents(cfg, ag)
+	if err != nil {
+		return fmt.Errorf("find upstream agents: %w", err)
+	}
+	downstream, err := agent.FindDownstreamAgents(cfg, ag)
+	if err != nil {
+		return fmt.Errorf("find downstream agents: %w", err)
+	}
+	example := chainExample(ag, upstream, downstream)
+
+	if jsonOutput {
+		return writeJSON(agentChainJSON{
+			Handle:     ag.Handle,
+			Upstream:   compatibleAgentsToJSON(upstream),
+			Downstream: compatibleAgentsToJSON(downstream),
+			Example:    example,
+		})
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	iconStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorText)
+	tierStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	handleStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	dividerStyle := lipgloss.NewStyle().Foreground(shared.ColorSubtle)
+
+	printGroup := func(title, none string, agents []agent.ChainableAgent) {
+		fmt.Println("  " + sectionStyle.Render(title))
+		if len(agents) == 0 {
+			fmt.Println("    " + tierStyle.Render(none))
+			return
+		}
+		for _, tier := range chainTiers {
+			printed := false
+			for _, ca := range agents {
+				if ca.Compatibility != tier {
+					continue
+				}
+				if !printed {
+					fmt.Println("    " + tierStyle.Render(tier.String()))
+					printed = true
+				}
+				desc := ""
+				if ca.Agent.Config.Description != "" {
+					desc = tierStyle.Render(" - " + ca.Agent.Config.Description)
+				}
+				fmt.Printf("      %s%s\n", handleStyle.Render(ca.Agent.Handle), desc)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("  " + iconStyle.Render("◆") + " " + headerStyle.Render(ag.Handle))
+	fmt.Println(dividerStyle.Render("  " + strings.Repeat("─", 58)))
+
+	noUpstream := "No agents with an output schema match this agent's input"
+	printGroup("Receives from", noUpstream, upstream)
+	fmt.Println()
+
+	noDownstream := "No agents accept this agent's output"
+	if ag.OutputSchema == nil {
+		noDownstream = "None; this agent has no output schema"
+	}
+	printGroup("Sends to", noDownstream, downstream)
+
+	if example != "" {
+		fmt.Println()
+		fmt.Println("  " + sectionStyle.Render("Try"))
+		fmt.Println("    " + example)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// compatibleAgentsToJSON converts chainable agents to their JSON form.
+func compatibleAgentsToJSON(agents []agent.ChainableAgent) []compatibleAgentJSON {
+	result := make([]compatibleAgentJSON, len(agents))
+	for i, ca := range agents {
+		result[i] = compatibleAgentJSON{
+			Handle:        ca.Agent.Handle,
+			Description:   ca.Agent.Config.Description,
+			Compatibility: ca.Compatibility.String(),
+		}
+	}
+	return result
+}
+
+// chainExample returns a pipe command for the most compatible neighbour of
+// ag, preferring a downstream agent on a tie. The lists must be sorted best
+// first, as FindUpstreamAgents and FindDownstreamAgents return them. It
+// returns "" when ag has no neighbours.
+func chainExample(ag agent.Agent, upstream, downstream []agent.ChainableAgent) string {
+	switch {
+	case len(downstream) > 0 && (len(upstream) == 0 || downstream[0].Compatibility >= upstream[0].Compatibility):
+		return pipeCommand(ag, downstream[0].Agent)
+	case len(upstream) > 0:
+		return pipeCommand(upstream[0].Agent, ag)
+	default:
+		return ""
+	}
+}
+
+// pipeCommand returns a shell command piping source's output into target,
+// with example input for source.
+func pipeCommand(source, target agent.Agent) string {
+	input := "your prompt"
+	if source.InputSchema != nil {
+		if data, err := json.Marshal(generateSchemaExample(source.InputSchema)); err == nil {
+			input = string(data)
+		}
+	}
+	return fmt.Sprintf("ayo %s %s | ayo %s", source.Handle, shellQuote(input), target.Handle)
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
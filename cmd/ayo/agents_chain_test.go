@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+func TestChainExample(t *testing.T) {
+	reviewer := agent.Agent{
+		Handle: "@reviewer",
+		InputSchema: &agent.Schema{
+			Type:       "object",
+			Properties: map[string]*agent.Schema{"note": {Type: "string", Enum: []any{"it's fine"}}},
+		},
+	}
+	reporter := agent.Agent{Handle: "@reporter"}
+	planner := agent.Agent{Handle: "@planner"}
+
+	exact := func(a agent.Agent) agent.ChainableAgent {
+		return agent.ChainableAgent{Agent: a, Compatibility: agent.CompatibilityExact}
+	}
+	freeform := func(a agent.Agent) agent.ChainableAgent {
+		return agent.ChainableAgent{Agent: a, Compatibility: agent.CompatibilityFreeform}
+	}
+
+	tests := []struct {
+		name       string
+		upstream   []agent.ChainableAgent
+		downstream []agent.ChainableAgent
+		want       string
+	}{
+		{"none", nil, nil, ""},
+		{
+			"downstream wins a tie",
+			[]agent.ChainableAgent{exact(planner)},
+			[]agent.ChainableAgent{exact(reporter)},
+			`ayo @reviewer '{"note":"it'\''s fine"}' | ayo @reporter`,
+		},
+		{
+			"better upstream",
+			[]agent.ChainableAgent{exact(planner)},
+			[]agent.ChainableAgent{freeform(reporter)},
+			`ayo @planner 'your prompt' | ayo @reviewer`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chainExample(reviewer, tt.upstream, tt.downstream); got != tt.want {
+				t.Errorf("chainExample() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
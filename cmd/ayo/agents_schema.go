@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"charm.land/fantasy/schema"
+	"github.com/charmbracelet/huh"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+// buildSchemaInteractively asks for output fields one at a time and returns
+// the schema they form. Returns nil if the user cancels.
+func buildSchemaInteractively() (*schema.Schema, error) {
+	var fields []agent.SchemaField
+	for {
+		f, err := askSchemaField(fields)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := agent.BuildSchema(append(fields, f)); err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+		} else {
+			fields = append(fields, f)
+		}
+
+		more := true
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title("Add another field?").
+				Description(schemaFieldSummary(fields)).
+				Affirmative("Add").
+				Negative("Done").
+				Value(&more),
+		)).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+			return nil, err
+		}
+		if !more {
+			if len(fields) == 0 {
+				return nil, nil
+			}
+			break
+		}
+	}
+
+	save := true
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title("Save output schema?").
+			Description(schemaFieldSummary(fields)).
+			Affirmative("Save").
+			Negative("Cancel").
+			Value(&save),
+	)).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+		return nil, err
+	}
+	if !save {
+		return nil, nil
+	}
+	return agent.BuildSchema(fields)
+}
+
+// askSchemaField prompts for one field. Object fields already defined are
+// offered as parents in the name's description.
+func askSchemaField(defined []agent.SchemaField) (agent.SchemaField, error) {
+	var (
+		f       agent.SchemaField
+		objects []string
+	)
+	for _, d := range defined {
+		if d.Type == "object" {
+			objects = append(objects, d.Path)
+		}
+	}
+	nameHelp := "Property name"
+	if len(objects) > 0 {
+		nameHelp = "Property name; nest with a dot, e.g. " + objects[0] + ".name"
+	}
+
+	typeOpts := make([]huh.Option[string], 0, len(agent.SchemaFieldTypes))
+	for _, t := range agent.SchemaFieldTypes {
+		typeOpts = append(typeOpts, huh.NewOption(t, t))
+	}
+	f.Type = "string"
+
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title("Field name").
+			Description(nameHelp).
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return errors.New("name is required")
+				}
+				return nil
+			}).
+			Value(&f.Path),
+		huh.NewSelect[string]().
+			Title("Type").
+			Options(typeOpts...).
+			Value(&f.Type),
+		huh.NewInput().
+			Title("Description").
+			Description("Tells the model what to put in the field").
+			Value(&f.Description),
+		huh.NewConfirm().
+			Title("Required?").
+			Value(&f.Required),
+	)).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+		return f, err
+	}
+	f.Path = strings.TrimSpace(f.Path)
+	f.Description = strings.TrimSpace(f.Description)
+
+	switch f.Type {
+	case "string":
+		var values string
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewInput().
+				Title("Allowed values").
+				Description("Comma-separated; leave empty to allow any text").
+				Value(&values),
+		)).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+			return f, err
+		}
+		f.Enum = splitAllowedValues(values)
+	case "array":
+		itemOpts := make([]huh.Option[string], 0, len(agent.SchemaItemTypes))
+		for _, t := range agent.SchemaItemTypes {
+			itemOpts = append(itemOpts, huh.NewOption(t, t))
+		}
+		f.ItemType = "string"
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Item type").
+				Options(itemOpts...).
+				Value(&f.ItemType),
+		)).WithTheme(huh.ThemeCharm()).Run(); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// splitAllowedValues parses a comma-separated list of enum values, dropping
+// blanks and duplicates.
+func splitAllowedValues(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// schemaFieldSummary lists fields as "path (type, required)" lines.
+func schemaFieldSummary(fields []agent.SchemaField) string {
+	if len(fields) == 0 {
+		return "No fields yet"
+	}
+	lines := make([]string, 0, len(fields))
+	for _, f := range fields {
+		kind := f.Type
+		if f.Type == "array" {
+			kind = "array of " + f.ItemType
+		}
+		if len(f.Enum) > 0 {
+			kind += ": " + strings.Join(f.Enum, " | ")
+		}
+		if f.Required {
+			kind += ", required"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", f.Path, kind))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeBuiltSchema encodes s, checks that it round-trips and accepts a
+// sample value, and writes it to a temporary file for agent.SaveWithSchemas.
+// The caller removes the file.
+func writeBuiltSchema(s *schema.Schema) (string, error) {
+	data, err := agent.MarshalSchema(s)
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	if err := agent.VerifyInferredSchema(data, s, []any{agent.SchemaExample(s)}); err != nil {
+		return "", fmt.Errorf("built schema is invalid: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "ayo-output-*.jsonschema")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
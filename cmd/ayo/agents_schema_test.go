@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+
+	"charm.land/fantasy/schema"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+func TestSplitAllowedValues(t *testing.T) {
+	got := splitAllowedValues(" low, high ,, low,medium ")
+	if want := []string{"low", "high", "medium"}; !slices.Equal(got, want) {
+		t.Errorf("splitAllowedValues = %v, want %v", got, want)
+	}
+	if got := splitAllowedValues(""); got != nil {
+		t.Errorf("empty input = %v, want nil", got)
+	}
+}
+
+func TestSchemaFieldSummary(t *testing.T) {
+	got := schemaFieldSummary([]agent.SchemaField{
+		{Path: "severity", Type: "string", Enum: []string{"low", "high"}, Required: true},
+		{Path: "tags", Type: "array", ItemType: "string"},
+	})
+	want := "severity (string: low | high, required)\ntags (array of string)"
+	if got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestWriteBuiltSchema(t *testing.T) {
+	s, err := agent.BuildSchema([]agent.SchemaField{{Path: "answer", Type: "string", Required: true}})
+	if err != nil {
+		t.Fatalf("BuildSchema: %v", err)
+	}
+	path, err := writeBuiltSchema(s)
+	if err != nil {
+		t.Fatalf("writeBuiltSchema: %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded schema.Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("written schema does not decode: %v", err)
+	}
+	if decoded.Type != "object" || decoded.Properties["answer"] == nil {
+		t.Errorf("written schema = %+v", decoded)
+	}
+}
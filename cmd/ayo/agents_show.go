@@ -0,0 +1,76 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/delegates"
+)
+
+// agentJSON is the --json output of "ayo agents show": the agent as it is
+// run, after defaults from the ayo config are applied.
+type agentJSON struct {
+	Handle         string             `json:"handle"`
+	Description    string             `json:"description,omitempty"`
+	Model          string             `json:"model"` // The agent's model, or the default model
+	BuiltIn        bool               `json:"builtin"`
+	Dir            string             `json:"dir"`
+	AllowedTools   []string           `json:"allowed_tools"`
+	Skills         []agentSkillJSON   `json:"skills"` // Active skills only; see --skills for the rest
+	InputSchema    *agent.Schema      `json:"input_schema,omitempty"`
+	OutputSchema   *agent.Schema      `json:"output_schema,omitempty"`
+	Delegates      map[string]string  `json:"delegates,omitempty"` // Agent delegates merged over the ayo config's
+	CallableAgents []string           `json:"callable_agents,omitempty"`
+	ContextFiles   []string           `json:"context_files,omitempty"` // Resolved paths
+	Memory         agent.MemoryConfig `json:"memory"`
+	Warnings       []string           `json:"warnings"`
+}
+
+// agentSkillJSON is an active skill in agentJSON.
+type agentSkillJSON struct {
+	Name   string `json:"name"`
+	Source string `json:"source"` // agent, user, built-in, or plugin
+	Path   string `json:"path"`
+}
+
+// agentToJSON describes ag for tools that introspect agents.
+func agentToJSON(cfg config.Config, ag agent.Agent) agentJSON {
+	report := ag.ResolveSkills()
+	skills := []agentSkillJSON{}
+	for _, s := range report.Skills {
+		if s.Active {
+			skills = append(skills, agentSkillJSON{Name: s.Name, Source: s.Source, Path: s.Path})
+		}
+	}
+
+	tools := ag.Config.AllowedTools
+	if tools == nil {
+		tools = []string{}
+	}
+	warnings := append(slices.Clone(report.Warnings), ag.ContextWarnings...)
+	if warnings == nil {
+		warnings = []string{}
+	}
+	allDelegates := delegates.GetAllDelegates(ag.Config.Delegates, cfg)
+	if len(allDelegates) == 0 {
+		allDelegates = nil
+	}
+
+	return agentJSON{
+		Handle:         ag.Handle,
+		Description:    ag.Config.Description,
+		Model:          ag.Model,
+		BuiltIn:        ag.BuiltIn,
+		Dir:            ag.Dir,
+		AllowedTools:   tools,
+		Skills:         skills,
+		InputSchema:    ag.InputSchema,
+		OutputSchema:   ag.OutputSchema,
+		Delegates:      allDelegates,
+		CallableAgents: ag.Config.CallableAgents,
+		ContextFiles:   ag.ContextFiles,
+		Memory:         ag.Config.Memory,
+		Warnings:       warnings,
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestAgentToJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.Config{DefaultModel: "gpt-5.2", Delegates: map[string]string{"coding": "@crush", "research": "@research"}}
+	ag := agent.Agent{
+		Handle: "@reviewer",
+		Dir:    t.TempDir(),
+		Model:  "gpt-5.2",
+		Config: agent.Config{
+			Description: "Reviews diffs",
+			Delegates:   map[string]string{"coding": "@coder"},
+		},
+		OutputSchema: &agent.Schema{
+			Type:       "object",
+			Properties: map[string]*agent.Schema{"verdict": {Type: "string"}},
+		},
+		ContextWarnings: []string{"context file style.md not found"},
+	}
+
+	data, err := json.Marshal(agentToJSON(cfg, ag))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["model"] != "gpt-5.2" || got["builtin"] != false {
+		t.Errorf("model/builtin = %v/%v", got["model"], got["builtin"])
+	}
+	if tools, ok := got["allowed_tools"].([]any); !ok || len(tools) != 0 {
+		t.Errorf("allowed_tools = %v, want an empty list", got["allowed_tools"])
+	}
+	if _, ok := got["input_schema"]; ok {
+		t.Error("input_schema should be omitted when the agent has none")
+	}
+	if !strings.Contains(string(data), `"verdict"`) {
+		t.Errorf("output schema not inlined: %s", data)
+	}
+	delegates, _ := got["delegates"].(map[string]any)
+	if delegates["coding"] != "@coder" || delegates["research"] != "@research" {
+		t.Errorf("delegates = %v, want the agent's merged over the config's", delegates)
+	}
+	if warnings, _ := got["warnings"].([]any); len(warnings) != 1 {
+		t.Errorf("warnings = %v", got["warnings"])
+	}
+}
+
+func TestShowAgentViewsConflict(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--skills", "--chain"}, "the --skills and --chain flags can't be used together"},
+		{[]string{"--with-memory", "deploy", "--usage"}, "the --with-memory and --usage flags can't be used together"},
+		{[]string{"--sample", "hi", "--resolved"}, "the --resolved and --sample flags can't be used together"},
+		{[]string{"--chain", "--json"}, ""},
+		{[]string{"--cost-estimate", "--sample", "hi"}, ""},
+	}
+	for _, tt := range tests {
+		cfgPath := ""
+		cmd := showAgentCmd(&cfgPath)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("parse %v: %v", tt.args, err)
+		}
+		err := checkShowAgentViews(cmd)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error %v", tt.args, err)
+			}
+		} else if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%v: error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
@@ -1,9 +1,7 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -232,14 +230,7 @@ func newChainExampleCmd(cfgPath *string) *cobra.Command {
 					return fmt.Errorf("%s has no input schema", handle)
 				}
 
-				example := generateSchemaExample(ag.InputSchema)
-				jsonBytes, err := json.MarshalIndent(example, "", "  ")
-				if err != nil {
-					return err
-				}
-
-				fmt.Println(string(jsonBytes))
-				return nil
+				return writeJSON(generateSchemaExample(ag.InputSchema))
 			})
 		},
 	}
@@ -389,7 +380,7 @@ func outputJSON(agents []agent.Agent) error {
 			HasOutput:   ag.OutputSchema != nil,
 		}
 	}
-	return json.NewEncoder(os.Stdout).Encode(result)
+	return writeJSON(result)
 }
 
 type schemaJSON struct {
@@ -406,7 +397,7 @@ func outputSchemaJSON(ag agent.Agent) error {
 		Input:       ag.InputSchema,
 		Output:      ag.OutputSchema,
 	}
-	return json.NewEncoder(os.Stdout).Encode(result)
+	return writeJSON(result)
 }
 
 type compatibleAgentJSON struct {
@@ -416,15 +407,7 @@ type compatibleAgentJSON struct {
 }
 
 func outputCompatibleJSON(agents []agent.ChainableAgent) error {
-	result := make([]compatibleAgentJSON, len(agents))
-	for i, ca := range agents {
-		result[i] = compatibleAgentJSON{
-			Handle:        ca.Agent.Handle,
-			Description:   ca.Agent.Config.Description,
-			Compatibility: ca.Compatibility.String(),
-		}
-	}
-	return json.NewEncoder(os.Stdout).Encode(result)
+	return writeJSON(compatibleAgentsToJSON(agents))
 }
 
 // generateSchemaExample generates example data for a schema.
@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/run"
 	"github.com/alexcabrera/ayo/internal/session"
 	"github.com/alexcabrera/ayo/internal/ui/chat"
 )
 
 // runInteractiveChat handles the interactive chat session loop using the alt-screen TUI.
-func runInteractiveChat(ctx context.Context, runner *run.Runner, ag agent.Agent, debug bool) error {
+// With showStats, the status bar shows the run stats after each turn.
+func runInteractiveChat(ctx context.Context, cfg config.Config, runner *run.Runner, ag agent.Agent, debug, showStats bool) error {
 	// Get session ID for display
 	sessionID := runner.GetSessionID(ag.Handle)
 
@@ -19,7 +23,10 @@ func runInteractiveChat(ctx context.Context, runner *run.Runner, ag agent.Agent,
 	// The runner's StreamWriter will send streaming events through the channel.
 	// This function just triggers the chat and returns the final response.
 	sendFn := func(ctx context.Context, message string) (string, error) {
-		_, err := runner.Chat(ctx, ag, message)
+		resp, err := runner.Chat(ctx, ag, message)
+		if errors.Is(err, run.ErrInterrupted) {
+			return resp, err
+		}
 		if err != nil {
 			return "", err
 		}
@@ -50,11 +57,27 @@ func runInteractiveChat(ctx context.Context, runner *run.Runner, ag agent.Agent,
 	// 1. An event channel for streaming events
 	// 2. An EventAggregator that forwards events to the TUI via program.Send()
 	// 3. A ChannelWriter that the runner will use to write events
-	program, _, channelWriter := chat.RunWithChannel(ctx, ag, sessionID, sendFn)
+	// Offer every agent handle when "@" is typed in the input
+	handles, _ := agent.ListHandles(cfg)
+
+	opts := []chat.Option{
+		chat.WithMentionHandles(handles),
+		chat.WithIdleTimeout(time.Duration(cfg.Chat.IdleTimeout) * time.Minute),
+	}
+	if showStats {
+		opts = append(opts, chat.WithStats())
+	}
+	program, _, channelWriter := chat.RunWithChannel(ctx, ag, sessionID, sendFn, opts...)
 
 	// Set the stream writer on the runner so streaming events go through the channel
 	runner.SetStreamWriter(channelWriter)
 
+	// Ask for approval of dangerous bash commands in the chat view
+	runner.SetCommandApprover(chat.NewCommandApprover(program))
+
+	// Show why extracted memories were or weren't stored in the sidebar
+	runner.SetAsyncStatus(chat.NewAsyncStatusHandler(program))
+
 	// Run the TUI
 	finalModel, err := program.Run()
 	if err != nil {
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/builtin"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+func newConfigCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect configuration",
+	}
+
+	cmd.AddCommand(newConfigShowCmd(cfgPath))
+	cmd.AddCommand(newConfigProvidersCmd(cfgPath))
+
+	return cmd
+}
+
+func newConfigShowCmd(cfgPath *string) *cobra.Command {
+	var effective bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show configuration settings and where they came from",
+		Long: `Show the settings in the config file, or with --effective, every setting in
+effect.
+
+The effective configuration lists each setting in the config schema with its
+resolved value and where it came from:
+
+  default  built-in default
+  file     set in the config file
+  env      derived from or overridden by an environment variable
+
+API keys and the webhook secret are masked.`,
+		Example: `  ayo config show
+  ayo config show --effective
+  ayo config show --effective --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := config.Effective(*cfgPath, builtin.ConfigSchema)
+			if err != nil {
+				return err
+			}
+			if !effective {
+				settings = slices.DeleteFunc(settings, func(s config.Setting) bool {
+					return s.Source != config.SourceFile
+				})
+			}
+
+			if jsonOutput {
+				return writeJSON(map[string]interface{}{
+					"path":     *cfgPath,
+					"settings": settings,
+				})
+			}
+			printConfigSettings(*cfgPath, settings)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&effective, "effective", false, "Show every setting in effect and where it came from")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// printConfigSettings prints one line per setting: key, value, and source.
+func printConfigSettings(path string, settings []config.Setting) {
+	keyStyle := lipgloss.NewStyle().Foreground(shared.ColorPrimary)
+	valueStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+
+	if _, err := os.Stat(path); err != nil {
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Config file: %s (not found)", path)))
+	} else {
+		fmt.Println(mutedStyle.Render("Config file: " + path))
+	}
+	fmt.Println()
+	if len(settings) == 0 {
+		fmt.Println("No settings in the config file; using defaults.")
+		return
+	}
+
+	width := 0
+	for _, s := range settings {
+		width = max(width, len(s.Key))
+	}
+	for _, s := range settings {
+		value := formatSettingValue(s.Value)
+		if s.Value == nil {
+			value = mutedStyle.Render(value)
+		} else {
+			value = valueStyle.Render(value)
+		}
+		source := string(s.Source)
+		if s.Source == config.SourceEnv {
+			source += ": " + s.Detail
+		}
+		fmt.Printf("%s  %s  %s\n", keyStyle.Render(fmt.Sprintf("%-*s", width, s.Key)), value, mutedStyle.Render("("+source+")"))
+	}
+}
+
+// formatSettingValue renders a setting value on one line.
+func formatSettingValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "not set"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.TrimSpace(string(data))
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/catwalk/pkg/embedded"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// probeNoKey marks a provider that was not probed because it has no API key.
+const probeNoKey run.ProbeStatus = "no_key"
+
+// providerTarget is a provider to test and the model to test it with.
+type providerTarget struct {
+	Name      string // Provider ID as shown to the user
+	Provider  catwalk.Provider
+	Model     string
+	KeySource string // "config" or the environment variable; "" if there is no key
+}
+
+// providerTestResult is one provider's line in 'ayo config providers test'.
+type providerTestResult struct {
+	Provider   string          `json:"provider"`
+	Model      string          `json:"model"`
+	Endpoint   string          `json:"endpoint,omitempty"`
+	Key        string          `json:"key,omitempty"` // Masked
+	KeySource  string          `json:"key_source,omitempty"`
+	Status     run.ProbeStatus `json:"status"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Detail     string          `json:"detail,omitempty"`
+	LatencyMS  int64           `json:"latency_ms"`
+}
+
+func newConfigProvidersCmd(cfgPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "Inspect configured model providers",
+	}
+
+	cmd.AddCommand(newConfigProvidersTestCmd(cfgPath))
+
+	return cmd
+}
+
+func newConfigProvidersTestCmd(cfgPath *string) *cobra.Command {
+	var model string
+	var timeout time.Duration
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "test [provider]",
+		Short: "Check that providers are reachable and accept their API keys",
+		Long: `Check each configured provider by asking it for a single token, the
+cheapest request that proves the endpoint is reachable and the API key is
+accepted.
+
+The providers tested are the one in the config file and every provider
+whose API key environment variable is set. Each is tested with its default
+small model, or with --model when a single provider is named.
+
+Each provider reports one of:
+
+  ok           the provider answered
+  auth_failed  the endpoint rejected the API key (HTTP 401 or 403)
+  unreachable  the endpoint did not respond
+  error        the endpoint responded with another error, such as an
+               unknown model or a rate limit
+  no_key       no API key is set, so the provider was not tested
+  offline      offline mode is on and the provider is not on this
+               machine, so it was not tested
+
+API keys are masked in the output. Exits with status 1 if any provider
+is not ok, other than those skipped in offline mode.`,
+		Example: `  ayo config providers test
+  ayo config providers test anthropic
+  ayo config providers test openai --model gpt-4.1-nano
+  ayo config providers test --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+
+			targets := providerTargets(cfg)
+			if len(args) == 1 {
+				targets, err = filterProviderTargets(targets, args[0])
+				if err != nil {
+					return err
+				}
+			}
+			if model != "" {
+				if len(targets) != 1 {
+					return fmt.Errorf("name the provider to test with --model: %s", providerTargetNames(targets))
+				}
+				targets[0].Model = model
+			}
+			if len(targets) == 0 {
+				return fmt.Errorf("no providers configured; run 'ayo setup'")
+			}
+
+			results := make([]providerTestResult, len(targets))
+			for i, t := range targets {
+				results[i] = testProvider(cmd.Context(), t, timeout, cfg.Offline)
+			}
+
+			failed := false
+			for _, r := range results {
+				failed = failed || (r.Status != run.ProbeOK && r.Status != run.ProbeOffline)
+			}
+			if jsonOutput {
+				if err := writeJSON(results); err != nil {
+					return err
+				}
+			} else {
+				printProviderTestResults(results)
+			}
+			if failed {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&model, "model", "m", "", "model to test with (requires a single provider)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Second, "time to wait for each provider")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// providerTargets lists the config file's provider followed by each
+// provider with an API key in the environment, skipping duplicates.
+func providerTargets(cfg config.Config) []providerTarget {
+	var targets []providerTarget
+	if cfg.Provider.ID != "" {
+		p := cfg.Provider
+		t := providerTarget{Name: string(p.ID), Provider: p, Model: p.DefaultSmallModelID}
+		if t.Model == "" {
+			t.Model = config.GetProviderDefaultSmallModel(string(p.ID))
+		}
+		if t.Model == "" {
+			t.Model = cfg.DefaultModel
+		}
+		envVar := strings.ToUpper(string(p.ID)) + "_API_KEY"
+		switch {
+		case p.APIKey != "":
+			t.KeySource = "config"
+		case os.Getenv(envVar) != "":
+			t.KeySource = envVar
+		}
+		targets = append(targets, t)
+	}
+
+	for _, d := range config.GetProvidersWithCredentials() {
+		p, ok := embeddedProviderForKey(d.EnvVar)
+		if d.ID == string(cfg.Provider.ID) || (ok && p.ID == cfg.Provider.ID) {
+			continue
+		}
+		t := providerTarget{Name: d.ID, KeySource: d.EnvVar}
+		if ok {
+			t.Provider = p
+			t.Model = p.DefaultSmallModelID
+		} else {
+			t.Provider = catwalk.Provider{ID: catwalk.InferenceProvider(d.ID), Name: d.Name}
+		}
+		// The key is read from the variable setup detected; provider IDs
+		// don't always match it, as with GEMINI_API_KEY for google
+		t.Provider.APIKey = os.Getenv(d.EnvVar)
+		targets = append(targets, t)
+	}
+	return targets
+}
+
+// embeddedProviderForKey returns the catwalk provider definition that reads
+// its API key from envVar, with environment references in its endpoint
+// resolved.
+func embeddedProviderForKey(envVar string) (catwalk.Provider, bool) {
+	for _, p := range embedded.GetAll() {
+		if p.APIKey != "$"+envVar {
+			continue
+		}
+		if strings.HasPrefix(p.APIEndpoint, "$") {
+			p.APIEndpoint = os.Getenv(strings.TrimPrefix(p.APIEndpoint, "$"))
+		}
+		return p, true
+	}
+	return catwalk.Provider{}, false
+}
+
+// filterProviderTargets returns the target named name.
+func filterProviderTargets(targets []providerTarget, name string) ([]providerTarget, error) {
+	for _, t := range targets {
+		if strings.EqualFold(t.Name, name) {
+			return []providerTarget{t}, nil
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("provider %q is not configured; run 'ayo setup'", name)
+	}
+	return nil, fmt.Errorf("provider %q is not configured; configured providers: %s", name, providerTargetNames(targets))
+}
+
+func providerTargetNames(targets []providerTarget) string {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// testProvider probes t unless it has no key, or offline is set and t is not
+// a local provider. Local providers, such as Ollama, are probed without a
+// key.
+func testProvider(ctx context.Context, t providerTarget, timeout time.Duration, offline bool) providerTestResult {
+	r := providerTestResult{
+		Provider:  t.Name,
+		Model:     t.Model,
+		Endpoint:  providerEndpoint(t.Provider),
+		KeySource: t.KeySource,
+	}
+	key := t.Provider.APIKey
+	if key == "" && t.KeySource != "" {
+		key = os.Getenv(t.KeySource)
+	}
+	r.Key = maskKey(key)
+
+	switch {
+	case t.KeySource == "" && !run.IsLocalProvider(t.Provider):
+		r.Status = probeNoKey
+		r.Detail = fmt.Sprintf("%s is not set", strings.ToUpper(t.Name)+"_API_KEY")
+		return r
+	case t.Model == "":
+		r.Status = run.ProbeError
+		r.Detail = "no model to test with; pass --model"
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	probe := run.ProbeProvider(ctx, t.Provider, t.Model, offline)
+	r.Status = probe.Status
+	r.StatusCode = probe.StatusCode
+	r.Detail = probe.Detail
+	r.LatencyMS = probe.Latency.Milliseconds()
+	return r
+}
+
+// maskKey hides all but the last four characters of an API key. Short keys
+// are hidden entirely.
+func maskKey(key string) string {
+	switch {
+	case key == "":
+		return ""
+	case len(key) < 12:
+		return "****"
+	default:
+		return "****" + key[len(key)-4:]
+	}
+}
+
+func printProviderTestResults(results []providerTestResult) {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Width(14)
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Provider Test"))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 50)))
+	fmt.Println()
+
+	passed := 0
+	for _, r := range results {
+		var status string
+		switch r.Status {
+		case run.ProbeOK:
+			passed++
+			status = okStyle.Render("OK")
+		case probeNoKey:
+			status = warnStyle.Render("NO KEY")
+		case run.ProbeOffline:
+			status = warnStyle.Render("OFFLINE")
+		case run.ProbeAuthFailed:
+			status = errStyle.Render("AUTH FAILED")
+		case run.ProbeUnreachable:
+			status = errStyle.Render("UNREACHABLE")
+		default:
+			status = errStyle.Render("ERROR")
+		}
+		detail := r.Model
+		if r.Key != "" {
+			detail += fmt.Sprintf("  key %s (%s)", r.Key, r.KeySource)
+		}
+		if r.Status != probeNoKey && r.Status != run.ProbeOffline {
+			detail += fmt.Sprintf("  %dms", r.LatencyMS)
+		}
+		fmt.Printf("  %s %s  %s\n", labelStyle.Render(r.Provider+":"), status, detail)
+		if r.Status != run.ProbeOK && r.Detail != "" {
+			fmt.Printf("  %s %s\n", labelStyle.Render(""), hintStyle.Render(r.Detail))
+		}
+	}
+	fmt.Println()
+
+	summary := fmt.Sprintf("  %d of %d providers ok", passed, len(results))
+	if passed < len(results) {
+		fmt.Println(errStyle.Render(summary))
+	} else {
+		fmt.Println(okStyle.Render(summary))
+	}
+	fmt.Println()
+}
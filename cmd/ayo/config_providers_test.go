@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+func TestProviderTargets(t *testing.T) {
+	for _, d := range config.DetectProviders() {
+		t.Setenv(d.EnvVar, "")
+	}
+	t.Setenv("GEMINI_API_KEY", "gemini-key-0123456789")
+	t.Setenv("ANTHROPIC_API_KEY", "anthropic-key-0123456789")
+
+	cfg := config.Config{Provider: catwalk.Provider{ID: "anthropic", Type: catwalk.TypeAnthropic}}
+	targets := providerTargets(cfg)
+	if len(targets) != 2 {
+		t.Fatalf("targets = %+v, want anthropic and google", targets)
+	}
+
+	configured := targets[0]
+	if configured.Name != "anthropic" || configured.KeySource != "ANTHROPIC_API_KEY" || configured.Model == "" {
+		t.Errorf("configured target = %+v", configured)
+	}
+	google := targets[1]
+	if google.Name != "google" || google.Provider.Type != catwalk.TypeGoogle || google.Model == "" {
+		t.Errorf("google target = %+v", google)
+	}
+	if google.Provider.APIKey != "gemini-key-0123456789" {
+		t.Errorf("google key = %q, want the GEMINI_API_KEY value", google.Provider.APIKey)
+	}
+
+	if _, err := filterProviderTargets(targets, "Google"); err != nil {
+		t.Errorf("filter by name: %v", err)
+	}
+	if _, err := filterProviderTargets(targets, "groq"); err == nil {
+		t.Error("expected an error for a provider that is not configured")
+	}
+}
+
+func TestTestProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"c1","object":"chat.completion","created":1,"model":"test",
+			"choices":[{"index":0,"message":{"role":"assistant","content":"p"},"finish_reason":"length"}]}`)
+	}))
+	defer server.Close()
+
+	target := providerTarget{
+		Name:      "test",
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "sk-test-0123456789abcd"},
+		Model:     "test",
+		KeySource: "config",
+	}
+	r := testProvider(context.Background(), target, 5*time.Second, false)
+	if r.Status != run.ProbeOK {
+		t.Errorf("status = %s (%s), want ok", r.Status, r.Detail)
+	}
+	if r.Key != "****abcd" {
+		t.Errorf("key = %q, want it masked", r.Key)
+	}
+
+	// Local endpoints such as the test server are probed without a key
+	target.Provider.APIEndpoint = "https://api.example.com/v1"
+	target.Provider.APIKey, target.KeySource = "", ""
+	if r := testProvider(context.Background(), target, 5*time.Second, false); r.Status != probeNoKey {
+		t.Errorf("status without a key = %s, want %s", r.Status, probeNoKey)
+	}
+}
+
+func TestTestProviderOffline(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"c1","object":"chat.completion","created":1,"model":"test",
+			"choices":[{"index":0,"message":{"role":"assistant","content":"p"},"finish_reason":"length"}]}`)
+	}))
+	defer server.Close()
+
+	// Local providers are still probed in offline mode
+	local := providerTarget{
+		Name:     "ollama",
+		Provider: catwalk.Provider{ID: "ollama", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL},
+		Model:    "test",
+	}
+	if r := testProvider(context.Background(), local, 5*time.Second, true); r.Status != run.ProbeOK {
+		t.Errorf("local status = %s (%s), want ok", r.Status, r.Detail)
+	}
+
+	remote := providerTarget{
+		Name:      "openai",
+		Provider:  catwalk.Provider{ID: "openai", Type: catwalk.TypeOpenAI, APIEndpoint: "https://api.openai.com/v1", APIKey: "sk-test-0123456789abcd"},
+		Model:     "gpt-4.1-nano",
+		KeySource: "config",
+	}
+	r := testProvider(context.Background(), remote, 5*time.Second, true)
+	if r.Status != run.ProbeOffline || !strings.Contains(r.Detail, "offline mode") {
+		t.Errorf("remote status = %s (%s), want offline", r.Status, r.Detail)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want only the local provider probed", requests)
+	}
+}
+
+func TestMaskKey(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"short":                  "****",
+		"sk-proj-abcdefghij1234": "****1234",
+	}
+	for key, want := range tests {
+		if got := maskKey(key); got != want {
+			t.Errorf("maskKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
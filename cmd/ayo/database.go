@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+// warnNoPersistence reports that a command is continuing without what (such
+// as "session persistence") because the database couldn't be opened. A
+// database locked by another ayo process is always reported, since the
+// user would otherwise lose history without knowing why; other failures
+// only with debug.
+func warnNoPersistence(what string, err error, debug bool) {
+	if debug || db.IsLocked(err) {
+		fmt.Fprintf(os.Stderr, "Warning: %s unavailable: %v\n", what, err)
+	}
+}
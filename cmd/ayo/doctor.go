@@ -1,33 +1,63 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kaptinlin/jsonschema"
 	"github.com/spf13/cobra"
 
+	"github.com/alexcabrera/ayo/internal/builtin"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/db"
 	"github.com/alexcabrera/ayo/internal/ollama"
 	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/plugins"
 	"github.com/alexcabrera/ayo/internal/version"
 )
 
+// checkStatus is the outcome of a single doctor check.
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// doctorCheck is the result of a single doctor check.
+type doctorCheck struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Hint   string // How to fix a warning or failure
+}
+
 func newDoctorCmd(cfgPath *string) *cobra.Command {
 	var verbose bool
 
 	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Check system health and dependencies",
-		Long:  "Diagnose the ayo installation, checking Ollama, models, database, and configuration.",
+		Long: `Diagnose the ayo installation, checking configuration, providers, Ollama,
+built-ins, directories, the database, and plugins.
+
+Each check reports OK, WARN, or FAIL with a hint on how to fix it.
+Exits with status 1 if any check fails.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := loadConfig(*cfgPath)
-			if err != nil {
-				cfg = config.Config{}
+			cfg, cfgErr := loadConfig(*cfgPath)
+			if cfgErr != nil {
+				cfg = config.Default()
 			}
 
 			ctx := cmd.Context()
@@ -37,19 +67,30 @@ func newDoctorCmd(cfgPath *string) *cobra.Command {
 			okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
 			warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
 			errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 			labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Width(30)
 
-			check := func(name string, ok bool, msg string) {
-				status := okStyle.Render("OK")
-				if !ok {
+			var passed, warnings, failures int
+			report := func(c doctorCheck) {
+				var status string
+				switch c.Status {
+				case checkPass:
+					passed++
+					status = okStyle.Render("OK")
+				case checkWarn:
+					warnings++
+					status = warnStyle.Render("WARN")
+				default:
+					failures++
 					status = errStyle.Render("FAIL")
 				}
-				fmt.Printf("  %s %s %s\n", labelStyle.Render(name), status, msg)
+				fmt.Printf("  %s %s %s\n", labelStyle.Render(c.Name+":"), status, c.Detail)
+				if c.Hint != "" && c.Status != checkPass {
+					fmt.Printf("  %s %s\n", labelStyle.Render(""), hintStyle.Render("hint: "+c.Hint))
+				}
 			}
-
-			warn := func(name string, msg string) {
-				status := warnStyle.Render("WARN")
-				fmt.Printf("  %s %s %s\n", labelStyle.Render(name), status, msg)
+			section := func(title string) {
+				fmt.Println(headerStyle.Render("  " + title))
 			}
 
 			fmt.Println()
@@ -58,178 +99,492 @@ func newDoctorCmd(cfgPath *string) *cobra.Command {
 			fmt.Println()
 
 			// Version info
-			fmt.Println(headerStyle.Render("  System"))
+			section("System")
 			fmt.Printf("  %s %s\n", labelStyle.Render("Ayo Version:"), version.Version)
 			fmt.Printf("  %s %s\n", labelStyle.Render("Go Version:"), runtime.Version())
 			fmt.Printf("  %s %s/%s\n", labelStyle.Render("Platform:"), runtime.GOOS, runtime.GOARCH)
 			fmt.Println()
 
-			// Check paths
-			fmt.Println(headerStyle.Render("  Paths"))
-			configExists := fileExists(paths.ConfigFile())
-			if configExists {
-				check("Config File:", true, paths.ConfigFile())
+			// Configuration
+			section("Configuration")
+			report(checkConfigFile(*cfgPath, builtin.ConfigSchema))
+			if cfg.DefaultModel != "" {
+				report(doctorCheck{Name: "Default Model", Status: checkPass, Detail: cfg.DefaultModel})
 			} else {
-				warn("Config File:", paths.ConfigFile()+" (using defaults)")
+				report(doctorCheck{
+					Name:   "Default Model",
+					Status: checkWarn,
+					Detail: "not set",
+					Hint:   "set default_model in the config or run 'ayo setup'",
+				})
 			}
+			fmt.Println()
 
-			dbExists := fileExists(paths.DatabasePath())
-			check("Database:", dbExists, paths.DatabasePath())
+			// Provider
+			section("Provider")
+			report(checkProviderKey(cfg.Provider))
+			report(checkProviderEndpoint(ctx, cfg.Provider))
+			fmt.Println()
 
-			builtinExists := dirExists(paths.UserDataDir())
-			check("Data Directory:", builtinExists, paths.UserDataDir())
+			// Directories
+			section("Directories")
+			report(checkWritableDir("Config Directory", paths.ConfigDir()))
+			report(checkWritableDir("Data Directory", paths.DataDir()))
 			fmt.Println()
 
-			// Check Ollama
-			fmt.Println(headerStyle.Render("  Ollama"))
-			ollamaHost := cfg.OllamaHost
-			if ollamaHost == "" {
-				ollamaHost = "http://localhost:11434"
+			// Built-ins
+			section("Built-ins")
+			for _, c := range checkBuiltins() {
+				report(c)
 			}
+			fmt.Println()
 
-			// Check if ollama binary exists
-			ollamaPath, pathErr := exec.LookPath("ollama")
-			if pathErr != nil {
-				check("Binary:", false, "not found in PATH")
-			} else {
-				check("Binary:", true, ollamaPath)
+			// Ollama
+			section("Ollama")
+			for _, c := range checkOllama(ctx, cfg, verbose) {
+				report(c)
 			}
+			fmt.Println()
 
-			// Check if Ollama is running
-			client := ollama.NewClient(ollama.WithHost(ollamaHost))
-			isAvailable := client.IsAvailable(ctx)
-			check("Service:", isAvailable, ollamaHost)
-
-			if isAvailable {
-				// Check version
-				ver, verErr := client.GetVersion(ctx)
-				if verErr != nil {
-					warn("Version:", "could not get version")
-				} else {
-					check("Version:", true, ver)
-				}
-
-				// Check models
-				models, modelsErr := client.ListModels(ctx)
-				if modelsErr != nil {
-					warn("Models:", "could not list models")
-				} else {
-					check("Models:", len(models) > 0, fmt.Sprintf("%d installed", len(models)))
-
-					if verbose {
-						for _, m := range models {
-							fmt.Printf("       - %s\n", m.Name)
-						}
-					}
-
-					// Check for required models
-					embModel := cfg.Embedding.Model
-					if embModel == "" {
-						embModel = "nomic-embed-text"
-					}
-					hasEmbedding := false
-					for _, m := range models {
-						if strings.HasPrefix(m.Name, embModel) || strings.Contains(m.Name, embModel) {
-							hasEmbedding = true
-							break
-						}
-					}
-					check("Embedding Model:", hasEmbedding, embModel)
-
-					smallModel := cfg.SmallModel
-					if smallModel == "" {
-						smallModel = "ministral-3:3b"
-					}
-					// Strip provider prefix (e.g., "ollama/") if present
-					if strings.Contains(smallModel, "/") {
-						parts := strings.SplitN(smallModel, "/", 2)
-						smallModel = parts[len(parts)-1]
-					}
-					hasSmall := false
-					for _, m := range models {
-						if strings.HasPrefix(m.Name, smallModel) || strings.Contains(m.Name, smallModel) {
-							hasSmall = true
-							break
-						}
-					}
-					check("Small Model:", hasSmall, smallModel)
-				}
-			} else {
-				warn("Service:", "not running - memory features will be disabled")
+			// Database
+			section("Database")
+			for _, c := range checkDatabase(ctx) {
+				report(c)
 			}
 			fmt.Println()
 
-			// Check database
-			fmt.Println(headerStyle.Render("  Database"))
-			if dbExists {
-				dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
-				if err != nil {
-					check("Connection:", false, err.Error())
-				} else {
-					check("Connection:", true, "OK")
-					defer dbConn.Close()
-
-					// Count sessions and memories
-					sessions, _ := queries.CountSessions(ctx)
-					check("Sessions:", true, fmt.Sprintf("%d", sessions))
-
-					// Count active memories
-					memories, _ := queries.ListMemories(ctx, db.ListMemoriesParams{
-						Lim: 1000,
-					})
-					check("Active Memories:", true, fmt.Sprintf("%d", len(memories)))
-				}
-			} else {
-				warn("Database:", "not created yet - run 'ayo setup'")
+			// Plugins
+			section("Plugins")
+			for _, c := range checkPlugins() {
+				report(c)
 			}
 			fmt.Println()
 
-			// Check config
-			fmt.Println(headerStyle.Render("  Configuration"))
-			if configExists {
-				if cfg.DefaultModel != "" {
-					check("Default Model:", true, cfg.DefaultModel)
-				} else {
-					warn("Default Model:", "not set")
-				}
+			summary := fmt.Sprintf("  %d passed, %d warnings, %d failed", passed, warnings, failures)
+			if failures > 0 {
+				fmt.Println(errStyle.Render(summary))
+				fmt.Println()
+				os.Exit(1)
+			}
+			if warnings > 0 {
+				fmt.Println(warnStyle.Render(summary))
 			} else {
-				warn("Config:", "not found - using defaults")
+				fmt.Println(okStyle.Render(summary))
 			}
 			fmt.Println()
 
-			// Recommendations
-			var recommendations []string
-			if !isAvailable {
-				recommendations = append(recommendations, "Start Ollama: ollama serve")
-			}
-			if pathErr != nil {
-				recommendations = append(recommendations, "Install Ollama: https://ollama.ai")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed output")
+
+	return cmd
+}
+
+// checkConfigFile verifies that the config file parses and matches the schema.
+func checkConfigFile(path string, schema []byte) doctorCheck {
+	c := doctorCheck{Name: "Config File"}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		c.Status = checkWarn
+		c.Detail = path + " (using defaults)"
+		c.Hint = "run 'ayo setup' to create one"
+		return c
+	}
+	if err != nil {
+		c.Status = checkFail
+		c.Detail = err.Error()
+		c.Hint = "check the permissions on " + path
+		return c
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("%s: invalid JSON: %v", path, err)
+		c.Hint = "fix the JSON syntax, or move the file aside and run 'ayo setup'"
+		return c
+	}
+
+	if len(schema) > 0 {
+		compiled, err := jsonschema.NewCompiler().Compile(schema)
+		if err != nil {
+			c.Status = checkWarn
+			c.Detail = fmt.Sprintf("could not compile config schema: %v", err)
+			return c
+		}
+		if result := compiled.Validate(doc); !result.IsValid() {
+			var problems []string
+			for field, e := range result.Errors {
+				problems = append(problems, fmt.Sprintf("%s: %s", field, e.Message))
 			}
+			sort.Strings(problems)
+			c.Status = checkFail
+			c.Detail = fmt.Sprintf("%s does not match the schema: %s", path, strings.Join(problems, "; "))
+			c.Hint = "see " + paths.ConfigSchemaFile() + " for valid fields"
+			return c
+		}
+	}
 
+	c.Status = checkPass
+	c.Detail = path
+	return c
+}
 
-			if len(recommendations) > 0 {
-				fmt.Println(headerStyle.Render("  Recommendations"))
-				for _, r := range recommendations {
-					fmt.Printf("  - %s\n", r)
-				}
-				fmt.Println()
+// providerEndpoint returns the API endpoint used for a provider.
+func providerEndpoint(p catwalk.Provider) string {
+	if p.APIEndpoint != "" {
+		return p.APIEndpoint
+	}
+	switch p.Type {
+	case catwalk.TypeOpenAI:
+		return "https://api.openai.com/v1"
+	case catwalk.TypeAnthropic:
+		return "https://api.anthropic.com"
+	case catwalk.TypeGoogle:
+		return "https://generativelanguage.googleapis.com"
+	case catwalk.TypeOpenRouter:
+		return "https://openrouter.ai/api/v1"
+	default:
+		return ""
+	}
+}
+
+// checkProviderKey verifies that an API key is available for the configured provider.
+func checkProviderKey(p catwalk.Provider) doctorCheck {
+	c := doctorCheck{Name: "API Key"}
+	if p.ID == "" {
+		c.Status = checkFail
+		c.Detail = "no provider configured"
+		c.Hint = "run 'ayo setup' to configure a provider"
+		return c
+	}
+
+	envVar := strings.ToUpper(string(p.ID)) + "_API_KEY"
+	switch {
+	case p.APIKey != "":
+		c.Status = checkPass
+		c.Detail = fmt.Sprintf("%s (from config)", p.ID)
+	case os.Getenv(envVar) != "":
+		c.Status = checkPass
+		c.Detail = fmt.Sprintf("%s (from %s)", p.ID, envVar)
+	default:
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("%s: %s is not set", p.ID, envVar)
+		c.Hint = fmt.Sprintf("export %s or run 'ayo setup'", envVar)
+	}
+	return c
+}
+
+// checkProviderEndpoint verifies that the provider's API endpoint responds.
+// Any HTTP response counts as reachable; authentication is not checked.
+func checkProviderEndpoint(ctx context.Context, p catwalk.Provider) doctorCheck {
+	c := doctorCheck{Name: "Endpoint"}
+
+	endpoint := providerEndpoint(p)
+	if endpoint == "" {
+		c.Status = checkWarn
+		c.Detail = fmt.Sprintf("unknown endpoint for provider type %q", p.Type)
+		c.Hint = "set provider.api_endpoint in the config"
+		return c
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("invalid endpoint %q: %v", endpoint, err)
+		c.Hint = "fix provider.api_endpoint in the config"
+		return c
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("%s unreachable: %v", endpoint, err)
+		c.Hint = "check your network connection and provider.api_endpoint"
+		return c
+	}
+	resp.Body.Close()
+
+	c.Status = checkPass
+	c.Detail = endpoint
+	return c
+}
+
+// checkWritableDir verifies that dir exists and files can be created in it.
+func checkWritableDir(name, dir string) doctorCheck {
+	c := doctorCheck{Name: name}
+
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		c.Status = checkFail
+		c.Detail = dir + " does not exist"
+		c.Hint = "create it with: mkdir -p " + dir
+		return c
+	}
+
+	f, err := os.CreateTemp(dir, ".ayo-doctor-*")
+	if err != nil {
+		c.Status = checkFail
+		c.Detail = dir + " is not writable"
+		c.Hint = "fix the permissions with: chmod u+w " + dir
+		return c
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	c.Status = checkPass
+	c.Detail = dir
+	return c
+}
+
+// checkBuiltins verifies that built-in agents and skills are installed and current.
+func checkBuiltins() []doctorCheck {
+	const reinstallHint = "run 'ayo agents update --force' to reinstall built-ins"
+	var checks []doctorCheck
+
+	installed, err := os.ReadFile(builtin.VersionFile())
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{
+			Name: "Version", Status: checkFail, Detail: "not installed", Hint: reinstallHint,
+		})
+	case string(installed) != builtin.Version:
+		checks = append(checks, doctorCheck{
+			Name:   "Version",
+			Status: checkFail,
+			Detail: fmt.Sprintf("installed %s, expected %s", installed, builtin.Version),
+			Hint:   reinstallHint,
+		})
+	default:
+		checks = append(checks, doctorCheck{Name: "Version", Status: checkPass, Detail: builtin.Version})
+	}
+
+	var missing []string
+	agents := builtin.ListAgents()
+	for _, handle := range agents {
+		if !builtin.IsInstalled(handle) {
+			missing = append(missing, handle)
+		}
+	}
+	if len(missing) > 0 {
+		checks = append(checks, doctorCheck{
+			Name:   "Agents",
+			Status: checkFail,
+			Detail: "missing " + strings.Join(missing, ", "),
+			Hint:   reinstallHint,
+		})
+	} else {
+		checks = append(checks, doctorCheck{
+			Name: "Agents", Status: checkPass, Detail: fmt.Sprintf("%d installed", len(agents)),
+		})
+	}
+
+	var modified []string
+	if agents, err := builtin.CheckModifiedAgents(); err == nil {
+		for _, m := range agents {
+			modified = append(modified, m.Handle)
+		}
+	}
+	if skills, err := builtin.CheckModifiedSkills(); err == nil {
+		for _, m := range skills {
+			modified = append(modified, m.Name)
+		}
+	}
+	if len(modified) > 0 {
+		checks = append(checks, doctorCheck{
+			Name:   "Local Modifications",
+			Status: checkWarn,
+			Detail: strings.Join(modified, ", "),
+			Hint:   "modified built-ins are overwritten on upgrade; copy changes to " + paths.AgentsDir(),
+		})
+	}
+
+	return checks
+}
+
+// checkOllama verifies Ollama and the models used for embeddings and the small model.
+// Ollama is optional, so problems are reported as warnings.
+func checkOllama(ctx context.Context, cfg config.Config, verbose bool) []doctorCheck {
+	var checks []doctorCheck
+
+	ollamaHost := cfg.OllamaHost
+	if ollamaHost == "" {
+		ollamaHost = "http://localhost:11434"
+	}
+
+	// Check if ollama binary exists
+	if ollamaPath, err := exec.LookPath("ollama"); err != nil {
+		checks = append(checks, doctorCheck{
+			Name: "Binary", Status: checkWarn, Detail: "not found in PATH",
+			Hint: "install Ollama from https://ollama.ai",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "Binary", Status: checkPass, Detail: ollamaPath})
+	}
+
+	// Check if Ollama is running
+	client := ollama.NewClient(ollama.WithHost(ollamaHost))
+	if !client.IsAvailable(ctx) {
+		checks = append(checks, doctorCheck{
+			Name:   "Service",
+			Status: checkWarn,
+			Detail: ollamaHost + " not running - memory features will be disabled",
+			Hint:   "start Ollama with: ollama serve",
+		})
+		return checks
+	}
+	checks = append(checks, doctorCheck{Name: "Service", Status: checkPass, Detail: ollamaHost})
+
+	if ver, err := client.GetVersion(ctx); err != nil {
+		checks = append(checks, doctorCheck{Name: "Version", Status: checkWarn, Detail: "could not get version"})
+	} else {
+		checks = append(checks, doctorCheck{Name: "Version", Status: checkPass, Detail: ver})
+	}
+
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		checks = append(checks, doctorCheck{Name: "Models", Status: checkWarn, Detail: "could not list models"})
+		return checks
+	}
+	modelsCheck := doctorCheck{Name: "Models", Status: checkPass, Detail: fmt.Sprintf("%d installed", len(models))}
+	if len(models) == 0 {
+		modelsCheck.Status = checkWarn
+	}
+	if verbose {
+		for _, m := range models {
+			modelsCheck.Detail += "\n       - " + m.Name
+		}
+	}
+	checks = append(checks, modelsCheck)
+
+	hasModel := func(name string) bool {
+		for _, m := range models {
+			if strings.HasPrefix(m.Name, name) || strings.Contains(m.Name, name) {
+				return true
 			}
+		}
+		return false
+	}
 
-			return nil
-		},
+	embModel := cfg.Embedding.Model
+	if embModel == "" {
+		embModel = "nomic-embed-text"
+	}
+	if hasModel(embModel) {
+		checks = append(checks, doctorCheck{Name: "Embedding Model", Status: checkPass, Detail: embModel})
+	} else {
+		checks = append(checks, doctorCheck{
+			Name: "Embedding Model", Status: checkWarn, Detail: embModel + " not installed",
+			Hint: "ollama pull " + embModel,
+		})
 	}
 
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed output")
+	smallModel := cfg.SmallModel
+	if smallModel == "" {
+		smallModel = "ministral-3:3b"
+	}
+	// Strip provider prefix (e.g., "ollama/") if present
+	if strings.Contains(smallModel, "/") {
+		parts := strings.SplitN(smallModel, "/", 2)
+		smallModel = parts[len(parts)-1]
+	}
+	if hasModel(smallModel) {
+		checks = append(checks, doctorCheck{Name: "Small Model", Status: checkPass, Detail: smallModel})
+	} else {
+		checks = append(checks, doctorCheck{
+			Name: "Small Model", Status: checkWarn, Detail: smallModel + " not installed",
+			Hint: "ollama pull " + smallModel,
+		})
+	}
 
-	return cmd
+	return checks
 }
 
-func fileExists(path string) bool {
-	info, err := os.Stat(path)
-	return err == nil && !info.IsDir()
+// checkDatabase verifies the session database can be opened.
+func checkDatabase(ctx context.Context) []doctorCheck {
+	dbPath := paths.DatabasePath()
+	if !fileExists(dbPath) {
+		return []doctorCheck{{
+			Name: "Database", Status: checkWarn, Detail: "not created yet",
+			Hint: "run 'ayo setup' or start a chat to create it",
+		}}
+	}
+
+	dbConn, queries, err := db.ConnectWithQueries(ctx, dbPath)
+	if err != nil {
+		return []doctorCheck{{
+			Name: "Connection", Status: checkFail, Detail: err.Error(),
+			Hint: "check the permissions on " + dbPath,
+		}}
+	}
+	defer dbConn.Close()
+
+	checks := []doctorCheck{{Name: "Connection", Status: checkPass, Detail: dbPath}}
+
+	// Count sessions and memories
+	sessions, _ := queries.CountSessions(ctx)
+	checks = append(checks, doctorCheck{Name: "Sessions", Status: checkPass, Detail: fmt.Sprintf("%d", sessions)})
+
+	// Count active memories
+	memories, _ := queries.ListMemories(ctx, db.ListMemoriesParams{
+		Lim: 1000,
+	})
+	checks = append(checks, doctorCheck{Name: "Active Memories", Status: checkPass, Detail: fmt.Sprintf("%d", len(memories))})
+
+	return checks
 }
 
-func dirExists(path string) bool {
+// checkPlugins verifies the plugin registry and each installed plugin.
+func checkPlugins() []doctorCheck {
+	reg, err := plugins.LoadRegistry()
+	if err != nil {
+		return []doctorCheck{{
+			Name: "Registry", Status: checkFail, Detail: err.Error(),
+			Hint: "fix or remove " + plugins.RegistryPath() + ", then reinstall plugins",
+		}}
+	}
+
+	installed := reg.List()
+	checks := []doctorCheck{{Name: "Registry", Status: checkPass, Detail: fmt.Sprintf("%d plugins", len(installed))}}
+
+	for _, p := range installed {
+		name := "Plugin " + p.Name
+		problems := plugins.Diagnose(p)
+		if len(problems) == 0 {
+			detail := p.Version
+			if p.Disabled {
+				detail += " (disabled)"
+			}
+			checks = append(checks, doctorCheck{Name: name, Status: checkPass, Detail: detail})
+			continue
+		}
+
+		// Missing binaries alone are a warning; a broken install fails
+		status := checkWarn
+		var details []string
+		for _, problem := range problems {
+			if problem.Kind != plugins.ProblemMissingDep {
+				status = checkFail
+			}
+			details = append(details, problem.Detail)
+		}
+		checks = append(checks, doctorCheck{
+			Name:   name,
+			Status: status,
+			Detail: strings.Join(details, "; "),
+			Hint:   "run 'ayo plugins doctor' for suggested fixes",
+		})
+	}
+
+	return checks
+}
+
+func fileExists(path string) bool {
 	info, err := os.Stat(path)
-	return err == nil && info.IsDir()
+	return err == nil && !info.IsDir()
 }
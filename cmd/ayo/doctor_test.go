@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/plugins"
+)
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ayo.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckConfigFile(t *testing.T) {
+	missing := checkConfigFile(filepath.Join(t.TempDir(), "missing.json"), configSchema)
+	if missing.Status != checkWarn {
+		t.Errorf("missing config status = %v, want warn", missing.Status)
+	}
+
+	valid := checkConfigFile(writeTestConfig(t, `{"default_model": "gpt-4.1", "ollama_host": "http://localhost:11434"}`), configSchema)
+	if valid.Status != checkPass {
+		t.Errorf("valid config status = %v (%s), want pass", valid.Status, valid.Detail)
+	}
+
+	broken := checkConfigFile(writeTestConfig(t, `{"default_model": `), configSchema)
+	if broken.Status != checkFail || !strings.Contains(broken.Detail, "invalid JSON") {
+		t.Errorf("broken config = %+v, want invalid JSON failure", broken)
+	}
+
+	wrongType := checkConfigFile(writeTestConfig(t, `{"default_model": 42}`), configSchema)
+	if wrongType.Status != checkFail || !strings.Contains(wrongType.Detail, "does not match the schema") {
+		t.Errorf("wrong type config = %+v, want schema failure", wrongType)
+	}
+	if wrongType.Hint == "" {
+		t.Error("schema failure should include a hint")
+	}
+}
+
+func TestCheckConfigFileAcceptsSavedDefaults(t *testing.T) {
+	// Configs written by 'ayo setup' must validate against the schema
+	data, err := json.Marshal(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checkConfigFile(writeTestConfig(t, string(data)), configSchema)
+	if c.Status != checkPass {
+		t.Errorf("default config status = %v (%s), want pass", c.Status, c.Detail)
+	}
+}
+
+func TestCheckProviderKey(t *testing.T) {
+	t.Setenv("GROQ_API_KEY", "")
+	missing := checkProviderKey(catwalk.Provider{ID: "groq"})
+	if missing.Status != checkFail || !strings.Contains(missing.Hint, "GROQ_API_KEY") {
+		t.Errorf("missing key = %+v, want failure naming GROQ_API_KEY", missing)
+	}
+
+	t.Setenv("GROQ_API_KEY", "test-key")
+	if c := checkProviderKey(catwalk.Provider{ID: "groq"}); c.Status != checkPass {
+		t.Errorf("env key status = %v, want pass", c.Status)
+	}
+
+	if c := checkProviderKey(catwalk.Provider{}); c.Status != checkFail {
+		t.Errorf("no provider status = %v, want fail", c.Status)
+	}
+}
+
+func TestCheckProviderEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	reachable := checkProviderEndpoint(context.Background(), catwalk.Provider{APIEndpoint: server.URL})
+	if reachable.Status != checkPass {
+		t.Errorf("reachable endpoint status = %v (%s), want pass", reachable.Status, reachable.Detail)
+	}
+
+	server.Close()
+	down := checkProviderEndpoint(context.Background(), catwalk.Provider{APIEndpoint: server.URL})
+	if down.Status != checkFail || down.Hint == "" {
+		t.Errorf("closed endpoint = %+v, want failure with hint", down)
+	}
+
+	unknown := checkProviderEndpoint(context.Background(), catwalk.Provider{Type: "custom"})
+	if unknown.Status != checkWarn {
+		t.Errorf("unknown endpoint status = %v, want warn", unknown.Status)
+	}
+}
+
+func TestCheckWritableDir(t *testing.T) {
+	dir := t.TempDir()
+	if c := checkWritableDir("Data Directory", dir); c.Status != checkPass {
+		t.Errorf("writable dir status = %v (%s), want pass", c.Status, c.Detail)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("check left %d files behind", len(entries))
+	}
+
+	missing := checkWritableDir("Data Directory", filepath.Join(dir, "missing"))
+	if missing.Status != checkFail || !strings.Contains(missing.Hint, "mkdir -p") {
+		t.Errorf("missing dir = %+v, want failure with mkdir hint", missing)
+	}
+
+	if os.Geteuid() != 0 {
+		readOnly := filepath.Join(dir, "readonly")
+		if err := os.Mkdir(readOnly, 0o555); err != nil {
+			t.Fatal(err)
+		}
+		if c := checkWritableDir("Data Directory", readOnly); c.Status != checkFail {
+			t.Errorf("read-only dir status = %v, want fail", c.Status)
+		}
+	}
+}
+
+func TestCheckPlugins(t *testing.T) {
+	dataDir := t.TempDir()
+	plugins.SetTestDataDir(dataDir)
+	defer plugins.SetTestDataDir("")
+
+	checks := checkPlugins()
+	if len(checks) != 1 || checks[0].Status != checkPass {
+		t.Fatalf("empty registry checks = %+v, want one passing check", checks)
+	}
+
+	reg, err := plugins.LoadRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(&plugins.InstalledPlugin{Name: "gone", Version: "1.0.0", Path: filepath.Join(dataDir, "plugins", "gone")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	checks = checkPlugins()
+	if len(checks) != 2 || checks[1].Status != checkFail || !strings.Contains(checks[1].Detail, "missing") {
+		t.Errorf("missing plugin checks = %+v, want failure for missing directory", checks)
+	}
+
+	if err := os.WriteFile(plugins.RegistryPath(), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checks = checkPlugins()
+	if len(checks) != 1 || checks[0].Status != checkFail || checks[0].Hint == "" {
+		t.Errorf("corrupt registry checks = %+v, want failure with hint", checks)
+	}
+}
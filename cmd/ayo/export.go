@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/builtin"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/export"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/redact"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/ui"
+)
+
+func newExportCmd(cfgPath *string) *cobra.Command {
+	var output string
+	var agentHandles []string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "export [session-id...]",
+		Short: "Package sessions, agents, and config into a bundle for bug reports",
+		Long: `Package sessions, the agents they used, and the effective config into a
+tar.gz bundle to attach to a bug report.
+
+The bundle contains:
+
+  manifest.json       ayo version, platform, and the bundle's contents
+  config.json         effective config settings, as in 'ayo config show --effective'
+  agents/@handle/     each agent's definition files
+  sessions/<id>.json  each session's transcript
+
+Secrets are redacted from every file with the built-in redaction patterns
+and any in the redaction config, even when redaction is disabled. API keys
+and the webhook secret are masked in the config. File attachments in
+transcripts are left out.
+
+Sessions are matched by ID, ID prefix, or title. Without session IDs or
+--agent, a list of recent sessions is shown to choose from.
+
+Review a bundle before sharing it with 'ayo export inspect'. Inspecting only
+reads the bundle; nothing in it is run.`,
+		Example: `  ayo export 4443df27
+  ayo export 4443df27 9c1e02aa -o bug-report.tar.gz
+  ayo export --agent @reviewer
+  ayo export inspect bug-report.tar.gz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			// Bundles are shared, so they are redacted even when provider
+			// requests are not
+			redactor, err := redact.New(cfg.Redaction.Patterns, cfg.Redaction.DisableBuiltin)
+			if err != nil {
+				return fmt.Errorf("redaction config: %w", err)
+			}
+			settings, err := config.Effective(*cfgPath, builtin.ConfigSchema)
+			if err != nil {
+				return err
+			}
+
+			services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer services.Close()
+
+			sessions, err := exportSessions(cmd, services, args, len(agentHandles) > 0)
+			if err != nil {
+				return err
+			}
+
+			var handles []string
+			for _, h := range agentHandles {
+				handles = append(handles, agent.NormalizeHandle(h))
+			}
+			for _, s := range sessions {
+				handles = append(handles, s.AgentHandle)
+			}
+			slices.Sort(handles)
+			handles = slices.Compact(handles)
+
+			if output == "" {
+				output = fmt.Sprintf("ayo-export-%s.tar.gz", time.Now().Format("20060102-150405"))
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			manifest, err := writeExport(cmd, f, cfg, redactor, settings, handles, services, sessions)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(output)
+				return err
+			}
+
+			if jsonOutput {
+				return writeJSON(map[string]any{
+					"path":     output,
+					"manifest": manifest,
+				})
+			}
+			fmt.Printf("Exported %s and %s to %s\n",
+				pluralize(len(manifest.Sessions), "session"),
+				pluralize(len(manifest.Agents), "agent"),
+				output)
+			if manifest.Redactions > 0 {
+				fmt.Printf("Redacted %s\n", pluralize(manifest.Redactions, "secret"))
+			}
+			fmt.Printf("Review it before sharing: ayo export inspect %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "bundle path (default ayo-export-<time>.tar.gz)")
+	cmd.Flags().StringSliceVarP(&agentHandles, "agent", "a", nil, "also include this agent's definition (repeatable)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(newExportInspectCmd())
+
+	return cmd
+}
+
+// exportSessions resolves the sessions to export. Without queries, the user
+// picks from recent sessions, unless agents alone are being exported.
+func exportSessions(cmd *cobra.Command, services *session.Services, queries []string, haveAgents bool) ([]session.Session, error) {
+	var sessions []session.Session
+	for _, q := range queries {
+		sess, err := findSession(cmd, services, q)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(sessions, func(s session.Session) bool { return s.ID == sess.ID }) {
+			sessions = append(sessions, sess)
+		}
+	}
+	if len(queries) > 0 || haveAgents {
+		return sessions, nil
+	}
+
+	if !isTerminal(os.Stdin) {
+		return nil, errors.New("no sessions given; pass session IDs or --agent")
+	}
+	recent, err := services.Sessions.List(cmd.Context(), 20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(recent) == 0 {
+		return nil, errors.New("no sessions to export")
+	}
+
+	options := make([]huh.Option[string], len(recent))
+	for i, s := range recent {
+		title := s.Title
+		if len(title) > 30 {
+			title = title[:27] + "..."
+		}
+		label := fmt.Sprintf("%s  %s  %s  %s", s.ID[:8], s.AgentHandle, title, formatTimeAgo(s.UpdatedAt))
+		options[i] = huh.NewOption(label, s.ID)
+	}
+	var selected []string
+	err = huh.NewMultiSelect[string]().
+		Title("Select sessions to export:").
+		Options(options...).
+		Value(&selected).
+		WithTheme(huh.ThemeCharm()).
+		Run()
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no sessions selected")
+	}
+	for _, s := range recent {
+		if slices.Contains(selected, s.ID) {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+// writeExport writes the bundle to f. Agents that can no longer be loaded
+// are skipped with a warning.
+func writeExport(cmd *cobra.Command, f *os.File, cfg config.Config, redactor *redact.Redactor, settings []config.Setting, handles []string, services *session.Services, sessions []session.Session) (export.Manifest, error) {
+	w := export.NewWriter(f, redactor)
+	if err := w.AddConfig(settings); err != nil {
+		return export.Manifest{}, err
+	}
+	for _, h := range handles {
+		ag, err := agent.Load(cfg, h)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping agent %s: %v\n", h, err)
+			continue
+		}
+		if err := w.AddAgent(ag.Handle, ag.Dir); err != nil {
+			return export.Manifest{}, err
+		}
+	}
+	for _, s := range sessions {
+		messages, err := services.Messages.List(cmd.Context(), s.ID)
+		if err != nil {
+			return export.Manifest{}, fmt.Errorf("failed to list messages: %w", err)
+		}
+		tags, err := services.Sessions.Tags(cmd.Context(), s.ID)
+		if err != nil {
+			return export.Manifest{}, fmt.Errorf("failed to list tags: %w", err)
+		}
+		if err := w.AddSession(s, tags, messages); err != nil {
+			return export.Manifest{}, err
+		}
+	}
+	return w.Close()
+}
+
+func newExportInspectCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "inspect <bundle>",
+		Short: "Show the contents of an export bundle",
+		Long: `Show an export bundle's manifest and session transcripts.
+
+The bundle is only read; nothing in it is run. With --json, the manifest is
+printed.`,
+		Example: `  ayo export inspect bug-report.tar.gz
+  ayo export inspect bug-report.tar.gz --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := export.Read(args[0])
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return writeJSON(b.Manifest)
+			}
+			return printExportBundle(b)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func printExportBundle(b *export.Bundle) error {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
+
+	m := b.Manifest
+	agents := strings.Join(m.Agents, ", ")
+	if agents == "" {
+		agents = "none"
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Export Bundle"))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("─", 60)))
+	fmt.Println()
+	fmt.Printf("  %s %s\n", labelStyle.Render("Ayo Version:"), valueStyle.Render(m.AyoVersion))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Platform:"), valueStyle.Render(m.OS+"/"+m.Arch))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Created:"), valueStyle.Render(m.CreatedAt.Local().Format("2006-01-02 15:04")))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Agents:"), valueStyle.Render(agents))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Files:"), valueStyle.Render(fmt.Sprintf("%d", len(m.Files))))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Redacted:"), valueStyle.Render(pluralize(m.Redactions, "secret")))
+	fmt.Println()
+
+	for _, t := range b.Transcripts {
+		title := t.Session.Title
+		if title == "" {
+			title = "untitled"
+		}
+		fmt.Println(headerStyle.Render(fmt.Sprintf("  Session %s  %s  %s", t.Session.ID[:min(8, len(t.Session.ID))], t.Session.Agent, title)))
+		fmt.Println(headerStyle.Render("  " + strings.Repeat("─", 60)))
+		fmt.Println()
+
+		messages := make([]session.Message, 0, len(t.Messages))
+		for _, msg := range t.Messages {
+			sm, err := msg.SessionMessage(t.Session.ID)
+			if err != nil {
+				return fmt.Errorf("session %s: %w", t.Session.ID, err)
+			}
+			messages = append(messages, sm)
+		}
+		fmt.Println(ui.RenderHistory(messages, t.Session.Agent))
+		fmt.Println()
+	}
+	return nil
+}
+
+// pluralize formats a count with its noun, e.g. "1 agent" or "2 agents".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
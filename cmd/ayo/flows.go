@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -14,10 +15,15 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/db"
 	"github.com/alexcabrera/ayo/internal/flows"
 	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 func newFlowsCmd(cfgPath *string) *cobra.Command {
@@ -44,6 +50,7 @@ Discovery priority (first found wins):
 	cmd.AddCommand(runFlowCmd(cfgPath))
 	cmd.AddCommand(validateFlowCmd())
 	cmd.AddCommand(newFlowCmd())
+	cmd.AddCommand(templatesFlowCmd())
 	cmd.AddCommand(historyFlowsCmd(cfgPath))
 	cmd.AddCommand(replayFlowCmd(cfgPath))
 
@@ -118,24 +125,22 @@ func outputFlowsJSON(flowList []flows.Flow) error {
 		})
 	}
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(output)
+	return writeJSON(output)
 }
 
 func outputFlowsTable(flowList []flows.Flow) error {
 	// Color palette
-	purple := lipgloss.Color("#a78bfa")
-	cyan := lipgloss.Color("#67e8f9")
-	muted := lipgloss.Color("#6b7280")
-	green := lipgloss.Color("#34d399")
+	primary := shared.ColorPrimary
+	secondary := shared.ColorSecondary
+	muted := shared.ColorMuted
+	success := shared.ColorSuccess
 
 	// Styles
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
-	nameStyle := lipgloss.NewStyle().Foreground(cyan).Bold(true)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
+	nameStyle := lipgloss.NewStyle().Foreground(secondary).Bold(true)
 	sourceStyle := lipgloss.NewStyle().Foreground(muted)
-	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#e5e7eb"))
-	yesStyle := lipgloss.NewStyle().Foreground(green)
+	descStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	yesStyle := lipgloss.NewStyle().Foreground(success)
 	noStyle := lipgloss.NewStyle().Foreground(muted)
 
 	// Calculate column widths
@@ -263,24 +268,28 @@ func outputFlowJSON(f *flows.Flow) error {
 	if f.HasOutputSchema() {
 		output["output_schema_path"] = f.OutputSchemaPath
 	}
+	if f.Transform != nil {
+		output["transform"] = f.Transform.String()
+	}
+	if len(f.Steps) > 0 {
+		output["steps"] = f.Steps
+	}
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(output)
+	return writeJSON(output)
 }
 
 func outputFlowDetails(f *flows.Flow, showScript bool) error {
 	// Styles
-	purple := lipgloss.Color("#a78bfa")
-	cyan := lipgloss.Color("#67e8f9")
-	muted := lipgloss.Color("#6b7280")
-	green := lipgloss.Color("#34d399")
+	primary := shared.ColorPrimary
+	secondary := shared.ColorSecondary
+	muted := shared.ColorMuted
+	success := shared.ColorSuccess
 
 	labelStyle := lipgloss.NewStyle().Foreground(muted).Width(14)
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#e5e7eb"))
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
-	pathStyle := lipgloss.NewStyle().Foreground(cyan)
-	schemaYes := lipgloss.NewStyle().Foreground(green).Render("yes")
+	valueStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
+	pathStyle := lipgloss.NewStyle().Foreground(secondary)
+	schemaYes := lipgloss.NewStyle().Foreground(success).Render("yes")
 	schemaNo := lipgloss.NewStyle().Foreground(muted).Render("no")
 
 	fmt.Println(headerStyle.Render(f.Name))
@@ -312,6 +321,13 @@ func outputFlowDetails(f *flows.Flow, showScript bool) error {
 	}
 	fmt.Printf("%s %s\n", labelStyle.Render("Output Schema:"), outputSchemaStr)
 
+	if f.Transform != nil {
+		fmt.Printf("%s %s\n", labelStyle.Render("Transform:"), valueStyle.Render(f.Transform.String()))
+	}
+	if len(f.Steps) > 0 {
+		fmt.Printf("%s %s\n", labelStyle.Render("Steps:"), valueStyle.Render(flows.StepsString(f.Steps)))
+	}
+
 	// Script preview or full
 	if showScript {
 		fmt.Println()
@@ -358,6 +374,16 @@ func runFlowCmd(cfgPath *string) *cobra.Command {
 	var timeout int
 	var validate bool
 	var noHistory bool
+	var webhook string
+	var outputFile string
+	var schemaOverride string
+	var each bool
+	var concurrency int
+	var failFast bool
+	var profile bool
+	var logLevel string
+	var fromSession string
+	var stream bool
 
 	cmd := &cobra.Command{
 		Use:   "run <name> [input]",
@@ -368,11 +394,57 @@ Input can be provided as:
   - Second argument: ayo flows run myflow '{"key": "value"}'
   - Stdin: echo '{"key": "value"}' | ayo flows run myflow
   - File: ayo flows run myflow --input data.json
+  - Session: ayo flows run myflow --input-from-session 4443df27
+
+With --input-from-session, the input is a past session's output: its
+structured output if the agent had an output schema, otherwise its last
+assistant response. The session is found by ID, ID prefix, or title, as with
+"ayo sessions continue". If the flow has an input schema, the response must
+be JSON (a single fenced code block is unwrapped) and is validated like any
+other input.
 
 Output:
   - Stdout: JSON result from the flow
   - Stderr: Logs and progress (streamed in real-time)
 
+With --output-file, the JSON result is written to that file instead of
+stdout ("-" means stdout). Parent directories are created, and the file is
+replaced atomically and only when the flow succeeds.
+
+With --stream, stdout is a stream of JSON events, one per line, as the flow
+runs: a "log" event for each stderr line, with the level, message, and
+fields of structured lines, and a final "result" event with the run ID,
+status, exit code, duration, and output. With --output-file, the output is
+written to the file and the result event names the file instead.
+
+With --webhook (or flows.webhook in config), the result is also POSTed as
+JSON to the URL when the run completes. Set AYO_WEBHOOK_SECRET (or
+flows.webhook_secret) to sign it in the X-Ayo-Signature header. A failed
+delivery is reported on stderr and doesn't change the exit code.
+
+With --input-schema-override, input is validated against the given schema
+file instead of the flow's input.jsonschema. This is for development, such
+as iterating on a schema change; it bypasses the flow's input contract, and
+the run history records the schema used.
+
+With --each, the input must be a JSON array and the flow runs once per
+element, up to --concurrency at a time. The output is a JSON array of the
+element outputs in input order, with null for elements that failed. Failed
+elements don't stop the batch unless --fail-fast is set. Each element is
+recorded in history as a run of its own, linked to a run for the batch.
+
+With --profile, each agent call is timed and a breakdown is printed to
+stderr when the run ends, with the time spent outside agent calls last.
+Flows with steps are timed per step. For script flows, every "ayo @handle"
+invocation in the script is timed through a wrapper placed first on the
+script's PATH. The timings are saved in the run history and shown by
+"ayo flows history show".
+
+Structured log lines on stderr, in logfmt (level=info msg=...) or JSON with a
+level field, are shown with their level, colored on a terminal. With
+--log-level, lines below that level (debug, info, warn, error) are hidden.
+Other lines are shown as written. The run history keeps the full stderr.
+
 Exit codes:
   0 - Success
   1 - General error
@@ -383,6 +455,34 @@ Exit codes:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
+			if !each && (cmd.Flags().Changed("concurrency") || failFast) {
+				return fmt.Errorf("the --concurrency and --fail-fast flags need --each")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("invalid --concurrency %d: must be 1 or more", concurrency)
+			}
+			if profile && each {
+				return fmt.Errorf("the --profile flag can't be used with --each")
+			}
+			if stream && each {
+				return fmt.Errorf("the --stream flag can't be used with --each")
+			}
+			if outputFile == "-" {
+				outputFile = ""
+			}
+			stderr, flushStderr, err := flowStderr(logLevel)
+			if err != nil {
+				return err
+			}
+			if stream {
+				min := flows.LogDebug
+				if logLevel != "" {
+					min, _ = flows.ParseLogLevel(logLevel)
+				}
+				w := newFlowEventWriter(os.Stdout, min)
+				stderr, flushStderr = w, w.Flush
+			}
+
 			// Discover flows
 			dirs := paths.FlowsDirs()
 			discovered, err := flows.Discover(dirs)
@@ -407,6 +507,7 @@ Exit codes:
 			opts := flows.RunOptions{
 				Timeout:  time.Duration(timeout) * time.Second,
 				Validate: validate,
+				Profile:  profile,
 			}
 
 			// Input from argument
@@ -419,6 +520,31 @@ Exit codes:
 				opts.InputFile = inputFile
 			}
 
+			// Input from a past session's output
+			if fromSession != "" {
+				if opts.Input != "" || opts.InputFile != "" {
+					return fmt.Errorf("the --input-from-session flag can't be used with an input argument or --input")
+				}
+				input, err := sessionFlowInput(cmd, fromSession, flow)
+				if err != nil {
+					return err
+				}
+				opts.Input = input
+			}
+
+			// Validate against another schema while developing the flow
+			if schemaOverride != "" {
+				abs, err := filepath.Abs(schemaOverride)
+				if err != nil {
+					return fmt.Errorf("input schema override: %w", err)
+				}
+				if _, err := os.Stat(abs); err != nil {
+					return fmt.Errorf("input schema override: %w", err)
+				}
+				opts.InputSchemaOverride = abs
+				fmt.Fprintf(os.Stderr, "warning: validating input against %s instead of the flow's input schema; the flow's input contract is not enforced\n", schemaOverride)
+			}
+
 			// Check if stdin has data (only if no other input provided)
 			if opts.Input == "" && opts.InputFile == "" && !isTerminal(os.Stdin) {
 				data, err := io.ReadAll(os.Stdin)
@@ -428,12 +554,27 @@ Exit codes:
 				opts.Input = string(data)
 			}
 
+			cfg, cfgErr := config.Load(*cfgPath)
+
+			// Webhook from the flag, falling back to config
+			if webhook == "" && cfgErr == nil {
+				webhook = cfg.Flows.Webhook
+			}
+			if webhook != "" && !validate {
+				secret := os.Getenv("AYO_WEBHOOK_SECRET")
+				if secret == "" {
+					secret = cfg.Flows.WebhookSecret
+				}
+				opts.Webhook = &flows.Webhook{URL: webhook, Secret: secret}
+			}
+
 			// Setup history recording if not disabled
 			if !noHistory && !validate {
-				cfg, err := config.Load(*cfgPath)
-				if err == nil {
+				if cfgErr == nil {
 					_, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
-					if err == nil {
+					if err != nil {
+						warnNoPersistence("flow history", err, false)
+					} else {
 						opts.History = flows.NewHistoryService(queries)
 						opts.AutoPrune = true
 						opts.RetentionDays = cfg.Flows.HistoryRetentionDays
@@ -442,8 +583,29 @@ Exit codes:
 				}
 			}
 
+			// Run steps through one in-process runner
+			if len(flow.Steps) > 0 && !validate {
+				if cfgErr != nil {
+					return fmt.Errorf("load config: %w", cfgErr)
+				}
+				agents, closeAgents, err := inProcessAgents(cmd.Context(), cfg)
+				if err != nil {
+					return err
+				}
+				defer closeAgents()
+				opts.Agents = agents
+			}
+
+			if each {
+				return runFlowEach(cmd.Context(), flow, opts, flows.EachOptions{
+					Concurrency: concurrency,
+					FailFast:    failFast,
+				}, outputFile, stderr, flushStderr)
+			}
+
 			// Run the flow with stderr streaming
-			result, err := flows.RunStreaming(cmd.Context(), flow, opts, os.Stderr)
+			result, err := flows.RunStreaming(cmd.Context(), flow, opts, stderr)
+			flushStderr()
 			if err != nil {
 				return err
 			}
@@ -452,9 +614,26 @@ Exit codes:
 			if result.Error != nil {
 				fmt.Fprintln(os.Stderr, result.Error)
 			}
+			if result.WebhookError != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", result.WebhookError)
+			}
+			if profile && !validate {
+				printFlowProfile(result)
+			}
 
-			// Output stdout (JSON)
-			if result.Stdout != "" {
+			// Output stdout (JSON), to the output file when the run succeeded
+			if outputFile != "" && result.Status == flows.RunStatusSuccess {
+				if err := writeFileAtomic(outputFile, []byte(result.Stdout)); err != nil {
+					return fmt.Errorf("write output file: %w", err)
+				}
+			} else {
+				outputFile = ""
+			}
+			if stream {
+				if err := writeJSONLine(flowResultEvent(result, outputFile)); err != nil {
+					return err
+				}
+			} else if outputFile == "" && result.Stdout != "" {
 				fmt.Print(result.Stdout)
 			}
 
@@ -478,13 +657,131 @@ Exit codes:
 	}
 
 	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "Input file path")
+	cmd.Flags().StringVar(&fromSession, "input-from-session", "", "Use a session's last response as input (ID, prefix, or title)")
 	cmd.Flags().IntVarP(&timeout, "timeout", "t", 300, "Timeout in seconds (default 5 minutes)")
 	cmd.Flags().BoolVar(&validate, "validate", false, "Validate input only, don't run")
 	cmd.Flags().BoolVar(&noHistory, "no-history", false, "Don't record run in history")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "POST the result to this URL on completion")
+	cmd.Flags().StringVarP(&outputFile, "output-file", "o", "", `Write the JSON result to this file ("-" for stdout)`)
+	cmd.Flags().StringVar(&schemaOverride, "input-schema-override", "", "Validate input against this schema instead of the flow's (development only)")
+	cmd.Flags().BoolVar(&each, "each", false, "Run the flow once per element of a JSON array input")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Elements to run at once with --each")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop starting elements after one fails with --each")
+	cmd.Flags().BoolVar(&profile, "profile", false, "Time each agent call and print a breakdown to stderr")
+	cmd.Flags().StringVar(&logLevel, "log-level", "", "Hide structured stderr log lines below this level (debug, info, warn, error)")
+	cmd.Flags().BoolVar(&stream, "stream", false, "Write stderr lines and the result to stdout as JSON events")
 
 	return cmd
 }
 
+// runFlowEach runs flow once per element of an array input and writes the
+// array of outputs like a single run's output. It exits with code 1 when
+// an element failed.
+func runFlowEach(ctx context.Context, flow *flows.Flow, opts flows.RunOptions, eachOpts flows.EachOptions, outputFile string, stderr io.Writer, flushStderr func()) error {
+	result, err := flows.RunEach(ctx, flow, opts, eachOpts, stderr)
+	flushStderr()
+	if err != nil {
+		return err
+	}
+
+	for i, r := range result.Results {
+		if r == nil {
+			continue
+		}
+		if r.Error != nil {
+			fmt.Fprintf(os.Stderr, "[%d] %v\n", i, r.Error)
+		}
+		if r.WebhookError != nil {
+			fmt.Fprintf(os.Stderr, "[%d] warning: %v\n", i, r.WebhookError)
+		}
+	}
+
+	if outputFile != "" && result.OK() {
+		if err := writeFileAtomic(outputFile, []byte(result.Output)); err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+	} else if result.Output != "" {
+		fmt.Print(result.Output)
+	}
+
+	if !result.OK() {
+		fmt.Fprintln(os.Stderr, result.Summary())
+		os.Exit(1)
+	}
+	return nil
+}
+
+// inProcessAgents returns a flow AgentRunner that runs agents in this
+// process instead of starting an ayo process per step. Each call gets its
+// own runner, so flows can run concurrently with --each. Sessions are saved
+// when the database is available. Call the returned function when the flow
+// is done.
+func inProcessAgents(ctx context.Context, cfg config.Config) (flows.AgentRunner, func(), error) {
+	services, err := session.Connect(ctx, paths.DatabasePath())
+	if err != nil {
+		warnNoPersistence("session persistence", err, false)
+		services = nil
+	}
+	closeServices := func() {
+		if services != nil {
+			services.Close()
+		}
+	}
+
+	opts := run.RunnerOptions{
+		Services:  services,
+		RawOutput: true,
+	}
+	// Fail on bad config before any step runs
+	if _, err := run.NewRunner(cfg, false, opts); err != nil {
+		closeServices()
+		return nil, nil, err
+	}
+
+	agents := func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		ag, err := agent.Load(cfg, handle)
+		if err != nil {
+			return "", err
+		}
+		if err := ag.ValidateInput(prompt); err != nil {
+			return "", err
+		}
+		runner, err := run.NewRunner(cfg, false, opts)
+		if err != nil {
+			return "", err
+		}
+		runner.SetStreamWriter(run.NewPrintWriterWithUI(ui.NewWithWriter(false, stderr), ag.Handle))
+		return runner.Text(ctx, ag, prompt, nil)
+	}
+	return agents, closeServices, nil
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, so readers never see a partial file. Parent directories are
+// created as needed.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 func validateFlowCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate <path>",
@@ -497,15 +794,15 @@ func validateFlowCmd() *cobra.Command {
 			flow, err := flows.DiscoverOne(path)
 			if err != nil {
 				// Styled error output
-				errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ef4444"))
+				errorStyle := lipgloss.NewStyle().Foreground(shared.ColorError)
 				fmt.Println(errorStyle.Render("x Flow validation failed"))
 				fmt.Printf("  %v\n", err)
 				os.Exit(1)
 			}
 
 			// Success output
-			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#34d399"))
-			muted := lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+			successStyle := lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+			muted := lipgloss.NewStyle().Foreground(shared.ColorMuted)
 
 			fmt.Println(successStyle.Render("v Flow is valid"))
 			fmt.Printf("  Name: %s\n", flow.Name)
@@ -523,6 +820,13 @@ func validateFlowCmd() *cobra.Command {
 			}
 			fmt.Printf("  Output schema: %s\n", outputStr)
 
+			if flow.Transform != nil {
+				fmt.Printf("  Transform: %s\n", flow.Transform.String())
+			}
+			if len(flow.Steps) > 0 {
+				fmt.Printf("  Steps: %s\n", flows.StepsString(flow.Steps))
+			}
+
 			return nil
 		},
 	}
@@ -534,14 +838,36 @@ func newFlowCmd() *cobra.Command {
 	var project bool
 	var withSchemas bool
 	var force bool
+	var templateName string
 
 	cmd := &cobra.Command{
 		Use:   "new <name>",
 		Short: "Create a new flow",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create a new flow from a template.
+
+The default template is a minimal script. Other templates create a flow
+package with input and output schemas. List them with 'ayo flows templates'.`,
+		Example: `  ayo flows new my-flow
+  ayo flows new summarize-docs --template map-reduce
+  ayo flows new my-flow --project --with-schemas`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
+			tmpl, err := flows.GetTemplate(templateName)
+			if err != nil {
+				return err
+			}
+			files, err := tmpl.Render(name)
+			if err != nil {
+				return err
+			}
+
+			// Non-default templates ship schemas that describe their input and output
+			if tmpl.Name != flows.DefaultTemplate {
+				withSchemas = true
+			}
+
 			// Determine target directory
 			var targetDir string
 			if project {
@@ -574,37 +900,10 @@ func newFlowCmd() *cobra.Command {
 
 				flowPath = filepath.Join(pkgDir, "flow.sh")
 
-				// Create input schema
-				inputSchema := `{
-  "$schema": "http://json-schema.org/draft-07/schema#",
-  "type": "object",
-  "properties": {
-    "input": {
-      "type": "string",
-      "description": "Input value"
-    }
-  },
-  "required": ["input"]
-}
-`
-				if err := os.WriteFile(filepath.Join(pkgDir, "input.jsonschema"), []byte(inputSchema), 0644); err != nil {
+				if err := os.WriteFile(filepath.Join(pkgDir, "input.jsonschema"), files.InputSchema, 0644); err != nil {
 					return fmt.Errorf("write input schema: %w", err)
 				}
-
-				// Create output schema
-				outputSchema := `{
-  "$schema": "http://json-schema.org/draft-07/schema#",
-  "type": "object",
-  "properties": {
-    "result": {
-      "type": "string",
-      "description": "Result value"
-    }
-  },
-  "required": ["result"]
-}
-`
-				if err := os.WriteFile(filepath.Join(pkgDir, "output.jsonschema"), []byte(outputSchema), 0644); err != nil {
+				if err := os.WriteFile(filepath.Join(pkgDir, "output.jsonschema"), files.OutputSchema, 0644); err != nil {
 					return fmt.Errorf("write output schema: %w", err)
 				}
 
@@ -622,35 +921,79 @@ func newFlowCmd() *cobra.Command {
 				fmt.Printf("Created: %s\n", flowPath)
 			}
 
-			// Create flow script
-			flowContent := fmt.Sprintf(`#!/usr/bin/env bash
-# ayo:flow
-# name: %s
-# description: TODO: Describe what this flow does
+			if err := os.WriteFile(flowPath, files.Script, 0755); err != nil {
+				return fmt.Errorf("write flow: %w", err)
+			}
+
+			return nil
+		},
+	}
 
-set -euo pipefail
+	cmd.Flags().BoolVar(&project, "project", false, "Create in project directory (.ayo/flows/)")
+	cmd.Flags().BoolVar(&withSchemas, "with-schemas", false, "Create with input/output schemas")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite if exists")
+	cmd.Flags().StringVarP(&templateName, "template", "t", flows.DefaultTemplate, "Template to use (see 'ayo flows templates')")
+
+	return cmd
+}
+
+func templatesFlowCmd() *cobra.Command {
+	var jsonOutput bool
 
-INPUT="${1:-$(cat)}"
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "List templates for new flows",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpls := flows.Templates()
 
-# TODO: Implement your flow
-# Example: pipe input through an agent
-# echo "$INPUT" | ayo @ayo "Process this input and return JSON"
+			if jsonOutput {
+				type templateJSON struct {
+					Name        string `json:"name"`
+					Description string `json:"description"`
+					Default     bool   `json:"default"`
+				}
+				output := make([]templateJSON, len(tmpls))
+				for i, t := range tmpls {
+					output[i] = templateJSON{
+						Name:        t.Name,
+						Description: t.Description,
+						Default:     t.Name == flows.DefaultTemplate,
+					}
+				}
+				return writeJSON(output)
+			}
 
-# For now, just echo the input
-echo "$INPUT"
-`, name)
+			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+			nameStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary).Bold(true)
+			descStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+			muted := lipgloss.NewStyle().Foreground(shared.ColorMuted)
 
-			if err := os.WriteFile(flowPath, []byte(flowContent), 0755); err != nil {
-				return fmt.Errorf("write flow: %w", err)
+			nameWidth := 0
+			for _, t := range tmpls {
+				if len(t.Name) > nameWidth {
+					nameWidth = len(t.Name)
+				}
 			}
 
+			fmt.Printf("%s  %s\n",
+				headerStyle.Render(padRight("NAME", nameWidth)),
+				headerStyle.Render("DESCRIPTION"),
+			)
+			for _, t := range tmpls {
+				desc := descStyle.Render(t.Description)
+				if t.Name == flows.DefaultTemplate {
+					desc += muted.Render(" (default)")
+				}
+				fmt.Printf("%s  %s\n", nameStyle.Render(padRight(t.Name, nameWidth)), desc)
+			}
+
+			fmt.Println()
+			fmt.Println(muted.Render("Create a flow with: ayo flows new <name> --template <template>"))
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&project, "project", false, "Create in project directory (.ayo/flows/)")
-	cmd.Flags().BoolVar(&withSchemas, "with-schemas", false, "Create with input/output schemas")
-	cmd.Flags().BoolVar(&force, "force", false, "Overwrite if exists")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 
 	return cmd
 }
@@ -761,28 +1104,26 @@ func historyShowCmd(cfgPath *string) *cobra.Command {
 }
 
 func outputHistoryJSON(runs []*flows.FlowRun) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(runs)
+	return writeJSON(runs)
 }
 
 func outputHistoryTable(runs []*flows.FlowRun) error {
 	// Color palette
-	purple := lipgloss.Color("#a78bfa")
-	cyan := lipgloss.Color("#67e8f9")
-	muted := lipgloss.Color("#6b7280")
-	green := lipgloss.Color("#34d399")
-	red := lipgloss.Color("#ef4444")
-	yellow := lipgloss.Color("#fbbf24")
+	primary := shared.ColorPrimary
+	secondary := shared.ColorSecondary
+	muted := shared.ColorMuted
+	success := shared.ColorSuccess
+	errColor := shared.ColorError
+	warning := shared.ColorTertiary
 
 	// Styles
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
 	idStyle := lipgloss.NewStyle().Foreground(muted)
-	nameStyle := lipgloss.NewStyle().Foreground(cyan)
-	successStyle := lipgloss.NewStyle().Foreground(green)
-	failedStyle := lipgloss.NewStyle().Foreground(red)
-	runningStyle := lipgloss.NewStyle().Foreground(yellow)
-	timeoutStyle := lipgloss.NewStyle().Foreground(yellow)
+	nameStyle := lipgloss.NewStyle().Foreground(secondary)
+	successStyle := lipgloss.NewStyle().Foreground(success)
+	failedStyle := lipgloss.NewStyle().Foreground(errColor)
+	runningStyle := lipgloss.NewStyle().Foreground(warning)
+	timeoutStyle := lipgloss.NewStyle().Foreground(warning)
 
 	// Column widths
 	idWidth := 8
@@ -790,8 +1131,11 @@ func outputHistoryTable(runs []*flows.FlowRun) error {
 	statusWidth := 10
 	durationWidth := 10
 
+	// Long histories are paged
+	out := ui.NewPager()
+
 	// Print header
-	fmt.Printf("%s  %s  %s  %s  %s\n",
+	fmt.Fprintf(out, "%s  %s  %s  %s  %s\n",
 		headerStyle.Render(padRight("ID", idWidth)),
 		headerStyle.Render(padRight("FLOW", nameWidth)),
 		headerStyle.Render(padRight("STATUS", statusWidth)),
@@ -832,7 +1176,7 @@ func outputHistoryTable(runs []*flows.FlowRun) error {
 
 		started := run.StartedAt.Format("2006-01-02 15:04:05")
 
-		fmt.Printf("%s  %s  %s  %s  %s\n",
+		fmt.Fprintf(out, "%s  %s  %s  %s  %s\n",
 			idStyle.Render(padRight(id, idWidth)),
 			nameStyle.Render(padRight(name, nameWidth)),
 			statusStyled,
@@ -841,29 +1185,27 @@ func outputHistoryTable(runs []*flows.FlowRun) error {
 		)
 	}
 
-	return nil
+	return out.Close()
 }
 
 func outputRunJSON(run *flows.FlowRun) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(run)
+	return writeJSON(run)
 }
 
 func outputRunDetails(run *flows.FlowRun) error {
 	// Styles
-	purple := lipgloss.Color("#a78bfa")
-	cyan := lipgloss.Color("#67e8f9")
-	muted := lipgloss.Color("#6b7280")
-	green := lipgloss.Color("#34d399")
-	red := lipgloss.Color("#ef4444")
+	primary := shared.ColorPrimary
+	secondary := shared.ColorSecondary
+	muted := shared.ColorMuted
+	success := shared.ColorSuccess
+	errColor := shared.ColorError
 
 	labelStyle := lipgloss.NewStyle().Foreground(muted).Width(14)
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#e5e7eb"))
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
-	pathStyle := lipgloss.NewStyle().Foreground(cyan)
-	successStyle := lipgloss.NewStyle().Foreground(green)
-	failedStyle := lipgloss.NewStyle().Foreground(red)
+	valueStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
+	pathStyle := lipgloss.NewStyle().Foreground(secondary)
+	successStyle := lipgloss.NewStyle().Foreground(success)
+	failedStyle := lipgloss.NewStyle().Foreground(errColor)
 
 	fmt.Println(headerStyle.Render("Flow Run: " + run.ID))
 	fmt.Println()
@@ -887,6 +1229,11 @@ func outputRunDetails(run *flows.FlowRun) error {
 		fmt.Printf("%s %d\n", labelStyle.Render("Exit Code:"), *run.ExitCode)
 	}
 
+	if run.InputSchemaOverride != "" {
+		warnStyle := lipgloss.NewStyle().Foreground(shared.ColorTertiary)
+		fmt.Printf("%s %s %s\n", labelStyle.Render("Input Schema:"), pathStyle.Render(run.InputSchemaOverride), warnStyle.Render("(override)"))
+	}
+
 	fmt.Printf("%s %s\n", labelStyle.Render("Started:"), valueStyle.Render(run.StartedAt.Format(time.RFC3339)))
 	if run.FinishedAt != nil {
 		fmt.Printf("%s %s\n", labelStyle.Render("Finished:"), valueStyle.Render(run.FinishedAt.Format(time.RFC3339)))
@@ -895,6 +1242,12 @@ func outputRunDetails(run *flows.FlowRun) error {
 		fmt.Printf("%s %s\n", labelStyle.Render("Duration:"), valueStyle.Render(formatDuration(time.Duration(run.DurationMs)*time.Millisecond)))
 	}
 
+	if run.Stages != nil {
+		fmt.Println()
+		fmt.Println(headerStyle.Render("Profile:"))
+		fmt.Println(formatFlowProfile(time.Duration(run.DurationMs)*time.Millisecond, run.Stages))
+	}
+
 	if run.ErrorMessage != "" {
 		fmt.Println()
 		fmt.Println(headerStyle.Render("Error:"))
@@ -952,13 +1305,48 @@ func formatJSON(s string) string {
 func replayFlowCmd(cfgPath *string) *cobra.Command {
 	var timeout int
 	var noHistory bool
+	var logLevel string
+	var diffOutput bool
+	var ignore []string
+	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "replay <run-id>",
 		Short: "Replay a flow run with its original input",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
+		Long: `Replay a flow run with its original input, using the flow's current version.
+
+With --diff, the replay's output is compared with the original run's output
+instead of printed. The result is reported as "identical", "changed" with
+each difference, or "error" when the replay failed. JSON outputs are
+compared by value, so formatting and key order don't matter. --ignore skips
+values that change on every run, such as timestamps and IDs; it takes a JSON
+pointer, where a "*" segment matches any key or array index, and can be
+repeated.
+
+Exit codes with --diff, as with diff(1):
+  0 - Output identical
+  1 - Output changed
+  2 - Error: the replay failed or timed out, or the run couldn't be replayed`,
+		Example: `  ayo flows replay 01HQ3K
+  ayo flows replay 01HQ3K --diff
+  ayo flows replay 01HQ3K --diff --ignore /generated_at --ignore '/items/*/id'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// With --diff every error exits 2, so it isn't mistaken for a change
+			defer func() {
+				if err != nil && diffOutput {
+					err = withExitCode(err, replayExitError)
+				}
+			}()
+
 			runID := args[0]
+			if !diffOutput && (len(ignore) > 0 || jsonOutput) {
+				return fmt.Errorf("the --ignore and --json flags need --diff")
+			}
+			stderr, flushStderr, err := flowStderr(logLevel)
+			if err != nil {
+				return err
+			}
 
 			_, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
 			if err != nil {
@@ -995,6 +1383,13 @@ func replayFlowCmd(cfgPath *string) *cobra.Command {
 			if flow == nil {
 				return fmt.Errorf("flow no longer exists: %s", run.FlowName)
 			}
+			if diffOutput && run.OutputJSON == "" {
+				return fmt.Errorf("run %s has no output to compare with", run.ID)
+			}
+			// Catch invalid pointers before spending a run
+			if _, err := flows.DiffOutputs("{}", "{}", ignore); err != nil {
+				return err
+			}
 
 			// Build run options
 			opts := flows.RunOptions{
@@ -1014,12 +1409,45 @@ func replayFlowCmd(cfgPath *string) *cobra.Command {
 				}
 			}
 
+			// Run steps through one in-process runner
+			if len(flow.Steps) > 0 {
+				cfg, err := config.Load(*cfgPath)
+				if err != nil {
+					return fmt.Errorf("load config: %w", err)
+				}
+				agents, closeAgents, err := inProcessAgents(cmd.Context(), cfg)
+				if err != nil {
+					return err
+				}
+				defer closeAgents()
+				opts.Agents = agents
+			}
+
 			// Run the flow with stderr streaming
-			result, err := flows.RunStreaming(cmd.Context(), flow, opts, os.Stderr)
+			result, err := flows.RunStreaming(cmd.Context(), flow, opts, stderr)
+			flushStderr()
 			if err != nil {
 				return err
 			}
 
+			if diffOutput {
+				diff, err := diffReplay(run, result, ignore)
+				if err != nil {
+					return err
+				}
+				if jsonOutput {
+					if err := writeJSON(diff); err != nil {
+						return err
+					}
+				} else {
+					fmt.Print(formatReplayDiff(diff))
+				}
+				if code := replayExitCode(diff.Status); code != 0 {
+					os.Exit(code)
+				}
+				return nil
+			}
+
 			// Handle result
 			if result.Error != nil {
 				fmt.Fprintln(os.Stderr, result.Error)
@@ -1051,6 +1479,10 @@ func replayFlowCmd(cfgPath *string) *cobra.Command {
 
 	cmd.Flags().IntVarP(&timeout, "timeout", "t", 300, "Timeout in seconds (default 5 minutes)")
 	cmd.Flags().BoolVar(&noHistory, "no-history", false, "Don't record replay in history")
+	cmd.Flags().StringVar(&logLevel, "log-level", "", "Hide structured stderr log lines below this level (debug, info, warn, error)")
+	cmd.Flags().BoolVar(&diffOutput, "diff", false, "Compare the replay's output with the original run's instead of printing it")
+	cmd.Flags().StringArrayVar(&ignore, "ignore", nil, "JSON pointer to a value --diff skips (repeatable, * matches any segment)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the --diff report as JSON")
 
 	return cmd
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// eachPrefix matches the "[3] " prefix --each adds to an element's stderr.
+var eachPrefix = regexp.MustCompile(`^\[\d+\] `)
+
+// flowLogWriter renders the stderr of a flow run. Structured log lines are
+// shown with their level, colored when color is set, and lines below min are
+// dropped. Other lines pass through unchanged. Partial lines are held until
+// they end or Flush is called.
+type flowLogWriter struct {
+	out   io.Writer
+	min   flows.LogLevel
+	color bool
+	buf   []byte
+}
+
+// flowStderr returns the writer a flow run streams stderr to. Structured
+// lines are rendered on a terminal, or anywhere once --log-level is set;
+// otherwise stderr passes through as the flow wrote it. The returned flush
+// writes a pending partial line.
+func flowStderr(logLevel string) (io.Writer, func(), error) {
+	color := isTerminal(os.Stderr)
+	if logLevel == "" && !color {
+		return os.Stderr, func() {}, nil
+	}
+	min := flows.LogDebug
+	if logLevel != "" {
+		level, err := flows.ParseLogLevel(logLevel)
+		if err != nil {
+			return nil, nil, err
+		}
+		min = level
+	}
+	w := &flowLogWriter{out: os.Stderr, min: min, color: color}
+	return w, w.Flush, nil
+}
+
+func (w *flowLogWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a pending partial line.
+func (w *flowLogWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *flowLogWriter) writeLine(line string) {
+	prefix := eachPrefix.FindString(line)
+	entry, ok := flows.ParseLogLine(line[len(prefix):])
+	if !ok {
+		io.WriteString(w.out, line+"\n")
+		return
+	}
+	if entry.Level < w.min {
+		return
+	}
+	io.WriteString(w.out, prefix+w.render(entry)+"\n")
+}
+
+// render formats a log line as its level, message, and fields. Timestamps
+// are left out; the lines are shown as they arrive.
+func (w *flowLogWriter) render(entry flows.LogLine) string {
+	level := strings.ToUpper(entry.Level.String())
+	level += strings.Repeat(" ", len("ERROR")-len(level))
+
+	var fields []string
+	for _, f := range entry.Fields {
+		if f.Key == "time" || f.Key == "ts" || f.Key == "timestamp" {
+			continue
+		}
+		value := f.Value
+		if value == "" || strings.ContainsAny(value, " \t\"=") {
+			value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+		}
+		fields = append(fields, f.Key+"="+value)
+	}
+
+	if !w.color {
+		return strings.TrimRight(strings.Join(append([]string{level, entry.Msg}, fields...), " "), " ")
+	}
+
+	levelStyle := lipgloss.NewStyle().Bold(true)
+	switch entry.Level {
+	case flows.LogDebug:
+		levelStyle = levelStyle.Foreground(shared.ColorMuted)
+	case flows.LogInfo:
+		levelStyle = levelStyle.Foreground(shared.ColorPrimary)
+	case flows.LogWarn:
+		levelStyle = levelStyle.Foreground(shared.ColorTertiary)
+	default:
+		levelStyle = levelStyle.Foreground(shared.ColorError)
+	}
+	parts := []string{levelStyle.Render(level), entry.Msg}
+	if len(fields) > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(shared.ColorTextDim).Render(strings.Join(fields, " ")))
+	}
+	return strings.TrimRight(strings.Join(parts, " "), " ")
+}
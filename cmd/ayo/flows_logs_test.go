@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+)
+
+func TestFlowLogWriter(t *testing.T) {
+	var out strings.Builder
+	w := &flowLogWriter{out: &out, min: flows.LogInfo}
+
+	w.Write([]byte("starting\nlevel=debug msg=noisy\n"))
+	w.Write([]byte(`time=2026-01-02T03:04:05Z level=info msg="fetched page" url=https://example.com` + "\n"))
+	w.Write([]byte(`[2] {"level":"warn","msg":"slow","took":"3 s"}` + "\n"))
+	w.Write([]byte("level=error msg=boom"))
+	w.Flush()
+
+	want := strings.Join([]string{
+		"starting",
+		"INFO  fetched page url=https://example.com",
+		`[2] WARN  slow took="3 s"`,
+		"ERROR boom",
+	}, "\n") + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFlowStderrRejectsUnknownLevel(t *testing.T) {
+	if _, _, err := flowStderr("loud"); err == nil || !strings.Contains(err.Error(), "unknown log level") {
+		t.Errorf("error = %v, want unknown log level", err)
+	}
+}
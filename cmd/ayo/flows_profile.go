@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// printFlowProfile prints where the time of a flow run went, for --profile.
+// It goes to stderr so piped output stays clean.
+func printFlowProfile(result *flows.RunResult) {
+	style := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, style.Render("Flow profile\n"+formatFlowProfile(result.Duration, result.Stages)))
+}
+
+// formatFlowProfile renders the run time and the stages of a run in the
+// order they started, with each one's share of the run. Time the run spent
+// outside agent calls is shown last; it is negative, and omitted, when calls
+// overlapped.
+func formatFlowProfile(total time.Duration, stages []flows.Stage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-12s %s\n", "Time", formatDuration(total))
+	if len(stages) == 0 {
+		fmt.Fprintf(&b, "  No agent calls were made")
+		return b.String()
+	}
+
+	nameWidth, agentWidth := len("other"), 0
+	for _, s := range stages {
+		nameWidth = max(nameWidth, len(s.Name))
+		if s.Agent != s.Name {
+			agentWidth = max(agentWidth, len(s.Agent))
+		}
+	}
+	row := func(name, agent string, d time.Duration, note string) {
+		share := ""
+		if total > 0 {
+			share = fmt.Sprintf("%3.0f%%", 100*d.Seconds()/total.Seconds())
+		}
+		line := fmt.Sprintf("    %-*s %-*s %8s %s %s", nameWidth, name, agentWidth, agent, formatDuration(d), share, note)
+		fmt.Fprintln(&b, strings.TrimRight(line, " "))
+	}
+
+	var inAgents time.Duration
+	fmt.Fprintf(&b, "  Stages\n")
+	for _, s := range stages {
+		agent := s.Agent
+		if agent == s.Name {
+			agent = ""
+		}
+		note := ""
+		if s.Failed {
+			note = "failed"
+		}
+		row(s.Name, agent, s.Duration, note)
+		inAgents += s.Duration
+	}
+	if other := total - inAgents; other > 0 {
+		row("other", "", other, "")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
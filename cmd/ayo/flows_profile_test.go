@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+)
+
+func TestFormatFlowProfile(t *testing.T) {
+	got := formatFlowProfile(10*time.Second, []flows.Stage{
+		{Name: "facts", Agent: "@researcher", Duration: 6 * time.Second},
+		{Name: "summary", Agent: "@writer", Start: 6 * time.Second, Duration: 3 * time.Second, Failed: true},
+	})
+	for _, want := range []string{
+		"Time         10.0s",
+		"    facts   @researcher     6.0s  60%",
+		"    summary @writer         3.0s  30% failed",
+		"    other                   1.0s  10%",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("profile is missing %q:\n%s", want, got)
+		}
+	}
+
+	// Script stages are named by their agent, so the agent isn't repeated
+	got = formatFlowProfile(time.Second, []flows.Stage{{Name: "@a", Agent: "@a", Duration: time.Second}})
+	if strings.Contains(got, "@a @a") || strings.Contains(got, "other") {
+		t.Errorf("script profile =\n%s", got)
+	}
+
+	if got := formatFlowProfile(time.Second, nil); !strings.Contains(got, "No agent calls") {
+		t.Errorf("empty profile =\n%s", got)
+	}
+}
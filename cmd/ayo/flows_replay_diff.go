@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// Outcomes of replay --diff.
+const (
+	replayIdentical = "identical"
+	replayChanged   = "changed"
+	replayError     = "error"
+)
+
+// Exit codes of replay --diff, as with diff(1): a change is told apart from
+// a replay or command that failed.
+const (
+	replayExitChanged = 1
+	replayExitError   = 2
+)
+
+// replayDiff compares a replay's output with the original run's, for
+// replay --diff.
+type replayDiff struct {
+	Status      string               `json:"status"` // One of the replay constants
+	RunID       string               `json:"run_id"`
+	ReplayRunID string               `json:"replay_run_id"`
+	Changes     []flows.OutputChange `json:"changes,omitempty"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// diffReplay compares result with the original run's output. A replay that
+// didn't succeed is an error, whatever it printed.
+func diffReplay(original *flows.FlowRun, result *flows.RunResult, ignore []string) (replayDiff, error) {
+	diff := replayDiff{RunID: original.ID, ReplayRunID: result.RunID}
+	if result.Status != flows.RunStatusSuccess {
+		diff.Status = replayError
+		diff.Error = string(result.Status)
+		if result.Error != nil {
+			diff.Error = result.Error.Error()
+		}
+		return diff, nil
+	}
+
+	changes, err := flows.DiffOutputs(original.OutputJSON, result.Stdout, ignore)
+	if err != nil {
+		return diff, err
+	}
+	diff.Changes = changes
+	diff.Status = replayIdentical
+	if len(changes) > 0 {
+		diff.Status = replayChanged
+	}
+	return diff, nil
+}
+
+// replayExitCode returns the exit code for a replay --diff outcome.
+func replayExitCode(status string) int {
+	switch status {
+	case replayIdentical:
+		return 0
+	case replayChanged:
+		return replayExitChanged
+	default:
+		return replayExitError
+	}
+}
+
+// formatReplayDiff renders the outcome of a replay and, when the output
+// changed, one line per change: "~" for a changed value, "+" for an added
+// one, and "-" for a removed one.
+func formatReplayDiff(diff replayDiff) string {
+	muted := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	statusStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorSuccess)
+	if diff.Status != replayIdentical {
+		statusStyle = statusStyle.Foreground(shared.ColorError)
+	}
+
+	var b strings.Builder
+	b.WriteString(statusStyle.Render(diff.Status))
+	switch diff.Status {
+	case replayIdentical:
+		b.WriteString(muted.Render(fmt.Sprintf("  replay %s matches run %s", diff.ReplayRunID, diff.RunID)))
+	case replayChanged:
+		b.WriteString(muted.Render(fmt.Sprintf("  replay %s differs from run %s in %s", diff.ReplayRunID, diff.RunID, pluralize(len(diff.Changes), "place"))))
+	case replayError:
+		b.WriteString(muted.Render(fmt.Sprintf("  replay %s of run %s failed: %s", diff.ReplayRunID, diff.RunID, diff.Error)))
+	}
+	b.WriteString("\n")
+
+	for _, c := range diff.Changes {
+		pointer := c.Pointer
+		if pointer == "" {
+			pointer = "(output)"
+		}
+		switch c.Kind {
+		case flows.ChangeAdded:
+			fmt.Fprintf(&b, "  + %s: %s\n", pointer, diffValue(c.After))
+		case flows.ChangeRemoved:
+			fmt.Fprintf(&b, "  - %s: %s\n", pointer, diffValue(c.Before))
+		default:
+			fmt.Fprintf(&b, "  ~ %s: %s -> %s\n", pointer, diffValue(c.Before), diffValue(c.After))
+		}
+	}
+	return b.String()
+}
+
+// diffValue renders a changed value as compact JSON, shortened to fit on
+// a line.
+func diffValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if len(s) > 60 {
+		s = s[:57] + "..."
+	}
+	return s
+}
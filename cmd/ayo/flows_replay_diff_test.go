@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+)
+
+func TestDiffReplay(t *testing.T) {
+	original := &flows.FlowRun{ID: "run-1", OutputJSON: `{"at": "09:00", "items": ["a", "b"], "total": 2}`}
+
+	diff, err := diffReplay(original, &flows.RunResult{
+		RunID:  "run-2",
+		Status: flows.RunStatusSuccess,
+		Stdout: `{"at": "10:00", "items": ["a", "b"], "total": 2}`,
+	}, []string{"/at"})
+	if err != nil || diff.Status != replayIdentical {
+		t.Errorf("ignored change: %+v, %v", diff, err)
+	}
+	if got := formatReplayDiff(diff); !strings.Contains(got, "identical") || !strings.Contains(got, "replay run-2 matches run run-1") {
+		t.Errorf("identical report = %q", got)
+	}
+
+	diff, _ = diffReplay(original, &flows.RunResult{
+		RunID:  "run-3",
+		Status: flows.RunStatusSuccess,
+		Stdout: `{"at": "09:00", "items": ["a"], "total": 1, "note": "short"}`,
+	}, nil)
+	if diff.Status != replayChanged || len(diff.Changes) != 3 {
+		t.Fatalf("changed: %+v", diff)
+	}
+	got := formatReplayDiff(diff)
+	for _, want := range []string{"differs from run run-1 in 3 places", "  - /items/1: \"b\"", "  + /note: \"short\"", "  ~ /total: 2 -> 1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("changed report is missing %q:\n%s", want, got)
+		}
+	}
+
+	diff, _ = diffReplay(original, &flows.RunResult{
+		RunID:  "run-4",
+		Status: flows.RunStatusFailed,
+		Stdout: original.OutputJSON,
+		Error:  errors.New("exit status 3"),
+	}, nil)
+	if diff.Status != replayError || diff.Error != "exit status 3" || len(diff.Changes) != 0 {
+		t.Errorf("failed replay: %+v", diff)
+	}
+}
+
+func TestReplayExitCode(t *testing.T) {
+	for status, want := range map[string]int{
+		replayIdentical: 0,
+		replayChanged:   1,
+		replayError:     2,
+	} {
+		if got := replayExitCode(status); got != want {
+			t.Errorf("replayExitCode(%q) = %d, want %d", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+// sessionFlowInput finds the session matching query and returns its output
+// as input for flow.
+func sessionFlowInput(cmd *cobra.Command, query string, flow *flows.Flow) (string, error) {
+	services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer services.Close()
+
+	sess, err := findSession(cmd, services, query)
+	if err != nil {
+		return "", err
+	}
+	messages, err := services.Messages.List(cmd.Context(), sess.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list messages: %w", err)
+	}
+	output, err := sessionOutput(sess, messages)
+	if err != nil {
+		return "", err
+	}
+	return flowInputFromOutput(output, sess, flow)
+}
+
+// sessionOutput returns a session's structured output if it has one, and
+// otherwise the text of its last assistant message.
+func sessionOutput(sess session.Session, messages []session.Message) (string, error) {
+	if sess.StructuredOutput != "" {
+		return sess.StructuredOutput, nil
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != session.RoleAssistant {
+			continue
+		}
+		if text := strings.TrimSpace(messages[i].TextContent()); text != "" {
+			return text, nil
+		}
+	}
+	return "", fmt.Errorf("session %s has no assistant response to use as input", shortID(sess.ID))
+}
+
+// flowInputFromOutput prepares a session's output as input for flow. A flow
+// with an input schema needs JSON; a response wrapped in a single Markdown
+// code fence is unwrapped first.
+func flowInputFromOutput(output string, sess session.Session, flow *flows.Flow) (string, error) {
+	if !flow.HasInputSchema() {
+		return output, nil
+	}
+	if json.Valid([]byte(output)) {
+		return output, nil
+	}
+	if inner, ok := unfence(output); ok && json.Valid([]byte(inner)) {
+		return inner, nil
+	}
+	return "", fmt.Errorf("the last response of session %s isn't JSON, but flow %s has an input schema; pass the input as an argument, with --input, or on stdin instead", shortID(sess.ID), flow.Name)
+}
+
+// unfence returns the content of s if s is a single fenced code block.
+func unfence(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") || len(s) < 6 {
+		return "", false
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "```"), "```")
+	_, body, ok := strings.Cut(s, "\n") // Drop the info string, e.g. "json"
+	if !ok || strings.Contains(body, "```") {
+		return "", false
+	}
+	return strings.TrimSpace(body), true
+}
+
+// shortID abbreviates a session ID for messages.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func TestSessionOutput(t *testing.T) {
+	msg := func(role session.MessageRole, text string) session.Message {
+		return session.Message{Role: role, Parts: []session.ContentPart{session.TextContent{Text: text}}}
+	}
+	messages := []session.Message{
+		msg(session.RoleUser, "summarize"),
+		msg(session.RoleAssistant, "first"),
+		msg(session.RoleUser, "again"),
+		msg(session.RoleAssistant, "second"),
+		{Role: session.RoleTool},
+	}
+
+	if got, err := sessionOutput(session.Session{ID: "s1"}, messages); err != nil || got != "second" {
+		t.Errorf("sessionOutput = %q, %v, want the last assistant message", got, err)
+	}
+	structured := session.Session{ID: "s1", StructuredOutput: `{"ok":true}`}
+	if got, _ := sessionOutput(structured, messages); got != `{"ok":true}` {
+		t.Errorf("sessionOutput = %q, want the structured output", got)
+	}
+	if _, err := sessionOutput(session.Session{ID: "s1"}, messages[:1]); err == nil {
+		t.Error("a session without an assistant response should fail")
+	}
+}
+
+func TestFlowInputFromOutput(t *testing.T) {
+	sess := session.Session{ID: "4443df27-aaaa"}
+	plain := &flows.Flow{Name: "notes"}
+	schemad := &flows.Flow{Name: "triage", InputSchemaPath: "/flows/triage/input.jsonschema"}
+
+	if got, err := flowInputFromOutput("just text", sess, plain); err != nil || got != "just text" {
+		t.Errorf("flow without a schema: %q, %v", got, err)
+	}
+	if got, err := flowInputFromOutput("```json\n{\"a\": 1}\n```", sess, schemad); err != nil || got != `{"a": 1}` {
+		t.Errorf("fenced JSON: %q, %v", got, err)
+	}
+	_, err := flowInputFromOutput("Here is the summary.", sess, schemad)
+	if err == nil || !strings.Contains(err.Error(), "4443df27") || !strings.Contains(err.Error(), "triage") {
+		t.Errorf("prose for a schema'd flow: err = %v, want one naming the session and flow", err)
+	}
+}
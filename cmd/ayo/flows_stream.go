@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+)
+
+// flowEventWriter turns the stderr of a flow run into "log" events, one JSON
+// object per line, for --stream. Structured log lines carry their level,
+// message, and fields, and lines below min are dropped. Partial lines are
+// held until they end or Flush is called.
+type flowEventWriter struct {
+	enc *json.Encoder
+	min flows.LogLevel
+	buf []byte
+}
+
+func newFlowEventWriter(out io.Writer, min flows.LogLevel) *flowEventWriter {
+	return &flowEventWriter{enc: json.NewEncoder(out), min: min}
+}
+
+func (w *flowEventWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a pending partial line.
+func (w *flowEventWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *flowEventWriter) writeLine(line string) {
+	event := map[string]interface{}{
+		"type": "log",
+		"time": time.Now().Format(time.RFC3339),
+		"line": line,
+	}
+	if entry, ok := flows.ParseLogLine(line); ok {
+		if entry.Level < w.min {
+			return
+		}
+		event["level"] = entry.Level.String()
+		event["msg"] = entry.Msg
+		if len(entry.Fields) > 0 {
+			fields := make(map[string]string, len(entry.Fields))
+			for _, f := range entry.Fields {
+				fields[f.Key] = f.Value
+			}
+			event["fields"] = fields
+		}
+	}
+	w.enc.Encode(event)
+}
+
+// flowResultEvent is the "result" event that ends a --stream run. The
+// flow's output is included unless it was written to outputFile.
+func flowResultEvent(result *flows.RunResult, outputFile string) map[string]interface{} {
+	event := map[string]interface{}{
+		"type":        "result",
+		"run_id":      result.RunID,
+		"status":      string(result.Status),
+		"exit_code":   result.ExitCode,
+		"duration_ms": result.Duration.Milliseconds(),
+	}
+	if result.Error != nil {
+		event["error"] = result.Error.Error()
+	}
+	switch {
+	case outputFile != "":
+		event["output_file"] = outputFile
+	case json.Valid([]byte(result.Stdout)):
+		event["output"] = json.RawMessage(bytes.TrimSpace([]byte(result.Stdout)))
+	case result.Stdout != "":
+		event["output"] = result.Stdout
+	}
+	return event
+}
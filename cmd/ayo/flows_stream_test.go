@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/flows"
+)
+
+func TestFlowEventWriter(t *testing.T) {
+	var out strings.Builder
+	w := newFlowEventWriter(&out, flows.LogInfo)
+
+	w.Write([]byte("starting\nlevel=debug msg=noisy\n"))
+	w.Write([]byte(`level=warn msg=slow took="3 s"`))
+	w.Flush()
+
+	var events []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %v, want 2", events)
+	}
+	if events[0]["line"] != "starting" || events[0]["level"] != nil {
+		t.Errorf("plain line event = %v", events[0])
+	}
+	fields, _ := events[1]["fields"].(map[string]interface{})
+	if events[1]["level"] != "warn" || events[1]["msg"] != "slow" || fields["took"] != "3 s" {
+		t.Errorf("structured line event = %v", events[1])
+	}
+}
+
+func TestFlowResultEvent(t *testing.T) {
+	result := &flows.RunResult{
+		RunID:    "run1",
+		Status:   flows.RunStatusSuccess,
+		Stdout:   "{\"ok\": true}\n",
+		Duration: 1500 * time.Millisecond,
+	}
+
+	data, _ := json.Marshal(flowResultEvent(result, ""))
+	if want := `{"duration_ms":1500,"exit_code":0,"output":{"ok":true},"run_id":"run1","status":"success","type":"result"}`; string(data) != want {
+		t.Errorf("event = %s, want %s", data, want)
+	}
+
+	event := flowResultEvent(result, "out.json")
+	if event["output_file"] != "out.json" || event["output"] != nil {
+		t.Errorf("event with output file = %v", event)
+	}
+
+	result.Status, result.Stdout, result.Error = flows.RunStatusFailed, "not json", errors.New("boom")
+	event = flowResultEvent(result, "")
+	if event["output"] != "not json" || event["error"] != "boom" {
+		t.Errorf("failed event = %v", event)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results", "out.json")
+
+	if err := writeFileAtomic(path, []byte(`{"a": 1}`)); err != nil {
+		t.Fatalf("writeFileAtomic: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"a": 2}`)); err != nil {
+		t.Fatalf("writeFileAtomic over an existing file: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"a": 2}` {
+		t.Errorf("content = %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want only the output (no leftover temp files)", len(entries))
+	}
+}
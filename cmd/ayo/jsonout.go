@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// defaultJSONIndent is the indentation of --json output when ui.json_indent
+// isn't set.
+const defaultJSONIndent = 2
+
+// jsonIndent indents --json output, from ui.json_indent and --compact.
+// Empty writes each value on one line.
+var jsonIndent = strings.Repeat(" ", defaultJSONIndent)
+
+// setJSONIndent indents --json output by n spaces, or writes each value on
+// one line when n is 0.
+func setJSONIndent(n int) {
+	jsonIndent = strings.Repeat(" ", min(max(n, 0), 8))
+}
+
+// newJSONEncoder returns an encoder for w that indents as configured.
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	if jsonIndent != "" {
+		enc.SetIndent("", jsonIndent)
+	}
+	return enc
+}
+
+// writeJSON writes JSON to stdout, indented as configured.
+func writeJSON(v interface{}) error {
+	return newJSONEncoder(os.Stdout).Encode(v)
+}
+
+// writeJSONLine writes v to stdout as one line of JSON whatever the
+// configured indentation, for output that is read a line at a time.
+func writeJSONLine(v interface{}) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
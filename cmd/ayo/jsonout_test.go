@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONIndent(t *testing.T) {
+	t.Cleanup(func() { setJSONIndent(defaultJSONIndent) })
+	v := map[string][]int{"a": {1}}
+
+	tests := []struct {
+		indent int
+		want   string
+	}{
+		{defaultJSONIndent, "{\n  \"a\": [\n    1\n  ]\n}\n"},
+		{4, "{\n    \"a\": [\n        1\n    ]\n}\n"},
+		{0, "{\"a\":[1]}\n"},
+		{-1, "{\"a\":[1]}\n"},
+	}
+	for _, tt := range tests {
+		setJSONIndent(tt.indent)
+		var buf bytes.Buffer
+		if err := newJSONEncoder(&buf).Encode(v); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("indent %d: got %q, want %q", tt.indent, buf.String(), tt.want)
+		}
+	}
+}
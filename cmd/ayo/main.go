@@ -3,12 +3,17 @@ package main
 import (
 	"context"
 	_ "embed"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/charmbracelet/fang"
 
 	"github.com/alexcabrera/ayo/internal/builtin"
+	"github.com/alexcabrera/ayo/internal/flows"
 	"github.com/alexcabrera/ayo/internal/version"
 )
 
@@ -20,9 +25,24 @@ func init() {
 }
 
 func main() {
+	// Profiled script flows call ayo through a timing wrapper
+	if len(os.Args) > 1 && os.Args[1] == flows.StageWrapperArg {
+		exe, err := os.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ayo: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(flows.RunStageWrapper(exe, os.Args[2:]))
+	}
+
 	ctx := context.Background()
 	cmd := newRootCmd()
 
+	// Let pending memory writes finish when interrupted
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go handleInterrupts(sigs, os.Stderr, os.Exit)
+
 	// Custom error handler that suppresses "input validation failed" since we already printed it
 	errorHandler := func(w io.Writer, styles fang.Styles, err error) {
 		if err.Error() == "input validation failed" {
@@ -35,6 +55,25 @@ func main() {
 		fang.WithVersion(version.Version),
 		fang.WithErrorHandler(errorHandler),
 	); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
+
+// exitCodeError is a command error that exits with code instead of 1, once
+// fang has reported it.
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// withExitCode makes err exit the process with code.
+func withExitCode(err error, code int) error {
+	return &exitCodeError{err: err, code: code}
+}
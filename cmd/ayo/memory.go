@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/db"
 	"github.com/alexcabrera/ayo/internal/embedding"
 	"github.com/alexcabrera/ayo/internal/memory"
@@ -46,6 +48,11 @@ Categories:
 	cmd.AddCommand(newMemoryForgetCmd())
 	cmd.AddCommand(newMemoryStatsCmd())
 	cmd.AddCommand(newMemoryClearCmd())
+	cmd.AddCommand(newMemoryExportCmd())
+	cmd.AddCommand(newMemoryNearestCmd())
+	cmd.AddCommand(newMemoryReviewCmd())
+	cmd.AddCommand(newMemoryImportCmd())
+	cmd.AddCommand(newMemoryWatchCmd())
 
 	return cmd
 }
@@ -53,6 +60,7 @@ Categories:
 func newMemoryListCmd() *cobra.Command {
 	var agentFilter string
 	var categoryFilter string
+	var pathFilter string
 	var limit int64
 	var jsonOutput bool
 
@@ -68,7 +76,20 @@ func newMemoryListCmd() *cobra.Command {
 
 			svc := memory.NewService(queries, nil)
 
-			memories, err := svc.List(cmd.Context(), agentFilter, limit, 0)
+			var memories []memory.Memory
+			if agent.IsHandlePattern(agentFilter) {
+				counts, err := svc.AgentCounts(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("failed to list memories: %w", err)
+				}
+				handles := make([]string, len(counts))
+				for i, c := range counts {
+					handles[i] = c.Key
+				}
+				memories, err = svc.ListByAgents(cmd.Context(), agent.MatchHandles(agentFilter, handles), limit)
+			} else {
+				memories, err = svc.List(cmd.Context(), agentFilter, limit, 0)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to list memories: %w", err)
 			}
@@ -84,6 +105,21 @@ func newMemoryListCmd() *cobra.Command {
 				memories = filtered
 			}
 
+			// Keep path-scoped memories that would be recalled in the directory
+			if pathFilter != "" {
+				dir, err := filepath.Abs(pathFilter)
+				if err != nil {
+					return fmt.Errorf("resolve path: %w", err)
+				}
+				var filtered []memory.Memory
+				for _, m := range memories {
+					if m.PathScope != "" && memory.PathApplies(m.PathScope, dir) {
+						filtered = append(filtered, m)
+					}
+				}
+				memories = filtered
+			}
+
 			if jsonOutput {
 				return writeJSON(memoriesToJSON(memories))
 			}
@@ -100,6 +136,7 @@ func newMemoryListCmd() *cobra.Command {
 			contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
 			agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
 			timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+			pathStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("108"))
 
 			fmt.Println()
 			fmt.Println(headerStyle.Render("  Memories"))
@@ -128,9 +165,14 @@ func newMemoryListCmd() *cobra.Command {
 					categoryStyle.Render(fmt.Sprintf("%-11s", m.Category)),
 					contentStyle.Render(content),
 				)
-				fmt.Printf("     %s  %s\n",
+				scope := ""
+				if m.PathScope != "" {
+					scope = "  " + pathStyle.Render(m.PathScope)
+				}
+				fmt.Printf("     %s  %s%s\n",
 					agentStyle.Render(agent),
 					timeStyle.Render(m.CreatedAt.Format("2006-01-02 15:04")),
+					scope,
 				)
 				fmt.Println()
 			}
@@ -139,8 +181,9 @@ func newMemoryListCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Filter by agent handle")
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Filter by agent handle or glob pattern (e.g. '@team.*')")
 	cmd.Flags().StringVarP(&categoryFilter, "category", "c", "", "Filter by category")
+	cmd.Flags().StringVarP(&pathFilter, "path", "p", "", "Show path-scoped memories recalled in this directory")
 	cmd.Flags().Int64VarP(&limit, "limit", "n", 50, "Maximum number of memories to show")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 
@@ -151,41 +194,112 @@ func newMemorySearchCmd() *cobra.Command {
 	var agentFilter string
 	var threshold float64
 	var limit int
+	var mode string
+	var weightsFlag string
+	var rerank bool
+	var explain bool
 	var jsonOutput bool
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search memories semantically",
-		Args:  cobra.ExactArgs(1),
+		Long: `Search memories by meaning or by keyword.
+
+Semantic search uses Ollama embeddings. When Ollama is not available, search
+falls back to keyword (BM25) matching automatically.
+
+Modes:
+  auto       Semantic when embeddings are available, keyword otherwise (default)
+  semantic   Vector similarity only (requires Ollama)
+  keyword    Keyword matching only
+  hybrid     Vector similarity blended with keyword matching
+
+--weights ranks results by a blend of signals instead of similarity alone,
+as an agent's memory.retrieval.weights does. Give name=value pairs for
+similarity, confidence, recency, and access_count, and optionally
+half_life_days (default 30) for how fast confidence and recency decay.
+The blended score is shown; --json includes both it and the similarity.
+
+--rerank has the small model reorder the results by relevance to the query,
+choosing from up to three times --limit candidates. --explain prints the
+small model's one-line reason each result matched. Both need Ollama; without
+it, the plain results are shown with a note.`,
+		Example: `  ayo memory search "editor preferences"
+  ayo memory search "database setup" --rerank
+  ayo memory search "deploy" --weights similarity=0.7,recency=0.2,access_count=0.1
+  ayo memory search "testing" --rerank --explain`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
 
+			searchMode, err := parseSearchMode(mode)
+			if err != nil {
+				return err
+			}
+			weights, err := memory.ParseRankingWeights(weightsFlag)
+			if err != nil {
+				return err
+			}
+
 			dbConn, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
 			if err != nil {
 				return fmt.Errorf("failed to connect to database: %w", err)
 			}
 			defer dbConn.Close()
 
-			// Try to create embedder for search
-			embedder, err := createEmbedder()
-			if err != nil {
-				return fmt.Errorf("failed to create embedder (run 'ayo setup' to install): %w", err)
+			// Try to create embedder for search; keyword mode doesn't need one
+			var embedder embedding.Embedder
+			if searchMode != memory.SearchModeKeyword {
+				embedder, err = createEmbedder()
+				if err != nil {
+					if searchMode == memory.SearchModeSemantic {
+						return fmt.Errorf("failed to create embedder (run 'ayo setup' to install): %w", err)
+					}
+					embedder = nil
+				}
+			}
+			if embedder != nil {
+				defer embedder.Close()
 			}
-			defer embedder.Close()
 
 			svc := memory.NewService(queries, embedder)
 
+			effectiveMode := svc.ResolveSearchMode(searchMode)
+			if effectiveMode == memory.SearchModeKeyword && searchMode != memory.SearchModeKeyword && !jsonOutput {
+				noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+				fmt.Fprintln(os.Stderr, noteStyle.Render("Ollama not available, using keyword search"))
+			}
+
+			// Fetch extra candidates for the small model to rerank
+			fetchLimit := limit
+			if rerank {
+				fetchLimit = max(limit, min(limit*rerankPool, maxRerankCandidates))
+			}
+
 			results, err := svc.Search(cmd.Context(), query, memory.SearchOptions{
 				AgentHandle: agentFilter,
 				Threshold:   float32(threshold),
-				Limit:       limit,
+				Limit:       fetchLimit,
+				Mode:        searchMode,
+				Weights:     weights,
 			})
 			if err != nil {
 				return fmt.Errorf("search failed: %w", err)
 			}
 
+			var judged map[string]smallmodel.MemoryRelevance
+			if (rerank || explain) && len(results) > 0 {
+				judged = judgeSearchResults(cmd.Context(), smallmodel.NewService(smallmodel.Config{}), query, results, jsonOutput)
+			}
+			if rerank && judged != nil {
+				results = rerankByRelevance(results, judged)
+			}
+			if len(results) > limit {
+				results = results[:limit]
+			}
+
 			if jsonOutput {
-				return writeJSON(searchResultsToJSON(results))
+				return writeJSON(judgedResultsToJSON(results, judged, explain))
 			}
 
 			if len(results) == 0 {
@@ -199,9 +313,15 @@ func newMemorySearchCmd() *cobra.Command {
 			scoreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
 			contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
 			categoryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+			explainStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+			modeLabel := string(effectiveMode)
+			if rerank && judged != nil {
+				modeLabel += ", reranked"
+			}
 
 			fmt.Println()
-			fmt.Println(headerStyle.Render(fmt.Sprintf("  Search Results for: %s", query)))
+			fmt.Println(headerStyle.Render(fmt.Sprintf("  Search Results for: %s (%s)", query, modeLabel)))
 			fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
 			fmt.Println()
 
@@ -216,14 +336,24 @@ func newMemorySearchCmd() *cobra.Command {
 					idShort = idShort[:8]
 				}
 
+				// Reranked results show the small model's relevance score
+				score := float64(r.Score)
+				j, isJudged := judged[r.Memory.ID]
+				if rerank && isJudged {
+					score = j.Score
+				}
+
 				fmt.Printf("  %s  %s  %s\n",
 					idStyle.Render(idShort),
-					scoreStyle.Render(fmt.Sprintf("%.2f", r.Similarity)),
+					scoreStyle.Render(fmt.Sprintf("%.2f", score)),
 					contentStyle.Render(content),
 				)
 				fmt.Printf("     %s\n",
 					categoryStyle.Render(string(r.Memory.Category)),
 				)
+				if explain && isJudged && j.Reason != "" {
+					fmt.Printf("     %s\n", explainStyle.Render("why: "+j.Reason))
+				}
 				fmt.Println()
 			}
 
@@ -234,18 +364,48 @@ func newMemorySearchCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Filter by agent handle")
 	cmd.Flags().Float64VarP(&threshold, "threshold", "t", 0.3, "Minimum similarity threshold (0-1)")
 	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum number of results")
+	cmd.Flags().StringVar(&mode, "mode", "auto", "Search mode: auto, semantic, keyword, hybrid")
+	cmd.Flags().StringVar(&weightsFlag, "weights", "", "Rank by weighted signals, e.g. similarity=0.7,recency=0.3")
+	cmd.Flags().BoolVar(&rerank, "rerank", false, "Reorder results by relevance using the small model")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Print why each result matched, using the small model")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 
 	return cmd
 }
 
+// parseSearchMode converts a --mode flag value to a memory.SearchMode.
+func parseSearchMode(s string) (memory.SearchMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return memory.SearchModeAuto, nil
+	case "semantic":
+		return memory.SearchModeSemantic, nil
+	case "keyword":
+		return memory.SearchModeKeyword, nil
+	case "hybrid":
+		return memory.SearchModeHybrid, nil
+	default:
+		return "", fmt.Errorf("invalid search mode %q (use auto, semantic, keyword, or hybrid)", s)
+	}
+}
+
 func newMemoryShowCmd() *cobra.Command {
 	var jsonOutput bool
+	var related bool
+	var relatedLimit int
 
 	cmd := &cobra.Command{
 		Use:   "show [id]",
 		Short: "Show memory details",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Show the details of a memory.
+
+With --related, the memories most similar to it are listed beneath the
+details, using the stored embeddings like ayo memory nearest. Related
+memories in a different category or for a different agent are marked, since
+they may conflict with this one.`,
+		Example: `  ayo memory show 3f2a
+  ayo memory show 3f2a --related -n 10`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dbConn, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
 			if err != nil {
@@ -270,8 +430,23 @@ func newMemoryShowCmd() *cobra.Command {
 				}
 			}
 
+			var relatedResults []memory.SearchResult
+			hasEmbedding := true
+			if related {
+				relatedResults, _, err = svc.Nearest(cmd.Context(), mem.ID, memory.SearchOptions{Limit: relatedLimit})
+				if errors.Is(err, memory.ErrNoEmbedding) {
+					hasEmbedding = false
+				} else if err != nil {
+					return fmt.Errorf("related failed: %w", err)
+				}
+			}
+
 			if jsonOutput {
-				return writeJSON(memoryToJSON(mem))
+				out := memoryToJSON(mem)
+				if related {
+					out["related"] = relatedToJSON(mem, relatedResults)
+				}
+				return writeJSON(out)
 			}
 
 			// Styles
@@ -309,11 +484,17 @@ func newMemoryShowCmd() *cobra.Command {
 
 			fmt.Println()
 
+			if related {
+				printRelatedMemories(mem, relatedResults, hasEmbedding)
+			}
+
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&related, "related", false, "List the most similar memories")
+	cmd.Flags().IntVarP(&relatedLimit, "limit", "n", 5, "Maximum number of related memories")
 
 	return cmd
 }
@@ -332,6 +513,15 @@ func newMemoryStoreCmd() *cobra.Command {
 			content := args[0]
 			ctx := cmd.Context()
 
+			// Scopes are matched against the working directory, so store them absolute
+			if pathScope != "" {
+				abs, err := filepath.Abs(pathScope)
+				if err != nil {
+					return fmt.Errorf("resolve path scope: %w", err)
+				}
+				pathScope = abs
+			}
+
 			// Don't show spinner for JSON output (used by agents)
 			var spinner *ui.Spinner
 			if !jsonOutput {
@@ -454,7 +644,7 @@ func newMemoryStoreCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&agentHandle, "agent", "a", "", "Agent handle for scoping")
 	cmd.Flags().StringVarP(&category, "category", "c", "fact", "Memory category (preference, fact, correction, pattern) - auto-detected if not specified")
-	cmd.Flags().StringVarP(&pathScope, "path", "p", "", "Path scope for this memory")
+	cmd.Flags().StringVarP(&pathScope, "path", "p", "", "Only recall this memory when running in this directory or below it")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 
 	return cmd
@@ -546,6 +736,13 @@ func newMemoryStatsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "stats",
 		Short: "Show memory statistics",
+		Long: `Show how memories are distributed: counts by status, category, and agent,
+average confidence, the oldest and newest active memory, storage used, and
+the most accessed memories.
+
+Counts by category and agent, confidence, and timestamps cover active
+memories. Storage includes superseded and forgotten memories, which stay
+in the database.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dbConn, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
 			if err != nil {
@@ -555,29 +752,16 @@ func newMemoryStatsCmd() *cobra.Command {
 
 			svc := memory.NewService(queries, nil)
 
-			total, err := svc.Count(cmd.Context(), "")
+			stats, err := svc.Stats(cmd.Context(), memoryStatsTopN)
 			if err != nil {
-				return fmt.Errorf("failed to count memories: %w", err)
+				return fmt.Errorf("failed to read memory statistics: %w", err)
 			}
 
 			if jsonOutput {
-				return writeJSON(map[string]interface{}{
-					"total_active": total,
-				})
+				return writeJSON(memoryStatsToJSON(stats))
 			}
 
-			// Styles
-			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
-			labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-			valueStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220"))
-
-			fmt.Println()
-			fmt.Println(headerStyle.Render("  Memory Statistics"))
-			fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 30)))
-			fmt.Println()
-			fmt.Printf("  %s %s\n", labelStyle.Render("Total Active Memories:"), valueStyle.Render(fmt.Sprintf("%d", total)))
-			fmt.Println()
-
+			printMemoryStats(stats)
 			return nil
 		},
 	}
@@ -587,6 +771,127 @@ func newMemoryStatsCmd() *cobra.Command {
 	return cmd
 }
 
+// memoryStatsTopN is the number of most accessed memories in memory stats.
+const memoryStatsTopN = 5
+
+// printMemoryStats prints the memory stats summary.
+func printMemoryStats(stats memory.Stats) {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	valueStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220"))
+	idStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	categoryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("69"))
+
+	field := func(label, value string) {
+		fmt.Printf("  %s %s\n", labelStyle.Render(fmt.Sprintf("%-16s", label)), valueStyle.Render(value))
+	}
+	section := func(title string) {
+		fmt.Println()
+		fmt.Println(headerStyle.Render("  " + title))
+		fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 30)))
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Memory Statistics"))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 30)))
+	fmt.Println()
+	field("Active:", fmt.Sprintf("%d", stats.Active))
+	for _, c := range stats.ByStatus {
+		if c.Key != string(memory.StatusActive) {
+			field(strings.ToUpper(c.Key[:1])+c.Key[1:]+":", fmt.Sprintf("%d", c.Count))
+		}
+	}
+	if stats.Active > 0 {
+		field("Avg confidence:", fmt.Sprintf("%.2f", stats.AvgConfidence))
+		field("Oldest:", stats.Oldest.Format("2006-01-02 15:04"))
+		field("Newest:", stats.Newest.Format("2006-01-02 15:04"))
+	}
+	field("Storage:", formatMemoryBytes(stats.StorageBytes))
+
+	if len(stats.ByCategory) > 0 {
+		section("By Category")
+		for _, c := range stats.ByCategory {
+			fmt.Printf("  %s %d\n", categoryStyle.Render(fmt.Sprintf("%-16s", c.Key)), c.Count)
+		}
+	}
+
+	if len(stats.ByAgent) > 0 {
+		section("By Agent")
+		for _, c := range stats.ByAgent {
+			agent := c.Key
+			if agent == "" {
+				agent = "global"
+			}
+			fmt.Printf("  %s %d\n", agentStyle.Render(fmt.Sprintf("%-16s", agent)), c.Count)
+		}
+	}
+
+	if len(stats.MostAccessed) > 0 {
+		section("Most Accessed")
+		for _, m := range stats.MostAccessed {
+			content := m.Content
+			if len(content) > 50 {
+				content = content[:47] + "..."
+			}
+			idShort := m.ID
+			if len(idShort) > 8 {
+				idShort = idShort[:8]
+			}
+			fmt.Printf("  %s  %s  %s\n",
+				idStyle.Render(idShort),
+				labelStyle.Render(fmt.Sprintf("%4dx", m.AccessCount)),
+				content,
+			)
+		}
+	}
+	fmt.Println()
+}
+
+// memoryStatsToJSON converts memory stats to JSON-friendly format.
+func memoryStatsToJSON(stats memory.Stats) map[string]interface{} {
+	counts := func(list []memory.StatCount) map[string]int64 {
+		result := make(map[string]int64, len(list))
+		for _, c := range list {
+			result[c.Key] = c.Count
+		}
+		return result
+	}
+
+	byAgent := counts(stats.ByAgent)
+	if n, ok := byAgent[""]; ok {
+		delete(byAgent, "")
+		byAgent["global"] = n
+	}
+
+	result := map[string]interface{}{
+		"total_active":   stats.Active,
+		"by_status":      counts(stats.ByStatus),
+		"by_category":    counts(stats.ByCategory),
+		"by_agent":       byAgent,
+		"avg_confidence": stats.AvgConfidence,
+		"storage_bytes":  stats.StorageBytes,
+		"most_accessed":  memoriesToJSON(stats.MostAccessed),
+	}
+	if !stats.Oldest.IsZero() {
+		result["oldest_created_at"] = stats.Oldest.Format(time.RFC3339)
+		result["newest_created_at"] = stats.Newest.Format(time.RFC3339)
+	}
+	return result
+}
+
+// formatMemoryBytes formats a byte count as B, KB, or MB.
+func formatMemoryBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
 func newMemoryClearCmd() *cobra.Command {
 	var agentFilter string
 	var force bool
@@ -603,6 +908,10 @@ func newMemoryClearCmd() *cobra.Command {
 
 			svc := memory.NewService(queries, nil)
 
+			if agent.IsHandlePattern(agentFilter) {
+				return clearMatchingMemories(cmd.Context(), svc, agentFilter, force)
+			}
+
 			count, err := svc.Count(cmd.Context(), agentFilter)
 			if err != nil {
 				return fmt.Errorf("failed to count memories: %w", err)
@@ -639,12 +948,307 @@ func newMemoryClearCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Only clear memories for this agent")
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Only clear memories for this agent, or agents matching a glob pattern")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
 
 	return cmd
 }
 
+// clearMatchingMemories forgets the memories of every agent matching
+// pattern. The agents and their memory counts are listed before anything is
+// cleared, and the confirmation names how many agents are affected.
+func clearMatchingMemories(ctx context.Context, svc *memory.Service, pattern string, force bool) error {
+	counts, err := svc.AgentCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count memories: %w", err)
+	}
+	var matched []memory.StatCount
+	var total int64
+	for _, c := range counts {
+		if agent.MatchHandle(pattern, c.Key) {
+			matched = append(matched, c)
+			total += c.Count
+		}
+	}
+	if len(matched) == 0 {
+		fmt.Printf("No memories for agents matching %s\n", pattern)
+		return nil
+	}
+
+	target := fmt.Sprintf("%d memories from %s matching %s", total, pluralize(len(matched), "agent"), pattern)
+	fmt.Printf("This will forget %s:\n", target)
+	for _, c := range matched {
+		fmt.Printf("  %s  %d\n", c.Key, c.Count)
+	}
+	if !force {
+		fmt.Print("Type 'yes' to confirm: ")
+		var confirm string
+		fmt.Scanln(&confirm)
+		if confirm != "yes" {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	for _, c := range matched {
+		if err := svc.Clear(ctx, c.Key); err != nil {
+			return fmt.Errorf("failed to clear memories for %s: %w", c.Key, err)
+		}
+	}
+	fmt.Printf("Cleared %s\n", target)
+	return nil
+}
+
+func newMemoryExportCmd() *cobra.Command {
+	var agentFilter string
+	var output string
+	var withEmbeddings bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export memories as JSON",
+		Long: `Export active memories as a JSON array for analysis or backup.
+
+With --embeddings, each memory also includes its raw embedding vector.
+Memories stored before an embedder was available have no vector; they are
+exported without one and counted in a note on stderr.`,
+		Example: `  ayo memory export > memories.json
+  ayo memory export --agent @ayo --embeddings -o vectors.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbConn, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer dbConn.Close()
+
+			svc := memory.NewService(queries, nil)
+
+			memories, err := svc.All(cmd.Context(), agentFilter)
+			if err != nil {
+				return fmt.Errorf("failed to list memories: %w", err)
+			}
+
+			entries := memoriesToJSON(memories)
+			missing := 0
+			if withEmbeddings {
+				for i, m := range memories {
+					if len(m.Embedding) == 0 {
+						missing++
+						continue
+					}
+					entries[i]["embedding"] = m.Embedding
+				}
+			}
+
+			out := os.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			if err := newJSONEncoder(out).Encode(entries); err != nil {
+				return err
+			}
+
+			if missing > 0 {
+				noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+				fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("%d of %d memories have no embedding (stored before an embedder was available)", missing, len(memories))))
+			}
+			if output != "" {
+				fmt.Fprintf(os.Stderr, "Exported %d memories to %s\n", len(memories), output)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Only export memories for this agent")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().BoolVar(&withEmbeddings, "embeddings", false, "Include raw embedding vectors")
+
+	return cmd
+}
+
+func newMemoryNearestCmd() *cobra.Command {
+	var agentFilter string
+	var threshold float64
+	var limit int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "nearest <id>",
+		Short: "Find memories similar to a memory",
+		Long: `Find the memories most similar to a given memory, using the stored
+embedding vectors and the same cosine similarity as semantic search. Nothing
+is re-embedded, so Ollama does not need to be running.
+
+Use it to find clusters of redundant memories to merge or forget. Memories
+without an embedding (stored before an embedder was available) are skipped.`,
+		Example: `  ayo memory nearest 3f2a
+  ayo memory nearest 3f2a --threshold 0.8 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbConn, queries, err := db.ConnectWithQueries(cmd.Context(), paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer dbConn.Close()
+
+			svc := memory.NewService(queries, nil)
+
+			mem, err := svc.GetByPrefix(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("memory not found: %w", err)
+			}
+
+			noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+			results, skipped, err := svc.Nearest(cmd.Context(), mem.ID, memory.SearchOptions{
+				AgentHandle: agentFilter,
+				Threshold:   float32(threshold),
+				Limit:       limit,
+			})
+			if errors.Is(err, memory.ErrNoEmbedding) {
+				if jsonOutput {
+					return writeJSON([]map[string]interface{}{})
+				}
+				fmt.Fprintln(os.Stderr, noteStyle.Render("Memory has no embedding (stored before an embedder was available)"))
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("nearest failed: %w", err)
+			}
+
+			if jsonOutput {
+				return writeJSON(searchResultsToJSON(results))
+			}
+
+			if skipped > 0 {
+				fmt.Fprintln(os.Stderr, noteStyle.Render(fmt.Sprintf("Skipped %d memories without a comparable embedding", skipped)))
+			}
+
+			if len(results) == 0 {
+				fmt.Println("No similar memories found")
+				return nil
+			}
+
+			// Styles
+			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+			idStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			scoreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+			contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
+			categoryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+
+			title := mem.Content
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+
+			fmt.Println()
+			fmt.Println(headerStyle.Render(fmt.Sprintf("  Nearest to: %s", title)))
+			fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
+			fmt.Println()
+
+			for _, r := range results {
+				content := r.Memory.Content
+				if len(content) > 60 {
+					content = content[:57] + "..."
+				}
+
+				idShort := r.Memory.ID
+				if len(idShort) > 8 {
+					idShort = idShort[:8]
+				}
+
+				fmt.Printf("  %s  %s  %s\n",
+					idStyle.Render(idShort),
+					scoreStyle.Render(fmt.Sprintf("%.2f", r.Similarity)),
+					contentStyle.Render(content),
+				)
+				fmt.Printf("     %s\n",
+					categoryStyle.Render(string(r.Memory.Category)),
+				)
+				fmt.Println()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Filter by agent handle")
+	cmd.Flags().Float64VarP(&threshold, "threshold", "t", 0.5, "Minimum similarity threshold (0-1)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum number of results")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// relatedConflicts describes how a related memory differs from mem in
+// category or agent, which may mean the two conflict.
+func relatedConflicts(mem, other memory.Memory) []string {
+	conflicts := []string{}
+	if other.Category != mem.Category {
+		conflicts = append(conflicts, fmt.Sprintf("category %s", other.Category))
+	}
+	if other.AgentHandle != mem.AgentHandle {
+		agent := other.AgentHandle
+		if agent == "" {
+			agent = "global"
+		}
+		conflicts = append(conflicts, fmt.Sprintf("agent %s", agent))
+	}
+	return conflicts
+}
+
+// printRelatedMemories lists the memories related to mem beneath its details.
+func printRelatedMemories(mem memory.Memory, results []memory.SearchResult, hasEmbedding bool) {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("245"))
+	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	idStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	scoreStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
+	categoryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+
+	fmt.Printf("%s\n", labelStyle.Render("Related:"))
+	switch {
+	case !hasEmbedding:
+		fmt.Printf("  %s\n", noteStyle.Render("Memory has no embedding (stored before an embedder was available)"))
+		fmt.Println()
+		return
+	case len(results) == 0:
+		fmt.Println("  No similar memories found")
+		fmt.Println()
+		return
+	}
+
+	for _, r := range results {
+		content := r.Memory.Content
+		if len(content) > 60 {
+			content = content[:57] + "..."
+		}
+		idShort := r.Memory.ID
+		if len(idShort) > 8 {
+			idShort = idShort[:8]
+		}
+
+		fmt.Printf("  %s  %s  %s\n",
+			idStyle.Render(idShort),
+			scoreStyle.Render(fmt.Sprintf("%.2f", r.Similarity)),
+			contentStyle.Render(content),
+		)
+		detail := categoryStyle.Render(string(r.Memory.Category))
+		if conflicts := relatedConflicts(mem, r.Memory); len(conflicts) > 0 {
+			detail += "  " + noteStyle.Render("possible conflict: "+strings.Join(conflicts, ", "))
+		}
+		fmt.Printf("     %s\n", detail)
+	}
+	fmt.Println()
+}
+
 func createEmbedder() (embedding.Embedder, error) {
 	client := ollama.NewClient()
 	if !client.IsAvailable(context.Background()) {
@@ -654,13 +1258,6 @@ func createEmbedder() (embedding.Embedder, error) {
 	return embedding.NewOllamaEmbedder(embedding.OllamaConfig{}), nil
 }
 
-// writeJSON writes JSON to stdout.
-func writeJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
-}
-
 // memoryToJSON converts a memory to a JSON-friendly map.
 func memoryToJSON(m memory.Memory) map[string]interface{} {
 	result := map[string]interface{}{
@@ -704,6 +1301,22 @@ func searchResultsToJSON(results []memory.SearchResult) []map[string]interface{}
 		output[i] = map[string]interface{}{
 			"memory":     memoryToJSON(r.Memory),
 			"similarity": r.Similarity,
+			"score":      r.Score,
+			"mode":       string(r.Mode),
+		}
+	}
+	return output
+}
+
+// relatedToJSON converts related memories to JSON, marking the ones that
+// differ from mem in category or agent.
+func relatedToJSON(mem memory.Memory, results []memory.SearchResult) []map[string]interface{} {
+	output := make([]map[string]interface{}, len(results))
+	for i, r := range results {
+		output[i] = map[string]interface{}{
+			"memory":     memoryToJSON(r.Memory),
+			"similarity": r.Similarity,
+			"conflicts":  relatedConflicts(mem, r.Memory),
 		}
 	}
 	return output
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+	"github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// importResult summarizes a memory import.
+type importResult struct {
+	Messages   int // User messages read from the export
+	Failed     int // Messages the small model could not read
+	Extracted  int // Memories extracted from the messages
+	Duplicates int // Extracted memories already known
+	Proposals  []memory.Proposal
+}
+
+func newMemoryImportCmd() *cobra.Command {
+	var fromChatGPT bool
+	var formatPath string
+	var agentHandle string
+	var dryRun bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Propose memories from another assistant's chat history",
+		Long: `Extract memories from the chat history exported by another assistant and
+queue them for review.
+
+With --from-chatgpt, <file> is the zip from ChatGPT's data export or the
+conversations.json inside it. For other tools, --format names a JSON file
+mapping the export to messages:
+
+  {
+    "messages": "conversations[].messages[]",
+    "text": "content",
+    "role": "author",
+    "user_role": "user"
+  }
+
+"messages" is the path from the root of the export to each message and
+"text" the path from a message to its text. Keys are separated by dots, and
+"[]" steps into each element of an array; a bare "[]" steps into an export
+that is an array. Only messages whose "role" is "user_role" (default
+"user") are read; leave "role" out to read every message.
+
+Each user message goes through the small model's memory extraction, which
+needs Ollama. Extracted memories that duplicate a stored memory, a queued
+proposal, or each other are skipped. The rest are queued as proposals, so
+nothing is stored until you accept it with ayo memory review. A memory
+close to a stored one replaces it when accepted.
+
+Use --dry-run to print what would be queued.`,
+		Example: `  ayo memory import ~/Downloads/chatgpt-export.zip --from-chatgpt
+  ayo memory import conversations.json --from-chatgpt --agent @ayo --dry-run
+  ayo memory import history.json --format mapping.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if fromChatGPT == (formatPath != "") {
+				return fmt.Errorf("specify the export format with either --from-chatgpt or --format")
+			}
+			if agentHandle != "" {
+				agentHandle = agent.NormalizeHandle(agentHandle)
+			}
+
+			messages, err := readImportMessages(args[0], fromChatGPT, formatPath)
+			if err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				return fmt.Errorf("no user messages found in %s", args[0])
+			}
+
+			smallSvc := smallmodel.NewService(smallmodel.Config{})
+			if !smallSvc.IsAvailable(ctx) {
+				return fmt.Errorf("memory import needs the small model; start Ollama and try again")
+			}
+
+			dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer dbConn.Close()
+
+			// Without embeddings only identical memories are recognized as duplicates
+			embedder, err := createEmbedder()
+			if err != nil {
+				embedder = nil
+			}
+			if embedder != nil {
+				defer embedder.Close()
+			}
+			svc := memory.NewService(queries, embedder)
+
+			result, err := runMemoryImport(ctx, svc, smallSvc, agentHandle, messages, dryRun, jsonOutput)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				proposals := make([]map[string]interface{}, len(result.Proposals))
+				for i, p := range result.Proposals {
+					proposals[i] = proposalToJSON(p)
+					if dryRun {
+						delete(proposals[i], "id")
+						delete(proposals[i], "created_at")
+					}
+				}
+				return writeJSON(map[string]interface{}{
+					"messages":   result.Messages,
+					"failed":     result.Failed,
+					"extracted":  result.Extracted,
+					"duplicates": result.Duplicates,
+					"queued":     !dryRun,
+					"proposals":  proposals,
+				})
+			}
+			printImportResult(result, dryRun)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromChatGPT, "from-chatgpt", false, "Read a ChatGPT data export")
+	cmd.Flags().StringVar(&formatPath, "format", "", "JSON file mapping the export to messages")
+	cmd.Flags().StringVarP(&agentHandle, "agent", "a", "", "Agent handle for scoping (default global)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the memories that would be queued without queueing them")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// readImportMessages reads the user messages of the export at path.
+func readImportMessages(path string, fromChatGPT bool, formatPath string) ([]memory.ImportedMessage, error) {
+	if !fromChatGPT {
+		format, err := memory.LoadImportFormat(formatPath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return format.Parse(data)
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		data, err = readZipFile(path, "conversations.json")
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return memory.ParseChatGPTExport(data)
+}
+
+// readZipFile reads the file called name from the zip archive at path,
+// wherever it is in the archive.
+func readZipFile(path, name string) ([]byte, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if filepath.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in %s", name, path)
+}
+
+// runMemoryImport extracts memories from messages, drops duplicates, and
+// queues the rest as proposals unless dryRun is set.
+func runMemoryImport(ctx context.Context, svc *memory.Service, smallSvc *smallmodel.Service, agentHandle string, messages []memory.ImportedMessage, dryRun, quiet bool) (importResult, error) {
+	result := importResult{Messages: len(messages)}
+
+	var spinner *ui.Spinner
+	if !quiet {
+		spinner = ui.NewSpinnerWithType("extracting memories...", ui.SpinnerMemory)
+		spinner.Start()
+	}
+
+	var candidates []memory.ImportCandidate
+	for i, msg := range messages {
+		if spinner != nil {
+			spinner.SetPhase(fmt.Sprintf("extracting memories from message %d of %d", i+1, len(messages)))
+		}
+		extraction, err := smallSvc.ExtractMemory(ctx, msg.Text)
+		if err != nil {
+			if ctx.Err() != nil {
+				if spinner != nil {
+					spinner.StopWithError("import cancelled")
+				}
+				return result, ctx.Err()
+			}
+			result.Failed++
+			continue
+		}
+		for _, item := range extraction.Items() {
+			candidates = append(candidates, memory.ImportCandidate{
+				Content:  item.Content,
+				Category: importCategory(item.Category),
+			})
+		}
+	}
+	result.Extracted = len(candidates)
+
+	if spinner != nil {
+		spinner.SetPhase("checking for duplicates")
+	}
+	proposals, duplicates, err := svc.PrepareImport(ctx, agentHandle, candidates)
+	if err != nil {
+		if spinner != nil {
+			spinner.StopWithError("failed to check for duplicates")
+		}
+		return result, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+	result.Duplicates = duplicates
+
+	if !dryRun {
+		for i, p := range proposals {
+			queued, err := svc.Propose(ctx, p)
+			if err != nil {
+				if spinner != nil {
+					spinner.StopWithError("failed to queue proposals")
+				}
+				return result, fmt.Errorf("failed to queue proposal: %w", err)
+			}
+			proposals[i] = queued
+		}
+	}
+	result.Proposals = proposals
+
+	if spinner != nil {
+		spinner.Stop()
+	}
+	return result, nil
+}
+
+// importCategory maps an extracted category to a memory category.
+func importCategory(s string) memory.Category {
+	switch s {
+	case "preference":
+		return memory.CategoryPreference
+	case "correction":
+		return memory.CategoryCorrection
+	case "pattern":
+		return memory.CategoryPattern
+	default:
+		return memory.CategoryFact
+	}
+}
+
+// printImportResult prints the memories an import queued, or would queue
+// with dryRun, and how many were skipped.
+func printImportResult(result importResult, dryRun bool) {
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	categoryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+
+	for _, p := range result.Proposals {
+		line := "  " + categoryStyle.Render(fmt.Sprintf("%-10s", p.Category)) + " " + p.Content
+		if p.SupersedesID != "" {
+			line += mutedStyle.Render(" (replaces " + p.SupersedesID[:8] + ")")
+		}
+		fmt.Println(line)
+	}
+	if len(result.Proposals) > 0 {
+		fmt.Println()
+	}
+
+	details := fmt.Sprintf("%s read, %d extracted, %s skipped", pluralize(result.Messages, "message"),
+		result.Extracted, pluralize(result.Duplicates, "duplicate"))
+	if result.Failed > 0 {
+		details += fmt.Sprintf(", %s failed", pluralize(result.Failed, "message"))
+	}
+	verb := "Queued"
+	if dryRun {
+		verb = "Would queue"
+	}
+	fmt.Printf("%s %s %s\n", verb, pluralize(len(result.Proposals), "proposal"), mutedStyle.Render("("+details+")"))
+	if !dryRun && len(result.Proposals) > 0 {
+		fmt.Println(mutedStyle.Render("Run ayo memory review to accept or reject them"))
+	}
+}
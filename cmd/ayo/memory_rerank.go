@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+)
+
+// rerankPool is how many times --limit results are fetched for --rerank, so
+// the small model can promote results ranked below the limit.
+const rerankPool = 3
+
+// maxRerankCandidates caps how many results are sent to the small model.
+const maxRerankCandidates = 30
+
+// judgeSearchResults asks the small model how relevant each result is to
+// query, for --rerank and --explain. When the small model is unavailable or
+// fails it prints a note (unless quiet) and returns nil, so the plain
+// results are shown.
+func judgeSearchResults(ctx context.Context, svc *smallmodel.Service, query string, results []memory.SearchResult, quiet bool) map[string]smallmodel.MemoryRelevance {
+	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	note := func(reason string) {
+		if !quiet {
+			fmt.Fprintln(os.Stderr, noteStyle.Render(reason+"; --rerank and --explain are unavailable"))
+		}
+	}
+
+	if !svc.IsAvailable(ctx) {
+		note("Small model not available")
+		return nil
+	}
+
+	candidates := make([]smallmodel.ExistingMemory, len(results))
+	for i, r := range results {
+		candidates[i] = smallmodel.ExistingMemory{ID: r.Memory.ID, Content: r.Memory.Content}
+	}
+	judged, err := svc.JudgeRelevance(ctx, query, candidates)
+	if err != nil {
+		note(fmt.Sprintf("Small model failed (%v)", err))
+		return nil
+	}
+
+	byID := make(map[string]smallmodel.MemoryRelevance, len(judged))
+	for _, j := range judged {
+		byID[j.ID] = j
+	}
+	return byID
+}
+
+// rerankByRelevance orders results by the small model's relevance score.
+// Results it didn't judge keep their order after the judged ones.
+func rerankByRelevance(results []memory.SearchResult, judged map[string]smallmodel.MemoryRelevance) []memory.SearchResult {
+	reranked := append([]memory.SearchResult(nil), results...)
+	sort.SliceStable(reranked, func(i, j int) bool {
+		a, aok := judged[reranked[i].Memory.ID]
+		b, bok := judged[reranked[j].Memory.ID]
+		if aok != bok {
+			return aok
+		}
+		return aok && a.Score > b.Score
+	})
+	return reranked
+}
+
+// judgedResultsToJSON converts search results to JSON, adding the small
+// model's relevance score and, with explain, its rationale.
+func judgedResultsToJSON(results []memory.SearchResult, judged map[string]smallmodel.MemoryRelevance, explain bool) []map[string]interface{} {
+	output := searchResultsToJSON(results)
+	for i, r := range results {
+		j, ok := judged[r.Memory.ID]
+		if !ok {
+			continue
+		}
+		output[i]["relevance"] = j.Score
+		if explain {
+			output[i]["explanation"] = j.Reason
+		}
+	}
+	return output
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+)
+
+func searchResult(id string, similarity float32) memory.SearchResult {
+	return memory.SearchResult{Memory: memory.Memory{ID: id, Content: "memory " + id}, Similarity: similarity}
+}
+
+func TestRerankByRelevance(t *testing.T) {
+	results := []memory.SearchResult{
+		searchResult("a", 0.9),
+		searchResult("b", 0.8),
+		searchResult("c", 0.7),
+		searchResult("d", 0.6),
+	}
+	judged := map[string]smallmodel.MemoryRelevance{
+		"a": {ID: "a", Score: 0.1},
+		"c": {ID: "c", Score: 0.9},
+		"d": {ID: "d", Score: 0.5},
+	}
+
+	got := rerankByRelevance(results, judged)
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.Memory.ID)
+	}
+	if want := "c d a b"; strings.Join(ids, " ") != want {
+		t.Errorf("reranked = %q, want %q", strings.Join(ids, " "), want)
+	}
+	if results[0].Memory.ID != "a" {
+		t.Error("rerankByRelevance modified its input")
+	}
+}
+
+func TestJudgeSearchResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models": []}`))
+		case "/api/chat":
+			json.NewEncoder(w).Encode(map[string]any{
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": `{"results": [{"id": "bbbbbbbb", "score": 0.9, "reason": "names the editor"}]}`,
+				},
+				"done": true,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	results := []memory.SearchResult{searchResult("aaaaaaaa-1", 0.9), searchResult("bbbbbbbb-2", 0.8)}
+	svc := smallmodel.NewService(smallmodel.Config{Host: server.URL})
+
+	judged := judgeSearchResults(context.Background(), svc, "which editor", results, true)
+	if j, ok := judged["bbbbbbbb-2"]; !ok || j.Score != 0.9 || j.Reason != "names the editor" {
+		t.Errorf("judged = %+v", judged)
+	}
+
+	out := judgedResultsToJSON(results, judged, true)
+	if _, ok := out[0]["relevance"]; ok {
+		t.Error("unjudged result has a relevance score")
+	}
+	if out[1]["relevance"] != 0.9 || out[1]["explanation"] != "names the editor" {
+		t.Errorf("judged JSON = %v", out[1])
+	}
+
+	// Without a small model, nothing is judged
+	server.Close()
+	if judged := judgeSearchResults(context.Background(), svc, "which editor", results, true); judged != nil {
+		t.Errorf("judged without a small model = %+v, want nil", judged)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// Review choices for a proposed memory.
+const (
+	reviewAccept = "accept"
+	reviewEdit   = "edit"
+	reviewReject = "reject"
+	reviewSkip   = "skip"
+	reviewQuit   = "quit"
+)
+
+func newMemoryReviewCmd() *cobra.Command {
+	var agentHandle string
+	var listOnly bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review memories proposed in review mode",
+		Long: `Review memories proposed by agents with formation_triggers.review set.
+
+In review mode, memories extracted from conversations are queued instead of
+stored. This command presents each proposal, oldest first, to accept, edit
+and accept, reject, or skip until later. Accepted memories are stored the
+same way automatic formation stores them, replacing the memory they were
+matched against when it is still active.
+
+Proposals stay queued across sessions until they are accepted or rejected.
+Use --list to print them without reviewing.`,
+		Example: `  ayo memory review
+  ayo memory review --agent @ayo
+  ayo memory review --list --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if jsonOutput && !listOnly {
+				return fmt.Errorf("the --json flag needs --list")
+			}
+			if agentHandle != "" {
+				agentHandle = agent.NormalizeHandle(agentHandle)
+			}
+
+			dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer dbConn.Close()
+
+			svc := memory.NewService(queries, nil)
+			proposals, err := svc.ListProposals(ctx, agentHandle)
+			if err != nil {
+				return fmt.Errorf("failed to list proposals: %w", err)
+			}
+
+			if listOnly {
+				if jsonOutput {
+					output := make([]map[string]interface{}, len(proposals))
+					for i, p := range proposals {
+						output[i] = proposalToJSON(p)
+					}
+					return writeJSON(output)
+				}
+				printProposals(ctx, svc, proposals)
+				return nil
+			}
+
+			if len(proposals) == 0 {
+				fmt.Println("No memories waiting for review.")
+				return nil
+			}
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return fmt.Errorf("reviewing memories needs a terminal; use --list to print them")
+			}
+
+			// Accepted memories are embedded for search, as in automatic formation
+			embedder, err := createEmbedder()
+			if err != nil {
+				embedder = nil
+			}
+			if embedder != nil {
+				defer embedder.Close()
+			}
+			return reviewProposals(ctx, memory.NewService(queries, embedder), proposals)
+		},
+	}
+
+	cmd.Flags().StringVarP(&agentHandle, "agent", "a", "", "Only review proposals from this agent")
+	cmd.Flags().BoolVar(&listOnly, "list", false, "Print the queued proposals without reviewing them")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format (with --list)")
+
+	return cmd
+}
+
+// reviewProposals asks what to do with each proposal in turn and applies
+// the answer immediately, so quitting keeps the decisions made so far.
+func reviewProposals(ctx context.Context, svc *memory.Service, proposals []memory.Proposal) error {
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	var accepted, rejected int
+
+	for i, p := range proposals {
+		fmt.Println()
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Proposal %d of %d", i+1, len(proposals))))
+		printProposal(p, proposalTarget(ctx, svc, p))
+
+		var choice string
+		err := huh.NewSelect[string]().
+			Title("Remember this?").
+			Options(
+				huh.NewOption("Accept", reviewAccept),
+				huh.NewOption("Edit, then accept", reviewEdit),
+				huh.NewOption("Reject", reviewReject),
+				huh.NewOption("Skip for now", reviewSkip),
+				huh.NewOption("Quit", reviewQuit),
+			).
+			Value(&choice).
+			WithTheme(huh.ThemeCharm()).
+			Run()
+		if err != nil {
+			return err
+		}
+
+		switch choice {
+		case reviewEdit:
+			content := p.Content
+			err := huh.NewText().
+				Title("Edit memory").
+				Value(&content).
+				WithTheme(huh.ThemeCharm()).
+				Run()
+			if err != nil {
+				return err
+			}
+			content = strings.TrimSpace(content)
+			if content == "" {
+				fmt.Println("Empty memory, skipped")
+				continue
+			}
+			p = p.WithContent(content)
+			fallthrough
+		case reviewAccept:
+			mem, err := svc.AcceptProposal(ctx, p)
+			if err != nil {
+				return fmt.Errorf("failed to store memory: %w", err)
+			}
+			accepted++
+			fmt.Printf("Stored as %s: %s\n", mem.Category, mem.ID[:8])
+		case reviewReject:
+			if err := svc.RejectProposal(ctx, p.ID); err != nil {
+				return fmt.Errorf("failed to reject proposal: %w", err)
+			}
+			rejected++
+			fmt.Println("Rejected")
+		case reviewQuit:
+			fmt.Println()
+			fmt.Println(reviewSummary(accepted, rejected, len(proposals)))
+			return nil
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(reviewSummary(accepted, rejected, len(proposals)))
+	return nil
+}
+
+// reviewSummary counts the proposals accepted, rejected, and left queued.
+func reviewSummary(accepted, rejected, total int) string {
+	return fmt.Sprintf("%d accepted, %d rejected, %d left for later", accepted, rejected, total-accepted-rejected)
+}
+
+// proposalTarget returns the content of the active memory p would replace,
+// or "" if it replaces none.
+func proposalTarget(ctx context.Context, svc *memory.Service, p memory.Proposal) string {
+	if p.SupersedesID == "" {
+		return ""
+	}
+	target, err := svc.Get(ctx, p.SupersedesID)
+	if err != nil || target.Status != memory.StatusActive {
+		return ""
+	}
+	return target.Content
+}
+
+// printProposal prints a proposal's content, where it came from, and the
+// memory it would replace.
+func printProposal(p memory.Proposal, target string) {
+	mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	textStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+
+	fmt.Println(textStyle.Render(p.Content))
+	details := []string{string(p.Category)}
+	if p.AgentHandle != "" {
+		details = append(details, p.AgentHandle)
+	}
+	details = append(details, p.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Println(mutedStyle.Render(strings.Join(details, " · ")))
+	if target != "" {
+		line := fmt.Sprintf("Replaces %q", target)
+		if p.SupersessionReason != "" {
+			line += ": " + p.SupersessionReason
+		}
+		fmt.Println(mutedStyle.Render(line))
+	}
+}
+
+// printProposals lists queued proposals for --list.
+func printProposals(ctx context.Context, svc *memory.Service, proposals []memory.Proposal) {
+	if len(proposals) == 0 {
+		fmt.Println("No memories waiting for review.")
+		return
+	}
+	for i, p := range proposals {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s ", p.ID[:8])
+		printProposal(p, proposalTarget(ctx, svc, p))
+	}
+}
+
+// proposalToJSON converts a proposal to a JSON-friendly map.
+func proposalToJSON(p memory.Proposal) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":         p.ID,
+		"content":    p.Content,
+		"category":   string(p.Category),
+		"created_at": p.CreatedAt.Format(time.RFC3339),
+	}
+	if p.AgentHandle != "" {
+		result["agent_handle"] = p.AgentHandle
+	}
+	if p.SourceSessionID != "" {
+		result["source_session_id"] = p.SourceSessionID
+	}
+	if p.SupersedesID != "" {
+		result["supersedes_id"] = p.SupersedesID
+		result["supersession_reason"] = p.SupersessionReason
+	}
+	return result
+}
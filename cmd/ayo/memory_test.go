@@ -0,0 +1,29 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/memory"
+)
+
+func TestRelatedConflicts(t *testing.T) {
+	mem := memory.Memory{Category: memory.CategoryPreference, AgentHandle: "@ayo"}
+	tests := []struct {
+		name  string
+		other memory.Memory
+		want  []string
+	}{
+		{"same category and agent", memory.Memory{Category: memory.CategoryPreference, AgentHandle: "@ayo"}, []string{}},
+		{"different category", memory.Memory{Category: memory.CategoryFact, AgentHandle: "@ayo"}, []string{"category fact"}},
+		{"global memory", memory.Memory{Category: memory.CategoryPreference}, []string{"agent global"}},
+		{"both", memory.Memory{Category: memory.CategoryFact, AgentHandle: "@writer"}, []string{"category fact", "agent @writer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relatedConflicts(mem, tt.other); !slices.Equal(got, tt.want) {
+				t.Errorf("relatedConflicts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+func newMemoryWatchCmd() *cobra.Command {
+	var agentFilter string
+	var categoryFilter string
+	var interval time.Duration
+	var since time.Duration
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print memory changes as they happen",
+		Long: `Print memories as they are created, superseded, edited, forgotten, or
+proposed for review, including by chats in other terminals. This is a
+debugging aid for tuning formation triggers and deduplication.
+
+The database is polled every --interval, so changes appear within about a
+second. Each line shows the time, action, category, agent, and content. A
+superseded memory is shown with the ID of the memory replacing it, which
+is shown as created. Extractions skipped as duplicates of an existing
+memory aren't stored, so they don't appear.
+
+Press ctrl+c to stop.`,
+		Example: `  ayo memory watch
+  ayo memory watch --agent @ayo --since 10m
+  ayo memory watch --json | jq -r .content`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if interval <= 0 {
+				return fmt.Errorf("the --interval flag must be positive")
+			}
+
+			dbConn, queries, err := db.ConnectWithQueries(ctx, paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer dbConn.Close()
+
+			if !jsonOutput {
+				mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+				fmt.Fprintln(os.Stderr, mutedStyle.Render("Watching memories (ctrl+c to stop)"))
+			}
+
+			w := memory.NewService(queries, nil).Watch(time.Now().Add(-since))
+			return watchMemories(ctx, w, interval, func(e memory.Event) error {
+				if agentFilter != "" && !agent.MatchHandle(agentFilter, e.Memory.AgentHandle) {
+					return nil
+				}
+				if categoryFilter != "" && string(e.Memory.Category) != categoryFilter {
+					return nil
+				}
+				if jsonOutput {
+					return writeJSONLine(memoryEventToJSON(e))
+				}
+				printMemoryEvent(e)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "Only show memories of this agent handle or glob pattern (e.g. '@team.*')")
+	cmd.Flags().StringVarP(&categoryFilter, "category", "c", "", "Only show memories in this category")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "How often to check for changes")
+	cmd.Flags().DurationVar(&since, "since", 0, "Also show memories changed this long ago, by their latest change (e.g. 10m)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output one JSON object per line")
+
+	return cmd
+}
+
+// watchMemories polls w every interval and passes each change to emit,
+// until ctx is done. A poll that finds the database locked by a writer is
+// retried at the next interval.
+func watchMemories(ctx context.Context, w *memory.Watcher, interval time.Duration, emit func(memory.Event) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		events, err := w.Poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case db.IsLocked(err):
+		case err != nil:
+			return fmt.Errorf("failed to read memories: %w", err)
+		}
+		for _, e := range events {
+			if err := emit(e); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func printMemoryEvent(e memory.Event) {
+	timeStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	idStyle := lipgloss.NewStyle().Foreground(shared.ColorSubtle)
+	categoryStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	agentStyle := lipgloss.NewStyle().Foreground(shared.ColorInfo)
+	contentStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+
+	actionStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	switch e.Action {
+	case memory.EventCreated:
+		actionStyle = actionStyle.Foreground(shared.ColorSuccess)
+	case memory.EventUpdated, memory.EventSuperseded:
+		actionStyle = actionStyle.Foreground(shared.ColorPrimary)
+	case memory.EventProposed:
+		actionStyle = actionStyle.Foreground(shared.ColorTertiary)
+	}
+
+	handle := e.Memory.AgentHandle
+	if handle == "" {
+		handle = "global"
+	}
+	content := strings.Join(strings.Fields(e.Memory.Content), " ")
+	if len(content) > 60 {
+		content = content[:57] + "..."
+	}
+
+	line := fmt.Sprintf("%s  %s  %s  %s  %s  %s",
+		timeStyle.Render(e.Time.Format("15:04:05")),
+		actionStyle.Render(fmt.Sprintf("%-10s", e.Action)),
+		categoryStyle.Render(fmt.Sprintf("%-10s", e.Memory.Category)),
+		idStyle.Render(shortID(e.Memory.ID)),
+		agentStyle.Render(handle),
+		contentStyle.Render(content),
+	)
+	switch {
+	case e.Action == memory.EventSuperseded && e.Memory.SupersededByID != "":
+		line += timeStyle.Render("  -> " + shortID(e.Memory.SupersededByID))
+	case e.Action == memory.EventProposed && e.Memory.SupersedesID != "":
+		line += timeStyle.Render("  replaces " + shortID(e.Memory.SupersedesID))
+	}
+	fmt.Println(line)
+}
+
+func memoryEventToJSON(e memory.Event) map[string]interface{} {
+	result := map[string]interface{}{
+		"action":   string(e.Action),
+		"time":     e.Time.Format(time.RFC3339),
+		"id":       e.Memory.ID,
+		"category": string(e.Memory.Category),
+		"content":  e.Memory.Content,
+	}
+	if e.Memory.AgentHandle != "" {
+		result["agent_handle"] = e.Memory.AgentHandle
+	}
+	if e.Memory.SupersedesID != "" {
+		result["supersedes_id"] = e.Memory.SupersedesID
+	}
+	if e.Memory.SupersededByID != "" {
+		result["superseded_by_id"] = e.Memory.SupersededByID
+	}
+	if e.Memory.SupersessionReason != "" {
+		result["supersession_reason"] = e.Memory.SupersessionReason
+	}
+	return result
+}
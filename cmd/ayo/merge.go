@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+func newMergeCmd(cfgPath *string) *cobra.Command {
+	var into string
+	var input string
+	var continueOnError bool
+	var noCache bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <source>... --into <merger>",
+		Short: "Run agents in parallel and merge their outputs with another agent",
+		Long: `Run several source agents at once on the same input, then pass their
+outputs to a merge agent as one JSON object keyed by source handle without
+the @:
+
+  {"research": {...}, "critic": {...}}
+
+Outputs of sources with an output schema are validated and included as JSON;
+other outputs are included as strings. If the merge agent has an input
+schema, it must be an object with a property for each source, and the
+sources' output schemas are checked against those properties before anything
+runs.
+
+The input is read from --input, or from stdin when it is piped. Each source's
+result is reported on stderr, and the merge agent's response is written to
+stdout. If any source fails, the merge agent is not run unless
+--continue-on-error is set, in which case failed sources are left out of the
+object.`,
+		Example: `  # Merge research and critique into a report
+  ayo merge @research @critic --into @editor --input '{"topic": "vector databases"}'
+
+  # Read the input from stdin and merge whatever succeeds
+  cat brief.json | ayo merge @a @b @c --into @merger --continue-on-error`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if into == "" {
+				return fmt.Errorf("name the merge agent with --into")
+			}
+			if input == "" && !isTerminal(os.Stdin) {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("read stdin: %w", err)
+				}
+				input = strings.TrimSpace(string(data))
+			}
+			if input == "" {
+				return fmt.Errorf("no input: pass --input or pipe it on stdin")
+			}
+
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				merger, sources, err := loadMergeAgents(cfg, into, args)
+				if err != nil {
+					return err
+				}
+				if problems := agent.CheckMergeSources(&merger, sources); len(problems) > 0 {
+					return fmt.Errorf("the sources don't fit %s's input schema:\n  %s", merger.Handle, strings.Join(problems, "\n  "))
+				}
+
+				services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+				if err != nil {
+					warnNoPersistence("session persistence", err, false)
+					services = nil
+				} else {
+					defer services.Close()
+				}
+				runner, err := run.NewRunner(cfg, false, run.RunnerOptions{
+					Services:  services,
+					RawOutput: true,
+					NoCache:   noCache,
+				})
+				if err != nil {
+					return err
+				}
+				// Tool activity of concurrent runs would interleave, so only
+				// the per-source results are shown
+				runner.SetStreamWriter(run.NullWriter{})
+
+				successStyle := lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+				errorStyle := lipgloss.NewStyle().Foreground(shared.ColorError)
+				mutedStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+				report := func(res run.FanInResult) {
+					took := mutedStyle.Render(fmt.Sprintf("(%s)", res.Duration.Round(100*time.Millisecond)))
+					if res.Err != nil {
+						fmt.Fprintf(os.Stderr, "%s %s: %s %s\n", errorStyle.Render("failed"), res.Handle, mergeSourceError(res.Err), took)
+						return
+					}
+					fmt.Fprintf(os.Stderr, "%s %s %s\n", successStyle.Render("ok"), res.Handle, took)
+				}
+
+				pending := len(sources)
+				spinner := ui.NewSpinner(fmt.Sprintf("running %d sources...", pending))
+				spinner.Start()
+				results := runner.FanIn(cmd.Context(), sources, input, func(res run.FanInResult) {
+					spinner.Stop()
+					report(res)
+					if pending--; pending > 0 {
+						spinner = ui.NewSpinner(fmt.Sprintf("waiting for %d of %d sources...", pending, len(sources)))
+						spinner.Start()
+					}
+				})
+				spinner.Stop()
+
+				failed := 0
+				for _, res := range results {
+					if res.Err != nil {
+						failed++
+					}
+				}
+				if failed == len(results) {
+					return fmt.Errorf("all %d sources failed", failed)
+				}
+				if failed > 0 && !continueOnError {
+					return fmt.Errorf("%d of %d sources failed; use --continue-on-error to merge the rest", failed, len(results))
+				}
+
+				mergeInput, err := run.MergeInput(results)
+				if err != nil {
+					return err
+				}
+				if err := merger.ValidateInput(mergeInput); err != nil {
+					return fmt.Errorf("merged outputs don't match %s's input schema: %w", merger.Handle, mergeSourceError(err))
+				}
+
+				spinner = ui.NewSpinner(fmt.Sprintf("merging into %s...", merger.Handle))
+				spinner.Start()
+				resp, err := runner.Text(cmd.Context(), merger, mergeInput, nil)
+				if err == nil {
+					err = merger.ValidateOutput(resp)
+				}
+				if err != nil {
+					spinner.StopWithError(merger.Handle + " failed")
+					return err
+				}
+				spinner.Stop()
+
+				fmt.Println(resp)
+				printRedactionSummary(runner)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&into, "into", "", "Merge agent that receives the sources' outputs (required)")
+	cmd.Flags().StringVar(&input, "input", "", "Input for every source agent (default: stdin)")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Merge the sources that succeeded when others fail")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the response cache")
+
+	return cmd
+}
+
+// loadMergeAgents loads the merge agent and the source agents, which must
+// be distinct.
+func loadMergeAgents(cfg config.Config, into string, handles []string) (agent.Agent, []agent.Agent, error) {
+	merger, err := agent.Load(cfg, into)
+	if err != nil {
+		return agent.Agent{}, nil, err
+	}
+	printAgentWarnings(merger)
+
+	var sources []agent.Agent
+	var seen []string
+	for _, h := range handles {
+		src, err := agent.Load(cfg, h)
+		if err != nil {
+			return agent.Agent{}, nil, err
+		}
+		if slices.Contains(seen, src.Handle) {
+			return agent.Agent{}, nil, fmt.Errorf("source %s is listed more than once; each source's output needs its own key", src.Handle)
+		}
+		seen = append(seen, src.Handle)
+		printAgentWarnings(src)
+		sources = append(sources, src)
+	}
+	return merger, sources, nil
+}
+
+// mergeSourceError shortens input validation errors, which otherwise
+// explain the whole schema, to what was wrong.
+func mergeSourceError(err error) error {
+	var inputErr *agent.InputValidationError
+	if errors.As(err, &inputErr) && inputErr.ParseError != nil {
+		return fmt.Errorf("invalid input: %w", inputErr.ParseError)
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/ollama"
+	"github.com/alexcabrera/ayo/internal/ui"
+)
+
+// ollamaAvailable reports whether Ollama at the configured host can serve
+// embeddings and the small model. In offline mode only an Ollama on this
+// machine is used.
+func ollamaAvailable(ctx context.Context, cfg config.Config) bool {
+	if cfg.Offline && !config.IsLocalEndpoint(cfg.OllamaHost) {
+		return false
+	}
+	return ollama.NewClient(ollama.WithHost(cfg.OllamaHost)).IsAvailable(ctx)
+}
+
+// memoryStatusPrinter returns a memory queue callback that prints progress
+// to stderr. While Ollama at host is known to be unavailable, failures are
+// reported once instead of for every memory.
+func memoryStatusPrinter(host string) func(ui.AsyncStatusMsg) {
+	var warned atomic.Bool
+	return func(msg ui.AsyncStatusMsg) {
+		switch msg.Status {
+		case ui.AsyncStatusInProgress:
+			fmt.Fprintf(os.Stderr, "  ◇ %s\n", msg.Message)
+		case ui.AsyncStatusCompleted:
+			fmt.Fprintf(os.Stderr, "  ◆ %s\n", msg.Message)
+		case ui.AsyncStatusFailed:
+			if available, known := ollama.CachedAvailability(host); known && !available {
+				if !warned.Swap(true) {
+					fmt.Fprintf(os.Stderr, "  × Ollama unavailable at %s; memories are not being stored\n", host)
+				}
+				return
+			}
+			fmt.Fprintf(os.Stderr, "  × %s\n", msg.Message)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -14,34 +15,40 @@ import (
 
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/plugins"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
-// Plugin CLI color palette - adaptive for light/dark terminals
+// Shared styles, set by initPluginStyles once the theme is applied
 var (
-	pluginPurple = lipgloss.AdaptiveColor{Light: "#5A56E0", Dark: "#a78bfa"}
-	pluginCyan   = lipgloss.AdaptiveColor{Light: "#0891b2", Dark: "#67e8f9"}
-	pluginGreen  = lipgloss.AdaptiveColor{Light: "#059669", Dark: "#34d399"}
-	pluginYellow = lipgloss.AdaptiveColor{Light: "#d97706", Dark: "#fbbf24"}
-	pluginRed    = lipgloss.AdaptiveColor{Light: "#dc2626", Dark: "#f87171"}
-	pluginMuted  = lipgloss.AdaptiveColor{Light: "#6b7280", Dark: "#9ca3af"}
-	pluginText   = lipgloss.AdaptiveColor{Light: "#1f2937", Dark: "#f3f4f6"}
+	pluginTitleStyle   lipgloss.Style
+	pluginNameStyle    lipgloss.Style
+	pluginVersionStyle lipgloss.Style
+	pluginMutedStyle   lipgloss.Style
+	pluginSuccessStyle lipgloss.Style
+	pluginWarnStyle    lipgloss.Style
+	pluginErrorStyle   lipgloss.Style
+	pluginTextStyle    lipgloss.Style
+
+	pluginCheckmark string
+	pluginCross     string
+	pluginArrow     string
 )
 
-// Shared styles
-var (
-	pluginTitleStyle   = lipgloss.NewStyle().Bold(true).Foreground(pluginPurple)
-	pluginNameStyle    = lipgloss.NewStyle().Bold(true).Foreground(pluginCyan)
-	pluginVersionStyle = lipgloss.NewStyle().Foreground(pluginGreen)
-	pluginMutedStyle   = lipgloss.NewStyle().Foreground(pluginMuted)
-	pluginSuccessStyle = lipgloss.NewStyle().Foreground(pluginGreen)
-	pluginWarnStyle    = lipgloss.NewStyle().Foreground(pluginYellow)
-	pluginErrorStyle   = lipgloss.NewStyle().Foreground(pluginRed)
-	pluginTextStyle    = lipgloss.NewStyle().Foreground(pluginText)
+// initPluginStyles builds the plugin styles from the theme palette.
+func initPluginStyles() {
+	pluginTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	pluginNameStyle = lipgloss.NewStyle().Bold(true).Foreground(shared.ColorSecondary)
+	pluginVersionStyle = lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+	pluginMutedStyle = lipgloss.NewStyle().Foreground(shared.ColorTextDim)
+	pluginSuccessStyle = lipgloss.NewStyle().Foreground(shared.ColorSuccess)
+	pluginWarnStyle = lipgloss.NewStyle().Foreground(shared.ColorTertiary)
+	pluginErrorStyle = lipgloss.NewStyle().Foreground(shared.ColorError)
+	pluginTextStyle = lipgloss.NewStyle().Foreground(shared.ColorTextBright)
 
 	pluginCheckmark = pluginSuccessStyle.Render("✓")
-	pluginCross     = pluginErrorStyle.Render("✗")
-	pluginArrow     = pluginMutedStyle.Render("→")
-)
+	pluginCross = pluginErrorStyle.Render("✗")
+	pluginArrow = pluginMutedStyle.Render("→")
+}
 
 func newPluginsCmd(cfgPath *string) *cobra.Command {
 	cmd := &cobra.Command{
@@ -64,6 +71,7 @@ Storage: ~/.local/share/ayo/plugins/`,
 	cmd.AddCommand(showPluginCmd(cfgPath))
 	cmd.AddCommand(updatePluginCmd(cfgPath))
 	cmd.AddCommand(removePluginCmd(cfgPath))
+	cmd.AddCommand(doctorPluginCmd(cfgPath))
 
 	return cmd
 }
@@ -90,17 +98,17 @@ func listPluginsCmd(cfgPath *string) *cobra.Command {
 			// Build table
 			t := table.New().
 				Border(lipgloss.RoundedBorder()).
-				BorderStyle(lipgloss.NewStyle().Foreground(pluginMuted)).
+				BorderStyle(lipgloss.NewStyle().Foreground(shared.ColorTextDim)).
 				Headers("PLUGIN", "VERSION", "PROVIDES").
 				StyleFunc(func(row, col int) lipgloss.Style {
 					if row == table.HeaderRow {
 						return lipgloss.NewStyle().
-							Foreground(pluginPurple).
+							Foreground(shared.ColorPrimary).
 							Bold(true).
 							Padding(0, 1)
 					}
 					return lipgloss.NewStyle().
-						Foreground(pluginText).
+						Foreground(shared.ColorTextBright).
 						Padding(0, 1)
 				})
 
@@ -179,7 +187,7 @@ Examples:
 				spinnerErr := spinner.New().
 					Title(fmt.Sprintf("Installing %s...", pluginNameStyle.Render(name))).
 					Type(spinner.Dots).
-					Style(lipgloss.NewStyle().Foreground(pluginPurple)).
+					Style(lipgloss.NewStyle().Foreground(shared.ColorPrimary)).
 					ActionWithErr(func(ctx context.Context) error {
 						_ = gitURL // Used in message above
 						result, installErr = plugins.Install(args[0], opts)
@@ -346,7 +354,7 @@ If no name is provided, updates all plugins.`,
 			spinnerErr := spinner.New().
 				Title(title).
 				Type(spinner.Dots).
-				Style(lipgloss.NewStyle().Foreground(pluginPurple)).
+				Style(lipgloss.NewStyle().Foreground(shared.ColorPrimary)).
 				ActionWithErr(func(ctx context.Context) error {
 					if len(args) == 1 {
 						result, err := plugins.Update(args[0], opts)
@@ -491,6 +499,77 @@ func removePluginCmd(cfgPath *string) *cobra.Command {
 	return cmd
 }
 
+func doctorPluginCmd(cfgPath *string) *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check installed plugins for problems",
+		Long: `Check every installed plugin: its directory exists, its manifest parses,
+its agents, skills, and tools are present, and the binaries it depends on
+are installed. Each problem is reported with a suggested fix.
+
+--fix removes registry entries whose plugin directory is gone. Other
+problems are left for you to fix. Exits with status 1 if any problem
+remains.`,
+		Example: `  ayo plugins doctor
+  ayo plugins doctor --fix`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := plugins.LoadRegistry()
+			if err != nil {
+				return fmt.Errorf("load registry: %w", err)
+			}
+
+			installed := registry.List()
+			if len(installed) == 0 {
+				fmt.Println(pluginMutedStyle.Render("No plugins installed."))
+				return nil
+			}
+
+			remaining := 0
+			for _, p := range installed {
+				problems := plugins.Diagnose(p)
+				if fix && len(problems) == 1 && problems[0].Dangling() {
+					if err := registry.Remove(p.Name); err != nil {
+						return fmt.Errorf("remove %s: %w", p.Name, err)
+					}
+					fmt.Printf("%s %s %s\n",
+						pluginCheckmark,
+						pluginNameStyle.Render(p.Name),
+						pluginMutedStyle.Render("removed dangling registry entry"),
+					)
+					continue
+				}
+
+				if len(problems) == 0 {
+					fmt.Printf("%s %s %s\n", pluginCheckmark, pluginNameStyle.Render(p.Name), pluginVersionStyle.Render("v"+p.Version))
+					continue
+				}
+
+				remaining += len(problems)
+				fmt.Printf("%s %s %s\n", pluginCross, pluginNameStyle.Render(p.Name), pluginVersionStyle.Render("v"+p.Version))
+				for _, problem := range problems {
+					fmt.Printf("    %s\n", pluginTextStyle.Render(problem.Detail))
+					fmt.Printf("    %s %s\n", pluginArrow, pluginMutedStyle.Render(problem.Fix))
+				}
+			}
+
+			fmt.Println()
+			if remaining > 0 {
+				fmt.Println(pluginErrorStyle.Render(fmt.Sprintf("%d problem(s) found", remaining)))
+				os.Exit(1)
+			}
+			fmt.Println(pluginSuccessStyle.Render("All plugins OK"))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Remove registry entries whose plugin directory is missing")
+
+	return cmd
+}
+
 // handleDelegateSetup prompts the user to configure delegates declared by a plugin.
 // If autoYes is true, it automatically accepts all prompts.
 func handleDelegateSetup(delegates map[string]string, autoYes bool) error {
@@ -739,7 +818,7 @@ func runInstallCommand(name, command string) error {
 	spinErr := spinner.New().
 		Title(fmt.Sprintf("Installing %s...", name)).
 		Type(spinner.Dots).
-		Style(lipgloss.NewStyle().Foreground(pluginPurple)).
+		Style(lipgloss.NewStyle().Foreground(shared.ColorPrimary)).
 		ActionWithErr(func(ctx context.Context) error {
 			cmd := exec.CommandContext(ctx, execPath, parts[1:]...)
 			cmd.Stdout = nil
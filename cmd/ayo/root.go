@@ -4,19 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/builtin"
+	"github.com/alexcabrera/ayo/internal/bundle"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/embedding"
 	"github.com/alexcabrera/ayo/internal/memory"
-	"github.com/alexcabrera/ayo/internal/ollama"
 	"github.com/alexcabrera/ayo/internal/paths"
 	"github.com/alexcabrera/ayo/internal/pipe"
 	"github.com/alexcabrera/ayo/internal/run"
@@ -28,8 +30,22 @@ import (
 func newRootCmd() *cobra.Command {
 	var cfgPath string
 	var attachments []string
+	var attachDirs []string
 	var debug bool
+	var verbose bool
 	var modelOverride string
+	var continueLast bool
+	var noMemory bool
+	var noSkills bool
+	var noCache bool
+	var seed int64
+	var showStats bool
+	var capturePath string
+	var offline bool
+	var promptFile string
+	var systemOverride string
+	var noPager bool
+	var compactJSON bool
 
 	cmd := &cobra.Command{
 		Use:           "ayo [@agent] [prompt]",
@@ -43,7 +59,12 @@ Examples:
   ayo "tell me a joke"          Run single prompt with @ayo
   ayo @myagent                  Start interactive chat with @myagent
   ayo @myagent "do something"   Run single prompt with @myagent
-  ayo -a file.txt "analyze"     Attach file to prompt`,
+  ayo -a file.txt "analyze"     Attach file to prompt
+  ayo --attach-dir src "review" Attach the files in a directory
+  ayo --prompt-file task.md     Read the prompt from a file ("-" for stdin)
+  ayo --system-override try.md  Try a different system prompt for one run
+  ayo --continue "and then?"    Continue the most recent @ayo session
+  ayo @myagent -c               Resume @myagent's latest session interactively`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Args:          cobra.ArbitraryArgs,
@@ -56,16 +77,72 @@ Examples:
 				}
 			}
 
+			// Fetch the AYO_BUNDLE bundle before agents or config are looked up
+			if ref := strings.TrimSpace(os.Getenv(bundle.EnvVar)); ref != "" {
+				b, err := bundle.Fetch(cmd.Context(), ref, paths.BundlesDir())
+				if err != nil {
+					return fmt.Errorf("load %s: %w", bundle.EnvVar, err)
+				}
+				if !b.Pinned {
+					fmt.Fprintf(os.Stderr, "warning: %s=%s isn't pinned to a digest, so its contents can change between runs; pin it with #sha256=<hex> or @sha256:<hex>\n", bundle.EnvVar, ref)
+				}
+				paths.SetBundleDir(b.Dir)
+				// The bundle's config applies only when the user has none
+				if cfg := b.ConfigFile(); cfg != "" && !cmd.Flags().Changed("config") && !fileExists(cfgPath) {
+					cfgPath = cfg
+				}
+				if debug {
+					fmt.Fprintf(os.Stderr, "Using bundle %s (%s) from %s\n", ref, b.Digest, b.Dir)
+				}
+			}
+
+			// Apply the theme before anything is rendered
+			if cfg, err := loadConfig(cfgPath); err == nil {
+				if err := ui.ApplyTheme(cfg.UI.Theme); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				}
+				ui.SetPager(cfg.UI.Pager)
+				if cfg.UI.JSONIndent != nil {
+					setJSONIndent(*cfg.UI.JSONIndent)
+				}
+			}
+			if noPager {
+				ui.SetPager(ui.PagerNever)
+			}
+			if compactJSON {
+				setJSONIndent(0)
+			}
+			initPluginStyles()
+
 			// Auto-install built-in agents and skills if needed (version-based)
 			return builtin.Install()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return withConfig(&cfgPath, func(cfg config.Config) error {
-				if len(args) == 0 {
+				if len(args) == 0 && !continueLast && promptFile == "" {
 					// No args: show help
 					return cmd.Help()
 				}
 
+				if continueLast && len(attachments) > 0 {
+					return errors.New("--attachment cannot be used with --continue")
+				}
+				if continueLast && len(attachDirs) > 0 {
+					return errors.New("--attach-dir cannot be used with --continue")
+				}
+
+				// Check attached directories up front and say what is left out
+				for _, dir := range attachDirs {
+					contents, err := run.CollectDir(dir)
+					if err != nil {
+						return fmt.Errorf("attach directory: %w", err)
+					}
+					for _, w := range contents.Warnings(dir) {
+						fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+					}
+					attachments = append(attachments, dir)
+				}
+
 				// Check for first-run (no providers configured)
 				if !config.HasAnyProvider() {
 					warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
@@ -77,12 +154,20 @@ Examples:
 				if modelOverride != "" {
 					cfg.DefaultModel = modelOverride
 				}
+				if capturePath != "" {
+					cfg.Capture.Path = capturePath
+				}
+				if offline {
+					cfg.Offline = true
+					// ayo processes the agent starts, such as through bash, stay offline too
+					os.Setenv(config.OfflineEnvVar, "1")
+				}
 
 				// Determine agent handle and remaining args
 				var handle string
 				var promptArgs []string
 
-				if strings.HasPrefix(args[0], "@") {
+				if len(args) > 0 && strings.HasPrefix(args[0], "@") {
 					// First arg is an agent handle
 					handle = agent.NormalizeHandle(args[0])
 					promptArgs = args[1:]
@@ -92,18 +177,49 @@ Examples:
 					promptArgs = args
 				}
 
+				// Read the prompt from --prompt-file instead of the arguments.
+				// Piped stdin is still read as context unless it is the file.
+				if promptFile != "" {
+					if len(promptArgs) > 0 {
+						return errors.New("cannot use a prompt argument with --prompt-file")
+					}
+					text, err := readPromptFile(promptFile)
+					if err != nil {
+						return err
+					}
+					promptArgs = []string{text}
+				}
+				stdinPiped := pipe.IsStdinPiped() && promptFile != "-"
+
 				ag, err := agent.Load(cfg, handle)
 				if err != nil {
 					return err
 				}
+				// Per-run isolation flags take precedence over the agent's config
+				if noMemory {
+					ag = ag.WithoutMemory()
+				}
+				if noSkills {
+					ag = ag.WithoutSkills()
+				}
+				if systemOverride != "" {
+					system, err := readSystemOverride(systemOverride)
+					if err != nil {
+						return err
+					}
+					ag = ag.WithSystem(system)
+					if isTerminal(os.Stderr) {
+						notice := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+						fmt.Fprintln(os.Stderr, notice.Render(fmt.Sprintf("System prompt for %s overridden by %s", ag.Handle, systemOverride)))
+					}
+				}
+				printAgentWarnings(ag)
 
 				// Initialize session services
 				services, err := session.Connect(cmd.Context(), paths.DatabasePath())
 				if err != nil {
-					// Log warning but continue without persistence
-					if debug {
-						fmt.Fprintf(os.Stderr, "Warning: session persistence unavailable: %v\n", err)
-					}
+					// Continue without persistence
+					warnNoPersistence("session persistence", err, debug)
 					services = nil
 				}
 				if services != nil {
@@ -118,8 +234,7 @@ Examples:
 				if services != nil {
 					// Create Ollama-based embedder and small model service
 					var embedder embedding.Embedder
-					ollamaClient := ollama.NewClient(ollama.WithHost(cfg.OllamaHost))
-					if ollamaClient.IsAvailable(cmd.Context()) {
+					if ollamaAvailable(cmd.Context(), cfg) {
 						embedder = embedding.NewOllamaEmbedder(embedding.OllamaConfig{
 							Host:  cfg.OllamaHost,
 							Model: cfg.Embedding.Model,
@@ -140,17 +255,8 @@ Examples:
 					// Create async memory queue
 					memQueue = memory.NewQueue(memSvc, memory.QueueConfig{
 						BufferSize: 100,
-						OnStatus: func(msg ui.AsyncStatusMsg) {
-							// For now, just print to stderr - will be wired to TUI later
-							switch msg.Status {
-							case ui.AsyncStatusInProgress:
-								fmt.Fprintf(os.Stderr, "  ◇ %s\n", msg.Message)
-							case ui.AsyncStatusCompleted:
-								fmt.Fprintf(os.Stderr, "  ◆ %s\n", msg.Message)
-							case ui.AsyncStatusFailed:
-								fmt.Fprintf(os.Stderr, "  × %s\n", msg.Message)
-							}
-						},
+						// For now, just print to stderr - will be wired to TUI later
+						OnStatus: memoryStatusPrinter(cfg.OllamaHost),
 					})
 					memQueue.Start()
 					defer memQueue.Stop(5 * time.Second)
@@ -180,16 +286,37 @@ Examples:
 					FormationService: formSvc,
 					SmallModel:       smallModelSvc,
 					MemoryQueue:      memQueue,
+					Verbose:          verbose,
+					NoCache:          noCache,
+					Seed:             seedOption(cmd, seed, cfg.Provider),
 				})
 				if err != nil {
 					return err
 				}
 
+				// Resume the agent's most recent session if requested
+				var resumed []session.Message
+				var resuming bool
+				if continueLast {
+					wd, _ := os.Getwd()
+					resumed, resuming, err = resumeLatestSession(cmd.Context(), services, runner, ag, wd)
+					if err != nil {
+						return err
+					}
+					if !resuming && debug {
+						fmt.Fprintf(os.Stderr, "DEBUG: no previous session for %s in this directory, starting a new one\n", ag.Handle)
+					}
+				}
+
 				// Non-interactive mode: prompt provided as positional args or stdin
-				if len(promptArgs) > 0 || pipe.IsStdinPiped() {
+				if len(promptArgs) > 0 || stdinPiped {
+					// The interactive TUI reads ctrl+c as a key, so only
+					// prompts are interrupted by signals
+					defer drainOnInterrupt(runner)()
+
 					var prompt string
 
-					if pipe.IsStdinPiped() {
+					if stdinPiped {
 						// Read from stdin
 						stdinData, err := pipe.ReadStdin()
 						if err != nil {
@@ -225,35 +352,77 @@ Examples:
 					ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
 					defer cancel()
 
-					result, err := runner.TextWithSession(ctx, ag, prompt, attachments)
-					if err != nil {
-						return err
+					var result run.TextResult
+					if resuming {
+						resp, err := runner.Chat(ctx, ag, prompt)
+						if err != nil {
+							return err
+						}
+						result = run.TextResult{Response: resp, SessionID: runner.GetSessionID(ag.Handle)}
+					} else {
+						result, err = runner.TextWithSession(ctx, ag, prompt, attachments)
+						if err != nil {
+							return err
+						}
 					}
 
 					// Wait for any pending memory formations to complete
 					runner.WaitForFormations(2 * time.Second)
 
-					// Output to stdout (for piping)
-					fmt.Println(result.Response)
+					// Output to stdout (for piping), paged when taller than the terminal
+					ui.Page(result.Response)
 
 					// Print session ID to stderr (visible even when piped)
 					if result.SessionID != "" && !pipe.IsStdoutPiped() {
 						sessionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 						fmt.Fprintln(os.Stderr, sessionStyle.Render(fmt.Sprintf("\nSession: %s", result.SessionID)))
 					}
+					printRedactionSummary(runner)
+					if showStats {
+						printRunStats(runner)
+					}
+					runner.Shutdown(true, drainTimeout)
 					return nil
 				}
 
 				// Interactive mode
-				return runInteractiveChat(cmd.Context(), runner, ag, debug)
+				if len(resumed) > 0 {
+					preview := ui.RenderHistoryPreview(resumed, ag.Handle, 3)
+					if preview != "" {
+						fmt.Println()
+						fmt.Println(preview)
+						fmt.Println()
+					}
+				}
+				err = runInteractiveChat(cmd.Context(), cfg, runner, ag, debug, showStats)
+				runner.Shutdown(true, drainTimeout)
+				printRedactionSummary(runner)
+				if showStats {
+					printRunStats(runner)
+				}
+				return err
 			})
 		},
 	}
 
 	cmd.PersistentFlags().StringVar(&cfgPath, "config", defaultConfigPath(), "path to config file")
+	cmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "don't show long output in a pager (overrides ui.pager)")
+	cmd.PersistentFlags().BoolVar(&compactJSON, "compact", false, "write --json output on one line (overrides ui.json_indent)")
 	cmd.Flags().StringSliceVarP(&attachments, "attachment", "a", nil, "file attachments")
+	cmd.Flags().StringArrayVar(&attachDirs, "attach-dir", nil, "attach the files in a directory, respecting .ayoignore (repeatable)")
+	cmd.Flags().StringVar(&promptFile, "prompt-file", "", `read the prompt from a file ("-" for stdin)`)
 	cmd.Flags().BoolVar(&debug, "debug", false, "show debug output including raw tool payloads")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "show full tool input and output without truncation")
 	cmd.Flags().StringVarP(&modelOverride, "model", "m", "", "model to use (overrides config default)")
+	cmd.Flags().BoolVarP(&continueLast, "continue", "c", false, "continue the agent's most recent session in this directory")
+	cmd.Flags().BoolVar(&noMemory, "no-memory", false, "run without memory retrieval or formation")
+	cmd.Flags().BoolVar(&noSkills, "no-skills", false, "run without the agent's skills")
+	cmd.Flags().StringVar(&systemOverride, "system-override", "", "replace the agent's system prompt with a file's contents for this run")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "bypass the response cache")
+	cmd.Flags().BoolVar(&showStats, "stats", false, "print tool calls and where the time went after the run")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "sampling seed for reproducible runs, where the provider supports it")
+	cmd.Flags().StringVar(&capturePath, "capture", "", "append each turn to a JSONL eval dataset (overrides capture.path)")
+	cmd.Flags().BoolVar(&offline, "offline", false, "only call local model providers such as Ollama (also AYO_OFFLINE=1)")
 
 	// Subcommands
 	cmd.AddCommand(newSetupCmd(&cfgPath))
@@ -261,10 +430,15 @@ Examples:
 	cmd.AddCommand(newSkillsCmd(&cfgPath))
 	cmd.AddCommand(newFlowsCmd(&cfgPath))
 	cmd.AddCommand(newChainCmd(&cfgPath))
+	cmd.AddCommand(newMergeCmd(&cfgPath))
 	cmd.AddCommand(newSessionsCmd(&cfgPath))
+	cmd.AddCommand(newUsageCmd())
 	cmd.AddCommand(newMemoryCmd())
+	cmd.AddCommand(newConfigCmd(&cfgPath))
 	cmd.AddCommand(newDoctorCmd(&cfgPath))
+	cmd.AddCommand(newExportCmd(&cfgPath))
 	cmd.AddCommand(newPluginsCmd(&cfgPath))
+	cmd.AddCommand(newServeCmd(&cfgPath))
 
 	return cmd
 }
@@ -277,6 +451,18 @@ func loadConfig(cfgPath string) (config.Config, error) {
 	return config.Load(cfgPath)
 }
 
+// seedOption returns the --seed value if it was given, noting when the
+// provider ignores seeds.
+func seedOption(cmd *cobra.Command, seed int64, p catwalk.Provider) *int64 {
+	if !cmd.Flags().Changed("seed") {
+		return nil
+	}
+	if !run.SeedSupported(p) {
+		fmt.Fprintf(os.Stderr, "Note: the %s provider does not support --seed; it is ignored\n", p.Name)
+	}
+	return &seed
+}
+
 func withConfig(cfgPath *string, fn func(config.Config) error) error {
 	cfg, err := loadConfig(*cfgPath)
 	if err != nil {
@@ -285,6 +471,21 @@ func withConfig(cfgPath *string, fn func(config.Config) error) error {
 	return fn(cfg)
 }
 
+// printRedactionSummary reports on stderr how many secrets were masked
+// before messages were sent to the provider.
+func printRedactionSummary(runner *run.Runner) {
+	n := runner.Redactions()
+	if n == 0 {
+		return
+	}
+	noun := "secrets"
+	if n == 1 {
+		noun = "secret"
+	}
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	fmt.Fprintln(os.Stderr, style.Render(fmt.Sprintf("Redacted %d %s before sending to the provider", n, noun)))
+}
+
 // printInputValidationError prints a formatted input validation error to stderr.
 // Returns a simple error to signal failure without duplicating the message.
 func printInputValidationError(err error) error {
@@ -315,6 +516,43 @@ func printInputValidationError(err error) error {
 	return err
 }
 
+// printAgentWarnings reports problems found while loading an agent,
+// such as missing context files, without stopping the run.
+func printAgentWarnings(ag agent.Agent) {
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	for _, w := range ag.ContextWarnings {
+		fmt.Fprintln(os.Stderr, warnStyle.Render(fmt.Sprintf("Warning: %s: %s", ag.Handle, w)))
+	}
+}
+
+// resumeLatestSession loads the agent's most recent session created in dir
+// into the runner. It reports false when persistence is unavailable or the
+// agent has no previous session in dir, in which case the caller starts a
+// new one.
+func resumeLatestSession(ctx context.Context, services *session.Services, runner *run.Runner, ag agent.Agent, dir string) ([]session.Message, bool, error) {
+	if services == nil {
+		return nil, false, nil
+	}
+
+	sessions, err := services.Sessions.ListByAgentInDir(ctx, ag.Handle, dir, 1)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, false, nil
+	}
+	sess := sessions[0]
+
+	messages, err := services.Messages.List(ctx, sess.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load messages: %w", err)
+	}
+	if err := runner.ResumeSession(ctx, ag, sess.ID, messages); err != nil {
+		return nil, false, fmt.Errorf("failed to resume session: %w", err)
+	}
+	return messages, true, nil
+}
+
 // formatInputValidationError creates a detailed error message for input validation failures.
 // buildFreeformPreamble creates a preamble for agents without input schemas
 // when receiving piped input from another agent.
@@ -342,3 +580,40 @@ func buildFreeformPreamble(jsonInput string) string {
 
 	return preamble.String()
 }
+
+// readPromptFile reads the prompt for --prompt-file, from stdin when path
+// is "-". An empty prompt is an error.
+func readPromptFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read prompt file: %w", err)
+	}
+	prompt := strings.TrimSpace(string(data))
+	if prompt == "" {
+		if path == "-" {
+			return "", errors.New("prompt from stdin is empty")
+		}
+		return "", fmt.Errorf("prompt file %s is empty", path)
+	}
+	return prompt, nil
+}
+
+// readSystemOverride reads the system prompt for --system-override.
+// An empty file is an error rather than a silent prompt-less run.
+func readSystemOverride(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read system override: %w", err)
+	}
+	system := strings.TrimSpace(string(data))
+	if system == "" {
+		return "", fmt.Errorf("system override %s is empty", path)
+	}
+	return system, nil
+}
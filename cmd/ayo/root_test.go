@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func TestReadPromptFile(t *testing.T) {
+	dir := t.TempDir()
+	task := filepath.Join(dir, "task.md")
+	if err := os.WriteFile(task, []byte("\nSummarize the \"release notes\" below.\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readPromptFile(task)
+	if err != nil {
+		t.Fatalf("readPromptFile: %v", err)
+	}
+	if got != `Summarize the "release notes" below.` {
+		t.Errorf("prompt = %q", got)
+	}
+
+	empty := filepath.Join(dir, "empty.md")
+	if err := os.WriteFile(empty, []byte(" \n\t\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readPromptFile(empty); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("empty file error = %v", err)
+	}
+	if _, err := readPromptFile(filepath.Join(dir, "missing.md")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestResumeLatestSessionInDirectory(t *testing.T) {
+	ctx := context.Background()
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	// Sessions of the same agent started in two directories
+	create := func(dir, text string) session.Session {
+		sess, err := services.Sessions.Create(ctx, session.CreateParams{AgentHandle: "@ayo", WorkingDir: dir})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if _, err := services.Messages.Create(ctx, session.CreateMessageParams{
+			SessionID: sess.ID,
+			Role:      session.RoleUser,
+			Parts:     []session.ContentPart{session.TextContent{Text: text}},
+		}); err != nil {
+			t.Fatalf("Create message: %v", err)
+		}
+		return sess
+	}
+	api := create("/work/api", "api question")
+	create("/work/web", "web question")
+
+	runner, err := run.NewRunner(config.Config{}, false, run.RunnerOptions{Services: services})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	ag := agent.Agent{Handle: "@ayo", Model: "test"}
+
+	messages, resuming, err := resumeLatestSession(ctx, services, runner, ag, "/work/api")
+	if err != nil || !resuming {
+		t.Fatalf("resumeLatestSession = %v, %v; want the /work/api session", resuming, err)
+	}
+	if got := runner.GetSessionID("@ayo"); got != api.ID {
+		t.Errorf("resumed session %s, want %s", got, api.ID)
+	}
+	if len(messages) != 1 || messages[0].TextContent() != "api question" {
+		t.Errorf("messages = %+v, want the /work/api history", messages)
+	}
+
+	// Without a session in the directory a new one is started
+	_, resuming, err = resumeLatestSession(ctx, services, runner, ag, "/work/docs")
+	if err != nil || resuming {
+		t.Errorf("resumeLatestSession in an empty directory = %v, %v; want no session", resuming, err)
+	}
+}
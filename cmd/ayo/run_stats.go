@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// printRunStats prints where the time of the run went, for --stats. It goes
+// to stderr so piped output stays clean.
+func printRunStats(runner *run.Runner) {
+	style := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, style.Render(formatRunStats(runner.Stats())))
+}
+
+// formatRunStats renders stats as a table: totals, then calls and time per
+// tool and per sub-agent, most time first.
+func formatRunStats(s run.RunStats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run stats\n")
+	fmt.Fprintf(&b, "  %-12s %s (model %s, tools %s)\n", "Time", formatDuration(s.Duration), formatDuration(s.ModelTime), formatDuration(s.ToolTime))
+	fmt.Fprintf(&b, "  %-12s %d\n", "Turns", s.Turns)
+	fmt.Fprintf(&b, "  %-12s %d\n", "Iterations", s.Steps)
+	fmt.Fprintf(&b, "  %-12s %d\n", "Tool calls", s.ToolCalls)
+	if s.InvalidToolCalls > 0 {
+		fmt.Fprintf(&b, "  %-12s %d\n", "Invalid args", s.InvalidToolCalls)
+	}
+
+	width := 0
+	for _, t := range s.Tools {
+		width = max(width, len(t.Name))
+	}
+	for _, a := range s.SubAgents {
+		width = max(width, len(a.Handle))
+	}
+	row := func(name string, calls int, d time.Duration) {
+		share := ""
+		if s.Duration > 0 {
+			share = fmt.Sprintf("%3.0f%%", 100*d.Seconds()/s.Duration.Seconds())
+		}
+		fmt.Fprintf(&b, "    %-*s %4dx %8s %s\n", width, name, calls, formatDuration(d), share)
+	}
+
+	if len(s.Tools) > 0 {
+		fmt.Fprintf(&b, "  Tools\n")
+		for _, t := range s.Tools {
+			row(t.Name, t.Calls, t.Duration)
+		}
+	}
+	if len(s.SubAgents) > 0 {
+		fmt.Fprintf(&b, "  Sub-agents\n")
+		for _, a := range s.SubAgents {
+			row(a.Handle, a.Calls, a.Duration)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
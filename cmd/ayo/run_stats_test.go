@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+func TestFormatRunStats(t *testing.T) {
+	got := formatRunStats(run.RunStats{
+		Turns:            1,
+		Steps:            3,
+		Duration:         10 * time.Second,
+		ModelTime:        time.Second,
+		ToolTime:         9 * time.Second,
+		ToolCalls:        3,
+		InvalidToolCalls: 1,
+		Tools: []run.ToolStats{
+			{Name: "bash", Calls: 2, Duration: 9 * time.Second},
+			{Name: "agent_call", Calls: 1, Duration: 0},
+		},
+		SubAgents: []run.SubAgentStats{{Handle: "@research", Calls: 1, Duration: 0}},
+	})
+
+	for _, want := range []string{
+		"Time         10.0s (model 1.0s, tools 9.0s)",
+		"Iterations   3",
+		"Invalid args 1",
+		"    bash          2x     9.0s  90%",
+		"  Sub-agents\n    @research     1x      0ms   0%",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatRunStats missing %q in:\n%s", want, got)
+		}
+	}
+}
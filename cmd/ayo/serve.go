@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/builtin"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/embedding"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/server"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+func newServeCmd(cfgPath *string) *cobra.Command {
+	var addr string
+	var timeout time.Duration
+	var debug bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve agents and memories over a local HTTP API",
+		Long: `Start an HTTP server that lets other applications list agents, run them,
+and list or search memories.
+
+Endpoints:
+  GET  /agents                 List agents
+  POST /agents/{handle}/run    Run an agent once with {"prompt", "attachments"}
+  GET  /memories               List memories (?agent=, ?limit=)
+  GET  /memories/search?q=     Search memories (?agent=, ?limit=)
+
+A run returns {"response", "session_id"}. With "Accept: text/event-stream",
+its output is streamed as server-sent events instead.
+
+Every request must carry "Authorization: Bearer <token>". Set the token
+with serve.token or AYO_SERVE_TOKEN; otherwise a random one is generated
+and printed at startup. Run requests must be sent with
+"Content-Type: application/json", and requests with an Origin header are
+refused, so web pages can't call the server.
+
+The server is local-first: it listens on 127.0.0.1:7878 by default,
+accepts only localhost and loopback Host headers there, and refuses other
+addresses unless a token is set. Tools run in the directory the server
+was started in, attachments must be inside it, and bash commands that
+need approval are denied.`,
+		Example: `  AYO_SERVE_TOKEN=secret ayo serve
+  AYO_SERVE_TOKEN=secret ayo serve --addr 0.0.0.0:7878
+  curl -s localhost:7878/agents/ayo/run -H 'Authorization: Bearer secret' \
+    -H 'Content-Type: application/json' -d '{"prompt": "hello"}'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				token := cfg.Serve.Token
+				if env := os.Getenv("AYO_SERVE_TOKEN"); env != "" {
+					token = env
+				}
+				if addr == "" {
+					addr = cfg.Serve.Addr
+				}
+				if addr == "" {
+					addr = server.DefaultAddr
+				}
+				if !cmd.Flags().Changed("timeout") && cfg.Serve.TimeoutSeconds > 0 {
+					timeout = time.Duration(cfg.Serve.TimeoutSeconds) * time.Second
+				}
+				if err := server.CheckAddr(addr, token); err != nil {
+					return err
+				}
+				generated := token == ""
+				if generated {
+					var err error
+					if token, err = generateToken(); err != nil {
+						return err
+					}
+				}
+
+				if err := builtin.Install(); err != nil {
+					return fmt.Errorf("install builtins: %w", err)
+				}
+
+				ctx := cmd.Context()
+				services, err := session.Connect(ctx, paths.DatabasePath())
+				if err != nil {
+					warnNoPersistence("session persistence", err, debug)
+					services = nil
+				}
+				if services != nil {
+					defer services.Close()
+				}
+
+				var memSvc *memory.Service
+				var formSvc *memory.FormationService
+				var smallModelSvc *smallmodel.Service
+				if services != nil {
+					var embedder embedding.Embedder
+					if ollamaAvailable(ctx, cfg) {
+						embedder = embedding.NewOllamaEmbedder(embedding.OllamaConfig{
+							Host:  cfg.OllamaHost,
+							Model: cfg.Embedding.Model,
+						})
+						defer embedder.Close()
+						smallModelSvc = smallmodel.NewService(smallmodel.Config{
+							Host:  cfg.OllamaHost,
+							Model: cfg.SmallModel,
+						})
+					} else if debug {
+						fmt.Fprintf(os.Stderr, "Warning: Ollama not available at %s, memory features disabled\n", cfg.OllamaHost)
+					}
+					memSvc = memory.NewService(services.Queries(), embedder)
+					formSvc = memory.NewFormationService(memSvc)
+				}
+
+				srv := server.New(server.Options{
+					Config:  cfg,
+					Token:   token,
+					Timeout: timeout,
+					Memory:  memSvc,
+					AnyHost: !server.IsLoopback(addr),
+					NewRunner: func(w run.StreamWriter) (*run.Runner, error) {
+						return run.NewRunner(cfg, debug, run.RunnerOptions{
+							Services:         services,
+							MemoryService:    memSvc,
+							FormationService: formSvc,
+							SmallModel:       smallModelSvc,
+							StreamWriter:     w,
+							RawOutput:        true,
+							ApproveCommand:   denyCommand,
+						})
+					},
+				})
+
+				listener, err := net.Listen("tcp", addr)
+				if err != nil {
+					return err
+				}
+				httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+				// An interrupt stops accepting requests and lets runs in
+				// progress and their background work finish
+				defer registerDrain(func(timeout time.Duration) {
+					shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+					defer cancel()
+					httpServer.Shutdown(shutdownCtx)
+					srv.Wait(time.Until(deadlineOf(shutdownCtx)))
+				})()
+
+				muted := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+				fmt.Fprintf(os.Stderr, "Serving on http://%s\n", listener.Addr())
+				if generated {
+					fmt.Fprintf(os.Stderr, "Token: %s\n", token)
+					fmt.Fprintln(os.Stderr, muted.Render("Send \"Authorization: Bearer <token>\" with each request; set serve.token or AYO_SERVE_TOKEN to choose the token"))
+				}
+
+				if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "", "address to listen on (default serve.addr or 127.0.0.1:7878)")
+	cmd.Flags().DurationVar(&timeout, "timeout", server.DefaultTimeout, "time limit for each agent run (overrides serve.timeout_seconds)")
+	cmd.Flags().BoolVar(&debug, "debug", false, "show debug output")
+
+	return cmd
+}
+
+// generateToken returns a random token for a server started without one.
+func generateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// denyCommand denies bash commands that need approval, since the server
+// has no one to ask.
+func denyCommand(ctx context.Context, command, description string) (bool, error) {
+	return false, nil
+}
+
+// deadlineOf returns ctx's deadline, or now if it has none.
+func deadlineOf(ctx context.Context) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now()
+}
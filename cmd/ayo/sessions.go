@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 	"time"
@@ -14,7 +16,6 @@ import (
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/embedding"
 	"github.com/alexcabrera/ayo/internal/memory"
-	"github.com/alexcabrera/ayo/internal/ollama"
 	"github.com/alexcabrera/ayo/internal/paths"
 	"github.com/alexcabrera/ayo/internal/run"
 	"github.com/alexcabrera/ayo/internal/session"
@@ -36,9 +37,13 @@ Storage: ~/.local/share/ayo/ayo.db`,
 	}
 
 	cmd.AddCommand(newSessionsListCmd())
-	cmd.AddCommand(newSessionsShowCmd())
+	cmd.AddCommand(newSessionsShowCmd(cfgPath))
 	cmd.AddCommand(newSessionsDeleteCmd())
 	cmd.AddCommand(newSessionsContinueCmd(cfgPath))
+	cmd.AddCommand(newSessionsBranchCmd(cfgPath))
+	cmd.AddCommand(newSessionsSummarizeCmd(cfgPath))
+	cmd.AddCommand(newSessionsTagCmd())
+	cmd.AddCommand(newSessionsTagsCmd())
 
 	return cmd
 }
@@ -46,6 +51,7 @@ Storage: ~/.local/share/ayo/ayo.db`,
 func newSessionsListCmd() *cobra.Command {
 	var agentFilter string
 	var sourceFilter string
+	var tagFilter string
 	var limit int64
 
 	cmd := &cobra.Command{
@@ -60,10 +66,20 @@ func newSessionsListCmd() *cobra.Command {
 
 			var sessions []session.Session
 			switch {
+			case tagFilter != "" && (agentFilter != "" || sourceFilter != ""):
+				sessions, err = listTaggedSessions(cmd.Context(), services, tagFilter, agentFilter, sourceFilter, limit)
+			case agent.IsHandlePattern(agentFilter):
+				var handles []string
+				handles, err = services.Sessions.AgentHandles(cmd.Context())
+				if err == nil {
+					sessions, err = services.Sessions.ListByAgents(cmd.Context(), agent.MatchHandles(agentFilter, handles), limit)
+				}
 			case agentFilter != "":
 				sessions, err = services.Sessions.ListByAgent(cmd.Context(), agentFilter, limit)
 			case sourceFilter != "":
 				sessions, err = services.Sessions.ListBySource(cmd.Context(), sourceFilter, limit)
+			case tagFilter != "":
+				sessions, err = services.Sessions.ListByTag(cmd.Context(), tagFilter, limit)
 			default:
 				sessions, err = services.Sessions.List(cmd.Context(), limit)
 			}
@@ -83,6 +99,7 @@ func newSessionsListCmd() *cobra.Command {
 			titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
 			countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 			timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+			tagStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("110"))
 
 			fmt.Println()
 			fmt.Println(headerStyle.Render("  Sessions"))
@@ -116,6 +133,9 @@ func newSessionsListCmd() *cobra.Command {
 					sourceIndicator,
 					timeStyle.Render(timeAgo),
 				)
+				if tags, _ := services.Sessions.Tags(cmd.Context(), s.ID); len(tags) > 0 {
+					fmt.Printf("    %s\n", tagStyle.Render(formatTags(tags)))
+				}
 				fmt.Println()
 			}
 
@@ -126,18 +146,29 @@ func newSessionsListCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "filter by agent handle")
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "filter by agent handle or glob pattern (e.g. '@team.*')")
 	cmd.Flags().StringVarP(&sourceFilter, "source", "s", "", "filter by source (ayo, crush, crush-via-ayo)")
+	cmd.Flags().StringVarP(&tagFilter, "tag", "t", "", "filter by tag (combines with --agent and --source)")
 	cmd.Flags().Int64VarP(&limit, "limit", "n", 20, "maximum number of sessions to show")
 
 	return cmd
 }
 
-func newSessionsShowCmd() *cobra.Command {
+func newSessionsShowCmd(cfgPath *string) *cobra.Command {
+	var raw bool
+
 	cmd := &cobra.Command{
 		Use:   "show [session-id]",
 		Short: "Show session details and conversation",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Show a session's details and conversation.
+
+--raw prints the messages a resumed session sends to the provider as JSON:
+the agent's current system messages followed by the stored messages, with
+their tool calls and results, after redaction. Memories that would be
+injected on resume are not included.`,
+		Example: `  ayo sessions show 4443df27
+  ayo sessions show 4443df27 --raw`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			services, err := session.Connect(cmd.Context(), paths.DatabasePath())
 			if err != nil {
@@ -203,44 +234,91 @@ func newSessionsShowCmd() *cobra.Command {
 				return fmt.Errorf("failed to list messages: %w", err)
 			}
 
+			if raw {
+				return printRawSession(cmd, *cfgPath, sess, messages)
+			}
+
 			// Styles
 			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
 			labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 			valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
 
+			// Long conversations are paged
+			out := ui.NewPager()
+
 			// Session details
-			fmt.Println()
-			fmt.Println(headerStyle.Render("  Session Details"))
-			fmt.Println(headerStyle.Render("  " + strings.Repeat("─", 60)))
-			fmt.Println()
-			fmt.Printf("  %s %s\n", labelStyle.Render("ID:"), valueStyle.Render(sess.ID))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Agent:"), valueStyle.Render(sess.AgentHandle))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Title:"), valueStyle.Render(sess.Title))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Messages:"), valueStyle.Render(fmt.Sprintf("%d", sess.MessageCount)))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Created:"), valueStyle.Render(formatTime(sess.CreatedAt)))
-			fmt.Printf("  %s %s\n", labelStyle.Render("Updated:"), valueStyle.Render(formatTime(sess.UpdatedAt)))
-			fmt.Println()
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, headerStyle.Render("  Session Details"))
+			fmt.Fprintln(out, headerStyle.Render("  "+strings.Repeat("─", 60)))
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("ID:"), valueStyle.Render(sess.ID))
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Agent:"), valueStyle.Render(sess.AgentHandle))
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Title:"), valueStyle.Render(sess.Title))
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Messages:"), valueStyle.Render(fmt.Sprintf("%d", sess.MessageCount)))
+			if sess.Seed != nil {
+				fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Seed:"), valueStyle.Render(fmt.Sprintf("%d", *sess.Seed)))
+			}
+			if tags, _ := services.Sessions.Tags(cmd.Context(), sess.ID); len(tags) > 0 {
+				fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Tags:"), valueStyle.Render(formatTags(tags)))
+			}
+			if parent, ok, _ := services.BranchParent(cmd.Context(), sess.ID); ok {
+				fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Branched from:"), valueStyle.Render(formatBranchPoint(cmd, services, parent)))
+			}
+			if branches, _ := services.Branches(cmd.Context(), sess.ID); len(branches) > 0 {
+				ids := make([]string, len(branches))
+				for i, b := range branches {
+					ids[i] = b.ChildID[:8]
+				}
+				fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Branches:"), valueStyle.Render(strings.Join(ids, ", ")))
+			}
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Created:"), valueStyle.Render(formatTime(sess.CreatedAt)))
+			fmt.Fprintf(out, "  %s %s\n", labelStyle.Render("Updated:"), valueStyle.Render(formatTime(sess.UpdatedAt)))
+			fmt.Fprintln(out)
 
 			// Conversation using common renderer
 			if len(messages) > 0 {
-				fmt.Println(headerStyle.Render("  Conversation"))
-				fmt.Println(headerStyle.Render("  " + strings.Repeat("─", 60)))
-				fmt.Println()
+				fmt.Fprintln(out, headerStyle.Render("  Conversation"))
+				fmt.Fprintln(out, headerStyle.Render("  "+strings.Repeat("─", 60)))
+				fmt.Fprintln(out)
 				output := ui.RenderHistory(messages, sess.AgentHandle)
-				fmt.Println(output)
-				fmt.Println()
+				fmt.Fprintln(out, output)
+				fmt.Fprintln(out)
 			} else {
-				fmt.Println(labelStyle.Render("  No messages in this session."))
-				fmt.Println()
+				fmt.Fprintln(out, labelStyle.Render("  No messages in this session."))
+				fmt.Fprintln(out)
 			}
 
-			return nil
+			return out.Close()
 		},
 	}
 
+	cmd.Flags().BoolVar(&raw, "raw", false, "print the provider messages a resumed session sends, as JSON")
+
 	return cmd
 }
 
+// printRawSession prints the provider messages rebuilt from a session. When
+// the session's agent can't be loaded, only the stored messages are printed.
+func printRawSession(cmd *cobra.Command, cfgPath string, sess session.Session, messages []session.Message) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		return err
+	}
+	runner, err := run.NewRunner(cfg, false, run.RunnerOptions{})
+	if err != nil {
+		return err
+	}
+
+	ag, err := agent.Load(cfg, sess.AgentHandle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; system messages omitted\n", err)
+		ag = agent.Agent{Handle: sess.AgentHandle}
+	}
+	enc := newJSONEncoder(os.Stdout)
+	enc.SetEscapeHTML(false) // keep prompt tags such as <tools> readable
+	return enc.Encode(runner.ResumeMessages(cmd.Context(), ag, messages))
+}
+
 func newSessionsDeleteCmd() *cobra.Command {
 	var force bool
 
@@ -296,6 +374,164 @@ func newSessionsDeleteCmd() *cobra.Command {
 	return cmd
 }
 
+func newSessionsTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag <session-id> <+tag|-tag>...",
+		Short: "Add or remove session tags",
+		Long: `Add or remove tags on a session.
+
+Prefix a tag with + to add it or - to remove it. A tag without a prefix is
+added. Tags are lowercased and may not contain spaces or commas.
+
+Examples:
+  ayo sessions tag abc123 +work +ayo
+  ayo sessions tag abc123 -draft`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Validate every tag before changing anything
+			var add, remove []string
+			for _, arg := range args[1:] {
+				target := &add
+				switch {
+				case strings.HasPrefix(arg, "+"):
+					arg = arg[1:]
+				case strings.HasPrefix(arg, "-"):
+					arg, target = arg[1:], &remove
+				}
+				tag, err := session.NormalizeTag(arg)
+				if err != nil {
+					return err
+				}
+				*target = append(*target, tag)
+			}
+
+			services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer services.Close()
+
+			sess, err := findSession(cmd, services, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := services.Sessions.AddTags(cmd.Context(), sess.ID, add...); err != nil {
+				return fmt.Errorf("failed to add tags: %w", err)
+			}
+			if err := services.Sessions.RemoveTags(cmd.Context(), sess.ID, remove...); err != nil {
+				return fmt.Errorf("failed to remove tags: %w", err)
+			}
+
+			tags, err := services.Sessions.Tags(cmd.Context(), sess.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list tags: %w", err)
+			}
+			if len(tags) == 0 {
+				fmt.Printf("Session %s has no tags\n", sess.ID[:8])
+			} else {
+				fmt.Printf("Session %s tags: %s\n", sess.ID[:8], formatTags(tags))
+			}
+			return nil
+		},
+	}
+
+	// Stop flag parsing at the session ID so -tag arguments aren't read as flags
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+func newSessionsTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List session tags with counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			services, err := session.Connect(cmd.Context(), paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer services.Close()
+
+			counts, err := services.Sessions.TagCounts(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to list tags: %w", err)
+			}
+
+			if len(counts) == 0 {
+				fmt.Println("No tags found")
+				return nil
+			}
+
+			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+			tagStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("110"))
+			countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+			width := 0
+			for _, c := range counts {
+				width = max(width, len(c.Tag))
+			}
+
+			fmt.Println()
+			fmt.Println(headerStyle.Render("  Tags"))
+			fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
+			fmt.Println()
+			for _, c := range counts {
+				noun := "sessions"
+				if c.Count == 1 {
+					noun = "session"
+				}
+				fmt.Printf("  %s  %s\n",
+					tagStyle.Render(fmt.Sprintf("%-*s", width, c.Tag)),
+					countStyle.Render(fmt.Sprintf("%d %s", c.Count, noun)),
+				)
+			}
+			fmt.Println()
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// listTaggedSessions returns the most recent sessions carrying tag that also
+// match the agent handle or pattern and the source, when those are set.
+func listTaggedSessions(ctx context.Context, services *session.Services, tag, agentFilter, source string, limit int64) ([]session.Session, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	// Every tagged session is read so the limit applies after filtering
+	tagged, err := services.Sessions.ListByTag(ctx, tag, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []session.Session
+	for _, s := range tagged {
+		if agentFilter != "" && !agent.MatchHandle(agentFilter, s.AgentHandle) {
+			continue
+		}
+		if source != "" && s.Source != source {
+			continue
+		}
+		sessions = append(sessions, s)
+		if int64(len(sessions)) == limit {
+			break
+		}
+	}
+	return sessions, nil
+}
+
+// formatTags renders tags as "#work #draft".
+func formatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = "#" + tag
+	}
+	return strings.Join(parts, " ")
+}
+
 func newSessionsContinueCmd(cfgPath *string) *cobra.Command {
 	var debug bool
 	var latest bool
@@ -382,94 +618,177 @@ Supports session ID prefix matching and title search.`,
 				}
 			}
 
-			// Load the agent
-			ag, err := agent.Load(cfg, sess.AgentHandle)
+			return resumeSessionChat(cmd, cfg, services, sess, debug)
+		},
+	}
+
+	cmd.Flags().BoolVar(&debug, "debug", false, "show debug output")
+	cmd.Flags().BoolVarP(&latest, "latest", "l", false, "continue the most recent session without prompting")
+
+	return cmd
+}
+
+func newSessionsBranchCmd(cfgPath *string) *cobra.Command {
+	var debug bool
+	var at int
+
+	cmd := &cobra.Command{
+		Use:   "branch <session-id>",
+		Short: "Fork a session and continue the copy",
+		Long: `Create a new session from a past conversation and continue it interactively.
+
+The new session copies the first --at messages of the original (all of them
+by default), so you can take the conversation in a different direction. The
+original session is not changed. 'ayo sessions show' lists a session's
+branches and, for a branch, the session and message it was branched from.
+
+Messages are numbered from 1 in the order 'ayo sessions show' prints them.
+Branch at the message before a prompt to ask it differently.
+
+Supports session ID prefix matching and title search.`,
+		Example: `  # Fork the whole conversation
+  ayo sessions branch abc123
+
+  # Keep the first 2 messages and ask the second question differently
+  ayo sessions branch abc123 --at 2`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(*cfgPath)
 			if err != nil {
-				return fmt.Errorf("failed to load agent %s: %w", sess.AgentHandle, err)
+				return err
 			}
 
-			// Load session messages
-			messages, err := services.Messages.List(cmd.Context(), sess.ID)
+			services, err := session.Connect(cmd.Context(), paths.DatabasePath())
 			if err != nil {
-				return fmt.Errorf("failed to load messages: %w", err)
-			}
-
-			// Create memory services with Ollama if available
-			var embedder embedding.Embedder
-			var smallModelSvc *smallmodel.Service
-			var memQueue *memory.Queue
-			ollamaClient := ollama.NewClient(ollama.WithHost(cfg.OllamaHost))
-			if ollamaClient.IsAvailable(cmd.Context()) {
-				embedder = embedding.NewOllamaEmbedder(embedding.OllamaConfig{
-					Host:  cfg.OllamaHost,
-					Model: cfg.Embedding.Model,
-				})
-				defer embedder.Close()
-				smallModelSvc = smallmodel.NewService(smallmodel.Config{
-					Host:  cfg.OllamaHost,
-					Model: cfg.SmallModel,
-				})
-			} else if debug {
-				fmt.Fprintf(os.Stderr, "Warning: Ollama not available at %s, memory features disabled\n", cfg.OllamaHost)
-			}
-			memSvc := memory.NewService(services.Queries(), embedder)
-			formSvc := memory.NewFormationService(memSvc)
-			
-			// Create async memory queue
-			memQueue = memory.NewQueue(memSvc, memory.QueueConfig{
-				BufferSize: 100,
-				OnStatus: func(msg ui.AsyncStatusMsg) {
-					switch msg.Status {
-					case ui.AsyncStatusInProgress:
-						fmt.Fprintf(os.Stderr, "  ◇ %s\n", msg.Message)
-					case ui.AsyncStatusCompleted:
-						fmt.Fprintf(os.Stderr, "  ◆ %s\n", msg.Message)
-					case ui.AsyncStatusFailed:
-						fmt.Fprintf(os.Stderr, "  × %s\n", msg.Message)
-					}
-				},
-			})
-			memQueue.Start()
-			defer memQueue.Stop(5 * time.Second)
-
-			// Create runner with services
-			runner, err := run.NewRunner(cfg, debug, run.RunnerOptions{
-				Services:         services,
-				MemoryService:    memSvc,
-				FormationService: formSvc,
-				SmallModel:       smallModelSvc,
-				MemoryQueue:      memQueue,
-			})
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer services.Close()
+
+			parent, err := findSession(cmd, services, args[0])
 			if err != nil {
 				return err
 			}
-
-			// Resume the session
-			if err := runner.ResumeSession(cmd.Context(), ag, sess.ID, messages); err != nil {
-				return fmt.Errorf("failed to resume session: %w", err)
+			if at < 0 {
+				return fmt.Errorf("--at must be a message number from 1 to %d", parent.MessageCount)
 			}
 
-			// Show preview of last 3 messages if there's history
-			if len(messages) > 0 {
-				preview := ui.RenderHistoryPreview(messages, sess.AgentHandle, 3)
-				if preview != "" {
-					fmt.Println()
-					fmt.Println(preview)
-					fmt.Println()
-				}
+			branch, err := services.Branch(cmd.Context(), parent.ID, at)
+			if err != nil {
+				return fmt.Errorf("failed to branch session: %w", err)
 			}
 
-			// Run interactive chat
-			return runInteractiveChat(cmd.Context(), runner, ag, debug)
+			labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+			idStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			fmt.Println(labelStyle.Render(fmt.Sprintf("Branched %s at message %d into %s",
+				idStyle.Render(parent.ID[:8]), branch.MessageCount, idStyle.Render(branch.ID[:8]))))
+
+			return resumeSessionChat(cmd, cfg, services, branch, debug)
 		},
 	}
 
+	cmd.Flags().IntVar(&at, "at", 0, "number of messages to keep (default: all)")
 	cmd.Flags().BoolVar(&debug, "debug", false, "show debug output")
-	cmd.Flags().BoolVarP(&latest, "latest", "l", false, "continue the most recent session without prompting")
 
 	return cmd
 }
 
+// formatBranchPoint describes where a branch was taken from, such as
+// "1a2b3c4d at message 4".
+func formatBranchPoint(cmd *cobra.Command, services *session.Services, parent session.Edge) string {
+	desc := parent.ParentID[:8]
+	if parent.TriggerMessageID == "" {
+		return desc
+	}
+	messages, err := services.Messages.List(cmd.Context(), parent.ParentID)
+	if err != nil {
+		return desc
+	}
+	for i, msg := range messages {
+		if msg.ID == parent.TriggerMessageID {
+			return fmt.Sprintf("%s at message %d", desc, i+1)
+		}
+	}
+	return desc
+}
+
+// resumeSessionChat loads a session's agent and history and continues the
+// conversation interactively.
+func resumeSessionChat(cmd *cobra.Command, cfg config.Config, services *session.Services, sess session.Session, debug bool) error {
+	// Load the agent
+	ag, err := agent.Load(cfg, sess.AgentHandle)
+	if err != nil {
+		return fmt.Errorf("failed to load agent %s: %w", sess.AgentHandle, err)
+	}
+	printAgentWarnings(ag)
+
+	// Load session messages
+	messages, err := services.Messages.List(cmd.Context(), sess.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load messages: %w", err)
+	}
+
+	// Create memory services with Ollama if available
+	var embedder embedding.Embedder
+	var smallModelSvc *smallmodel.Service
+	var memQueue *memory.Queue
+	if ollamaAvailable(cmd.Context(), cfg) {
+		embedder = embedding.NewOllamaEmbedder(embedding.OllamaConfig{
+			Host:  cfg.OllamaHost,
+			Model: cfg.Embedding.Model,
+		})
+		defer embedder.Close()
+		smallModelSvc = smallmodel.NewService(smallmodel.Config{
+			Host:  cfg.OllamaHost,
+			Model: cfg.SmallModel,
+		})
+	} else if debug {
+		fmt.Fprintf(os.Stderr, "Warning: Ollama not available at %s, memory features disabled\n", cfg.OllamaHost)
+	}
+	memSvc := memory.NewService(services.Queries(), embedder)
+	formSvc := memory.NewFormationService(memSvc)
+	
+	// Create async memory queue
+	memQueue = memory.NewQueue(memSvc, memory.QueueConfig{
+		BufferSize: 100,
+		OnStatus:   memoryStatusPrinter(cfg.OllamaHost),
+	})
+	memQueue.Start()
+	defer memQueue.Stop(5 * time.Second)
+
+	// Create runner with services
+	runner, err := run.NewRunner(cfg, debug, run.RunnerOptions{
+		Services:         services,
+		MemoryService:    memSvc,
+		FormationService: formSvc,
+		SmallModel:       smallModelSvc,
+		MemoryQueue:      memQueue,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resume the session
+	if err := runner.ResumeSession(cmd.Context(), ag, sess.ID, messages); err != nil {
+		return fmt.Errorf("failed to resume session: %w", err)
+	}
+
+	// Show preview of last 3 messages if there's history
+	if len(messages) > 0 {
+		preview := ui.RenderHistoryPreview(messages, sess.AgentHandle, 3)
+		if preview != "" {
+			fmt.Println()
+			fmt.Println(preview)
+			fmt.Println()
+		}
+	}
+
+	// Run interactive chat
+	err = runInteractiveChat(cmd.Context(), cfg, runner, ag, debug, false)
+	runner.Shutdown(true, drainTimeout)
+	printRedactionSummary(runner)
+	return err
+}
+
 // findSession finds a session by exact ID, prefix match, or title search.
 // If multiple matches are found, prompts user to select one.
 func findSession(cmd *cobra.Command, services *session.Services, query string) (session.Session, error) {
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/embedding"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/run"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+	"github.com/alexcabrera/ayo/internal/ui"
+)
+
+// Transcript chunk sizes for summarizing, in bytes. Local small models run
+// with a few thousand tokens of context; provider models take much more.
+const (
+	smallModelSummaryChunk = 8000
+	modelSummaryChunk      = 48000
+)
+
+// sessionSummaryJSON is the --json output of "ayo sessions summarize".
+type sessionSummaryJSON struct {
+	SessionID string `json:"session_id"`
+	Summary   string `json:"summary"`
+	Model     string `json:"model"`
+	Chunks    int    `json:"chunks"`
+	MemoryID  string `json:"memory_id,omitempty"`
+}
+
+func newSessionsSummarizeCmd(cfgPath *string) *cobra.Command {
+	var modelID string
+	var agentModel bool
+	var saveMemory bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "summarize <session-id>",
+		Short: "Summarize a session's conversation",
+		Long: `Summarize a session's conversation in a short paragraph.
+
+The summary is written by the small model (see 'small_model' in the config)
+through Ollama. With --agent-model it is written by the session agent's model
+instead, and with --model by the given model from the configured provider.
+When Ollama isn't running, the agent's model is used.
+
+Only the text of user and assistant messages is summarized. A conversation
+too long for one request is split into parts that are summarized separately,
+then the part summaries are summarized together.
+
+With --save-memory, the summary is also stored as a fact memory for the
+session's agent, so its future sessions can draw on it.
+
+Supports session ID prefix matching and title search.`,
+		Example: `  ayo sessions summarize 4443df27
+  ayo sessions summarize 4443df27 --agent-model --save-memory`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if agentModel && modelID != "" {
+				return fmt.Errorf("the --agent-model and --model flags can't be used together")
+			}
+			cfg, err := config.Load(*cfgPath)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			services, err := session.Connect(ctx, paths.DatabasePath())
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			defer services.Close()
+
+			sess, err := findSession(cmd, services, args[0])
+			if err != nil {
+				return err
+			}
+			messages, err := services.Messages.List(ctx, sess.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list messages: %w", err)
+			}
+
+			// The small model, unless a provider model was asked for or
+			// Ollama isn't running
+			var summarize session.SummarizeFunc
+			chunk := smallModelSummaryChunk
+			useSmallModel := modelID == "" && !agentModel
+			if useSmallModel && !ollamaAvailable(ctx, cfg) {
+				useSmallModel = false
+				if !jsonOutput {
+					fmt.Fprintf(os.Stderr, "Ollama not available at %s; summarizing with the agent's model\n", cfg.OllamaHost)
+				}
+			}
+			if useSmallModel {
+				svc := smallmodel.NewService(smallmodel.Config{Host: cfg.OllamaHost, Model: cfg.SmallModel})
+				summarize = svc.SummarizeConversation
+				modelID = svc.Model()
+			} else {
+				if modelID == "" {
+					modelID = sessionAgentModel(cfg, sess)
+				}
+				runner, err := run.NewRunner(cfg, false, run.RunnerOptions{})
+				if err != nil {
+					return err
+				}
+				if summarize, err = runner.Summarizer(modelID); err != nil {
+					return err
+				}
+				chunk = modelSummaryChunk
+			}
+
+			var spinner *ui.Spinner
+			if !jsonOutput {
+				spinner = ui.NewSpinner("summarizing...")
+				spinner.Start()
+			}
+			result, err := session.Summarize(ctx, messages, chunk, summarize)
+			if spinner != nil {
+				spinner.Stop()
+			}
+			if errors.Is(err, session.ErrNothingToSummarize) {
+				return fmt.Errorf("session %s has no messages to summarize", sess.ID[:8])
+			}
+			if err != nil {
+				return err
+			}
+
+			out := sessionSummaryJSON{
+				SessionID: sess.ID,
+				Summary:   result.Summary,
+				Model:     modelID,
+				Chunks:    result.Chunks,
+			}
+			if saveMemory {
+				mem, err := saveSessionSummary(cmd, cfg, services, sess, result.Summary)
+				if err != nil {
+					return err
+				}
+				out.MemoryID = mem.ID
+			}
+
+			if jsonOutput {
+				return writeJSON(out)
+			}
+
+			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+			mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			title := sess.Title
+			if title == "" {
+				title = sess.ID[:8]
+			}
+			fmt.Println(headerStyle.Render(title))
+			parts := ""
+			if result.Chunks > 1 {
+				parts = fmt.Sprintf(", %d parts", result.Chunks)
+			}
+			fmt.Println(mutedStyle.Render(fmt.Sprintf("%s, %d messages%s, summarized by %s", sess.AgentHandle, len(messages), parts, modelID)))
+			fmt.Println()
+			fmt.Println(result.Summary)
+			if out.MemoryID != "" {
+				fmt.Println()
+				fmt.Println(mutedStyle.Render("Saved as memory " + out.MemoryID[:8]))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&modelID, "model", "m", "", "summarize with this model from the configured provider")
+	cmd.Flags().BoolVar(&agentModel, "agent-model", false, "summarize with the session agent's model instead of the small model")
+	cmd.Flags().BoolVar(&saveMemory, "save-memory", false, "store the summary as a memory for the session's agent")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// sessionAgentModel returns the model of the session's agent, or the
+// default model if the agent can't be loaded.
+func sessionAgentModel(cfg config.Config, sess session.Session) string {
+	if ag, err := agent.Load(cfg, sess.AgentHandle); err == nil && ag.Model != "" {
+		return ag.Model
+	}
+	return cfg.DefaultModel
+}
+
+// saveSessionSummary stores summary as a fact memory for the session's
+// agent, linked to the session.
+func saveSessionSummary(cmd *cobra.Command, cfg config.Config, services *session.Services, sess session.Session, summary string) (memory.Memory, error) {
+	// Without Ollama the memory is stored without an embedding
+	var embedder embedding.Embedder
+	if ollamaAvailable(cmd.Context(), cfg) {
+		embedder = embedding.NewOllamaEmbedder(embedding.OllamaConfig{
+			Host:  cfg.OllamaHost,
+			Model: cfg.Embedding.Model,
+		})
+		defer embedder.Close()
+	}
+
+	content := "Summary of an earlier conversation"
+	if sess.Title != "" {
+		content += fmt.Sprintf(" (%q)", sess.Title)
+	}
+	mem, err := memory.NewService(services.Queries(), embedder).Create(cmd.Context(), memory.Memory{
+		Content:         content + ": " + summary,
+		Category:        memory.CategoryFact,
+		AgentHandle:     sess.AgentHandle,
+		SourceSessionID: sess.ID,
+	})
+	if err != nil {
+		return memory.Memory{}, fmt.Errorf("failed to store memory: %w", err)
+	}
+	return mem, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func TestListTaggedSessions(t *testing.T) {
+	ctx := context.Background()
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	create := func(handle, source string, tags ...string) session.Session {
+		sess, err := services.Sessions.Create(ctx, session.CreateParams{AgentHandle: handle, Source: source})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if len(tags) > 0 {
+			if err := services.Sessions.AddTags(ctx, sess.ID, tags...); err != nil {
+				t.Fatalf("AddTags: %v", err)
+			}
+		}
+		return sess
+	}
+	want := create("@ayo", session.SourceAyo, "work")
+	create("@ayo", session.SourceAyo)                    // Right agent, no tag
+	create("@research", session.SourceAyo, "work")       // Tagged, other agent
+	crush := create("@ayo", session.SourceCrush, "work") // Tagged, other source
+
+	got, err := listTaggedSessions(ctx, services, "work", "@ayo", "", 20)
+	if err != nil {
+		t.Fatalf("listTaggedSessions: %v", err)
+	}
+	if ids := sessionIDs(got); len(ids) != 2 || !ids[want.ID] || !ids[crush.ID] {
+		t.Errorf("--agent @ayo --tag work = %v, want the two tagged @ayo sessions", ids)
+	}
+
+	got, err = listTaggedSessions(ctx, services, "work", "ayo", session.SourceAyo, 20)
+	if err != nil {
+		t.Fatalf("listTaggedSessions: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != want.ID {
+		t.Errorf("--agent ayo --source ayo --tag work = %v, want only %s", sessionIDs(got), want.ID)
+	}
+
+	got, err = listTaggedSessions(ctx, services, "work", "@*", "", 1)
+	if err != nil {
+		t.Fatalf("listTaggedSessions: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("limit 1 returned %d sessions", len(got))
+	}
+}
+
+func sessionIDs(sessions []session.Session) map[string]bool {
+	ids := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		ids[s.ID] = true
+	}
+	return ids
+}
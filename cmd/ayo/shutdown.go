@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// drainTimeout bounds how long an exit waits for a runner's pending
+// background work before cancelling it.
+const drainTimeout = 3 * time.Second
+
+// interruptExitCode is the conventional exit status after SIGINT.
+const interruptExitCode = 130
+
+var drains struct {
+	sync.Mutex
+	next int
+	fns  map[int]func(timeout time.Duration)
+}
+
+// drainOnInterrupt makes an interrupt drain runner's background work (title
+// generation, memory formations, and queued memory writes) before the
+// process exits, cancelling whatever is still running after the timeout.
+// Call the returned function once the runner is done.
+func drainOnInterrupt(runner *run.Runner) func() {
+	return registerDrain(func(timeout time.Duration) {
+		runner.Shutdown(true, timeout)
+	})
+}
+
+func registerDrain(fn func(timeout time.Duration)) func() {
+	drains.Lock()
+	defer drains.Unlock()
+	if drains.fns == nil {
+		drains.fns = make(map[int]func(time.Duration))
+	}
+	id := drains.next
+	drains.next++
+	drains.fns[id] = fn
+	return func() {
+		drains.Lock()
+		defer drains.Unlock()
+		delete(drains.fns, id)
+	}
+}
+
+// handleInterrupts exits on the first signal from sigs, after running the
+// registered drains together under one drainTimeout. A second signal exits
+// without waiting for them.
+func handleInterrupts(sigs <-chan os.Signal, stderr io.Writer, exit func(code int)) {
+	<-sigs
+
+	drains.Lock()
+	fns := make([]func(time.Duration), 0, len(drains.fns))
+	for _, fn := range drains.fns {
+		fns = append(fns, fn)
+	}
+	drains.Unlock()
+	if len(fns) == 0 {
+		exit(interruptExitCode)
+		return
+	}
+
+	fmt.Fprintln(stderr, "\nfinishing up... (ctrl+c again to quit now)")
+
+	// Drain every runner at once, so the exit waits for one timeout however
+	// many runners there are.
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(drainTimeout)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(drainTimeout):
+	case <-sigs:
+	}
+	exit(interruptExitCode)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// interrupt runs handleInterrupts until it exits and returns the exit code
+// and what it printed. extra signals are sent after the first.
+func interrupt(t *testing.T, extra int) (int, string) {
+	t.Helper()
+	sigs := make(chan os.Signal, 1+extra)
+	for range 1 + extra {
+		sigs <- os.Interrupt
+	}
+	var stderr bytes.Buffer
+	code := -1
+	handleInterrupts(sigs, &stderr, func(c int) { code = c })
+	return code, stderr.String()
+}
+
+func TestHandleInterruptsWithoutDrains(t *testing.T) {
+	code, out := interrupt(t, 0)
+	if code != interruptExitCode || out != "" {
+		t.Errorf("exit %d, output %q; want %d and no output", code, out, interruptExitCode)
+	}
+}
+
+func TestHandleInterruptsDrains(t *testing.T) {
+	var gotTimeout time.Duration
+	defer registerDrain(func(timeout time.Duration) { gotTimeout = timeout })()
+
+	code, out := interrupt(t, 0)
+	if code != interruptExitCode || !strings.Contains(out, "finishing up") {
+		t.Errorf("exit %d, output %q", code, out)
+	}
+	if gotTimeout != drainTimeout {
+		t.Errorf("drain ran with timeout %v, want %v", gotTimeout, drainTimeout)
+	}
+}
+
+func TestHandleInterruptsSecondSignalForcesExit(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	defer registerDrain(func(time.Duration) { <-block })()
+
+	done := make(chan int)
+	go func() {
+		code, _ := interrupt(t, 1)
+		done <- code
+	}()
+	select {
+	case code := <-done:
+		if code != interruptExitCode {
+			t.Errorf("exit %d, want %d", code, interruptExitCode)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second interrupt did not exit while the drain was blocked")
+	}
+}
+
+func TestHandleInterruptsDrainsConcurrently(t *testing.T) {
+	// Each drain waits for the other to start, which only happens when they
+	// run at the same time.
+	var started, finished sync.WaitGroup
+	started.Add(2)
+	for range 2 {
+		finished.Add(1)
+		defer registerDrain(func(time.Duration) {
+			defer finished.Done()
+			started.Done()
+			started.Wait()
+		})()
+	}
+
+	start := time.Now()
+	if code, _ := interrupt(t, 0); code != interruptExitCode {
+		t.Errorf("exit %d, want %d", code, interruptExitCode)
+	}
+	if elapsed := time.Since(start); elapsed >= drainTimeout {
+		t.Errorf("drains took %v, want them to run together", elapsed)
+	}
+	finished.Wait()
+}
@@ -9,10 +9,14 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/builtin"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/plugins"
 	"github.com/alexcabrera/ayo/internal/skills"
+	"github.com/alexcabrera/ayo/internal/tools"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 func newSkillsCmd(cfgPath *string) *cobra.Command {
@@ -37,9 +41,10 @@ Discovery priority (first found wins):
 
 	cmd.AddCommand(listSkillsCmd(cfgPath))
 	cmd.AddCommand(showSkillCmd(cfgPath))
-	cmd.AddCommand(validateSkillCmd())
-	cmd.AddCommand(createSkillCmd(cfgPath))
+	cmd.AddCommand(validateSkillCmd(cfgPath))
+	cmd.AddCommand(newSkillCmd(cfgPath))
 	cmd.AddCommand(updateSkillsCmd(cfgPath))
+	cmd.AddCommand(skillStatsCmd())
 
 	return cmd
 }
@@ -67,17 +72,17 @@ func listSkillsCmd(cfgPath *string) *cobra.Command {
 				}
 
 				// Color palette
-				purple := lipgloss.Color("#a78bfa")
-				cyan := lipgloss.Color("#67e8f9")
-				muted := lipgloss.Color("#6b7280")
-				text := lipgloss.Color("#e5e7eb")
-				subtle := lipgloss.Color("#374151")
+				primary := shared.ColorPrimary
+				secondary := shared.ColorSecondary
+				muted := shared.ColorMuted
+				text := shared.ColorText
+				subtle := shared.ColorSubtle
 
 				// Styles
-				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(purple)
+				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primary)
 				sectionStyle := lipgloss.NewStyle().Foreground(muted).Bold(true)
-				iconStyle := lipgloss.NewStyle().Foreground(cyan)
-				nameStyle := lipgloss.NewStyle().Foreground(cyan).Bold(true)
+				iconStyle := lipgloss.NewStyle().Foreground(secondary)
+				nameStyle := lipgloss.NewStyle().Foreground(secondary).Bold(true)
 				descStyle := lipgloss.NewStyle().Foreground(text)
 				countStyle := lipgloss.NewStyle().Foreground(muted)
 				dividerStyle := lipgloss.NewStyle().Foreground(subtle)
@@ -120,7 +125,7 @@ func listSkillsCmd(cfgPath *string) *cobra.Command {
 				fmt.Printf("  %s\n", sectionStyle.Render("User-defined"))
 				if len(userSkills) == 0 {
 					fmt.Printf("    %s\n", emptyStyle.Render("No user-defined skills"))
-					fmt.Printf("    %s\n", emptyStyle.Render("Create one with: ayo skills create <name> --shared"))
+					fmt.Printf("    %s\n", emptyStyle.Render("Create one with: ayo skills new <name> --shared"))
 				} else {
 					for _, s := range userSkills {
 						renderSkill(s)
@@ -153,10 +158,23 @@ func listSkillsCmd(cfgPath *string) *cobra.Command {
 }
 
 func showSkillCmd(cfgPath *string) *cobra.Command {
+	var agentHandle string
+	var jsonOutput bool
+
 	cmd := &cobra.Command{
 		Use:   "show <name>",
 		Short: "Show skill details",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show a skill's metadata, the tools that require it, the agents that
+include it, and its SKILL.md instructions.
+
+The skill is resolved the way agents resolve skills: shared skills first,
+then built-in and plugin skills. With --agent, the agent's own skills
+directory is searched first, so an agent-specific skill that overrides a
+shared one is shown.`,
+		Example: `  ayo skills show agent-discovery
+  ayo skills show debugging --agent @ayo
+  ayo skills show agent-discovery --json`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -166,94 +184,59 @@ func showSkillCmd(cfgPath *string) *cobra.Command {
 					return fmt.Errorf("install builtins: %w", err)
 				}
 
-				// Discover all skills
-				result := skills.DiscoverAll(skills.DiscoveryOptions{
-					SharedDirs: paths.SkillsDirs(),
-				})
-
-				// Find the skill
-				var meta *skills.Metadata
-				for i := range result.Skills {
-					if result.Skills[i].Name == name {
-						meta = &result.Skills[i]
-						break
+				agentDir := ""
+				if agentHandle != "" {
+					ag, err := agent.Load(cfg, agentHandle)
+					if err != nil {
+						return err
 					}
+					agentDir = ag.Dir
 				}
 
-				if meta == nil {
+				meta, ok := agent.FindSkill(agentDir, name)
+				if !ok {
 					return fmt.Errorf("skill not found: %s", name)
 				}
 
 				// Load full skill
-				skill, err := skills.Load(*meta)
+				skill, err := skills.Load(meta)
 				if err != nil {
 					return fmt.Errorf("load skill: %w", err)
 				}
 
-				// Styles
-				headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
-				labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-				valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
-
-				// Build output
-				fmt.Println()
-				fmt.Println(headerStyle.Render("📚 " + skill.Metadata.Name))
-				fmt.Println(strings.Repeat("─", 60))
-
-				fmt.Printf("%s %s\n", labelStyle.Render("Source:"), valueStyle.Render(skill.Metadata.Source.String()))
-
-				if v := skill.Metadata.Version(); v != "" {
-					fmt.Printf("%s %s\n", labelStyle.Render("Version:"), valueStyle.Render(v))
-				}
-				if a := skill.Metadata.Author(); a != "" {
-					fmt.Printf("%s %s\n", labelStyle.Render("Author:"), valueStyle.Render(a))
-				}
-				if skill.Metadata.License != "" {
-					fmt.Printf("%s %s\n", labelStyle.Render("License:"), valueStyle.Render(skill.Metadata.License))
-				}
-				if skill.Metadata.Compatibility != "" {
-					fmt.Printf("%s %s\n", labelStyle.Render("Compatibility:"), valueStyle.Render(skill.Metadata.Compatibility))
+				agents, err := agent.AgentsUsingSkill(cfg, name)
+				if err != nil {
+					return fmt.Errorf("find agents using skill: %w", err)
 				}
 
-				fmt.Println(strings.Repeat("─", 60))
-
-				// Description (wrapped)
-				fmt.Println(valueStyle.Render(skill.Metadata.Description))
-
-				fmt.Println(strings.Repeat("─", 60))
-
-				fmt.Printf("%s %s\n", labelStyle.Render("Location:"), valueStyle.Render(skill.Metadata.Path))
-
-				// Optional directories
-				var extras []string
-				if skill.Metadata.HasScripts {
-					extras = append(extras, "scripts/")
-				}
-				if skill.Metadata.HasRefs {
-					extras = append(extras, "references/")
-				}
-				if skill.Metadata.HasAssets {
-					extras = append(extras, "assets/")
+				details := newSkillShowJSON(skill, agents)
+				if jsonOutput {
+					return writeJSON(details)
 				}
-				if len(extras) > 0 {
-					fmt.Printf("%s %s\n", labelStyle.Render("Includes:"), valueStyle.Render(strings.Join(extras, ", ")))
-				}
-
-				fmt.Println()
-
+				printSkillDetails(details)
 				return nil
 			})
 		},
 	}
 
+	cmd.Flags().StringVar(&agentHandle, "agent", "", "resolve the skill as this agent would, including its own skills")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
 	return cmd
 }
 
-func validateSkillCmd() *cobra.Command {
+func validateSkillCmd(cfgPath *string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate <path>",
 		Short: "Validate a skill directory",
-		Args:  cobra.ExactArgs(1),
+		Long: `Validate a skill directory against the agentskills spec.
+
+Checks the required frontmatter fields, that the name is not already used
+by another discovered skill, and that every tool in allowed-tools is built
+in or provided by an enabled plugin. Exits non-zero when any check fails,
+so skills can be checked in CI.`,
+		Example: `  ayo skills validate ./my-skill`,
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			skillDir := args[0]
 
@@ -266,34 +249,68 @@ func validateSkillCmd() *cobra.Command {
 				skillDir = filepath.Join(cwd, skillDir)
 			}
 
-			errors := skills.Validate(skillDir)
+			return withConfig(cfgPath, func(cfg config.Config) error {
+				discovered := skills.DiscoverAll(skills.DiscoveryOptions{
+					SharedDirs:    paths.SkillsDirs(),
+					UserSharedDir: cfg.SkillsDir,
+					BuiltinDir:    builtin.SkillsInstallDir(),
+				})
 
-			if len(errors) == 0 {
-				successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-				fmt.Println(successStyle.Render("✓ Skill is valid"))
-				return nil
-			}
+				errors := skills.ValidateWithOptions(skillDir, skills.ValidateOptions{
+					Discovered: discovered.Skills,
+					KnownTool:  knownSkillTool(cfg),
+				})
 
-			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-			fmt.Println(errorStyle.Render("✗ Validation errors:"))
-			for _, e := range errors {
-				fmt.Printf("  • %s\n", e.Error())
-			}
+				if len(errors) == 0 {
+					successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+					fmt.Println(successStyle.Render("✓ Skill is valid"))
+					return nil
+				}
+
+				errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+				fmt.Println(errorStyle.Render("✗ Validation errors:"))
+				for _, e := range errors {
+					fmt.Printf("  • %s\n", e.Error())
+				}
 
-			return fmt.Errorf("validation failed with %d errors", len(errors))
+				return fmt.Errorf("validation failed with %d errors", len(errors))
+			})
 		},
 	}
 
 	return cmd
 }
 
-func createSkillCmd(cfgPath *string) *cobra.Command {
+// knownSkillTool reports whether a tool named in a skill's allowed-tools
+// resolves to a built-in tool or a tool from an enabled plugin.
+func knownSkillTool(cfg config.Config) func(string) bool {
+	known := map[string]bool{"bash": true, "agent_call": true, "todo": true, "memory": true, "plan": true, "http": true}
+	if registry, err := plugins.LoadRegistry(); err == nil {
+		for _, plugin := range registry.ListEnabled() {
+			for _, tool := range plugin.Tools {
+				known[tool] = true
+			}
+		}
+	}
+	return func(name string) bool {
+		return known[name] || known[tools.ResolveToolName(name, &cfg)]
+	}
+}
+
+func newSkillCmd(cfgPath *string) *cobra.Command {
 	var shared bool
 
 	cmd := &cobra.Command{
-		Use:   "create <name>",
-		Short: "Create a new skill from template",
-		Args:  cobra.ExactArgs(1),
+		Use:     "new <name>",
+		Short:   "Create a new skill from template",
+		Aliases: []string{"create"},
+		Long: `Create a skill directory with a template SKILL.md.
+
+The skill is created in the current directory, or in the shared skills
+directory with --shared. Check it with 'ayo skills validate' after editing.`,
+		Example: `  ayo skills new my-skill
+  ayo skills new my-skill --shared`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
@@ -358,7 +375,8 @@ Show example interactions.
 				successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
 				fmt.Println(successStyle.Render("✓ Created skill: " + name))
 				fmt.Printf("  Location: %s\n", skillDir)
-				fmt.Println("  Edit SKILL.md to customize your skill.")
+				fmt.Println("  Edit SKILL.md to customize your skill, then check it with:")
+				fmt.Printf("  ayo skills validate %s\n", skillDir)
 
 				return nil
 			})
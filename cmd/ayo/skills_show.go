@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/skills"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// skillToolJSON is a tool that requires a skill.
+type skillToolJSON struct {
+	Tool   string `json:"tool"`
+	Reason string `json:"reason"`
+}
+
+// skillShowJSON is the --json output of "ayo skills show".
+type skillShowJSON struct {
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	Source          string          `json:"source"`
+	Path            string          `json:"path"`
+	Version         string          `json:"version,omitempty"`
+	Author          string          `json:"author,omitempty"`
+	License         string          `json:"license,omitempty"`
+	Compatibility   string          `json:"compatibility,omitempty"`
+	Includes        []string        `json:"includes"`
+	RequiredByTools []skillToolJSON `json:"required_by_tools"`
+	Agents          []string        `json:"agents"`
+	Content         string          `json:"content"`
+}
+
+// newSkillShowJSON collects what "ayo skills show" prints about skill.
+// agents are the handles of agents that have the skill attached.
+func newSkillShowJSON(skill skills.Skill, agents []string) skillShowJSON {
+	meta := skill.Metadata
+	details := skillShowJSON{
+		Name:            meta.Name,
+		Description:     meta.Description,
+		Source:          meta.Source.String(),
+		Path:            meta.Path,
+		Version:         meta.Version(),
+		Author:          meta.Author(),
+		License:         meta.License,
+		Compatibility:   meta.Compatibility,
+		Includes:        []string{},
+		RequiredByTools: []skillToolJSON{},
+		Agents:          agents,
+		Content:         skill.Body,
+	}
+	if details.Agents == nil {
+		details.Agents = []string{}
+	}
+
+	if meta.HasScripts {
+		details.Includes = append(details.Includes, "scripts/")
+	}
+	if meta.HasRefs {
+		details.Includes = append(details.Includes, "references/")
+	}
+	if meta.HasAssets {
+		details.Includes = append(details.Includes, "assets/")
+	}
+	for _, req := range skills.GetToolsRequiringSkill(meta.Name) {
+		details.RequiredByTools = append(details.RequiredByTools, skillToolJSON{Tool: req.ToolName, Reason: req.Reason})
+	}
+	return details
+}
+
+// printSkillDetails prints a skill's metadata, the tools that require it,
+// the agents that use it, and its SKILL.md instructions.
+func printSkillDetails(details skillShowJSON) {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorPrimary)
+	iconStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(shared.ColorText)
+	labelStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	valueStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
+	handleStyle := lipgloss.NewStyle().Foreground(shared.ColorSecondary)
+	dividerStyle := lipgloss.NewStyle().Foreground(shared.ColorSubtle)
+	divider := dividerStyle.Render("  " + strings.Repeat("─", 58))
+
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Printf("  %s %s\n", labelStyle.Render(fmt.Sprintf("%-14s", label+":")), valueStyle.Render(value))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("  " + iconStyle.Render("◆") + " " + headerStyle.Render(details.Name))
+	fmt.Println(divider)
+	field("Source", details.Source)
+	field("Location", details.Path)
+	field("Version", details.Version)
+	field("Author", details.Author)
+	field("License", details.License)
+	field("Compatibility", details.Compatibility)
+	field("Includes", strings.Join(details.Includes, ", "))
+
+	if details.Description != "" {
+		fmt.Println()
+		fmt.Println("  " + valueStyle.Render(details.Description))
+	}
+
+	fmt.Println()
+	fmt.Println("  " + sectionStyle.Render("Required by tools"))
+	if len(details.RequiredByTools) == 0 {
+		fmt.Println("    " + labelStyle.Render("No tools require this skill"))
+	}
+	for _, req := range details.RequiredByTools {
+		fmt.Printf("    %s%s\n", handleStyle.Render(req.Tool), labelStyle.Render(" - "+req.Reason))
+	}
+
+	fmt.Println()
+	fmt.Println("  " + sectionStyle.Render("Used by agents"))
+	if len(details.Agents) == 0 {
+		fmt.Println("    " + labelStyle.Render("No agents include this skill"))
+	}
+	for _, handle := range details.Agents {
+		fmt.Println("    " + handleStyle.Render(handle))
+	}
+
+	fmt.Println()
+	fmt.Println("  " + sectionStyle.Render("SKILL.md"))
+	fmt.Println(divider)
+	for _, line := range strings.Split(details.Content, "\n") {
+		fmt.Println(strings.TrimRight("  "+line, " "))
+	}
+	fmt.Println()
+}
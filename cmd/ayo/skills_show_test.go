@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+func TestNewSkillShowJSON(t *testing.T) {
+	skill := skills.Skill{
+		Metadata: skills.Metadata{
+			Name:        "agent-discovery",
+			Description: "Find agents",
+			Path:        "/skills/agent-discovery/SKILL.md",
+			Source:      skills.SourceBuiltIn,
+			RawMetadata: map[string]string{"version": "1.0"},
+			HasScripts:  true,
+			HasAssets:   true,
+		},
+		Body: "# Agent Discovery",
+	}
+
+	details := newSkillShowJSON(skill, nil)
+	if details.Source != "built-in" || details.Version != "1.0" || details.Content != "# Agent Discovery" {
+		t.Errorf("details = %+v", details)
+	}
+	if strings.Join(details.Includes, ",") != "scripts/,assets/" {
+		t.Errorf("Includes = %q", details.Includes)
+	}
+	if len(details.RequiredByTools) != 1 || details.RequiredByTools[0].Tool != "agent_call" {
+		t.Errorf("RequiredByTools = %+v", details.RequiredByTools)
+	}
+
+	// Empty lists are encoded as [] rather than null
+	data, err := json.Marshal(details)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"agents":[]`) {
+		t.Errorf("JSON = %s, want an empty agents list", data)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func skillStatsCmd() *cobra.Command {
+	var since string
+	var until string
+	var agentFilter string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report how often each skill was in an agent's prompt",
+		Long: `Report skill usage recorded in stored sessions, per agent and skill.
+
+Usage is recorded only for agents with "log_skills": true in their
+config.json. Each turn counts every skill in the prompt. For agents with
+skill_selection, SELECTED counts the turns selection chose the skill and
+SKIPPED the turns it left the skill out; skills that are rarely chosen are
+candidates for pruning.
+
+The time range covers sessions created between --since and --until. Both
+accept a date (2006-01-02) or a number of days ago (7d). --since also
+accepts "all".`,
+		Example: `  ayo skills stats
+  ayo skills stats --since all --agent @ayo
+  ayo skills stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			filter := session.UsageFilter{}
+			var err error
+			if filter.Since, err = parseUsageTime("since", since, now); err != nil {
+				return err
+			}
+			if filter.Until, err = parseUsageTime("until", until, now); err != nil {
+				return err
+			}
+			if agentFilter != "" {
+				filter.AgentHandle = agent.NormalizeHandle(agentFilter)
+			}
+			return runSkillStats(cmd.Context(), filter, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", `start of the range: a date, a number of days ago, or "all"`)
+	cmd.Flags().StringVar(&until, "until", "", "end of the range: a date or a number of days ago (default now)")
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "only include this agent's sessions")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// runSkillStats prints the skill usage report for filter, one table per
+// agent, or as JSON.
+func runSkillStats(ctx context.Context, filter session.UsageFilter, jsonOutput bool) error {
+	services, err := session.Connect(ctx, paths.DatabasePath())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer services.Close()
+
+	report, err := services.Sessions.SkillUsageReport(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to read skill usage: %w", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(report)
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	columnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	if len(report.Skills) == 0 {
+		fmt.Println("No skill usage recorded")
+		fmt.Println(countStyle.Render(`Set "log_skills": true in an agent's config.json to record it`))
+		return nil
+	}
+
+	rangeText := "all time"
+	if !report.Since.IsZero() {
+		rangeText = report.Since.Format("2006-01-02") + " to " + report.Until.Format("2006-01-02")
+	}
+
+	width := len("SKILL")
+	for _, s := range report.Skills {
+		width = max(width, len(s.Skill))
+	}
+	row := func(name, sessions, turns, selected, skipped string) string {
+		return fmt.Sprintf("%-*s  %8s  %8s  %8s  %8s", width, name, sessions, turns, selected, skipped)
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Skill usage") + countStyle.Render("  "+rangeText))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
+	current := ""
+	for _, s := range report.Skills {
+		if s.Agent != current {
+			if current != "" {
+				fmt.Println()
+			}
+			current = s.Agent
+			fmt.Println("  " + agentStyle.Render(s.Agent))
+			fmt.Println("    " + columnStyle.Render(row("SKILL", "SESSIONS", "TURNS", "SELECTED", "SKIPPED")))
+		}
+		fmt.Println("    " + row(s.Skill, strconv.FormatInt(s.Sessions, 10), strconv.FormatInt(s.Turns, 10),
+			strconv.FormatInt(s.Selected, 10), strconv.FormatInt(s.Skipped, 10)))
+	}
+	fmt.Println()
+
+	return nil
+}
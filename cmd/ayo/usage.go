@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func newUsageCmd() *cobra.Command {
+	var since string
+	var until string
+	var agentFilter string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Report token usage and cost per agent and model",
+		Long: `Report token usage and estimated cost from stored sessions, totaled per
+agent and per model and ranked by cost.
+
+The time range covers sessions created between --since and --until. Both
+accept a date (2006-01-02) or a number of days ago (7d). --since also
+accepts "all".
+
+Sessions created before usage tracking have no usage data. They are
+counted per agent and reported under the model "unknown".`,
+		Example: `  ayo usage
+  ayo usage --since 7d
+  ayo usage --since 2026-01-01 --until 2026-02-01
+  ayo usage --agent @ayo --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			filter := session.UsageFilter{}
+			var err error
+			if filter.Since, err = parseUsageTime("since", since, now); err != nil {
+				return err
+			}
+			if filter.Until, err = parseUsageTime("until", until, now); err != nil {
+				return err
+			}
+			if agentFilter != "" {
+				filter.AgentHandle = agent.NormalizeHandle(agentFilter)
+			}
+			return runUsageReport(cmd.Context(), filter, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", `start of the range: a date, a number of days ago, or "all"`)
+	cmd.Flags().StringVar(&until, "until", "", "end of the range: a date or a number of days ago (default now)")
+	cmd.Flags().StringVarP(&agentFilter, "agent", "a", "", "only include this agent's sessions")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// parseUsageTime parses the value of the named flag: a date (2006-01-02),
+// a number of days before now (7d), or "all". Empty and "all" return the
+// zero time.
+func parseUsageTime(flag, value string, now time.Time) (time.Time, error) {
+	if value == "" || value == "all" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s value %q: use a date (2006-01-02) or a number of days (7d)", flag, value)
+	}
+	return t, nil
+}
+
+// runUsageReport prints the usage report for filter as tables or JSON.
+func runUsageReport(ctx context.Context, filter session.UsageFilter, jsonOutput bool) error {
+	services, err := session.Connect(ctx, paths.DatabasePath())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer services.Close()
+
+	report, err := services.Sessions.UsageReport(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	if jsonOutput {
+		return writeJSON(report)
+	}
+
+	if len(report.Agents) == 0 {
+		fmt.Println("No sessions found")
+		return nil
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	columnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	rangeText := "all time"
+	if !report.Since.IsZero() {
+		rangeText = report.Since.Format("2006-01-02") + " to " + report.Until.Format("2006-01-02")
+	}
+
+	width := len("unknown")
+	for _, a := range report.Agents {
+		width = max(width, len(a.Agent))
+	}
+	for _, m := range report.Models {
+		width = max(width, len(m.Model))
+	}
+	row := func(name, sessions, prompt, completion, cost string) string {
+		return fmt.Sprintf("%-*s  %8s  %10s  %10s  %10s", width, name, sessions, prompt, completion, cost)
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Usage by agent") + countStyle.Render("  "+rangeText))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
+	fmt.Println("  " + columnStyle.Render(row("AGENT", "SESSIONS", "PROMPT", "COMPLETION", "COST")))
+	untracked := int64(0)
+	for _, a := range report.Agents {
+		untracked += a.UntrackedSessions
+		prompt, completion, cost := formatUsageCount(a.PromptTokens), formatUsageCount(a.CompletionTokens), fmt.Sprintf("$%.3f", a.CostUSD)
+		if a.UntrackedSessions == a.Sessions {
+			prompt, completion, cost = session.UnknownModel, session.UnknownModel, session.UnknownModel
+		}
+		name := fmt.Sprintf("%-*s", width, a.Agent)
+		fmt.Println("  " + agentStyle.Render(name) + row("", strconv.FormatInt(a.Sessions, 10), prompt, completion, cost)[width:])
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("  Usage by model"))
+	fmt.Println(headerStyle.Render("  " + strings.Repeat("-", 60)))
+	fmt.Println("  " + columnStyle.Render(row("MODEL", "SESSIONS", "PROMPT", "COMPLETION", "COST")))
+	for _, m := range report.Models {
+		if m.Model == session.UnknownModel {
+			fmt.Println("  " + countStyle.Render(row(m.Model, strconv.FormatInt(m.Sessions, 10), "-", "-", "-")))
+			continue
+		}
+		fmt.Println("  " + row(m.Model, strconv.FormatInt(m.Sessions, 10), formatUsageCount(m.PromptTokens), formatUsageCount(m.CompletionTokens), fmt.Sprintf("$%.3f", m.CostUSD)))
+	}
+
+	if untracked > 0 {
+		noun := "sessions"
+		if untracked == 1 {
+			noun = "session"
+		}
+		fmt.Println()
+		fmt.Println("  " + countStyle.Render(fmt.Sprintf("%d %s without usage data (created before usage tracking)", untracked, noun)))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// formatUsageCount formats a token count with thousands separators.
+func formatUsageCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUsageTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "all", want: time.Time{}},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "0d", want: now},
+		{value: "2026-01-02", want: time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local)},
+		{value: "bad", wantErr: true},
+		{value: "-3d", wantErr: true},
+		{value: "01/02/2026", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseUsageTime("since", tt.value, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseUsageTime(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseUsageTime(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatUsageCount(t *testing.T) {
+	for n, want := range map[int64]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567"} {
+		if got := formatUsageCount(n); got != want {
+			t.Errorf("formatUsageCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
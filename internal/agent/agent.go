@@ -1,12 +1,15 @@
 package agent
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -16,6 +19,7 @@ import (
 	"github.com/alexcabrera/ayo/internal/builtin"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/delegates"
+	"github.com/alexcabrera/ayo/internal/memory"
 	"github.com/alexcabrera/ayo/internal/paths"
 	"github.com/alexcabrera/ayo/internal/skills"
 )
@@ -41,12 +45,37 @@ type Config struct {
 	IgnoreBuiltinSkills bool     `json:"ignore_builtin_skills,omitempty"`
 	IgnoreSharedSkills  bool     `json:"ignore_shared_skills,omitempty"`
 
+	// Inject only the skills relevant to each query
+	SkillSelection SkillSelectionConfig `json:"skill_selection,omitempty"`
+
+	// Record which skills were in the prompt of each turn, for ayo skills stats
+	LogSkills bool `json:"log_skills,omitempty"`
+
 	// Memory configuration
 	Memory MemoryConfig `json:"memory,omitempty"`
 
 	// Delegation configuration
 	// Maps task types (e.g., "coding", "research") to agent handles (e.g., "@crush")
 	Delegates map[string]string `json:"delegates,omitempty"`
+
+	// Agents this agent may invoke with agent_call, in addition to builtin
+	// and plugin agents. Entries are handles or patterns such as "@team.*";
+	// "*" allows any agent.
+	CallableAgents []string `json:"callable_agents,omitempty"`
+
+	// Environment context block at the top of the system prompt.
+	// NoEnvContext omits it; EnvContext limits it to the listed fields
+	// (datetime, os, arch, cwd, shell, home). Empty means all fields.
+	NoEnvContext bool     `json:"no_env_context,omitempty"`
+	EnvContext   []string `json:"env_context,omitempty"`
+
+	// Context files attached to every run, e.g. a coding-style guide.
+	// Relative paths are resolved against the agent directory.
+	ContextFiles []string `json:"context_files,omitempty"`
+
+	// Sampling temperature; nil uses the provider default. At 0 responses
+	// are deterministic enough to be cached (see the cache config).
+	Temperature *float64 `json:"temperature,omitempty"`
 }
 
 // MemoryConfig configures agent memory behavior.
@@ -63,6 +92,7 @@ type FormationTriggerConfig struct {
 	OnPreference   bool `json:"on_preference,omitempty"`   // User expresses preference
 	OnProjectFact  bool `json:"on_project_fact,omitempty"` // Learns something about project
 	ExplicitOnly   bool `json:"explicit_only,omitempty"`   // Only when user says "remember"
+	Review         bool `json:"review,omitempty"`          // Queue memories for "ayo memory review" instead of storing them
 }
 
 // RetrievalConfig configures memory retrieval behavior.
@@ -70,6 +100,10 @@ type RetrievalConfig struct {
 	AutoInject  bool    `json:"auto_inject,omitempty"` // Auto-retrieve at session start
 	Threshold   float32 `json:"threshold,omitempty"`   // Similarity threshold (0-1)
 	MaxMemories int     `json:"max_memories,omitempty"` // Context budget
+
+	// Weights blend similarity with confidence, recency, and access count
+	// to rank memories. Unset ranks by similarity alone.
+	Weights memory.RankingWeights `json:"weights,omitzero"`
 }
 
 // GuardrailsPrompt is the hardcoded safety guardrails applied to agents.
@@ -111,11 +145,55 @@ type Agent struct {
 	SkillsWarnings  []string
 	SkillsPrompt    string
 	ToolsPrompt     string
+	ContextFiles    []string // Resolved paths of context files attached to every run
+	ContextWarnings []string // Context files that could not be resolved
 	DelegateContext string // XML block with configured delegates
 	Config          Config
 	BuiltIn         bool
 	InputSchema     *schema.Schema // JSON schema for input validation (optional)
 	OutputSchema    *schema.Schema // JSON schema for output formatting (optional)
+
+	// Layers of CombinedSystem around System, kept so WithSystem can
+	// swap the agent's prompt without dropping guardrails or the wrapper.
+	systemBefore string
+	systemAfter  string
+}
+
+// WithoutMemory returns a copy of the agent with memory retrieval, formation,
+// and the memory tool turned off. The agent's stored config is not modified.
+func (a Agent) WithoutMemory() Agent {
+	a.Config.Memory.Enabled = false
+	if slices.Contains(a.Config.AllowedTools, "memory") {
+		a.Config.AllowedTools = slices.DeleteFunc(slices.Clone(a.Config.AllowedTools), func(t string) bool {
+			return t == "memory"
+		})
+		a.ToolsPrompt = BuildToolsPrompt(a.Config.AllowedTools)
+	}
+	return a
+}
+
+// WithSystem returns a copy of the agent with system in place of its own
+// system prompt. The environment context, guardrails, and system prefix and
+// suffix still wrap it. The agent's stored prompt is not modified.
+func (a Agent) WithSystem(system string) Agent {
+	a.System = system
+	parts := make([]string, 0, 3)
+	for _, part := range []string{a.systemBefore, system, a.systemAfter} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	a.CombinedSystem = strings.TrimSpace(strings.Join(parts, "\n\n"))
+	return a
+}
+
+// WithoutSkills returns a copy of the agent with no skills attached.
+// The agent's stored config is not modified.
+func (a Agent) WithoutSkills() Agent {
+	a.Skills = nil
+	a.SkillsWarnings = nil
+	a.SkillsPrompt = ""
+	return a
 }
 
 func NormalizeHandle(handle string) string {
@@ -125,6 +203,30 @@ func NormalizeHandle(handle string) string {
 	return "@" + handle
 }
 
+// IsHandlePattern reports whether handle contains glob wildcards, as in
+// "@team.*".
+func IsHandlePattern(handle string) bool {
+	return strings.ContainsAny(handle, "*?[")
+}
+
+// MatchHandle reports whether handle matches pattern, a handle that may
+// contain path.Match wildcards. The pattern's "@" is optional.
+func MatchHandle(pattern, handle string) bool {
+	ok, _ := path.Match(NormalizeHandle(pattern), handle)
+	return ok
+}
+
+// MatchHandles returns the handles that match pattern, in order.
+func MatchHandles(pattern string, handles []string) []string {
+	var matched []string
+	for _, handle := range handles {
+		if MatchHandle(pattern, handle) {
+			matched = append(matched, handle)
+		}
+	}
+	return matched
+}
+
 func ListHandles(cfg config.Config) ([]string, error) {
 	handleSet := make(map[string]struct{})
 
@@ -280,47 +382,45 @@ func loadFromDir(cfg config.Config, normalized string, baseDir string, isBuiltIn
 
 	// Load optional prefix and suffix for user customization
 	// These are layered on top of guardrails, not a replacement
-	var prefix, suffix string
-	if cfg.SystemPrefix != "" {
-		prefix = strings.TrimSpace(readOptional(cfg.SystemPrefix))
-	} else if prefixPath := paths.FindPromptFile("system-prefix.md"); prefixPath != "" {
-		prefix = strings.TrimSpace(readOptional(prefixPath))
+	prefixFiles := cfg.SystemPrefix
+	if len(prefixFiles) == 0 {
+		prefixFiles = paths.FindPromptFiles("system-prefix.md")
 	}
-	if cfg.SystemSuffix != "" {
-		suffix = strings.TrimSpace(readOptional(cfg.SystemSuffix))
-	} else if suffixPath := paths.FindPromptFile("system-suffix.md"); suffixPath != "" {
-		suffix = strings.TrimSpace(readOptional(suffixPath))
+	suffixFiles := cfg.SystemSuffix
+	if len(suffixFiles) == 0 {
+		suffixFiles = paths.FindPromptFiles("system-suffix.md")
 	}
+	prefix := readLayers(prefixFiles)
+	suffix := readLayers(suffixFiles)
 
 	// Build environment context block (placed at top of system prompt)
-	envContext := buildEnvContext()
+	var envContext string
+	if !agentConfig.NoEnvContext {
+		envContext, err = buildEnvContext(agentConfig.EnvContext)
+		if err != nil {
+			return agent, err
+		}
+	}
 
 	// Assemble system prompt: envContext + guardrails + prefix + agent + suffix
-	combinedParts := make([]string, 0, 5)
-	combinedParts = append(combinedParts, envContext)
+	beforeParts := make([]string, 0, 3)
+	if envContext != "" {
+		beforeParts = append(beforeParts, envContext)
+	}
 	if guardrailsEnabled {
-		combinedParts = append(combinedParts, GuardrailsPrompt)
+		beforeParts = append(beforeParts, GuardrailsPrompt)
 	}
 	if prefix != "" {
-		combinedParts = append(combinedParts, prefix)
+		beforeParts = append(beforeParts, prefix)
 	}
-	combinedParts = append(combinedParts, agentSystem)
+	systemBefore := strings.Join(beforeParts, "\n\n")
+	combinedParts := append(slices.Clone(beforeParts), agentSystem)
 	if suffix != "" {
 		combinedParts = append(combinedParts, suffix)
 	}
 	combined := strings.TrimSpace(strings.Join(combinedParts, "\n\n"))
 
-	agentSkillsDir := filepath.Join(dir, "skills")
-	discovery := skills.DiscoverForAgent(
-		agentSkillsDir,
-		paths.SkillsDirs(),
-		skills.DiscoveryFilterConfig{
-			IncludeSkills: agentConfig.Skills,
-			ExcludeSkills: agentConfig.ExcludeSkills,
-			IgnoreBuiltin: agentConfig.IgnoreBuiltinSkills,
-			IgnoreShared:  agentConfig.IgnoreSharedSkills,
-		},
-	)
+	discovery := skills.DiscoverAll(skillDiscoveryOptions(dir, agentConfig))
 	skillsPrompt := buildSkillsPrompt(discovery.Skills)
 	toolsPrompt := BuildToolsPrompt(agentConfig.AllowedTools)
 
@@ -339,6 +439,8 @@ func loadFromDir(cfg config.Config, normalized string, baseDir string, isBuiltIn
 	// Build delegate context from all sources
 	delegateContext := buildDelegateContext(cfg, agentConfig.Delegates)
 
+	contextFiles, contextWarnings := resolveContextFiles(dir, agentConfig.ContextFiles)
+
 	agent = Agent{
 		Handle:          normalized,
 		Dir:             dir,
@@ -349,11 +451,15 @@ func loadFromDir(cfg config.Config, normalized string, baseDir string, isBuiltIn
 		SkillsWarnings:  discovery.Warnings,
 		SkillsPrompt:    skillsPrompt,
 		ToolsPrompt:     toolsPrompt,
+		ContextFiles:    contextFiles,
+		ContextWarnings: contextWarnings,
 		DelegateContext: delegateContext,
 		Config:          agentConfig,
 		BuiltIn:         isBuiltIn,
 		InputSchema:     inputSchema,
 		OutputSchema:    outputSchema,
+		systemBefore:    systemBefore,
+		systemAfter:     suffix,
 	}
 	return agent, nil
 }
@@ -365,6 +471,23 @@ func resolveModel(cfg config.Config, agentConfig Config) string {
 	return cfg.DefaultModel
 }
 
+// readLayers reads prompt files and joins them in order, skipping files
+// that are missing or empty. A leading "~/" expands to the home directory.
+func readLayers(files []string) string {
+	var layers []string
+	for _, path := range files {
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if layer := strings.TrimSpace(readOptional(path)); layer != "" {
+			layers = append(layers, layer)
+		}
+	}
+	return strings.Join(layers, "\n\n")
+}
+
 func readOptional(path string) string {
 	if path == "" {
 		return ""
@@ -405,38 +528,64 @@ func buildDelegateContext(cfg config.Config, agentDelegates map[string]string) s
 	return b.String()
 }
 
+// EnvContextFields are the fields of the environment context block, in the
+// order they are written.
+var EnvContextFields = []string{"datetime", "os", "arch", "cwd", "shell", "home"}
+
 // buildEnvContext returns environment information for the system prompt.
 // This is placed at the top so the model has immediate context about
-// the runtime environment and current time.
-func buildEnvContext() string {
+// the runtime environment and current time. Only the listed fields are
+// included; an empty list includes all of them.
+func buildEnvContext(fields []string) (string, error) {
+	for _, f := range fields {
+		if !slices.Contains(EnvContextFields, f) {
+			return "", fmt.Errorf("unknown env_context field %q (use %s)", f, strings.Join(EnvContextFields, ", "))
+		}
+	}
+	include := func(field string) bool {
+		return len(fields) == 0 || slices.Contains(fields, field)
+	}
+
 	var b strings.Builder
 	b.WriteString("<environment>\n")
 
 	// Current datetime
-	now := time.Now()
-	b.WriteString(fmt.Sprintf("datetime: %s\n", now.Format("2006-01-02 15:04:05 MST")))
+	if include("datetime") {
+		now := time.Now()
+		b.WriteString(fmt.Sprintf("datetime: %s\n", now.Format("2006-01-02 15:04:05 MST")))
+	}
 
 	// Platform info
-	b.WriteString(fmt.Sprintf("os: %s\n", runtime.GOOS))
-	b.WriteString(fmt.Sprintf("arch: %s\n", runtime.GOARCH))
+	if include("os") {
+		b.WriteString(fmt.Sprintf("os: %s\n", runtime.GOOS))
+	}
+	if include("arch") {
+		b.WriteString(fmt.Sprintf("arch: %s\n", runtime.GOARCH))
+	}
 
 	// Working directory
-	if wd, err := os.Getwd(); err == nil {
-		b.WriteString(fmt.Sprintf("cwd: %s\n", wd))
+	if include("cwd") {
+		if wd, err := os.Getwd(); err == nil {
+			b.WriteString(fmt.Sprintf("cwd: %s\n", wd))
+		}
 	}
 
 	// Shell (from environment)
-	if shell := os.Getenv("SHELL"); shell != "" {
-		b.WriteString(fmt.Sprintf("shell: %s\n", shell))
+	if include("shell") {
+		if shell := os.Getenv("SHELL"); shell != "" {
+			b.WriteString(fmt.Sprintf("shell: %s\n", shell))
+		}
 	}
 
 	// Home directory
-	if home, err := os.UserHomeDir(); err == nil {
-		b.WriteString(fmt.Sprintf("home: %s\n", home))
+	if include("home") {
+		if home, err := os.UserHomeDir(); err == nil {
+			b.WriteString(fmt.Sprintf("home: %s\n", home))
+		}
 	}
 
 	b.WriteString("</environment>")
-	return b.String()
+	return b.String(), nil
 }
 
 // DefaultAgent is the default agent handle used when no agent is specified.
@@ -552,14 +701,44 @@ func copySchemaFile(srcPath, agentDir, destName string) error {
 	return nil
 }
 
+// loadAgentConfig loads config.json from an agent directory, expanding
+// environment variable references in its string values.
 func loadAgentConfig(dir string) (Config, error) {
 	cfg := Config{}
-	configPath := filepath.Join(dir, "config.json")
-	data, err := os.ReadFile(configPath)
+	data, err := readAgentConfig(dir)
+	if err != nil || data == nil {
+		return cfg, err
+	}
+
+	// Expand ${VAR} and ${VAR:-fallback} in string values
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return cfg, err
+	}
+	expanded, err := expandEnvValues(doc)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return cfg, nil
-		}
+		return cfg, fmt.Errorf("%s: %w", filepath.Join(dir, "config.json"), err)
+	}
+	if data, err = json.Marshal(expanded); err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Memory.Retrieval.Weights.Validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", filepath.Join(dir, "config.json"), err)
+	}
+	return cfg, nil
+}
+
+// loadRawAgentConfig loads config.json without expanding variable
+// references, for callers that rewrite the file.
+func loadRawAgentConfig(dir string) (Config, error) {
+	cfg := Config{}
+	data, err := readAgentConfig(dir)
+	if err != nil || data == nil {
 		return cfg, err
 	}
 	if err := json.Unmarshal(data, &cfg); err != nil {
@@ -568,6 +747,16 @@ func loadAgentConfig(dir string) (Config, error) {
 	return cfg, nil
 }
 
+// readAgentConfig returns the contents of config.json in dir, or nil if
+// there is none.
+func readAgentConfig(dir string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
 // loadInputSchema loads the input.jsonschema file from the agent directory if it exists.
 // Returns nil if no schema file is present.
 func loadInputSchema(dir string) (*schema.Schema, error) {
@@ -697,6 +886,15 @@ func (a *Agent) ValidateInput(input string) error {
 		}
 	}
 
+	// The schema validator treats formats as annotations; enforce the common ones
+	if err := validateFormats(parsed, a.InputSchema); err != nil {
+		return &InputValidationError{
+			Input:      input,
+			ParseError: err,
+			Schema:     a.InputSchema,
+		}
+	}
+
 	return nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/skills"
 )
 
 func TestDefaultAgent(t *testing.T) {
@@ -22,13 +23,13 @@ func TestLoadCombinesPrefixAgentSuffix(t *testing.T) {
 	home := t.TempDir()
 	cfg := config.Config{
 		AgentsDir:    filepath.Join(home, "ayo", "agents"),
-		SystemPrefix: filepath.Join(home, "ayo", "prompts", "system-prefix.md"),
-		SystemSuffix: filepath.Join(home, "ayo", "prompts", "system-suffix.md"),
+		SystemPrefix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "system-prefix.md")},
+		SystemSuffix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "system-suffix.md")},
 		DefaultModel: "gpt-5.2",
 	}
 
-	mustWrite(t, cfg.SystemPrefix, "PREFIX")
-	mustWrite(t, cfg.SystemSuffix, "SUFFIX")
+	mustWrite(t, cfg.SystemPrefix[0], "PREFIX")
+	mustWrite(t, cfg.SystemSuffix[0], "SUFFIX")
 
 	agentDir := filepath.Join(cfg.AgentsDir, "@alice")
 	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
@@ -55,12 +56,86 @@ func TestLoadCombinesPrefixAgentSuffix(t *testing.T) {
 	}
 }
 
+func TestWithSystemKeepsWrapper(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{
+		AgentsDir:    filepath.Join(home, "ayo", "agents"),
+		SystemPrefix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "system-prefix.md")},
+		SystemSuffix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "system-suffix.md")},
+		DefaultModel: "gpt-5.2",
+	}
+
+	mustWrite(t, cfg.SystemPrefix[0], "PREFIX")
+	mustWrite(t, cfg.SystemSuffix[0], "SUFFIX")
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@alice")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	writeAgentConfig(t, agentDir, Config{NoEnvContext: true})
+
+	ag, err := Load(cfg, "@alice")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	override := ag.WithSystem("OVERRIDE")
+	if override.System != "OVERRIDE" || ag.System != "AGENT" {
+		t.Fatalf("System = %q, original %q", override.System, ag.System)
+	}
+	want := strings.Replace(ag.CombinedSystem, "AGENT", "OVERRIDE", 1)
+	if override.CombinedSystem != want {
+		t.Errorf("combined = %q, want %q", override.CombinedSystem, want)
+	}
+	if !strings.HasPrefix(override.CombinedSystem, "<guardrails>") || !strings.HasSuffix(override.CombinedSystem, "PREFIX\n\nOVERRIDE\n\nSUFFIX") {
+		t.Errorf("override lost its wrapper:\n%s", override.CombinedSystem)
+	}
+
+	// An agent without layers gets the override alone
+	if got := (Agent{}).WithSystem("ONLY").CombinedSystem; got != "ONLY" {
+		t.Errorf("bare combined = %q", got)
+	}
+}
+
+func TestLoadMergesPromptLayersInOrder(t *testing.T) {
+	home := t.TempDir()
+	prompts := filepath.Join(home, "ayo", "prompts")
+	cfg := config.Config{
+		AgentsDir: filepath.Join(home, "ayo", "agents"),
+		SystemPrefix: config.PromptFiles{
+			filepath.Join(prompts, "org-prefix.md"),
+			filepath.Join(prompts, "missing-prefix.md"),
+			filepath.Join(prompts, "team-prefix.md"),
+		},
+		SystemSuffix: config.PromptFiles{
+			filepath.Join(prompts, "org-suffix.md"),
+			filepath.Join(prompts, "project-suffix.md"),
+		},
+		DefaultModel: "gpt-5.2",
+	}
+	mustWrite(t, cfg.SystemPrefix[0], "ORG PREFIX")
+	mustWrite(t, cfg.SystemPrefix[2], "TEAM PREFIX")
+	mustWrite(t, cfg.SystemSuffix[0], "ORG SUFFIX")
+	mustWrite(t, cfg.SystemSuffix[1], "PROJECT SUFFIX")
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@dana")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	writeAgentConfig(t, agentDir, Config{})
+
+	ag, err := Load(cfg, "@dana")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := "ORG PREFIX\n\nTEAM PREFIX\n\nAGENT\n\nORG SUFFIX\n\nPROJECT SUFFIX"
+	if !strings.HasSuffix(ag.CombinedSystem, want) {
+		t.Fatalf("combined should end with the layers in order, got:\n%s", ag.CombinedSystem)
+	}
+}
+
 func TestLoadHandlesMissingPrefixSuffix(t *testing.T) {
 	home := t.TempDir()
 	cfg := config.Config{
 		AgentsDir:    filepath.Join(home, "ayo", "agents"),
-		SystemPrefix: filepath.Join(home, "ayo", "prompts", "prefix_missing.md"),
-		SystemSuffix: filepath.Join(home, "ayo", "prompts", "suffix_missing.md"),
+		SystemPrefix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "prefix_missing.md")},
+		SystemSuffix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "suffix_missing.md")},
 		DefaultModel: "gpt-5.2",
 	}
 
@@ -88,6 +163,67 @@ func TestLoadHandlesMissingPrefixSuffix(t *testing.T) {
 	}
 }
 
+func TestLoadWithoutEnvContext(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{
+		AgentsDir:    filepath.Join(home, "ayo", "agents"),
+		SystemPrefix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "prefix_missing.md")},
+		SystemSuffix: config.PromptFiles{filepath.Join(home, "ayo", "prompts", "suffix_missing.md")},
+	}
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@dave")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	writeAgentConfig(t, agentDir, Config{NoEnvContext: true})
+
+	ag, err := Load(cfg, "@dave")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if strings.Contains(ag.CombinedSystem, "<environment>") {
+		t.Fatalf("combined should omit the environment block, got:\n%s", ag.CombinedSystem)
+	}
+	if !strings.HasPrefix(ag.CombinedSystem, "<guardrails>") {
+		t.Fatalf("combined should start with guardrails, got:\n%s", ag.CombinedSystem)
+	}
+}
+
+func TestLoadWithEnvContextSubset(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "ayo", "agents")}
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@erin")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	writeAgentConfig(t, agentDir, Config{EnvContext: []string{"datetime", "os"}})
+
+	ag, err := Load(cfg, "@erin")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, want := range []string{"datetime:", "os:"} {
+		if !strings.Contains(ag.CombinedSystem, want) {
+			t.Errorf("combined should include %q, got:\n%s", want, ag.CombinedSystem)
+		}
+	}
+	for _, unwanted := range []string{"cwd:", "home:", "arch:"} {
+		if strings.Contains(ag.CombinedSystem, unwanted) {
+			t.Errorf("combined should not include %q, got:\n%s", unwanted, ag.CombinedSystem)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownEnvContextField(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "ayo", "agents")}
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@frank")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	writeAgentConfig(t, agentDir, Config{EnvContext: []string{"hostname"}})
+
+	if _, err := Load(cfg, "@frank"); err == nil || !strings.Contains(err.Error(), `unknown env_context field "hostname"`) {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
+
 func mustWrite(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -362,6 +498,84 @@ func TestValidateInputNoSchema(t *testing.T) {
 	}
 }
 
+func TestValidateInputFormats(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{
+		AgentsDir:    filepath.Join(home, "ayo", "agents"),
+		DefaultModel: "gpt-5.2",
+	}
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@format-agent")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "Agent with formats")
+	writeAgentConfig(t, agentDir, Config{})
+
+	schema := `{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string", "format": "email"},
+			"due": {"type": "string", "format": "date"},
+			"links": {"type": "array", "items": {"type": "string", "format": "uri"}},
+			"color": {"type": "string", "format": "hex-color"}
+		}
+	}`
+	mustWrite(t, filepath.Join(agentDir, "input.jsonschema"), schema)
+
+	ag, err := Load(cfg, "@format-agent")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "valid formats",
+			input: `{"email": "bob@example.com", "due": "2024-01-31", "links": ["https://example.com"]}`,
+		},
+		{
+			name:  "unknown format is not checked",
+			input: `{"color": "not a color"}`,
+		},
+		{
+			name:    "invalid email",
+			input:   `{"email": "bob"}`,
+			wantErr: `field "email" must be an email address (format "email"), got "bob"`,
+		},
+		{
+			name:    "invalid date",
+			input:   `{"due": "31/01/2024"}`,
+			wantErr: `field "due" must be a date`,
+		},
+		{
+			name:    "invalid uri in array",
+			input:   `{"links": ["https://example.com", "example.com"]}`,
+			wantErr: `field "links[1]" must be an absolute URI`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ag.ValidateInput(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateInput() = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *InputValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateInput() = %v, want InputValidationError", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateInput() = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadOutputSchema(t *testing.T) {
 	home := t.TempDir()
 	cfg := config.Config{
@@ -820,3 +1034,117 @@ func TestLoadWithGuardrailsDisabled(t *testing.T) {
 		t.Errorf("combined should contain agent system, got:\n%s", ag.CombinedSystem)
 	}
 }
+
+func TestLoadResolvesContextFiles(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{
+		AgentsDir:    filepath.Join(home, "ayo", "agents"),
+		DefaultModel: "gpt-5.2",
+	}
+
+	agentDir := filepath.Join(cfg.AgentsDir, "@context-agent")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "Agent with context files")
+	mustWrite(t, filepath.Join(agentDir, "docs", "style.md"), "Use tabs")
+	absFile := filepath.Join(home, "shared.txt")
+	mustWrite(t, absFile, "Shared notes")
+	writeAgentConfig(t, agentDir, Config{
+		ContextFiles: []string{"docs/style.md", absFile, "missing.md", "docs"},
+	})
+
+	ag, err := Load(cfg, "@context-agent")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	want := []string{filepath.Join(agentDir, "docs", "style.md"), absFile}
+	if len(ag.ContextFiles) != len(want) {
+		t.Fatalf("ContextFiles = %v, want %v", ag.ContextFiles, want)
+	}
+	for i := range want {
+		if ag.ContextFiles[i] != want[i] {
+			t.Errorf("ContextFiles[%d] = %q, want %q", i, ag.ContextFiles[i], want[i])
+		}
+	}
+
+	if len(ag.ContextWarnings) != 2 {
+		t.Fatalf("ContextWarnings = %v, want 2 warnings", ag.ContextWarnings)
+	}
+	if !strings.Contains(ag.ContextWarnings[0], "missing.md not found") {
+		t.Errorf("ContextWarnings[0] = %q, want missing file warning", ag.ContextWarnings[0])
+	}
+	if !strings.Contains(ag.ContextWarnings[1], "docs is not a regular file") {
+		t.Errorf("ContextWarnings[1] = %q, want directory warning", ag.ContextWarnings[1])
+	}
+}
+
+func TestWithoutMemory(t *testing.T) {
+	ag := Agent{
+		ToolsPrompt: BuildToolsPrompt([]string{"bash", "memory"}),
+		Config: Config{
+			AllowedTools: []string{"bash", "memory"},
+			Memory:       MemoryConfig{Enabled: true},
+		},
+	}
+
+	isolated := ag.WithoutMemory()
+	if isolated.Config.Memory.Enabled {
+		t.Error("memory should be disabled")
+	}
+	if len(isolated.Config.AllowedTools) != 1 || isolated.Config.AllowedTools[0] != "bash" {
+		t.Errorf("memory tool should be removed, got %v", isolated.Config.AllowedTools)
+	}
+	if isolated.ToolsPrompt != BuildToolsPrompt([]string{"bash"}) {
+		t.Error("tools prompt should be rebuilt without the memory tool")
+	}
+
+	// The original agent is untouched
+	if !ag.Config.Memory.Enabled || len(ag.Config.AllowedTools) != 2 {
+		t.Errorf("original agent was modified: %+v", ag.Config)
+	}
+}
+
+func TestWithoutSkills(t *testing.T) {
+	ag := Agent{
+		Skills:         []skills.Metadata{{Name: "debugging"}},
+		SkillsWarnings: []string{"skill warning"},
+		SkillsPrompt:   "<available_skills>...</available_skills>",
+	}
+
+	isolated := ag.WithoutSkills()
+	if len(isolated.Skills) != 0 || isolated.SkillsPrompt != "" || len(isolated.SkillsWarnings) != 0 {
+		t.Errorf("skills should be cleared, got %+v", isolated)
+	}
+	if ag.SkillsPrompt == "" {
+		t.Error("original agent was modified")
+	}
+}
+
+func TestMatchHandle(t *testing.T) {
+	tests := []struct {
+		pattern string
+		handle  string
+		want    bool
+	}{
+		{"@team.*", "@team.foo", true},
+		{"team.*", "@team.bar", true},
+		{"@team.*", "@teams", false},
+		{"@team.?", "@team.a", true},
+		{"@*", "@ayo", true},
+		{"@*", "", false},
+		{"@[ab]*", "@bob", true},
+		{"@team.foo", "@team.foo", true},
+	}
+	for _, tt := range tests {
+		if got := MatchHandle(tt.pattern, tt.handle); got != tt.want {
+			t.Errorf("MatchHandle(%q, %q) = %v, want %v", tt.pattern, tt.handle, got, tt.want)
+		}
+	}
+
+	if !IsHandlePattern("@team.*") || IsHandlePattern("@team.foo") {
+		t.Error("IsHandlePattern misclassified a handle")
+	}
+	got := MatchHandles("@team.*", []string{"@ayo", "@team.bar", "@team.foo", ""})
+	if len(got) != 2 || got[0] != "@team.bar" || got[1] != "@team.foo" {
+		t.Errorf("MatchHandles = %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// CloneSource is an existing agent to copy into a new one.
+type CloneSource struct {
+	Handle string
+	Dir    string
+
+	// Config as written, with ${VAR} references unexpanded. SystemFile is
+	// cleared: the clone keeps its system prompt in system.md.
+	Config Config
+
+	// System is the agent's own system prompt, without guardrails, skills,
+	// or other layers added at load time.
+	System string
+}
+
+// LoadCloneSource finds the agent handle, which may be a user, built-in, or
+// plugin agent, and reads what a clone copies from it.
+func LoadCloneSource(cfg config.Config, handle string) (CloneSource, error) {
+	ag, err := Load(cfg, handle)
+	if err != nil {
+		return CloneSource{}, err
+	}
+	raw, err := loadRawAgentConfig(ag.Dir)
+	if err != nil {
+		return CloneSource{}, fmt.Errorf("read %s: %w", filepath.Join(ag.Dir, "config.json"), err)
+	}
+	raw.SystemFile = ""
+	return CloneSource{Handle: ag.Handle, Dir: ag.Dir, Config: raw, System: ag.System}, nil
+}
+
+// CopyFiles copies the files of the source agent into dir: its schemas,
+// agent-local skills, context files, and anything else in its directory.
+// config.json is left out; the clone's config is written separately.
+func (s CloneSource) CopyFiles(dir string) error {
+	return filepath.WalkDir(s.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.Dir, p)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0o755)
+		}
+		if rel == "config.json" || !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		// Built-in agents may be read-only; the clone is the user's to edit
+		return os.WriteFile(dst, data, info.Mode().Perm()|0o600)
+	})
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestCloneAgent(t *testing.T) {
+	t.Setenv("AYO_TEST_MODEL", "gpt-5.2")
+
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "agents")}
+	srcDir := filepath.Join(cfg.AgentsDir, "@source")
+	mustWrite(t, filepath.Join(srcDir, "config.json"), `{
+  "model": "${AYO_TEST_MODEL}",
+  "system_file": "prompt.md",
+  "description": "Reviews code",
+  "skills": ["local-skill"],
+  "memory": {"enabled": true}
+}`)
+	mustWrite(t, filepath.Join(srcDir, "prompt.md"), "REVIEW CAREFULLY")
+	mustWrite(t, filepath.Join(srcDir, "output.jsonschema"), `{"type": "object"}`)
+	mustWrite(t, filepath.Join(srcDir, "skills", "local-skill", "SKILL.md"), "---\nname: local-skill\ndescription: A local skill\n---\nDo it.")
+
+	src, err := LoadCloneSource(cfg, "@source")
+	if err != nil {
+		t.Fatalf("LoadCloneSource: %v", err)
+	}
+	if src.Config.Model != "${AYO_TEST_MODEL}" || src.Config.SystemFile != "" || !src.Config.Memory.Enabled {
+		t.Errorf("config = %+v, want the raw config without system_file", src.Config)
+	}
+	if src.System != "REVIEW CAREFULLY" {
+		t.Errorf("system = %q", src.System)
+	}
+
+	dstDir := filepath.Join(cfg.AgentsDir, "@copy")
+	if err := src.CopyFiles(dstDir); err != nil {
+		t.Fatalf("CopyFiles: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "config.json")); !os.IsNotExist(err) {
+		t.Error("config.json should not be copied")
+	}
+
+	src.Config.Description = "Reviews code quickly"
+	ag, err := Save(cfg, "@copy", src.Config, src.System)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if ag.Model != "gpt-5.2" || ag.System != "REVIEW CAREFULLY" || ag.Config.Description != "Reviews code quickly" {
+		t.Errorf("clone = model %q, system %q, description %q", ag.Model, ag.System, ag.Config.Description)
+	}
+	if !ag.HasOutputSchema() {
+		t.Error("clone should have its own copy of the output schema")
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "skills", "local-skill", "SKILL.md")); err != nil {
+		t.Errorf("agent-local skill should be copied: %v", err)
+	}
+
+	// The source is untouched
+	if srcAg, err := Load(cfg, "@source"); err != nil || srcAg.Config.Description != "Reviews code" {
+		t.Errorf("source changed: %+v, %v", srcAg.Config, err)
+	}
+}
+
+func TestLoadCloneSourceMissing(t *testing.T) {
+	cfg := config.Config{AgentsDir: filepath.Join(t.TempDir(), "agents")}
+	if _, err := LoadCloneSource(cfg, "@nope"); err == nil {
+		t.Error("expected an error for a missing agent")
+	}
+}
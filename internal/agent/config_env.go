@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// expandEnv replaces ${VAR} and ${VAR:-fallback} references in s with
+// values from the environment. The fallback is used when VAR is unset or
+// empty; a ${VAR} reference to an unset variable is an error naming it.
+// Strings without ${ are returned unchanged.
+func expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	rest := s
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		b.WriteString(rest[:start])
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		ref := rest[start+2 : start+end]
+		rest = rest[start+end+1:]
+
+		name, fallback, hasFallback := strings.Cut(ref, ":-")
+		if !validEnvName(name) {
+			return "", fmt.Errorf("invalid variable name %q in %q", name, s)
+		}
+		value, set := os.LookupEnv(name)
+		switch {
+		case hasFallback && value == "":
+			value = fallback
+		case !set:
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		b.WriteString(value)
+	}
+}
+
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// expandEnvValues expands variable references in every string of a decoded
+// JSON value, including object values and array elements.
+func expandEnvValues(v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return expandEnv(val)
+	case map[string]any:
+		for k, item := range val {
+			expanded, err := expandEnvValues(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			val[k] = expanded
+		}
+		return val, nil
+	case []any:
+		for i, item := range val {
+			expanded, err := expandEnvValues(item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = expanded
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("AYO_TEST_MODEL", "gpt-5.2")
+	t.Setenv("AYO_TEST_EMPTY", "")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"$HOME and $AYO_TEST_MODEL", "$HOME and $AYO_TEST_MODEL"},
+		{"${AYO_TEST_MODEL}", "gpt-5.2"},
+		{"prompts/${AYO_TEST_MODEL}.md", "prompts/gpt-5.2.md"},
+		{"${AYO_TEST_UNSET:-fallback}", "fallback"},
+		{"${AYO_TEST_EMPTY:-fallback}", "fallback"},
+		{"${AYO_TEST_MODEL:-fallback}", "gpt-5.2"},
+		{"${AYO_TEST_UNSET:-}", ""},
+		{"${AYO_TEST_EMPTY}", ""},
+		{"${AYO_TEST_MODEL}-${AYO_TEST_UNSET:-mini}", "gpt-5.2-mini"},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in)
+		if err != nil {
+			t.Errorf("expandEnv(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpandEnvErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"${AYO_TEST_UNSET}", "environment variable AYO_TEST_UNSET is not set"},
+		{"${AYO_TEST_UNSET", "unterminated variable reference"},
+		{"${}", `invalid variable name ""`},
+		{"${1X}", `invalid variable name "1X"`},
+	}
+	for _, tt := range tests {
+		if _, err := expandEnv(tt.in); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expandEnv(%q) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestLoadExpandsEnvInConfig(t *testing.T) {
+	t.Setenv("AYO_TEST_MODEL", "gpt-5.2")
+	t.Setenv("AYO_TEST_CODER", "@crush")
+
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "agents")}
+	agentDir := filepath.Join(cfg.AgentsDir, "@env")
+	mustWrite(t, filepath.Join(agentDir, "prompt-dev.md"), "DEV PROMPT")
+	mustWrite(t, filepath.Join(agentDir, "config.json"), `{
+  "model": "${AYO_TEST_MODEL}",
+  "system_file": "prompt-${AYO_TEST_STAGE:-dev}.md",
+  "delegates": {"coding": "${AYO_TEST_CODER}"},
+  "temperature": 0.2
+}`)
+
+	ag, err := Load(cfg, "@env")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if ag.Model != "gpt-5.2" {
+		t.Errorf("model = %q, want gpt-5.2", ag.Model)
+	}
+	if ag.Config.Delegates["coding"] != "@crush" {
+		t.Errorf("delegates = %v", ag.Config.Delegates)
+	}
+	if !strings.Contains(ag.CombinedSystem, "DEV PROMPT") {
+		t.Errorf("system file should fall back to prompt-dev.md, got:\n%s", ag.CombinedSystem)
+	}
+	if ag.Config.Temperature == nil || *ag.Config.Temperature != 0.2 {
+		t.Errorf("temperature = %v, want 0.2", ag.Config.Temperature)
+	}
+
+	// The raw config keeps the references for rewriting
+	raw, err := loadRawAgentConfig(agentDir)
+	if err != nil {
+		t.Fatalf("loadRawAgentConfig: %v", err)
+	}
+	if raw.Model != "${AYO_TEST_MODEL}" {
+		t.Errorf("raw model = %q", raw.Model)
+	}
+}
+
+func TestLoadReportsUnsetEnvInConfig(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "agents")}
+	agentDir := filepath.Join(cfg.AgentsDir, "@env")
+	mustWrite(t, filepath.Join(agentDir, "system.md"), "AGENT")
+	mustWrite(t, filepath.Join(agentDir, "config.json"), `{"model": "${AYO_TEST_UNSET_MODEL}"}`)
+
+	_, err := Load(cfg, "@env")
+	if err == nil || !strings.Contains(err.Error(), "model: environment variable AYO_TEST_UNSET_MODEL is not set") {
+		t.Errorf("Load error = %v, want the unset variable named", err)
+	}
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveContextFiles resolves an agent's context files relative to its
+// directory. Files that don't exist or aren't regular files are dropped and
+// reported as warnings so the agent still loads.
+func resolveContextFiles(dir string, files []string) (resolved []string, warnings []string) {
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+
+		path := file
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("context file %s not found", file))
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			warnings = append(warnings, fmt.Sprintf("context file %s is not a regular file", file))
+			continue
+		}
+		resolved = append(resolved, path)
+	}
+	return resolved, warnings
+}
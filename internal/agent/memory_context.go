@@ -15,7 +15,8 @@ type MemoryContext struct {
 }
 
 // BuildMemoryContext retrieves relevant memories and formats them for prompt injection.
-func BuildMemoryContext(ctx context.Context, svc *memory.Service, agentHandle, pathScope, query string, cfg MemoryConfig) (*MemoryContext, error) {
+// Path-scoped memories are only retrieved when workDir is within their scope.
+func BuildMemoryContext(ctx context.Context, svc *memory.Service, agentHandle, workDir, query string, cfg MemoryConfig) (*MemoryContext, error) {
 	if svc == nil || !cfg.Enabled {
 		return nil, nil
 	}
@@ -43,9 +44,10 @@ func BuildMemoryContext(ctx context.Context, svc *memory.Service, agentHandle, p
 
 	results, err := svc.Search(ctx, query, memory.SearchOptions{
 		AgentHandle: agentFilter,
-		PathScope:   pathScope,
+		PathScope:   workDir,
 		Threshold:   threshold,
 		Limit:       maxMems,
+		Weights:     cfg.Retrieval.Weights,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MergeKey is the key of a source agent's output in the object a merge
+// agent receives: the source's handle without the @.
+func MergeKey(handle string) string {
+	return strings.TrimPrefix(handle, "@")
+}
+
+// CheckMergeSources reports why the outputs of sources, keyed by MergeKey,
+// would not fit merger's input schema: a key the schema has no property
+// for, an output the property can't hold, or a required property no source
+// provides. A merger without an input schema accepts any sources.
+func CheckMergeSources(merger *Agent, sources []Agent) []string {
+	in := merger.InputSchema
+	if in == nil {
+		return nil
+	}
+	if in.Type != "object" {
+		return []string{fmt.Sprintf("%s's input schema must be an object to receive merged outputs, not %s", merger.Handle, in.Type)}
+	}
+
+	var problems []string
+	keys := make([]string, len(sources))
+	for i, src := range sources {
+		key := MergeKey(src.Handle)
+		keys[i] = key
+		prop, ok := in.Properties[key]
+		if !ok {
+			if len(in.Properties) > 0 {
+				problems = append(problems, fmt.Sprintf("%s's input schema has no %q property for %s's output", merger.Handle, key, src.Handle))
+			}
+			continue
+		}
+		if prop == nil {
+			continue
+		}
+		if src.OutputSchema == nil {
+			if prop.Type != "" && prop.Type != "string" {
+				problems = append(problems, fmt.Sprintf("%s has no output schema, so its output is text, but %s expects %q to be %s", src.Handle, merger.Handle, key, prop.Type))
+			}
+			continue
+		}
+		if checkSchemaCompatibility(src.OutputSchema, prop) == CompatibilityNone {
+			problems = append(problems, fmt.Sprintf("%s's output schema doesn't match the %q property of %s's input schema", src.Handle, key, merger.Handle))
+		}
+	}
+	for _, required := range in.Required {
+		if !slices.Contains(keys, required) {
+			problems = append(problems, fmt.Sprintf("%s requires %q, which no source provides", merger.Handle, required))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"charm.land/fantasy/schema"
+)
+
+func TestMergeKey(t *testing.T) {
+	if got := MergeKey("@research"); got != "research" {
+		t.Errorf("MergeKey(@research) = %q", got)
+	}
+	if got := MergeKey("ayo.coding"); got != "ayo.coding" {
+		t.Errorf("MergeKey(ayo.coding) = %q", got)
+	}
+}
+
+func TestCheckMergeSources(t *testing.T) {
+	findings := &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{"findings": {Type: "array"}},
+		Required:   []string{"findings"},
+	}
+	merger := &Agent{
+		Handle: "@editor",
+		InputSchema: &schema.Schema{
+			Type: "object",
+			Properties: map[string]*schema.Schema{
+				"research": findings,
+				"critic":   {Type: "string"},
+			},
+			Required: []string{"research", "critic"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		merger  *Agent
+		sources []Agent
+		want    []string
+	}{
+		{
+			name:   "no input schema",
+			merger: &Agent{Handle: "@any"},
+			sources: []Agent{
+				{Handle: "@a"},
+				{Handle: "@b", OutputSchema: findings},
+			},
+		},
+		{
+			name:   "compatible",
+			merger: merger,
+			sources: []Agent{
+				{Handle: "@research", OutputSchema: findings},
+				{Handle: "@critic"},
+			},
+		},
+		{
+			name:   "not an object",
+			merger: &Agent{Handle: "@list", InputSchema: &schema.Schema{Type: "array"}},
+			want:   []string{"must be an object"},
+		},
+		{
+			name:   "unknown key",
+			merger: merger,
+			sources: []Agent{
+				{Handle: "@research", OutputSchema: findings},
+				{Handle: "@critic"},
+				{Handle: "@extra"},
+			},
+			want: []string{`no "extra" property`},
+		},
+		{
+			name:   "text output for an object",
+			merger: merger,
+			sources: []Agent{
+				{Handle: "@research"},
+				{Handle: "@critic"},
+			},
+			want: []string{"@research has no output schema"},
+		},
+		{
+			name:   "mismatched output schema",
+			merger: merger,
+			sources: []Agent{
+				{Handle: "@research", OutputSchema: &schema.Schema{
+					Type:       "object",
+					Properties: map[string]*schema.Schema{"summary": {Type: "string"}},
+				}},
+				{Handle: "@critic"},
+			},
+			want: []string{"@research's output schema doesn't match"},
+		},
+		{
+			name:    "missing required",
+			merger:  merger,
+			sources: []Agent{{Handle: "@critic"}},
+			want:    []string{`requires "research"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckMergeSources(tt.merger, tt.sources)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("problems = %q, want %d", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// RenamePlan describes the changes made by renaming a user agent.
+type RenamePlan struct {
+	OldHandle string
+	NewHandle string
+	OldDir    string
+	NewDir    string
+
+	// GlobalDelegates lists task types in the global config that delegate
+	// to the old handle.
+	GlobalDelegates []string
+
+	// AgentDelegates maps agent handles (as they exist before the rename) to
+	// the task types in their config that delegate to the old handle.
+	AgentDelegates map[string][]string
+}
+
+// PlanRename checks that oldHandle can be renamed to newHandle and collects
+// the delegate mappings that must follow it. Only agents in cfg.AgentsDir
+// can be renamed.
+func PlanRename(cfg config.Config, oldHandle, newHandle string) (RenamePlan, error) {
+	plan := RenamePlan{
+		OldHandle:      NormalizeHandle(oldHandle),
+		NewHandle:      NormalizeHandle(newHandle),
+		AgentDelegates: make(map[string][]string),
+	}
+
+	if IsReservedNamespace(plan.OldHandle) || IsReservedNamespace(plan.NewHandle) {
+		return RenamePlan{}, ErrReservedNamespace
+	}
+	if plan.OldHandle == plan.NewHandle {
+		return RenamePlan{}, fmt.Errorf("new handle is the same as the old handle: %s", plan.NewHandle)
+	}
+
+	plan.OldDir = filepath.Join(cfg.AgentsDir, plan.OldHandle)
+	plan.NewDir = filepath.Join(cfg.AgentsDir, plan.NewHandle)
+
+	if info, err := os.Stat(plan.OldDir); err != nil || !info.IsDir() {
+		return RenamePlan{}, fmt.Errorf("agent not found in %s: %s", cfg.AgentsDir, plan.OldHandle)
+	}
+	handles, err := ListHandles(cfg)
+	if err != nil {
+		return RenamePlan{}, err
+	}
+	for _, h := range handles {
+		if h == plan.NewHandle {
+			return RenamePlan{}, fmt.Errorf("agent already exists: %s", plan.NewHandle)
+		}
+	}
+
+	plan.GlobalDelegates = delegatesTo(cfg.Delegates, plan.OldHandle)
+
+	entries, err := os.ReadDir(cfg.AgentsDir)
+	if err != nil {
+		return RenamePlan{}, fmt.Errorf("read agents directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "@") {
+			continue
+		}
+		agCfg, err := loadRawAgentConfig(filepath.Join(cfg.AgentsDir, entry.Name()))
+		if err != nil {
+			return RenamePlan{}, fmt.Errorf("load config for %s: %w", entry.Name(), err)
+		}
+		if taskTypes := delegatesTo(agCfg.Delegates, plan.OldHandle); len(taskTypes) > 0 {
+			plan.AgentDelegates[entry.Name()] = taskTypes
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyRename moves the agent directory and rewrites the delegate mappings
+// collected in plan. cfgPath is the global config file to update.
+func ApplyRename(cfgPath string, cfg config.Config, plan RenamePlan) error {
+	if err := os.Rename(plan.OldDir, plan.NewDir); err != nil {
+		return fmt.Errorf("move agent directory: %w", err)
+	}
+
+	for handle, taskTypes := range plan.AgentDelegates {
+		// The renamed agent may delegate to itself
+		if handle == plan.OldHandle {
+			handle = plan.NewHandle
+		}
+		if err := updateAgentDelegates(filepath.Join(cfg.AgentsDir, handle), taskTypes, plan.NewHandle); err != nil {
+			return fmt.Errorf("update delegates for %s: %w", handle, err)
+		}
+	}
+
+	if len(plan.GlobalDelegates) > 0 {
+		// Reload so only the delegates change in the file on disk
+		globalCfg, err := config.Load(cfgPath)
+		if err != nil {
+			return fmt.Errorf("load global config: %w", err)
+		}
+		for _, taskType := range delegatesTo(globalCfg.Delegates, plan.OldHandle) {
+			globalCfg.Delegates[taskType] = plan.NewHandle
+		}
+		if err := config.Save(cfgPath, globalCfg); err != nil {
+			return fmt.Errorf("update global delegates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updateAgentDelegates points the given task types in an agent's config at
+// handle.
+func updateAgentDelegates(dir string, taskTypes []string, handle string) error {
+	agCfg, err := loadRawAgentConfig(dir)
+	if err != nil {
+		return err
+	}
+	for _, taskType := range taskTypes {
+		agCfg.Delegates[taskType] = handle
+	}
+	data, err := json.MarshalIndent(agCfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal agent config: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, "config.json"), data, 0o644)
+}
+
+// delegatesTo returns the sorted task types in delegates mapped to handle.
+func delegatesTo(delegates map[string]string, handle string) []string {
+	var taskTypes []string
+	for taskType, h := range delegates {
+		if NormalizeHandle(h) == handle {
+			taskTypes = append(taskTypes, taskType)
+		}
+	}
+	sort.Strings(taskTypes)
+	return taskTypes
+}
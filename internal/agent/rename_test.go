@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestRenameUpdatesDelegates(t *testing.T) {
+	home := t.TempDir()
+	cfgPath := filepath.Join(home, "ayo.json")
+	cfg := config.Config{
+		AgentsDir: filepath.Join(home, "agents"),
+		Delegates: map[string]string{"coding": "@old", "research": "@other"},
+	}
+	if err := config.Save(cfgPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	writeAgentConfig(t, filepath.Join(cfg.AgentsDir, "@old"), Config{Model: "m", Delegates: map[string]string{"debug": "@old"}})
+	writeAgentConfig(t, filepath.Join(cfg.AgentsDir, "@lead"), Config{Model: "m", Delegates: map[string]string{"coding": "old", "docs": "@other"}})
+
+	plan, err := PlanRename(cfg, "old", "@new")
+	if err != nil {
+		t.Fatalf("PlanRename: %v", err)
+	}
+	if !reflect.DeepEqual(plan.GlobalDelegates, []string{"coding"}) {
+		t.Errorf("GlobalDelegates = %v", plan.GlobalDelegates)
+	}
+	wantAgents := map[string][]string{"@old": {"debug"}, "@lead": {"coding"}}
+	if !reflect.DeepEqual(plan.AgentDelegates, wantAgents) {
+		t.Errorf("AgentDelegates = %v, want %v", plan.AgentDelegates, wantAgents)
+	}
+
+	if err := ApplyRename(cfgPath, cfg, plan); err != nil {
+		t.Fatalf("ApplyRename: %v", err)
+	}
+
+	if _, err := os.Stat(plan.OldDir); !os.IsNotExist(err) {
+		t.Errorf("old directory should be gone, got %v", err)
+	}
+	renamed, err := loadAgentConfig(plan.NewDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Delegates["debug"] != "@new" {
+		t.Errorf("self delegate = %q, want @new", renamed.Delegates["debug"])
+	}
+	lead, err := loadAgentConfig(filepath.Join(cfg.AgentsDir, "@lead"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lead.Delegates["coding"] != "@new" || lead.Delegates["docs"] != "@other" {
+		t.Errorf("lead delegates = %v", lead.Delegates)
+	}
+	saved, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Delegates["coding"] != "@new" || saved.Delegates["research"] != "@other" {
+		t.Errorf("global delegates = %v", saved.Delegates)
+	}
+}
+
+func TestPlanRenameErrors(t *testing.T) {
+	home := t.TempDir()
+	cfg := config.Config{AgentsDir: filepath.Join(home, "agents")}
+	writeAgentConfig(t, filepath.Join(cfg.AgentsDir, "@old"), Config{Model: "m"})
+	writeAgentConfig(t, filepath.Join(cfg.AgentsDir, "@taken"), Config{Model: "m"})
+
+	if _, err := PlanRename(cfg, "@old", "@ayo.new"); !errors.Is(err, ErrReservedNamespace) {
+		t.Errorf("reserved target: got %v", err)
+	}
+	if _, err := PlanRename(cfg, "@missing", "@new"); err == nil {
+		t.Error("expected error for missing agent")
+	}
+	if _, err := PlanRename(cfg, "@old", "@taken"); err == nil {
+		t.Error("expected error for existing target")
+	}
+	if _, err := PlanRename(cfg, "@old", "old"); err == nil {
+		t.Error("expected error for unchanged handle")
+	}
+}
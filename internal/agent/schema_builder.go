@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"charm.land/fantasy/schema"
+)
+
+// SchemaFieldTypes are the field types the schema builder supports.
+var SchemaFieldTypes = []string{"string", "number", "boolean", "object", "array"}
+
+// SchemaItemTypes are the element types of array fields.
+var SchemaItemTypes = []string{"string", "number", "boolean"}
+
+// SchemaField describes one property of a schema built field by field.
+type SchemaField struct {
+	Path        string   // Property name; dotted to nest in an object field, e.g. "author.name"
+	Type        string   // One of SchemaFieldTypes
+	Required    bool     // Must be present in its parent object
+	Description string   // Guidance for the model filling the field
+	Enum        []string // Allowed values of a string field
+	ItemType    string   // Element type of an array field, one of SchemaItemTypes
+}
+
+// BuildSchema assembles a draft-07 object schema from fields. An object
+// field must come before the fields nested in it.
+func BuildSchema(fields []SchemaField) (*schema.Schema, error) {
+	root := &schema.Schema{Type: "object", Properties: map[string]*schema.Schema{}}
+	objects := map[string]*schema.Schema{"": root}
+
+	for _, f := range fields {
+		parentPath, name := "", f.Path
+		if i := strings.LastIndex(f.Path, "."); i >= 0 {
+			parentPath, name = f.Path[:i], f.Path[i+1:]
+		}
+		if name == "" || strings.TrimSpace(name) != name {
+			return nil, fmt.Errorf("invalid field name %q", f.Path)
+		}
+		parent, ok := objects[parentPath]
+		if !ok {
+			return nil, fmt.Errorf("field %s: %s is not an object field", f.Path, parentPath)
+		}
+		if _, exists := parent.Properties[name]; exists {
+			return nil, fmt.Errorf("duplicate field %s", f.Path)
+		}
+
+		prop, err := buildFieldSchema(f)
+		if err != nil {
+			return nil, err
+		}
+		parent.Properties[name] = prop
+		if f.Type == "object" {
+			objects[f.Path] = prop
+		}
+		if f.Required {
+			parent.Required = append(parent.Required, name)
+			sort.Strings(parent.Required)
+		}
+	}
+
+	if len(root.Properties) == 0 {
+		return nil, fmt.Errorf("schema has no fields")
+	}
+	return root, nil
+}
+
+func buildFieldSchema(f SchemaField) (*schema.Schema, error) {
+	if !slices.Contains(SchemaFieldTypes, f.Type) {
+		return nil, fmt.Errorf("field %s: unsupported type %q", f.Path, f.Type)
+	}
+	if len(f.Enum) > 0 && f.Type != "string" {
+		return nil, fmt.Errorf("field %s: only string fields can have allowed values", f.Path)
+	}
+
+	s := &schema.Schema{Type: f.Type, Description: f.Description}
+	for _, v := range f.Enum {
+		s.Enum = append(s.Enum, v)
+	}
+	switch f.Type {
+	case "object":
+		s.Properties = map[string]*schema.Schema{}
+	case "array":
+		if !slices.Contains(SchemaItemTypes, f.ItemType) {
+			return nil, fmt.Errorf("field %s: unsupported item type %q", f.Path, f.ItemType)
+		}
+		s.Items = &schema.Schema{Type: f.ItemType}
+	}
+	return s, nil
+}
+
+// SchemaExample returns a value that satisfies s, for checking that a built
+// schema validates.
+func SchemaExample(s *schema.Schema) any {
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+	switch s.Type {
+	case "object":
+		obj := map[string]any{}
+		for name, prop := range s.Properties {
+			obj[name] = SchemaExample(prop)
+		}
+		return obj
+	case "array":
+		if s.Items == nil {
+			return []any{}
+		}
+		return []any{SchemaExample(s.Items)}
+	case "number", "integer":
+		return 1.0
+	case "boolean":
+		return true
+	default:
+		return "example"
+	}
+}
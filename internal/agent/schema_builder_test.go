@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy/schema"
+)
+
+func TestBuildSchema(t *testing.T) {
+	s, err := BuildSchema([]SchemaField{
+		{Path: "summary", Type: "string", Required: true, Description: "One sentence"},
+		{Path: "severity", Type: "string", Required: true, Enum: []string{"low", "high"}},
+		{Path: "score", Type: "number"},
+		{Path: "urgent", Type: "boolean"},
+		{Path: "tags", Type: "array", ItemType: "string"},
+		{Path: "author", Type: "object", Required: true},
+		{Path: "author.name", Type: "string", Required: true},
+	})
+	if err != nil {
+		t.Fatalf("BuildSchema: %v", err)
+	}
+
+	if got := s.Required; !slices.Equal(got, []string{"author", "severity", "summary"}) {
+		t.Errorf("required = %v", got)
+	}
+	if got := s.Properties["severity"].Enum; len(got) != 2 || got[0] != "low" {
+		t.Errorf("severity enum = %v", got)
+	}
+	if got := s.Properties["tags"].Items; got == nil || got.Type != "string" {
+		t.Errorf("tags items = %+v", got)
+	}
+	author := s.Properties["author"]
+	if author.Properties["name"] == nil || !slices.Equal(author.Required, []string{"name"}) {
+		t.Errorf("author = %+v", author)
+	}
+	if s.Properties["summary"].Description != "One sentence" {
+		t.Errorf("summary description = %q", s.Properties["summary"].Description)
+	}
+
+	data, err := MarshalSchema(s)
+	if err != nil {
+		t.Fatalf("MarshalSchema: %v", err)
+	}
+	if !strings.Contains(string(data), SchemaDraft07) {
+		t.Errorf("schema should declare draft-07:\n%s", data)
+	}
+	if err := VerifyInferredSchema(data, s, []any{SchemaExample(s)}); err != nil {
+		t.Errorf("built schema should round-trip and accept its example: %v", err)
+	}
+
+	var bad map[string]any
+	_ = json.Unmarshal([]byte(`{"summary": "x", "severity": "medium", "author": {"name": "a"}}`), &bad)
+	if err := schema.ValidateAgainstSchema(bad, *s); err == nil {
+		t.Error("a value outside the enum should not validate")
+	}
+}
+
+func TestBuildSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []SchemaField
+		want   string
+	}{
+		{"empty", nil, "no fields"},
+		{"blank name", []SchemaField{{Path: "", Type: "string"}}, "invalid field name"},
+		{"duplicate", []SchemaField{{Path: "a", Type: "string"}, {Path: "a", Type: "number"}}, "duplicate field a"},
+		{"unknown type", []SchemaField{{Path: "a", Type: "date"}}, `unsupported type "date"`},
+		{"enum on number", []SchemaField{{Path: "a", Type: "number", Enum: []string{"1"}}}, "only string fields"},
+		{"array of objects", []SchemaField{{Path: "a", Type: "array", ItemType: "object"}}, `unsupported item type "object"`},
+		{"missing parent", []SchemaField{{Path: "a.b", Type: "string"}}, "a is not an object field"},
+		{"non-object parent", []SchemaField{{Path: "a", Type: "string"}, {Path: "a.b", Type: "string"}}, "a is not an object field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildSchema(tt.fields)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
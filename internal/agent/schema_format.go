@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"charm.land/fantasy/schema"
+	"github.com/kaptinlin/jsonschema"
+)
+
+// stringFormat is a JSON Schema string format that input validation enforces.
+type stringFormat struct {
+	valid    func(any) bool
+	expected string // What a valid value looks like, for error messages
+}
+
+// checkedFormats are the formats ValidateInput enforces. Other formats are
+// annotations, as the JSON Schema spec allows, and are not checked.
+var checkedFormats = map[string]stringFormat{
+	"date-time":     {jsonschema.IsDateTime, "an RFC 3339 date-time such as 2024-01-31T09:00:00Z"},
+	"date":          {jsonschema.IsDate, "a date such as 2024-01-31"},
+	"time":          {jsonschema.IsTime, "a time such as 09:00:00Z"},
+	"email":         {jsonschema.IsEmail, "an email address"},
+	"uri":           {jsonschema.IsURI, "an absolute URI such as https://example.com"},
+	"uri-reference": {jsonschema.IsURIReference, "a URI reference"},
+	"hostname":      {jsonschema.IsHostname, "a hostname"},
+	"ipv4":          {jsonschema.IsIPV4, "an IPv4 address"},
+	"ipv6":          {jsonschema.IsIPV6, "an IPv6 address"},
+	"uuid":          {jsonschema.IsUUID, "a UUID"},
+}
+
+// validateFormats checks string values against the formats their schemas
+// declare. The value must already match the schema's types.
+func validateFormats(value any, s *schema.Schema) error {
+	problems := formatProblems(value, s, "")
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("validation failed: %s", strings.Join(problems, "; "))
+}
+
+// formatProblems returns a message for each value under path that doesn't
+// match its declared format.
+func formatProblems(value any, s *schema.Schema, path string) []string {
+	if s == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		f, ok := checkedFormats[s.Format]
+		if ok && !f.valid(v) {
+			field := "input"
+			if path != "" {
+				field = fmt.Sprintf("field %q", path)
+			}
+			return []string{fmt.Sprintf("%s must be %s (format %q), got %q", field, f.expected, s.Format, v)}
+		}
+	case map[string]any:
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var problems []string
+		for _, name := range names {
+			if child, ok := v[name]; ok {
+				childPath := name
+				if path != "" {
+					childPath = path + "." + name
+				}
+				problems = append(problems, formatProblems(child, s.Properties[name], childPath)...)
+			}
+		}
+		return problems
+	case []any:
+		var problems []string
+		for i, item := range v {
+			problems = append(problems, formatProblems(item, s.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+	}
+	return nil
+}
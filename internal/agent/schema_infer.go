@@ -0,0 +1,254 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"charm.land/fantasy/schema"
+)
+
+// SchemaDraft07 is the $schema URI written with inferred schemas.
+const SchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// maxInferredEnum is the most distinct string values inferred as an enum.
+const maxInferredEnum = 5
+
+// InferredField is an object property of an inferred schema, exposed so
+// callers can relax or tighten it before saving.
+type InferredField struct {
+	Path     string         // Dotted path, e.g. "items[].name"
+	Name     string         // Property name within Parent
+	Parent   *schema.Schema // Object schema containing the property
+	Schema   *schema.Schema // The property's schema
+	Required bool           // Present in every example object
+	Values   []string       // Distinct string values seen, when no enum was inferred
+}
+
+// SchemaInference is the result of inferring a schema from examples.
+type SchemaInference struct {
+	Schema *schema.Schema
+	Fields []InferredField
+}
+
+// observation accumulates the values seen at one position in the examples.
+type observation struct {
+	count   int            // Non-null values seen
+	nulls   int            // Null values seen
+	types   map[string]int // JSON schema type of each non-null value
+	objects int            // Object values seen
+
+	props     map[string]*observation
+	propOrder []string
+	propSeen  map[string]int // Objects in which each property appeared
+
+	items *observation // Elements of every array seen
+
+	strings     map[string]int
+	stringOrder []string
+}
+
+func newObservation() *observation {
+	return &observation{
+		types:    make(map[string]int),
+		props:    make(map[string]*observation),
+		propSeen: make(map[string]int),
+		strings:  make(map[string]int),
+	}
+}
+
+func (o *observation) add(v any) {
+	if v == nil {
+		o.nulls++
+		return
+	}
+	o.count++
+
+	switch val := v.(type) {
+	case map[string]any:
+		o.types["object"]++
+		o.objects++
+		for name, pv := range val {
+			if _, ok := o.props[name]; !ok {
+				o.props[name] = newObservation()
+				o.propOrder = append(o.propOrder, name)
+			}
+			o.props[name].add(pv)
+			o.propSeen[name]++
+		}
+	case []any:
+		o.types["array"]++
+		if o.items == nil {
+			o.items = newObservation()
+		}
+		for _, item := range val {
+			o.items.add(item)
+		}
+	case string:
+		o.types["string"]++
+		if _, ok := o.strings[val]; !ok {
+			o.stringOrder = append(o.stringOrder, val)
+		}
+		o.strings[val]++
+	case bool:
+		o.types["boolean"]++
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			o.types["integer"]++
+		} else {
+			o.types["number"]++
+		}
+	}
+}
+
+// schemaType returns the single type covering every value seen, or "" when
+// the values are mixed or include null.
+func (o *observation) schemaType() string {
+	if o.nulls > 0 || len(o.types) == 0 {
+		return ""
+	}
+	if len(o.types) == 2 && o.types["integer"] > 0 && o.types["number"] > 0 {
+		return "number"
+	}
+	if len(o.types) != 1 {
+		return ""
+	}
+	for t := range o.types {
+		return t
+	}
+	return ""
+}
+
+// InferSchema builds a draft-07 schema that accepts every example. Types
+// come from the values seen; mixed or null values leave a field untyped.
+// Object properties present in every example are required. String fields
+// with a small set of repeated values (two to five) become enums.
+func InferSchema(examples []any) (*SchemaInference, error) {
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples to infer a schema from")
+	}
+
+	root := newObservation()
+	for _, ex := range examples {
+		root.add(ex)
+	}
+
+	inf := &SchemaInference{}
+	inf.Schema = inf.build(root, "")
+	return inf, nil
+}
+
+func (inf *SchemaInference) build(o *observation, path string) *schema.Schema {
+	s := &schema.Schema{Type: o.schemaType()}
+
+	switch s.Type {
+	case "object":
+		s.Properties = make(map[string]*schema.Schema, len(o.props))
+		names := append([]string(nil), o.propOrder...)
+		sort.Strings(names)
+		for _, name := range names {
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			// Append the field before its children so Fields stays depth-first
+			idx := len(inf.Fields)
+			inf.Fields = append(inf.Fields, InferredField{Path: fieldPath, Name: name, Parent: s})
+			prop := inf.build(o.props[name], fieldPath)
+			s.Properties[name] = prop
+
+			field := &inf.Fields[idx]
+			field.Schema = prop
+			if o.propSeen[name] == o.objects && o.props[name].nulls == 0 {
+				field.Required = true
+				s.Required = append(s.Required, name)
+			}
+			if prop.Type == "string" && len(prop.Enum) == 0 {
+				field.Values = append([]string(nil), o.props[name].stringOrder...)
+			}
+		}
+	case "array":
+		if o.items != nil && (o.items.count > 0 || o.items.nulls > 0) {
+			s.Items = inf.build(o.items, path+"[]")
+		}
+	case "string":
+		// A single repeated value is more likely a coincidence of the
+		// examples than a constant
+		if len(o.strings) >= 2 && len(o.strings) <= maxInferredEnum && o.count > len(o.strings) {
+			for _, v := range o.stringOrder {
+				s.Enum = append(s.Enum, v)
+			}
+		}
+	}
+
+	return s
+}
+
+// MarshalSchema encodes s as an indented draft-07 schema document.
+func MarshalSchema(s *schema.Schema) ([]byte, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc["$schema"] = SchemaDraft07
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// VerifyInferredSchema checks that data decodes back to s as a
+// schema.Schema and that every example validates against it.
+func VerifyInferredSchema(data []byte, s *schema.Schema, examples []any) error {
+	var decoded schema.Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("schema does not decode: %w", err)
+	}
+	want, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	got, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	var wantDoc, gotDoc any
+	_ = json.Unmarshal(want, &wantDoc)
+	_ = json.Unmarshal(got, &gotDoc)
+	if !reflect.DeepEqual(wantDoc, gotDoc) {
+		return fmt.Errorf("schema does not round-trip")
+	}
+
+	for i, ex := range examples {
+		if err := schema.ValidateAgainstSchema(ex, decoded); err != nil {
+			return fmt.Errorf("example %d does not match the schema: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// SetFieldRequired marks a field required or optional in its parent object.
+func SetFieldRequired(f InferredField, required bool) {
+	var names []string
+	for _, name := range f.Parent.Required {
+		if name != f.Name {
+			names = append(names, name)
+		}
+	}
+	if required {
+		names = append(names, f.Name)
+		sort.Strings(names)
+	}
+	f.Parent.Required = names
+}
+
+// RestrictToValues turns a string field into an enum of the values seen.
+func RestrictToValues(f InferredField) {
+	f.Schema.Enum = nil
+	for _, v := range f.Values {
+		f.Schema.Enum = append(f.Schema.Enum, v)
+	}
+}
@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseExamples(t *testing.T, docs ...string) []any {
+	t.Helper()
+	var out []any
+	for _, d := range docs {
+		var v any
+		if err := json.Unmarshal([]byte(d), &v); err != nil {
+			t.Fatalf("parse example: %v", err)
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestInferSchema(t *testing.T) {
+	examples := parseExamples(t,
+		`{"title": "A", "score": 1, "status": "open", "tags": ["x", "y"], "meta": {"ratio": 0.5}}`,
+		`{"title": "B", "score": 2.5, "status": "open", "tags": [], "note": null, "meta": {"ratio": 1}}`,
+		`{"title": "C", "score": 3, "status": "closed", "tags": ["x"], "meta": {"ratio": 2}}`,
+	)
+
+	inf, err := InferSchema(examples)
+	if err != nil {
+		t.Fatalf("InferSchema: %v", err)
+	}
+	s := inf.Schema
+
+	if s.Type != "object" {
+		t.Fatalf("Type = %q, want object", s.Type)
+	}
+	if want := []string{"meta", "score", "status", "tags", "title"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("Required = %v, want %v", s.Required, want)
+	}
+	if got := s.Properties["score"].Type; got != "number" {
+		t.Errorf("score type = %q, want number", got)
+	}
+	if got := s.Properties["status"].Enum; !reflect.DeepEqual(got, []any{"open", "closed"}) {
+		t.Errorf("status enum = %v", got)
+	}
+	if got := s.Properties["title"].Enum; got != nil {
+		t.Errorf("title should not be an enum, got %v", got)
+	}
+	if got := s.Properties["tags"].Items; got == nil || got.Type != "string" {
+		t.Errorf("tags items = %+v, want string", got)
+	}
+	if got := s.Properties["note"].Type; got != "" {
+		t.Errorf("null-only field should be untyped, got %q", got)
+	}
+	if got := s.Properties["meta"].Properties["ratio"].Type; got != "number" {
+		t.Errorf("meta.ratio type = %q, want number", got)
+	}
+
+	var paths []string
+	for _, f := range inf.Fields {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"meta", "meta.ratio", "note", "score", "status", "tags", "title"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("field paths = %v, want %v", paths, want)
+	}
+
+	data, err := MarshalSchema(s)
+	if err != nil {
+		t.Fatalf("MarshalSchema: %v", err)
+	}
+	if !strings.Contains(string(data), SchemaDraft07) {
+		t.Error("marshalled schema should declare draft-07")
+	}
+	if err := VerifyInferredSchema(data, s, examples); err != nil {
+		t.Errorf("VerifyInferredSchema: %v", err)
+	}
+}
+
+func TestInferSchemaIntegers(t *testing.T) {
+	inf, err := InferSchema(parseExamples(t, `{"n": 1}`, `{"n": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inf.Schema.Properties["n"].Type; got != "integer" {
+		t.Errorf("n type = %q, want integer", got)
+	}
+}
+
+func TestInferSchemaSingleValueIsNotEnum(t *testing.T) {
+	inf, err := InferSchema(parseExamples(t, `{"v": "1"}`, `{"v": "1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inf.Schema.Properties["v"].Enum; got != nil {
+		t.Errorf("single repeated value should not be an enum, got %v", got)
+	}
+}
+
+func TestInferSchemaMixedTypes(t *testing.T) {
+	inf, err := InferSchema(parseExamples(t, `{"v": 1}`, `{"v": "one"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inf.Schema.Properties["v"].Type; got != "" {
+		t.Errorf("mixed field should be untyped, got %q", got)
+	}
+}
+
+func TestInferSchemaNoExamples(t *testing.T) {
+	if _, err := InferSchema(nil); err == nil {
+		t.Error("expected error for no examples")
+	}
+}
+
+func TestAdjustInferredFields(t *testing.T) {
+	examples := parseExamples(t, `{"name": "a", "kind": "x"}`, `{"name": "b", "kind": "y"}`)
+	inf, err := InferSchema(examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range inf.Fields {
+		switch f.Name {
+		case "name":
+			SetFieldRequired(f, false)
+		case "kind":
+			RestrictToValues(f)
+		}
+	}
+
+	if !reflect.DeepEqual(inf.Schema.Required, []string{"kind"}) {
+		t.Errorf("Required = %v, want [kind]", inf.Schema.Required)
+	}
+	if got := inf.Schema.Properties["kind"].Enum; !reflect.DeepEqual(got, []any{"x", "y"}) {
+		t.Errorf("kind enum = %v", got)
+	}
+
+	data, err := MarshalSchema(inf.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyInferredSchema(data, inf.Schema, examples); err != nil {
+		t.Errorf("VerifyInferredSchema: %v", err)
+	}
+	if err := VerifyInferredSchema(data, inf.Schema, parseExamples(t, `{"kind": "z"}`)); err == nil {
+		t.Error("expected tightened schema to reject an unseen value")
+	}
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/alexcabrera/ayo/internal/embedding"
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+// Skill selection defaults.
+const (
+	defaultSkillTopK      = 5
+	defaultSkillMinSkills = 10
+)
+
+// SkillSelectionConfig configures query-based skill selection. When enabled,
+// the query and each skill's description are embedded and only the most
+// relevant skills are injected.
+type SkillSelectionConfig struct {
+	Enabled   bool `json:"enabled,omitempty"`
+	TopK      int  `json:"top_k,omitempty"`      // Skills to inject (default 5)
+	MinSkills int  `json:"min_skills,omitempty"` // Inject all skills when the agent has this many or fewer (default 10)
+}
+
+// SkillSelection reports which skills were chosen for a query.
+type SkillSelection struct {
+	Skills   []skills.Metadata // Skills injected into the prompt
+	Total    int               // Skills available to the agent
+	Selected bool              // False when all skills were kept
+	Reason   string            // Why all skills were kept, when Selected is false
+}
+
+// SelectSkills returns a copy of the agent whose skills prompt lists only
+// the skills most relevant to query. All skills are kept when selection is
+// disabled, the agent has few skills, no embedder is available, or
+// embedding fails.
+func (a Agent) SelectSkills(ctx context.Context, embedder embedding.Embedder, query string) (Agent, SkillSelection) {
+	cfg := a.Config.SkillSelection
+	sel := SkillSelection{Skills: a.Skills, Total: len(a.Skills)}
+
+	topK := cfg.TopK
+	if topK <= 0 {
+		topK = defaultSkillTopK
+	}
+	minSkills := cfg.MinSkills
+	if minSkills <= 0 {
+		minSkills = defaultSkillMinSkills
+	}
+
+	switch {
+	case !cfg.Enabled:
+		sel.Reason = "skill selection is disabled"
+		return a, sel
+	case len(a.Skills) <= minSkills || len(a.Skills) <= topK:
+		sel.Reason = fmt.Sprintf("agent has %d skills, at or below the selection threshold", len(a.Skills))
+		return a, sel
+	case embedder == nil:
+		sel.Reason = "no embedder is available"
+		return a, sel
+	case query == "":
+		sel.Reason = "no query to select skills for"
+		return a, sel
+	}
+
+	texts := make([]string, 0, len(a.Skills)+1)
+	texts = append(texts, query)
+	for _, m := range a.Skills {
+		texts = append(texts, m.Name+": "+m.Description)
+	}
+	vectors, err := embedder.EmbedBatch(ctx, texts)
+	if err != nil || len(vectors) != len(texts) {
+		sel.Reason = "embedding failed"
+		if err != nil {
+			sel.Reason = fmt.Sprintf("embedding failed: %v", err)
+		}
+		return a, sel
+	}
+
+	order := make([]int, len(a.Skills))
+	scores := make([]float32, len(a.Skills))
+	for i := range a.Skills {
+		order[i] = i
+		scores[i] = embedding.CosineSimilarity(vectors[0], vectors[i+1])
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+
+	chosen := make([]skills.Metadata, 0, topK)
+	for _, i := range order[:topK] {
+		chosen = append(chosen, a.Skills[i])
+	}
+
+	a.Skills = chosen
+	a.SkillsPrompt = buildSkillsPrompt(a.Skills)
+	sel.Skills = a.Skills
+	sel.Selected = true
+	return a, sel
+}
@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+// keywordEmbedder embeds text as counts of a fixed vocabulary, so texts
+// sharing words are similar.
+type keywordEmbedder struct {
+	vocab []string
+	err   error
+}
+
+func (e keywordEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	v := make([]float32, len(e.vocab))
+	for i, w := range e.vocab {
+		v[i] = float32(strings.Count(strings.ToLower(text), w))
+	}
+	return v, nil
+}
+
+func (e keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = e.Embed(ctx, t)
+	}
+	return out, nil
+}
+
+func (e keywordEmbedder) Dimension() int { return len(e.vocab) }
+func (e keywordEmbedder) Close() error   { return nil }
+
+func selectionAgent(n int, cfg SkillSelectionConfig) Agent {
+	topics := []string{"docker", "git", "sql", "python", "testing"}
+	var metas []skills.Metadata
+	for i := 0; i < n; i++ {
+		topic := topics[i%len(topics)]
+		metas = append(metas, skills.Metadata{
+			Name:        fmt.Sprintf("%s-%d", topic, i),
+			Description: "Help with " + topic,
+		})
+	}
+	return Agent{
+		Skills:       metas,
+		SkillsPrompt: buildSkillsPrompt(metas),
+		Config:       Config{SkillSelection: cfg},
+	}
+}
+
+func TestSelectSkillsPicksRelevant(t *testing.T) {
+	ag := selectionAgent(12, SkillSelectionConfig{Enabled: true, TopK: 2})
+	embedder := keywordEmbedder{vocab: []string{"docker", "git", "sql", "python", "testing"}}
+
+	selected, sel := ag.SelectSkills(context.Background(), embedder, "my docker build fails")
+	if !sel.Selected || sel.Total != 12 {
+		t.Fatalf("expected selection of 12 skills, got %+v", sel)
+	}
+	if len(selected.Skills) != 2 {
+		t.Fatalf("expected 2 skills, got %d", len(selected.Skills))
+	}
+	for _, m := range selected.Skills {
+		if !strings.HasPrefix(m.Name, "docker-") {
+			t.Errorf("unexpected skill %s", m.Name)
+		}
+		if !strings.Contains(selected.SkillsPrompt, m.Name) {
+			t.Errorf("skills prompt should list %s", m.Name)
+		}
+	}
+	if strings.Contains(selected.SkillsPrompt, "git-1") {
+		t.Error("skills prompt should not list unselected skills")
+	}
+	if len(ag.Skills) != 12 {
+		t.Error("original agent should keep all skills")
+	}
+}
+
+func TestSelectSkillsFallsBack(t *testing.T) {
+	embedder := keywordEmbedder{vocab: []string{"docker"}}
+	tests := []struct {
+		name     string
+		ag       Agent
+		embedder keywordEmbedder
+		noEmbed  bool
+		query    string
+		reason   string
+	}{
+		{"disabled", selectionAgent(12, SkillSelectionConfig{}), embedder, false, "docker", "disabled"},
+		{"below threshold", selectionAgent(8, SkillSelectionConfig{Enabled: true}), embedder, false, "docker", "threshold"},
+		{"custom threshold", selectionAgent(12, SkillSelectionConfig{Enabled: true, MinSkills: 20}), embedder, false, "docker", "threshold"},
+		{"no embedder", selectionAgent(12, SkillSelectionConfig{Enabled: true}), embedder, true, "docker", "no embedder"},
+		{"empty query", selectionAgent(12, SkillSelectionConfig{Enabled: true}), embedder, false, "", "no query"},
+		{"embedding error", selectionAgent(12, SkillSelectionConfig{Enabled: true}), keywordEmbedder{err: errors.New("offline")}, false, "docker", "offline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selected Agent
+			var sel SkillSelection
+			if tt.noEmbed {
+				selected, sel = tt.ag.SelectSkills(context.Background(), nil, tt.query)
+			} else {
+				selected, sel = tt.ag.SelectSkills(context.Background(), tt.embedder, tt.query)
+			}
+			if sel.Selected {
+				t.Fatal("expected all skills to be kept")
+			}
+			if !strings.Contains(sel.Reason, tt.reason) {
+				t.Errorf("Reason = %q, want it to mention %q", sel.Reason, tt.reason)
+			}
+			if len(selected.Skills) != len(tt.ag.Skills) || selected.SkillsPrompt != tt.ag.SkillsPrompt {
+				t.Error("fallback should keep all skills and the original prompt")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+// SkillResolution explains whether one skill is attached to an agent and why.
+type SkillResolution struct {
+	Name       string   `json:"name"`
+	Source     string   `json:"source,omitempty"` // agent, user, built-in, or plugin; empty if not found
+	Path       string   `json:"path,omitempty"`
+	Active     bool     `json:"active"`
+	Reason     string   `json:"reason"`
+	RequiredBy []string `json:"required_by,omitempty"` // Allowed tools that need the skill
+}
+
+// SkillsReport is how every skill an agent could use was resolved.
+type SkillsReport struct {
+	Skills   []SkillResolution `json:"skills"`
+	Warnings []string          `json:"warnings"`
+}
+
+// skillDiscoveryOptions returns the skill discovery options for the agent in
+// dir. The installed built-in skills are tagged as built-in so
+// ignore_builtin_skills applies to them; every other skills directory is shared.
+func skillDiscoveryOptions(dir string, cfg Config) skills.DiscoveryOptions {
+	builtinDir := paths.BuiltinSkillsDir()
+	shared := slices.DeleteFunc(paths.SkillsDirs(), func(d string) bool {
+		return d == builtinDir
+	})
+	return skills.DiscoveryOptions{
+		AgentSkillsDir: filepath.Join(dir, "skills"),
+		SharedDirs:     shared,
+		BuiltinDir:     builtinDir,
+		IncludeSkills:  cfg.Skills,
+		ExcludeSkills:  cfg.ExcludeSkills,
+		IgnoreBuiltin:  cfg.IgnoreBuiltinSkills,
+		IgnoreShared:   cfg.IgnoreSharedSkills,
+	}
+}
+
+// FindSkill resolves the named skill the way the agent in dir would see it,
+// ignoring the agent's skill filters: its own skills directory first, then
+// shared, built-in, and plugin skills. With an empty dir, agent-specific
+// skills are skipped.
+func FindSkill(dir, name string) (skills.Metadata, bool) {
+	opts := skillDiscoveryOptions(dir, Config{})
+	if dir == "" {
+		opts.AgentSkillsDir = ""
+	}
+	for _, m := range skills.DiscoverAll(opts).Skills {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return skills.Metadata{}, false
+}
+
+// AgentsUsingSkill returns the handles of agents that have the named skill
+// attached, whichever directory each resolves it from.
+func AgentsUsingSkill(cfg config.Config, name string) ([]string, error) {
+	handles, err := ListHandles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var using []string
+	for _, handle := range handles {
+		ag, err := Load(cfg, handle)
+		if err != nil {
+			continue // Skip agents that fail to load
+		}
+		if slices.ContainsFunc(ag.Skills, func(m skills.Metadata) bool { return m.Name == name }) {
+			using = append(using, handle)
+		}
+	}
+	return using, nil
+}
+
+// ResolveSkills reports every skill the agent could use, whether it is
+// attached, and why, along with discovery warnings and skills that are
+// requested or required by a tool but missing.
+func (a Agent) ResolveSkills() SkillsReport {
+	available := skills.DiscoverAll(skillDiscoveryOptions(a.Dir, Config{}))
+	return resolveSkills(a.Config, a.Skills, available.Skills, a.SkillsWarnings)
+}
+
+// resolveSkills explains the active skills against every available skill.
+func resolveSkills(cfg Config, active, available []skills.Metadata, warnings []string) SkillsReport {
+	requiredBy := make(map[string][]string)
+	for _, req := range skills.GetToolRequirementsForTools(cfg.AllowedTools) {
+		for _, name := range req.RequiredSkills {
+			requiredBy[name] = append(requiredBy[name], req.ToolName)
+		}
+	}
+
+	report := SkillsReport{Warnings: append([]string{}, warnings...)}
+	seen := make(map[string]bool)
+	add := func(m skills.Metadata, isActive bool, reason string) {
+		seen[m.Name] = true
+		report.Skills = append(report.Skills, SkillResolution{
+			Name:       m.Name,
+			Source:     m.Source.String(),
+			Path:       m.Path,
+			Active:     isActive,
+			Reason:     reason,
+			RequiredBy: requiredBy[m.Name],
+		})
+	}
+
+	for _, m := range active {
+		reason := "discovered"
+		if slices.Contains(cfg.Skills, m.Name) {
+			reason = "listed in skills"
+		}
+		add(m, true, reason)
+	}
+	for _, m := range available {
+		if seen[m.Name] {
+			continue
+		}
+		var reason string
+		switch {
+		case slices.Contains(cfg.ExcludeSkills, m.Name):
+			reason = "listed in exclude_skills"
+		case m.Source == skills.SourceBuiltIn && cfg.IgnoreBuiltinSkills:
+			reason = "ignore_builtin_skills is set"
+		case m.Source == skills.SourceUserShared && cfg.IgnoreSharedSkills:
+			reason = "ignore_shared_skills is set"
+		default:
+			reason = "not listed in skills"
+		}
+		add(m, false, reason)
+	}
+
+	// Skills asked for by name that no directory provides
+	var missing []string
+	for _, name := range append(slices.Clone(cfg.Skills), slices.Sorted(maps.Keys(requiredBy))...) {
+		if !seen[name] && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range missing {
+		seen[name] = true
+		report.Skills = append(report.Skills, SkillResolution{
+			Name:       name,
+			Reason:     "not found",
+			RequiredBy: requiredBy[name],
+		})
+		if slices.Contains(cfg.Skills, name) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("skill %q is listed in skills but was not found", name))
+		}
+	}
+
+	sort.Slice(report.Skills, func(i, j int) bool {
+		return report.Skills[i].Name < report.Skills[j].Name
+	})
+	for _, s := range report.Skills {
+		if !s.Active {
+			for _, tool := range s.RequiredBy {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("tool %s requires skill %q, which is not active", tool, s.Name))
+			}
+		}
+	}
+
+	return report
+}
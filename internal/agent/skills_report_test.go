@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+func TestResolveSkills(t *testing.T) {
+	debugging := skills.Metadata{Name: "debugging", Path: "/agent/skills/debugging/SKILL.md", Source: skills.SourceAgentSpecific}
+	available := []skills.Metadata{
+		debugging,
+		{Name: "agent-discovery", Source: skills.SourceBuiltIn},
+		{Name: "ayo", Source: skills.SourceBuiltIn},
+		{Name: "notes", Source: skills.SourceUserShared},
+		{Name: "deploy", Source: skills.SourceUserShared},
+	}
+	cfg := Config{
+		AllowedTools:        []string{"bash", "agent_call"},
+		Skills:              []string{"debugging", "notes", "missing"},
+		ExcludeSkills:       []string{"deploy"},
+		IgnoreBuiltinSkills: true,
+		IgnoreSharedSkills:  true,
+	}
+
+	report := resolveSkills(cfg, []skills.Metadata{debugging}, available, []string{"duplicate skill ayo from builtin ignored"})
+
+	want := []SkillResolution{
+		{Name: "agent-discovery", Source: "built-in", Reason: "ignore_builtin_skills is set", RequiredBy: []string{"agent_call"}},
+		{Name: "ayo", Source: "built-in", Reason: "ignore_builtin_skills is set"},
+		{Name: "debugging", Source: "agent", Path: debugging.Path, Active: true, Reason: "listed in skills"},
+		{Name: "deploy", Source: "user", Reason: "listed in exclude_skills"},
+		{Name: "missing", Reason: "not found"},
+		{Name: "notes", Source: "user", Reason: "ignore_shared_skills is set"},
+	}
+	if len(report.Skills) != len(want) {
+		t.Fatalf("Skills = %+v, want %d entries", report.Skills, len(want))
+	}
+	for i, w := range want {
+		got := report.Skills[i]
+		if got.Name != w.Name || got.Source != w.Source || got.Path != w.Path || got.Active != w.Active ||
+			got.Reason != w.Reason || !slices.Equal(got.RequiredBy, w.RequiredBy) {
+			t.Errorf("Skills[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	wantWarnings := []string{
+		"duplicate skill ayo from builtin ignored",
+		`skill "missing" is listed in skills but was not found`,
+		`tool agent_call requires skill "agent-discovery", which is not active`,
+	}
+	if !slices.Equal(report.Warnings, wantWarnings) {
+		t.Errorf("Warnings = %q, want %q", report.Warnings, wantWarnings)
+	}
+}
+
+func TestResolveSkillsWithoutIncludeList(t *testing.T) {
+	notes := skills.Metadata{Name: "notes", Source: skills.SourceUserShared}
+	report := resolveSkills(Config{}, []skills.Metadata{notes}, []skills.Metadata{notes}, nil)
+
+	if len(report.Skills) != 1 || !report.Skills[0].Active || report.Skills[0].Reason != "discovered" {
+		t.Errorf("Skills = %+v, want notes active as discovered", report.Skills)
+	}
+	if report.Warnings == nil || len(report.Warnings) != 0 {
+		t.Errorf("Warnings = %#v, want an empty list", report.Warnings)
+	}
+}
+
+func TestFindSkill(t *testing.T) {
+	agentDir := t.TempDir()
+	mustWrite(t, filepath.Join(agentDir, "skills", "find-skill-test", "SKILL.md"),
+		"---\nname: find-skill-test\ndescription: Agent-specific test skill\n---\n\nInstructions.")
+
+	meta, ok := FindSkill(agentDir, "find-skill-test")
+	if !ok {
+		t.Fatal("FindSkill did not find the agent's own skill")
+	}
+	if meta.Source != skills.SourceAgentSpecific {
+		t.Errorf("Source = %s, want agent", meta.Source)
+	}
+
+	// Without an agent directory, agent-specific skills aren't searched
+	if _, ok := FindSkill("", "find-skill-test"); ok {
+		t.Error("FindSkill found an agent-specific skill without an agent")
+	}
+}
@@ -3,6 +3,7 @@ package builtin
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -289,3 +290,69 @@ func TestGetAllBuiltinSkillInfos(t *testing.T) {
 		t.Error("expected debugging in skill infos")
 	}
 }
+
+func TestCheckAgentUpdates(t *testing.T) {
+	installDir := t.TempDir()
+	if err := extractAgents(installDir, nil); err != nil {
+		t.Fatalf("extractAgents: %v", err)
+	}
+
+	updates, err := checkAgentUpdates(installDir)
+	if err != nil {
+		t.Fatalf("checkAgentUpdates: %v", err)
+	}
+	for _, u := range updates {
+		if !u.Installed || u.UpdateAvailable() || len(u.Modified) > 0 {
+			t.Errorf("fresh install: %+v", u)
+		}
+	}
+
+	// A local edit is a modification, not an update
+	systemPath := filepath.Join(installDir, "@ayo", "system.md")
+	os.WriteFile(systemPath, []byte("custom"), 0o644)
+	// A file that changed in this release since install is an update
+	manifest := loadAgentsManifest(installDir)
+	manifest["@ayo"]["config.json"] = "old"
+	writeAgentsManifest(installDir, manifest)
+
+	updates, _ = checkAgentUpdates(installDir)
+	ayo := findAgentUpdate(updates, "@ayo")
+	if !slices.Equal(ayo.Modified, []string{"config.json", "system.md"}) || !slices.Equal(ayo.Changed, []string{"config.json"}) {
+		t.Errorf("@ayo: modified %v, changed %v", ayo.Modified, ayo.Changed)
+	}
+
+	// Reinstalling keeps the modified agent and its manifest entries
+	if err := extractAgents(installDir, map[string]bool{"@ayo": true}); err != nil {
+		t.Fatalf("extractAgents: %v", err)
+	}
+	if data, _ := os.ReadFile(systemPath); string(data) != "custom" {
+		t.Errorf("system.md = %q, want the local edit kept", data)
+	}
+	updates, _ = checkAgentUpdates(installDir)
+	if ayo := findAgentUpdate(updates, "@ayo"); len(ayo.Changed) != 1 {
+		t.Errorf("after keeping @ayo: changed %v", ayo.Changed)
+	}
+}
+
+func TestCheckAgentUpdatesWithoutManifest(t *testing.T) {
+	installDir := t.TempDir()
+	extractAgents(installDir, nil)
+	os.Remove(filepath.Join(installDir, agentsManifestFile))
+	os.WriteFile(filepath.Join(installDir, "@ayo", "notes.md"), []byte("mine"), 0o644)
+
+	updates, _ := checkAgentUpdates(installDir)
+	ayo := findAgentUpdate(updates, "@ayo")
+	want := []string{"notes.md (added)"}
+	if !slices.Equal(ayo.Modified, want) || !slices.Equal(ayo.Changed, want) {
+		t.Errorf("without manifest: modified %v, changed %v; want both %v", ayo.Modified, ayo.Changed, want)
+	}
+}
+
+func findAgentUpdate(updates []AgentUpdate, handle string) AgentUpdate {
+	for _, u := range updates {
+		if u.Handle == handle {
+			return u
+		}
+	}
+	return AgentUpdate{}
+}
@@ -2,10 +2,16 @@ package builtin
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/alexcabrera/ayo/internal/paths"
 )
@@ -21,121 +27,192 @@ type ModifiedAgent struct {
 	ModifiedFiles []string // List of modified file names
 }
 
-// CheckModifiedAgents compares installed agents against embedded versions
-// and returns a list of agents that have been modified locally.
+// CheckModifiedAgents compares installed agents against the files that were
+// installed and returns the agents that have been modified locally.
 func CheckModifiedAgents() ([]ModifiedAgent, error) {
-	installDir := InstallDir()
+	updates, err := checkAgentUpdates(InstallDir())
+	if err != nil {
+		return nil, err
+	}
+
 	var modified []ModifiedAgent
+	for _, u := range updates {
+		if len(u.Modified) > 0 {
+			modified = append(modified, ModifiedAgent{
+				Handle:        u.Handle,
+				InstalledDir:  u.InstalledDir,
+				ModifiedFiles: u.Modified,
+			})
+		}
+	}
+	return modified, nil
+}
+
+// AgentUpdate describes how an installed built-in agent differs from the
+// version embedded in this release.
+type AgentUpdate struct {
+	Handle       string   // e.g., "@ayo"
+	InstalledDir string   // Full path to installed agent dir
+	Installed    bool     // False when the agent isn't installed yet
+	Changed      []string // Files that differ between the installed release and this one
+	Modified     []string // Files changed locally since they were installed
+}
+
+// UpdateAvailable reports whether this release changes the agent.
+func (u AgentUpdate) UpdateAvailable() bool {
+	return !u.Installed || len(u.Changed) > 0
+}
+
+// CheckAgentUpdates compares every embedded agent against its installed copy.
+func CheckAgentUpdates() ([]AgentUpdate, error) {
+	return checkAgentUpdates(InstallDir())
+}
 
-	// Get list of embedded agents
+func checkAgentUpdates(installDir string) ([]AgentUpdate, error) {
 	entries, err := agentsFS.ReadDir("agents")
 	if err != nil {
 		return nil, err
 	}
+	manifest := loadAgentsManifest(installDir)
 
+	var updates []AgentUpdate
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
 		handle := entry.Name() // e.g., "@ayo"
-		agentInstallDir := filepath.Join(installDir, handle)
+		update := AgentUpdate{
+			Handle:       handle,
+			InstalledDir: filepath.Join(installDir, handle),
+		}
 
-		// Check if agent is installed
-		if _, err := os.Stat(agentInstallDir); os.IsNotExist(err) {
-			continue // Not installed, nothing to compare
+		if _, err := os.Stat(update.InstalledDir); os.IsNotExist(err) {
+			updates = append(updates, update)
+			continue
 		}
+		update.Installed = true
 
-		// Compare files
-		modifiedFiles, err := compareAgentFiles(handle, agentInstallDir)
+		embedded, err := embeddedAgentHashes(handle)
+		if err != nil {
+			continue // Skip on error
+		}
+		installed, err := dirHashes(update.InstalledDir)
 		if err != nil {
 			continue // Skip on error
 		}
 
-		if len(modifiedFiles) > 0 {
-			modified = append(modified, ModifiedAgent{
-				Handle:        handle,
-				InstalledDir:  agentInstallDir,
-				ModifiedFiles: modifiedFiles,
-			})
+		// Compare against the files as they were installed. Installs from
+		// before the manifest was recorded can't tell local modifications
+		// from updates, so every difference counts as both.
+		if base, ok := manifest[handle]; ok {
+			update.Modified = diffHashes(base, installed, "deleted", "added")
+			update.Changed = diffHashes(base, embedded, "removed", "new")
+		} else {
+			update.Modified = diffHashes(embedded, installed, "deleted", "added")
+			update.Changed = update.Modified
 		}
+		updates = append(updates, update)
 	}
 
-	return modified, nil
+	return updates, nil
 }
 
-// compareAgentFiles compares installed files against embedded files for an agent
-func compareAgentFiles(handle, installedDir string) ([]string, error) {
-	var modifiedFiles []string
-	embeddedBase := filepath.Join("agents", handle)
+// agentsManifestFile records the content hashes of the installed agent
+// files, so local modifications can be told apart from updates.
+const agentsManifestFile = ".manifest.json"
 
-	err := fs.WalkDir(agentsFS, embeddedBase, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if d.IsDir() {
-			return nil
-		}
+// loadAgentsManifest returns the installed file hashes by agent handle and
+// relative path, or nil if no manifest was recorded.
+func loadAgentsManifest(installDir string) map[string]map[string]string {
+	data, err := os.ReadFile(filepath.Join(installDir, agentsManifestFile))
+	if err != nil {
+		return nil
+	}
+	var manifest map[string]map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+	return manifest
+}
 
-		// Get relative path from agent dir
-		relPath, _ := filepath.Rel(embeddedBase, path)
-		installedPath := filepath.Join(installedDir, relPath)
+func writeAgentsManifest(installDir string, manifest map[string]map[string]string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(installDir, agentsManifestFile), data, 0o644)
+}
 
-		// Read embedded file
-		embeddedData, err := agentsFS.ReadFile(path)
-		if err != nil {
-			return nil // Skip
+// embeddedAgentHashes returns the content hashes of an embedded agent's
+// files by path relative to the agent directory.
+func embeddedAgentHashes(handle string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	embeddedBase := path.Join("agents", handle)
+	err := fs.WalkDir(agentsFS, embeddedBase, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
 		}
-
-		// Read installed file
-		installedData, err := os.ReadFile(installedPath)
+		data, err := agentsFS.ReadFile(p)
 		if err != nil {
-			if os.IsNotExist(err) {
-				// File was deleted - consider it modified
-				modifiedFiles = append(modifiedFiles, relPath+" (deleted)")
-			}
-			return nil
-		}
-
-		// Compare content
-		if !bytes.Equal(embeddedData, installedData) {
-			modifiedFiles = append(modifiedFiles, relPath)
+			return err
 		}
-
+		hashes[strings.TrimPrefix(p, embeddedBase+"/")] = fileHash(data)
 		return nil
 	})
+	return hashes, err
+}
 
-	if err != nil {
-		return nil, err
-	}
-
-	// Also check for extra files that don't exist in embedded
-	err = filepath.WalkDir(installedDir, func(path string, d fs.DirEntry, err error) error {
+// dirHashes returns the content hashes of the files under dir by
+// slash-separated relative path.
+func dirHashes(dir string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
 		if err != nil {
 			return err
 		}
+		relPath, _ := filepath.Rel(dir, p)
+		hashes[filepath.ToSlash(relPath)] = fileHash(data)
+		return nil
+	})
+	return hashes, err
+}
 
-		if d.IsDir() {
-			return nil
+// diffHashes lists the files that differ between two sets of hashes, sorted.
+// Files only in from or only in to are labeled with gone or extra.
+func diffHashes(from, to map[string]string, gone, extra string) []string {
+	var diff []string
+	for name, hash := range from {
+		other, ok := to[name]
+		switch {
+		case !ok:
+			diff = append(diff, name+" ("+gone+")")
+		case other != hash:
+			diff = append(diff, name)
 		}
-
-		relPath, _ := filepath.Rel(installedDir, path)
-		embeddedPath := filepath.Join(embeddedBase, relPath)
-
-		if _, err := agentsFS.ReadFile(embeddedPath); err != nil {
-			// File exists in installed but not in embedded - it's an addition
-			modifiedFiles = append(modifiedFiles, relPath+" (added)")
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			diff = append(diff, name+" ("+extra+")")
 		}
+	}
+	sort.Strings(diff)
+	return diff
+}
 
-		return nil
-	})
-
-	return modifiedFiles, err
+func fileHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // Install extracts built-in agents and skills to the platform-specific install directory.
 // It only reinstalls if the version has changed or content is missing.
+// Agents with local modifications are left in place; use ForceInstall to
+// replace them.
 func Install() error {
 	versionFile := VersionFile()
 
@@ -144,13 +221,30 @@ func Install() error {
 		return nil
 	}
 
-	_, err := ForceInstall()
+	// Only a recorded manifest tells local modifications apart from updates
+	keep := make(map[string]bool)
+	if loadAgentsManifest(InstallDir()) != nil {
+		modified, err := CheckModifiedAgents()
+		if err != nil {
+			return err
+		}
+		for _, m := range modified {
+			keep[m.Handle] = true
+		}
+	}
+
+	_, err := install(keep)
 	return err
 }
 
-// ForceInstall extracts built-in agents and skills regardless of version.
-// Returns the install directory path.
+// ForceInstall extracts built-in agents and skills regardless of version,
+// replacing local modifications. Returns the install directory path.
 func ForceInstall() (string, error) {
+	return install(nil)
+}
+
+// install extracts built-in agents and skills, skipping the agents in keep.
+func install(keep map[string]bool) (string, error) {
 	installDir := InstallDir()
 	skillsInstallDir := SkillsInstallDir()
 	promptsInstallDir := PromptsInstallDir()
@@ -168,7 +262,7 @@ func ForceInstall() (string, error) {
 	}
 
 	// Extract all agents (including agent-specific skills)
-	if err := extractAgents(installDir); err != nil {
+	if err := extractAgents(installDir, keep); err != nil {
 		return "", fmt.Errorf("extract agents: %w", err)
 	}
 
@@ -204,9 +298,15 @@ func needsInstall(versionFile string) bool {
 	return string(data) != Version
 }
 
-// extractAgents copies all embedded agents to the install directory
-func extractAgents(installDir string) error {
-	return fs.WalkDir(agentsFS, "agents", func(path string, d fs.DirEntry, err error) error {
+// extractAgents copies all embedded agents to the install directory, except
+// the agents in keep, and records the installed files in the manifest.
+func extractAgents(installDir string, keep map[string]bool) error {
+	manifest := loadAgentsManifest(installDir)
+	if manifest == nil {
+		manifest = make(map[string]map[string]string)
+	}
+
+	err := fs.WalkDir(agentsFS, "agents", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -220,7 +320,18 @@ func extractAgents(installDir string) error {
 		relPath, _ := filepath.Rel("agents", path)
 		destPath := filepath.Join(installDir, relPath)
 
+		handle, agentPath, _ := strings.Cut(filepath.ToSlash(relPath), "/")
+		if keep[handle] {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
 		if d.IsDir() {
+			if agentPath == "" {
+				manifest[handle] = make(map[string]string)
+			}
 			return os.MkdirAll(destPath, 0o755)
 		}
 
@@ -234,9 +345,15 @@ func extractAgents(installDir string) error {
 		if err := os.WriteFile(destPath, data, 0o644); err != nil {
 			return fmt.Errorf("write %s: %w", destPath, err)
 		}
+		manifest[handle][agentPath] = fileHash(data)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return writeAgentsManifest(installDir, manifest)
 }
 
 // extractSkills copies all shared embedded skills to the install directory
@@ -0,0 +1,281 @@
+// Package bundle fetches AYO_BUNDLE bundles: archives of agents, skills,
+// prompts, flows, and an ayo.json config, downloaded from a URL or an OCI
+// registry so a CI job can run shared agents without cloning them.
+//
+// A bundle is a tar (optionally gzipped) archive laid out like a config
+// directory:
+//
+//	ayo.json
+//	agents/@handle/...
+//	skills/name/SKILL.md
+//	prompts/system-prefix.md
+//	flows/name.sh
+//
+// Bundles are extracted into a cache directory named by digest and reused
+// until the digest changes. Only a reference pinned to a digest guarantees
+// the contents; a tag or plain URL can serve different contents each run.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvVar is the environment variable holding the bundle reference.
+const EnvVar = "AYO_BUNDLE"
+
+// maxExtractSize bounds the total size of the files in a bundle, so a
+// small compressed archive can't fill the disk when extracted.
+var maxExtractSize int64 = 256 << 20
+
+// Bundle is a fetched bundle extracted on disk.
+type Bundle struct {
+	Ref    string // Reference it was fetched from
+	Digest string // Cache key, e.g. "sha256:..."
+	Dir    string // Extracted contents
+	Cached bool   // Whether the contents were already in the cache
+	Pinned bool   // Whether Ref names a digest the contents were checked against
+}
+
+// ConfigFile returns the bundle's ayo.json, or empty string if it has none.
+func (b *Bundle) ConfigFile() string {
+	path := filepath.Join(b.Dir, "ayo.json")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Fetch downloads the bundle at ref into cacheDir, or reuses the cached copy.
+//
+// ref is either an http(s) URL of a tar or tar.gz archive, optionally pinned
+// with a "#sha256=<hex>" fragment, or an OCI reference such as
+// "oci://ghcr.io/org/agents:v1" or "ghcr.io/org/agents@sha256:<hex>" whose
+// artifact has a single archive layer. Downloads are verified against their
+// pinned or advertised digests before they are extracted.
+func Fetch(ctx context.Context, ref, cacheDir string) (*Bundle, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, errors.New("bundle reference is empty")
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create bundle cache: %w", err)
+	}
+
+	var b *Bundle
+	var err error
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		b, err = fetchURL(ctx, ref, cacheDir)
+	} else {
+		b, err = fetchOCI(ctx, ref, cacheDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.Pinned = pinned(ref)
+	return b, nil
+}
+
+// pinned reports whether ref names the digest of its contents: a URL with
+// a "#sha256=" fragment or an OCI reference with "@sha256:". Fetch rejects
+// malformed digests, so the prefix is enough.
+func pinned(ref string) bool {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return strings.Contains(ref, "#sha256=")
+	}
+	return strings.Contains(ref, "@sha256:")
+}
+
+// fetchURL fetches an archive over HTTP. A pinned digest is checked against
+// the cache first, so pinned bundles are only downloaded once.
+func fetchURL(ctx context.Context, ref, cacheDir string) (*Bundle, error) {
+	url, pin, _ := strings.Cut(ref, "#")
+	var want string
+	if pin != "" {
+		hexDigest, ok := strings.CutPrefix(pin, "sha256=")
+		if !ok || !validHex(hexDigest) {
+			return nil, fmt.Errorf("invalid digest pin %q: want #sha256=<hex>", pin)
+		}
+		want = "sha256:" + strings.ToLower(hexDigest)
+		if b := cached(ref, want, cacheDir); b != nil {
+			return b, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch bundle %s: %s", url, resp.Status)
+	}
+
+	archive, digest, err := download(resp.Body, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive)
+	if want != "" && digest != want {
+		return nil, fmt.Errorf("bundle digest mismatch: got %s, want %s", digest, want)
+	}
+	return extractCached(ref, digest, archive, cacheDir)
+}
+
+// cached returns the cached bundle for digest, or nil if it isn't cached.
+func cached(ref, digest, cacheDir string) *Bundle {
+	dir := cacheEntry(cacheDir, digest)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	return &Bundle{Ref: ref, Digest: digest, Dir: dir, Cached: true}
+}
+
+// cacheEntry returns the cache directory for digest.
+func cacheEntry(cacheDir, digest string) string {
+	return filepath.Join(cacheDir, strings.ReplaceAll(digest, ":", "-"))
+}
+
+// download writes r to a temporary file in dir and returns its path and
+// sha256 digest.
+func download(r io.Reader, dir string) (string, string, error) {
+	f, err := os.CreateTemp(dir, ".download-")
+	if err != nil {
+		return "", "", fmt.Errorf("download bundle: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		os.Remove(f.Name())
+		return "", "", fmt.Errorf("download bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", "", fmt.Errorf("download bundle: %w", err)
+	}
+	return f.Name(), "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractCached extracts archive into the cache entry for digest, unless
+// another invocation got there first.
+func extractCached(ref, digest, archive, cacheDir string) (*Bundle, error) {
+	if b := cached(ref, digest, cacheDir); b != nil {
+		return b, nil
+	}
+
+	tmp, err := os.MkdirTemp(cacheDir, ".extract-")
+	if err != nil {
+		return nil, fmt.Errorf("extract bundle: %w", err)
+	}
+	if err := extract(archive, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("extract bundle: %w", err)
+	}
+
+	dir := cacheEntry(cacheDir, digest)
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		if b := cached(ref, digest, cacheDir); b != nil {
+			return b, nil
+		}
+		return nil, fmt.Errorf("extract bundle: %w", err)
+	}
+	return &Bundle{Ref: ref, Digest: digest, Dir: dir}, nil
+}
+
+// extract unpacks a tar or tar.gz archive into dir. Entries that would land
+// outside dir, links, and special files are rejected, as are archives whose
+// files add up to more than maxExtractSize.
+func extract(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q is outside the bundle", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if total += hdr.Size; total > maxExtractSize {
+				return fmt.Errorf("bundle is larger than %d MiB when extracted", maxExtractSize>>20)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode)&0o755|0o644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("archive entry %q: unsupported type %q", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// validHex reports whether s is a hex-encoded sha256 digest.
+func validHex(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,261 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeArchive returns a tar.gz of files.
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestFetchURL(t *testing.T) {
+	archive := makeArchive(t, map[string]string{
+		"ayo.json":                   `{"default_model": "gpt-4.1"}`,
+		"agents/@ci/config.json":     `{}`,
+		"agents/@ci/system.md":       "You review code.",
+		"prompts/system-prefix.md":   "Be brief.",
+		"skills/review/SKILL.md":     "---\nname: review\n---\n",
+		"./skills/review/extra.md":   "extra",
+		"skills/review/scripts/x.sh": "#!/bin/sh\n",
+	})
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDir := t.TempDir()
+	ref := server.URL + "/bundle.tar.gz#sha256=" + strings.TrimPrefix(digestOf(archive), "sha256:")
+
+	b, err := Fetch(context.Background(), ref, cacheDir)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if b.Cached || !b.Pinned || b.Digest != digestOf(archive) {
+		t.Errorf("Fetch = %+v, want fresh pinned bundle with digest %s", b, digestOf(archive))
+	}
+	if data, err := os.ReadFile(filepath.Join(b.Dir, "agents", "@ci", "system.md")); err != nil || string(data) != "You review code." {
+		t.Errorf("agent system.md = %q, %v", data, err)
+	}
+	if b.ConfigFile() != filepath.Join(b.Dir, "ayo.json") {
+		t.Errorf("ConfigFile = %q", b.ConfigFile())
+	}
+
+	// A pinned bundle is served from the cache without a request.
+	b, err = Fetch(context.Background(), ref, cacheDir)
+	if err != nil {
+		t.Fatalf("Fetch cached: %v", err)
+	}
+	if !b.Cached || requests != 1 {
+		t.Errorf("second Fetch: cached = %v, requests = %d, want cached with 1 request", b.Cached, requests)
+	}
+
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Errorf("cache has %d entries, want only the bundle", len(entries))
+	}
+}
+
+func TestFetchURLDigestMismatch(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"ayo.json": "{}"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	ref := server.URL + "/bundle.tar.gz#sha256=" + strings.Repeat("0", 64)
+	_, err := Fetch(context.Background(), ref, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("Fetch = %v, want digest mismatch", err)
+	}
+}
+
+func TestFetchRejectsEscapingEntries(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"../evil.txt": "x"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDir := t.TempDir()
+	_, err := Fetch(context.Background(), server.URL+"/bundle.tar.gz", cacheDir)
+	if err == nil || !strings.Contains(err.Error(), "outside the bundle") {
+		t.Errorf("Fetch = %v, want outside the bundle error", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(cacheDir), "evil.txt")); err == nil {
+		t.Error("archive entry was written outside the cache")
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("cache has %d leftover entries", len(entries))
+	}
+}
+
+func TestFetchRejectsOversizedBundle(t *testing.T) {
+	old := maxExtractSize
+	maxExtractSize = 8
+	t.Cleanup(func() { maxExtractSize = old })
+
+	archive := makeArchive(t, map[string]string{"a.md": "12345", "b.md": "67890"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDir := t.TempDir()
+	_, err := Fetch(context.Background(), server.URL+"/bundle.tar.gz", cacheDir)
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Fetch = %v, want size limit error", err)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("cache has %d leftover entries", len(entries))
+	}
+}
+
+func TestPinned(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"https://example.com/bundle.tar.gz", false},
+		{"https://example.com/bundle.tar.gz#sha256=" + strings.Repeat("a", 64), true},
+		{"oci://ghcr.io/org/ayo-bundle:v1", false},
+		{"ghcr.io/org/ayo-bundle@sha256:" + strings.Repeat("a", 64), true},
+	}
+	for _, tt := range tests {
+		if got := pinned(tt.ref); got != tt.want {
+			t.Errorf("pinned(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestFetchOCI(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"agents/@ci/system.md": "hi"})
+	layerDigest := digestOf(archive)
+	manifest, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]any{
+			{"mediaType": "application/vnd.ayo.bundle.v1.tar+gzip", "digest": layerDigest, "size": len(archive)},
+		},
+	})
+
+	var blobRequests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:org/agents:pull" {
+				t.Errorf("token scope = %q", r.URL.Query().Get("scope"))
+			}
+			w.Write([]byte(`{"token": "anon"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anon" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test",scope="repository:org/agents:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/org/agents/manifests/v1", "/v2/org/agents/manifests/" + digestOf(manifest):
+			w.Write(manifest)
+		case "/v2/org/agents/blobs/" + layerDigest:
+			blobRequests++
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	registry := strings.TrimPrefix(server.URL, "http://")
+	cacheDir := t.TempDir()
+
+	b, err := Fetch(context.Background(), "oci://"+registry+"/org/agents:v1", cacheDir)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if b.Digest != digestOf(manifest) {
+		t.Errorf("Digest = %s, want manifest digest %s", b.Digest, digestOf(manifest))
+	}
+	if data, err := os.ReadFile(filepath.Join(b.Dir, "agents", "@ci", "system.md")); err != nil || string(data) != "hi" {
+		t.Errorf("agent system.md = %q, %v", data, err)
+	}
+
+	// The tag is re-resolved, but the unchanged digest skips the blob.
+	b, err = Fetch(context.Background(), registry+"/org/agents:v1", cacheDir)
+	if err != nil {
+		t.Fatalf("Fetch again: %v", err)
+	}
+	if !b.Cached || blobRequests != 1 {
+		t.Errorf("second Fetch: cached = %v, blob requests = %d", b.Cached, blobRequests)
+	}
+
+	_, err = Fetch(context.Background(), registry+"/org/agents@sha256:"+strings.Repeat("0", 64), t.TempDir())
+	if err == nil {
+		t.Error("Fetch with a digest the registry doesn't have succeeded")
+	}
+}
+
+func TestParseOCIRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		ref  string
+		want ociRef
+	}{
+		{"oci://ghcr.io/org/agents:v1", ociRef{"ghcr.io", "org/agents", "v1"}},
+		{"ghcr.io/org/agents", ociRef{"ghcr.io", "org/agents", "latest"}},
+		{"localhost:5000/agents@" + digest, ociRef{"localhost:5000", "agents", digest}},
+	}
+	for _, tt := range tests {
+		got, err := parseOCIRef(tt.ref)
+		if err != nil {
+			t.Errorf("parseOCIRef(%q): %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseOCIRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"agents", "ghcr.io/org/agents@sha256:nothex"} {
+		if _, err := parseOCIRef(ref); err == nil {
+			t.Errorf("parseOCIRef(%q) succeeded, want error", ref)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// manifestAccept lists the manifest media types ayo understands.
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// maxManifestSize bounds how much of a manifest response is read.
+const maxManifestSize = 4 << 20
+
+// ociRef is a parsed OCI reference.
+type ociRef struct {
+	registry  string
+	repo      string
+	reference string // Tag or digest
+}
+
+// parseOCIRef parses "[oci://]registry/repo[:tag|@digest]". The tag
+// defaults to "latest".
+func parseOCIRef(ref string) (ociRef, error) {
+	s := strings.TrimPrefix(ref, "oci://")
+	registry, repo, ok := strings.Cut(s, "/")
+	if !ok || registry == "" || repo == "" {
+		return ociRef{}, fmt.Errorf("invalid bundle reference %q: want an http(s) URL or registry/repository[:tag|@digest]", ref)
+	}
+
+	r := ociRef{registry: registry, repo: repo, reference: "latest"}
+	if name, digest, ok := strings.Cut(repo, "@"); ok {
+		hexDigest, ok := strings.CutPrefix(digest, "sha256:")
+		if !ok || !validHex(hexDigest) {
+			return ociRef{}, fmt.Errorf("invalid bundle digest %q: want sha256:<hex>", digest)
+		}
+		r.repo, r.reference = name, strings.ToLower(digest)
+	} else if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		r.repo, r.reference = repo[:i], repo[i+1:]
+	}
+	if r.repo == "" || r.reference == "" {
+		return ociRef{}, fmt.Errorf("invalid bundle reference %q", ref)
+	}
+	return r, nil
+}
+
+// pinned reports whether the reference is a digest rather than a tag.
+func (r ociRef) pinned() bool {
+	return strings.HasPrefix(r.reference, "sha256:")
+}
+
+// baseURL returns the registry API base. Local registries are spoken to
+// over plain HTTP.
+func (r ociRef) baseURL() string {
+	host := r.registry
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	if host == "localhost" || strings.HasPrefix(host, "127.") {
+		return "http://" + r.registry + "/v2/" + r.repo
+	}
+	return "https://" + r.registry + "/v2/" + r.repo
+}
+
+// ociManifest is the part of an image manifest ayo reads.
+type ociManifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []any  `json:"manifests"`
+	Layers    []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+}
+
+// fetchOCI pulls a single-layer artifact from an OCI registry. The cache is
+// keyed by manifest digest, so a pinned reference needs no network access
+// once cached and a tag only costs a manifest request.
+func fetchOCI(ctx context.Context, ref, cacheDir string) (*Bundle, error) {
+	r, err := parseOCIRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if r.pinned() {
+		if b := cached(ref, r.reference, cacheDir); b != nil {
+			return b, nil
+		}
+	}
+
+	c := &registryClient{}
+	resp, err := c.get(ctx, r.baseURL()+"/manifests/"+r.reference, manifestAccept)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle manifest: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if r.pinned() && digest != r.reference {
+		return nil, fmt.Errorf("bundle manifest digest mismatch: got %s, want %s", digest, r.reference)
+	}
+	if b := cached(ref, digest, cacheDir); b != nil {
+		return b, nil
+	}
+
+	var m ociManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("parse bundle manifest: %w", err)
+	}
+	if len(m.Manifests) > 0 {
+		return nil, errors.New("bundle reference is an image index; push the bundle as a single-layer artifact")
+	}
+	if len(m.Layers) != 1 {
+		return nil, fmt.Errorf("bundle artifact has %d layers, want 1", len(m.Layers))
+	}
+	layer := m.Layers[0]
+
+	resp, err = c.get(ctx, r.baseURL()+"/blobs/"+layer.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	archive, got, err := download(resp.Body, cacheDir)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive)
+	if got != layer.Digest {
+		return nil, fmt.Errorf("bundle layer digest mismatch: got %s, want %s", got, layer.Digest)
+	}
+	return extractCached(ref, digest, archive, cacheDir)
+}
+
+// registryClient makes registry requests, fetching an anonymous bearer
+// token when the registry asks for one.
+type registryClient struct {
+	token string
+}
+
+// get issues a GET and returns the response if it succeeded.
+func (c *registryClient) get(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	resp, err := c.do(ctx, endpoint, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if c.token, err = fetchToken(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(ctx, endpoint, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetch bundle %s: %s", endpoint, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *registryClient) do(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle: %w", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch bundle: %w", err)
+	}
+	return resp, nil
+}
+
+// fetchToken answers a Bearer challenge with an anonymous token request.
+func fetchToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("bundle registry requires unsupported authentication %q", scheme)
+	}
+
+	fields := map[string]string{}
+	for _, p := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if ok {
+			fields[k] = strings.Trim(v, `"`)
+		}
+	}
+	realm := fields["realm"]
+	if realm == "" {
+		return "", errors.New("bundle registry auth challenge has no realm")
+	}
+	q := url.Values{}
+	for _, k := range []string{"service", "scope"} {
+		if fields[k] != "" {
+			q.Set(k, fields[k])
+		}
+	}
+	if len(q) > 0 {
+		realm += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+	if err != nil {
+		return "", fmt.Errorf("fetch bundle token: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch bundle token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch bundle token: %s", resp.Status)
+	}
+
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("parse bundle token: %w", err)
+	}
+	if tok.Token != "" {
+		return tok.Token, nil
+	}
+	if tok.AccessToken != "" {
+		return tok.AccessToken, nil
+	}
+	return "", errors.New("bundle registry returned an empty token")
+}
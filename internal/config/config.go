@@ -3,8 +3,11 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/alexcabrera/ayo/internal/paths"
@@ -16,8 +19,8 @@ import (
 type Config struct {
 	Schema         string           `json:"$schema,omitempty"`
 	AgentsDir      string           `json:"agents_dir,omitempty"`
-	SystemPrefix   string           `json:"system_prefix,omitempty"`
-	SystemSuffix   string           `json:"system_suffix,omitempty"`
+	SystemPrefix   PromptFiles      `json:"system_prefix,omitempty"`
+	SystemSuffix   PromptFiles      `json:"system_suffix,omitempty"`
 	SkillsDir      string           `json:"skills_dir,omitempty"`
 	DefaultModel   string           `json:"default_model,omitempty"`
 	SmallModel     string           `json:"small_model,omitempty"`
@@ -30,16 +33,87 @@ type Config struct {
 	// Flows configuration
 	Flows FlowsConfig `json:"flows,omitempty"`
 
+	// Titles configures automatic session title generation
+	Titles TitlesConfig `json:"titles,omitempty"`
+
+	// Redaction masks secrets in messages before they reach the provider
+	Redaction RedactionConfig `json:"redaction,omitempty"`
+
+	// UI configures terminal output
+	UI UIConfig `json:"ui,omitempty"`
+
+	// Chat configures interactive chat sessions
+	Chat ChatConfig `json:"chat,omitempty"`
+
+	// Sessions configures what is stored in saved sessions
+	Sessions SessionsConfig `json:"sessions,omitempty"`
+
+	// HTTP configures the built-in http tool
+	HTTP HTTPToolConfig `json:"http,omitempty"`
+
+	// Bash configures the built-in bash tool
+	Bash BashConfig `json:"bash,omitempty"`
+
+	// Capture records turns to a JSONL dataset for evals and fine-tuning
+	Capture CaptureConfig `json:"capture,omitempty"`
+
+	// Cache reuses responses of deterministic agents
+	Cache CacheConfig `json:"cache,omitempty"`
+
+	// RateLimit throttles calls to the provider across concurrent agents
+	RateLimit RateLimitConfig `json:"rate_limit,omitempty"`
+
+	// Serve configures the local HTTP API started by `ayo serve`
+	Serve ServeConfig `json:"serve,omitempty"`
+
+	// Offline only allows model calls to providers on this machine, such as
+	// Ollama. Title generation and memory formation are skipped.
+	Offline bool `json:"offline,omitempty"`
+
 	// Delegates maps task types to agent handles for global delegation.
 	// Example: {"coding": "@crush", "research": "@research"}
 	Delegates map[string]string `json:"delegates,omitempty"`
 
+	// MaxDelegationDepth limits how deeply agent_call invocations can nest.
+	// nil uses the default of 3; 0 disables delegation entirely.
+	MaxDelegationDepth *int `json:"max_delegation_depth,omitempty"`
+
 	// DefaultTools maps tool type aliases to concrete tool names.
 	// Example: {"search": "searxng"}
 	// This allows agents to use generic tool types that resolve to user-configured tools.
 	DefaultTools map[string]string `json:"default_tools,omitempty"`
 }
 
+// PromptFiles is a list of prompt files merged in order. In JSON it is a
+// single path or an array of paths.
+type PromptFiles []string
+
+// UnmarshalJSON accepts a path or an array of paths.
+func (p *PromptFiles) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*p = nil
+		if single != "" {
+			*p = PromptFiles{single}
+		}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("prompt files must be a path or an array of paths")
+	}
+	*p = list
+	return nil
+}
+
+// MarshalJSON writes a single file as a plain path.
+func (p PromptFiles) MarshalJSON() ([]byte, error) {
+	if len(p) == 1 {
+		return json.Marshal(p[0])
+	}
+	return json.Marshal([]string(p))
+}
+
 // FlowsConfig configures the flows system.
 type FlowsConfig struct {
 	// HistoryRetentionDays is the maximum age of flow run history in days.
@@ -49,6 +123,200 @@ type FlowsConfig struct {
 	// HistoryMaxRuns is the maximum number of flow runs to keep.
 	// Excess runs are pruned (oldest first). Default: 1000.
 	HistoryMaxRuns int `json:"history_max_runs,omitempty"`
+
+	// Webhook is a URL that receives each flow run's result as a JSON POST.
+	// `ayo flows run --webhook` overrides it.
+	Webhook string `json:"webhook,omitempty"`
+
+	// WebhookSecret signs webhook payloads with HMAC-SHA256 in the
+	// X-Ayo-Signature header. The AYO_WEBHOOK_SECRET environment variable
+	// takes precedence.
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+}
+
+// TitlesConfig configures LLM-generated session titles.
+type TitlesConfig struct {
+	// Disabled turns off LLM title generation. Sessions keep the
+	// truncated first prompt as their title.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Model is the model used to generate titles. A small, cheap model is
+	// recommended. Default: the agent's model.
+	Model string `json:"model,omitempty"`
+
+	// Prompt overrides the title generation prompt. The placeholders
+	// {{user}} and {{assistant}} are replaced with the first exchange.
+	// If neither placeholder is present, the exchange is appended.
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// RedactionConfig configures secret redaction in user messages and tool output.
+type RedactionConfig struct {
+	// Disabled turns off redaction. Messages are sent unmodified.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Patterns are additional regular expressions to redact. A pattern with
+	// a named group "secret" redacts only that group.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// DisableBuiltin lists built-in patterns to skip, e.g. "bearer-token".
+	DisableBuiltin []string `json:"disable_builtin,omitempty"`
+}
+
+// UIConfig configures terminal output.
+type UIConfig struct {
+	// ToolOutput configures how long tool output is truncated for display.
+	ToolOutput ToolOutputConfig `json:"tool_output,omitempty"`
+
+	// Theme is a built-in theme ("dark", "light", "mono") or the path of a
+	// JSON theme file. Default: "dark". NO_COLOR forces "mono".
+	Theme string `json:"theme,omitempty"`
+
+	// Pager shows output taller than the terminal: "auto" (default) uses
+	// $PAGER, then less, then more; "never" turns paging off; anything
+	// else is the pager command.
+	Pager string `json:"pager,omitempty"`
+
+	// JSONIndent is the number of spaces --json output is indented by.
+	// 0 writes each value on one line. Default: 2.
+	JSONIndent *int `json:"json_indent,omitempty"`
+}
+
+// ToolOutputConfig configures truncation of long tool output. It affects
+// display only; the model always receives the full output.
+type ToolOutputConfig struct {
+	// Strategy selects which lines are shown: "head_tail", "head", "tail",
+	// or "smart", which keeps error lines and bash stderr first.
+	// Default: "head_tail" for command output, "head" for boxed tool results.
+	Strategy string `json:"strategy,omitempty"`
+
+	// MaxLines is the number of lines shown before truncating.
+	// Default: 20 for command output, 50 for boxed tool results.
+	MaxLines int `json:"max_lines,omitempty"`
+
+	// ErrorPattern overrides the regular expression for lines the smart
+	// strategy keeps.
+	ErrorPattern string `json:"error_pattern,omitempty"`
+}
+
+// ChatConfig configures interactive chat sessions.
+type ChatConfig struct {
+	// ContextStrategy selects how much history is sent with each message:
+	// "tokens" (default) keeps the most recent turns within MaxTokens,
+	// "turns" keeps the last MaxTurns turns, and "full" sends everything.
+	// System messages are always sent, and the stored session keeps the
+	// full history.
+	ContextStrategy string `json:"context_strategy,omitempty"`
+
+	// MaxTurns is the number of recent turns kept by the "turns" strategy.
+	// A turn is a user message and everything after it. Default: 20.
+	MaxTurns int `json:"max_turns,omitempty"`
+
+	// MaxTokens is the estimated token budget of the "tokens" strategy.
+	// Default: 75% of the model's context window, when the provider
+	// publishes it.
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Summarize replaces dropped turns with a summary written by the small
+	// model. Without a small model, dropped turns are omitted.
+	Summarize bool `json:"summarize,omitempty"`
+
+	// IdleTimeout closes the chat after this many minutes without input,
+	// after a warning. Turns in progress are never interrupted. 0 disables
+	// the timeout.
+	IdleTimeout int `json:"idle_timeout,omitempty"`
+}
+
+// SessionsConfig configures saved sessions.
+type SessionsConfig struct {
+	// MaxToolResultBytes caps each tool result stored in a session. Longer
+	// results are truncated in storage only; the model still receives the
+	// full output. Default: 32 KB. Negative stores results in full.
+	MaxToolResultBytes int `json:"max_tool_result_bytes,omitempty"`
+}
+
+// HTTPToolConfig configures the built-in http tool.
+type HTTPToolConfig struct {
+	// AllowedHosts lists the hosts the tool may reach. Entries match a host
+	// exactly or, as "*.example.com", any subdomain. Empty blocks every host.
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+
+	// MaxResponseBytes caps the response body returned to the agent.
+	// Default: 64 KB.
+	MaxResponseBytes int `json:"max_response_bytes,omitempty"`
+
+	// TimeoutSeconds bounds each request. Default: 30.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// BashConfig configures the built-in bash tool.
+type BashConfig struct {
+	// DisableConfirm runs dangerous commands without asking for approval.
+	DisableConfirm bool `json:"disable_confirm,omitempty"`
+
+	// DangerousPatterns are additional regular expressions for commands
+	// that need approval before they run.
+	DangerousPatterns []string `json:"dangerous_patterns,omitempty"`
+
+	// DisableBuiltin lists built-in patterns to skip, e.g. "git-push-force".
+	DisableBuiltin []string `json:"disable_builtin,omitempty"`
+
+	// AllowNonInteractive runs dangerous commands when there is no terminal
+	// to ask, such as when stdin is piped. By default they are denied.
+	AllowNonInteractive bool `json:"allow_non_interactive,omitempty"`
+}
+
+// CaptureConfig configures recording of turns to an eval dataset.
+type CaptureConfig struct {
+	// Path is the JSONL file each successful turn is appended to; "~/"
+	// expands to the home directory. Empty disables capture. `ayo --capture` overrides it.
+	Path string `json:"path,omitempty"`
+
+	// SampleRate is the fraction of turns captured, from 0 to 1.
+	// nil captures every turn.
+	SampleRate *float64 `json:"sample_rate,omitempty"`
+
+	// DisableRedaction records secrets unmodified. By default captured
+	// text is redacted with the redaction patterns, even when redaction
+	// of provider requests is disabled.
+	DisableRedaction bool `json:"disable_redaction,omitempty"`
+}
+
+// CacheConfig configures the agent response cache. Only turns of agents
+// with temperature 0 that make no tool calls are cached.
+type CacheConfig struct {
+	// Enabled turns on the cache. `ayo --no-cache` bypasses it for a run.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TTLSeconds is how long a cached response is reused. Default: 86400.
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// RateLimitConfig throttles calls to the provider. All calls in one ayo
+// process share the limits, including parallel sub-agents, batch runs, and
+// title generation. Zero leaves a limit off.
+type RateLimitConfig struct {
+	// RequestsPerMinute caps model requests per minute.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+
+	// TokensPerMinute caps tokens per minute. Requests are admitted on
+	// their estimated prompt size and corrected by the reported usage.
+	TokensPerMinute int `json:"tokens_per_minute,omitempty"`
+}
+
+// ServeConfig configures the HTTP API started by `ayo serve`.
+type ServeConfig struct {
+	// Addr is the address to listen on. Default: 127.0.0.1:7878.
+	// Addresses other than loopback require a token.
+	Addr string `json:"addr,omitempty"`
+
+	// Token is the bearer token every request must carry. The
+	// AYO_SERVE_TOKEN environment variable takes precedence; without
+	// either, a random token is generated at startup.
+	Token string `json:"token,omitempty"`
+
+	// TimeoutSeconds bounds each agent run. Default: 300.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
 }
 
 // EmbeddingConfig configures the embedding system.
@@ -89,8 +357,8 @@ func Default() Config {
 
 	return Config{
 		AgentsDir:      paths.AgentsDir(),
-		SystemPrefix:   "", // Uses paths.FindPromptFile("system-prefix.md")
-		SystemSuffix:   "", // Uses paths.FindPromptFile("system-suffix.md")
+		SystemPrefix:   nil, // Uses paths.FindPromptFiles("system-prefix.md")
+		SystemSuffix:   nil, // Uses paths.FindPromptFiles("system-suffix.md")
 		SkillsDir:      paths.SkillsDir(),
 		DefaultModel:   defaultModel,
 		SmallModel:     smallModel,
@@ -114,9 +382,41 @@ func Default() Config {
 	}
 }
 
+// OfflineEnvVar turns on offline mode when set to a true value such as 1,
+// overriding the config file.
+const OfflineEnvVar = "AYO_OFFLINE"
+
+// offlineFromEnv reports whether OfflineEnvVar turns on offline mode.
+func offlineFromEnv() bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(OfflineEnvVar)))
+	return err == nil && on
+}
+
+// IsLocalEndpoint reports whether endpoint, a URL or host:port, is on this
+// machine: localhost or a loopback address.
+func IsLocalEndpoint(endpoint string) bool {
+	host := strings.TrimSpace(endpoint)
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // Load reads configuration from the given path, falling back to defaults when missing.
 func Load(path string) (Config, error) {
 	cfg := Default()
+	if offlineFromEnv() {
+		cfg.Offline = true
+	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -133,6 +433,10 @@ func Load(path string) (Config, error) {
 	if strings.TrimSpace(cfg.CatwalkBaseURL) == "" {
 		cfg.CatwalkBaseURL = defaultCatwalkURL()
 	}
+	// The environment wins over the file
+	if offlineFromEnv() {
+		cfg.Offline = true
+	}
 
 	return cfg, nil
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -19,12 +20,12 @@ func TestDefaultPaths(t *testing.T) {
 		t.Fatalf("skills dir mismatch: got %s, want %s", cfg.SkillsDir, paths.SkillsDir())
 	}
 
-	// System prompts are now resolved at load time via paths.FindPromptFile
-	// Default config has empty strings for SystemPrefix and SystemSuffix
-	if cfg.SystemPrefix != "" {
+	// System prompts are now resolved at load time via paths.FindPromptFiles
+	// Default config has no SystemPrefix or SystemSuffix files
+	if len(cfg.SystemPrefix) != 0 {
 		t.Fatalf("expected empty SystemPrefix, got %s", cfg.SystemPrefix)
 	}
-	if cfg.SystemSuffix != "" {
+	if len(cfg.SystemSuffix) != 0 {
 		t.Fatalf("expected empty SystemSuffix, got %s", cfg.SystemSuffix)
 	}
 
@@ -116,3 +117,71 @@ func TestLoadMissingConfig(t *testing.T) {
 		t.Error("expected non-empty default model")
 	}
 }
+
+func TestLoadOfflineFromEnv(t *testing.T) {
+	path := t.TempDir() + "/ayo.json"
+	if err := os.WriteFile(path, []byte(`{"offline": false}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(OfflineEnvVar, "1")
+	for _, p := range []string{path, "/nonexistent/path/ayo.json"} {
+		cfg, err := Load(p)
+		if err != nil {
+			t.Fatalf("load %s: %v", p, err)
+		}
+		if !cfg.Offline {
+			t.Errorf("Load(%s) with %s=1: Offline = false", p, OfflineEnvVar)
+		}
+	}
+
+	t.Setenv(OfflineEnvVar, "no")
+	if cfg, _ := Load(path); cfg.Offline {
+		t.Errorf("Load with %s=no: Offline = true", OfflineEnvVar)
+	}
+}
+
+func TestIsLocalEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"http://localhost:11434":     true,
+		"http://127.0.0.1:11434/v1":  true,
+		"http://[::1]:8080":          true,
+		"localhost:11434":            true,
+		"http://ollama.localhost":    true,
+		"https://api.openai.com/v1":  false,
+		"http://192.168.1.20:11434":  false,
+		"http://localhost.evil.com/": false,
+		"":                           false,
+	}
+	for endpoint, want := range tests {
+		if got := IsLocalEndpoint(endpoint); got != want {
+			t.Errorf("IsLocalEndpoint(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+}
+
+func TestPromptFilesJSON(t *testing.T) {
+	var cfg Config
+	data := `{"system_prefix": "org.md", "system_suffix": ["org-suffix.md", "team-suffix.md"]}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(cfg.SystemPrefix) != 1 || cfg.SystemPrefix[0] != "org.md" {
+		t.Errorf("SystemPrefix = %q", cfg.SystemPrefix)
+	}
+	if len(cfg.SystemSuffix) != 2 || cfg.SystemSuffix[1] != "team-suffix.md" {
+		t.Errorf("SystemSuffix = %q", cfg.SystemSuffix)
+	}
+
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(out), `"system_prefix":"org.md"`) || !strings.Contains(string(out), `"system_suffix":["org-suffix.md","team-suffix.md"]`) {
+		t.Errorf("marshal = %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"system_prefix": 3}`), &cfg); err == nil {
+		t.Error("expected an error for a non-path prompt file")
+	}
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Source is where an effective config value came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
+
+// maskedValue replaces secrets in effective config output.
+const maskedValue = "********"
+
+// secretKeys are the settings whose values are masked.
+var secretKeys = map[string]bool{
+	"provider.api_key":     true,
+	"embedding.api_key":    true,
+	"flows.webhook_secret": true,
+	"serve.token":          true,
+}
+
+// Setting is one resolved config value and where it came from.
+type Setting struct {
+	Key         string `json:"key"` // Dotted path, e.g. "flows.webhook"
+	Value       any    `json:"value"`
+	Source      Source `json:"source"`
+	Detail      string `json:"detail,omitempty"` // The file or environment variables the value came from
+	Description string `json:"description,omitempty"`
+}
+
+// Effective resolves the config at path the way Load does and reports every
+// field in the schema, in schema order, with the value in effect and its
+// source. Secrets are masked.
+func Effective(path string, schema []byte) ([]Setting, error) {
+	fields, err := schemaFields(schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse config schema: %w", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	effective, err := toMap(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	file := map[string]any{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parse config: %w", err)
+		}
+	}
+
+	settings := make([]Setting, 0, len(fields))
+	for _, f := range fields {
+		value, ok := lookup(effective, f.key)
+		if !ok {
+			// Unset fields fall back to the default documented in the schema
+			value = f.defaultValue
+		}
+		s := Setting{Key: f.key, Value: value, Source: SourceDefault, Description: f.description}
+
+		if fileValue, ok := lookup(file, f.key); ok && reflect.DeepEqual(fileValue, value) {
+			s.Source, s.Detail = SourceFile, path
+		} else if detail := envDefault(f.key); detail != "" {
+			s.Source, s.Detail = SourceEnv, detail
+		}
+		if v, detail := envOverride(f.key); detail != "" {
+			s.Value, s.Source, s.Detail = v, SourceEnv, detail
+		}
+
+		s.Value = maskSecret(f.key, s.Value)
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// envOverride returns the value an environment variable forces for key
+// regardless of the config file, and the variable's name.
+func envOverride(key string) (any, string) {
+	switch key {
+	case "flows.webhook_secret":
+		if v := os.Getenv("AYO_WEBHOOK_SECRET"); v != "" {
+			return v, "AYO_WEBHOOK_SECRET"
+		}
+	case "serve.token":
+		if v := os.Getenv("AYO_SERVE_TOKEN"); v != "" {
+			return v, "AYO_SERVE_TOKEN"
+		}
+	case "ui.theme":
+		if os.Getenv("NO_COLOR") != "" {
+			return "mono", "NO_COLOR"
+		}
+	case "offline":
+		if offlineFromEnv() {
+			return true, OfflineEnvVar
+		}
+	}
+	return nil, ""
+}
+
+// envDefault returns the environment variables a default for key was
+// derived from, or "" if the default does not depend on the environment.
+func envDefault(key string) string {
+	switch key {
+	case "catwalk_base_url":
+		if strings.TrimSpace(os.Getenv("CATWALK_URL")) != "" {
+			return "CATWALK_URL"
+		}
+	case "default_model", "small_model":
+		// Picked from the providers that have API keys
+		var vars []string
+		for _, p := range GetProvidersWithCredentials() {
+			vars = append(vars, p.EnvVar)
+		}
+		return strings.Join(vars, ", ")
+	}
+	return ""
+}
+
+// maskSecret hides secret values.
+func maskSecret(key string, value any) any {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value
+	}
+	if secretKeys[key] {
+		return maskedValue
+	}
+	return s
+}
+
+// toMap converts cfg to its JSON object form.
+func toMap(cfg Config) (map[string]any, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// lookup returns the value at a dotted key in a decoded JSON object.
+func lookup(m map[string]any, key string) (any, bool) {
+	var v any = m
+	for _, part := range strings.Split(key, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// schemaField is a leaf setting described by the config schema.
+type schemaField struct {
+	key          string
+	description  string
+	defaultValue any
+}
+
+// schemaNode is the part of a JSON schema used to list settings.
+type schemaNode struct {
+	Type        any              `json:"type"`
+	Description string           `json:"description"`
+	Default     any              `json:"default"`
+	Properties  schemaProperties `json:"properties"`
+}
+
+// schemaProperties keeps a schema's properties in document order.
+type schemaProperties []schemaProperty
+
+type schemaProperty struct {
+	name string
+	node schemaNode
+}
+
+// UnmarshalJSON decodes the properties object in order.
+func (p *schemaProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var node schemaNode
+		if err := dec.Decode(&node); err != nil {
+			return err
+		}
+		*p = append(*p, schemaProperty{name: tok.(string), node: node})
+	}
+	return nil
+}
+
+// schemaFields lists the settings in schema in order. Objects with declared
+// properties are expanded; anything else, including maps, is one setting.
+func schemaFields(schema []byte) ([]schemaField, error) {
+	var root schemaNode
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, err
+	}
+
+	var fields []schemaField
+	var walk func(prefix string, props schemaProperties)
+	walk = func(prefix string, props schemaProperties) {
+		for _, p := range props {
+			if p.name == "$schema" {
+				continue
+			}
+			key := prefix + p.name
+			if len(p.node.Properties) > 0 {
+				walk(key+".", p.node.Properties)
+				continue
+			}
+			def := p.node.Default
+			if def == nil && p.node.Type == "boolean" {
+				def = false
+			}
+			fields = append(fields, schemaField{key: key, description: p.node.Description, defaultValue: def})
+		}
+	}
+	walk("", root.Properties)
+	return fields, nil
+}
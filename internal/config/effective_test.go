@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSchema = `{
+  "properties": {
+    "$schema": {"type": "string"},
+    "default_model": {"type": "string", "description": "Default LLM model"},
+    "catwalk_base_url": {"type": "string"},
+    "flows": {
+      "type": "object",
+      "properties": {
+        "history_max_runs": {"type": "integer"},
+        "history_retention_days": {"type": "integer", "default": 30},
+        "webhook": {"type": "string"},
+        "webhook_secret": {"type": "string"}
+      }
+    },
+    "ui": {"type": "object", "properties": {"theme": {"type": "string"}}},
+    "titles": {"type": "object", "properties": {"disabled": {"type": "boolean"}}},
+    "delegates": {"type": "object", "additionalProperties": {"type": "string"}}
+  }
+}`
+
+func TestEffective(t *testing.T) {
+	t.Setenv("CATWALK_URL", "https://catwalk.example")
+	t.Setenv("AYO_WEBHOOK_SECRET", "")
+	t.Setenv("NO_COLOR", "1")
+
+	path := filepath.Join(t.TempDir(), "ayo.json")
+	data := `{
+  "default_model": "claude-sonnet-4",
+  "flows": {"webhook_secret": "s3cret"},
+  "ui": {"theme": "light"},
+  "delegates": {"coding": "@crush"}
+}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := Effective(path, []byte(testSchema))
+	if err != nil {
+		t.Fatalf("Effective: %v", err)
+	}
+
+	want := []Setting{
+		{Key: "default_model", Value: "claude-sonnet-4", Source: SourceFile, Detail: path, Description: "Default LLM model"},
+		{Key: "catwalk_base_url", Value: "https://catwalk.example", Source: SourceEnv, Detail: "CATWALK_URL"},
+		{Key: "flows.history_max_runs", Value: float64(1000), Source: SourceDefault},
+		{Key: "flows.history_retention_days", Value: float64(30), Source: SourceDefault},
+		{Key: "flows.webhook", Value: nil, Source: SourceDefault},
+		{Key: "flows.webhook_secret", Value: "********", Source: SourceFile, Detail: path},
+		{Key: "ui.theme", Value: "mono", Source: SourceEnv, Detail: "NO_COLOR"},
+		{Key: "titles.disabled", Value: false, Source: SourceDefault},
+		{Key: "delegates", Value: map[string]any{"coding": "@crush"}, Source: SourceFile, Detail: path},
+	}
+	if len(settings) != len(want) {
+		t.Fatalf("Effective = %+v, want %d settings", settings, len(want))
+	}
+	for i, w := range want {
+		got := settings[i]
+		if got.Key != w.Key || got.Source != w.Source || got.Detail != w.Detail || got.Description != w.Description {
+			t.Errorf("settings[%d] = %+v, want %+v", i, got, w)
+		}
+		if m, ok := w.Value.(map[string]any); ok {
+			if gm, _ := got.Value.(map[string]any); len(gm) != len(m) || gm["coding"] != m["coding"] {
+				t.Errorf("settings[%d].Value = %v, want %v", i, got.Value, w.Value)
+			}
+		} else if got.Value != w.Value {
+			t.Errorf("settings[%d].Value = %v, want %v", i, got.Value, w.Value)
+		}
+	}
+}
+
+func TestEffectiveSecretFromEnv(t *testing.T) {
+	t.Setenv("AYO_WEBHOOK_SECRET", "from-env")
+
+	settings, err := Effective(filepath.Join(t.TempDir(), "missing.json"), []byte(testSchema))
+	if err != nil {
+		t.Fatalf("Effective: %v", err)
+	}
+	for _, s := range settings {
+		if s.Key == "flows.webhook_secret" {
+			if s.Value != maskedValue || s.Source != SourceEnv || s.Detail != "AYO_WEBHOOK_SECRET" {
+				t.Errorf("webhook_secret = %+v, want masked from AYO_WEBHOOK_SECRET", s)
+			}
+			return
+		}
+	}
+	t.Fatal("flows.webhook_secret missing")
+}
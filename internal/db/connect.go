@@ -3,12 +3,16 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/ncruces/go-sqlite3"
 	"github.com/pressly/goose/v3"
 
 	// ncruces/go-sqlite3 provides a pure-Go SQLite driver using WebAssembly.
@@ -16,7 +20,22 @@ import (
 	_ "github.com/ncruces/go-sqlite3/embed"
 )
 
+// ErrDatabaseLocked is returned by Connect when the database stays locked by
+// another process through every retry.
+var ErrDatabaseLocked = errors.New("another ayo process is using the database")
+
+// Lock handling. SQLite waits up to busyTimeout for a lock on each
+// statement; if connecting still fails because the database is locked,
+// Connect retries lockRetries times, doubling the wait from lockBackoff.
+var (
+	busyTimeout = time.Second
+	lockRetries = 3
+	lockBackoff = 250 * time.Millisecond
+)
+
 // Connect opens a SQLite database connection and runs migrations.
+// While another process holds a lock on the database, Connect retries with
+// backoff, then returns an error wrapping ErrDatabaseLocked.
 func Connect(ctx context.Context, dbPath string) (*sql.DB, error) {
 	if dbPath == "" {
 		return nil, fmt.Errorf("database path is not set")
@@ -28,22 +47,51 @@ func Connect(ctx context.Context, dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// ncruces driver uses "sqlite3" as the driver name and requires file: prefix
-	db, err := sql.Open("sqlite3", "file:"+dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+	backoff := lockBackoff
+	for attempt := 0; ; attempt++ {
+		db, err := connectSQLite(ctx, dbPath)
+		if err == nil {
+			return db, nil
+		}
+		if !IsLocked(err) {
+			return nil, err
+		}
+		if attempt == lockRetries {
+			return nil, fmt.Errorf("%w; try again when it finishes", ErrDatabaseLocked)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
 	}
+}
 
-	// Enable foreign keys
-	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+// IsLocked reports whether err means another connection holds a lock on
+// the database.
+func IsLocked(err error) bool {
+	if errors.Is(err, ErrDatabaseLocked) || errors.Is(err, sqlite3.BUSY) || errors.Is(err, sqlite3.LOCKED) {
+		return true
 	}
+	// Migration errors don't always wrap the driver error
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
 
-	// Set WAL mode for better concurrency
-	if _, err := db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
+// connectSQLite opens the SQLite file at dbPath and runs migrations.
+func connectSQLite(ctx context.Context, dbPath string) (*sql.DB, error) {
+	// ncruces driver uses "sqlite3" as the driver name and requires file:
+	// prefix. Pragmas in the DSN apply to every pooled connection: foreign
+	// keys, WAL mode for better concurrency, and waiting for locks instead of
+	// failing at once.
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_pragma=journal_mode(wal)",
+		dbPath, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	if err = db.PingContext(ctx); err != nil {
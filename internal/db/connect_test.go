@@ -2,9 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConnect(t *testing.T) {
@@ -103,3 +106,80 @@ func TestConnectEmptyPath(t *testing.T) {
 		t.Error("expected error for empty path")
 	}
 }
+
+// lockDatabase holds a write lock on the SQLite file at path until the
+// returned function is called.
+func lockDatabase(t *testing.T, path string) func() {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := tx.Exec("CREATE TABLE lock_holder (id INTEGER)"); err != nil {
+		t.Fatalf("take lock: %v", err)
+	}
+	return func() {
+		tx.Rollback()
+		conn.Close()
+	}
+}
+
+// fastLockRetries shortens the lock waits for a test.
+func fastLockRetries(t *testing.T) {
+	oldTimeout, oldRetries, oldBackoff := busyTimeout, lockRetries, lockBackoff
+	busyTimeout, lockRetries, lockBackoff = 10*time.Millisecond, 3, 10*time.Millisecond
+	t.Cleanup(func() {
+		busyTimeout, lockRetries, lockBackoff = oldTimeout, oldRetries, oldBackoff
+	})
+}
+
+func TestConnectLockedDatabase(t *testing.T) {
+	fastLockRetries(t)
+	path := filepath.Join(t.TempDir(), "ayo.db")
+	unlock := lockDatabase(t, path)
+	defer unlock()
+
+	_, err := Connect(context.Background(), path)
+	if !errors.Is(err, ErrDatabaseLocked) {
+		t.Fatalf("Connect error = %v, want ErrDatabaseLocked", err)
+	}
+	if !IsLocked(err) {
+		t.Error("IsLocked = false for ErrDatabaseLocked")
+	}
+}
+
+func TestConnectRetriesUntilUnlocked(t *testing.T) {
+	fastLockRetries(t)
+	lockRetries = 10
+	path := filepath.Join(t.TempDir(), "ayo.db")
+	unlock := lockDatabase(t, path)
+	time.AfterFunc(50*time.Millisecond, unlock)
+
+	db, err := Connect(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer db.Close()
+
+	// Pragmas apply to every pooled connection, not just the first
+	db.SetMaxOpenConns(2)
+	for range 2 {
+		var fk int
+		if err := db.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil || fk != 1 {
+			t.Errorf("foreign_keys = %d, %v; want 1", fk, err)
+		}
+	}
+}
+
+func TestIsLocked(t *testing.T) {
+	if IsLocked(nil) || IsLocked(errors.New("no such table")) {
+		t.Error("IsLocked = true for an unrelated error")
+	}
+	if !IsLocked(errors.New("failed to apply migrations: database is locked")) {
+		t.Error("IsLocked = false for a wrapped lock message")
+	}
+}
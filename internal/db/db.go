@@ -24,6 +24,15 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addSessionTagStmt, err = db.PrepareContext(ctx, addSessionTag); err != nil {
+		return nil, fmt.Errorf("error preparing query AddSessionTag: %w", err)
+	}
+	if q.addSessionUsageStmt, err = db.PrepareContext(ctx, addSessionUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query AddSessionUsage: %w", err)
+	}
+	if q.addSkillUsageStmt, err = db.PrepareContext(ctx, addSkillUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query AddSkillUsage: %w", err)
+	}
 	if q.clearAllMemoriesStmt, err = db.PrepareContext(ctx, clearAllMemories); err != nil {
 		return nil, fmt.Errorf("error preparing query ClearAllMemories: %w", err)
 	}
@@ -48,9 +57,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countMemoriesByAgentStmt, err = db.PrepareContext(ctx, countMemoriesByAgent); err != nil {
 		return nil, fmt.Errorf("error preparing query CountMemoriesByAgent: %w", err)
 	}
+	if q.countMemoriesByCategoryStmt, err = db.PrepareContext(ctx, countMemoriesByCategory); err != nil {
+		return nil, fmt.Errorf("error preparing query CountMemoriesByCategory: %w", err)
+	}
+	if q.countMemoriesByStatusStmt, err = db.PrepareContext(ctx, countMemoriesByStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query CountMemoriesByStatus: %w", err)
+	}
+	if q.countMemoriesPerAgentStmt, err = db.PrepareContext(ctx, countMemoriesPerAgent); err != nil {
+		return nil, fmt.Errorf("error preparing query CountMemoriesPerAgent: %w", err)
+	}
 	if q.countMessagesBySessionStmt, err = db.PrepareContext(ctx, countMessagesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query CountMessagesBySession: %w", err)
 	}
+	if q.countSessionTagsStmt, err = db.PrepareContext(ctx, countSessionTags); err != nil {
+		return nil, fmt.Errorf("error preparing query CountSessionTags: %w", err)
+	}
 	if q.countSessionsStmt, err = db.PrepareContext(ctx, countSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query CountSessions: %w", err)
 	}
@@ -69,6 +90,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createMemoryStmt, err = db.PrepareContext(ctx, createMemory); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMemory: %w", err)
 	}
+	if q.createMemoryProposalStmt, err = db.PrepareContext(ctx, createMemoryProposal); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateMemoryProposal: %w", err)
+	}
 	if q.createMessageStmt, err = db.PrepareContext(ctx, createMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMessage: %w", err)
 	}
@@ -81,12 +105,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteEdgesBySessionStmt, err = db.PrepareContext(ctx, deleteEdgesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteEdgesBySession: %w", err)
 	}
+	if q.deleteExpiredResponsesStmt, err = db.PrepareContext(ctx, deleteExpiredResponses); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredResponses: %w", err)
+	}
 	if q.deleteFlowRunStmt, err = db.PrepareContext(ctx, deleteFlowRun); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteFlowRun: %w", err)
 	}
 	if q.deleteMemoryStmt, err = db.PrepareContext(ctx, deleteMemory); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMemory: %w", err)
 	}
+	if q.deleteMemoryProposalStmt, err = db.PrepareContext(ctx, deleteMemoryProposal); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMemoryProposal: %w", err)
+	}
 	if q.deleteMessageStmt, err = db.PrepareContext(ctx, deleteMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMessage: %w", err)
 	}
@@ -102,6 +132,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getAllActiveMemoriesWithEmbeddingsStmt, err = db.PrepareContext(ctx, getAllActiveMemoriesWithEmbeddings); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllActiveMemoriesWithEmbeddings: %w", err)
 	}
+	if q.getCachedResponseStmt, err = db.PrepareContext(ctx, getCachedResponse); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCachedResponse: %w", err)
+	}
 	if q.getChildEdgesStmt, err = db.PrepareContext(ctx, getChildEdges); err != nil {
 		return nil, fmt.Errorf("error preparing query GetChildEdges: %w", err)
 	}
@@ -114,6 +147,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getLastFlowRunStmt, err = db.PrepareContext(ctx, getLastFlowRun); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLastFlowRun: %w", err)
 	}
+	if q.getMemoriesForKeywordSearchStmt, err = db.PrepareContext(ctx, getMemoriesForKeywordSearch); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMemoriesForKeywordSearch: %w", err)
+	}
 	if q.getMemoriesForSearchStmt, err = db.PrepareContext(ctx, getMemoriesForSearch); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMemoriesForSearch: %w", err)
 	}
@@ -123,6 +159,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getMemoryHistoryStmt, err = db.PrepareContext(ctx, getMemoryHistory); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMemoryHistory: %w", err)
 	}
+	if q.getMemoryStatsStmt, err = db.PrepareContext(ctx, getMemoryStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMemoryStats: %w", err)
+	}
 	if q.getMessageStmt, err = db.PrepareContext(ctx, getMessage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMessage: %w", err)
 	}
@@ -162,27 +201,69 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listMemoriesByPathStmt, err = db.PrepareContext(ctx, listMemoriesByPath); err != nil {
 		return nil, fmt.Errorf("error preparing query ListMemoriesByPath: %w", err)
 	}
+	if q.listMemoriesChangedSinceStmt, err = db.PrepareContext(ctx, listMemoriesChangedSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMemoriesChangedSince: %w", err)
+	}
+	if q.listMemoryProposalsStmt, err = db.PrepareContext(ctx, listMemoryProposals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMemoryProposals: %w", err)
+	}
+	if q.listMemoryProposalsByAgentStmt, err = db.PrepareContext(ctx, listMemoryProposalsByAgent); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMemoryProposalsByAgent: %w", err)
+	}
+	if q.listMemoryProposalsSinceStmt, err = db.PrepareContext(ctx, listMemoryProposalsSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMemoryProposalsSince: %w", err)
+	}
+	if q.listMostAccessedMemoriesStmt, err = db.PrepareContext(ctx, listMostAccessedMemories); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMostAccessedMemories: %w", err)
+	}
 	if q.listMessagesBySessionStmt, err = db.PrepareContext(ctx, listMessagesBySession); err != nil {
 		return nil, fmt.Errorf("error preparing query ListMessagesBySession: %w", err)
 	}
+	if q.listSessionAgentsStmt, err = db.PrepareContext(ctx, listSessionAgents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSessionAgents: %w", err)
+	}
+	if q.listSessionTagsStmt, err = db.PrepareContext(ctx, listSessionTags); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSessionTags: %w", err)
+	}
 	if q.listSessionsStmt, err = db.PrepareContext(ctx, listSessions); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessions: %w", err)
 	}
 	if q.listSessionsByAgentStmt, err = db.PrepareContext(ctx, listSessionsByAgent); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessionsByAgent: %w", err)
 	}
+	if q.listSessionsByAgentInDirStmt, err = db.PrepareContext(ctx, listSessionsByAgentInDir); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSessionsByAgentInDir: %w", err)
+	}
 	if q.listSessionsBySourceStmt, err = db.PrepareContext(ctx, listSessionsBySource); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSessionsBySource: %w", err)
 	}
+	if q.listSessionsByTagStmt, err = db.PrepareContext(ctx, listSessionsByTag); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSessionsByTag: %w", err)
+	}
 	if q.pruneFlowRunsByAgeStmt, err = db.PrepareContext(ctx, pruneFlowRunsByAge); err != nil {
 		return nil, fmt.Errorf("error preparing query PruneFlowRunsByAge: %w", err)
 	}
 	if q.pruneFlowRunsByCountStmt, err = db.PrepareContext(ctx, pruneFlowRunsByCount); err != nil {
 		return nil, fmt.Errorf("error preparing query PruneFlowRunsByCount: %w", err)
 	}
+	if q.putCachedResponseStmt, err = db.PrepareContext(ctx, putCachedResponse); err != nil {
+		return nil, fmt.Errorf("error preparing query PutCachedResponse: %w", err)
+	}
+	if q.removeSessionTagStmt, err = db.PrepareContext(ctx, removeSessionTag); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveSessionTag: %w", err)
+	}
 	if q.searchSessionsByTitleStmt, err = db.PrepareContext(ctx, searchSessionsByTitle); err != nil {
 		return nil, fmt.Errorf("error preparing query SearchSessionsByTitle: %w", err)
 	}
+	if q.sumSkillUsageStmt, err = db.PrepareContext(ctx, sumSkillUsage); err != nil {
+		return nil, fmt.Errorf("error preparing query SumSkillUsage: %w", err)
+	}
+	if q.sumUsageByAgentStmt, err = db.PrepareContext(ctx, sumUsageByAgent); err != nil {
+		return nil, fmt.Errorf("error preparing query SumUsageByAgent: %w", err)
+	}
+	if q.sumUsageByModelStmt, err = db.PrepareContext(ctx, sumUsageByModel); err != nil {
+		return nil, fmt.Errorf("error preparing query SumUsageByModel: %w", err)
+	}
 	if q.supersedeMemoryStmt, err = db.PrepareContext(ctx, supersedeMemory); err != nil {
 		return nil, fmt.Errorf("error preparing query SupersedeMemory: %w", err)
 	}
@@ -198,6 +279,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateSessionStmt, err = db.PrepareContext(ctx, updateSession); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSession: %w", err)
 	}
+	if q.updateSessionSeedStmt, err = db.PrepareContext(ctx, updateSessionSeed); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSessionSeed: %w", err)
+	}
 	if q.updateSessionTitleStmt, err = db.PrepareContext(ctx, updateSessionTitle); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateSessionTitle: %w", err)
 	}
@@ -206,6 +290,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addSessionTagStmt != nil {
+		if cerr := q.addSessionTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addSessionTagStmt: %w", cerr)
+		}
+	}
+	if q.addSessionUsageStmt != nil {
+		if cerr := q.addSessionUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addSessionUsageStmt: %w", cerr)
+		}
+	}
+	if q.addSkillUsageStmt != nil {
+		if cerr := q.addSkillUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addSkillUsageStmt: %w", cerr)
+		}
+	}
 	if q.clearAllMemoriesStmt != nil {
 		if cerr := q.clearAllMemoriesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing clearAllMemoriesStmt: %w", cerr)
@@ -246,11 +345,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countMemoriesByAgentStmt: %w", cerr)
 		}
 	}
+	if q.countMemoriesByCategoryStmt != nil {
+		if cerr := q.countMemoriesByCategoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countMemoriesByCategoryStmt: %w", cerr)
+		}
+	}
+	if q.countMemoriesByStatusStmt != nil {
+		if cerr := q.countMemoriesByStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countMemoriesByStatusStmt: %w", cerr)
+		}
+	}
+	if q.countMemoriesPerAgentStmt != nil {
+		if cerr := q.countMemoriesPerAgentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countMemoriesPerAgentStmt: %w", cerr)
+		}
+	}
 	if q.countMessagesBySessionStmt != nil {
 		if cerr := q.countMessagesBySessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countMessagesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.countSessionTagsStmt != nil {
+		if cerr := q.countSessionTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countSessionTagsStmt: %w", cerr)
+		}
+	}
 	if q.countSessionsStmt != nil {
 		if cerr := q.countSessionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countSessionsStmt: %w", cerr)
@@ -281,6 +400,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createMemoryStmt: %w", cerr)
 		}
 	}
+	if q.createMemoryProposalStmt != nil {
+		if cerr := q.createMemoryProposalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createMemoryProposalStmt: %w", cerr)
+		}
+	}
 	if q.createMessageStmt != nil {
 		if cerr := q.createMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createMessageStmt: %w", cerr)
@@ -301,6 +425,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteEdgesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.deleteExpiredResponsesStmt != nil {
+		if cerr := q.deleteExpiredResponsesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredResponsesStmt: %w", cerr)
+		}
+	}
 	if q.deleteFlowRunStmt != nil {
 		if cerr := q.deleteFlowRunStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteFlowRunStmt: %w", cerr)
@@ -311,6 +440,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteMemoryStmt: %w", cerr)
 		}
 	}
+	if q.deleteMemoryProposalStmt != nil {
+		if cerr := q.deleteMemoryProposalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMemoryProposalStmt: %w", cerr)
+		}
+	}
 	if q.deleteMessageStmt != nil {
 		if cerr := q.deleteMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteMessageStmt: %w", cerr)
@@ -336,6 +470,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAllActiveMemoriesWithEmbeddingsStmt: %w", cerr)
 		}
 	}
+	if q.getCachedResponseStmt != nil {
+		if cerr := q.getCachedResponseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCachedResponseStmt: %w", cerr)
+		}
+	}
 	if q.getChildEdgesStmt != nil {
 		if cerr := q.getChildEdgesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getChildEdgesStmt: %w", cerr)
@@ -356,6 +495,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getLastFlowRunStmt: %w", cerr)
 		}
 	}
+	if q.getMemoriesForKeywordSearchStmt != nil {
+		if cerr := q.getMemoriesForKeywordSearchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMemoriesForKeywordSearchStmt: %w", cerr)
+		}
+	}
 	if q.getMemoriesForSearchStmt != nil {
 		if cerr := q.getMemoriesForSearchStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMemoriesForSearchStmt: %w", cerr)
@@ -371,6 +515,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getMemoryHistoryStmt: %w", cerr)
 		}
 	}
+	if q.getMemoryStatsStmt != nil {
+		if cerr := q.getMemoryStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMemoryStatsStmt: %w", cerr)
+		}
+	}
 	if q.getMessageStmt != nil {
 		if cerr := q.getMessageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMessageStmt: %w", cerr)
@@ -436,11 +585,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listMemoriesByPathStmt: %w", cerr)
 		}
 	}
+	if q.listMemoriesChangedSinceStmt != nil {
+		if cerr := q.listMemoriesChangedSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMemoriesChangedSinceStmt: %w", cerr)
+		}
+	}
+	if q.listMemoryProposalsStmt != nil {
+		if cerr := q.listMemoryProposalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMemoryProposalsStmt: %w", cerr)
+		}
+	}
+	if q.listMemoryProposalsByAgentStmt != nil {
+		if cerr := q.listMemoryProposalsByAgentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMemoryProposalsByAgentStmt: %w", cerr)
+		}
+	}
+	if q.listMemoryProposalsSinceStmt != nil {
+		if cerr := q.listMemoryProposalsSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMemoryProposalsSinceStmt: %w", cerr)
+		}
+	}
+	if q.listMostAccessedMemoriesStmt != nil {
+		if cerr := q.listMostAccessedMemoriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMostAccessedMemoriesStmt: %w", cerr)
+		}
+	}
 	if q.listMessagesBySessionStmt != nil {
 		if cerr := q.listMessagesBySessionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listMessagesBySessionStmt: %w", cerr)
 		}
 	}
+	if q.listSessionAgentsStmt != nil {
+		if cerr := q.listSessionAgentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSessionAgentsStmt: %w", cerr)
+		}
+	}
+	if q.listSessionTagsStmt != nil {
+		if cerr := q.listSessionTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSessionTagsStmt: %w", cerr)
+		}
+	}
 	if q.listSessionsStmt != nil {
 		if cerr := q.listSessionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSessionsStmt: %w", cerr)
@@ -451,11 +635,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listSessionsByAgentStmt: %w", cerr)
 		}
 	}
+	if q.listSessionsByAgentInDirStmt != nil {
+		if cerr := q.listSessionsByAgentInDirStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSessionsByAgentInDirStmt: %w", cerr)
+		}
+	}
 	if q.listSessionsBySourceStmt != nil {
 		if cerr := q.listSessionsBySourceStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSessionsBySourceStmt: %w", cerr)
 		}
 	}
+	if q.listSessionsByTagStmt != nil {
+		if cerr := q.listSessionsByTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSessionsByTagStmt: %w", cerr)
+		}
+	}
 	if q.pruneFlowRunsByAgeStmt != nil {
 		if cerr := q.pruneFlowRunsByAgeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing pruneFlowRunsByAgeStmt: %w", cerr)
@@ -466,11 +660,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing pruneFlowRunsByCountStmt: %w", cerr)
 		}
 	}
+	if q.putCachedResponseStmt != nil {
+		if cerr := q.putCachedResponseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing putCachedResponseStmt: %w", cerr)
+		}
+	}
+	if q.removeSessionTagStmt != nil {
+		if cerr := q.removeSessionTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeSessionTagStmt: %w", cerr)
+		}
+	}
 	if q.searchSessionsByTitleStmt != nil {
 		if cerr := q.searchSessionsByTitleStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing searchSessionsByTitleStmt: %w", cerr)
 		}
 	}
+	if q.sumSkillUsageStmt != nil {
+		if cerr := q.sumSkillUsageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumSkillUsageStmt: %w", cerr)
+		}
+	}
+	if q.sumUsageByAgentStmt != nil {
+		if cerr := q.sumUsageByAgentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumUsageByAgentStmt: %w", cerr)
+		}
+	}
+	if q.sumUsageByModelStmt != nil {
+		if cerr := q.sumUsageByModelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumUsageByModelStmt: %w", cerr)
+		}
+	}
 	if q.supersedeMemoryStmt != nil {
 		if cerr := q.supersedeMemoryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing supersedeMemoryStmt: %w", cerr)
@@ -496,6 +715,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateSessionStmt: %w", cerr)
 		}
 	}
+	if q.updateSessionSeedStmt != nil {
+		if cerr := q.updateSessionSeedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSessionSeedStmt: %w", cerr)
+		}
+	}
 	if q.updateSessionTitleStmt != nil {
 		if cerr := q.updateSessionTitleStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateSessionTitleStmt: %w", cerr)
@@ -540,6 +764,9 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                     DBTX
 	tx                                     *sql.Tx
+	addSessionTagStmt                      *sql.Stmt
+	addSessionUsageStmt                    *sql.Stmt
+	addSkillUsageStmt                      *sql.Stmt
 	clearAllMemoriesStmt                   *sql.Stmt
 	clearMemoriesByAgentStmt               *sql.Stmt
 	completeFlowRunStmt                    *sql.Stmt
@@ -548,31 +775,41 @@ type Queries struct {
 	countFlowRunsByStatusStmt              *sql.Stmt
 	countMemoriesStmt                      *sql.Stmt
 	countMemoriesByAgentStmt               *sql.Stmt
+	countMemoriesByCategoryStmt            *sql.Stmt
+	countMemoriesByStatusStmt              *sql.Stmt
+	countMemoriesPerAgentStmt              *sql.Stmt
 	countMessagesBySessionStmt             *sql.Stmt
+	countSessionTagsStmt                   *sql.Stmt
 	countSessionsStmt                      *sql.Stmt
 	countSessionsByAgentStmt               *sql.Stmt
 	countSessionsBySourceStmt              *sql.Stmt
 	createEdgeStmt                         *sql.Stmt
 	createFlowRunStmt                      *sql.Stmt
 	createMemoryStmt                       *sql.Stmt
+	createMemoryProposalStmt               *sql.Stmt
 	createMessageStmt                      *sql.Stmt
 	createSessionStmt                      *sql.Stmt
 	deleteEdgeStmt                         *sql.Stmt
 	deleteEdgesBySessionStmt               *sql.Stmt
+	deleteExpiredResponsesStmt             *sql.Stmt
 	deleteFlowRunStmt                      *sql.Stmt
 	deleteMemoryStmt                       *sql.Stmt
+	deleteMemoryProposalStmt               *sql.Stmt
 	deleteMessageStmt                      *sql.Stmt
 	deleteMessagesBySessionStmt            *sql.Stmt
 	deleteSessionStmt                      *sql.Stmt
 	forgetMemoryStmt                       *sql.Stmt
 	getAllActiveMemoriesWithEmbeddingsStmt *sql.Stmt
+	getCachedResponseStmt                  *sql.Stmt
 	getChildEdgesStmt                      *sql.Stmt
 	getFlowRunStmt                         *sql.Stmt
 	getFlowRunByPrefixStmt                 *sql.Stmt
 	getLastFlowRunStmt                     *sql.Stmt
+	getMemoriesForKeywordSearchStmt        *sql.Stmt
 	getMemoriesForSearchStmt               *sql.Stmt
 	getMemoryStmt                          *sql.Stmt
 	getMemoryHistoryStmt                   *sql.Stmt
+	getMemoryStatsStmt                     *sql.Stmt
 	getMessageStmt                         *sql.Stmt
 	getParentEdgesStmt                     *sql.Stmt
 	getSessionStmt                         *sql.Stmt
@@ -586,18 +823,33 @@ type Queries struct {
 	listMemoriesByAgentAndPathStmt         *sql.Stmt
 	listMemoriesByCategoryStmt             *sql.Stmt
 	listMemoriesByPathStmt                 *sql.Stmt
+	listMemoriesChangedSinceStmt           *sql.Stmt
+	listMemoryProposalsStmt                *sql.Stmt
+	listMemoryProposalsByAgentStmt         *sql.Stmt
+	listMemoryProposalsSinceStmt           *sql.Stmt
+	listMostAccessedMemoriesStmt           *sql.Stmt
 	listMessagesBySessionStmt              *sql.Stmt
+	listSessionAgentsStmt                  *sql.Stmt
+	listSessionTagsStmt                    *sql.Stmt
 	listSessionsStmt                       *sql.Stmt
 	listSessionsByAgentStmt                *sql.Stmt
+	listSessionsByAgentInDirStmt           *sql.Stmt
 	listSessionsBySourceStmt               *sql.Stmt
+	listSessionsByTagStmt                  *sql.Stmt
 	pruneFlowRunsByAgeStmt                 *sql.Stmt
 	pruneFlowRunsByCountStmt               *sql.Stmt
+	putCachedResponseStmt                  *sql.Stmt
+	removeSessionTagStmt                   *sql.Stmt
 	searchSessionsByTitleStmt              *sql.Stmt
+	sumSkillUsageStmt                      *sql.Stmt
+	sumUsageByAgentStmt                    *sql.Stmt
+	sumUsageByModelStmt                    *sql.Stmt
 	supersedeMemoryStmt                    *sql.Stmt
 	updateMemoryStmt                       *sql.Stmt
 	updateMemoryAccessStmt                 *sql.Stmt
 	updateMessageStmt                      *sql.Stmt
 	updateSessionStmt                      *sql.Stmt
+	updateSessionSeedStmt                  *sql.Stmt
 	updateSessionTitleStmt                 *sql.Stmt
 }
 
@@ -605,6 +857,9 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                     tx,
 		tx:                                     tx,
+		addSessionTagStmt:                      q.addSessionTagStmt,
+		addSessionUsageStmt:                    q.addSessionUsageStmt,
+		addSkillUsageStmt:                      q.addSkillUsageStmt,
 		clearAllMemoriesStmt:                   q.clearAllMemoriesStmt,
 		clearMemoriesByAgentStmt:               q.clearMemoriesByAgentStmt,
 		completeFlowRunStmt:                    q.completeFlowRunStmt,
@@ -613,31 +868,41 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		countFlowRunsByStatusStmt:              q.countFlowRunsByStatusStmt,
 		countMemoriesStmt:                      q.countMemoriesStmt,
 		countMemoriesByAgentStmt:               q.countMemoriesByAgentStmt,
+		countMemoriesByCategoryStmt:            q.countMemoriesByCategoryStmt,
+		countMemoriesByStatusStmt:              q.countMemoriesByStatusStmt,
+		countMemoriesPerAgentStmt:              q.countMemoriesPerAgentStmt,
 		countMessagesBySessionStmt:             q.countMessagesBySessionStmt,
+		countSessionTagsStmt:                   q.countSessionTagsStmt,
 		countSessionsStmt:                      q.countSessionsStmt,
 		countSessionsByAgentStmt:               q.countSessionsByAgentStmt,
 		countSessionsBySourceStmt:              q.countSessionsBySourceStmt,
 		createEdgeStmt:                         q.createEdgeStmt,
 		createFlowRunStmt:                      q.createFlowRunStmt,
 		createMemoryStmt:                       q.createMemoryStmt,
+		createMemoryProposalStmt:               q.createMemoryProposalStmt,
 		createMessageStmt:                      q.createMessageStmt,
 		createSessionStmt:                      q.createSessionStmt,
 		deleteEdgeStmt:                         q.deleteEdgeStmt,
 		deleteEdgesBySessionStmt:               q.deleteEdgesBySessionStmt,
+		deleteExpiredResponsesStmt:             q.deleteExpiredResponsesStmt,
 		deleteFlowRunStmt:                      q.deleteFlowRunStmt,
 		deleteMemoryStmt:                       q.deleteMemoryStmt,
+		deleteMemoryProposalStmt:               q.deleteMemoryProposalStmt,
 		deleteMessageStmt:                      q.deleteMessageStmt,
 		deleteMessagesBySessionStmt:            q.deleteMessagesBySessionStmt,
 		deleteSessionStmt:                      q.deleteSessionStmt,
 		forgetMemoryStmt:                       q.forgetMemoryStmt,
 		getAllActiveMemoriesWithEmbeddingsStmt: q.getAllActiveMemoriesWithEmbeddingsStmt,
+		getCachedResponseStmt:                  q.getCachedResponseStmt,
 		getChildEdgesStmt:                      q.getChildEdgesStmt,
 		getFlowRunStmt:                         q.getFlowRunStmt,
 		getFlowRunByPrefixStmt:                 q.getFlowRunByPrefixStmt,
 		getLastFlowRunStmt:                     q.getLastFlowRunStmt,
+		getMemoriesForKeywordSearchStmt:        q.getMemoriesForKeywordSearchStmt,
 		getMemoriesForSearchStmt:               q.getMemoriesForSearchStmt,
 		getMemoryStmt:                          q.getMemoryStmt,
 		getMemoryHistoryStmt:                   q.getMemoryHistoryStmt,
+		getMemoryStatsStmt:                     q.getMemoryStatsStmt,
 		getMessageStmt:                         q.getMessageStmt,
 		getParentEdgesStmt:                     q.getParentEdgesStmt,
 		getSessionStmt:                         q.getSessionStmt,
@@ -651,18 +916,33 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		listMemoriesByAgentAndPathStmt:         q.listMemoriesByAgentAndPathStmt,
 		listMemoriesByCategoryStmt:             q.listMemoriesByCategoryStmt,
 		listMemoriesByPathStmt:                 q.listMemoriesByPathStmt,
+		listMemoriesChangedSinceStmt:           q.listMemoriesChangedSinceStmt,
+		listMemoryProposalsStmt:                q.listMemoryProposalsStmt,
+		listMemoryProposalsByAgentStmt:         q.listMemoryProposalsByAgentStmt,
+		listMemoryProposalsSinceStmt:           q.listMemoryProposalsSinceStmt,
+		listMostAccessedMemoriesStmt:           q.listMostAccessedMemoriesStmt,
 		listMessagesBySessionStmt:              q.listMessagesBySessionStmt,
+		listSessionAgentsStmt:                  q.listSessionAgentsStmt,
+		listSessionTagsStmt:                    q.listSessionTagsStmt,
 		listSessionsStmt:                       q.listSessionsStmt,
 		listSessionsByAgentStmt:                q.listSessionsByAgentStmt,
+		listSessionsByAgentInDirStmt:           q.listSessionsByAgentInDirStmt,
 		listSessionsBySourceStmt:               q.listSessionsBySourceStmt,
+		listSessionsByTagStmt:                  q.listSessionsByTagStmt,
 		pruneFlowRunsByAgeStmt:                 q.pruneFlowRunsByAgeStmt,
 		pruneFlowRunsByCountStmt:               q.pruneFlowRunsByCountStmt,
+		putCachedResponseStmt:                  q.putCachedResponseStmt,
+		removeSessionTagStmt:                   q.removeSessionTagStmt,
 		searchSessionsByTitleStmt:              q.searchSessionsByTitleStmt,
+		sumSkillUsageStmt:                      q.sumSkillUsageStmt,
+		sumUsageByAgentStmt:                    q.sumUsageByAgentStmt,
+		sumUsageByModelStmt:                    q.sumUsageByModelStmt,
 		supersedeMemoryStmt:                    q.supersedeMemoryStmt,
 		updateMemoryStmt:                       q.updateMemoryStmt,
 		updateMemoryAccessStmt:                 q.updateMemoryAccessStmt,
 		updateMessageStmt:                      q.updateMessageStmt,
 		updateSessionStmt:                      q.updateSessionStmt,
+		updateSessionSeedStmt:                  q.updateSessionSeedStmt,
 		updateSessionTitleStmt:                 q.updateSessionTitleStmt,
 	}
 }
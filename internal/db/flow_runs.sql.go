@@ -19,9 +19,10 @@ UPDATE flow_runs SET
     stderr_log = ?5,
     output_validated = ?6,
     finished_at = ?7,
-    duration_ms = ?8
-WHERE id = ?9
-RETURNING id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated
+    duration_ms = ?8,
+    stages_json = ?9
+WHERE id = ?10
+RETURNING id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json
 `
 
 type CompleteFlowRunParams struct {
@@ -33,6 +34,7 @@ type CompleteFlowRunParams struct {
 	OutputValidated int64          `json:"output_validated"`
 	FinishedAt      sql.NullInt64  `json:"finished_at"`
 	DurationMs      sql.NullInt64  `json:"duration_ms"`
+	StagesJson      sql.NullString `json:"stages_json"`
 	ID              string         `json:"id"`
 }
 
@@ -46,6 +48,7 @@ func (q *Queries) CompleteFlowRun(ctx context.Context, arg CompleteFlowRunParams
 		arg.OutputValidated,
 		arg.FinishedAt,
 		arg.DurationMs,
+		arg.StagesJson,
 		arg.ID,
 	)
 	var i FlowRun
@@ -67,6 +70,8 @@ func (q *Queries) CompleteFlowRun(ctx context.Context, arg CompleteFlowRunParams
 		&i.SessionID,
 		&i.InputValidated,
 		&i.OutputValidated,
+		&i.InputSchemaOverride,
+		&i.StagesJson,
 	)
 	return i, err
 }
@@ -115,7 +120,8 @@ INSERT INTO flow_runs (
     input_validated,
     started_at,
     parent_run_id,
-    session_id
+    session_id,
+    input_schema_override
 ) VALUES (
     ?1,
     ?2,
@@ -126,20 +132,22 @@ INSERT INTO flow_runs (
     ?6,
     ?7,
     ?8,
-    ?9
-) RETURNING id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated
+    ?9,
+    ?10
+) RETURNING id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json
 `
 
 type CreateFlowRunParams struct {
-	ID             string         `json:"id"`
-	FlowName       string         `json:"flow_name"`
-	FlowPath       string         `json:"flow_path"`
-	FlowSource     string         `json:"flow_source"`
-	InputJson      sql.NullString `json:"input_json"`
-	InputValidated int64          `json:"input_validated"`
-	StartedAt      int64          `json:"started_at"`
-	ParentRunID    sql.NullString `json:"parent_run_id"`
-	SessionID      sql.NullString `json:"session_id"`
+	ID                  string         `json:"id"`
+	FlowName            string         `json:"flow_name"`
+	FlowPath            string         `json:"flow_path"`
+	FlowSource          string         `json:"flow_source"`
+	InputJson           sql.NullString `json:"input_json"`
+	InputValidated      int64          `json:"input_validated"`
+	StartedAt           int64          `json:"started_at"`
+	ParentRunID         sql.NullString `json:"parent_run_id"`
+	SessionID           sql.NullString `json:"session_id"`
+	InputSchemaOverride sql.NullString `json:"input_schema_override"`
 }
 
 func (q *Queries) CreateFlowRun(ctx context.Context, arg CreateFlowRunParams) (FlowRun, error) {
@@ -153,6 +161,7 @@ func (q *Queries) CreateFlowRun(ctx context.Context, arg CreateFlowRunParams) (F
 		arg.StartedAt,
 		arg.ParentRunID,
 		arg.SessionID,
+		arg.InputSchemaOverride,
 	)
 	var i FlowRun
 	err := row.Scan(
@@ -173,6 +182,8 @@ func (q *Queries) CreateFlowRun(ctx context.Context, arg CreateFlowRunParams) (F
 		&i.SessionID,
 		&i.InputValidated,
 		&i.OutputValidated,
+		&i.InputSchemaOverride,
+		&i.StagesJson,
 	)
 	return i, err
 }
@@ -187,7 +198,7 @@ func (q *Queries) DeleteFlowRun(ctx context.Context, id string) error {
 }
 
 const getFlowRun = `-- name: GetFlowRun :one
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE id = ?1 LIMIT 1
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE id = ?1 LIMIT 1
 `
 
 func (q *Queries) GetFlowRun(ctx context.Context, id string) (FlowRun, error) {
@@ -211,12 +222,14 @@ func (q *Queries) GetFlowRun(ctx context.Context, id string) (FlowRun, error) {
 		&i.SessionID,
 		&i.InputValidated,
 		&i.OutputValidated,
+		&i.InputSchemaOverride,
+		&i.StagesJson,
 	)
 	return i, err
 }
 
 const getFlowRunByPrefix = `-- name: GetFlowRunByPrefix :many
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE id LIKE ?1 || '%' ORDER BY started_at DESC LIMIT 10
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE id LIKE ?1 || '%' ORDER BY started_at DESC LIMIT 10
 `
 
 func (q *Queries) GetFlowRunByPrefix(ctx context.Context, prefix sql.NullString) ([]FlowRun, error) {
@@ -246,6 +259,8 @@ func (q *Queries) GetFlowRunByPrefix(ctx context.Context, prefix sql.NullString)
 			&i.SessionID,
 			&i.InputValidated,
 			&i.OutputValidated,
+			&i.InputSchemaOverride,
+			&i.StagesJson,
 		); err != nil {
 			return nil, err
 		}
@@ -261,7 +276,7 @@ func (q *Queries) GetFlowRunByPrefix(ctx context.Context, prefix sql.NullString)
 }
 
 const getLastFlowRun = `-- name: GetLastFlowRun :one
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE flow_name = ?1 ORDER BY started_at DESC LIMIT 1
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE flow_name = ?1 ORDER BY started_at DESC LIMIT 1
 `
 
 func (q *Queries) GetLastFlowRun(ctx context.Context, flowName string) (FlowRun, error) {
@@ -285,12 +300,14 @@ func (q *Queries) GetLastFlowRun(ctx context.Context, flowName string) (FlowRun,
 		&i.SessionID,
 		&i.InputValidated,
 		&i.OutputValidated,
+		&i.InputSchemaOverride,
+		&i.StagesJson,
 	)
 	return i, err
 }
 
 const listFlowRuns = `-- name: ListFlowRuns :many
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs ORDER BY started_at DESC LIMIT ?1
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs ORDER BY started_at DESC LIMIT ?1
 `
 
 func (q *Queries) ListFlowRuns(ctx context.Context, limit int64) ([]FlowRun, error) {
@@ -320,6 +337,8 @@ func (q *Queries) ListFlowRuns(ctx context.Context, limit int64) ([]FlowRun, err
 			&i.SessionID,
 			&i.InputValidated,
 			&i.OutputValidated,
+			&i.InputSchemaOverride,
+			&i.StagesJson,
 		); err != nil {
 			return nil, err
 		}
@@ -335,7 +354,7 @@ func (q *Queries) ListFlowRuns(ctx context.Context, limit int64) ([]FlowRun, err
 }
 
 const listFlowRunsByName = `-- name: ListFlowRunsByName :many
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE flow_name = ?1 ORDER BY started_at DESC LIMIT ?2
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE flow_name = ?1 ORDER BY started_at DESC LIMIT ?2
 `
 
 type ListFlowRunsByNameParams struct {
@@ -370,6 +389,8 @@ func (q *Queries) ListFlowRunsByName(ctx context.Context, arg ListFlowRunsByName
 			&i.SessionID,
 			&i.InputValidated,
 			&i.OutputValidated,
+			&i.InputSchemaOverride,
+			&i.StagesJson,
 		); err != nil {
 			return nil, err
 		}
@@ -385,7 +406,7 @@ func (q *Queries) ListFlowRunsByName(ctx context.Context, arg ListFlowRunsByName
 }
 
 const listFlowRunsBySession = `-- name: ListFlowRunsBySession :many
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE session_id = ?1 ORDER BY started_at DESC
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE session_id = ?1 ORDER BY started_at DESC
 `
 
 func (q *Queries) ListFlowRunsBySession(ctx context.Context, sessionID sql.NullString) ([]FlowRun, error) {
@@ -415,6 +436,8 @@ func (q *Queries) ListFlowRunsBySession(ctx context.Context, sessionID sql.NullS
 			&i.SessionID,
 			&i.InputValidated,
 			&i.OutputValidated,
+			&i.InputSchemaOverride,
+			&i.StagesJson,
 		); err != nil {
 			return nil, err
 		}
@@ -430,7 +453,7 @@ func (q *Queries) ListFlowRunsBySession(ctx context.Context, sessionID sql.NullS
 }
 
 const listFlowRunsByStatus = `-- name: ListFlowRunsByStatus :many
-SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated FROM flow_runs WHERE status = ?1 ORDER BY started_at DESC LIMIT ?2
+SELECT id, flow_name, flow_path, flow_source, status, exit_code, error_message, input_json, output_json, stderr_log, started_at, finished_at, duration_ms, parent_run_id, session_id, input_validated, output_validated, input_schema_override, stages_json FROM flow_runs WHERE status = ?1 ORDER BY started_at DESC LIMIT ?2
 `
 
 type ListFlowRunsByStatusParams struct {
@@ -465,6 +488,8 @@ func (q *Queries) ListFlowRunsByStatus(ctx context.Context, arg ListFlowRunsBySt
 			&i.SessionID,
 			&i.InputValidated,
 			&i.OutputValidated,
+			&i.InputSchemaOverride,
+			&i.StagesJson,
 		); err != nil {
 			return nil, err
 		}
@@ -67,6 +67,122 @@ func (q *Queries) CountMemoriesByAgent(ctx context.Context, arg CountMemoriesByA
 	return count, err
 }
 
+const countMemoriesByCategory = `-- name: CountMemoriesByCategory :many
+SELECT category, COUNT(*) AS count
+FROM memories
+WHERE status = 'active'
+GROUP BY category
+ORDER BY count DESC, category
+`
+
+type CountMemoriesByCategoryRow struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+func (q *Queries) CountMemoriesByCategory(ctx context.Context) ([]CountMemoriesByCategoryRow, error) {
+	rows, err := q.query(ctx, q.countMemoriesByCategoryStmt, countMemoriesByCategory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountMemoriesByCategoryRow{}
+	for rows.Next() {
+		var i CountMemoriesByCategoryRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMemoriesByStatus = `-- name: CountMemoriesByStatus :many
+SELECT CAST(COALESCE(status, 'active') AS TEXT) AS status, COUNT(*) AS count
+FROM memories
+GROUP BY COALESCE(status, 'active')
+ORDER BY count DESC, status
+`
+
+type CountMemoriesByStatusRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountMemoriesByStatus(ctx context.Context) ([]CountMemoriesByStatusRow, error) {
+	rows, err := q.query(ctx, q.countMemoriesByStatusStmt, countMemoriesByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountMemoriesByStatusRow{}
+	for rows.Next() {
+		var i CountMemoriesByStatusRow
+		if err := rows.Scan(
+			&i.Status,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countMemoriesPerAgent = `-- name: CountMemoriesPerAgent :many
+SELECT CAST(COALESCE(agent_handle, '') AS TEXT) AS agent_handle, COUNT(*) AS count
+FROM memories
+WHERE status = 'active'
+GROUP BY agent_handle
+ORDER BY count DESC, agent_handle
+`
+
+type CountMemoriesPerAgentRow struct {
+	AgentHandle string `json:"agent_handle"`
+	Count       int64  `json:"count"`
+}
+
+func (q *Queries) CountMemoriesPerAgent(ctx context.Context) ([]CountMemoriesPerAgentRow, error) {
+	rows, err := q.query(ctx, q.countMemoriesPerAgentStmt, countMemoriesPerAgent)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountMemoriesPerAgentRow{}
+	for rows.Next() {
+		var i CountMemoriesPerAgentRow
+		if err := rows.Scan(
+			&i.AgentHandle,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createMemory = `-- name: CreateMemory :exec
 INSERT INTO memories (
     id, agent_handle, path_scope, content, category, embedding,
@@ -189,6 +305,67 @@ func (q *Queries) GetAllActiveMemoriesWithEmbeddings(ctx context.Context) ([]Get
 	return items, nil
 }
 
+const getMemoriesForKeywordSearch = `-- name: GetMemoriesForKeywordSearch :many
+SELECT id, agent_handle, path_scope, content, category, embedding, confidence,
+       last_accessed_at, access_count, created_at
+FROM memories
+WHERE status = 'active'
+  AND (agent_handle = ?1 OR agent_handle IS NULL OR ?1 IS NULL)
+  AND (path_scope = ?2 OR path_scope IS NULL OR ?2 IS NULL)
+`
+
+type GetMemoriesForKeywordSearchParams struct {
+	AgentHandle sql.NullString `json:"agent_handle"`
+	PathScope   sql.NullString `json:"path_scope"`
+}
+
+type GetMemoriesForKeywordSearchRow struct {
+	ID             string          `json:"id"`
+	AgentHandle    sql.NullString  `json:"agent_handle"`
+	PathScope      sql.NullString  `json:"path_scope"`
+	Content        string          `json:"content"`
+	Category       string          `json:"category"`
+	Embedding      []byte          `json:"embedding"`
+	Confidence     sql.NullFloat64 `json:"confidence"`
+	LastAccessedAt sql.NullInt64   `json:"last_accessed_at"`
+	AccessCount    sql.NullInt64   `json:"access_count"`
+	CreatedAt      int64           `json:"created_at"`
+}
+
+func (q *Queries) GetMemoriesForKeywordSearch(ctx context.Context, arg GetMemoriesForKeywordSearchParams) ([]GetMemoriesForKeywordSearchRow, error) {
+	rows, err := q.query(ctx, q.getMemoriesForKeywordSearchStmt, getMemoriesForKeywordSearch, arg.AgentHandle, arg.PathScope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMemoriesForKeywordSearchRow{}
+	for rows.Next() {
+		var i GetMemoriesForKeywordSearchRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentHandle,
+			&i.PathScope,
+			&i.Content,
+			&i.Category,
+			&i.Embedding,
+			&i.Confidence,
+			&i.LastAccessedAt,
+			&i.AccessCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMemoriesForSearch = `-- name: GetMemoriesForSearch :many
 SELECT id, agent_handle, path_scope, content, category, embedding, confidence,
        last_accessed_at, access_count, created_at
@@ -342,6 +519,34 @@ func (q *Queries) GetMemoryHistory(ctx context.Context, id string) ([]GetMemoryH
 	return items, nil
 }
 
+const getMemoryStats = `-- name: GetMemoryStats :one
+SELECT
+    CAST(COALESCE(AVG(CASE WHEN status = 'active' THEN confidence END), 0) AS REAL) AS avg_confidence,
+    CAST(COALESCE(MIN(CASE WHEN status = 'active' THEN created_at END), 0) AS INTEGER) AS oldest_created_at,
+    CAST(COALESCE(MAX(CASE WHEN status = 'active' THEN created_at END), 0) AS INTEGER) AS newest_created_at,
+    CAST(COALESCE(SUM(LENGTH(CAST(content AS BLOB)) + COALESCE(LENGTH(embedding), 0)), 0) AS INTEGER) AS storage_bytes
+FROM memories
+`
+
+type GetMemoryStatsRow struct {
+	AvgConfidence   float64 `json:"avg_confidence"`
+	OldestCreatedAt int64   `json:"oldest_created_at"`
+	NewestCreatedAt int64   `json:"newest_created_at"`
+	StorageBytes    int64   `json:"storage_bytes"`
+}
+
+func (q *Queries) GetMemoryStats(ctx context.Context) (GetMemoryStatsRow, error) {
+	row := q.queryRow(ctx, q.getMemoryStatsStmt, getMemoryStats)
+	var i GetMemoryStatsRow
+	err := row.Scan(
+		&i.AvgConfidence,
+		&i.OldestCreatedAt,
+		&i.NewestCreatedAt,
+		&i.StorageBytes,
+	)
+	return i, err
+}
+
 const listMemories = `-- name: ListMemories :many
 SELECT id, agent_handle, path_scope, content, category, embedding, source_session_id, source_message_id, created_at, updated_at, confidence, last_accessed_at, access_count, supersedes_id, superseded_by_id, supersession_reason, status FROM memories
 WHERE status = COALESCE(?1, 'active')
@@ -648,6 +853,102 @@ func (q *Queries) ListMemoriesByPath(ctx context.Context, arg ListMemoriesByPath
 	return items, nil
 }
 
+const listMemoriesChangedSince = `-- name: ListMemoriesChangedSince :many
+SELECT id, agent_handle, path_scope, content, category, embedding, source_session_id, source_message_id, created_at, updated_at, confidence, last_accessed_at, access_count, supersedes_id, superseded_by_id, supersession_reason, status FROM memories
+WHERE updated_at >= ?
+ORDER BY updated_at, created_at, id
+`
+
+func (q *Queries) ListMemoriesChangedSince(ctx context.Context, updatedAt int64) ([]Memory, error) {
+	rows, err := q.query(ctx, q.listMemoriesChangedSinceStmt, listMemoriesChangedSince, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Memory{}
+	for rows.Next() {
+		var i Memory
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentHandle,
+			&i.PathScope,
+			&i.Content,
+			&i.Category,
+			&i.Embedding,
+			&i.SourceSessionID,
+			&i.SourceMessageID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Confidence,
+			&i.LastAccessedAt,
+			&i.AccessCount,
+			&i.SupersedesID,
+			&i.SupersededByID,
+			&i.SupersessionReason,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMostAccessedMemories = `-- name: ListMostAccessedMemories :many
+SELECT id, agent_handle, path_scope, content, category, embedding, source_session_id, source_message_id, created_at, updated_at, confidence, last_accessed_at, access_count, supersedes_id, superseded_by_id, supersession_reason, status FROM memories
+WHERE status = 'active'
+  AND access_count > 0
+ORDER BY access_count DESC, last_accessed_at DESC
+LIMIT ?
+`
+
+func (q *Queries) ListMostAccessedMemories(ctx context.Context, limit int64) ([]Memory, error) {
+	rows, err := q.query(ctx, q.listMostAccessedMemoriesStmt, listMostAccessedMemories, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Memory{}
+	for rows.Next() {
+		var i Memory
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentHandle,
+			&i.PathScope,
+			&i.Content,
+			&i.Category,
+			&i.Embedding,
+			&i.SourceSessionID,
+			&i.SourceMessageID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Confidence,
+			&i.LastAccessedAt,
+			&i.AccessCount,
+			&i.SupersedesID,
+			&i.SupersededByID,
+			&i.SupersessionReason,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const supersedeMemory = `-- name: SupersedeMemory :exec
 UPDATE memories SET
     status = 'superseded',
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Session tags (user-assigned labels for organizing sessions)
+CREATE TABLE session_tags (
+    session_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    created_at INTEGER NOT NULL,            -- Unix seconds
+    PRIMARY KEY (session_id, tag),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_tags_tag ON session_tags(tag);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_session_tags_tag;
+DROP TABLE IF EXISTS session_tags;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Token usage and estimated cost per session and model. Sessions created
+-- before usage tracking have no rows.
+CREATE TABLE session_usage (
+    session_id TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    cost_usd REAL NOT NULL DEFAULT 0,       -- 0 when the provider publishes no pricing
+    updated_at INTEGER NOT NULL,            -- Unix seconds
+    PRIMARY KEY (session_id, model),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS session_usage;
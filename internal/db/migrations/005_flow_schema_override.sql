@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Input schema used instead of the flow's own when a run was started with
+-- --input-schema-override. NULL for runs validated against the flow's schema.
+ALTER TABLE flow_runs ADD COLUMN input_schema_override TEXT;
+
+-- +goose Down
+
+ALTER TABLE flow_runs DROP COLUMN input_schema_override;
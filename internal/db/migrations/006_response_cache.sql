@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Cached agent responses, keyed by a hash of the request. Only deterministic
+-- requests (temperature 0, no tool calls) are stored.
+CREATE TABLE response_cache (
+    key TEXT PRIMARY KEY,                   -- SHA-256 of model, sampling, tools, and messages
+    model TEXT NOT NULL,
+    response TEXT NOT NULL,
+    created_at INTEGER NOT NULL,            -- Unix seconds
+    expires_at INTEGER NOT NULL             -- Unix seconds
+);
+
+CREATE INDEX idx_response_cache_expires ON response_cache(expires_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_response_cache_expires;
+DROP TABLE IF EXISTS response_cache;
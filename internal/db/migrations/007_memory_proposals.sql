@@ -0,0 +1,22 @@
+-- +goose Up
+
+-- Memories extracted in review mode, waiting for `ayo memory review` to
+-- accept, edit, or reject them.
+CREATE TABLE memory_proposals (
+    id TEXT PRIMARY KEY,
+    agent_handle TEXT,                      -- NULL = global, "@ayo" = agent-specific
+    content TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT 'fact',
+    embedding BLOB,                         -- Serialized float32 vector of content
+    source_session_id TEXT,
+    supersedes_id TEXT,                     -- Memory to supersede when accepted
+    supersession_reason TEXT,
+    created_at INTEGER NOT NULL             -- Unix seconds
+);
+
+CREATE INDEX idx_memory_proposals_created ON memory_proposals(created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_memory_proposals_created;
+DROP TABLE IF EXISTS memory_proposals;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Sampling seed of the latest run in the session that was started with
+-- --seed. NULL for sessions run without one.
+ALTER TABLE sessions ADD COLUMN seed INTEGER;
+
+-- +goose Down
+
+ALTER TABLE sessions DROP COLUMN seed;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- Timing of each agent call in a run started with --profile, as a JSON
+-- array. NULL for runs that weren't profiled.
+ALTER TABLE flow_runs ADD COLUMN stages_json TEXT;
+
+-- +goose Down
+
+ALTER TABLE flow_runs DROP COLUMN stages_json;
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- Skills in the prompt per session, recorded for agents with log_skills.
+-- Each turn adds to the counts of every skill available to the agent.
+CREATE TABLE skill_usage (
+    session_id TEXT NOT NULL,
+    skill TEXT NOT NULL,
+    turns INTEGER NOT NULL DEFAULT 0,       -- Turns with the skill in the prompt
+    selected INTEGER NOT NULL DEFAULT 0,    -- Of those, turns where skill selection chose it
+    skipped INTEGER NOT NULL DEFAULT 0,     -- Turns where skill selection left it out
+    updated_at INTEGER NOT NULL,            -- Unix seconds
+    PRIMARY KEY (session_id, skill),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS skill_usage;
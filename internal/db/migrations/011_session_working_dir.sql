@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Directory ayo was run from when the session was created, so --continue
+-- can pick the latest session for the current directory. NULL for
+-- sessions created before it was recorded.
+ALTER TABLE sessions ADD COLUMN working_dir TEXT;
+
+CREATE INDEX idx_sessions_agent_dir ON sessions(agent_handle, working_dir, updated_at DESC);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_sessions_agent_dir;
+ALTER TABLE sessions DROP COLUMN working_dir;
@@ -9,23 +9,25 @@ import (
 )
 
 type FlowRun struct {
-	ID              string         `json:"id"`
-	FlowName        string         `json:"flow_name"`
-	FlowPath        string         `json:"flow_path"`
-	FlowSource      string         `json:"flow_source"`
-	Status          string         `json:"status"`
-	ExitCode        sql.NullInt64  `json:"exit_code"`
-	ErrorMessage    sql.NullString `json:"error_message"`
-	InputJson       sql.NullString `json:"input_json"`
-	OutputJson      sql.NullString `json:"output_json"`
-	StderrLog       sql.NullString `json:"stderr_log"`
-	StartedAt       int64          `json:"started_at"`
-	FinishedAt      sql.NullInt64  `json:"finished_at"`
-	DurationMs      sql.NullInt64  `json:"duration_ms"`
-	ParentRunID     sql.NullString `json:"parent_run_id"`
-	SessionID       sql.NullString `json:"session_id"`
-	InputValidated  int64          `json:"input_validated"`
-	OutputValidated int64          `json:"output_validated"`
+	ID                  string         `json:"id"`
+	FlowName            string         `json:"flow_name"`
+	FlowPath            string         `json:"flow_path"`
+	FlowSource          string         `json:"flow_source"`
+	Status              string         `json:"status"`
+	ExitCode            sql.NullInt64  `json:"exit_code"`
+	ErrorMessage        sql.NullString `json:"error_message"`
+	InputJson           sql.NullString `json:"input_json"`
+	OutputJson          sql.NullString `json:"output_json"`
+	StderrLog           sql.NullString `json:"stderr_log"`
+	StartedAt           int64          `json:"started_at"`
+	FinishedAt          sql.NullInt64  `json:"finished_at"`
+	DurationMs          sql.NullInt64  `json:"duration_ms"`
+	ParentRunID         sql.NullString `json:"parent_run_id"`
+	SessionID           sql.NullString `json:"session_id"`
+	InputValidated      int64          `json:"input_validated"`
+	OutputValidated     int64          `json:"output_validated"`
+	InputSchemaOverride sql.NullString `json:"input_schema_override"`
+	StagesJson          sql.NullString `json:"stages_json"`
 }
 
 type Memory struct {
@@ -48,6 +50,18 @@ type Memory struct {
 	Status             sql.NullString  `json:"status"`
 }
 
+type MemoryProposal struct {
+	ID                 string         `json:"id"`
+	AgentHandle        sql.NullString `json:"agent_handle"`
+	Content            string         `json:"content"`
+	Category           string         `json:"category"`
+	Embedding          []byte         `json:"embedding"`
+	SourceSessionID    sql.NullString `json:"source_session_id"`
+	SupersedesID       sql.NullString `json:"supersedes_id"`
+	SupersessionReason sql.NullString `json:"supersession_reason"`
+	CreatedAt          int64          `json:"created_at"`
+}
+
 type Message struct {
 	ID         string         `json:"id"`
 	SessionID  string         `json:"session_id"`
@@ -60,6 +74,14 @@ type Message struct {
 	FinishedAt sql.NullInt64  `json:"finished_at"`
 }
 
+type ResponseCache struct {
+	Key       string `json:"key"`
+	Model     string `json:"model"`
+	Response  string `json:"response"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
 type Session struct {
 	ID               string         `json:"id"`
 	AgentHandle      string         `json:"agent_handle"`
@@ -75,6 +97,8 @@ type Session struct {
 	CreatedAt        int64          `json:"created_at"`
 	UpdatedAt        int64          `json:"updated_at"`
 	FinishedAt       sql.NullInt64  `json:"finished_at"`
+	Seed             sql.NullInt64  `json:"seed"`
+	WorkingDir       sql.NullString `json:"working_dir"`
 }
 
 type SessionEdge struct {
@@ -84,3 +108,27 @@ type SessionEdge struct {
 	TriggerMessageID sql.NullString `json:"trigger_message_id"`
 	CreatedAt        int64          `json:"created_at"`
 }
+
+type SessionTag struct {
+	SessionID string `json:"session_id"`
+	Tag       string `json:"tag"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type SessionUsage struct {
+	SessionID        string  `json:"session_id"`
+	Model            string  `json:"model"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	CostUsd          float64 `json:"cost_usd"`
+	UpdatedAt        int64   `json:"updated_at"`
+}
+
+type SkillUsage struct {
+	SessionID string `json:"session_id"`
+	Skill     string `json:"skill"`
+	Turns     int64  `json:"turns"`
+	Selected  int64  `json:"selected"`
+	Skipped   int64  `json:"skipped"`
+	UpdatedAt int64  `json:"updated_at"`
+}
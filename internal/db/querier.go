@@ -10,6 +10,9 @@ import (
 )
 
 type Querier interface {
+	AddSessionTag(ctx context.Context, arg AddSessionTagParams) error
+	AddSessionUsage(ctx context.Context, arg AddSessionUsageParams) error
+	AddSkillUsage(ctx context.Context, arg AddSkillUsageParams) error
 	ClearAllMemories(ctx context.Context, updatedAt int64) error
 	ClearMemoriesByAgent(ctx context.Context, arg ClearMemoriesByAgentParams) error
 	CompleteFlowRun(ctx context.Context, arg CompleteFlowRunParams) (FlowRun, error)
@@ -18,31 +21,41 @@ type Querier interface {
 	CountFlowRunsByStatus(ctx context.Context, status string) (int64, error)
 	CountMemories(ctx context.Context, status sql.NullString) (int64, error)
 	CountMemoriesByAgent(ctx context.Context, arg CountMemoriesByAgentParams) (int64, error)
+	CountMemoriesByCategory(ctx context.Context) ([]CountMemoriesByCategoryRow, error)
+	CountMemoriesByStatus(ctx context.Context) ([]CountMemoriesByStatusRow, error)
+	CountMemoriesPerAgent(ctx context.Context) ([]CountMemoriesPerAgentRow, error)
 	CountMessagesBySession(ctx context.Context, sessionID string) (int64, error)
+	CountSessionTags(ctx context.Context) ([]CountSessionTagsRow, error)
 	CountSessions(ctx context.Context) (int64, error)
 	CountSessionsByAgent(ctx context.Context, agentHandle string) (int64, error)
 	CountSessionsBySource(ctx context.Context, source string) (int64, error)
 	CreateEdge(ctx context.Context, arg CreateEdgeParams) error
 	CreateFlowRun(ctx context.Context, arg CreateFlowRunParams) (FlowRun, error)
 	CreateMemory(ctx context.Context, arg CreateMemoryParams) error
+	CreateMemoryProposal(ctx context.Context, arg CreateMemoryProposalParams) error
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	DeleteEdge(ctx context.Context, arg DeleteEdgeParams) error
 	DeleteEdgesBySession(ctx context.Context, sessionID string) error
+	DeleteExpiredResponses(ctx context.Context, now int64) error
 	DeleteFlowRun(ctx context.Context, id string) error
 	DeleteMemory(ctx context.Context, id string) error
+	DeleteMemoryProposal(ctx context.Context, id string) error
 	DeleteMessage(ctx context.Context, id string) error
 	DeleteMessagesBySession(ctx context.Context, sessionID string) error
 	DeleteSession(ctx context.Context, id string) error
 	ForgetMemory(ctx context.Context, arg ForgetMemoryParams) error
 	GetAllActiveMemoriesWithEmbeddings(ctx context.Context) ([]GetAllActiveMemoriesWithEmbeddingsRow, error)
+	GetCachedResponse(ctx context.Context, arg GetCachedResponseParams) (string, error)
 	GetChildEdges(ctx context.Context, parentID string) ([]SessionEdge, error)
 	GetFlowRun(ctx context.Context, id string) (FlowRun, error)
 	GetFlowRunByPrefix(ctx context.Context, prefix sql.NullString) ([]FlowRun, error)
 	GetLastFlowRun(ctx context.Context, flowName string) (FlowRun, error)
+	GetMemoriesForKeywordSearch(ctx context.Context, arg GetMemoriesForKeywordSearchParams) ([]GetMemoriesForKeywordSearchRow, error)
 	GetMemoriesForSearch(ctx context.Context, arg GetMemoriesForSearchParams) ([]GetMemoriesForSearchRow, error)
 	GetMemory(ctx context.Context, id string) (Memory, error)
 	GetMemoryHistory(ctx context.Context, id string) ([]GetMemoryHistoryRow, error)
+	GetMemoryStats(ctx context.Context) (GetMemoryStatsRow, error)
 	GetMessage(ctx context.Context, id string) (Message, error)
 	GetParentEdges(ctx context.Context, childID string) ([]SessionEdge, error)
 	GetSession(ctx context.Context, id string) (Session, error)
@@ -56,18 +69,33 @@ type Querier interface {
 	ListMemoriesByAgentAndPath(ctx context.Context, arg ListMemoriesByAgentAndPathParams) ([]Memory, error)
 	ListMemoriesByCategory(ctx context.Context, arg ListMemoriesByCategoryParams) ([]Memory, error)
 	ListMemoriesByPath(ctx context.Context, arg ListMemoriesByPathParams) ([]Memory, error)
+	ListMemoriesChangedSince(ctx context.Context, updatedAt int64) ([]Memory, error)
+	ListMemoryProposals(ctx context.Context) ([]MemoryProposal, error)
+	ListMemoryProposalsByAgent(ctx context.Context, agentHandle sql.NullString) ([]MemoryProposal, error)
+	ListMemoryProposalsSince(ctx context.Context, createdAt int64) ([]MemoryProposal, error)
+	ListMostAccessedMemories(ctx context.Context, limit int64) ([]Memory, error)
 	ListMessagesBySession(ctx context.Context, sessionID string) ([]Message, error)
+	ListSessionAgents(ctx context.Context) ([]string, error)
+	ListSessionTags(ctx context.Context, sessionID string) ([]string, error)
 	ListSessions(ctx context.Context, limit int64) ([]Session, error)
 	ListSessionsByAgent(ctx context.Context, arg ListSessionsByAgentParams) ([]Session, error)
+	ListSessionsByAgentInDir(ctx context.Context, arg ListSessionsByAgentInDirParams) ([]Session, error)
 	ListSessionsBySource(ctx context.Context, arg ListSessionsBySourceParams) ([]Session, error)
+	ListSessionsByTag(ctx context.Context, arg ListSessionsByTagParams) ([]Session, error)
 	PruneFlowRunsByAge(ctx context.Context, cutoffTimestamp int64) error
 	PruneFlowRunsByCount(ctx context.Context, keepCount int64) error
+	PutCachedResponse(ctx context.Context, arg PutCachedResponseParams) error
+	RemoveSessionTag(ctx context.Context, arg RemoveSessionTagParams) error
 	SearchSessionsByTitle(ctx context.Context, arg SearchSessionsByTitleParams) ([]Session, error)
+	SumSkillUsage(ctx context.Context, arg SumSkillUsageParams) ([]SumSkillUsageRow, error)
+	SumUsageByAgent(ctx context.Context, arg SumUsageByAgentParams) ([]SumUsageByAgentRow, error)
+	SumUsageByModel(ctx context.Context, arg SumUsageByModelParams) ([]SumUsageByModelRow, error)
 	SupersedeMemory(ctx context.Context, arg SupersedeMemoryParams) error
 	UpdateMemory(ctx context.Context, arg UpdateMemoryParams) error
 	UpdateMemoryAccess(ctx context.Context, arg UpdateMemoryAccessParams) error
 	UpdateMessage(ctx context.Context, arg UpdateMessageParams) error
 	UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error)
+	UpdateSessionSeed(ctx context.Context, arg UpdateSessionSeedParams) error
 	UpdateSessionTitle(ctx context.Context, arg UpdateSessionTitleParams) error
 }
 
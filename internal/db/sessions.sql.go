@@ -58,10 +58,11 @@ INSERT INTO sessions (
     message_count,
     created_at,
     updated_at,
-    finished_at
+    finished_at,
+    working_dir
 ) VALUES (
-    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, 0, strftime('%s', 'now'), strftime('%s', 'now'), NULL
-) RETURNING id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, 0, strftime('%s', 'now'), strftime('%s', 'now'), NULL, ?11
+) RETURNING id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir
 `
 
 type CreateSessionParams struct {
@@ -75,6 +76,7 @@ type CreateSessionParams struct {
 	ChainDepth       int64          `json:"chain_depth"`
 	ChainSource      sql.NullString `json:"chain_source"`
 	Source           string         `json:"source"`
+	WorkingDir       sql.NullString `json:"working_dir"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -89,6 +91,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ChainDepth,
 		arg.ChainSource,
 		arg.Source,
+		arg.WorkingDir,
 	)
 	var i Session
 	err := row.Scan(
@@ -106,6 +109,8 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.FinishedAt,
+		&i.Seed,
+		&i.WorkingDir,
 	)
 	return i, err
 }
@@ -120,7 +125,7 @@ func (q *Queries) DeleteSession(ctx context.Context, id string) error {
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions WHERE id = ?1 LIMIT 1
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE id = ?1 LIMIT 1
 `
 
 func (q *Queries) GetSession(ctx context.Context, id string) (Session, error) {
@@ -141,12 +146,14 @@ func (q *Queries) GetSession(ctx context.Context, id string) (Session, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.FinishedAt,
+		&i.Seed,
+		&i.WorkingDir,
 	)
 	return i, err
 }
 
 const getSessionByPrefix = `-- name: GetSessionByPrefix :many
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions WHERE id LIKE ?1 || '%' ORDER BY updated_at DESC LIMIT 10
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE id LIKE ?1 || '%' ORDER BY updated_at DESC LIMIT 10
 `
 
 func (q *Queries) GetSessionByPrefix(ctx context.Context, prefix sql.NullString) ([]Session, error) {
@@ -173,6 +180,8 @@ func (q *Queries) GetSessionByPrefix(ctx context.Context, prefix sql.NullString)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
 		); err != nil {
 			return nil, err
 		}
@@ -187,8 +196,35 @@ func (q *Queries) GetSessionByPrefix(ctx context.Context, prefix sql.NullString)
 	return items, nil
 }
 
+const listSessionAgents = `-- name: ListSessionAgents :many
+SELECT DISTINCT agent_handle FROM sessions ORDER BY agent_handle
+`
+
+func (q *Queries) ListSessionAgents(ctx context.Context) ([]string, error) {
+	rows, err := q.query(ctx, q.listSessionAgentsStmt, listSessionAgents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var agent_handle string
+		if err := rows.Scan(&agent_handle); err != nil {
+			return nil, err
+		}
+		items = append(items, agent_handle)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSessions = `-- name: ListSessions :many
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions ORDER BY updated_at DESC LIMIT ?1
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions ORDER BY updated_at DESC LIMIT ?1
 `
 
 func (q *Queries) ListSessions(ctx context.Context, limit int64) ([]Session, error) {
@@ -215,6 +251,8 @@ func (q *Queries) ListSessions(ctx context.Context, limit int64) ([]Session, err
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
 		); err != nil {
 			return nil, err
 		}
@@ -230,7 +268,7 @@ func (q *Queries) ListSessions(ctx context.Context, limit int64) ([]Session, err
 }
 
 const listSessionsByAgent = `-- name: ListSessionsByAgent :many
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions WHERE agent_handle = ?1 ORDER BY updated_at DESC LIMIT ?2
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE agent_handle = ?1 ORDER BY updated_at DESC LIMIT ?2
 `
 
 type ListSessionsByAgentParams struct {
@@ -262,6 +300,58 @@ func (q *Queries) ListSessionsByAgent(ctx context.Context, arg ListSessionsByAge
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionsByAgentInDir = `-- name: ListSessionsByAgentInDir :many
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE agent_handle = ?1 AND working_dir = ?2 ORDER BY updated_at DESC LIMIT ?3
+`
+
+type ListSessionsByAgentInDirParams struct {
+	AgentHandle string         `json:"agent_handle"`
+	WorkingDir  sql.NullString `json:"working_dir"`
+	Limit       int64          `json:"limit"`
+}
+
+func (q *Queries) ListSessionsByAgentInDir(ctx context.Context, arg ListSessionsByAgentInDirParams) ([]Session, error) {
+	rows, err := q.query(ctx, q.listSessionsByAgentInDirStmt, listSessionsByAgentInDir, arg.AgentHandle, arg.WorkingDir, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.AgentHandle,
+			&i.Title,
+			&i.Source,
+			&i.InputSchema,
+			&i.OutputSchema,
+			&i.StructuredInput,
+			&i.StructuredOutput,
+			&i.ChainDepth,
+			&i.ChainSource,
+			&i.MessageCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
 		); err != nil {
 			return nil, err
 		}
@@ -277,7 +367,7 @@ func (q *Queries) ListSessionsByAgent(ctx context.Context, arg ListSessionsByAge
 }
 
 const listSessionsBySource = `-- name: ListSessionsBySource :many
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions WHERE source = ?1 ORDER BY updated_at DESC LIMIT ?2
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE source = ?1 ORDER BY updated_at DESC LIMIT ?2
 `
 
 type ListSessionsBySourceParams struct {
@@ -309,6 +399,8 @@ func (q *Queries) ListSessionsBySource(ctx context.Context, arg ListSessionsBySo
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
 		); err != nil {
 			return nil, err
 		}
@@ -324,7 +416,7 @@ func (q *Queries) ListSessionsBySource(ctx context.Context, arg ListSessionsBySo
 }
 
 const searchSessionsByTitle = `-- name: SearchSessionsByTitle :many
-SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at FROM sessions WHERE title LIKE '%' || ?1 || '%' ORDER BY updated_at DESC LIMIT ?2
+SELECT id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir FROM sessions WHERE title LIKE '%' || ?1 || '%' ORDER BY updated_at DESC LIMIT ?2
 `
 
 type SearchSessionsByTitleParams struct {
@@ -356,6 +448,8 @@ func (q *Queries) SearchSessionsByTitle(ctx context.Context, arg SearchSessionsB
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.FinishedAt,
+			&i.Seed,
+			&i.WorkingDir,
 		); err != nil {
 			return nil, err
 		}
@@ -377,7 +471,7 @@ UPDATE sessions SET
     finished_at = ?3,
     updated_at = strftime('%s', 'now')
 WHERE id = ?4
-RETURNING id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at
+RETURNING id, agent_handle, title, source, input_schema, output_schema, structured_input, structured_output, chain_depth, chain_source, message_count, created_at, updated_at, finished_at, seed, working_dir
 `
 
 type UpdateSessionParams struct {
@@ -410,10 +504,28 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.FinishedAt,
+		&i.Seed,
+		&i.WorkingDir,
 	)
 	return i, err
 }
 
+const updateSessionSeed = `-- name: UpdateSessionSeed :exec
+UPDATE sessions SET
+    seed = ?1
+WHERE id = ?2
+`
+
+type UpdateSessionSeedParams struct {
+	Seed sql.NullInt64 `json:"seed"`
+	ID   string        `json:"id"`
+}
+
+func (q *Queries) UpdateSessionSeed(ctx context.Context, arg UpdateSessionSeedParams) error {
+	_, err := q.exec(ctx, q.updateSessionSeedStmt, updateSessionSeed, arg.Seed, arg.ID)
+	return err
+}
+
 const updateSessionTitle = `-- name: UpdateSessionTitle :exec
 UPDATE sessions SET
     title = ?1,
@@ -9,7 +9,8 @@ INSERT INTO flow_runs (
     input_validated,
     started_at,
     parent_run_id,
-    session_id
+    session_id,
+    input_schema_override
 ) VALUES (
     @id,
     @flow_name,
@@ -20,7 +21,8 @@ INSERT INTO flow_runs (
     @input_validated,
     @started_at,
     @parent_run_id,
-    @session_id
+    @session_id,
+    @input_schema_override
 ) RETURNING *;
 
 -- name: CompleteFlowRun :one
@@ -32,7 +34,8 @@ UPDATE flow_runs SET
     stderr_log = @stderr_log,
     output_validated = @output_validated,
     finished_at = @finished_at,
-    duration_ms = @duration_ms
+    duration_ms = @duration_ms,
+    stages_json = @stages_json
 WHERE id = @id
 RETURNING *;
 
@@ -119,6 +119,14 @@ WHERE status = 'active'
   AND (agent_handle = sqlc.narg(agent_handle) OR agent_handle IS NULL OR sqlc.narg(agent_handle) IS NULL)
   AND (path_scope = sqlc.narg(path_scope) OR path_scope IS NULL OR sqlc.narg(path_scope) IS NULL);
 
+-- name: GetMemoriesForKeywordSearch :many
+SELECT id, agent_handle, path_scope, content, category, embedding, confidence,
+       last_accessed_at, access_count, created_at
+FROM memories
+WHERE status = 'active'
+  AND (agent_handle = sqlc.narg(agent_handle) OR agent_handle IS NULL OR sqlc.narg(agent_handle) IS NULL)
+  AND (path_scope = sqlc.narg(path_scope) OR path_scope IS NULL OR sqlc.narg(path_scope) IS NULL);
+
 -- name: ClearMemoriesByAgent :exec
 UPDATE memories SET
     status = 'forgotten',
@@ -129,3 +137,43 @@ WHERE agent_handle = ?;
 UPDATE memories SET
     status = 'forgotten',
     updated_at = ?;
+
+-- name: CountMemoriesByStatus :many
+SELECT CAST(COALESCE(status, 'active') AS TEXT) AS status, COUNT(*) AS count
+FROM memories
+GROUP BY COALESCE(status, 'active')
+ORDER BY count DESC, status;
+
+-- name: CountMemoriesByCategory :many
+SELECT category, COUNT(*) AS count
+FROM memories
+WHERE status = 'active'
+GROUP BY category
+ORDER BY count DESC, category;
+
+-- name: CountMemoriesPerAgent :many
+SELECT CAST(COALESCE(agent_handle, '') AS TEXT) AS agent_handle, COUNT(*) AS count
+FROM memories
+WHERE status = 'active'
+GROUP BY agent_handle
+ORDER BY count DESC, agent_handle;
+
+-- name: GetMemoryStats :one
+SELECT
+    CAST(COALESCE(AVG(CASE WHEN status = 'active' THEN confidence END), 0) AS REAL) AS avg_confidence,
+    CAST(COALESCE(MIN(CASE WHEN status = 'active' THEN created_at END), 0) AS INTEGER) AS oldest_created_at,
+    CAST(COALESCE(MAX(CASE WHEN status = 'active' THEN created_at END), 0) AS INTEGER) AS newest_created_at,
+    CAST(COALESCE(SUM(LENGTH(CAST(content AS BLOB)) + COALESCE(LENGTH(embedding), 0)), 0) AS INTEGER) AS storage_bytes
+FROM memories;
+
+-- name: ListMemoriesChangedSince :many
+SELECT * FROM memories
+WHERE updated_at >= ?
+ORDER BY updated_at, created_at, id;
+
+-- name: ListMostAccessedMemories :many
+SELECT * FROM memories
+WHERE status = 'active'
+  AND access_count > 0
+ORDER BY access_count DESC, last_accessed_at DESC
+LIMIT ?;
@@ -0,0 +1,22 @@
+-- name: CreateMemoryProposal :exec
+INSERT INTO memory_proposals (
+    id, agent_handle, content, category, embedding,
+    source_session_id, supersedes_id, supersession_reason, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListMemoryProposals :many
+SELECT * FROM memory_proposals
+ORDER BY created_at, id;
+
+-- name: ListMemoryProposalsByAgent :many
+SELECT * FROM memory_proposals
+WHERE agent_handle = ?
+ORDER BY created_at, id;
+
+-- name: ListMemoryProposalsSince :many
+SELECT * FROM memory_proposals
+WHERE created_at >= ?
+ORDER BY created_at, id;
+
+-- name: DeleteMemoryProposal :exec
+DELETE FROM memory_proposals WHERE id = ?;
@@ -0,0 +1,15 @@
+-- name: GetCachedResponse :one
+SELECT response FROM response_cache
+WHERE key = @key AND expires_at > @now;
+
+-- name: PutCachedResponse :exec
+INSERT INTO response_cache (key, model, response, created_at, expires_at)
+VALUES (@key, @model, @response, @created_at, @expires_at)
+ON CONFLICT (key) DO UPDATE SET
+    model = excluded.model,
+    response = excluded.response,
+    created_at = excluded.created_at,
+    expires_at = excluded.expires_at;
+
+-- name: DeleteExpiredResponses :exec
+DELETE FROM response_cache WHERE expires_at <= @now;
@@ -0,0 +1,21 @@
+-- name: AddSessionTag :exec
+INSERT OR IGNORE INTO session_tags (session_id, tag, created_at)
+VALUES (@session_id, @tag, strftime('%s', 'now'));
+
+-- name: RemoveSessionTag :exec
+DELETE FROM session_tags WHERE session_id = @session_id AND tag = @tag;
+
+-- name: ListSessionTags :many
+SELECT tag FROM session_tags WHERE session_id = @session_id ORDER BY tag;
+
+-- name: ListSessionsByTag :many
+SELECT sessions.* FROM sessions
+JOIN session_tags ON session_tags.session_id = sessions.id
+WHERE session_tags.tag = @tag
+ORDER BY sessions.updated_at DESC
+LIMIT @limit;
+
+-- name: CountSessionTags :many
+SELECT tag, COUNT(*) AS count FROM session_tags
+GROUP BY tag
+ORDER BY count DESC, tag;
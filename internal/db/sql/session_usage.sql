@@ -0,0 +1,37 @@
+-- name: AddSessionUsage :exec
+INSERT INTO session_usage (session_id, model, prompt_tokens, completion_tokens, cost_usd, updated_at)
+VALUES (@session_id, @model, @prompt_tokens, @completion_tokens, @cost_usd, strftime('%s', 'now'))
+ON CONFLICT (session_id, model) DO UPDATE SET
+    prompt_tokens = prompt_tokens + excluded.prompt_tokens,
+    completion_tokens = completion_tokens + excluded.completion_tokens,
+    cost_usd = cost_usd + excluded.cost_usd,
+    updated_at = excluded.updated_at;
+
+-- name: SumUsageByAgent :many
+SELECT
+    sessions.agent_handle,
+    COUNT(DISTINCT sessions.id) AS sessions,
+    COUNT(DISTINCT CASE WHEN session_usage.session_id IS NULL THEN sessions.id END) AS untracked_sessions,
+    CAST(COALESCE(SUM(session_usage.prompt_tokens), 0) AS INTEGER) AS prompt_tokens,
+    CAST(COALESCE(SUM(session_usage.completion_tokens), 0) AS INTEGER) AS completion_tokens,
+    CAST(COALESCE(SUM(session_usage.cost_usd), 0) AS REAL) AS cost_usd
+FROM sessions
+LEFT JOIN session_usage ON session_usage.session_id = sessions.id
+WHERE sessions.created_at >= @since AND sessions.created_at <= @until
+    AND (@agent_handle = '' OR sessions.agent_handle = @agent_handle)
+GROUP BY sessions.agent_handle
+ORDER BY cost_usd DESC, prompt_tokens + completion_tokens DESC, sessions.agent_handle;
+
+-- name: SumUsageByModel :many
+SELECT
+    CAST(COALESCE(session_usage.model, '') AS TEXT) AS model,
+    COUNT(DISTINCT sessions.id) AS sessions,
+    CAST(COALESCE(SUM(session_usage.prompt_tokens), 0) AS INTEGER) AS prompt_tokens,
+    CAST(COALESCE(SUM(session_usage.completion_tokens), 0) AS INTEGER) AS completion_tokens,
+    CAST(COALESCE(SUM(session_usage.cost_usd), 0) AS REAL) AS cost_usd
+FROM sessions
+LEFT JOIN session_usage ON session_usage.session_id = sessions.id
+WHERE sessions.created_at >= @since AND sessions.created_at <= @until
+    AND (@agent_handle = '' OR sessions.agent_handle = @agent_handle)
+GROUP BY session_usage.model
+ORDER BY cost_usd DESC, prompt_tokens + completion_tokens DESC, model;
@@ -13,9 +13,10 @@ INSERT INTO sessions (
     message_count,
     created_at,
     updated_at,
-    finished_at
+    finished_at,
+    working_dir
 ) VALUES (
-    @id, @agent_handle, @title, @input_schema, @output_schema, @structured_input, @structured_output, @chain_depth, @chain_source, @source, 0, strftime('%s', 'now'), strftime('%s', 'now'), NULL
+    @id, @agent_handle, @title, @input_schema, @output_schema, @structured_input, @structured_output, @chain_depth, @chain_source, @source, 0, strftime('%s', 'now'), strftime('%s', 'now'), NULL, @working_dir
 ) RETURNING *;
 
 -- name: GetSession :one
@@ -24,12 +25,18 @@ SELECT * FROM sessions WHERE id = @id LIMIT 1;
 -- name: GetSessionByPrefix :many
 SELECT * FROM sessions WHERE id LIKE @prefix || '%' ORDER BY updated_at DESC LIMIT 10;
 
+-- name: ListSessionAgents :many
+SELECT DISTINCT agent_handle FROM sessions ORDER BY agent_handle;
+
 -- name: ListSessions :many
 SELECT * FROM sessions ORDER BY updated_at DESC LIMIT @limit;
 
 -- name: ListSessionsByAgent :many
 SELECT * FROM sessions WHERE agent_handle = @agent_handle ORDER BY updated_at DESC LIMIT @limit;
 
+-- name: ListSessionsByAgentInDir :many
+SELECT * FROM sessions WHERE agent_handle = @agent_handle AND working_dir = @working_dir ORDER BY updated_at DESC LIMIT @limit;
+
 -- name: SearchSessionsByTitle :many
 SELECT * FROM sessions WHERE title LIKE '%' || @query || '%' ORDER BY updated_at DESC LIMIT @limit;
 
@@ -42,6 +49,11 @@ UPDATE sessions SET
 WHERE id = @id
 RETURNING *;
 
+-- name: UpdateSessionSeed :exec
+UPDATE sessions SET
+    seed = @seed
+WHERE id = @id;
+
 -- name: UpdateSessionTitle :exec
 UPDATE sessions SET
     title = @title,
@@ -0,0 +1,23 @@
+-- name: AddSkillUsage :exec
+INSERT INTO skill_usage (session_id, skill, turns, selected, skipped, updated_at)
+VALUES (@session_id, @skill, @turns, @selected, @skipped, strftime('%s', 'now'))
+ON CONFLICT (session_id, skill) DO UPDATE SET
+    turns = turns + excluded.turns,
+    selected = selected + excluded.selected,
+    skipped = skipped + excluded.skipped,
+    updated_at = excluded.updated_at;
+
+-- name: SumSkillUsage :many
+SELECT
+    sessions.agent_handle,
+    skill_usage.skill,
+    COUNT(DISTINCT sessions.id) AS sessions,
+    CAST(SUM(skill_usage.turns) AS INTEGER) AS turns,
+    CAST(SUM(skill_usage.selected) AS INTEGER) AS selected,
+    CAST(SUM(skill_usage.skipped) AS INTEGER) AS skipped
+FROM skill_usage
+JOIN sessions ON sessions.id = skill_usage.session_id
+WHERE sessions.created_at >= @since AND sessions.created_at <= @until
+    AND (@agent_handle = '' OR sessions.agent_handle = @agent_handle)
+GROUP BY sessions.agent_handle, skill_usage.skill
+ORDER BY sessions.agent_handle, turns DESC, skill_usage.skill;
@@ -0,0 +1,384 @@
+// Package export writes and reads run bundles: tar.gz archives that package
+// what is needed to reproduce a problem in a bug report. A bundle is laid out
+// as:
+//
+//	manifest.json
+//	config.json
+//	agents/@handle/...
+//	sessions/<id>.json
+//
+// Secrets are redacted from every file before it is written, and files are
+// stored without execute permissions. Reading a bundle only decodes it;
+// nothing in it is run.
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/redact"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/version"
+)
+
+// FormatVersion is the bundle layout version recorded in the manifest.
+const FormatVersion = 1
+
+// maxFileSize bounds a single file read back from a bundle.
+const maxFileSize = 64 << 20
+
+// Manifest describes a bundle's contents.
+type Manifest struct {
+	Format     int           `json:"format"`
+	AyoVersion string        `json:"ayo_version"`
+	CreatedAt  time.Time     `json:"created_at"`
+	OS         string        `json:"os"`
+	Arch       string        `json:"arch"`
+	Agents     []string      `json:"agents"`
+	Sessions   []SessionInfo `json:"sessions"`
+	Files      []string      `json:"files"`
+	Redactions int           `json:"redactions"` // Secrets replaced while writing
+}
+
+// SessionInfo summarizes an exported session.
+type SessionInfo struct {
+	ID        string    `json:"id"`
+	Agent     string    `json:"agent"`
+	Model     string    `json:"model,omitempty"` // Model of the latest reply
+	Title     string    `json:"title,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Messages  int       `json:"messages"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Transcript is an exported session and its messages.
+type Transcript struct {
+	Session  SessionInfo `json:"session"`
+	Messages []Message   `json:"messages"`
+}
+
+// Message is a session message. Parts use the session storage encoding;
+// file data is left out.
+type Message struct {
+	Role      string          `json:"role"`
+	Model     string          `json:"model,omitempty"`
+	Provider  string          `json:"provider,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Parts     json.RawMessage `json:"parts"`
+}
+
+// SessionMessage converts m back to a session message for rendering.
+func (m Message) SessionMessage(sessionID string) (session.Message, error) {
+	parts, err := session.UnmarshalParts(m.Parts)
+	if err != nil {
+		return session.Message{}, err
+	}
+	return session.Message{
+		SessionID: sessionID,
+		Role:      session.MessageRole(m.Role),
+		Parts:     parts,
+		Model:     m.Model,
+		Provider:  m.Provider,
+		CreatedAt: m.CreatedAt.Unix(),
+	}, nil
+}
+
+// Writer writes a bundle. Call Close to write the manifest and finish the
+// archive.
+type Writer struct {
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	redactor *redact.Redactor
+	manifest Manifest
+}
+
+// NewWriter starts a bundle written to w. Every file added is redacted with
+// redactor.
+func NewWriter(w io.Writer, redactor *redact.Redactor) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz:       gz,
+		tw:       tar.NewWriter(gz),
+		redactor: redactor,
+		manifest: Manifest{
+			Format:     FormatVersion,
+			AyoVersion: version.Version,
+			CreatedAt:  time.Now().UTC(),
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			Agents:     []string{},
+			Sessions:   []SessionInfo{},
+		},
+	}
+}
+
+// AddConfig writes the effective config settings as config.json. Callers
+// pass settings with secrets already masked; string values are redacted
+// as well, to catch secrets in other settings.
+func (w *Writer) AddConfig(settings any) error {
+	return w.writeJSON("config.json", settings)
+}
+
+// AddAgent writes the files of the agent directory dir under
+// agents/<handle>/.
+func (w *Writer) AddAgent(handle, dir string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return w.writeFile(path.Join("agents", handle, filepath.ToSlash(rel)), data)
+	})
+	if err != nil {
+		return fmt.Errorf("export agent %s: %w", handle, err)
+	}
+	w.manifest.Agents = append(w.manifest.Agents, handle)
+	return nil
+}
+
+// AddSession writes the transcript of sess, with its tags, as
+// sessions/<id>.json.
+func (w *Writer) AddSession(sess session.Session, tags []string, messages []session.Message) error {
+	t := Transcript{
+		Session: SessionInfo{
+			ID:        sess.ID,
+			Agent:     sess.AgentHandle,
+			Title:     w.redact(sess.Title),
+			Tags:      tags,
+			Messages:  len(messages),
+			CreatedAt: time.Unix(sess.CreatedAt, 0).UTC(),
+			UpdatedAt: time.Unix(sess.UpdatedAt, 0).UTC(),
+		},
+		Messages: make([]Message, len(messages)),
+	}
+	for i, m := range messages {
+		parts, err := session.MarshalParts(withoutFileData(m.Parts))
+		if err != nil {
+			return fmt.Errorf("export session %s: %w", sess.ID, err)
+		}
+		t.Messages[i] = Message{
+			Role:      string(m.Role),
+			Model:     m.Model,
+			Provider:  m.Provider,
+			CreatedAt: time.Unix(m.CreatedAt, 0).UTC(),
+			Parts:     parts,
+		}
+		if m.Model != "" {
+			t.Session.Model = m.Model
+		}
+	}
+	if err := w.writeJSON(path.Join("sessions", sess.ID+".json"), t); err != nil {
+		return err
+	}
+	w.manifest.Sessions = append(w.manifest.Sessions, t.Session)
+	return nil
+}
+
+// Close writes manifest.json and finishes the archive. It returns the
+// manifest that was written.
+func (w *Writer) Close() (Manifest, error) {
+	m := w.manifest
+	m.Files = append(m.Files, "manifest.json")
+	data, err := encodeIndented(m)
+	if err != nil {
+		return m, err
+	}
+	if err := w.writeEntry("manifest.json", data); err != nil {
+		return m, err
+	}
+	if err := w.tw.Close(); err != nil {
+		return m, err
+	}
+	return m, w.gz.Close()
+}
+
+// writeJSON encodes v with every string value redacted. Redacting the
+// decoded strings rather than the encoded file keeps the JSON valid and
+// matches secrets as they were written, without JSON escaping.
+func (w *Writer) writeJSON(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	data, err = encodeIndented(w.redactValue(doc))
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	return w.addFile(name, data)
+}
+
+// encodeIndented encodes v as indented JSON, keeping prompt tags such as
+// <tools> readable.
+func encodeIndented(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redactValue redacts the strings in a decoded JSON value.
+func (w *Writer) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return w.redact(v)
+	case []any:
+		for i := range v {
+			v[i] = w.redactValue(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = w.redactValue(v[k])
+		}
+	}
+	return v
+}
+
+// writeFile redacts data and adds it to the bundle.
+func (w *Writer) writeFile(name string, data []byte) error {
+	return w.addFile(name, []byte(w.redact(string(data))))
+}
+
+func (w *Writer) redact(s string) string {
+	redacted, n := w.redactor.Redact(s)
+	w.manifest.Redactions += n
+	return redacted
+}
+
+// addFile adds data to the archive and the manifest.
+func (w *Writer) addFile(name string, data []byte) error {
+	if err := w.writeEntry(name, data); err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, name)
+	return nil
+}
+
+func (w *Writer) writeEntry(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  w.manifest.CreatedAt,
+		Typeflag: tar.TypeReg,
+	}
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+// withoutFileData drops the data of file parts, keeping their names and
+// media types.
+func withoutFileData(parts []session.ContentPart) []session.ContentPart {
+	out := make([]session.ContentPart, len(parts))
+	for i, p := range parts {
+		if f, ok := p.(session.FileContent); ok {
+			f.Data = nil
+			p = f
+		}
+		out[i] = p
+	}
+	return out
+}
+
+// Bundle is a bundle read back for inspection.
+type Bundle struct {
+	Manifest    Manifest
+	Transcripts []Transcript // In manifest order
+}
+
+// Read decodes the bundle at path. Only the manifest and transcripts are
+// decoded; other files are skipped.
+func Read(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a bundle: %w", path, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	transcripts := map[string]Transcript{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		name := strings.TrimPrefix(hdr.Name, "./")
+		switch {
+		case name == "manifest.json":
+			manifest = &Manifest{}
+			if err := decodeEntry(tr, name, manifest); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(name, "sessions/") && strings.HasSuffix(name, ".json"):
+			var t Transcript
+			if err := decodeEntry(tr, name, &t); err != nil {
+				return nil, err
+			}
+			transcripts[t.Session.ID] = t
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%s has no manifest.json", path)
+	}
+
+	b := &Bundle{Manifest: *manifest}
+	for _, s := range manifest.Sessions {
+		if t, ok := transcripts[s.ID]; ok {
+			b.Transcripts = append(b.Transcripts, t)
+		}
+	}
+	return b, nil
+}
+
+func decodeEntry(r io.Reader, name string, v any) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxFileSize))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package export
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/redact"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+const testKey = "sk-proj-abcdefghijklmnopqrstuvwxyz0123"
+
+func TestWriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	agentDir := filepath.Join(dir, "@helper")
+	if err := os.MkdirAll(filepath.Join(agentDir, "skills", "deploy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(agentDir, "system.md"), []byte("Use key "+testKey), 0o644)
+	os.WriteFile(filepath.Join(agentDir, "skills", "deploy", "run.sh"), []byte("#!/bin/sh\n"), 0o755)
+
+	redactor, err := redact.New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "bundle.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(f, redactor)
+	if err := w.AddConfig(map[string]any{"default_model": "gpt-5.2", "webhook": "https://x.test?token=" + testKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddAgent("@helper", agentDir); err != nil {
+		t.Fatal(err)
+	}
+	sess := session.Session{ID: "0123456789abcdef", AgentHandle: "@helper", Title: "deploy", CreatedAt: 1700000000, UpdatedAt: 1700000060}
+	messages := []session.Message{
+		{Role: session.RoleUser, Parts: []session.ContentPart{
+			session.TextContent{Text: "deploy with " + testKey},
+			session.FileContent{Filename: "notes.txt", Data: []byte("attachment"), MediaType: "text/plain"},
+		}},
+		{Role: session.RoleAssistant, Model: "gpt-5.2", Parts: []session.ContentPart{
+			session.ToolCall{ID: "1", Name: "bash", Input: `{"command":"curl -H \"Authorization: Bearer abcdefgh12345678\" x.test"}`},
+		}},
+	}
+	if err := w.AddSession(sess, []string{"work"}, messages); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if manifest.Redactions != 4 {
+		t.Errorf("redactions = %d, want 4", manifest.Redactions)
+	}
+
+	// No file keeps a secret, attachment data, or an execute bit
+	for name, entry := range readEntries(t, path) {
+		if strings.Contains(entry.data, testKey) || strings.Contains(entry.data, "abcdefgh12345678") {
+			t.Errorf("%s contains a secret:\n%s", name, entry.data)
+		}
+		if strings.Contains(entry.data, "YXR0YWNobWVudA") {
+			t.Errorf("%s contains attachment data", name)
+		}
+		if entry.mode&0o111 != 0 {
+			t.Errorf("%s mode = %o, want no execute bits", name, entry.mode)
+		}
+		if strings.HasSuffix(name, ".json") && !json.Valid([]byte(entry.data)) {
+			t.Errorf("%s is not valid JSON", name)
+		}
+	}
+
+	b, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(b.Manifest.Agents) != 1 || len(b.Manifest.Sessions) != 1 || len(b.Transcripts) != 1 {
+		t.Fatalf("manifest = %+v", b.Manifest)
+	}
+	info := b.Transcripts[0].Session
+	if info.Agent != "@helper" || info.Model != "gpt-5.2" || info.Title != "deploy" || len(info.Tags) != 1 ||
+		info.CreatedAt.Unix() != sess.CreatedAt || info.UpdatedAt.Unix() != sess.UpdatedAt {
+		t.Errorf("session metadata = %+v", info)
+	}
+	msg, err := b.Transcripts[0].Messages[0].SessionMessage(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.TextContent(); got != "deploy with "+redact.Placeholder {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestReadRejectsNonBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.txt")
+	os.WriteFile(path, []byte("hello"), 0o644)
+	if _, err := Read(path); err == nil {
+		t.Error("expected an error for a file that is not a bundle")
+	}
+}
+
+type tarEntry struct {
+	data string
+	mode int64
+}
+
+func readEntries(t *testing.T, path string) map[string]tarEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	entries := map[string]tarEntry{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		entries[hdr.Name] = tarEntry{data: string(data), mode: hdr.Mode}
+	}
+	return entries
+}
@@ -0,0 +1,164 @@
+package flows
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kinds of OutputChange.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// OutputChange is a difference between two outputs of a flow.
+type OutputChange struct {
+	Pointer string `json:"pointer"` // JSON pointer to the value; "" is the whole output
+	Kind    string `json:"kind"`    // One of the Change constants
+	Before  any    `json:"before,omitempty"`
+	After   any    `json:"after,omitempty"`
+}
+
+// DiffOutputs compares two flow outputs as JSON and returns their
+// differences in document order. Values at the ignore pointers, and
+// everything beneath them, are skipped; a "*" segment in an ignore pointer
+// matches any key or array index, as in "/items/*/id". Outputs that aren't
+// JSON are compared as text.
+func DiffOutputs(before, after string, ignore []string) ([]OutputChange, error) {
+	ignored := make([][]string, len(ignore))
+	for i, p := range ignore {
+		segments, err := parsePointer(p)
+		if err != nil {
+			return nil, err
+		}
+		ignored[i] = segments
+	}
+
+	var b, a any
+	if json.Unmarshal([]byte(before), &b) != nil || json.Unmarshal([]byte(after), &a) != nil {
+		if pointerIgnored(nil, ignored) || strings.TrimSpace(before) == strings.TrimSpace(after) {
+			return nil, nil
+		}
+		return []OutputChange{{Kind: ChangeChanged, Before: before, After: after}}, nil
+	}
+
+	var changes []OutputChange
+	diffValues(nil, b, a, ignored, &changes)
+	return changes, nil
+}
+
+// diffValues appends the differences between before and after, found at
+// the pointer made of segments, to changes.
+func diffValues(segments []string, before, after any, ignored [][]string, changes *[]OutputChange) {
+	if pointerIgnored(segments, ignored) {
+		return
+	}
+
+	switch b := before.(type) {
+	case map[string]any:
+		if a, ok := after.(map[string]any); ok {
+			keys := make([]string, 0, len(b)+len(a))
+			for k := range b {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				bv, inBefore := b[k]
+				av, inAfter := a[k]
+				child := append(slices.Clip(segments), k)
+				switch {
+				case !inAfter:
+					if !pointerIgnored(child, ignored) {
+						*changes = append(*changes, OutputChange{Pointer: formatPointer(child), Kind: ChangeRemoved, Before: bv})
+					}
+				case !inBefore:
+					if !pointerIgnored(child, ignored) {
+						*changes = append(*changes, OutputChange{Pointer: formatPointer(child), Kind: ChangeAdded, After: av})
+					}
+				default:
+					diffValues(child, bv, av, ignored, changes)
+				}
+			}
+			return
+		}
+	case []any:
+		if a, ok := after.([]any); ok {
+			for i := range max(len(b), len(a)) {
+				child := append(slices.Clip(segments), strconv.Itoa(i))
+				switch {
+				case i >= len(a):
+					if !pointerIgnored(child, ignored) {
+						*changes = append(*changes, OutputChange{Pointer: formatPointer(child), Kind: ChangeRemoved, Before: b[i]})
+					}
+				case i >= len(b):
+					if !pointerIgnored(child, ignored) {
+						*changes = append(*changes, OutputChange{Pointer: formatPointer(child), Kind: ChangeAdded, After: a[i]})
+					}
+				default:
+					diffValues(child, b[i], a[i], ignored, changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, OutputChange{Pointer: formatPointer(segments), Kind: ChangeChanged, Before: before, After: after})
+	}
+}
+
+// pointerIgnored reports whether the value at segments is at or beneath one
+// of the ignored pointers.
+func pointerIgnored(segments []string, ignored [][]string) bool {
+	for _, pattern := range ignored {
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, s := range pattern {
+			if s != "*" && s != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into unescaped segments.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with /", p)
+	}
+	segments := strings.Split(p[1:], "/")
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segments, nil
+}
+
+// formatPointer joins segments into an RFC 6901 JSON pointer.
+func formatPointer(segments []string) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package flows
+
+import (
+	"testing"
+)
+
+func TestDiffOutputs(t *testing.T) {
+	before := `{"id": "run-1", "summary": "ok", "items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "a/b": 1, "gone": true}`
+	after := `{"id": "run-2", "summary": "ok", "items": [{"id": 7, "name": "a"}, {"id": 8, "name": "c"}, {"id": 9, "name": "d"}], "a/b": 2, "new": null}`
+
+	changes, err := DiffOutputs(before, after, []string{"/id", "/items/*/id"})
+	if err != nil {
+		t.Fatalf("DiffOutputs: %v", err)
+	}
+	want := []OutputChange{
+		{Pointer: "/a~1b", Kind: ChangeChanged, Before: float64(1), After: float64(2)},
+		{Pointer: "/gone", Kind: ChangeRemoved, Before: true},
+		{Pointer: "/items/1/name", Kind: ChangeChanged, Before: "b", After: "c"},
+		{Pointer: "/items/2", Kind: ChangeAdded, After: map[string]any{"id": float64(9), "name": "d"}},
+		{Pointer: "/new", Kind: ChangeAdded},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i].Pointer != want[i].Pointer || changes[i].Kind != want[i].Kind {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+	if changes[2].Before != "b" || changes[2].After != "c" {
+		t.Errorf("values of %s = %v, %v", changes[2].Pointer, changes[2].Before, changes[2].After)
+	}
+
+	// Formatting and key order don't matter
+	if changes, _ := DiffOutputs(`{"a": 1, "b": [1, 2]}`, "{\n  \"b\": [1, 2],\n  \"a\": 1\n}\n", nil); len(changes) != 0 {
+		t.Errorf("reformatted output changed: %+v", changes)
+	}
+
+	// A changed type is one change at the value
+	changes, _ = DiffOutputs(`{"a": [1]}`, `{"a": {"0": 1}}`, nil)
+	if len(changes) != 1 || changes[0].Pointer != "/a" || changes[0].Kind != ChangeChanged {
+		t.Errorf("type change = %+v", changes)
+	}
+
+	// Text outputs are compared as text
+	changes, _ = DiffOutputs("done\n", "done", nil)
+	if len(changes) != 0 {
+		t.Errorf("identical text changed: %+v", changes)
+	}
+	changes, _ = DiffOutputs("done", "failed", nil)
+	if len(changes) != 1 || changes[0].Pointer != "" || changes[0].After != "failed" {
+		t.Errorf("text change = %+v", changes)
+	}
+
+	// Ignoring the root ignores everything
+	if changes, _ := DiffOutputs(`{"a": 1}`, `{"a": 2}`, []string{""}); len(changes) != 0 {
+		t.Errorf("root ignored: %+v", changes)
+	}
+
+	if _, err := DiffOutputs("{}", "{}", []string{"id"}); err == nil {
+		t.Error("expected an error for a pointer without a leading /")
+	}
+}
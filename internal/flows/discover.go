@@ -1,6 +1,7 @@
 package flows
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -111,6 +112,25 @@ func loadSimpleFlow(path string, source FlowSource) (*Flow, error) {
 		Raw: raw,
 	}
 
+	if expr, ok := raw.Frontmatter["transform"]; ok {
+		transform, err := ParseTransform(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform: %w", err)
+		}
+		flow.Transform = transform
+	}
+
+	if expr, ok := raw.Frontmatter["steps"]; ok {
+		steps, err := ParseSteps(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid steps: %w", err)
+		}
+		if strings.TrimSpace(raw.Script) != "" {
+			return nil, fmt.Errorf("flow declares steps and also has a script: use one or the other")
+		}
+		flow.Steps = steps
+	}
+
 	return flow, nil
 }
 
@@ -3,6 +3,7 @@ package flows
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -251,6 +252,28 @@ echo "not a flow"
 	}
 }
 
+func TestDiscoverOne_InvalidTransform(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `#!/usr/bin/env bash
+# ayo:flow
+# name: bad-transform
+# description: Transform is not a pointer
+# transform: result.items
+
+echo '{}'
+`
+	path := filepath.Join(tmpDir, "bad-transform.sh")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := DiscoverOne(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid transform") {
+		t.Errorf("DiscoverOne() error = %v, want invalid transform", err)
+	}
+}
+
 func TestSourceFromPath(t *testing.T) {
 	tests := []struct {
 		path string
@@ -0,0 +1,205 @@
+package flows
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EachOptions configures RunEach.
+type EachOptions struct {
+	Concurrency int  // Elements run at once; below 1 means 1
+	FailFast    bool // Start no more elements after one fails
+}
+
+// EachResult is the outcome of running a flow once per array element.
+type EachResult struct {
+	RunID   string       // Batch run recorded in history; empty without history
+	Results []*RunResult // One per element in input order; nil if not run
+	Output  string       // JSON array of element outputs, null where an element failed or didn't run
+	Failed  int          // Elements that ran and failed
+	NotRun  int          // Elements skipped after a failure with FailFast
+}
+
+// OK reports whether every element ran and succeeded.
+func (r *EachResult) OK() bool {
+	return r.Failed == 0 && r.NotRun == 0
+}
+
+// RunEach runs flow once per element of the JSON array input, with up to
+// each.Concurrency elements at a time. Every element is a run of its own,
+// linked in history to a batch run for the whole array. Stderr lines of an
+// element are prefixed with its index. An input that isn't a JSON array is
+// an error.
+func RunEach(ctx context.Context, flow *Flow, opts RunOptions, each EachOptions, stderrWriter io.Writer) (*EachResult, error) {
+	input, err := resolveInput(opts)
+	if err != nil {
+		return nil, fmt.Errorf("resolve input: %w", err)
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(input), &elements); err != nil {
+		return nil, errors.New("input must be a JSON array to run the flow once per element")
+	}
+
+	res := &EachResult{Results: make([]*RunResult, len(elements))}
+
+	// The batch run is the parent of the element runs
+	parentRunID := opts.ParentRunID
+	start := time.Now()
+	if opts.History != nil && !opts.Validate {
+		id, err := opts.History.RecordStart(ctx, flow, input, false, opts.ParentRunID, opts.SessionID, opts.InputSchemaOverride)
+		if err == nil {
+			res.RunID = id
+			parentRunID = id
+		}
+	}
+
+	concurrency := max(each.Concurrency, 1)
+	var mu sync.Mutex // Guards stopped and the shared stderr writer
+	stopped := false
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, max(len(elements), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				mu.Lock()
+				skip := stopped
+				mu.Unlock()
+				if skip {
+					continue
+				}
+
+				elemOpts := opts
+				elemOpts.Input = string(elements[i])
+				elemOpts.InputFile = ""
+				elemOpts.ParentRunID = parentRunID
+				elemOpts.AutoPrune = false
+				var w io.Writer
+				var pw *prefixWriter
+				if stderrWriter != nil {
+					pw = &prefixWriter{prefix: fmt.Sprintf("[%d] ", i), out: stderrWriter, mu: &mu}
+					w = pw
+				}
+				result, err := RunStreaming(ctx, flow, elemOpts, w)
+				if err != nil {
+					result = &RunResult{Flow: flow, Status: RunStatusError, Error: err}
+				}
+				if pw != nil {
+					pw.Flush()
+				}
+
+				mu.Lock()
+				res.Results[i] = result
+				if result.Status != RunStatusSuccess && each.FailFast {
+					stopped = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range elements {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	outputs := make([]json.RawMessage, len(elements))
+	for i, result := range res.Results {
+		switch {
+		case result == nil:
+			res.NotRun++
+		case result.Status != RunStatusSuccess:
+			res.Failed++
+		default:
+			outputs[i] = elementOutput(result.Stdout)
+		}
+	}
+	if !opts.Validate {
+		data, err := json.Marshal(outputs)
+		if err != nil {
+			return nil, err
+		}
+		res.Output = string(data) + "\n"
+	}
+
+	if res.RunID != "" {
+		complete := CompleteResult{Status: RunStatusSuccess, OutputJSON: res.Output}
+		if !res.OK() {
+			complete.Status = RunStatusFailed
+			complete.ExitCode = 1
+			complete.ErrorMessage = res.Summary()
+		}
+		_, _ = opts.History.RecordComplete(ctx, res.RunID, complete, start)
+		if opts.AutoPrune && (opts.RetentionDays > 0 || opts.MaxRuns > 0) {
+			_ = opts.History.Prune(ctx, opts.RetentionDays, opts.MaxRuns)
+		}
+	}
+
+	return res, nil
+}
+
+// Summary describes how many elements failed or didn't run.
+func (r *EachResult) Summary() string {
+	msg := fmt.Sprintf("%d of %d elements failed", r.Failed, len(r.Results))
+	if r.NotRun > 0 {
+		msg += fmt.Sprintf(", %d not run", r.NotRun)
+	}
+	return msg
+}
+
+// elementOutput returns an element's stdout as JSON, quoting it as a string
+// when it isn't valid JSON.
+func elementOutput(stdout string) json.RawMessage {
+	trimmed := bytes.TrimSpace([]byte(stdout))
+	if len(trimmed) == 0 {
+		return nil
+	}
+	if json.Valid(trimmed) {
+		return trimmed
+	}
+	quoted, _ := json.Marshal(string(trimmed))
+	return quoted
+}
+
+// prefixWriter writes complete lines to out with a prefix, holding back a
+// partial line until it is finished or flushed. Writers sharing out share mu.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	mu     *sync.Mutex
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a pending partial line.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+}
@@ -0,0 +1,124 @@
+package flows
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeEachFlow writes a flow that echoes its input, or fails when the
+// input contains "bad".
+func writeEachFlow(t *testing.T) *Flow {
+	t.Helper()
+	content := `#!/usr/bin/env bash
+# ayo:flow
+# name: each-flow
+# description: Echo input unless it is bad
+
+INPUT="${1:-$(cat)}"
+echo "processing $INPUT" >&2
+if [[ "$INPUT" == *bad* ]]; then
+  exit 3
+fi
+echo "$INPUT"
+`
+	path := filepath.Join(t.TempDir(), "each-flow.sh")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	flow, err := DiscoverOne(path)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+	return flow
+}
+
+func TestRunEach(t *testing.T) {
+	flow := writeEachFlow(t)
+	_, queries, cleanup := setupTestDB(t)
+	defer cleanup()
+	history := NewHistoryService(queries)
+
+	var stderr bytes.Buffer
+	res, err := RunEach(context.Background(), flow, RunOptions{
+		Input:   `[{"n": 1}, "bad", {"n": 3}, [4]]`,
+		History: history,
+	}, EachOptions{Concurrency: 3}, &stderr)
+	if err != nil {
+		t.Fatalf("RunEach: %v", err)
+	}
+
+	if want := `[{"n":1},null,{"n":3},[4]]` + "\n"; res.Output != want {
+		t.Errorf("Output = %q, want %q", res.Output, want)
+	}
+	if res.Failed != 1 || res.NotRun != 0 || res.OK() {
+		t.Errorf("Failed = %d, NotRun = %d, OK = %v; want one failure", res.Failed, res.NotRun, res.OK())
+	}
+	if res.Results[1].ExitCode != 3 {
+		t.Errorf("failed element exit code = %d, want 3", res.Results[1].ExitCode)
+	}
+	if !strings.Contains(stderr.String(), `[2] processing {"n": 3}`) {
+		t.Errorf("stderr lines should be prefixed with the element index:\n%s", stderr.String())
+	}
+
+	parent, err := history.GetRun(context.Background(), res.RunID)
+	if err != nil {
+		t.Fatalf("GetRun(batch): %v", err)
+	}
+	if parent.Status != RunStatusFailed || parent.ErrorMessage != "1 of 4 elements failed" {
+		t.Errorf("batch run = %s %q", parent.Status, parent.ErrorMessage)
+	}
+	for i, r := range res.Results {
+		child, err := history.GetRun(context.Background(), r.RunID)
+		if err != nil {
+			t.Fatalf("GetRun(element %d): %v", i, err)
+		}
+		if child.ParentRunID != res.RunID {
+			t.Errorf("element %d ParentRunID = %q, want the batch run %q", i, child.ParentRunID, res.RunID)
+		}
+	}
+}
+
+func TestRunEachFailFast(t *testing.T) {
+	flow := writeEachFlow(t)
+
+	res, err := RunEach(context.Background(), flow, RunOptions{
+		Input: `["ok", "bad", "ok", "ok"]`,
+	}, EachOptions{Concurrency: 1, FailFast: true}, nil)
+	if err != nil {
+		t.Fatalf("RunEach: %v", err)
+	}
+	if res.Failed != 1 || res.NotRun != 2 {
+		t.Errorf("Failed = %d, NotRun = %d; want 1 and 2", res.Failed, res.NotRun)
+	}
+	if got := res.Summary(); got != "1 of 4 elements failed, 2 not run" {
+		t.Errorf("Summary = %q", got)
+	}
+	if want := `["ok",null,null,null]` + "\n"; res.Output != want {
+		t.Errorf("Output = %q, want %q", res.Output, want)
+	}
+}
+
+func TestRunEachRequiresArray(t *testing.T) {
+	flow := writeEachFlow(t)
+	_, err := RunEach(context.Background(), flow, RunOptions{Input: `{"n": 1}`}, EachOptions{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "JSON array") {
+		t.Errorf("RunEach(object) error = %v, want a JSON array error", err)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := &prefixWriter{prefix: "[0] ", out: &out, mu: &mu}
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthree"))
+	w.Flush()
+	if want := "[0] one\n[0] two\n[0] three\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -34,6 +34,10 @@ type RunOptions struct {
 	Validate   bool              // Validate only, don't run
 	Env        map[string]string // Additional environment variables
 
+	// InputSchemaOverride is a schema file validated in place of the flow's
+	// input schema. It is a development aid and bypasses the flow's contract.
+	InputSchemaOverride string
+
 	// History recording options
 	History       *HistoryService // If set, records run history
 	ParentRunID   string          // Parent run ID if this is a nested flow
@@ -41,6 +45,17 @@ type RunOptions struct {
 	AutoPrune     bool            // If true, prunes old runs after completion
 	RetentionDays int             // Max age in days for pruning
 	MaxRuns       int64           // Max runs to keep for pruning
+
+	// Webhook, if set, receives the result when the run completes
+	Webhook *Webhook
+
+	// Agents runs the agent calls of flows with steps. If nil, each step
+	// runs as an "ayo @handle" subprocess.
+	Agents AgentRunner
+
+	// Profile times each agent call: every step of a flow with steps, or
+	// every "ayo @handle" invocation of a script
+	Profile bool
 }
 
 // RunResult contains the outcome of a flow execution.
@@ -56,6 +71,9 @@ type RunResult struct {
 	Duration  time.Duration
 	InputUsed string // Actual input JSON
 	Error     error
+	Stages    []Stage // Agent call timings, when run with Profile
+
+	WebhookError error // Set when the result could not be delivered to the webhook
 }
 
 // Run executes a flow and returns the result.
@@ -74,18 +92,20 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		recordHistoryIfEnabled(ctx, opts, flow, result, false)
+		sendWebhookIfEnabled(ctx, opts, result)
 		return result, nil
 	}
 	result.InputUsed = input
 
 	// Validate input against schema
-	inputValidated := flow.HasInputSchema()
-	if err := ValidateInput(flow, input); err != nil {
+	inputValidated, err := validateRunInput(flow, opts, input)
+	if err != nil {
 		result.Status = RunStatusValidationFailed
 		result.Error = err
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+		sendWebhookIfEnabled(ctx, opts, result)
 		return result, nil
 	}
 
@@ -99,7 +119,7 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 
 	// Record start in history
 	if opts.History != nil {
-		runID, err := opts.History.RecordStart(ctx, flow, input, inputValidated, opts.ParentRunID, opts.SessionID)
+		runID, err := opts.History.RecordStart(ctx, flow, input, inputValidated, opts.ParentRunID, opts.SessionID, opts.InputSchemaOverride)
 		if err == nil {
 			result.RunID = runID
 		}
@@ -114,6 +134,16 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// Flows with steps call agents directly instead of running the script
+	if len(flow.Steps) > 0 {
+		runSteps(ctx, flow, input, opts, nil, result)
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+		sendWebhookIfEnabled(ctx, opts, result)
+		return result, nil
+	}
+
 	// Prepare command
 	cmd := exec.CommandContext(ctx, "bash", flow.Path, input)
 
@@ -127,6 +157,21 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 	// Set environment
 	cmd.Env = buildEnv(flow, result.RunID, input, opts.Env)
 
+	// Time the script's agent calls through a wrapper around ayo
+	var profile *scriptProfile
+	if opts.Profile {
+		if profile, err = profileScript(cmd); err != nil {
+			result.Status = RunStatusError
+			result.Error = err
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(result.StartTime)
+			recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+			sendWebhookIfEnabled(ctx, opts, result)
+			return result, nil
+		}
+		defer profile.close()
+	}
+
 	// Capture output
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -136,6 +181,9 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 	err = cmd.Run()
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
+	if profile != nil {
+		result.Stages = profile.stages(result.StartTime)
+	}
 	result.Stdout = stdout.String()
 	result.Stderr = stderr.String()
 
@@ -155,10 +203,12 @@ func Run(ctx context.Context, flow *Flow, opts RunOptions) (*RunResult, error) {
 	} else {
 		result.Status = RunStatusSuccess
 		result.ExitCode = 0
+		applyTransform(flow, result)
 	}
 
 	// Record completion in history
 	recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+	sendWebhookIfEnabled(ctx, opts, result)
 
 	return result, nil
 }
@@ -179,18 +229,20 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		recordHistoryIfEnabled(ctx, opts, flow, result, false)
+		sendWebhookIfEnabled(ctx, opts, result)
 		return result, nil
 	}
 	result.InputUsed = input
 
 	// Validate input against schema
-	inputValidated := flow.HasInputSchema()
-	if err := ValidateInput(flow, input); err != nil {
+	inputValidated, err := validateRunInput(flow, opts, input)
+	if err != nil {
 		result.Status = RunStatusValidationFailed
 		result.Error = err
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+		sendWebhookIfEnabled(ctx, opts, result)
 		return result, nil
 	}
 
@@ -204,7 +256,7 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 
 	// Record start in history
 	if opts.History != nil {
-		runID, err := opts.History.RecordStart(ctx, flow, input, inputValidated, opts.ParentRunID, opts.SessionID)
+		runID, err := opts.History.RecordStart(ctx, flow, input, inputValidated, opts.ParentRunID, opts.SessionID, opts.InputSchemaOverride)
 		if err == nil {
 			result.RunID = runID
 		}
@@ -219,6 +271,16 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// Flows with steps call agents directly instead of running the script
+	if len(flow.Steps) > 0 {
+		runSteps(ctx, flow, input, opts, stderrWriter, result)
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+		sendWebhookIfEnabled(ctx, opts, result)
+		return result, nil
+	}
+
 	// Prepare command
 	cmd := exec.CommandContext(ctx, "bash", flow.Path, input)
 
@@ -232,6 +294,21 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 	// Set environment
 	cmd.Env = buildEnv(flow, result.RunID, input, opts.Env)
 
+	// Time the script's agent calls through a wrapper around ayo
+	var profile *scriptProfile
+	if opts.Profile {
+		if profile, err = profileScript(cmd); err != nil {
+			result.Status = RunStatusError
+			result.Error = err
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(result.StartTime)
+			recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+			sendWebhookIfEnabled(ctx, opts, result)
+			return result, nil
+		}
+		defer profile.close()
+	}
+
 	// Capture stdout, stream stderr
 	var stdout bytes.Buffer
 	var stderrBuf bytes.Buffer
@@ -248,6 +325,9 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 	err = cmd.Run()
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
+	if profile != nil {
+		result.Stages = profile.stages(result.StartTime)
+	}
 	result.Stdout = stdout.String()
 	result.Stderr = stderrBuf.String()
 
@@ -267,14 +347,41 @@ func RunStreaming(ctx context.Context, flow *Flow, opts RunOptions, stderrWriter
 	} else {
 		result.Status = RunStatusSuccess
 		result.ExitCode = 0
+		applyTransform(flow, result)
 	}
 
 	// Record completion in history
 	recordHistoryIfEnabled(ctx, opts, flow, result, inputValidated)
+	sendWebhookIfEnabled(ctx, opts, result)
 
 	return result, nil
 }
 
+// applyTransform reshapes the stdout of a successful run with the flow's
+// transform. A failing transform fails the run and leaves stdout untouched.
+func applyTransform(flow *Flow, result *RunResult) {
+	if flow.Transform == nil {
+		return
+	}
+	transformed, err := flow.Transform.Apply(result.Stdout)
+	if err != nil {
+		result.Status = RunStatusError
+		result.Error = fmt.Errorf("transform output: %w", err)
+		return
+	}
+	result.Stdout = transformed
+}
+
+// validateRunInput validates input against the schema override when set,
+// otherwise against the flow's input schema. It reports whether a schema
+// was checked.
+func validateRunInput(flow *Flow, opts RunOptions, input string) (bool, error) {
+	if opts.InputSchemaOverride != "" {
+		return true, ValidateInputOverride(opts.InputSchemaOverride, input)
+	}
+	return flow.HasInputSchema(), ValidateInput(flow, input)
+}
+
 // resolveInput determines the input JSON from options.
 func resolveInput(opts RunOptions) (string, error) {
 	// 1. Explicit input argument
@@ -366,6 +473,7 @@ func recordHistoryIfEnabled(ctx context.Context, opts RunOptions, flow *Flow, re
 		OutputJSON:      result.Stdout,
 		StderrLog:       result.Stderr,
 		OutputValidated: flow.HasOutputSchema() && result.Status == RunStatusSuccess,
+		Stages:          result.Stages,
 	}, result.StartTime)
 
 	// Auto-prune if enabled
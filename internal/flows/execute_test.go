@@ -210,6 +210,69 @@ echo "This should not appear"
 	}
 }
 
+func TestRunStreaming_InputSchemaOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	flowContent := `#!/usr/bin/env bash
+# ayo:flow
+# name: override-flow
+# description: Echo input
+
+cat
+`
+	flowPath := filepath.Join(tmpDir, "override-flow.sh")
+	if err := os.WriteFile(flowPath, []byte(flowContent), 0755); err != nil {
+		t.Fatal(err)
+	}
+	strict := filepath.Join(tmpDir, "input.jsonschema")
+	if err := os.WriteFile(strict, []byte(`{"type": "object", "required": ["name"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loose := filepath.Join(tmpDir, "loose.jsonschema")
+	if err := os.WriteFile(loose, []byte(`{"type": "object"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	flow, err := DiscoverOne(flowPath)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+	flow.InputSchemaPath = strict
+
+	_, queries, cleanup := setupTestDB(t)
+	defer cleanup()
+	history := NewHistoryService(queries)
+
+	var stderr strings.Builder
+	result, err := RunStreaming(context.Background(), flow, RunOptions{Input: `{"draft": true}`}, &stderr)
+	if err != nil {
+		t.Fatalf("RunStreaming: %v", err)
+	}
+	if result.Status != RunStatusValidationFailed {
+		t.Fatalf("Status = %v without override, want %v", result.Status, RunStatusValidationFailed)
+	}
+
+	result, err = RunStreaming(context.Background(), flow, RunOptions{
+		Input:               `{"draft": true}`,
+		InputSchemaOverride: loose,
+		History:             history,
+	}, &stderr)
+	if err != nil {
+		t.Fatalf("RunStreaming: %v", err)
+	}
+	if result.Status != RunStatusSuccess {
+		t.Fatalf("Status = %v with override, want %v (error: %v)", result.Status, RunStatusSuccess, result.Error)
+	}
+
+	run, err := history.GetRun(context.Background(), result.RunID)
+	if err != nil {
+		t.Fatalf("GetRun: %v", err)
+	}
+	if run.InputSchemaOverride != loose || !run.InputValidated {
+		t.Errorf("history = override %q, validated %v; want %q, true", run.InputSchemaOverride, run.InputValidated, loose)
+	}
+}
+
 func TestRun_InputFile(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -288,6 +351,78 @@ echo "GOT: $INPUT"
 	}
 }
 
+func TestRun_Transform(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	flowContent := `#!/usr/bin/env bash
+# ayo:flow
+# name: transform-flow
+# description: Reshape output
+# transform: {"summary": "/result/summary", "count": "/result/count"}
+
+echo '{"result": {"summary": "done", "count": 3, "debug": "noise"}}'
+`
+	flowPath := filepath.Join(tmpDir, "transform-flow.sh")
+	if err := os.WriteFile(flowPath, []byte(flowContent), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	flow, err := DiscoverOne(flowPath)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+	if flow.Transform == nil {
+		t.Fatal("expected transform to be parsed")
+	}
+
+	result, err := Run(context.Background(), flow, RunOptions{Input: "{}"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != RunStatusSuccess {
+		t.Fatalf("Status = %v, want %v (error: %v)", result.Status, RunStatusSuccess, result.Error)
+	}
+	if got := compactJSON(t, result.Stdout); got != `{"count":3,"summary":"done"}` {
+		t.Errorf("Stdout = %s, want transformed output", got)
+	}
+}
+
+func TestRun_TransformError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	flowContent := `#!/usr/bin/env bash
+# ayo:flow
+# name: transform-error
+# description: Transform points at a missing field
+# transform: /result/missing
+
+echo '{"result": {}}'
+`
+	flowPath := filepath.Join(tmpDir, "transform-error.sh")
+	if err := os.WriteFile(flowPath, []byte(flowContent), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	flow, err := DiscoverOne(flowPath)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+
+	result, err := RunStreaming(context.Background(), flow, RunOptions{Input: "{}"}, nil)
+	if err != nil {
+		t.Fatalf("RunStreaming: %v", err)
+	}
+	if result.Status != RunStatusError {
+		t.Errorf("Status = %v, want %v", result.Status, RunStatusError)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), `transform output: /result/missing: key "missing" not found`) {
+		t.Errorf("Error = %v, want transform error", result.Error)
+	}
+	if !strings.Contains(result.Stdout, `"result"`) {
+		t.Errorf("Stdout = %q, want untransformed output", result.Stdout)
+	}
+}
+
 func TestResolveInput(t *testing.T) {
 	tmpDir := t.TempDir()
 
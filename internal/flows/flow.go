@@ -34,6 +34,12 @@ type Flow struct {
 	InputSchemaPath  string // Path to input.jsonschema
 	OutputSchemaPath string // Path to output.jsonschema
 
+	// Optional output transform from the transform frontmatter field (nil if not present)
+	Transform *Transform
+
+	// Agent calls from the steps frontmatter field, run instead of the script
+	Steps []Step
+
 	// Metadata
 	Metadata FlowMetadata
 
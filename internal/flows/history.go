@@ -3,6 +3,7 @@ package flows
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -30,6 +31,13 @@ type FlowRun struct {
 	SessionID       string
 	InputValidated  bool
 	OutputValidated bool
+
+	// InputSchemaOverride is the schema the input was validated against in
+	// place of the flow's own, when the run used --input-schema-override.
+	InputSchemaOverride string
+
+	// Stages are the agent call timings of a run started with --profile.
+	Stages []Stage `json:",omitempty"`
 }
 
 // RunFilter contains optional filters for listing runs.
@@ -51,19 +59,21 @@ func NewHistoryService(queries *db.Queries) *HistoryService {
 }
 
 // RecordStart creates a new running flow record and returns its ID.
-func (h *HistoryService) RecordStart(ctx context.Context, flow *Flow, input string, inputValidated bool, parentRunID, sessionID string) (string, error) {
+// schemaOverride is the input schema used in place of the flow's, if any.
+func (h *HistoryService) RecordStart(ctx context.Context, flow *Flow, input string, inputValidated bool, parentRunID, sessionID, schemaOverride string) (string, error) {
 	id := ulid.Make().String()
 
 	params := db.CreateFlowRunParams{
-		ID:             id,
-		FlowName:       flow.Name,
-		FlowPath:       flow.Path,
-		FlowSource:     string(flow.Source),
-		InputJson:      toNullString(input),
-		InputValidated: boolToInt64(inputValidated),
-		StartedAt:      time.Now().UnixMilli(),
-		ParentRunID:    toNullString(parentRunID),
-		SessionID:      toNullString(sessionID),
+		ID:                  id,
+		FlowName:            flow.Name,
+		FlowPath:            flow.Path,
+		FlowSource:          string(flow.Source),
+		InputJson:           toNullString(input),
+		InputValidated:      boolToInt64(inputValidated),
+		StartedAt:           time.Now().UnixMilli(),
+		ParentRunID:         toNullString(parentRunID),
+		SessionID:           toNullString(sessionID),
+		InputSchemaOverride: toNullString(schemaOverride),
 	}
 
 	_, err := h.queries.CreateFlowRun(ctx, params)
@@ -82,6 +92,7 @@ type CompleteResult struct {
 	OutputJSON      string
 	StderrLog       string
 	OutputValidated bool
+	Stages          []Stage // Recorded when not nil
 }
 
 // RecordComplete updates a flow run with completion data.
@@ -100,6 +111,11 @@ func (h *HistoryService) RecordComplete(ctx context.Context, runID string, resul
 		FinishedAt:      sql.NullInt64{Int64: now.UnixMilli(), Valid: true},
 		DurationMs:      sql.NullInt64{Int64: durationMs, Valid: true},
 	}
+	if result.Stages != nil {
+		if data, err := json.Marshal(result.Stages); err == nil {
+			params.StagesJson = toNullString(string(data))
+		}
+	}
 
 	dbRun, err := h.queries.CompleteFlowRun(ctx, params)
 	if err != nil {
@@ -254,20 +270,21 @@ func int64ToBool(i int64) bool {
 
 func dbFlowRunToFlowRun(dbRun db.FlowRun) *FlowRun {
 	run := &FlowRun{
-		ID:              dbRun.ID,
-		FlowName:        dbRun.FlowName,
-		FlowPath:        dbRun.FlowPath,
-		FlowSource:      FlowSource(dbRun.FlowSource),
-		Status:          RunStatus(dbRun.Status),
-		ErrorMessage:    dbRun.ErrorMessage.String,
-		InputJSON:       dbRun.InputJson.String,
-		OutputJSON:      dbRun.OutputJson.String,
-		StderrLog:       dbRun.StderrLog.String,
-		StartedAt:       time.UnixMilli(dbRun.StartedAt),
-		ParentRunID:     dbRun.ParentRunID.String,
-		SessionID:       dbRun.SessionID.String,
-		InputValidated:  int64ToBool(dbRun.InputValidated),
-		OutputValidated: int64ToBool(dbRun.OutputValidated),
+		ID:                  dbRun.ID,
+		FlowName:            dbRun.FlowName,
+		FlowPath:            dbRun.FlowPath,
+		FlowSource:          FlowSource(dbRun.FlowSource),
+		Status:              RunStatus(dbRun.Status),
+		ErrorMessage:        dbRun.ErrorMessage.String,
+		InputJSON:           dbRun.InputJson.String,
+		OutputJSON:          dbRun.OutputJson.String,
+		StderrLog:           dbRun.StderrLog.String,
+		StartedAt:           time.UnixMilli(dbRun.StartedAt),
+		ParentRunID:         dbRun.ParentRunID.String,
+		SessionID:           dbRun.SessionID.String,
+		InputValidated:      int64ToBool(dbRun.InputValidated),
+		OutputValidated:     int64ToBool(dbRun.OutputValidated),
+		InputSchemaOverride: dbRun.InputSchemaOverride.String,
 	}
 
 	if dbRun.ExitCode.Valid {
@@ -284,5 +301,9 @@ func dbFlowRunToFlowRun(dbRun db.FlowRun) *FlowRun {
 		run.DurationMs = dbRun.DurationMs.Int64
 	}
 
+	if dbRun.StagesJson.Valid {
+		_ = json.Unmarshal([]byte(dbRun.StagesJson.String), &run.Stages)
+	}
+
 	return run
 }
@@ -46,7 +46,7 @@ func TestHistoryService_RecordAndComplete(t *testing.T) {
 	}
 
 	// Record start
-	runID, err := svc.RecordStart(ctx, flow, `{"input": "test"}`, true, "", "")
+	runID, err := svc.RecordStart(ctx, flow, `{"input": "test"}`, true, "", "", "")
 	if err != nil {
 		t.Fatalf("RecordStart: %v", err)
 	}
@@ -82,6 +82,7 @@ func TestHistoryService_RecordAndComplete(t *testing.T) {
 		OutputJSON:      `{"result": "done"}`,
 		StderrLog:       "some logs",
 		OutputValidated: true,
+		Stages:          []Stage{{Name: "plan", Agent: "@planner", Duration: 2 * time.Second}},
 	}
 
 	completedRun, err := svc.RecordComplete(ctx, runID, result, startedAt)
@@ -104,6 +105,10 @@ func TestHistoryService_RecordAndComplete(t *testing.T) {
 	if completedRun.DurationMs <= 0 {
 		t.Error("DurationMs should be positive")
 	}
+
+	if len(completedRun.Stages) != 1 || completedRun.Stages[0] != result.Stages[0] {
+		t.Errorf("Stages = %+v, want %+v", completedRun.Stages, result.Stages)
+	}
 }
 
 func TestHistoryService_ListRuns(t *testing.T) {
@@ -119,7 +124,7 @@ func TestHistoryService_ListRuns(t *testing.T) {
 
 	// Create runs
 	for i := 0; i < 3; i++ {
-		runID, _ := svc.RecordStart(ctx, flow1, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow1, "{}", false, "", "", "")
 		status := RunStatusSuccess
 		if i == 1 {
 			status = RunStatusFailed
@@ -128,7 +133,7 @@ func TestHistoryService_ListRuns(t *testing.T) {
 	}
 
 	for i := 0; i < 2; i++ {
-		runID, _ := svc.RecordStart(ctx, flow2, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow2, "{}", false, "", "", "")
 		svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 	}
 
@@ -181,7 +186,7 @@ func TestHistoryService_GetLastRun(t *testing.T) {
 	// Create runs with small delays
 	var lastRunID string
 	for i := 0; i < 3; i++ {
-		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 		svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 		lastRunID = runID
 		time.Sleep(10 * time.Millisecond) // Ensure different timestamps
@@ -206,7 +211,7 @@ func TestHistoryService_GetRunByPrefix(t *testing.T) {
 	svc := NewHistoryService(queries)
 
 	flow := &Flow{Name: "prefix-flow", Path: "/path/flow.sh", Dir: "/path", Source: FlowSourceUser}
-	runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+	runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 	svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 
 	// Get by exact ID
@@ -237,7 +242,7 @@ func TestHistoryService_DeleteRun(t *testing.T) {
 	svc := NewHistoryService(queries)
 
 	flow := &Flow{Name: "delete-flow", Path: "/path/flow.sh", Dir: "/path", Source: FlowSourceUser}
-	runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+	runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 	svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 
 	// Verify it exists
@@ -269,7 +274,7 @@ func TestHistoryService_PruneByAge(t *testing.T) {
 
 	// Create runs
 	for i := 0; i < 5; i++ {
-		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 		svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 	}
 
@@ -295,7 +300,7 @@ func TestHistoryService_PruneByCount(t *testing.T) {
 
 	// Create runs
 	for i := 0; i < 10; i++ {
-		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 		svc.RecordComplete(ctx, runID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 	}
 
@@ -321,7 +326,7 @@ func TestHistoryService_CountRuns(t *testing.T) {
 
 	// Create runs
 	for i := 0; i < 5; i++ {
-		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "")
+		runID, _ := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 		status := RunStatusSuccess
 		if i%2 == 0 {
 			status = RunStatusFailed
@@ -367,14 +372,14 @@ func TestHistoryService_ParentAndSession(t *testing.T) {
 	flow := &Flow{Name: "linked-flow", Path: "/path/flow.sh", Dir: "/path", Source: FlowSourceUser}
 
 	// Create parent run
-	parentID, err := svc.RecordStart(ctx, flow, "{}", false, "", "")
+	parentID, err := svc.RecordStart(ctx, flow, "{}", false, "", "", "")
 	if err != nil {
 		t.Fatalf("RecordStart parent: %v", err)
 	}
 	svc.RecordComplete(ctx, parentID, CompleteResult{Status: RunStatusSuccess, ExitCode: 0}, time.Now())
 
 	// Create child run with parent ID (no session since it requires a real session in the DB)
-	childID, err := svc.RecordStart(ctx, flow, "{}", false, parentID, "")
+	childID, err := svc.RecordStart(ctx, flow, "{}", false, parentID, "", "")
 	if err != nil {
 		t.Fatalf("RecordStart child: %v", err)
 	}
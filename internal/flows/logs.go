@@ -0,0 +1,200 @@
+package flows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is the severity of a structured log line written by a flow.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the level's name.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// ParseLogLevel parses a level name. Common aliases such as "trace",
+// "warning", and "fatal" map to the nearest level.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace", "debug":
+		return LogDebug, nil
+	case "info", "notice":
+		return LogInfo, nil
+	case "warn", "warning":
+		return LogWarn, nil
+	case "error", "err", "fatal", "panic", "critical":
+		return LogError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q: must be debug, info, warn, or error", s)
+}
+
+// LogField is a key and value of a structured log line, other than its
+// level and message.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// LogLine is a structured log line written by a flow to stderr.
+type LogLine struct {
+	Level  LogLevel
+	Msg    string
+	Fields []LogField
+}
+
+// Keys recognized as the level and message of a log line.
+var (
+	logLevelKeys = []string{"level", "lvl", "severity"}
+	logMsgKeys   = []string{"msg", "message"}
+)
+
+// ParseLogLine recognizes a structured log line, either logfmt such as
+// `level=info msg="fetched page" url=...` or a JSON object with a level
+// field. It reports false for lines without a known level, which are plain
+// text. Logfmt fields keep their order; JSON fields are sorted by key.
+func ParseLogLine(line string) (LogLine, bool) {
+	line = strings.TrimSpace(line)
+	var fields []LogField
+	if strings.HasPrefix(line, "{") {
+		var ok bool
+		if fields, ok = parseJSONFields(line); !ok {
+			return LogLine{}, false
+		}
+	} else {
+		fields = parseLogfmt(line)
+	}
+
+	var result LogLine
+	hasLevel := false
+	for _, f := range fields {
+		switch {
+		case !hasLevel && containsKey(logLevelKeys, f.Key):
+			level, err := ParseLogLevel(f.Value)
+			if err != nil {
+				return LogLine{}, false
+			}
+			result.Level = level
+			hasLevel = true
+		case result.Msg == "" && containsKey(logMsgKeys, f.Key):
+			result.Msg = f.Value
+		default:
+			result.Fields = append(result.Fields, f)
+		}
+	}
+	if !hasLevel {
+		return LogLine{}, false
+	}
+	return result, true
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSONFields reads the members of a JSON object as fields. Values that
+// aren't strings keep their JSON form.
+func parseJSONFields(line string) ([]LogField, bool) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		return nil, false
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]LogField, 0, len(keys))
+	for _, k := range keys {
+		value := string(bytes.TrimSpace(obj[k]))
+		var s string
+		if err := json.Unmarshal(obj[k], &s); err == nil {
+			value = s
+		}
+		fields = append(fields, LogField{Key: k, Value: value})
+	}
+	return fields, true
+}
+
+// parseLogfmt splits a logfmt line into fields. Values may be quoted with
+// Go-style escapes; a key without "=" has an empty value. Text that doesn't
+// follow logfmt yields no level, so the line is treated as plain.
+func parseLogfmt(line string) []LogField {
+	var fields []LogField
+	for line != "" {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			break
+		}
+		end := strings.IndexAny(line, "= \t")
+		if end < 0 {
+			fields = append(fields, LogField{Key: line})
+			break
+		}
+		key := line[:end]
+		if line[end] != '=' {
+			fields = append(fields, LogField{Key: key})
+			line = line[end:]
+			continue
+		}
+		line = line[end+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			n := quotedLen(line)
+			unquoted, err := strconv.Unquote(line[:n])
+			if err != nil {
+				unquoted = strings.Trim(line[:n], `"`)
+			}
+			value, line = unquoted, line[n:]
+		} else {
+			n := strings.IndexAny(line, " \t")
+			if n < 0 {
+				n = len(line)
+			}
+			value, line = line[:n], line[n:]
+		}
+		fields = append(fields, LogField{Key: key, Value: value})
+	}
+	return fields
+}
+
+// quotedLen returns the length of the quoted string at the start of s,
+// including its quotes, or len(s) when it is unterminated.
+func quotedLen(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
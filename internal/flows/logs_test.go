@@ -0,0 +1,69 @@
+package flows
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want LogLine
+		ok   bool
+	}{
+		{
+			name: "logfmt",
+			line: `time=2026-01-02T03:04:05Z level=INFO msg="fetched page" url=https://example.com status=200`,
+			want: LogLine{Level: LogInfo, Msg: "fetched page", Fields: []LogField{
+				{"time", "2026-01-02T03:04:05Z"}, {"url", "https://example.com"}, {"status", "200"},
+			}},
+			ok: true,
+		},
+		{
+			name: "logfmt escapes",
+			line: `level=warning msg="said \"hi\"" retry`,
+			want: LogLine{Level: LogWarn, Msg: `said "hi"`, Fields: []LogField{{"retry", ""}}},
+			ok:   true,
+		},
+		{
+			name: "json",
+			line: `{"level":"error","msg":"boom","code":3,"ok":false}`,
+			want: LogLine{Level: LogError, Msg: "boom", Fields: []LogField{{"code", "3"}, {"ok", "false"}}},
+			ok:   true,
+		},
+		{
+			name: "json severity",
+			line: `{"severity":"debug","message":"tick"}`,
+			want: LogLine{Level: LogDebug, Msg: "tick"},
+			ok:   true,
+		},
+		{name: "plain text", line: "Fetching https://example.com...", ok: false},
+		{name: "unknown level", line: "level=loud msg=hi", ok: false},
+		{name: "json without level", line: `{"msg":"hi"}`, ok: false},
+		{name: "invalid json", line: `{"level":"info"`, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLogLine(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v (got %+v)", ok, tt.ok, got)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for in, want := range map[string]LogLevel{"debug": LogDebug, "INFO": LogInfo, "warning": LogWarn, "fatal": LogError} {
+		got, err := ParseLogLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLogLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,170 @@
+package flows
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stage is the timing of one agent call in a profiled run: a declared step,
+// or an "ayo @handle" invocation in a script flow.
+type Stage struct {
+	Name     string        `json:"name"` // Step id, or the agent handle in script flows
+	Agent    string        `json:"agent"`
+	Start    time.Duration `json:"start_ns"` // Since the run started
+	Duration time.Duration `json:"duration_ns"`
+	Failed   bool          `json:"failed,omitempty"`
+}
+
+// ProfileEnvVar names the file that ayo invocations in a profiled script
+// flow append their timing to.
+const ProfileEnvVar = "AYO_FLOW_PROFILE"
+
+// StageWrapperArg is the first argument of an ayo invocation made through
+// the timing wrapper of a profiled script flow. main hands such invocations
+// to RunStageWrapper.
+const StageWrapperArg = "__flow-stage"
+
+// stageRecord is a line of the profile file.
+type stageRecord struct {
+	Agent      string    `json:"agent"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	ExitCode   int       `json:"exit_code"`
+}
+
+// scriptProfile times the agent calls of a script flow. An "ayo" wrapper
+// first on the script's PATH runs each invocation through
+// RunStageWrapper, which appends its timing to a file.
+type scriptProfile struct {
+	dir string // Holds the wrapper and the profile file
+}
+
+// startScriptProfile writes the wrapper that sends ayo invocations through
+// exe.
+func startScriptProfile(exe string) (*scriptProfile, error) {
+	dir, err := os.MkdirTemp("", "ayo-flow-profile-*")
+	if err != nil {
+		return nil, fmt.Errorf("create profile dir: %w", err)
+	}
+	wrapper := fmt.Sprintf("#!/bin/sh\nexec %s %s \"$@\"\n", shellQuote(exe), StageWrapperArg)
+	if err := os.WriteFile(filepath.Join(dir, "ayo"), []byte(wrapper), 0o755); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("write profile wrapper: %w", err)
+	}
+	return &scriptProfile{dir: dir}, nil
+}
+
+// profileScript sends the agent calls of cmd, a script flow, through the
+// timing wrapper.
+func profileScript(cmd *exec.Cmd) (*scriptProfile, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("profile: find ayo executable: %w", err)
+	}
+	p, err := startScriptProfile(exe)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+	cmd.Env = p.env(cmd.Env)
+	return p, nil
+}
+
+// env returns env with the wrapper first on PATH and the profile file set.
+func (p *scriptProfile) env(env []string) []string {
+	path := p.dir
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, "PATH="); ok {
+			path = p.dir + string(os.PathListSeparator) + v
+		}
+	}
+	return append(env, "PATH="+path, ProfileEnvVar+"="+filepath.Join(p.dir, "stages.jsonl"))
+}
+
+// stages returns the recorded agent calls in the order they started.
+func (p *scriptProfile) stages(runStart time.Time) []Stage {
+	f, err := os.Open(filepath.Join(p.dir, "stages.jsonl"))
+	if err != nil {
+		return []Stage{}
+	}
+	defer f.Close()
+
+	stages := []Stage{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec stageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		stages = append(stages, Stage{
+			Name:     rec.Agent,
+			Agent:    rec.Agent,
+			Start:    rec.StartedAt.Sub(runStart),
+			Duration: rec.FinishedAt.Sub(rec.StartedAt),
+			Failed:   rec.ExitCode != 0,
+		})
+	}
+	sort.SliceStable(stages, func(i, j int) bool { return stages[i].Start < stages[j].Start })
+	return stages
+}
+
+// close removes the wrapper and the profile file.
+func (p *scriptProfile) close() {
+	os.RemoveAll(p.dir)
+}
+
+// RunStageWrapper runs exe with args as the wrapper of a profiled script
+// flow does, and returns its exit code. Agent invocations ("ayo @handle
+// ...") are timed and appended to the file named by ProfileEnvVar.
+func RunStageWrapper(exe string, args []string) int {
+	cmd := exec.Command(exe, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	rec := stageRecord{StartedAt: start, FinishedAt: time.Now()}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		rec.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "ayo: %v\n", err)
+		rec.ExitCode = 1
+	}
+
+	if path := os.Getenv(ProfileEnvVar); path != "" && len(args) > 0 && strings.HasPrefix(args[0], "@") {
+		rec.Agent = args[0]
+		appendStageRecord(path, rec)
+	}
+	return rec.ExitCode
+}
+
+// appendStageRecord appends rec to the profile file. Each record is one
+// small append, so concurrent agent calls don't interleave.
+func appendStageRecord(path string, rec stageRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,92 @@
+package flows
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_StepsProfile(t *testing.T) {
+	flow := writeStepsFlow(t, `# steps: [{"id": "plan", "agent": "@planner", "prompt": "go"}, {"id": "write", "agent": "@writer", "prompt": "{{plan}}"}]
+`)
+	agents := func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		time.Sleep(20 * time.Millisecond)
+		if handle == "@writer" {
+			return "", errors.New("model unavailable")
+		}
+		return "plan", nil
+	}
+
+	result, err := Run(context.Background(), flow, RunOptions{Agents: agents, Profile: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Stages) != 2 {
+		t.Fatalf("Stages = %+v, want 2", result.Stages)
+	}
+	plan, write := result.Stages[0], result.Stages[1]
+	if plan.Name != "plan" || plan.Agent != "@planner" || plan.Failed || plan.Duration < 20*time.Millisecond {
+		t.Errorf("plan stage = %+v", plan)
+	}
+	if write.Name != "write" || !write.Failed || write.Start < plan.Start+plan.Duration {
+		t.Errorf("write stage = %+v, want a failed stage after plan", write)
+	}
+
+	result, err = Run(context.Background(), flow, RunOptions{Agents: agents})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Stages != nil {
+		t.Errorf("Stages = %+v without Profile, want nil", result.Stages)
+	}
+}
+
+func TestScriptProfile(t *testing.T) {
+	p, err := startScriptProfile("/usr/local/bin/ayo")
+	if err != nil {
+		t.Fatalf("startScriptProfile: %v", err)
+	}
+	defer p.close()
+
+	wrapper, err := os.ReadFile(filepath.Join(p.dir, "ayo"))
+	if err != nil || !strings.Contains(string(wrapper), "exec '/usr/local/bin/ayo' "+StageWrapperArg+` "$@"`) {
+		t.Errorf("wrapper = %q, %v", wrapper, err)
+	}
+
+	env := p.env([]string{"HOME=/home/me", "PATH=/usr/bin"})
+	var profileFile string
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, ProfileEnvVar+"="); ok {
+			profileFile = v
+		}
+	}
+	if env[len(env)-2] != "PATH="+p.dir+string(os.PathListSeparator)+"/usr/bin" || profileFile == "" {
+		t.Fatalf("env = %q", env)
+	}
+
+	// A stand-in for the real ayo that fails after a moment
+	realAyo := filepath.Join(t.TempDir(), "ayo")
+	if err := os.WriteFile(realAyo, []byte("#!/bin/sh\nsleep 0.05\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ProfileEnvVar, profileFile)
+
+	start := time.Now()
+	if code := RunStageWrapper(realAyo, []string{"@reviewer", "look"}); code != 3 {
+		t.Errorf("RunStageWrapper exit code = %d, want the agent's 3", code)
+	}
+	RunStageWrapper(realAyo, []string{"flows", "list"})
+
+	stages := p.stages(start)
+	if len(stages) != 1 {
+		t.Fatalf("stages = %+v, want only the agent call", stages)
+	}
+	if s := stages[0]; s.Agent != "@reviewer" || !s.Failed || s.Duration < 50*time.Millisecond {
+		t.Errorf("stage = %+v", s)
+	}
+}
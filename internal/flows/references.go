@@ -0,0 +1,51 @@
+package flows
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// AgentReference is a line in a flow script that invokes an agent.
+type AgentReference struct {
+	Flow string
+	Path string
+	Line int    // 1-based line number in Path
+	Text string // The line, trimmed
+}
+
+// FindAgentReferences returns the lines of the given flows that invoke
+// handle as "ayo @handle" or declare a step with it as the agent. Longer
+// handles sharing the prefix, such as @handle-v2, are not matched.
+func FindAgentReferences(flowList []Flow, handle string) ([]AgentReference, error) {
+	bare := strings.TrimPrefix(handle, "@")
+	pattern := regexp.MustCompile(`\bayo\s+@` + regexp.QuoteMeta(bare) + `(?:$|[^\w.@-])` +
+		`|"agent"\s*:\s*"@?` + regexp.QuoteMeta(bare) + `"`)
+
+	var refs []AgentReference
+	for _, f := range flowList {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("open flow %s: %w", f.Name, err)
+		}
+		scanner := bufio.NewScanner(file)
+		for line := 1; scanner.Scan(); line++ {
+			if pattern.MatchString(scanner.Text()) {
+				refs = append(refs, AgentReference{
+					Flow: f.Name,
+					Path: f.Path,
+					Line: line,
+					Text: strings.TrimSpace(scanner.Text()),
+				})
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read flow %s: %w", f.Name, err)
+		}
+	}
+	return refs, nil
+}
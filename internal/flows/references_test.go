@@ -0,0 +1,68 @@
+package flows
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindAgentReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	script := `#!/usr/bin/env bash
+# ayo:flow
+# name: review
+# description: Review code
+
+REVIEW=$(ayo @reviewer "review this")
+echo "$REVIEW" | ayo @reviewer-v2 "again"
+echo "$REVIEW" | ayo   @reviewer
+ayo @other "ask @reviewer"
+`
+	path := filepath.Join(tmpDir, "review.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := FindAgentReferences([]Flow{{Name: "review", Path: path}}, "reviewer")
+	if err != nil {
+		t.Fatalf("FindAgentReferences: %v", err)
+	}
+
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 references, got %d: %+v", len(refs), refs)
+	}
+	if refs[0].Line != 6 || refs[0].Text != `REVIEW=$(ayo @reviewer "review this")` {
+		t.Errorf("first reference = %+v", refs[0])
+	}
+	if refs[1].Line != 8 || refs[1].Flow != "review" {
+		t.Errorf("second reference = %+v", refs[1])
+	}
+}
+
+func TestFindAgentReferencesSteps(t *testing.T) {
+	script := `#!/usr/bin/env bash
+# ayo:flow
+# name: steps
+# description: Steps
+# steps: [{"agent": "@reviewer-v2", "prompt": "a"}, {"agent":"reviewer", "prompt": "b"}]
+`
+	path := filepath.Join(t.TempDir(), "steps.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := FindAgentReferences([]Flow{{Name: "steps", Path: path}}, "@reviewer")
+	if err != nil {
+		t.Fatalf("FindAgentReferences: %v", err)
+	}
+	if len(refs) != 1 || refs[0].Line != 5 {
+		t.Errorf("references = %+v, want line 5", refs)
+	}
+}
+
+func TestFindAgentReferencesMissingFile(t *testing.T) {
+	_, err := FindAgentReferences([]Flow{{Name: "gone", Path: filepath.Join(t.TempDir(), "gone.sh")}}, "@x")
+	if err == nil {
+		t.Error("expected error for missing flow file")
+	}
+}
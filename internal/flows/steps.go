@@ -0,0 +1,231 @@
+package flows
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Step is an agent call declared with the steps frontmatter field. Steps
+// run in order, without a shell script:
+//
+//	# steps: [{"id": "facts", "agent": "@researcher", "prompt": "Find facts about {{input.topic}}"}, {"agent": "@writer", "prompt": "Write a summary of {{facts}}"}]
+//
+// Prompts can reference the flow input as {{input}} and the output of an
+// earlier step by its id. A dotted path selects a field from JSON:
+// {{input.topic}}, {{facts.items.0}}. The output of the last step is the
+// flow's output.
+type Step struct {
+	ID     string `json:"id,omitempty"` // Defaults to step1, step2, ...
+	Agent  string `json:"agent"`
+	Prompt string `json:"prompt"`
+}
+
+// AgentRunner runs an agent on a prompt and returns its response. Progress
+// and logs go to stderr.
+type AgentRunner func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error)
+
+// stepRefPattern matches {{name}} and {{name.path.to.field}} in step prompts.
+var stepRefPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w-]*)((?:\.[\w-]+)*)\s*\}\}`)
+
+// stepIDPattern restricts step ids to names that can be referenced.
+var stepIDPattern = regexp.MustCompile(`^[A-Za-z_][\w-]*$`)
+
+// ParseSteps parses a steps frontmatter value.
+func ParseSteps(expr string) ([]Step, error) {
+	var steps []Step
+	if err := json.Unmarshal([]byte(strings.TrimSpace(expr)), &steps); err != nil {
+		return nil, fmt.Errorf("must be a JSON array of steps: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps declared")
+	}
+
+	known := map[string]bool{"input": true}
+	for i := range steps {
+		s := &steps[i]
+		if s.ID == "" {
+			s.ID = fmt.Sprintf("step%d", i+1)
+		}
+		if !stepIDPattern.MatchString(s.ID) {
+			return nil, fmt.Errorf("step %d: invalid id %q: use letters, digits, - and _", i+1, s.ID)
+		}
+		if known[s.ID] {
+			return nil, fmt.Errorf("step %d: id %q is already used", i+1, s.ID)
+		}
+		s.Agent = strings.TrimSpace(s.Agent)
+		if s.Agent == "" {
+			return nil, fmt.Errorf("step %s: agent is required", s.ID)
+		}
+		if !strings.HasPrefix(s.Agent, "@") {
+			s.Agent = "@" + s.Agent
+		}
+		if strings.TrimSpace(s.Prompt) == "" {
+			return nil, fmt.Errorf("step %s: prompt is required", s.ID)
+		}
+		for _, m := range stepRefPattern.FindAllStringSubmatch(s.Prompt, -1) {
+			if !known[m[1]] {
+				return nil, fmt.Errorf("step %s: {{%s}} does not refer to the input or an earlier step", s.ID, m[1])
+			}
+		}
+		known[s.ID] = true
+	}
+	return steps, nil
+}
+
+// StepsString describes steps as a chain of agents, such as
+// "facts (@researcher) -> step2 (@writer)".
+func StepsString(steps []Step) string {
+	parts := make([]string, len(steps))
+	for i, s := range steps {
+		parts[i] = fmt.Sprintf("%s (%s)", s.ID, s.Agent)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// renderPrompt fills in the references in a step prompt. values holds the
+// input and earlier step outputs, decoded from JSON where possible.
+func (s Step) renderPrompt(values map[string]any) (string, error) {
+	var renderErr error
+	prompt := stepRefPattern.ReplaceAllStringFunc(s.Prompt, func(ref string) string {
+		m := stepRefPattern.FindStringSubmatch(ref)
+		value := values[m[1]]
+		if m[2] != "" {
+			for _, key := range strings.Split(strings.TrimPrefix(m[2], "."), ".") {
+				next, ok := stepField(value, key)
+				if !ok {
+					if renderErr == nil {
+						renderErr = fmt.Errorf("step %s: %s has no field %q", s.ID, strings.Trim(ref, "{} "), key)
+					}
+					return ref
+				}
+				value = next
+			}
+		}
+		if str, ok := value.(string); ok {
+			return str
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	})
+	return prompt, renderErr
+}
+
+// stepField returns a field of a decoded JSON object or an element of an
+// array.
+func stepField(value any, key string) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		field, ok := v[key]
+		return field, ok
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return v[i], true
+	}
+	return nil, false
+}
+
+// decodeStepValue decodes JSON text, falling back to the trimmed text.
+func decodeStepValue(text string) any {
+	text = strings.TrimSpace(text)
+	var value any
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		return value
+	}
+	return text
+}
+
+// runSteps runs a flow's steps and records the outcome in result. The last
+// step's output becomes stdout: as is when it is JSON, otherwise as a JSON
+// string.
+func runSteps(ctx context.Context, flow *Flow, input string, opts RunOptions, stderrWriter io.Writer, result *RunResult) {
+	var stderr bytes.Buffer
+	var w io.Writer = &stderr
+	if stderrWriter != nil {
+		w = io.MultiWriter(&stderr, stderrWriter)
+	}
+	defer func() { result.Stderr = stderr.String() }()
+
+	agents := opts.Agents
+	if agents == nil {
+		dir := flow.Dir
+		if opts.WorkingDir != "" {
+			dir = opts.WorkingDir
+		}
+		agents = subprocessAgentRunner(dir, buildEnv(flow, result.RunID, input, opts.Env))
+	}
+
+	values := map[string]any{"input": decodeStepValue(input)}
+	var output string
+	for _, step := range flow.Steps {
+		prompt, err := step.renderPrompt(values)
+		if err != nil {
+			result.Status = RunStatusError
+			result.Error = err
+			return
+		}
+
+		fmt.Fprintf(w, "step %s: %s\n", step.ID, step.Agent)
+		start := time.Now()
+		output, err = agents(ctx, step.Agent, prompt, w)
+		if opts.Profile {
+			result.Stages = append(result.Stages, Stage{
+				Name:     step.ID,
+				Agent:    step.Agent,
+				Start:    start.Sub(result.StartTime),
+				Duration: time.Since(start),
+				Failed:   err != nil,
+			})
+		}
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				result.Status = RunStatusTimeout
+				result.Error = fmt.Errorf("flow timed out in step %s", step.ID)
+				return
+			}
+			result.Status = RunStatusFailed
+			result.Error = fmt.Errorf("step %s (%s): %w", step.ID, step.Agent, err)
+			return
+		}
+		values[step.ID] = decodeStepValue(output)
+	}
+
+	output = strings.TrimSpace(output)
+	if !json.Valid([]byte(output)) {
+		data, _ := json.Marshal(output)
+		output = string(data)
+	}
+	result.Stdout = output + "\n"
+	result.Status = RunStatusSuccess
+	applyTransform(flow, result)
+}
+
+// subprocessAgentRunner runs each agent as an "ayo @handle" subprocess, for
+// callers that don't provide an in-process runner.
+func subprocessAgentRunner(dir string, env []string) AgentRunner {
+	return func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		cmd := exec.CommandContext(ctx, "ayo", handle, prompt)
+		cmd.Dir = dir
+		cmd.Env = env
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return "", err
+		}
+		return stdout.String(), nil
+	}
+}
@@ -0,0 +1,175 @@
+package flows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSteps(t *testing.T) {
+	steps, err := ParseSteps(`[{"id": "facts", "agent": "researcher", "prompt": "About {{input.topic}}"}, {"agent": "@writer", "prompt": "Summarize {{ facts }}"}]`)
+	if err != nil {
+		t.Fatalf("ParseSteps: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("got %d steps, want 2", len(steps))
+	}
+	if steps[0].Agent != "@researcher" {
+		t.Errorf("Agent = %q, want @researcher", steps[0].Agent)
+	}
+	if steps[1].ID != "step2" {
+		t.Errorf("default ID = %q, want step2", steps[1].ID)
+	}
+	if got := StepsString(steps); got != "facts (@researcher) -> step2 (@writer)" {
+		t.Errorf("StepsString = %q", got)
+	}
+}
+
+func TestParseStepsErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`{"agent": "@a"}`, "JSON array"},
+		{`[]`, "no steps"},
+		{`[{"prompt": "hi"}]`, "agent is required"},
+		{`[{"agent": "@a"}]`, "prompt is required"},
+		{`[{"id": "x", "agent": "@a", "prompt": "hi"}, {"id": "x", "agent": "@b", "prompt": "hi"}]`, "already used"},
+		{`[{"id": "input", "agent": "@a", "prompt": "hi"}]`, "already used"},
+		{`[{"id": "a b", "agent": "@a", "prompt": "hi"}]`, "invalid id"},
+		{`[{"agent": "@a", "prompt": "{{later}}"}, {"id": "later", "agent": "@b", "prompt": "hi"}]`, "earlier step"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSteps(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseSteps(%s) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestStepRenderPrompt(t *testing.T) {
+	values := map[string]any{
+		"input": decodeStepValue(`{"topic": "go", "tags": ["a", "b"], "n": 2}`),
+		"notes": decodeStepValue("plain text\n"),
+	}
+	step := Step{ID: "s", Prompt: "{{input.topic}} {{input.tags.1}} {{input.n}} {{input.tags}} [{{notes}}]"}
+	got, err := step.renderPrompt(values)
+	if err != nil {
+		t.Fatalf("renderPrompt: %v", err)
+	}
+	if want := `go b 2 ["a","b"] [plain text]`; got != want {
+		t.Errorf("renderPrompt = %q, want %q", got, want)
+	}
+
+	step = Step{ID: "s", Prompt: "{{input.missing}}"}
+	if _, err := step.renderPrompt(values); err == nil || !strings.Contains(err.Error(), `no field "missing"`) {
+		t.Errorf("renderPrompt error = %v, want missing field", err)
+	}
+}
+
+func writeStepsFlow(t *testing.T, frontmatter string) *Flow {
+	t.Helper()
+	content := "#!/usr/bin/env bash\n# ayo:flow\n# name: steps-flow\n# description: Steps\n" + frontmatter
+	path := filepath.Join(t.TempDir(), "steps-flow.sh")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	flow, err := DiscoverOne(path)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+	return flow
+}
+
+func TestRun_Steps(t *testing.T) {
+	flow := writeStepsFlow(t, `# steps: [{"id": "plan", "agent": "@planner", "prompt": "Plan {{input.task}}"}, {"agent": "@writer", "prompt": "Write {{plan.title}}"}]
+`)
+
+	var calls []string
+	agents := func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		calls = append(calls, handle+": "+prompt)
+		fmt.Fprintln(stderr, "working")
+		if handle == "@planner" {
+			return `{"title": "docs"}`, nil
+		}
+		return "done writing", nil
+	}
+
+	var streamed strings.Builder
+	result, err := RunStreaming(context.Background(), flow, RunOptions{Input: `{"task": "docs"}`, Agents: agents}, &streamed)
+	if err != nil {
+		t.Fatalf("RunStreaming: %v", err)
+	}
+	if result.Status != RunStatusSuccess {
+		t.Fatalf("Status = %v (%v), want success", result.Status, result.Error)
+	}
+	want := []string{"@planner: Plan docs", "@writer: Write docs"}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if result.Stdout != "\"done writing\"\n" {
+		t.Errorf("Stdout = %q, want the last output as a JSON string", result.Stdout)
+	}
+	if !strings.Contains(result.Stderr, "step plan: @planner") || streamed.String() != result.Stderr {
+		t.Errorf("Stderr = %q, streamed = %q", result.Stderr, streamed.String())
+	}
+}
+
+func TestRun_StepsTransform(t *testing.T) {
+	flow := writeStepsFlow(t, `# steps: [{"agent": "@a", "prompt": "go"}]
+# transform: /items/0
+`)
+	agents := func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		return `{"items": ["first"]}`, nil
+	}
+	result, err := Run(context.Background(), flow, RunOptions{Agents: agents})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != RunStatusSuccess || strings.TrimSpace(result.Stdout) != `"first"` {
+		t.Errorf("result = %v %q, want transformed output", result.Status, result.Stdout)
+	}
+}
+
+func TestRun_StepsFailure(t *testing.T) {
+	flow := writeStepsFlow(t, `# steps: [{"id": "one", "agent": "@a", "prompt": "go"}, {"agent": "@b", "prompt": "{{one}}"}]
+`)
+	called := 0
+	agents := func(ctx context.Context, handle, prompt string, stderr io.Writer) (string, error) {
+		called++
+		return "", errors.New("model unavailable")
+	}
+	result, err := Run(context.Background(), flow, RunOptions{Agents: agents})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != RunStatusFailed || called != 1 {
+		t.Errorf("Status = %v after %d calls, want failed after 1", result.Status, called)
+	}
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "step one (@a): model unavailable") {
+		t.Errorf("Error = %v", result.Error)
+	}
+}
+
+func TestDiscoverOne_StepsWithScript(t *testing.T) {
+	content := `#!/usr/bin/env bash
+# ayo:flow
+# name: mixed
+# description: Mixed
+# steps: [{"agent": "@a", "prompt": "go"}]
+
+echo hi
+`
+	path := filepath.Join(t.TempDir(), "mixed.sh")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DiscoverOne(path); err == nil || !strings.Contains(err.Error(), "steps and also has a script") {
+		t.Errorf("DiscoverOne error = %v, want steps and script conflict", err)
+	}
+}
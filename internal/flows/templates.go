@@ -0,0 +1,84 @@
+package flows
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// DefaultTemplate is the template used by `ayo flows new` when none is specified.
+const DefaultTemplate = "basic"
+
+// Template is a named scaffold for new flows.
+type Template struct {
+	Name        string
+	Description string
+}
+
+// TemplateFiles holds the rendered contents of a template.
+type TemplateFiles struct {
+	Script       []byte
+	InputSchema  []byte
+	OutputSchema []byte
+}
+
+var templates = []Template{
+	{Name: "basic", Description: "Minimal script that echoes its input"},
+	{Name: "map-reduce", Description: "Run an agent on each item, then combine the results"},
+	{Name: "validate-transform-emit", Description: "Validate records, transform valid ones, emit a report"},
+	{Name: "multi-agent-pipeline", Description: "Plan, execute, and review a task with separate agents"},
+}
+
+// Templates returns the available flow templates.
+func Templates() []Template {
+	out := make([]Template, len(templates))
+	copy(out, templates)
+	return out
+}
+
+// GetTemplate returns the template with the given name.
+func GetTemplate(name string) (Template, error) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		names[i] = t.Name
+	}
+	return Template{}, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Render returns the template files with the flow name filled in.
+func (t Template) Render(flowName string) (TemplateFiles, error) {
+	read := func(file string) ([]byte, error) {
+		data, err := templatesFS.ReadFile("templates/" + t.Name + "/" + file)
+		if err != nil {
+			return nil, fmt.Errorf("read template %s: %w", file, err)
+		}
+		return data, nil
+	}
+
+	script, err := read("flow.sh")
+	if err != nil {
+		return TemplateFiles{}, err
+	}
+	input, err := read("input.jsonschema")
+	if err != nil {
+		return TemplateFiles{}, err
+	}
+	output, err := read("output.jsonschema")
+	if err != nil {
+		return TemplateFiles{}, err
+	}
+
+	return TemplateFiles{
+		Script:       []byte(strings.ReplaceAll(string(script), "{{name}}", flowName)),
+		InputSchema:  input,
+		OutputSchema: output,
+	}, nil
+}
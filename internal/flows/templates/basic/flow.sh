@@ -0,0 +1,15 @@
+#!/usr/bin/env bash
+# ayo:flow
+# name: {{name}}
+# description: TODO: Describe what this flow does
+
+set -euo pipefail
+
+INPUT="${1:-$(cat)}"
+
+# TODO: Implement your flow
+# Example: pipe input through an agent
+# echo "$INPUT" | ayo @ayo "Process this input and return JSON"
+
+# For now, just echo the input
+echo "$INPUT"
@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+# ayo:flow
+# name: {{name}}
+# description: Apply an instruction to each item, then combine the results
+
+set -euo pipefail
+
+# Contract: logs go to stderr, the final JSON result goes to stdout.
+log() { echo "[${AYO_FLOW_NAME:-{{name}}}] $*" >&2; }
+
+INPUT="${1:-$(cat)}"
+AGENT="${AGENT:-@ayo}"
+
+INSTRUCTION=$(echo "$INPUT" | jq -r '.instruction')
+COUNT=$(echo "$INPUT" | jq '.items | length')
+log "mapping $COUNT item(s) with $AGENT"
+
+# Map: run the agent once per item, collecting one JSON object per line
+MAPPED=""
+for i in $(seq 0 $((COUNT - 1))); do
+  ITEM=$(echo "$INPUT" | jq -r ".items[$i]")
+  log "item $((i + 1))/$COUNT"
+  RESULT=$(echo "$ITEM" | ayo "$AGENT" "$INSTRUCTION
+Return only the result, no explanation." 2>/dev/null)
+  MAPPED+=$(jq -cn --arg item "$ITEM" --arg result "$RESULT" '{item: $item, result: $result}')
+  MAPPED+=$'\n'
+done
+RESULTS=$(printf '%s' "$MAPPED" | jq -s '.')
+
+# Reduce: combine the per-item results into a single summary
+log "reducing results"
+SUMMARY=$(echo "$RESULTS" | ayo "$AGENT" "Combine these per-item results into one concise summary.
+Return only the summary text." 2>/dev/null)
+
+jq -n --argjson results "$RESULTS" --arg summary "$SUMMARY" \
+  '{results: $results, summary: $summary}'
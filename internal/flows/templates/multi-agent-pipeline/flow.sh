@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+# ayo:flow
+# name: {{name}}
+# description: Plan, execute, and review a task with separate agents
+
+set -euo pipefail
+
+# Contract: logs go to stderr, the final JSON result goes to stdout.
+log() { echo "[${AYO_FLOW_NAME:-{{name}}}] $*" >&2; }
+
+INPUT="${1:-$(cat)}"
+
+# Each stage can use a different agent
+PLANNER="${PLANNER:-@ayo}"
+WORKER="${WORKER:-@ayo}"
+REVIEWER="${REVIEWER:-@ayo}"
+
+TASK=$(echo "$INPUT" | jq -r '.task')
+CONTEXT=$(echo "$INPUT" | jq -r '.context // ""')
+
+# Stage 1: Plan
+log "planning with $PLANNER"
+PLAN=$(ayo "$PLANNER" "Break this task into a short numbered plan.
+Task: $TASK
+Context: $CONTEXT
+Return only the plan." 2>/dev/null)
+
+# Stage 2: Execute
+log "executing with $WORKER"
+RESULT=$(echo "$PLAN" | ayo "$WORKER" "Carry out this plan for the task: $TASK
+Return only the result." 2>/dev/null)
+
+# Stage 3: Review
+log "reviewing with $REVIEWER"
+REVIEW=$(echo "$RESULT" | ayo "$REVIEWER" "Review this result for the task: $TASK
+Reply with APPROVED on the first line if it is acceptable, otherwise CHANGES_REQUESTED,
+followed by brief feedback." 2>/dev/null)
+
+APPROVED=false
+if [[ "$(echo "$REVIEW" | head -n 1)" == *APPROVED* && "$(echo "$REVIEW" | head -n 1)" != *CHANGES_REQUESTED* ]]; then
+  APPROVED=true
+fi
+log "approved: $APPROVED"
+
+jq -n --arg plan "$PLAN" --arg result "$RESULT" --arg review "$REVIEW" --argjson approved "$APPROVED" \
+  '{plan: $plan, result: $result, review: $review, approved: $approved}'
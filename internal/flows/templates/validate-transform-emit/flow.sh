@@ -0,0 +1,35 @@
+#!/usr/bin/env bash
+# ayo:flow
+# name: {{name}}
+# description: Validate records, transform the valid ones, and emit a report
+
+set -euo pipefail
+
+# Contract: logs go to stderr, the final JSON result goes to stdout.
+log() { echo "[${AYO_FLOW_NAME:-{{name}}}] $*" >&2; }
+
+INPUT="${1:-$(cat)}"
+AGENT="${AGENT:-@ayo}"
+
+# Validate: split records into valid and rejected.
+# TODO: Adjust the rule to match your data.
+VALID=$(echo "$INPUT" | jq '[.records[] | select(.id != null and (.text // "") != "")]')
+REJECTED=$(echo "$INPUT" | jq '[.records[] | select(.id == null or (.text // "") == "")]')
+log "validated: $(echo "$VALID" | jq length) valid, $(echo "$REJECTED" | jq length) rejected"
+
+# Transform: enrich each valid record with agent output
+TRANSFORMED=""
+while IFS= read -r RECORD; do
+  [[ -z "$RECORD" ]] && continue
+  ID=$(echo "$RECORD" | jq -r '.id')
+  log "transforming $ID"
+  # TODO: Replace with your transformation
+  SUMMARY=$(echo "$RECORD" | jq -r '.text' | ayo "$AGENT" "Summarize this text in one sentence.
+Return only the sentence." 2>/dev/null)
+  TRANSFORMED+=$(echo "$RECORD" | jq -c --arg summary "$SUMMARY" '. + {summary: $summary}')
+  TRANSFORMED+=$'\n'
+done < <(echo "$VALID" | jq -c '.[]')
+
+# Emit: a single JSON document on stdout
+printf '%s' "$TRANSFORMED" | jq -s --argjson rejected "$REJECTED" \
+  '{records: ., rejected: $rejected, stats: {transformed: length, rejected: ($rejected | length)}}'
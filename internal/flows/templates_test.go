@@ -0,0 +1,53 @@
+package flows
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTemplatesRender(t *testing.T) {
+	for _, tmpl := range Templates() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			files, err := tmpl.Render("my-flow")
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			raw, err := ParseFrontmatter(files.Script)
+			if err != nil {
+				t.Fatalf("ParseFrontmatter: %v", err)
+			}
+			if raw.Frontmatter["name"] != "my-flow" {
+				t.Errorf("name = %q, want my-flow", raw.Frontmatter["name"])
+			}
+			if strings.Contains(string(files.Script), "{{name}}") {
+				t.Error("script has unreplaced {{name}} placeholder")
+			}
+
+			for label, data := range map[string][]byte{
+				"input":  files.InputSchema,
+				"output": files.OutputSchema,
+			} {
+				var schema map[string]any
+				if err := json.Unmarshal(data, &schema); err != nil {
+					t.Errorf("%s schema is not valid JSON: %v", label, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetTemplate(t *testing.T) {
+	tmpl, err := GetTemplate(DefaultTemplate)
+	if err != nil {
+		t.Fatalf("GetTemplate(%q): %v", DefaultTemplate, err)
+	}
+	if tmpl.Name != DefaultTemplate {
+		t.Errorf("Name = %q, want %q", tmpl.Name, DefaultTemplate)
+	}
+
+	if _, err := GetTemplate("nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
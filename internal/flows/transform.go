@@ -0,0 +1,181 @@
+package flows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Transform reshapes a flow's JSON output before it is returned.
+//
+// It is declared with the transform frontmatter field, either as a single
+// JSON pointer selecting part of the output:
+//
+//	# transform: /result/items
+//
+// or as a JSON object mapping output fields to pointers. Mappings may nest:
+//
+//	# transform: {"title": "/data/title", "meta": {"count": "/data/total"}}
+type Transform struct {
+	expr    string
+	pointer string               // Set when the transform selects a single value
+	fields  map[string]Transform // Set when the transform builds an object
+}
+
+// ParseTransform parses a transform frontmatter value.
+func ParseTransform(expr string) (*Transform, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+
+	if strings.HasPrefix(expr, "{") {
+		var spec any
+		if err := json.Unmarshal([]byte(expr), &spec); err != nil {
+			return nil, fmt.Errorf("mapping is not valid JSON: %w", err)
+		}
+		t, err := parseTransformSpec(spec, "")
+		if err != nil {
+			return nil, err
+		}
+		t.expr = expr
+		return &t, nil
+	}
+
+	if err := checkPointer(expr); err != nil {
+		return nil, err
+	}
+	return &Transform{expr: expr, pointer: expr}, nil
+}
+
+// parseTransformSpec converts a decoded mapping into a Transform.
+// path locates the spec within the mapping for error messages.
+func parseTransformSpec(spec any, path string) (Transform, error) {
+	switch s := spec.(type) {
+	case string:
+		if err := checkPointer(s); err != nil {
+			return Transform{}, fmt.Errorf("field %q: %w", path, err)
+		}
+		return Transform{pointer: s}, nil
+	case map[string]any:
+		fields := make(map[string]Transform, len(s))
+		for key, value := range s {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			t, err := parseTransformSpec(value, fieldPath)
+			if err != nil {
+				return Transform{}, err
+			}
+			fields[key] = t
+		}
+		return Transform{fields: fields}, nil
+	default:
+		return Transform{}, fmt.Errorf("field %q must be a JSON pointer string or an object", path)
+	}
+}
+
+// checkPointer verifies that s is a JSON pointer (RFC 6901).
+func checkPointer(s string) error {
+	if !strings.HasPrefix(s, "/") {
+		return fmt.Errorf("invalid JSON pointer %q: must start with /", s)
+	}
+	return nil
+}
+
+// String returns the transform as written in the frontmatter.
+func (t *Transform) String() string {
+	return t.expr
+}
+
+// Apply runs the transform on a JSON document and returns the reshaped JSON.
+func (t *Transform) Apply(output string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(output))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("flow output is not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return "", fmt.Errorf("flow output contains more than one JSON value")
+	}
+
+	value, err := t.eval(doc)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("encode transformed output: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (t *Transform) eval(doc any) (any, error) {
+	if t.fields == nil {
+		return resolvePointer(doc, t.pointer)
+	}
+
+	out := make(map[string]any, len(t.fields))
+	for key, field := range t.fields {
+		value, err := field.eval(doc)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// resolvePointer returns the value at a JSON pointer within doc.
+func resolvePointer(doc any, pointer string) (any, error) {
+	current := doc
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%s: key %q not found", pointer, token)
+			}
+			current = value
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("%s: %q is not a valid array index", pointer, token)
+			}
+			if idx >= len(node) {
+				return nil, fmt.Errorf("%s: index %d out of range (length %d)", pointer, idx, len(node))
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("%s: cannot look up %q in %s", pointer, token, jsonKind(current))
+		}
+	}
+	return current, nil
+}
+
+// jsonKind names the JSON type of a decoded value for error messages.
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,116 @@
+package flows
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	valid := []string{
+		"/result",
+		"/items/0/name",
+		`{"title": "/data/title"}`,
+		`{"meta": {"count": "/data/total"}, "first": "/data/items/0"}`,
+	}
+	for _, expr := range valid {
+		if _, err := ParseTransform(expr); err != nil {
+			t.Errorf("ParseTransform(%q) error: %v", expr, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"":                       "empty",
+		"result":                 "must start with /",
+		`{"title": }`:            "not valid JSON",
+		`{"title": "data"}`:      `field "title"`,
+		`{"meta": {"n": 1}}`:     `field "meta.n" must be`,
+		`{"list": ["/a", "/b"]}`: `field "list" must be`,
+	}
+	for expr, want := range invalid {
+		_, err := ParseTransform(expr)
+		if err == nil {
+			t.Errorf("ParseTransform(%q) expected error", expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseTransform(%q) error = %q, want it to contain %q", expr, err, want)
+		}
+	}
+}
+
+func TestTransformApply(t *testing.T) {
+	output := `{"data": {"title": "Report", "total": 12345678901234567890, "items": [{"name": "a"}, {"name": "b"}], "a/b": true}}`
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"/data/title", `"Report"`},
+		{"/data/items/1", `{"name":"b"}`},
+		{"/data/a~1b", `true`},
+		{`{"title": "/data/title", "meta": {"count": "/data/total"}}`, `{"meta":{"count":12345678901234567890},"title":"Report"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			transform, err := ParseTransform(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseTransform: %v", err)
+			}
+			got, err := transform.Apply(output)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if compact := compactJSON(t, got); compact != tt.want {
+				t.Errorf("Apply() = %s, want %s", compact, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformApplyErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		output string
+		want   string
+	}{
+		{"/data/missing", `{"data": {}}`, `/data/missing: key "missing" not found`},
+		{"/items/5", `{"items": [1, 2]}`, "index 5 out of range (length 2)"},
+		{"/items/first", `{"items": [1, 2]}`, `"first" is not a valid array index`},
+		{"/name/first", `{"name": "ayo"}`, `cannot look up "first" in a string`},
+		{"/data", `not json`, "flow output is not valid JSON"},
+		{"/data", `{"data": 1} {"data": 2}`, "more than one JSON value"},
+	}
+
+	for _, tt := range tests {
+		transform, err := ParseTransform(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseTransform(%q): %v", tt.expr, err)
+		}
+		_, err = transform.Apply(tt.output)
+		if err == nil {
+			t.Errorf("Apply(%q) on %s expected error", tt.expr, tt.output)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Apply(%q) error = %q, want it to contain %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func compactJSON(t *testing.T, s string) string {
+	t.Helper()
+	var buf strings.Builder
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("invalid JSON %q: %v", s, err)
+	}
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(buf.String())
+}
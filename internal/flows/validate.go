@@ -36,6 +36,17 @@ func ValidateInput(flow *Flow, input string) error {
 	return validateJSON(input, schemaData, "input")
 }
 
+// ValidateInputOverride validates the input JSON against the schema at
+// schemaPath in place of the flow's input schema.
+func ValidateInputOverride(schemaPath, input string) error {
+	schemaData, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("read input schema override: %w", err)
+	}
+
+	return validateJSON(input, schemaData, "input")
+}
+
 // ValidateOutput validates the output JSON against the flow's output schema.
 // Returns warnings, not errors - output is still valid even if schema check fails.
 func ValidateOutput(flow *Flow, output string) []string {
@@ -0,0 +1,121 @@
+package flows
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 of the request body,
+// formatted as "sha256=<hex>", when a webhook secret is configured.
+const WebhookSignatureHeader = "X-Ayo-Signature"
+
+// webhookTimeout bounds delivery so a slow endpoint can't hold up the run.
+const webhookTimeout = 10 * time.Second
+
+// Webhook posts the result of a run to a URL when the run completes.
+type Webhook struct {
+	URL    string
+	Secret string       // Signs the payload when set
+	Client *http.Client // nil = http.DefaultClient
+}
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	RunID      string    `json:"run_id"`
+	Flow       string    `json:"flow"`
+	Status     RunStatus `json:"status"`
+	ExitCode   int       `json:"exit_code"`
+	Output     any       `json:"output"` // Parsed JSON, or the raw text if stdout isn't JSON
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// NewWebhookPayload builds the payload for a completed run.
+func NewWebhookPayload(result *RunResult) WebhookPayload {
+	payload := WebhookPayload{
+		RunID:      result.RunID,
+		Status:     result.Status,
+		ExitCode:   result.ExitCode,
+		DurationMS: result.Duration.Milliseconds(),
+		StartedAt:  result.StartTime,
+		FinishedAt: result.EndTime,
+	}
+	if result.Flow != nil {
+		payload.Flow = result.Flow.Name
+	}
+	if result.Error != nil {
+		payload.Error = result.Error.Error()
+	}
+	if result.Stdout != "" {
+		var output any
+		if err := json.Unmarshal([]byte(result.Stdout), &output); err == nil {
+			payload.Output = output
+		} else {
+			payload.Output = result.Stdout
+		}
+	}
+	return payload
+}
+
+// SignWebhookPayload returns the signature header value for body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts the run's result. Any non-2xx response is an error.
+func (w *Webhook) Send(ctx context.Context, result *RunResult) error {
+	body, err := json.Marshal(NewWebhookPayload(result))
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// sendWebhookIfEnabled delivers the run's result to the configured webhook.
+// Delivery failures are recorded on the result and never change its status.
+func sendWebhookIfEnabled(ctx context.Context, opts RunOptions, result *RunResult) {
+	if opts.Webhook == nil || opts.Webhook.URL == "" {
+		return
+	}
+	// The run's context may have timed out; the result should still be sent
+	if err := opts.Webhook.Send(context.WithoutCancel(ctx), result); err != nil {
+		result.WebhookError = fmt.Errorf("deliver webhook: %w", err)
+	}
+}
@@ -0,0 +1,87 @@
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFlow(t *testing.T, body string) *Flow {
+	t.Helper()
+	content := "#!/usr/bin/env bash\n# ayo:flow\n# name: hook-flow\n# description: Webhook test\n\n" + body + "\n"
+	flowPath := filepath.Join(t.TempDir(), "hook-flow.sh")
+	if err := os.WriteFile(flowPath, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	flow, err := DiscoverOne(flowPath)
+	if err != nil {
+		t.Fatalf("DiscoverOne: %v", err)
+	}
+	return flow
+}
+
+func TestRun_Webhook(t *testing.T) {
+	var payload WebhookPayload
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(WebhookSignatureHeader)
+		json.Unmarshal(body, &payload)
+	}))
+	defer server.Close()
+
+	flow := writeTestFlow(t, `echo '{"count": 3}'`)
+	result, err := Run(context.Background(), flow, RunOptions{
+		Webhook: &Webhook{URL: server.URL, Secret: "s3cret"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.WebhookError != nil {
+		t.Fatalf("WebhookError = %v", result.WebhookError)
+	}
+
+	if payload.RunID != result.RunID || payload.Flow != "hook-flow" || payload.Status != RunStatusSuccess {
+		t.Errorf("payload = %+v", payload)
+	}
+	if output, ok := payload.Output.(map[string]any); !ok || output["count"] != float64(3) {
+		t.Errorf("Output = %#v, want the parsed JSON", payload.Output)
+	}
+	if signature != SignWebhookPayload("s3cret", body) {
+		t.Errorf("signature = %q, want HMAC of the body", signature)
+	}
+}
+
+func TestRun_WebhookFailureKeepsStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	flow := writeTestFlow(t, `echo "not json"; exit 0`)
+	result, err := Run(context.Background(), flow, RunOptions{
+		Webhook: &Webhook{URL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Status != RunStatusSuccess {
+		t.Errorf("Status = %v, want success despite the webhook failure", result.Status)
+	}
+	if result.WebhookError == nil {
+		t.Error("expected a webhook error for a 500 response")
+	}
+}
+
+func TestNewWebhookPayloadRawOutput(t *testing.T) {
+	payload := NewWebhookPayload(&RunResult{RunID: "r1", Status: RunStatusFailed, Stdout: "plain text"})
+	if payload.Output != "plain text" {
+		t.Errorf("Output = %#v, want the raw text", payload.Output)
+	}
+}
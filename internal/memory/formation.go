@@ -213,6 +213,7 @@ func (f *FormationService) processFormation(ctx context.Context, intent Formatio
 		PathScope:   intent.PathScope,
 		Threshold:   SupersedeThreshold,
 		Limit:       1,
+		Mode:        SearchModeSemantic, // Dedup thresholds are calibrated for cosine similarity
 	})
 	if err != nil {
 		// Log but continue with creation
@@ -0,0 +1,318 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alexcabrera/ayo/internal/embedding"
+)
+
+// ImportedMessage is a user message read from another tool's export.
+type ImportedMessage struct {
+	Conversation string // Title of the conversation, if the export has one
+	Text         string
+}
+
+// chatGPTConversation is a conversation in a ChatGPT export's
+// conversations.json. Messages form a tree in Mapping; CurrentNode is the
+// last message of the branch shown in the conversation.
+type chatGPTConversation struct {
+	Title       string                 `json:"title"`
+	CurrentNode string                 `json:"current_node"`
+	Mapping     map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	Parent  string `json:"parent"`
+	Message *struct {
+		Author struct {
+			Role string `json:"role"`
+		} `json:"author"`
+		CreateTime float64 `json:"create_time"`
+		Content    struct {
+			Parts []json.RawMessage `json:"parts"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// ParseChatGPTExport reads the user messages of the conversations.json in
+// a ChatGPT data export. Each conversation's current branch is read in
+// order; branches left by editing a message are skipped. Parts other than
+// text, such as images, are ignored.
+func ParseChatGPTExport(data []byte) ([]ImportedMessage, error) {
+	var conversations []chatGPTConversation
+	if err := json.Unmarshal(data, &conversations); err != nil {
+		return nil, fmt.Errorf("not a ChatGPT conversations.json: %w", err)
+	}
+
+	var messages []ImportedMessage
+	for _, conv := range conversations {
+		for _, id := range chatGPTBranch(conv) {
+			msg := conv.Mapping[id].Message
+			if msg == nil || msg.Author.Role != "user" {
+				continue
+			}
+			var parts []string
+			for _, raw := range msg.Content.Parts {
+				var text string
+				if json.Unmarshal(raw, &text) == nil && strings.TrimSpace(text) != "" {
+					parts = append(parts, strings.TrimSpace(text))
+				}
+			}
+			if len(parts) > 0 {
+				messages = append(messages, ImportedMessage{Conversation: conv.Title, Text: strings.Join(parts, "\n")})
+			}
+		}
+	}
+	return messages, nil
+}
+
+// chatGPTBranch returns the IDs of the conversation's current branch, first
+// message first. Without a current node, every message is returned in the
+// order it was written.
+func chatGPTBranch(conv chatGPTConversation) []string {
+	if _, ok := conv.Mapping[conv.CurrentNode]; ok {
+		var ids []string
+		seen := make(map[string]bool)
+		for id := conv.CurrentNode; id != "" && !seen[id]; id = conv.Mapping[id].Parent {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		slices.Reverse(ids)
+		return ids
+	}
+
+	ids := make([]string, 0, len(conv.Mapping))
+	for id, node := range conv.Mapping {
+		if node.Message != nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		ta, tb := conv.Mapping[a].Message.CreateTime, conv.Mapping[b].Message.CreateTime
+		switch {
+		case ta < tb:
+			return -1
+		case ta > tb:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return ids
+}
+
+// ImportFormat maps another tool's JSON export to messages. Paths are keys
+// separated by dots, where "[]" after a key, or on its own, steps into each
+// element of an array, as in "conversations[].messages[]".
+type ImportFormat struct {
+	Messages string `json:"messages"`            // Path from the root to each message
+	Text     string `json:"text"`                // Path from a message to its text
+	Role     string `json:"role,omitempty"`      // Path from a message to its role; empty imports every message
+	UserRole string `json:"user_role,omitempty"` // Role of user messages (default "user")
+}
+
+// LoadImportFormat reads an import format from a JSON file.
+func LoadImportFormat(path string) (ImportFormat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImportFormat{}, err
+	}
+	var f ImportFormat
+	if err := json.Unmarshal(data, &f); err != nil {
+		return ImportFormat{}, fmt.Errorf("invalid import format %s: %w", path, err)
+	}
+	if f.Messages == "" || f.Text == "" {
+		return ImportFormat{}, fmt.Errorf("invalid import format %s: \"messages\" and \"text\" paths are required", path)
+	}
+	return f, nil
+}
+
+// Parse reads the user messages of an export in the format. String values
+// found at the text path are joined with newlines.
+func (f ImportFormat) Parse(data []byte) ([]ImportedMessage, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	userRole := f.UserRole
+	if userRole == "" {
+		userRole = "user"
+	}
+
+	var messages []ImportedMessage
+	for _, msg := range selectPath(root, f.Messages) {
+		if f.Role != "" && !slices.Contains(selectStrings(msg, f.Role), userRole) {
+			continue
+		}
+		if text := strings.TrimSpace(strings.Join(selectStrings(msg, f.Text), "\n")); text != "" {
+			messages = append(messages, ImportedMessage{Text: text})
+		}
+	}
+	return messages, nil
+}
+
+// selectPath returns the values at path in v. A missing key or a step into
+// something that isn't an array yields nothing.
+func selectPath(v any, path string) []any {
+	values := []any{v}
+	for _, segment := range strings.Split(path, ".") {
+		key, iterate := strings.CutSuffix(segment, "[]")
+		var next []any
+		for _, value := range values {
+			if key != "" {
+				obj, ok := value.(map[string]any)
+				if !ok {
+					continue
+				}
+				if value, ok = obj[key]; !ok {
+					continue
+				}
+			}
+			if !iterate {
+				next = append(next, value)
+				continue
+			}
+			if arr, ok := value.([]any); ok {
+				next = append(next, arr...)
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// selectStrings returns the strings at path in v, including the strings in
+// an array at path.
+func selectStrings(v any, path string) []string {
+	var out []string
+	for _, value := range selectPath(v, path) {
+		switch value := value.(type) {
+		case string:
+			out = append(out, value)
+		case []any:
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					out = append(out, s)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ImportCandidate is a memory extracted from imported history.
+type ImportCandidate struct {
+	Content  string
+	Category Category
+}
+
+// PrepareImport drops candidates that duplicate an active memory, a queued
+// proposal, or an earlier candidate, and returns the rest as proposals for
+// agentHandle along with the number dropped. A candidate similar to an
+// active memory, but not a duplicate of it, supersedes that memory when
+// accepted. Without an embedder, only candidates with the same text are
+// duplicates.
+func (s *Service) PrepareImport(ctx context.Context, agentHandle string, candidates []ImportCandidate) ([]Proposal, int, error) {
+	existing, err := s.All(ctx, agentHandle)
+	if err != nil {
+		return nil, 0, err
+	}
+	queued, err := s.ListProposals(ctx, agentHandle)
+	if err != nil {
+		return nil, 0, err
+	}
+	seen := make(map[string]bool)
+	var seenEmbeddings [][]float32
+	for _, m := range existing {
+		seen[normalizeImportText(m.Content)] = true
+	}
+	var unembedded []string
+	for _, p := range queued {
+		seen[normalizeImportText(p.Content)] = true
+		if len(p.Embedding) > 0 {
+			seenEmbeddings = append(seenEmbeddings, p.Embedding)
+		} else {
+			unembedded = append(unembedded, p.Content)
+		}
+	}
+	if s.embedder != nil && len(unembedded) > 0 {
+		embs, err := s.embedder.EmbedBatch(ctx, unembedded)
+		if err != nil {
+			return nil, 0, fmt.Errorf("embed queued proposals: %w", err)
+		}
+		seenEmbeddings = append(seenEmbeddings, embs...)
+	}
+
+	var similar []BatchSearchResult
+	if s.embedder != nil && len(candidates) > 0 {
+		contents := make([]string, len(candidates))
+		for i, c := range candidates {
+			contents[i] = c.Content
+		}
+		similar, err = s.SearchBatch(ctx, contents, SearchOptions{
+			AgentHandle: agentHandle,
+			Threshold:   SupersedeThreshold,
+			Limit:       1,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var proposals []Proposal
+	duplicates := 0
+	for i, c := range candidates {
+		key := normalizeImportText(c.Content)
+		if key == "" {
+			continue
+		}
+		p := Proposal{AgentHandle: agentHandle, Content: c.Content, Category: c.Category}
+		if i < len(similar) {
+			p.Embedding = similar[i].Embedding
+		}
+		if seen[key] || duplicatesEmbedding(p.Embedding, seenEmbeddings) {
+			duplicates++
+			continue
+		}
+		if i < len(similar) && len(similar[i].Results) > 0 {
+			match := similar[i].Results[0]
+			if match.Similarity >= ExactDuplicateThreshold {
+				duplicates++
+				continue
+			}
+			p.SupersedesID = match.Memory.ID
+			p.SupersessionReason = "updated via memory import"
+		}
+
+		seen[key] = true
+		if len(p.Embedding) > 0 {
+			seenEmbeddings = append(seenEmbeddings, p.Embedding)
+		}
+		proposals = append(proposals, p)
+	}
+	return proposals, duplicates, nil
+}
+
+// duplicatesEmbedding reports whether emb is as close to one of others as
+// an exact duplicate.
+func duplicatesEmbedding(emb []float32, others [][]float32) bool {
+	if len(emb) == 0 {
+		return false
+	}
+	for _, other := range others {
+		if len(other) == len(emb) && embedding.CosineSimilarity(emb, other) >= ExactDuplicateThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImportText folds case, whitespace, and trailing punctuation so
+// the same memory phrased identically compares equal.
+func normalizeImportText(s string) string {
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(s), " ")), ".!")
+}
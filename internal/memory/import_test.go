@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+const chatGPTExport = `[
+  {
+    "title": "Editors",
+    "current_node": "c",
+    "mapping": {
+      "root": {"parent": null, "message": null},
+      "a": {"parent": "root", "message": {"author": {"role": "user"}, "create_time": 1, "content": {"parts": ["I use vim", {"asset_pointer": "file-1"}]}}},
+      "old": {"parent": "a", "message": {"author": {"role": "user"}, "create_time": 2, "content": {"parts": ["edited away"]}}},
+      "b": {"parent": "a", "message": {"author": {"role": "assistant"}, "create_time": 3, "content": {"parts": ["Nice"]}}},
+      "c": {"parent": "b", "message": {"author": {"role": "user"}, "create_time": 4, "content": {"parts": ["My team deploys on Fridays"]}}}
+    }
+  },
+  {
+    "title": "No current node",
+    "mapping": {
+      "y": {"parent": "x", "message": {"author": {"role": "user"}, "create_time": 20, "content": {"parts": ["second"]}}},
+      "x": {"parent": null, "message": {"author": {"role": "user"}, "create_time": 10, "content": {"parts": ["first"]}}}
+    }
+  }
+]`
+
+func TestParseChatGPTExport(t *testing.T) {
+	messages, err := ParseChatGPTExport([]byte(chatGPTExport))
+	if err != nil {
+		t.Fatalf("ParseChatGPTExport: %v", err)
+	}
+	want := []ImportedMessage{
+		{Conversation: "Editors", Text: "I use vim"},
+		{Conversation: "Editors", Text: "My team deploys on Fridays"},
+		{Conversation: "No current node", Text: "first"},
+		{Conversation: "No current node", Text: "second"},
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("messages = %+v, want %+v", messages, want)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("message %d = %+v, want %+v", i, messages[i], want[i])
+		}
+	}
+
+	if _, err := ParseChatGPTExport([]byte(`{"not": "a list"}`)); err == nil {
+		t.Error("expected an error for an object")
+	}
+}
+
+func TestImportFormat(t *testing.T) {
+	export := `{"chats": [
+	  {"turns": [{"from": "human", "text": "I prefer tabs"}, {"from": "bot", "text": "Noted"}]},
+	  {"turns": [{"from": "human", "text": ["Line one", "Line two"]}, {"from": "human"}]}
+	]}`
+
+	f := ImportFormat{Messages: "chats[].turns[]", Text: "text", Role: "from", UserRole: "human"}
+	messages, err := f.Parse([]byte(export))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Text != "I prefer tabs" || messages[1].Text != "Line one\nLine two" {
+		t.Errorf("messages = %+v", messages)
+	}
+
+	// Without a role path every message is imported
+	f.Role = ""
+	if messages, _ := f.Parse([]byte(export)); len(messages) != 3 {
+		t.Errorf("without role: %+v", messages)
+	}
+
+	// A top-level array is stepped into with a bare "[]"
+	f = ImportFormat{Messages: "[]", Text: "body"}
+	if messages, _ := f.Parse([]byte(`[{"body": "a"}, {"body": "b"}, "skip"]`)); len(messages) != 2 {
+		t.Errorf("top-level array: %+v", messages)
+	}
+}
+
+func TestLoadImportFormat(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`{"messages": "items[]", "text": "content"}`), 0o644)
+	f, err := LoadImportFormat(good)
+	if err != nil || f.Messages != "items[]" || f.Text != "content" {
+		t.Errorf("LoadImportFormat = %+v, %v", f, err)
+	}
+
+	missing := filepath.Join(dir, "missing.json")
+	os.WriteFile(missing, []byte(`{"messages": "items[]"}`), 0o644)
+	if _, err := LoadImportFormat(missing); err == nil {
+		t.Error("expected an error without a text path")
+	}
+}
+
+// keyedEmbedder embeds known texts to fixed vectors and anything else to a
+// vector orthogonal to all of them.
+type keyedEmbedder map[string][]float32
+
+func (k keyedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if emb, ok := k[text]; ok {
+		return emb, nil
+	}
+	return []float32{0, 0, 0, 1}, nil
+}
+
+func (k keyedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = k.Embed(ctx, text)
+	}
+	return out, nil
+}
+
+func (k keyedEmbedder) Dimension() int { return 4 }
+func (k keyedEmbedder) Close() error   { return nil }
+
+func TestPrepareImport(t *testing.T) {
+	ctx := context.Background()
+	testDB, queries, err := db.ConnectWithQueries(ctx, ":memory:")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer testDB.Close()
+
+	svc := NewService(queries, keyedEmbedder{
+		"Uses npm":                {1, 0, 0, 0},
+		"Uses npm for packages":   {1, 0.05, 0, 0}, // duplicate of "Uses npm"
+		"Uses pnpm":               {1, 0.6, 0, 0},  // similar to "Uses npm"
+		"Works in Go":             {0, 1, 0, 0},
+		"Writes Go":               {0, 1, 0.1, 0}, // duplicate of "Works in Go"
+		"Deploys on Fridays":      {0, 0, 1, 0},
+		"Team deploys on Fridays": {0, 0, 1, 0.1}, // duplicate of a queued proposal
+	})
+	old, err := svc.Create(ctx, Memory{Content: "Uses npm", Category: CategoryPreference, AgentHandle: "@ayo"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := svc.Propose(ctx, Proposal{AgentHandle: "@ayo", Content: "Deploys on Fridays"}); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+
+	proposals, duplicates, err := svc.PrepareImport(ctx, "@ayo", []ImportCandidate{
+		{Content: "uses  NPM.", Category: CategoryPreference},
+		{Content: "Uses npm for packages", Category: CategoryPreference},
+		{Content: "Team deploys on Fridays", Category: CategoryFact},
+		{Content: "Uses pnpm", Category: CategoryPreference},
+		{Content: "Works in Go", Category: CategoryFact},
+		{Content: "Writes Go", Category: CategoryFact},
+		{Content: "Works in Go", Category: CategoryFact},
+	})
+	if err != nil {
+		t.Fatalf("PrepareImport: %v", err)
+	}
+	if duplicates != 5 {
+		t.Errorf("duplicates = %d, want 5", duplicates)
+	}
+	if len(proposals) != 2 {
+		t.Fatalf("proposals = %+v, want 2", proposals)
+	}
+	if p := proposals[0]; p.Content != "Uses pnpm" || p.SupersedesID != old.ID || p.AgentHandle != "@ayo" || len(p.Embedding) == 0 {
+		t.Errorf("proposal 0 = %+v, want to supersede %s", p, old.ID)
+	}
+	if p := proposals[1]; p.Content != "Works in Go" || p.SupersedesID != "" {
+		t.Errorf("proposal 1 = %+v", p)
+	}
+
+	// Without an embedder only identical text is a duplicate
+	plain := NewService(queries, nil)
+	proposals, duplicates, err = plain.PrepareImport(ctx, "@ayo", []ImportCandidate{
+		{Content: "Uses npm"},
+		{Content: "Uses npm for packages"},
+	})
+	if err != nil || duplicates != 1 || len(proposals) != 1 {
+		t.Errorf("without embedder = %+v, %d, %v", proposals, duplicates, err)
+	}
+}
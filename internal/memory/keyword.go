@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// BM25 tuning parameters (standard Okapi defaults).
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// stopwords are common English words ignored by keyword search.
+var stopwords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {},
+	"by": {}, "do": {}, "does": {}, "for": {}, "from": {}, "has": {}, "have": {},
+	"i": {}, "in": {}, "is": {}, "it": {}, "its": {}, "me": {}, "my": {}, "of": {},
+	"on": {}, "or": {}, "our": {}, "so": {}, "that": {}, "the": {}, "their": {},
+	"them": {}, "they": {}, "this": {}, "to": {}, "was": {}, "we": {}, "were": {},
+	"what": {}, "when": {}, "where": {}, "which": {}, "who": {}, "why": {}, "will": {},
+	"with": {}, "you": {}, "your": {},
+}
+
+// tokenize splits text into lowercase terms, dropping punctuation and stopwords.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if len(f) < 2 {
+			continue
+		}
+		if _, ok := stopwords[f]; ok {
+			continue
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// keywordScore is the BM25 result for a single document.
+type keywordScore struct {
+	BM25     float64 // Raw BM25 score, used for ranking
+	Coverage float32 // IDF-weighted fraction of query terms present (0-1)
+}
+
+// keywordScorer scores documents against a query using BM25 over a fixed corpus.
+type keywordScorer struct {
+	queryTerms []string
+	idf        map[string]float64
+	docs       [][]string
+	avgLen     float64
+}
+
+// newKeywordScorer builds a scorer for the query over the given documents.
+func newKeywordScorer(query string, documents []string) *keywordScorer {
+	ks := &keywordScorer{
+		queryTerms: uniqueTerms(tokenize(query)),
+		idf:        make(map[string]float64),
+		docs:       make([][]string, len(documents)),
+	}
+
+	docFreq := make(map[string]int)
+	totalLen := 0
+	for i, d := range documents {
+		terms := tokenize(d)
+		ks.docs[i] = terms
+		totalLen += len(terms)
+		for _, t := range uniqueTerms(terms) {
+			docFreq[t]++
+		}
+	}
+
+	n := float64(len(documents))
+	if n > 0 {
+		ks.avgLen = float64(totalLen) / n
+	}
+	for _, t := range ks.queryTerms {
+		df := float64(docFreq[t])
+		ks.idf[t] = math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+
+	return ks
+}
+
+// Score returns the BM25 score and query coverage for the i-th document.
+func (ks *keywordScorer) Score(i int) keywordScore {
+	if len(ks.queryTerms) == 0 || i < 0 || i >= len(ks.docs) {
+		return keywordScore{}
+	}
+
+	doc := ks.docs[i]
+	tf := make(map[string]int, len(doc))
+	for _, t := range doc {
+		tf[t]++
+	}
+
+	docLen := float64(len(doc))
+	var score, matchedIDF, totalIDF float64
+	for _, t := range ks.queryTerms {
+		idf := ks.idf[t]
+		totalIDF += idf
+
+		freq := float64(tf[t])
+		if freq == 0 {
+			continue
+		}
+		matchedIDF += idf
+
+		norm := 1.0
+		if ks.avgLen > 0 {
+			norm = 1 - bm25B + bm25B*docLen/ks.avgLen
+		}
+		score += idf * (freq * (bm25K1 + 1)) / (freq + bm25K1*norm)
+	}
+
+	var coverage float32
+	if totalIDF > 0 {
+		coverage = float32(matchedIDF / totalIDF)
+	}
+	return keywordScore{BM25: score, Coverage: coverage}
+}
+
+// uniqueTerms returns terms with duplicates removed, preserving order.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	out := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("The user prefers Go, and uses gofmt!")
+	want := []string{"user", "prefers", "go", "uses", "gofmt"}
+
+	if len(got) != len(want) {
+		t.Fatalf("tokenize() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tokenize()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestKeywordScorer(t *testing.T) {
+	docs := []string{
+		"User likes Go programming language",
+		"User prefers dark theme",
+		"Go is used for the backend; Go tests run with go test",
+	}
+	ks := newKeywordScorer("go programming", docs)
+
+	first := ks.Score(0)
+	second := ks.Score(1)
+	third := ks.Score(2)
+
+	if second.BM25 != 0 || second.Coverage != 0 {
+		t.Errorf("unrelated document scored %+v, want zero", second)
+	}
+	if first.Coverage != 1 {
+		t.Errorf("full match coverage = %v, want 1", first.Coverage)
+	}
+	if third.Coverage <= 0 || third.Coverage >= 1 {
+		t.Errorf("partial match coverage = %v, want between 0 and 1", third.Coverage)
+	}
+	if first.BM25 <= third.BM25 {
+		t.Errorf("full match BM25 %v should exceed partial match %v", first.BM25, third.BM25)
+	}
+}
+
+func TestKeywordSearchWithoutEmbedder(t *testing.T) {
+	ctx := context.Background()
+	testDB, queries, err := db.ConnectWithQueries(ctx, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	defer testDB.Close()
+
+	svc := NewService(queries, nil)
+
+	for _, m := range []Memory{
+		{Content: "User likes Go programming language", Category: CategoryFact},
+		{Content: "User prefers dark theme", Category: CategoryPreference},
+		{Content: "Always use gofmt for formatting Go code", Category: CategoryCorrection},
+	} {
+		if _, err := svc.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	if mode := svc.ResolveSearchMode(SearchModeAuto); mode != SearchModeKeyword {
+		t.Errorf("ResolveSearchMode(auto) = %q, want keyword", mode)
+	}
+
+	results, err := svc.Search(ctx, "go programming", SearchOptions{Threshold: 0.1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Memory.Content != "User likes Go programming language" {
+		t.Errorf("top result = %q, want the Go programming memory", results[0].Memory.Content)
+	}
+	for _, r := range results {
+		if r.Mode != SearchModeKeyword {
+			t.Errorf("result mode = %q, want keyword", r.Mode)
+		}
+	}
+
+	// Semantic search cannot run without an embedder
+	results, err = svc.Search(ctx, "go programming", SearchOptions{Mode: SearchModeSemantic})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no semantic results without embedder, got %d", len(results))
+	}
+}
+
+func TestHybridSearch(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if mode := svc.ResolveSearchMode(SearchModeAuto); mode != SearchModeSemantic {
+		t.Errorf("ResolveSearchMode(auto) = %q, want semantic", mode)
+	}
+
+	for _, m := range []Memory{
+		{Content: "User likes Go programming language", Category: CategoryFact},
+		{Content: "User prefers dark theme", Category: CategoryPreference},
+	} {
+		if _, err := svc.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	results, err := svc.Search(ctx, "go programming", SearchOptions{
+		Threshold: 0.0,
+		Mode:      SearchModeHybrid,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected hybrid results")
+	}
+	if results[0].Memory.Content != "User likes Go programming language" {
+		t.Errorf("top result = %q, want the Go programming memory", results[0].Memory.Content)
+	}
+	for _, r := range results {
+		if r.Mode != SearchModeHybrid {
+			t.Errorf("result mode = %q, want hybrid", r.Mode)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package memory
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -56,20 +57,39 @@ type Memory struct {
 	Status             Status
 }
 
+// SearchMode selects how memories are matched against a query.
+type SearchMode string
+
+const (
+	SearchModeAuto     SearchMode = ""         // Semantic if an embedder is available, keyword otherwise
+	SearchModeSemantic SearchMode = "semantic" // Vector similarity only
+	SearchModeKeyword  SearchMode = "keyword"  // BM25 keyword matching only
+	SearchModeHybrid   SearchMode = "hybrid"   // Vector similarity blended with keyword matching
+)
+
+// HybridKeywordWeight is the share of the hybrid score contributed by keyword matching.
+const HybridKeywordWeight float32 = 0.3
+
 // SearchResult represents a memory search result with similarity score.
+// In keyword mode, Similarity is the IDF-weighted fraction of query terms found
+// in the memory, so it stays in the same 0-1 range as cosine similarity.
 type SearchResult struct {
 	Memory     Memory
 	Similarity float32
+	Score      float32 // Ranking score; Similarity unless SearchOptions.Weights are set
 	Distance   float32
+	Mode       SearchMode // Mode that produced this result
 }
 
 // SearchOptions configures memory search.
 type SearchOptions struct {
 	AgentHandle string  // Filter by agent (empty = include global)
-	PathScope   string  // Filter by path scope (empty = include global)
+	PathScope   string  // Working directory: keep memories scoped to it or a parent, plus global ones (empty = no filter)
 	Threshold   float32 // Minimum similarity threshold (0-1)
 	Limit       int     // Maximum results
 	Categories  []Category // Filter by categories (empty = all)
+	Mode        SearchMode // Search mode (empty = auto)
+	Weights     RankingWeights // Blend of signals to rank by (zero = similarity only)
 }
 
 // Service provides memory operations.
@@ -91,6 +111,14 @@ func (s *Service) HasEmbedder() bool {
 	return s != nil && s.embedder != nil
 }
 
+// Embedder returns the service's embedder, or nil if none is configured.
+func (s *Service) Embedder() embedding.Embedder {
+	if s == nil {
+		return nil
+	}
+	return s.embedder
+}
+
 // Create stores a new memory with automatic embedding generation.
 func (s *Service) Create(ctx context.Context, m Memory) (Memory, error) {
 	if m.ID == "" {
@@ -238,14 +266,55 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	return s.queries.DeleteMemory(ctx, id)
 }
 
-// Search performs semantic search over memories.
-// If no embedder is configured, returns empty results.
+// Search finds memories relevant to the query.
+// Semantic (vector) search is used when an embedder is configured; otherwise
+// Search falls back to BM25 keyword scoring so memory stays usable without Ollama.
 func (s *Service) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
-	// Without an embedder, we can't do semantic search
+	mode := s.ResolveSearchMode(opts.Mode)
+
+	// Semantic search was explicitly requested but we have nothing to embed with
+	if mode == SearchModeSemantic && s.embedder == nil {
+		return nil, nil
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = 10
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = 0.3
+	}
+
+	var results []SearchResult
+	var err error
+	if mode == SearchModeSemantic {
+		results, err = s.semanticSearch(ctx, query, opts)
+	} else {
+		results, err = s.keywordSearch(ctx, query, opts, mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.finishSearch(ctx, results, opts), nil
+}
+
+// BatchSearchResult holds the semantic search results for one query in a batch.
+type BatchSearchResult struct {
+	Query     string
+	Embedding []float32 // Query embedding, reusable when storing the query as a memory
+	Results   []SearchResult
+}
+
+// SearchBatch runs a semantic search for each query, embedding all queries in
+// one call and loading candidates once. Results are returned in query order.
+func (s *Service) SearchBatch(ctx context.Context, queries []string, opts SearchOptions) ([]BatchSearchResult, error) {
 	if s.embedder == nil {
+		return nil, fmt.Errorf("semantic search requires an embedder")
+	}
+	if len(queries) == 0 {
 		return nil, nil
 	}
-	
+
 	if opts.Limit == 0 {
 		opts.Limit = 10
 	}
@@ -253,6 +322,72 @@ func (s *Service) Search(ctx context.Context, query string, opts SearchOptions)
 		opts.Threshold = 0.3
 	}
 
+	embeddings, err := s.embedder.EmbedBatch(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != len(queries) {
+		return nil, fmt.Errorf("embedder returned %d embeddings for %d queries", len(embeddings), len(queries))
+	}
+
+	candidates, err := s.queries.GetMemoriesForSearch(ctx, db.GetMemoriesForSearchParams{
+		AgentHandle: toNullString(opts.AgentHandle),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batch := make([]BatchSearchResult, len(queries))
+	for i, q := range queries {
+		results := scoreCandidates(embeddings[i], candidates, opts)
+		batch[i] = BatchSearchResult{
+			Query:     q,
+			Embedding: embeddings[i],
+			Results:   s.finishSearch(ctx, results, opts),
+		}
+	}
+
+	return batch, nil
+}
+
+// finishSearch ranks results by the weights, applies the limit, and records access.
+func (s *Service) finishSearch(ctx context.Context, results []SearchResult, opts SearchOptions) []SearchResult {
+	rank(results, opts.Weights, time.Now())
+
+	// Apply limit
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	// Update access timestamps for returned results
+	now := time.Now().Unix()
+	for _, r := range results {
+		_ = s.queries.UpdateMemoryAccess(ctx, db.UpdateMemoryAccessParams{
+			LastAccessedAt: sql.NullInt64{Int64: now, Valid: true},
+			ID:             r.Memory.ID,
+		})
+	}
+
+	return results
+}
+
+// ResolveSearchMode returns the mode Search will actually use for the requested mode.
+// Without an embedder, auto and hybrid searches degrade to keyword search.
+func (s *Service) ResolveSearchMode(mode SearchMode) SearchMode {
+	if !s.HasEmbedder() {
+		if mode == SearchModeSemantic {
+			return SearchModeSemantic
+		}
+		return SearchModeKeyword
+	}
+	if mode == SearchModeAuto {
+		return SearchModeSemantic
+	}
+	return mode
+}
+
+// semanticSearch scores candidates by cosine similarity to the query embedding.
+func (s *Service) semanticSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
 	// Generate query embedding
 	queryEmb, err := s.embedder.Embed(ctx, query)
 	if err != nil {
@@ -262,13 +397,17 @@ func (s *Service) Search(ctx context.Context, query string, opts SearchOptions)
 	// Get all candidate memories
 	candidates, err := s.queries.GetMemoriesForSearch(ctx, db.GetMemoriesForSearchParams{
 		AgentHandle: toNullString(opts.AgentHandle),
-		PathScope:   toNullString(opts.PathScope),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate similarity for each candidate
+	return scoreCandidates(queryEmb, candidates, opts), nil
+}
+
+// scoreCandidates returns the candidates whose cosine similarity to queryEmb
+// meets the threshold and the category and path filters, unsorted.
+func scoreCandidates(queryEmb []float32, candidates []db.GetMemoriesForSearchRow, opts SearchOptions) []SearchResult {
 	var results []SearchResult
 	for _, c := range candidates {
 		memEmb := embedding.DeserializeFloat32(c.Embedding)
@@ -281,60 +420,133 @@ func (s *Service) Search(ctx context.Context, query string, opts SearchOptions)
 			continue
 		}
 
-		// Filter by category if specified
-		if len(opts.Categories) > 0 {
-			found := false
-			for _, cat := range opts.Categories {
-				if string(cat) == c.Category {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		if !matchesCategories(c.Category, opts.Categories) {
+			continue
+		}
+
+		if !PathApplies(fromNullString(c.PathScope), opts.PathScope) {
+			continue
 		}
 
 		results = append(results, SearchResult{
-			Memory: Memory{
-				ID:             c.ID,
-				AgentHandle:    fromNullString(c.AgentHandle),
-				PathScope:      fromNullString(c.PathScope),
-				Content:        c.Content,
-				Category:       Category(c.Category),
-				Embedding:      memEmb,
-				Confidence:     c.Confidence.Float64,
-				LastAccessedAt: time.Unix(c.LastAccessedAt.Int64, 0),
-				AccessCount:    c.AccessCount.Int64,
-				CreatedAt:      time.Unix(c.CreatedAt, 0),
-			},
+			Memory:     fromSearchRow(c),
 			Similarity: similarity,
 			Distance:   embedding.CosineDistance(queryEmb, memEmb),
+			Mode:       SearchModeSemantic,
 		})
 	}
 
-	// Sort by similarity (descending)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Similarity > results[j].Similarity
+	return results
+}
+
+// keywordSearch scores candidates with BM25. In hybrid mode the keyword
+// coverage is blended with vector similarity for memories that have embeddings.
+func (s *Service) keywordSearch(ctx context.Context, query string, opts SearchOptions, mode SearchMode) ([]SearchResult, error) {
+	var queryEmb []float32
+	if mode == SearchModeHybrid {
+		emb, err := s.embedder.Embed(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		queryEmb = emb
+	}
+
+	rows, err := s.queries.GetMemoriesForKeywordSearch(ctx, db.GetMemoriesForKeywordSearchParams{
+		AgentHandle: toNullString(opts.AgentHandle),
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Apply limit
-	if len(results) > opts.Limit {
-		results = results[:opts.Limit]
+	// Category filtering happens before scoring so IDF reflects the searched corpus
+	var candidates []db.GetMemoriesForSearchRow
+	for _, r := range rows {
+		if matchesCategories(r.Category, opts.Categories) && PathApplies(fromNullString(r.PathScope), opts.PathScope) {
+			candidates = append(candidates, db.GetMemoriesForSearchRow(r))
+		}
 	}
 
-	// Update access timestamps for returned results
-	now := time.Now().Unix()
-	for _, r := range results {
-		_ = s.queries.UpdateMemoryAccess(ctx, db.UpdateMemoryAccessParams{
-			LastAccessedAt: sql.NullInt64{Int64: now, Valid: true},
-			ID:             r.Memory.ID,
+	docs := make([]string, len(candidates))
+	for i, c := range candidates {
+		docs[i] = c.Content
+	}
+	scorer := newKeywordScorer(query, docs)
+
+	type scored struct {
+		result SearchResult
+		bm25   float64
+	}
+	var hits []scored
+	for i, c := range candidates {
+		kw := scorer.Score(i)
+		similarity := kw.Coverage
+
+		var distance float32 = 1 - kw.Coverage
+		if mode == SearchModeHybrid {
+			memEmb := embedding.DeserializeFloat32(c.Embedding)
+			vector := embedding.CosineSimilarity(queryEmb, memEmb)
+			similarity = (1-HybridKeywordWeight)*vector + HybridKeywordWeight*kw.Coverage
+			distance = 1 - similarity
+		} else if kw.BM25 == 0 {
+			continue
+		}
+
+		if similarity < opts.Threshold {
+			continue
+		}
+
+		hits = append(hits, scored{
+			result: SearchResult{
+				Memory:     fromSearchRow(c),
+				Similarity: similarity,
+				Distance:   distance,
+				Mode:       mode,
+			},
+			bm25: kw.BM25,
 		})
 	}
 
+	// Rank by BM25 within equal similarity so stronger keyword matches win ties
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].bm25 > hits[j].bm25
+	})
+
+	results := make([]SearchResult, len(hits))
+	for i, h := range hits {
+		results[i] = h.result
+	}
 	return results, nil
 }
 
+// matchesCategories reports whether category is in the filter (empty filter matches all).
+func matchesCategories(category string, filter []Category) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, cat := range filter {
+		if string(cat) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// fromSearchRow converts a search candidate row to a Memory.
+func fromSearchRow(c db.GetMemoriesForSearchRow) Memory {
+	return Memory{
+		ID:             c.ID,
+		AgentHandle:    fromNullString(c.AgentHandle),
+		PathScope:      fromNullString(c.PathScope),
+		Content:        c.Content,
+		Category:       Category(c.Category),
+		Embedding:      embedding.DeserializeFloat32(c.Embedding),
+		Confidence:     c.Confidence.Float64,
+		LastAccessedAt: time.Unix(c.LastAccessedAt.Int64, 0),
+		AccessCount:    c.AccessCount.Int64,
+		CreatedAt:      time.Unix(c.CreatedAt, 0),
+	}
+}
+
 // List returns memories with optional filtering.
 func (s *Service) List(ctx context.Context, agentHandle string, limit, offset int64) ([]Memory, error) {
 	var dbMems []db.Memory
@@ -366,6 +578,88 @@ func (s *Service) List(ctx context.Context, agentHandle string, limit, offset in
 	return memories, nil
 }
 
+// ChangedSince returns the memories created, edited, superseded, or
+// forgotten at or after since, in the order they changed. Times are stored
+// to the second.
+func (s *Service) ChangedSince(ctx context.Context, since time.Time) ([]Memory, error) {
+	dbMems, err := s.queries.ListMemoriesChangedSince(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	memories := make([]Memory, len(dbMems))
+	for i, m := range dbMems {
+		memories[i] = fromDBMemory(m)
+	}
+	return memories, nil
+}
+
+// All returns every active memory, for an agent or for all agents when
+// agentHandle is empty.
+func (s *Service) All(ctx context.Context, agentHandle string) ([]Memory, error) {
+	const pageSize = 500
+	var all []Memory
+	for offset := int64(0); ; offset += pageSize {
+		page, err := s.List(ctx, agentHandle, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// ErrNoEmbedding is returned when a memory has no stored embedding, for
+// example because it was stored before an embedder was available.
+var ErrNoEmbedding = errors.New("memory has no embedding")
+
+// Nearest returns the active memories most similar to the memory with the
+// given ID, scored by cosine similarity of their stored embeddings; nothing
+// is re-embedded. Candidates without a comparable embedding are skipped, and
+// their number is returned alongside the results.
+func (s *Service) Nearest(ctx context.Context, id string, opts SearchOptions) ([]SearchResult, int, error) {
+	target, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(target.Embedding) == 0 {
+		return nil, 0, ErrNoEmbedding
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = 10
+	}
+
+	rows, err := s.queries.GetMemoriesForKeywordSearch(ctx, db.GetMemoriesForKeywordSearchParams{
+		AgentHandle: toNullString(opts.AgentHandle),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Embeddings from a different model can't be compared
+	var candidates []db.GetMemoriesForSearchRow
+	skipped := 0
+	for _, r := range rows {
+		if r.ID == target.ID {
+			continue
+		}
+		if len(embedding.DeserializeFloat32(r.Embedding)) != len(target.Embedding) {
+			skipped++
+			continue
+		}
+		candidates = append(candidates, db.GetMemoriesForSearchRow(r))
+	}
+
+	results := scoreCandidates(target.Embedding, candidates, opts)
+	rank(results, RankingWeights{}, time.Now())
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, skipped, nil
+}
+
 // Count returns the total number of active memories.
 func (s *Service) Count(ctx context.Context, agentHandle string) (int64, error) {
 	if agentHandle != "" {
@@ -377,6 +671,115 @@ func (s *Service) Count(ctx context.Context, agentHandle string) (int64, error)
 	return s.queries.CountMemories(ctx, sql.NullString{})
 }
 
+// AgentCounts returns the number of active memories per agent, most first.
+// Global memories are counted under an empty key.
+func (s *Service) AgentCounts(ctx context.Context) ([]StatCount, error) {
+	rows, err := s.queries.CountMemoriesPerAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]StatCount, len(rows))
+	for i, r := range rows {
+		counts[i] = StatCount{Key: r.AgentHandle, Count: r.Count}
+	}
+	return counts, nil
+}
+
+// ListByAgents returns up to limit active memories of the given agents,
+// newest first.
+func (s *Service) ListByAgents(ctx context.Context, agentHandles []string, limit int64) ([]Memory, error) {
+	var memories []Memory
+	for _, handle := range agentHandles {
+		page, err := s.List(ctx, handle, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, page...)
+	}
+	sort.SliceStable(memories, func(i, j int) bool {
+		return memories[i].CreatedAt.After(memories[j].CreatedAt)
+	})
+	if int64(len(memories)) > limit {
+		memories = memories[:limit]
+	}
+	return memories, nil
+}
+
+// StatCount is the number of memories sharing a status, category, or agent.
+type StatCount struct {
+	Key   string // Empty agent = global memories
+	Count int64
+}
+
+// Stats summarizes stored memories. Counts by category and agent, the
+// average confidence, and the timestamps cover active memories only.
+type Stats struct {
+	Active        int64
+	ByStatus      []StatCount // All memories, including superseded and forgotten
+	ByCategory    []StatCount
+	ByAgent       []StatCount
+	AvgConfidence float64
+	Oldest        time.Time // Zero when there are no active memories
+	Newest        time.Time
+	StorageBytes  int64    // Content and embeddings of all memories
+	MostAccessed  []Memory // Up to topN, most accessed first
+}
+
+// Stats returns aggregate statistics with the topN most accessed memories.
+func (s *Service) Stats(ctx context.Context, topN int64) (Stats, error) {
+	var stats Stats
+
+	statuses, err := s.queries.CountMemoriesByStatus(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	for _, r := range statuses {
+		stats.ByStatus = append(stats.ByStatus, StatCount{Key: r.Status, Count: r.Count})
+		if r.Status == string(StatusActive) {
+			stats.Active = r.Count
+		}
+	}
+
+	categories, err := s.queries.CountMemoriesByCategory(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	for _, r := range categories {
+		stats.ByCategory = append(stats.ByCategory, StatCount{Key: r.Category, Count: r.Count})
+	}
+
+	agents, err := s.queries.CountMemoriesPerAgent(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	for _, r := range agents {
+		stats.ByAgent = append(stats.ByAgent, StatCount{Key: r.AgentHandle, Count: r.Count})
+	}
+
+	agg, err := s.queries.GetMemoryStats(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats.AvgConfidence = agg.AvgConfidence
+	stats.StorageBytes = agg.StorageBytes
+	if stats.Active > 0 {
+		stats.Oldest = time.Unix(agg.OldestCreatedAt, 0)
+		stats.Newest = time.Unix(agg.NewestCreatedAt, 0)
+	}
+
+	if topN > 0 {
+		rows, err := s.queries.ListMostAccessedMemories(ctx, topN)
+		if err != nil {
+			return Stats{}, err
+		}
+		for _, r := range rows {
+			stats.MostAccessed = append(stats.MostAccessed, fromDBMemory(r))
+		}
+	}
+
+	return stats, nil
+}
+
 // Clear removes all memories for an agent (or all if agentHandle is empty).
 func (s *Service) Clear(ctx context.Context, agentHandle string) error {
 	now := time.Now().Unix()
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/alexcabrera/ayo/internal/db"
@@ -138,6 +139,42 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestSearchPathScope(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, m := range []Memory{
+		{Content: "Use tabs in this repo", Category: CategoryPreference, PathScope: "/work/app"},
+		{Content: "Use spaces in that repo", Category: CategoryPreference, PathScope: "/work/other"},
+		{Content: "Use short commit messages", Category: CategoryPreference},
+	} {
+		if _, err := svc.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	for _, mode := range []SearchMode{SearchModeSemantic, SearchModeKeyword} {
+		results, err := svc.Search(ctx, "tabs commit", SearchOptions{
+			PathScope: "/work/app/internal",
+			Threshold: 0.0,
+			Limit:     10,
+			Mode:      mode,
+		})
+		if err != nil {
+			t.Fatalf("%s search failed: %v", mode, err)
+		}
+		got := map[string]bool{}
+		for _, r := range results {
+			got[r.Memory.Content] = true
+		}
+		if !got["Use tabs in this repo"] || !got["Use short commit messages"] || got["Use spaces in that repo"] {
+			t.Errorf("%s search from a subdirectory returned %v", mode, got)
+		}
+	}
+}
+
 func TestSupersede(t *testing.T) {
 	svc, cleanup := setupTestService(t)
 	defer cleanup()
@@ -269,6 +306,44 @@ func TestListAndCount(t *testing.T) {
 	}
 }
 
+func TestListByAgents(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, handle := range []string{"@team.foo", "@team.foo", "@team.bar", "@other", ""} {
+		if _, err := svc.Create(ctx, Memory{Content: "Memory for " + handle, Category: CategoryFact, AgentHandle: handle}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	counts, err := svc.AgentCounts(ctx)
+	if err != nil {
+		t.Fatalf("AgentCounts failed: %v", err)
+	}
+	if len(counts) != 4 || counts[0].Key != "@team.foo" || counts[0].Count != 2 {
+		t.Errorf("AgentCounts = %+v", counts)
+	}
+
+	mems, err := svc.ListByAgents(ctx, []string{"@team.foo", "@team.bar"}, 100)
+	if err != nil {
+		t.Fatalf("ListByAgents failed: %v", err)
+	}
+	if len(mems) != 3 {
+		t.Fatalf("Expected 3 memories, got %d", len(mems))
+	}
+	for i := 1; i < len(mems); i++ {
+		if mems[i].CreatedAt.After(mems[i-1].CreatedAt) {
+			t.Errorf("memories not newest first: %v after %v", mems[i].CreatedAt, mems[i-1].CreatedAt)
+		}
+	}
+
+	if mems, _ := svc.ListByAgents(ctx, []string{"@team.foo", "@team.bar"}, 2); len(mems) != 2 {
+		t.Errorf("Expected the limit to apply across agents, got %d", len(mems))
+	}
+}
+
 func TestClear(t *testing.T) {
 	svc, cleanup := setupTestService(t)
 	defer cleanup()
@@ -299,3 +374,168 @@ func TestClear(t *testing.T) {
 		t.Errorf("Expected 0 memories after clear, got %d", count)
 	}
 }
+
+func TestSearchBatch(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for _, m := range []Memory{
+		{Content: "User likes Go programming language", Category: CategoryFact},
+		{Content: "User prefers dark theme", Category: CategoryPreference},
+	} {
+		if _, err := svc.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	queries := []string{"User prefers dark theme", "User likes Go programming language"}
+	batch, err := svc.SearchBatch(ctx, queries, SearchOptions{Threshold: 0.99, Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchBatch failed: %v", err)
+	}
+	if len(batch) != len(queries) {
+		t.Fatalf("expected %d results, got %d", len(queries), len(batch))
+	}
+
+	for i, b := range batch {
+		if b.Query != queries[i] {
+			t.Errorf("batch[%d].Query = %q, want %q", i, b.Query, queries[i])
+		}
+		if len(b.Embedding) != 384 {
+			t.Errorf("batch[%d] embedding has %d dims, want 384", i, len(b.Embedding))
+		}
+		if len(b.Results) == 0 || b.Results[0].Memory.Content != queries[i] {
+			t.Errorf("batch[%d] top result should be the exact match, got %+v", i, b.Results)
+		}
+	}
+
+	// SearchBatch is semantic only
+	if _, err := NewService(svc.queries, nil).SearchBatch(ctx, queries, SearchOptions{}); err == nil {
+		t.Error("expected error without embedder")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	target, _ := svc.Create(ctx, Memory{Content: "target", Category: CategoryFact, Embedding: []float32{1, 0, 0}})
+	near, _ := svc.Create(ctx, Memory{Content: "close", Category: CategoryFact, Embedding: []float32{0.9, 0.1, 0}})
+	far, _ := svc.Create(ctx, Memory{Content: "far", Category: CategoryFact, Embedding: []float32{0, 1, 0}})
+	// Stored before an embedder was available, and from another model
+	NewService(svc.queries, nil).Create(ctx, Memory{Content: "no embedding", Category: CategoryFact})
+	svc.Create(ctx, Memory{Content: "other model", Category: CategoryFact, Embedding: []float32{1, 0}})
+
+	results, skipped, err := svc.Nearest(ctx, target.ID, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Nearest failed: %v", err)
+	}
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	if len(results) != 2 || results[0].Memory.ID != near.ID || results[1].Memory.ID != far.ID {
+		t.Fatalf("results = %+v, want near then far", results)
+	}
+
+	results, _, _ = svc.Nearest(ctx, target.ID, SearchOptions{Threshold: 0.5})
+	if len(results) != 1 {
+		t.Errorf("threshold: got %d results, want 1", len(results))
+	}
+}
+
+func TestNearestWithoutEmbedding(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	m, _ := NewService(svc.queries, nil).Create(ctx, Memory{Content: "no embedding", Category: CategoryFact})
+	if _, _, err := svc.Nearest(ctx, m.ID, SearchOptions{}); !errors.Is(err, ErrNoEmbedding) {
+		t.Errorf("err = %v, want ErrNoEmbedding", err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		svc.Create(ctx, Memory{Content: "memory", Category: CategoryFact, AgentHandle: "@ayo"})
+	}
+	svc.Create(ctx, Memory{Content: "global", Category: CategoryFact})
+
+	all, err := svc.All(ctx, "")
+	if err != nil || len(all) != 4 {
+		t.Errorf("All = %d memories, %v; want 4", len(all), err)
+	}
+	mine, _ := svc.All(ctx, "@ayo")
+	if len(mine) != 3 {
+		t.Errorf("All(@ayo) = %d memories, want 3", len(mine))
+	}
+}
+
+func TestStats(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	empty, err := svc.Stats(ctx, 5)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if empty.Active != 0 || !empty.Oldest.IsZero() || len(empty.MostAccessed) != 0 {
+		t.Errorf("empty stats = %+v", empty)
+	}
+
+	a, _ := svc.Create(ctx, Memory{Content: "likes go", Category: CategoryPreference, AgentHandle: "@ayo", Confidence: 0.5})
+	b, _ := svc.Create(ctx, Memory{Content: "uses vim", Category: CategoryFact, AgentHandle: "@ayo"})
+	svc.Create(ctx, Memory{Content: "global fact", Category: CategoryFact})
+	svc.Supersede(ctx, b.ID, Memory{Content: "uses helix", Category: CategoryFact, AgentHandle: "@ayo"}, "switched")
+	for i := 0; i < 2; i++ {
+		svc.queries.UpdateMemoryAccess(ctx, db.UpdateMemoryAccessParams{ID: a.ID})
+	}
+
+	stats, err := svc.Stats(ctx, 5)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Active != 3 {
+		t.Errorf("Active = %d, want 3", stats.Active)
+	}
+	if got := statCount(stats.ByStatus, "superseded"); got != 1 {
+		t.Errorf("superseded = %d, want 1", got)
+	}
+	if got := statCount(stats.ByCategory, "fact"); got != 2 {
+		t.Errorf("fact = %d, want 2", got)
+	}
+	if got := statCount(stats.ByAgent, "@ayo"); got != 2 {
+		t.Errorf("@ayo = %d, want 2", got)
+	}
+	if got := statCount(stats.ByAgent, ""); got != 1 {
+		t.Errorf("global = %d, want 1", got)
+	}
+	if want := (0.5 + 1 + 1) / 3; stats.AvgConfidence < want-0.001 || stats.AvgConfidence > want+0.001 {
+		t.Errorf("AvgConfidence = %v, want %v", stats.AvgConfidence, want)
+	}
+	if stats.Oldest.IsZero() || stats.Newest.Before(stats.Oldest) {
+		t.Errorf("Oldest = %v, Newest = %v", stats.Oldest, stats.Newest)
+	}
+	if stats.StorageBytes <= 0 {
+		t.Errorf("StorageBytes = %d, want > 0", stats.StorageBytes)
+	}
+	if len(stats.MostAccessed) != 1 || stats.MostAccessed[0].ID != a.ID || stats.MostAccessed[0].AccessCount != 2 {
+		t.Errorf("MostAccessed = %+v, want only %s", stats.MostAccessed, a.ID)
+	}
+}
+
+func statCount(counts []StatCount, key string) int64 {
+	for _, c := range counts {
+		if c.Key == key {
+			return c.Count
+		}
+	}
+	return 0
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/embedding"
+	"github.com/google/uuid"
+)
+
+// Proposal is an extracted memory waiting for review. Proposals persist
+// until they are accepted or rejected.
+type Proposal struct {
+	ID                 string
+	AgentHandle        string // Empty for global memories
+	Content            string
+	Category           Category
+	Embedding          []float32 // Embedding of Content, empty if unknown
+	SourceSessionID    string
+	SupersedesID       string // Memory to supersede when accepted, if any
+	SupersessionReason string
+	CreatedAt          time.Time
+}
+
+// WithContent returns p with edited content. The embedding is dropped when
+// the content changes so accepting the proposal embeds the new content.
+func (p Proposal) WithContent(content string) Proposal {
+	if content != p.Content {
+		p.Content = content
+		p.Embedding = nil
+	}
+	return p
+}
+
+// Propose queues a memory for review instead of storing it.
+func (s *Service) Propose(ctx context.Context, p Proposal) (Proposal, error) {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	if p.Category == "" {
+		p.Category = CategoryFact
+	}
+	p.CreatedAt = time.Now()
+
+	err := s.queries.CreateMemoryProposal(ctx, db.CreateMemoryProposalParams{
+		ID:                 p.ID,
+		AgentHandle:        toNullString(p.AgentHandle),
+		Content:            p.Content,
+		Category:           string(p.Category),
+		Embedding:          embedding.SerializeFloat32(p.Embedding),
+		SourceSessionID:    toNullString(p.SourceSessionID),
+		SupersedesID:       toNullString(p.SupersedesID),
+		SupersessionReason: toNullString(p.SupersessionReason),
+		CreatedAt:          p.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return Proposal{}, err
+	}
+	return p, nil
+}
+
+// ListProposals returns the proposals waiting for review, oldest first.
+// An empty agentHandle lists proposals for every agent.
+func (s *Service) ListProposals(ctx context.Context, agentHandle string) ([]Proposal, error) {
+	var rows []db.MemoryProposal
+	var err error
+	if agentHandle != "" {
+		rows, err = s.queries.ListMemoryProposalsByAgent(ctx, toNullString(agentHandle))
+	} else {
+		rows, err = s.queries.ListMemoryProposals(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fromDBProposals(rows), nil
+}
+
+// ProposalsSince returns the proposals queued at or after since, oldest
+// first.
+func (s *Service) ProposalsSince(ctx context.Context, since time.Time) ([]Proposal, error) {
+	rows, err := s.queries.ListMemoryProposalsSince(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return fromDBProposals(rows), nil
+}
+
+func fromDBProposals(rows []db.MemoryProposal) []Proposal {
+	proposals := make([]Proposal, len(rows))
+	for i, row := range rows {
+		proposals[i] = Proposal{
+			ID:                 row.ID,
+			AgentHandle:        row.AgentHandle.String,
+			Content:            row.Content,
+			Category:           Category(row.Category),
+			Embedding:          embedding.DeserializeFloat32(row.Embedding),
+			SourceSessionID:    row.SourceSessionID.String,
+			SupersedesID:       row.SupersedesID.String,
+			SupersessionReason: row.SupersessionReason.String,
+			CreatedAt:          time.Unix(row.CreatedAt, 0),
+		}
+	}
+	return proposals
+}
+
+// AcceptProposal stores p as a memory and removes it from the review queue.
+// It supersedes p.SupersedesID if that memory is still active, and creates
+// a new memory otherwise.
+func (s *Service) AcceptProposal(ctx context.Context, p Proposal) (Memory, error) {
+	mem := Memory{
+		Content:         p.Content,
+		Category:        p.Category,
+		AgentHandle:     p.AgentHandle,
+		Embedding:       p.Embedding,
+		SourceSessionID: p.SourceSessionID,
+	}
+
+	supersede := false
+	if p.SupersedesID != "" {
+		target, err := s.Get(ctx, p.SupersedesID)
+		supersede = err == nil && target.Status == StatusActive
+	}
+
+	var created Memory
+	var err error
+	if supersede {
+		created, err = s.Supersede(ctx, p.SupersedesID, mem, p.SupersessionReason)
+	} else {
+		created, err = s.Create(ctx, mem)
+	}
+	if err != nil {
+		return Memory{}, err
+	}
+
+	if err := s.queries.DeleteMemoryProposal(ctx, p.ID); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// RejectProposal removes a proposal without storing it.
+func (s *Service) RejectProposal(ctx context.Context, id string) error {
+	return s.queries.DeleteMemoryProposal(ctx, id)
+}
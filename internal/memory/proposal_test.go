@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProposals(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	old, err := svc.Create(ctx, Memory{Content: "Uses npm", Category: CategoryPreference, AgentHandle: "@ayo"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	replace, err := svc.Propose(ctx, Proposal{
+		AgentHandle:        "@ayo",
+		Content:            "Uses pnpm",
+		Category:           CategoryPreference,
+		SupersedesID:       old.ID,
+		SupersessionReason: "switched package manager",
+	})
+	if err != nil || replace.ID == "" {
+		t.Fatalf("Propose = %+v, %v", replace, err)
+	}
+	other, err := svc.Propose(ctx, Proposal{AgentHandle: "@other", Content: "Deploys on Fridays"})
+	if err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if other.Category != CategoryFact {
+		t.Errorf("default category = %q, want fact", other.Category)
+	}
+
+	// Proposals are queued, not stored as memories
+	if n, _ := svc.Count(ctx, ""); n != 1 {
+		t.Errorf("Count = %d after proposing, want 1", n)
+	}
+	all, err := svc.ListProposals(ctx, "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("ListProposals = %v, %v; want 2 proposals", all, err)
+	}
+	mine, err := svc.ListProposals(ctx, "@ayo")
+	if err != nil || len(mine) != 1 || mine[0].SupersedesID != old.ID {
+		t.Fatalf("ListProposals(@ayo) = %+v, %v", mine, err)
+	}
+
+	// Accepting an edited proposal supersedes the matched memory
+	created, err := svc.AcceptProposal(ctx, mine[0].WithContent("Uses pnpm everywhere"))
+	if err != nil {
+		t.Fatalf("AcceptProposal: %v", err)
+	}
+	if created.Content != "Uses pnpm everywhere" || created.SupersedesID != old.ID || len(created.Embedding) == 0 {
+		t.Errorf("accepted memory = %+v", created)
+	}
+	if got, _ := svc.Get(ctx, old.ID); got.Status != StatusSuperseded {
+		t.Errorf("old memory status = %q, want superseded", got.Status)
+	}
+
+	if err := svc.RejectProposal(ctx, other.ID); err != nil {
+		t.Fatalf("RejectProposal: %v", err)
+	}
+	if left, _ := svc.ListProposals(ctx, ""); len(left) != 0 {
+		t.Errorf("proposals left = %+v, want none", left)
+	}
+}
+
+func TestAcceptProposalWithoutActiveTarget(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	old, err := svc.Create(ctx, Memory{Content: "Uses npm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := svc.Propose(ctx, Proposal{Content: "Uses pnpm", SupersedesID: old.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Forget(ctx, old.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The target was forgotten after proposing, so the memory is created new
+	created, err := svc.AcceptProposal(ctx, p)
+	if err != nil {
+		t.Fatalf("AcceptProposal: %v", err)
+	}
+	if created.SupersedesID != "" {
+		t.Errorf("SupersedesID = %q, want a new memory", created.SupersedesID)
+	}
+	if got, _ := svc.Get(ctx, old.ID); got.Status != StatusForgotten {
+		t.Errorf("forgotten memory status = %q, want it untouched", got.Status)
+	}
+}
+
+func TestProposalWithContent(t *testing.T) {
+	p := Proposal{Content: "a", Embedding: []float32{1}}
+	if got := p.WithContent("a"); len(got.Embedding) != 1 {
+		t.Error("unchanged content dropped the embedding")
+	}
+	if got := p.WithContent("b"); got.Content != "b" || got.Embedding != nil {
+		t.Errorf("WithContent(b) = %+v, want new content without embedding", got)
+	}
+}
@@ -25,8 +25,10 @@ type Queue struct {
 	requests   chan QueueRequest
 	onStatus   func(ui.AsyncStatusMsg)
 	wg         sync.WaitGroup
-	ctx        context.Context
+	ctx        context.Context // Cancelled to stop the worker after draining
 	cancel     context.CancelFunc
+	work       context.Context // Parent of each request; cancelled to abort
+	abort      context.CancelFunc
 	bufferSize int
 }
 
@@ -44,6 +46,7 @@ func NewQueue(service *Service, cfg QueueConfig) *Queue {
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	work, abort := context.WithCancel(context.Background())
 
 	return &Queue{
 		service:    service,
@@ -51,6 +54,8 @@ func NewQueue(service *Service, cfg QueueConfig) *Queue {
 		onStatus:   cfg.OnStatus,
 		ctx:        ctx,
 		cancel:     cancel,
+		work:       work,
+		abort:      abort,
 		bufferSize: bufferSize,
 	}
 }
@@ -93,8 +98,19 @@ func (q *Queue) Start() {
 func (q *Queue) Stop(timeout time.Duration) {
 	// Signal shutdown
 	q.cancel()
+	q.wait(timeout)
+}
+
+// Abort stops the queue without storing pending items. A request being
+// stored is cancelled, and Abort waits up to timeout for it to return.
+func (q *Queue) Abort(timeout time.Duration) {
+	q.abort()
+	q.cancel()
+	q.wait(timeout)
+}
 
-	// Wait with timeout
+// wait blocks until the worker exits or timeout expires.
+func (q *Queue) wait(timeout time.Duration) {
 	done := make(chan struct{})
 	go func() {
 		q.wg.Wait()
@@ -145,6 +161,11 @@ func (q *Queue) drain() {
 
 // processRequest handles a single memory request.
 func (q *Queue) processRequest(req QueueRequest) {
+	if q.work.Err() != nil {
+		q.sendStatus(req.ID, ui.AsyncStatusFailed, "Memory not stored: queue aborted")
+		return
+	}
+
 	// Guard against nil service (shouldn't happen in production)
 	if q.service == nil {
 		q.sendStatus(req.ID, ui.AsyncStatusFailed, "Memory service not available")
@@ -155,7 +176,7 @@ func (q *Queue) processRequest(req QueueRequest) {
 	q.sendStatus(req.ID, ui.AsyncStatusInProgress, "Storing memory...")
 
 	// Create the memory (this includes embedding generation)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(q.work, 30*time.Second)
 	defer cancel()
 
 	_, err := q.service.Create(ctx, Memory{
@@ -135,3 +135,26 @@ func TestQueue_DrainOnStop(t *testing.T) {
 		t.Errorf("expected 0 pending after stop, got %d", q.Pending())
 	}
 }
+
+func TestQueue_Abort(t *testing.T) {
+	var mu sync.Mutex
+	var failed []string
+	q := NewQueue(nil, QueueConfig{
+		BufferSize: 10,
+		OnStatus: func(msg ui.AsyncStatusMsg) {
+			if msg.Status == ui.AsyncStatusFailed {
+				mu.Lock()
+				failed = append(failed, msg.Message)
+				mu.Unlock()
+			}
+		},
+	})
+	q.Abort(time.Second)
+	q.processRequest(QueueRequest{ID: "pending", Content: "test", Category: CategoryFact})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 1 || failed[0] != "Memory not stored: queue aborted" {
+		t.Errorf("failures = %q, want the pending item dropped", failed)
+	}
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHalfLifeDays is how long recency and confidence take to halve
+// when RankingWeights.HalfLifeDays is unset.
+const DefaultHalfLifeDays = 30
+
+// accessSaturation is the access count that scores 0.5 for the access
+// signal; the score approaches 1 as a memory keeps being used.
+const accessSaturation = 5
+
+// RankingWeights blend several signals into a search result's Score. Each
+// signal is in the 0-1 range, and the score is their weighted average. The
+// zero value ranks by similarity alone.
+type RankingWeights struct {
+	Similarity   float64 `json:"similarity,omitempty"`     // Similarity to the query
+	Confidence   float64 `json:"confidence,omitempty"`     // Confidence, halved every half-life since last access
+	Recency      float64 `json:"recency,omitempty"`        // Halved every half-life since the memory was created
+	AccessCount  float64 `json:"access_count,omitempty"`   // How often the memory has been retrieved
+	HalfLifeDays float64 `json:"half_life_days,omitempty"` // Default 30
+}
+
+// IsZero reports whether no signal is weighted, so results rank by
+// similarity alone.
+func (w RankingWeights) IsZero() bool {
+	return w.Similarity == 0 && w.Confidence == 0 && w.Recency == 0 && w.AccessCount == 0
+}
+
+// Validate checks that weights are not negative.
+func (w RankingWeights) Validate() error {
+	for _, f := range w.fields() {
+		if *f.value < 0 || math.IsNaN(*f.value) {
+			return fmt.Errorf("ranking weight %s must not be negative", f.name)
+		}
+	}
+	return nil
+}
+
+type weightField struct {
+	name  string
+	value *float64
+}
+
+// fields lists w's fields by their JSON names.
+func (w *RankingWeights) fields() []weightField {
+	return []weightField{
+		{"similarity", &w.Similarity},
+		{"confidence", &w.Confidence},
+		{"recency", &w.Recency},
+		{"access_count", &w.AccessCount},
+		{"half_life_days", &w.HalfLifeDays},
+	}
+}
+
+// Score blends r's signals as of now. With zero weights it is r's similarity.
+func (w RankingWeights) Score(r SearchResult, now time.Time) float32 {
+	total := w.Similarity + w.Confidence + w.Recency + w.AccessCount
+	if total == 0 {
+		return r.Similarity
+	}
+	halfLife := w.HalfLifeDays
+	if halfLife == 0 {
+		halfLife = DefaultHalfLifeDays
+	}
+
+	m := r.Memory
+	lastUsed := m.LastAccessedAt
+	if lastUsed.Before(m.CreatedAt) {
+		lastUsed = m.CreatedAt
+	}
+	access := float64(m.AccessCount)
+
+	score := w.Similarity*float64(r.Similarity) +
+		w.Confidence*m.Confidence*decay(now.Sub(lastUsed), halfLife) +
+		w.Recency*decay(now.Sub(m.CreatedAt), halfLife) +
+		w.AccessCount*access/(access+accessSaturation)
+	return float32(score / total)
+}
+
+// decay halves every halfLifeDays of age. Future times count as no age.
+func decay(age time.Duration, halfLifeDays float64) float64 {
+	days := max(age.Hours()/24, 0)
+	return math.Pow(0.5, days/halfLifeDays)
+}
+
+// rank sets each result's Score and sorts by it, keeping the order of
+// equal scores.
+func rank(results []SearchResult, w RankingWeights, now time.Time) {
+	for i := range results {
+		results[i].Score = w.Score(results[i], now)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
+
+// ParseRankingWeights parses weights written as comma-separated
+// name=value pairs, e.g. "similarity=0.7,recency=0.2,access_count=0.1".
+// Names are the JSON field names of RankingWeights.
+func ParseRankingWeights(s string) (RankingWeights, error) {
+	var w RankingWeights
+	fields := map[string]*float64{}
+	for _, f := range w.fields() {
+		fields[f.name] = f.value
+	}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return RankingWeights{}, fmt.Errorf("invalid weight %q: want name=value", pair)
+		}
+		field, known := fields[strings.TrimSpace(name)]
+		if !known {
+			return RankingWeights{}, fmt.Errorf("unknown weight %q: want similarity, confidence, recency, access_count, or half_life_days", name)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return RankingWeights{}, fmt.Errorf("invalid weight %q: %w", pair, err)
+		}
+		*field = v
+	}
+	return w, w.Validate()
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRankDefaultIsSimilarity(t *testing.T) {
+	now := time.Now()
+	results := []SearchResult{
+		{Memory: Memory{ID: "old", CreatedAt: now.AddDate(-1, 0, 0), Confidence: 0.2}, Similarity: 0.9},
+		{Memory: Memory{ID: "used", CreatedAt: now, Confidence: 1, AccessCount: 50}, Similarity: 0.6},
+	}
+	rank(results, RankingWeights{}, now)
+	if results[0].Memory.ID != "old" {
+		t.Errorf("zero weights ranked %s first, want the most similar", results[0].Memory.ID)
+	}
+	for _, r := range results {
+		if r.Score != r.Similarity {
+			t.Errorf("%s score = %v, want its similarity %v", r.Memory.ID, r.Score, r.Similarity)
+		}
+	}
+}
+
+func TestRankWeighted(t *testing.T) {
+	now := time.Now()
+	results := []SearchResult{
+		// Semantically close, but seen once long ago
+		{Memory: Memory{ID: "one-off", CreatedAt: now.AddDate(0, -6, 0), Confidence: 1}, Similarity: 0.8},
+		// Less similar, but recent and used often
+		{Memory: Memory{ID: "frequent", CreatedAt: now.AddDate(0, 0, -2), LastAccessedAt: now, Confidence: 1, AccessCount: 20}, Similarity: 0.6},
+	}
+	w := RankingWeights{Similarity: 0.5, Confidence: 0.2, Recency: 0.2, AccessCount: 0.1}
+	rank(results, w, now)
+	if results[0].Memory.ID != "frequent" {
+		t.Errorf("ranked %s first, want the recent, frequently used memory", results[0].Memory.ID)
+	}
+	if results[0].Similarity != 0.6 {
+		t.Errorf("similarity = %v, want the raw similarity kept", results[0].Similarity)
+	}
+	if results[0].Score <= 0 || results[0].Score > 1 {
+		t.Errorf("score = %v, want it in (0, 1]", results[0].Score)
+	}
+}
+
+func TestRankingWeightsDecay(t *testing.T) {
+	now := time.Now()
+	r := SearchResult{Memory: Memory{CreatedAt: now.AddDate(0, 0, -10), Confidence: 1}}
+	got := RankingWeights{Recency: 1, HalfLifeDays: 10}.Score(r, now)
+	if got < 0.49 || got > 0.51 {
+		t.Errorf("recency after one half-life = %v, want 0.5", got)
+	}
+}
+
+func TestParseRankingWeights(t *testing.T) {
+	w, err := ParseRankingWeights("similarity=0.7, recency=0.2,access_count=0.1,half_life_days=14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RankingWeights{Similarity: 0.7, Recency: 0.2, AccessCount: 0.1, HalfLifeDays: 14}
+	if w != want {
+		t.Errorf("weights = %+v, want %+v", w, want)
+	}
+
+	if w, err := ParseRankingWeights(""); err != nil || !w.IsZero() {
+		t.Errorf("empty weights = %+v, %v", w, err)
+	}
+	for _, bad := range []string{"similarity", "freshness=1", "recency=x", "recency=-1"} {
+		if _, err := ParseRankingWeights(bad); err == nil {
+			t.Errorf("ParseRankingWeights(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PathApplies reports whether a memory with the given path scope applies in
+// dir. Global memories (empty scope) apply everywhere; scoped ones apply in
+// their directory and its subdirectories. An empty dir matches every scope.
+func PathApplies(scope, dir string) bool {
+	if scope == "" || dir == "" {
+		return true
+	}
+	scope = filepath.Clean(scope)
+	dir = filepath.Clean(dir)
+	if scope == dir || scope == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(dir, scope+string(filepath.Separator))
+}
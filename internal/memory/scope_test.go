@@ -0,0 +1,24 @@
+package memory
+
+import "testing"
+
+func TestPathApplies(t *testing.T) {
+	tests := []struct {
+		scope, dir string
+		want       bool
+	}{
+		{"", "/home/me/proj", true},
+		{"/home/me/proj", "", true},
+		{"/home/me/proj", "/home/me/proj", true},
+		{"/home/me/proj/", "/home/me/proj/cmd/tool", true},
+		{"/", "/home/me/proj", true},
+		{"/home/me/proj", "/home/me/project", false},
+		{"/home/me/proj", "/home/me", false},
+		{"/home/me/proj", "/srv/proj", false},
+	}
+	for _, tt := range tests {
+		if got := PathApplies(tt.scope, tt.dir); got != tt.want {
+			t.Errorf("PathApplies(%q, %q) = %v, want %v", tt.scope, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// EventAction is what happened to a memory in a watch Event.
+type EventAction string
+
+const (
+	EventCreated    EventAction = "created"    // A new memory was stored
+	EventSuperseded EventAction = "superseded" // A memory was replaced by a newer one
+	EventUpdated    EventAction = "updated"    // A memory was edited
+	EventForgotten  EventAction = "forgotten"  // A memory was forgotten
+	EventArchived   EventAction = "archived"   // A memory was archived
+	EventProposed   EventAction = "proposed"   // A memory was queued for review
+)
+
+// Event is a change to the memory store seen by a Watcher.
+type Event struct {
+	Action EventAction
+	Time   time.Time
+	// Memory is the changed memory. For EventProposed it holds the
+	// proposal's ID, agent, content, category, and supersession.
+	Memory Memory
+}
+
+// Watcher polls the store for memory changes, so it sees changes made by
+// other processes. Duplicates that formation skipped aren't stored, so they
+// aren't seen.
+type Watcher struct {
+	svc   *Service
+	since time.Time
+
+	// What the last poll saw at or after since. Times are stored to the
+	// second, so the next poll reads since's second again and reports only
+	// what changed.
+	memories  map[string]memoryState
+	proposals map[string]time.Time
+}
+
+type memoryState struct {
+	updatedAt time.Time
+	status    Status
+	content   string
+	category  Category
+}
+
+func stateOf(m Memory) memoryState {
+	return memoryState{updatedAt: m.UpdatedAt, status: m.Status, content: m.Content, category: m.Category}
+}
+
+// Watch returns a Watcher for changes at or after since.
+func (s *Service) Watch(since time.Time) *Watcher {
+	return &Watcher{
+		svc:       s,
+		since:     since.Truncate(time.Second),
+		memories:  make(map[string]memoryState),
+		proposals: make(map[string]time.Time),
+	}
+}
+
+// Poll returns the changes since the last poll, oldest first.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	memories, err := w.svc.ChangedSince(ctx, w.since)
+	if err != nil {
+		return nil, err
+	}
+	proposals, err := w.svc.ProposalsSince(ctx, w.since)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	latest := w.since
+	for _, m := range memories {
+		latest = maxTime(latest, m.UpdatedAt)
+		prev, seen := w.memories[m.ID]
+		if seen && prev == stateOf(m) {
+			continue
+		}
+		w.memories[m.ID] = stateOf(m)
+		events = append(events, Event{Action: memoryAction(m, seen), Time: m.UpdatedAt, Memory: m})
+	}
+	for _, p := range proposals {
+		latest = maxTime(latest, p.CreatedAt)
+		if _, seen := w.proposals[p.ID]; seen {
+			continue
+		}
+		w.proposals[p.ID] = p.CreatedAt
+		events = append(events, Event{
+			Action: EventProposed,
+			Time:   p.CreatedAt,
+			Memory: Memory{
+				ID:                 p.ID,
+				AgentHandle:        p.AgentHandle,
+				Content:            p.Content,
+				Category:           p.Category,
+				SourceSessionID:    p.SourceSessionID,
+				CreatedAt:          p.CreatedAt,
+				UpdatedAt:          p.CreatedAt,
+				SupersedesID:       p.SupersedesID,
+				SupersessionReason: p.SupersessionReason,
+			},
+		})
+	}
+
+	// Forget what the next poll won't read again
+	w.since = latest
+	for id, s := range w.memories {
+		if s.updatedAt.Before(latest) {
+			delete(w.memories, id)
+		}
+	}
+	for id, t := range w.proposals {
+		if t.Before(latest) {
+			delete(w.proposals, id)
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b Event) int { return a.Time.Compare(b.Time) })
+	return events, nil
+}
+
+// memoryAction returns what happened to m, which changed since it was
+// last seen, if it was. A superseded memory is reported with its
+// SupersededByID, and the memory replacing it as created.
+func memoryAction(m Memory, seen bool) EventAction {
+	switch m.Status {
+	case StatusSuperseded:
+		return EventSuperseded
+	case StatusForgotten:
+		return EventForgotten
+	case StatusArchived:
+		return EventArchived
+	}
+	if !seen && m.UpdatedAt.Equal(m.CreatedAt) {
+		return EventCreated
+	}
+	return EventUpdated
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	w := svc.Watch(time.Now())
+
+	// poll checks the actions seen, in any order: changes in the same
+	// second may be read in either order.
+	poll := func(want ...EventAction) []Event {
+		t.Helper()
+		events, err := w.Poll(ctx)
+		if err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+		got := make([]EventAction, len(events))
+		for i, e := range events {
+			got[i] = e.Action
+		}
+		slices.Sort(got)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Poll = %v, want %v", got, want)
+		}
+		return events
+	}
+
+	poll()
+
+	old, err := svc.Create(ctx, Memory{Content: "Uses npm", Category: CategoryPreference, AgentHandle: "@ayo"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	events := poll(EventCreated)
+	if events[0].Memory.ID != old.ID || events[0].Memory.Category != CategoryPreference {
+		t.Errorf("event memory = %+v, want %s", events[0].Memory, old.ID)
+	}
+	// Changes are reported once, even within the same second
+	poll()
+
+	old.Content = "Uses npm for everything"
+	if err := svc.Update(ctx, old); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	poll(EventUpdated)
+
+	replacement, err := svc.Supersede(ctx, old.ID, Memory{Content: "Uses pnpm", Category: CategoryPreference}, "switched")
+	if err != nil {
+		t.Fatalf("Supersede: %v", err)
+	}
+	events = poll(EventCreated, EventSuperseded)
+	for _, e := range events {
+		if e.Action == EventSuperseded && (e.Memory.ID != old.ID || e.Memory.SupersededByID != replacement.ID) {
+			t.Errorf("superseded event = %+v, want %s replaced by %s", e.Memory, old.ID, replacement.ID)
+		}
+	}
+
+	if err := svc.Forget(ctx, replacement.ID); err != nil {
+		t.Fatalf("Forget: %v", err)
+	}
+	if _, err := svc.Propose(ctx, Proposal{Content: "Deploys on Fridays"}); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	for _, e := range poll(EventForgotten, EventProposed) {
+		if e.Action == EventProposed && (e.Memory.Content != "Deploys on Fridays" || e.Memory.Category != CategoryFact) {
+			t.Errorf("proposed event memory = %+v", e.Memory)
+		}
+	}
+	poll()
+}
+
+func TestWatcherSkipsEarlierChanges(t *testing.T) {
+	svc, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, Memory{Content: "Uses npm"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	w := svc.Watch(time.Now().Add(time.Second))
+
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Poll = %+v, want no events from before the watch", events)
+	}
+}
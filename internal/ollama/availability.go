@@ -0,0 +1,70 @@
+package ollama
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AvailabilityTTL is how long an IsAvailable result is reused before the
+// server is probed again.
+var AvailabilityTTL = 30 * time.Second
+
+// ErrUnavailable is returned without contacting the server when it was
+// recently found unavailable.
+var ErrUnavailable = errors.New("ollama unavailable")
+
+// availability caches IsAvailable results by host for every client in the
+// process.
+var availability = struct {
+	sync.Mutex
+	hosts map[string]availabilityResult
+}{hosts: make(map[string]availabilityResult)}
+
+type availabilityResult struct {
+	available bool
+	checked   time.Time
+}
+
+// CachedAvailability returns the last availability result for host and
+// whether it is recent enough to trust. It never contacts the server, so
+// callers can report an unavailable server without probing again.
+func CachedAvailability(host string) (available, known bool) {
+	availability.Lock()
+	defer availability.Unlock()
+	result, ok := availability.hosts[host]
+	if !ok || time.Since(result.checked) > AvailabilityTTL {
+		return false, false
+	}
+	return result.available, true
+}
+
+// InvalidateAvailability forgets the cached result for host, so the next
+// IsAvailable probes the server and requests are sent again.
+func InvalidateAvailability(host string) {
+	availability.Lock()
+	defer availability.Unlock()
+	delete(availability.hosts, host)
+}
+
+func rememberAvailability(host string, available bool) {
+	availability.Lock()
+	defer availability.Unlock()
+	availability.hosts[host] = availabilityResult{available: available, checked: time.Now()}
+}
+
+// do sends req. A server that can't be reached is remembered as
+// unavailable, and requests fail with ErrUnavailable without contacting it
+// until AvailabilityTTL passes.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if available, known := CachedAvailability(c.baseURL); known && !available {
+		return nil, fmt.Errorf("%w at %s", ErrUnavailable, c.baseURL)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil && req.Context().Err() == nil {
+		rememberAvailability(c.baseURL, false)
+	}
+	return resp, err
+}
@@ -0,0 +1,97 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsAvailableCachesResult(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithHost(server.URL))
+	for range 3 {
+		if !client.IsAvailable(context.Background()) {
+			t.Fatal("expected IsAvailable to return true")
+		}
+	}
+	// Other clients for the same host share the result
+	if !NewClient(WithHost(server.URL)).IsAvailable(context.Background()) {
+		t.Fatal("expected a new client to reuse the result")
+	}
+	if n := probes.Load(); n != 1 {
+		t.Errorf("probed %d times, want 1", n)
+	}
+
+	if available, known := CachedAvailability(server.URL); !available || !known {
+		t.Errorf("CachedAvailability = %v, %v; want true, true", available, known)
+	}
+
+	InvalidateAvailability(server.URL)
+	if _, known := CachedAvailability(server.URL); known {
+		t.Error("invalidated result should be unknown")
+	}
+	client.IsAvailable(context.Background())
+	if n := probes.Load(); n != 2 {
+		t.Errorf("probed %d times after invalidating, want 2", n)
+	}
+}
+
+func TestIsAvailableReprobesAfterTTL(t *testing.T) {
+	old := AvailabilityTTL
+	AvailabilityTTL = time.Millisecond
+	defer func() { AvailabilityTTL = old }()
+
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithHost(server.URL))
+	client.IsAvailable(context.Background())
+	time.Sleep(5 * time.Millisecond)
+	client.IsAvailable(context.Background())
+	if n := probes.Load(); n != 2 {
+		t.Errorf("probed %d times, want 2 once the result expired", n)
+	}
+}
+
+func TestFailedRequestMarksUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	host := server.URL
+	client := NewClient(WithHost(host))
+	if !client.IsAvailable(context.Background()) {
+		t.Fatal("expected IsAvailable to return true")
+	}
+	server.Close()
+	defer InvalidateAvailability(host)
+
+	// The first request that can't reach the server replaces the cached result
+	if _, err := client.ListModels(context.Background()); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("first request error = %v, want a connection error", err)
+	}
+	if available, known := CachedAvailability(host); available || !known {
+		t.Errorf("CachedAvailability = %v, %v; want false, true", available, known)
+	}
+	if client.IsAvailable(context.Background()) {
+		t.Error("IsAvailable should report the failed request")
+	}
+
+	// Later requests fail without contacting the server
+	if _, err := client.ListModels(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("second request error = %v, want ErrUnavailable", err)
+	}
+}
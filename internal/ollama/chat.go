@@ -68,7 +68,7 @@ func (c *Client) Chat(ctx context.Context, model string, messages []Message, opt
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("chat request: %w", err)
 	}
@@ -108,7 +108,7 @@ func (c *Client) ChatJSON(ctx context.Context, model string, messages []Message,
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("chat request: %w", err)
 	}
@@ -69,8 +69,20 @@ func NewClient(opts ...Option) *Client {
 	return c
 }
 
-// IsAvailable checks if the Ollama server is running and responding.
+// IsAvailable checks if the Ollama server is running and responding. The
+// result is reused for AvailabilityTTL; a request that can't reach the
+// server replaces it with unavailable.
 func (c *Client) IsAvailable(ctx context.Context) bool {
+	if available, known := CachedAvailability(c.baseURL); known {
+		return available
+	}
+	available := c.probe(ctx)
+	rememberAvailability(c.baseURL, available)
+	return available
+}
+
+// probe asks the server for its models to see whether it responds.
+func (c *Client) probe(ctx context.Context) bool {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/tags", nil)
 	if err != nil {
 		return false
@@ -105,7 +117,7 @@ func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("list models: %w", err)
 	}
@@ -160,7 +172,7 @@ func (c *Client) GetVersion(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", fmt.Errorf("get version: %w", err)
 	}
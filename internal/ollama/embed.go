@@ -55,7 +55,7 @@ func (c *Client) EmbedBatch(ctx context.Context, model string, texts []string) (
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("embed request: %w", err)
 	}
@@ -4,7 +4,8 @@
 //  1. ./.config/ayo (local project config)
 //  2. ./.local/share/ayo (local project data)
 //  3. ~/.config/ayo (user config)
-//  4. ~/.local/share/ayo (user data / built-ins)
+//  4. the AYO_BUNDLE bundle, when one is active
+//  5. ~/.local/share/ayo (user data / built-ins)
 //
 // For writes, ayo uses:
 //   - User agents/skills: ~/.config/ayo (or ./.config/ayo with --dev)
@@ -22,6 +23,8 @@ import (
 var (
 	devRoot     string
 	devRootOnce sync.Once
+
+	bundleDir string
 )
 
 // IsDevMode returns true if ayo is running from a source checkout.
@@ -209,6 +212,23 @@ func VersionFile() string {
 	return filepath.Join(DataDir(), ".builtin-version")
 }
 
+// BundlesDir returns the cache of downloaded AYO_BUNDLE bundles.
+// Location: ~/.local/share/ayo/bundles, one subdirectory per bundle digest.
+func BundlesDir() string {
+	return filepath.Join(DataDir(), "bundles")
+}
+
+// SetBundleDir makes dir the active bundle for lookups, or clears it when
+// dir is empty. ayo sets it once at startup when AYO_BUNDLE is set.
+func SetBundleDir(dir string) {
+	bundleDir = dir
+}
+
+// BundleDir returns the active bundle directory, or empty string if none.
+func BundleDir() string {
+	return bundleDir
+}
+
 // LocalConfigDir returns the local project config directory (./.config/ayo).
 // Returns empty string if not in a directory context or on Windows.
 func LocalConfigDir() string {
@@ -314,7 +334,7 @@ func DataDirs() []string {
 }
 
 // AgentsDirs returns all agent directories in lookup priority order.
-// Order: local config, local data, user config, user data (built-in).
+// Order: local config, local data, user config, bundle, user data (built-in).
 // Only includes directories that exist.
 func AgentsDirs() []string {
 	var dirs []string
@@ -331,13 +351,14 @@ func AgentsDirs() []string {
 	check(LocalConfigDir())
 	check(LocalDataDir())
 	check(UserConfigDir())
+	check(BundleDir())
 	check(UserDataDir())
 
 	return dirs
 }
 
 // SkillsDirs returns all skills directories in lookup priority order.
-// Order: local config, local data, user config, user data (built-in).
+// Order: local config, local data, user config, bundle, user data (built-in).
 // Only includes directories that exist.
 func SkillsDirs() []string {
 	var dirs []string
@@ -354,6 +375,7 @@ func SkillsDirs() []string {
 	check(LocalConfigDir())
 	check(LocalDataDir())
 	check(UserConfigDir())
+	check(BundleDir())
 	check(UserDataDir())
 
 	return dirs
@@ -372,30 +394,43 @@ func UserPromptsDir() string {
 	return filepath.Join(ConfigDir(), "prompts")
 }
 
-// FindPromptFile looks for a prompt file in priority order:
-// 1. ./.config/ayo/prompts/{name}
-// 2. ~/.config/ayo/prompts/{name}
-// 3. ./.local/share/ayo/prompts/{name}
-// 4. ~/.local/share/ayo/prompts/{name}
-// Returns empty string if not found.
-func FindPromptFile(name string) string {
-	// Priority order: local config, user config, local data, user data
+// FindPromptFiles returns every layer of a prompt file, broadest first:
+// 1. ~/.local/share/ayo/prompts/{name}
+// 2. ./.local/share/ayo/prompts/{name}
+// 3. {bundle}/prompts/{name}
+// 4. ~/.config/ayo/prompts/{name}
+// 5. ./.config/ayo/prompts/{name}
+// Layers that don't exist are skipped, so the result may be empty.
+func FindPromptFiles(name string) []string {
 	candidates := []string{
-		filepath.Join(LocalConfigDir(), "prompts", name),
-		filepath.Join(UserConfigDir(), "prompts", name),
-		filepath.Join(LocalDataDir(), "prompts", name),
 		filepath.Join(UserDataDir(), "prompts", name),
+		filepath.Join(LocalDataDir(), "prompts", name),
+		bundlePath("prompts", name),
+		filepath.Join(UserConfigDir(), "prompts", name),
+		filepath.Join(LocalConfigDir(), "prompts", name),
 	}
 
+	var found []string
+	seen := make(map[string]bool)
 	for _, path := range candidates {
-		if path == "" {
+		if path == "" || seen[path] {
 			continue
 		}
+		seen[path] = true
 		if _, err := os.Stat(path); err == nil {
-			return path
+			found = append(found, path)
 		}
 	}
-	return ""
+	return found
+}
+
+// bundlePath joins elem onto the active bundle directory, or returns empty
+// string if no bundle is active.
+func bundlePath(elem ...string) string {
+	if bundleDir == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{bundleDir}, elem...)...)
 }
 
 // DatabasePath returns the path to the SQLite database file.
@@ -565,7 +600,7 @@ func ProjectFlowsDir() string {
 }
 
 // FlowsDirs returns all flows directories in lookup priority order.
-// Order: project (.ayo/flows), user config, builtin.
+// Order: project (.ayo/flows), user config, bundle, builtin.
 // Only includes directories that exist.
 func FlowsDirs() []string {
 	var dirs []string
@@ -586,6 +621,9 @@ func FlowsDirs() []string {
 	// User flows (~/.config/ayo/flows)
 	add(UserFlowsDir())
 
+	// Bundle flows ({bundle}/flows)
+	add(bundlePath("flows"))
+
 	// Built-in flows (~/.local/share/ayo/flows)
 	add(BuiltinFlowsDir())
 
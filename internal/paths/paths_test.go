@@ -188,3 +188,88 @@ func TestWindowsPaths(t *testing.T) {
 		t.Errorf("Windows DataDir should contain 'ayo': got %s", dataDir)
 	}
 }
+
+func TestFindPromptFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("local prompt directories are not used on Windows")
+	}
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+
+	write := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("layer"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	userData := filepath.Join(home, ".local", "share", "ayo", "prompts", "system-prefix.md")
+	userConfig := filepath.Join(home, ".config", "ayo", "prompts", "system-prefix.md")
+	localConfig := filepath.Join(project, ".config", "ayo", "prompts", "system-prefix.md")
+	write(userData)
+	write(userConfig)
+	write(localConfig)
+
+	got := FindPromptFiles("system-prefix.md")
+	want := []string{userData, userConfig, localConfig}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FindPromptFiles = %q, want %q", got, want)
+	}
+	if got := FindPromptFiles("missing.md"); len(got) != 0 {
+		t.Errorf("FindPromptFiles(missing) = %q, want none", got)
+	}
+}
+
+func TestBundleDirPriority(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("local directories are not used on Windows")
+	}
+	home := t.TempDir()
+	project := t.TempDir()
+	bundle := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(project)
+	SetBundleDir(bundle)
+	t.Cleanup(func() { SetBundleDir("") })
+
+	localAgents := filepath.Join(project, ".config", "ayo", "agents")
+	bundleAgents := filepath.Join(bundle, "agents")
+	userAgents := filepath.Join(home, ".config", "ayo", "agents")
+	for _, dir := range []string{localAgents, bundleAgents, userAgents, filepath.Join(bundle, "prompts")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := AgentsDirs()
+	want := []string{localAgents, userAgents, bundleAgents}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("AgentsDirs = %q, want %q", got, want)
+	}
+
+	prefix := filepath.Join(bundle, "prompts", "system-prefix.md")
+	if err := os.WriteFile(prefix, []byte("bundle"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindPromptFiles("system-prefix.md"); strings.Join(got, "\n") != prefix {
+		t.Errorf("FindPromptFiles = %q, want bundle prompt %q", got, prefix)
+	}
+
+	// The user's own prompt layers over the bundle's
+	userPrefix := filepath.Join(home, ".config", "ayo", "prompts", "system-prefix.md")
+	if err := os.MkdirAll(filepath.Dir(userPrefix), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userPrefix, []byte("user"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got = FindPromptFiles("system-prefix.md")
+	want = []string{prefix, userPrefix}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FindPromptFiles = %q, want %q", got, want)
+	}
+}
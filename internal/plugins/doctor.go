@@ -0,0 +1,112 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProblemKind classifies a problem found with an installed plugin.
+type ProblemKind int
+
+const (
+	// ProblemMissingDir means the plugin directory is gone, leaving a
+	// dangling registry entry.
+	ProblemMissingDir ProblemKind = iota
+
+	// ProblemManifest means the manifest is missing or invalid.
+	ProblemManifest
+
+	// ProblemMissingContent means a registered agent, skill, or tool is
+	// missing from the plugin directory.
+	ProblemMissingContent
+
+	// ProblemMissingDep means a binary the plugin depends on is not in PATH.
+	ProblemMissingDep
+)
+
+// Problem is something wrong with an installed plugin.
+type Problem struct {
+	Kind   ProblemKind
+	Detail string
+	Fix    string // Suggested fix for the user
+}
+
+// Dangling reports whether the problem is a registry entry whose plugin
+// directory no longer exists.
+func (p Problem) Dangling() bool {
+	return p.Kind == ProblemMissingDir
+}
+
+// Diagnose checks an installed plugin: its directory exists, its manifest
+// parses, the agents, skills, and tools it registered are present, and the
+// binaries it depends on are installed. It returns nil for a healthy plugin.
+func Diagnose(p *InstalledPlugin) []Problem {
+	reinstall := "reinstall with: " + ReinstallCommand(p)
+
+	if info, err := os.Stat(p.Path); err != nil || !info.IsDir() {
+		return []Problem{{
+			Kind:   ProblemMissingDir,
+			Detail: p.Path + " is missing",
+			Fix:    fmt.Sprintf("%s, or remove the entry with: ayo plugins doctor --fix", reinstall),
+		}}
+	}
+
+	manifest, err := readManifest(p.Path)
+	if err != nil {
+		return []Problem{{Kind: ProblemManifest, Detail: err.Error(), Fix: reinstall}}
+	}
+
+	var problems []Problem
+	for _, dir := range []struct {
+		kind  string
+		names []string
+	}{
+		{"agents", p.Agents},
+		{"skills", p.Skills},
+		{"tools", p.Tools},
+	} {
+		for _, name := range dir.names {
+			if _, err := os.Stat(filepath.Join(p.Path, dir.kind, name)); err != nil {
+				problems = append(problems, Problem{
+					Kind:   ProblemMissingContent,
+					Detail: fmt.Sprintf("%s/%s is missing", dir.kind, name),
+					Fix:    reinstall,
+				})
+			}
+		}
+	}
+
+	for _, dep := range CheckMissingDependencies(manifest) {
+		problems = append(problems, Problem{
+			Kind:   ProblemMissingDep,
+			Detail: "missing binary: " + dep.Name,
+			Fix:    depFix(dep),
+		})
+	}
+
+	return problems
+}
+
+// ReinstallCommand returns the command that reinstalls p from its source.
+func ReinstallCommand(p *InstalledPlugin) string {
+	if local, ok := strings.CutPrefix(p.GitURL, "local:"); ok {
+		return "ayo plugins install --force --local " + local
+	}
+	return "ayo plugins install --force " + p.GitURL
+}
+
+// depFix suggests how to install a missing binary.
+func depFix(dep BinaryDep) string {
+	switch {
+	case dep.InstallCmd != "":
+		return "install with: " + dep.InstallCmd
+	case dep.InstallHint != "":
+		return dep.InstallHint
+	case dep.InstallURL != "":
+		return "see " + dep.InstallURL
+	default:
+		return "install " + dep.Name + " and make sure it is in PATH"
+	}
+}
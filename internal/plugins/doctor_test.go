@@ -0,0 +1,72 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{
+		"name": "test-plugin",
+		"version": "1.0.0",
+		"description": "A test plugin",
+		"agents": ["@test-agent"],
+		"skills": ["test-skill"],
+		"dependencies": {"binaries": [{"name": "ayo-no-such-binary", "install_cmd": "go install example.com/ayo-no-such-binary@latest"}]}
+	}`
+	os.MkdirAll(filepath.Join(dir, "agents", "@test-agent"), 0o755)
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &InstalledPlugin{
+		Name:   "test-plugin",
+		GitURL: "https://github.com/test/ayo-plugins-test-plugin",
+		Path:   dir,
+		Agents: []string{"@test-agent"},
+		Skills: []string{"test-skill"},
+	}
+	problems := Diagnose(p)
+	if len(problems) != 2 {
+		t.Fatalf("problems = %+v, want the missing skill and binary", problems)
+	}
+	if problems[0].Kind != ProblemMissingContent || problems[0].Detail != "skills/test-skill is missing" {
+		t.Errorf("problem = %+v, want the missing skill", problems[0])
+	}
+	if !strings.Contains(problems[0].Fix, "ayo plugins install --force https://github.com/test/ayo-plugins-test-plugin") {
+		t.Errorf("fix = %q, want the reinstall command", problems[0].Fix)
+	}
+	if problems[1].Kind != ProblemMissingDep || !strings.Contains(problems[1].Fix, "go install") {
+		t.Errorf("problem = %+v, want the missing binary with its install command", problems[1])
+	}
+
+	os.MkdirAll(filepath.Join(dir, "skills", "test-skill"), 0o755)
+	if problems := Diagnose(p); len(problems) != 1 {
+		t.Errorf("problems = %+v, want only the missing binary", problems)
+	}
+}
+
+func TestDiagnoseBrokenInstall(t *testing.T) {
+	dir := t.TempDir()
+
+	missing := &InstalledPlugin{Name: "gone", GitURL: "local:/src/gone", Path: filepath.Join(dir, "gone")}
+	problems := Diagnose(missing)
+	if len(problems) != 1 || !problems[0].Dangling() {
+		t.Fatalf("problems = %+v, want a dangling entry", problems)
+	}
+	if !strings.Contains(problems[0].Fix, "ayo plugins install --force --local /src/gone") {
+		t.Errorf("fix = %q, want a local reinstall", problems[0].Fix)
+	}
+
+	broken := &InstalledPlugin{Name: "broken", Path: dir}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	problems = Diagnose(broken)
+	if len(problems) != 1 || problems[0].Kind != ProblemManifest {
+		t.Errorf("problems = %+v, want an invalid manifest", problems)
+	}
+}
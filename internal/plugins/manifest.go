@@ -201,6 +201,21 @@ var versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[a-zA-Z0-9.]+)?(\+[a-zA
 
 // LoadManifest reads and validates a manifest from the given plugin directory.
 func LoadManifest(pluginDir string) (*Manifest, error) {
+	m, err := readManifest(pluginDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.ValidateContents(pluginDir); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// readManifest reads a manifest and validates its fields, without checking
+// the declared agents, skills, and tools exist.
+func readManifest(pluginDir string) (*Manifest, error) {
 	manifestPath := filepath.Join(pluginDir, ManifestFile)
 
 	data, err := os.ReadFile(manifestPath)
@@ -220,10 +235,6 @@ func LoadManifest(pluginDir string) (*Manifest, error) {
 		return nil, err
 	}
 
-	if err := m.ValidateContents(pluginDir); err != nil {
-		return nil, err
-	}
-
 	return &m, nil
 }
 
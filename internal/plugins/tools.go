@@ -100,6 +100,10 @@ type ToolParameter struct {
 // ToolFile is the expected filename for tool definitions.
 const ToolFile = "tool.json"
 
+// ToolRenderTemplate is the optional print-mode output template, relative
+// to the tool directory.
+const ToolRenderTemplate = "render/print.tmpl"
+
 // Tool definition errors
 var (
 	ErrToolDefNotFound      = errors.New("tool.json not found")
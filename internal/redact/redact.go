@@ -0,0 +1,100 @@
+// Package redact masks secrets in text before it is sent to a model provider.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Placeholder replaces every redacted secret.
+const Placeholder = "[REDACTED]"
+
+// secretGroup is the capture group name that limits redaction to part of a
+// match, so context such as "Authorization: Bearer" survives.
+const secretGroup = "secret"
+
+// builtinPatterns are the secret patterns checked unless disabled by name.
+var builtinPatterns = []struct {
+	name string
+	expr string
+}{
+	{"aws-access-key", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{"aws-secret-key", `(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`},
+	{"bearer-token", `(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{8,}=*)`},
+	{"private-key", `-----BEGIN[A-Z ]*PRIVATE KEY-----(?s:.*?)(?:-----END[A-Z ]*PRIVATE KEY-----|\z)`},
+	{"github-token", `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`},
+	{"api-key", `\bsk-(?:ant-|proj-)?[A-Za-z0-9_\-]{20,}`},
+	{"slack-token", `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+	{"google-api-key", `\bAIza[0-9A-Za-z_\-]{35}`},
+}
+
+// Builtins returns the names of the built-in patterns.
+func Builtins() []string {
+	names := make([]string, len(builtinPatterns))
+	for i, p := range builtinPatterns {
+		names[i] = p.name
+	}
+	return names
+}
+
+// Redactor replaces secrets matching a set of patterns.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New creates a redactor from the built-in patterns, minus those named in
+// disabled, plus the custom regular expressions in extra. A custom pattern
+// with a named group "secret" redacts only that group.
+func New(extra, disabled []string) (*Redactor, error) {
+	builtins := Builtins()
+	for _, name := range disabled {
+		if !slices.Contains(builtins, name) {
+			return nil, fmt.Errorf("unknown built-in pattern %q", name)
+		}
+	}
+
+	r := &Redactor{}
+	for _, p := range builtinPatterns {
+		if slices.Contains(disabled, p.name) {
+			continue
+		}
+		r.patterns = append(r.patterns, regexp.MustCompile(p.expr))
+	}
+	for _, expr := range extra {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns s with secrets replaced by Placeholder, and the number of
+// secrets replaced. A nil Redactor returns s unchanged.
+func (r *Redactor) Redact(s string) (string, int) {
+	if r == nil || s == "" {
+		return s, 0
+	}
+
+	count := 0
+	for _, re := range r.patterns {
+		group := re.SubexpIndex(secretGroup)
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			if group < 0 {
+				count++
+				return Placeholder
+			}
+			// Re-run on the match to locate the secret group within it
+			loc := re.FindStringSubmatchIndex(match)
+			start, end := loc[2*group], loc[2*group+1]
+			if start < 0 || start == end {
+				return match
+			}
+			count++
+			return match[:start] + Placeholder + match[end:]
+		})
+	}
+	return s, count
+}
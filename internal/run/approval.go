@@ -0,0 +1,199 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/huh"
+	"golang.org/x/term"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// CommandApprover asks the user whether a dangerous bash command may run.
+// It blocks until the user answers or ctx is done.
+type CommandApprover func(ctx context.Context, command, description string) (bool, error)
+
+// builtinDangerousCommands are the bash command patterns that need approval
+// unless disabled by name.
+var builtinDangerousCommands = []struct {
+	name string
+	expr string
+}{
+	{"rm-recursive", `\brm\b[^;&|\n]*\s(?:-[a-zA-Z]*[rR]|--recursive\b)`},
+	{"git-push-force", `\bgit\b[^;&|\n]*\bpush\b[^;&|\n]*\s(?:-f\b|--force\b)`},
+	{"git-reset-hard", `\bgit\b[^;&|\n]*\breset\b[^;&|\n]*\s--hard\b`},
+	{"git-clean", `\bgit\b[^;&|\n]*\bclean\b[^;&|\n]*\s-[a-zA-Z]*f`},
+	{"mkfs", `\b(?:mkfs(?:\.\w+)?|wipefs|fdisk)\b`},
+	{"dd", `\bdd\b[^;&|\n]*\bof=`},
+	{"chmod-recursive", `\b(?:chmod|chown)\b[^;&|\n]*\s-[a-zA-Z]*R`},
+	{"shutdown", `\b(?:shutdown|reboot|halt|poweroff)\b`},
+}
+
+// DangerousCommandBuiltins returns the names of the built-in dangerous
+// command patterns.
+func DangerousCommandBuiltins() []string {
+	names := make([]string, len(builtinDangerousCommands))
+	for i, p := range builtinDangerousCommands {
+		names[i] = p.name
+	}
+	return names
+}
+
+// commandGuard holds the dangerous command patterns. It is shared with
+// sub-agent runners so only one approval prompt is shown at a time.
+type commandGuard struct {
+	patterns            []*regexp.Regexp
+	allowNonInteractive bool
+	mu                  sync.Mutex
+}
+
+// newCommandGuard builds the guard from config, or nil when confirmation is
+// disabled.
+func newCommandGuard(cfg config.BashConfig) (*commandGuard, error) {
+	if cfg.DisableConfirm {
+		return nil, nil
+	}
+	builtins := DangerousCommandBuiltins()
+	for _, name := range cfg.DisableBuiltin {
+		if !slices.Contains(builtins, name) {
+			return nil, fmt.Errorf("bash config: unknown built-in pattern %q", name)
+		}
+	}
+
+	g := &commandGuard{allowNonInteractive: cfg.AllowNonInteractive}
+	for _, p := range builtinDangerousCommands {
+		if slices.Contains(cfg.DisableBuiltin, p.name) {
+			continue
+		}
+		g.patterns = append(g.patterns, regexp.MustCompile(p.expr))
+	}
+	for _, expr := range cfg.DangerousPatterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("bash config: invalid pattern %q: %w", expr, err)
+		}
+		g.patterns = append(g.patterns, re)
+	}
+	return g, nil
+}
+
+// dangerous reports whether command matches a dangerous pattern.
+func (g *commandGuard) dangerous(command string) bool {
+	for _, re := range g.patterns {
+		if re.MatchString(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCommandApprover sets how dangerous bash commands are approved. The TUI
+// uses this to ask in its own view. Without one, the runner asks on the
+// terminal, or denies the command when there is no terminal.
+func (r *Runner) SetCommandApprover(a CommandApprover) {
+	r.approver = a
+}
+
+// errNoTerminal means there is no terminal to ask for approval on.
+var errNoTerminal = errors.New("no terminal")
+
+// approveCommand reports whether a bash command may run. The returned
+// message explains a denial to the model.
+func (r *Runner) approveCommand(ctx context.Context, command, description string) (bool, string) {
+	if r.guard == nil || !r.guard.dangerous(command) {
+		return true, ""
+	}
+
+	r.guard.mu.Lock()
+	defer r.guard.mu.Unlock()
+
+	approve := r.approver
+	if approve == nil {
+		approve = terminalApprover
+	}
+	ok, err := approve(ctx, command, description)
+	switch {
+	case errors.Is(err, errNoTerminal):
+		if r.guard.allowNonInteractive {
+			return true, ""
+		}
+		return false, "command not run: it matches a dangerous command pattern and there is no terminal to ask the user for approval"
+	case err != nil:
+		if r.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: command approval: %v\n", err)
+		}
+		return false, "command not run: approval failed"
+	case !ok:
+		return false, "command not run: the user denied it"
+	}
+	return true, ""
+}
+
+// terminalApprover asks for approval with a prompt on stderr. It returns
+// errNoTerminal when stdin or stderr is not a terminal.
+func terminalApprover(ctx context.Context, command, description string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return false, errNoTerminal
+	}
+	var approved bool
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Run this command?").
+				Description("$ " + command + "\n" + description).
+				Affirmative("Run").
+				Negative("Deny").
+				Value(&approved),
+		),
+	).WithTheme(huh.ThemeCharm()).WithOutput(os.Stderr)
+	if err := form.RunWithContext(ctx); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return false, nil
+		}
+		return false, err
+	}
+	return approved, nil
+}
+
+// approvalTool wraps the bash tool so dangerous commands are approved
+// before they run.
+type approvalTool struct {
+	fantasy.AgentTool
+	runner *Runner
+}
+
+// withCommandApproval wraps the bash tool with approval. Tools are returned
+// unchanged when confirmation is disabled.
+func (r *Runner) withCommandApproval(tools []fantasy.AgentTool) []fantasy.AgentTool {
+	if r.guard == nil {
+		return tools
+	}
+	wrapped := make([]fantasy.AgentTool, len(tools))
+	for i, t := range tools {
+		if t.Info().Name == "bash" {
+			t = &approvalTool{AgentTool: t, runner: r}
+		}
+		wrapped[i] = t
+	}
+	return wrapped
+}
+
+func (t *approvalTool) Run(ctx context.Context, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+	var params BashParams
+	if err := json.Unmarshal([]byte(call.Input), &params); err != nil {
+		// Malformed input is rejected by the tool itself
+		return t.AgentTool.Run(ctx, call)
+	}
+	if ok, reason := t.runner.approveCommand(ctx, params.Command, params.Description); !ok {
+		return fantasy.NewTextErrorResponse(reason), nil
+	}
+	return t.AgentTool.Run(ctx, call)
+}
@@ -0,0 +1,171 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestCommandGuardDangerous(t *testing.T) {
+	g, err := newCommandGuard(config.BashConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dangerous := []string{
+		"rm -rf build",
+		"rm -r -f /tmp/x",
+		"cd src && rm --recursive out",
+		"git push --force origin main",
+		"git push -f",
+		"git reset --hard HEAD~1",
+		"git clean -fdx",
+		"mkfs.ext4 /dev/sdb1",
+		"dd if=/dev/zero of=/dev/sda",
+		"chmod -R 777 .",
+		"sudo reboot",
+	}
+	for _, cmd := range dangerous {
+		if !g.dangerous(cmd) {
+			t.Errorf("dangerous(%q) = false, want true", cmd)
+		}
+	}
+	safe := []string{
+		"rm file.txt",
+		"rm -f file.txt",
+		"grep -r TODO .",
+		"git push origin main",
+		"git reset HEAD file.go",
+		"ls -la",
+		"npm run format -- --fix",
+	}
+	for _, cmd := range safe {
+		if g.dangerous(cmd) {
+			t.Errorf("dangerous(%q) = true, want false", cmd)
+		}
+	}
+}
+
+func TestNewCommandGuardConfig(t *testing.T) {
+	g, err := newCommandGuard(config.BashConfig{
+		DangerousPatterns: []string{`\bterraform\s+destroy\b`},
+		DisableBuiltin:    []string{"git-push-force"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.dangerous("terraform destroy -auto-approve") {
+		t.Error("custom pattern should match")
+	}
+	if g.dangerous("git push --force") {
+		t.Error("disabled built-in pattern should not match")
+	}
+
+	if g, err := newCommandGuard(config.BashConfig{DisableConfirm: true}); g != nil || err != nil {
+		t.Errorf("newCommandGuard with confirmation disabled = %v, %v, want nil", g, err)
+	}
+	if _, err := newCommandGuard(config.BashConfig{DisableBuiltin: []string{"nope"}}); err == nil {
+		t.Error("expected an error for an unknown built-in pattern")
+	}
+	if _, err := newCommandGuard(config.BashConfig{DangerousPatterns: []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+// approvalBash returns the bash tool of a runner with the given approver,
+// and a file in its working directory that rm -rf would delete.
+func approvalBash(t *testing.T, cfg config.BashConfig, approver CommandApprover) (fantasy.AgentTool, string) {
+	t.Helper()
+	r, err := NewRunner(config.Config{Bash: cfg}, false, RunnerOptions{ApproveCommand: approver})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "build")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return r.withCommandApproval([]fantasy.AgentTool{NewBashTool(dir)})[0], target
+}
+
+func runBash(t *testing.T, tool fantasy.AgentTool, command string) fantasy.ToolResponse {
+	t.Helper()
+	resp, err := tool.Run(context.Background(), fantasy.ToolCall{
+		ID:    "call-1",
+		Name:  "bash",
+		Input: `{"command": "` + command + `", "description": "Clean build"}`,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	return resp
+}
+
+func TestApprovalToolDenied(t *testing.T) {
+	var asked string
+	tool, target := approvalBash(t, config.BashConfig{}, func(ctx context.Context, command, description string) (bool, error) {
+		asked = command
+		return false, nil
+	})
+
+	resp := runBash(t, tool, "rm -rf build")
+	if asked != "rm -rf build" {
+		t.Errorf("approver asked about %q", asked)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content, "denied") {
+		t.Errorf("response = %+v, want a denial", resp)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Error("denied command should not run")
+	}
+}
+
+func TestApprovalToolApproved(t *testing.T) {
+	tool, target := approvalBash(t, config.BashConfig{}, func(ctx context.Context, command, description string) (bool, error) {
+		return true, nil
+	})
+
+	if resp := runBash(t, tool, "rm -rf build"); resp.IsError {
+		t.Errorf("response = %+v, want success", resp)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("approved command should run")
+	}
+}
+
+func TestApprovalToolSkipsSafeCommands(t *testing.T) {
+	tool, _ := approvalBash(t, config.BashConfig{}, func(ctx context.Context, command, description string) (bool, error) {
+		t.Errorf("approver called for a safe command %q", command)
+		return false, nil
+	})
+	if resp := runBash(t, tool, "echo hello"); resp.IsError {
+		t.Errorf("response = %+v, want success", resp)
+	}
+}
+
+func TestApprovalToolNonInteractive(t *testing.T) {
+	noTerminal := func(ctx context.Context, command, description string) (bool, error) {
+		return false, errNoTerminal
+	}
+
+	tool, target := approvalBash(t, config.BashConfig{}, noTerminal)
+	if resp := runBash(t, tool, "rm -rf build"); !resp.IsError || !strings.Contains(resp.Content, "no terminal") {
+		t.Errorf("response = %+v, want a denial without a terminal", resp)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Error("command should not run without a terminal")
+	}
+
+	tool, target = approvalBash(t, config.BashConfig{AllowNonInteractive: true}, noTerminal)
+	if resp := runBash(t, tool, "rm -rf build"); resp.IsError {
+		t.Errorf("response = %+v, want success with allow_non_interactive", resp)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("command should run with allow_non_interactive")
+	}
+}
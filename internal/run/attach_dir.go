@@ -0,0 +1,192 @@
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on a directory attached with --attach-dir.
+const (
+	// MaxDirFiles is the most files attached from one directory.
+	MaxDirFiles = 200
+
+	// MaxDirBytes is the most file content attached from one directory.
+	MaxDirBytes = 512 * 1024
+)
+
+// IgnoreFile lists patterns of paths to leave out when attaching a
+// directory, one per line in the directory's root.
+const IgnoreFile = ".ayoignore"
+
+// DirFile is a file attached from a directory.
+type DirFile struct {
+	Path      string // Path on disk
+	Name      string // Path relative to the directory's parent, with forward slashes
+	MediaType string
+	Data      []byte
+	Text      bool // Inlined into the prompt rather than sent as a file part
+}
+
+// DirAttachment is the contents of a directory selected for attaching.
+type DirAttachment struct {
+	Files []DirFile
+
+	// Skipped lists binary files that cannot be sent, by Name.
+	Skipped []string
+
+	// Omitted counts files left out because the file or size limit was
+	// reached.
+	Omitted int
+}
+
+// Warnings describes files that were skipped or omitted, for the user.
+func (d DirAttachment) Warnings(dir string) []string {
+	var warnings []string
+	if d.Omitted > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: attached %d files, omitted %d more (limit %d files, %d KB)", dir, len(d.Files), d.Omitted, MaxDirFiles, MaxDirBytes/1024))
+	}
+	if len(d.Skipped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: skipped %d binary files: %s", dir, len(d.Skipped), strings.Join(d.Skipped, ", ")))
+	}
+	return warnings
+}
+
+// CollectDir walks dir in lexical order and reads the files to attach.
+// Text files are inlined; binary files with a known media type, such as
+// images and PDFs, are sent as file parts and other binaries are skipped.
+// Hidden files and directories, .git, and paths matching .ayoignore are
+// left out.
+func CollectDir(dir string) (DirAttachment, error) {
+	var result DirAttachment
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return result, err
+	}
+	if !info.IsDir() {
+		return result, fmt.Errorf("%s is not a directory", dir)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return result, err
+	}
+	prefix := filepath.Base(abs)
+	ignore, err := readIgnoreFile(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return result, err
+	}
+
+	total := 0
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if strings.HasPrefix(d.Name(), ".") || ignored(ignore, rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if len(result.Files) >= MaxDirFiles || total+int(fi.Size()) > MaxDirBytes {
+			result.Omitted++
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		f := DirFile{Path: p, Name: path.Join(prefix, rel), Data: data}
+		f.MediaType, f.Text = classifyFile(p, data)
+		if !f.Text && f.MediaType == "" {
+			result.Skipped = append(result.Skipped, f.Name)
+			return nil
+		}
+		total += len(data)
+		result.Files = append(result.Files, f)
+		return nil
+	})
+	return result, err
+}
+
+// classifyFile returns a file's media type and whether it is text. Binary
+// files whose type is unknown get an empty media type.
+func classifyFile(p string, data []byte) (string, bool) {
+	mediaType := mime.TypeByExtension(filepath.Ext(p))
+	if mediaType != "" && !isTextMediaType(mediaType) {
+		return mediaType, false
+	}
+	sample := data[:min(len(data), 8000)]
+	if bytes.IndexByte(sample, 0) >= 0 || !utf8.Valid(data) {
+		return "", false
+	}
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+	return mediaType, true
+}
+
+// readIgnoreFile reads .ayoignore patterns, skipping blank lines and
+// comments. A missing file has no patterns.
+func readIgnoreFile(p string) ([]string, error) {
+	data, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// ignored reports whether rel matches an ignore pattern. Patterns without a
+// slash match a file or directory name at any depth; patterns with one match
+// the path from the directory root. A trailing slash matches directories
+// only.
+func ignored(patterns []string, rel string, isDir bool) bool {
+	for _, pattern := range patterns {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(strings.TrimPrefix(pattern, "/"), target); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCollectDir(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "proj")
+	writeTree(t, root, map[string]string{
+		"README.md":      "# Project",
+		"src/main.go":    "package main",
+		"src/gen/out.go": "generated",
+		"build/app.log":  "log",
+		"notes.tmp":      "scratch",
+		".git/config":    "[core]",
+		".env":           "SECRET=1",
+		"logo.png":       "\x89PNG\r\n\x1a\n",
+		"tool":           "\x7fELF\x00\x00",
+		IgnoreFile:       "# generated code\nsrc/gen/\nbuild/\n*.tmp\n",
+	})
+
+	dir, err := CollectDir(root)
+	if err != nil {
+		t.Fatalf("CollectDir: %v", err)
+	}
+
+	var names []string
+	for _, f := range dir.Files {
+		names = append(names, f.Name)
+	}
+	want := "proj/README.md,proj/logo.png,proj/src/main.go"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("files = %s, want %s", got, want)
+	}
+	for _, f := range dir.Files {
+		if f.Name == "proj/logo.png" && (f.Text || f.MediaType != "image/png") {
+			t.Errorf("logo.png = text %v, %s; want an image part", f.Text, f.MediaType)
+		}
+		if f.Name == "proj/src/main.go" && !f.Text {
+			t.Error("main.go should be inlined as text")
+		}
+	}
+	if len(dir.Skipped) != 1 || dir.Skipped[0] != "proj/tool" {
+		t.Errorf("skipped = %v, want the unknown binary", dir.Skipped)
+	}
+	if dir.Omitted != 0 {
+		t.Errorf("omitted = %d, want 0", dir.Omitted)
+	}
+}
+
+func TestCollectDirLimits(t *testing.T) {
+	root := t.TempDir()
+	big := strings.Repeat("x", MaxDirBytes/2+1)
+	writeTree(t, root, map[string]string{"a.txt": big, "b.txt": big, "c.txt": "small"})
+
+	dir, err := CollectDir(root)
+	if err != nil {
+		t.Fatalf("CollectDir: %v", err)
+	}
+	if len(dir.Files) != 2 || dir.Omitted != 1 {
+		t.Errorf("files = %d, omitted = %d; want 2 and 1", len(dir.Files), dir.Omitted)
+	}
+	warnings := dir.Warnings("docs")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "omitted 1 more") {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestCollectDirRejectsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.txt")
+	writeTree(t, filepath.Dir(file), map[string]string{"a.txt": "x"})
+	if _, err := CollectDir(file); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("error = %v, want not a directory", err)
+	}
+}
+
+func TestBuildMessagesWithAttachedDirectory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "docs")
+	writeTree(t, root, map[string]string{
+		"intro.md":       "Welcome",
+		"guide/setup.md": "Install it",
+		"diagram.png":    "\x89PNG\r\n\x1a\n",
+	})
+
+	r := &Runner{}
+	msgs := r.buildMessagesWithAttachments(context.Background(), agent.Agent{}, "summarize", []string{root})
+	user := msgs[len(msgs)-1]
+
+	content := getTextContent(user)
+	for _, want := range []string{`<file path="docs/intro.md">`, `<file path="docs/guide/setup.md">`, "Install it", "summarize"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("prompt should contain %q, got %q", want, content)
+		}
+	}
+	var parts []string
+	for _, part := range user.Content {
+		if fp, ok := part.(fantasy.FilePart); ok {
+			parts = append(parts, fp.Filename)
+		}
+	}
+	if len(parts) != 1 || parts[0] != "docs/diagram.png" {
+		t.Errorf("file parts = %v, want docs/diagram.png", parts)
+	}
+}
@@ -0,0 +1,92 @@
+package run
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// backgroundWork tracks the work a runner keeps doing after a turn returns,
+// such as title generation, so it can be drained or cancelled on shutdown.
+// The zero value is ready to use.
+type backgroundWork struct {
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// context returns the context background work runs under. It is cancelled
+// by Runner.Shutdown.
+func (b *backgroundWork) context() context.Context {
+	b.once.Do(func() {
+		b.ctx, b.cancel = context.WithCancel(context.Background())
+	})
+	return b.ctx
+}
+
+// stop cancels the background context.
+func (b *backgroundWork) stop() {
+	b.context()
+	b.cancel()
+}
+
+// wait blocks until tracked work finishes or timeout expires. It reports
+// whether the work finished.
+func (b *backgroundWork) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// goBackground runs fn in a goroutine that Shutdown drains or cancels. ctx
+// is cancelled when the runner shuts down.
+func (r *Runner) goBackground(fn func(ctx context.Context)) {
+	ctx := r.background.context()
+	r.background.wg.Add(1)
+	go func() {
+		defer r.background.wg.Done()
+		fn(ctx)
+	}()
+}
+
+// withShutdown returns a copy of ctx that is also cancelled when the runner
+// shuts down, for work that belongs to a turn but may outlive the caller's
+// interest in it, such as memory formation. Call stop when the work is done.
+func (r *Runner) withShutdown(ctx context.Context) (_ context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	unregister := context.AfterFunc(r.background.context(), cancel)
+	return ctx, func() {
+		unregister()
+		cancel()
+	}
+}
+
+// Shutdown stops the runner's background work: title generation, memory
+// formation, and queued memory writes. With drain, it first waits up to
+// timeout for the work to finish; whatever is still running is then
+// cancelled, so no provider calls outlive the runner. Without drain, the
+// work is cancelled at once and Shutdown waits up to timeout for it to stop.
+func (r *Runner) Shutdown(drain bool, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	if drain {
+		r.WaitForFormations(timeout)
+		r.background.wait(time.Until(deadline))
+		r.WaitForMemoryQueue(time.Until(deadline))
+	}
+
+	r.background.stop()
+	if r.memoryQueue != nil {
+		r.memoryQueue.Abort(time.Until(deadline))
+	}
+	r.WaitForFormations(time.Until(deadline))
+	r.background.wait(time.Until(deadline))
+}
@@ -0,0 +1,57 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsBackgroundWork(t *testing.T) {
+	r := &Runner{}
+	stopped := make(chan error, 1)
+	r.goBackground(func(ctx context.Context) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+	})
+
+	r.Shutdown(false, time.Second)
+	select {
+	case err := <-stopped:
+		if err != context.Canceled {
+			t.Errorf("ctx.Err() = %v, want context.Canceled", err)
+		}
+	default:
+		t.Fatal("background work still running after Shutdown")
+	}
+}
+
+func TestShutdownDrainsBackgroundWork(t *testing.T) {
+	r := &Runner{}
+	finished := make(chan bool, 1)
+	r.goBackground(func(ctx context.Context) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			finished <- true
+		case <-ctx.Done():
+			finished <- false
+		}
+	})
+
+	r.Shutdown(true, time.Second)
+	if !<-finished {
+		t.Error("draining Shutdown cancelled work that would have finished in time")
+	}
+}
+
+func TestWithShutdownCancelsFormation(t *testing.T) {
+	r := &Runner{}
+	ctx, stop := r.withShutdown(context.Background())
+	defer stop()
+
+	r.Shutdown(false, time.Second)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("formation context not cancelled by Shutdown")
+	}
+}
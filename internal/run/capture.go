@@ -0,0 +1,273 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/redact"
+)
+
+// CaptureVersion is the schema version of captured records. It changes only
+// when a field is renamed or removed.
+const CaptureVersion = 1
+
+// CaptureRecord is one turn in an eval dataset: what was sent to the model
+// and what came back. Records are written one per line to the capture file.
+type CaptureRecord struct {
+	Version   int              `json:"version"`
+	Time      time.Time        `json:"time"`
+	SessionID string           `json:"session_id,omitempty"`
+	Agent     string           `json:"agent"`
+	Provider  string           `json:"provider"`
+	Model     string           `json:"model"`
+	System    string           `json:"system"`
+	Messages  []CaptureMessage `json:"messages"` // Request messages, without the system prompt
+	Tools     []CaptureTool    `json:"tools"`
+	Skills    *TurnSkills      `json:"skills,omitempty"` // Skills in the prompt; omitted for agents without skills
+	Output    []CaptureMessage `json:"output"`           // Assistant and tool messages produced by the turn
+	Response  string           `json:"response"`
+	Usage     CaptureUsage     `json:"usage"`
+}
+
+// CaptureMessage is a message in a captured turn.
+type CaptureMessage struct {
+	Role    string        `json:"role"`
+	Content []CapturePart `json:"content"`
+}
+
+// CapturePart is a piece of message content. Type is "text", "reasoning",
+// "file", "tool_call", or "tool_result". File data is not captured.
+type CapturePart struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	Input      string `json:"input,omitempty"`  // Tool call arguments as JSON
+	Output     string `json:"output,omitempty"` // Tool result text
+	IsError    bool   `json:"is_error,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	MediaType  string `json:"media_type,omitempty"`
+}
+
+// CaptureTool is a tool offered to the model. Parameters is a JSON Schema
+// object.
+type CaptureTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// CaptureUsage is the token usage of a captured turn.
+type CaptureUsage struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	ReasoningTokens     int64   `json:"reasoning_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CostUSD             float64 `json:"cost_usd"` // 0 when the provider publishes no pricing
+}
+
+// captureSink appends sampled turns to a JSONL file.
+type captureSink struct {
+	path     string
+	rate     float64
+	redactor *redact.Redactor // nil = record secrets unmodified
+	mu       sync.Mutex
+}
+
+// newCaptureSink builds the capture sink from config, or nil when capture is
+// off.
+func newCaptureSink(cfg config.CaptureConfig, redaction config.RedactionConfig) (*captureSink, error) {
+	if strings.TrimSpace(cfg.Path) == "" {
+		return nil, nil
+	}
+	rate := 1.0
+	if cfg.SampleRate != nil {
+		rate = *cfg.SampleRate
+	}
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("capture config: sample_rate must be between 0 and 1, got %g", rate)
+	}
+	path := cfg.Path
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	sink := &captureSink{path: path, rate: rate}
+	if !cfg.DisableRedaction {
+		redactor, err := redact.New(redaction.Patterns, redaction.DisableBuiltin)
+		if err != nil {
+			return nil, fmt.Errorf("redaction config: %w", err)
+		}
+		sink.redactor = redactor
+	}
+	return sink, nil
+}
+
+// sample reports whether the next turn is captured.
+func (s *captureSink) sample() bool {
+	return s.rate >= 1 || rand.Float64() < s.rate
+}
+
+// write redacts rec and appends it to the capture file.
+func (s *captureSink) write(rec CaptureRecord) error {
+	s.redactRecord(&rec)
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// redactRecord masks secrets in the text of rec. Tool definitions are left
+// as is.
+func (s *captureSink) redactRecord(rec *CaptureRecord) {
+	if s.redactor == nil {
+		return
+	}
+	mask := func(text string) string {
+		redacted, _ := s.redactor.Redact(text)
+		return redacted
+	}
+	rec.System = mask(rec.System)
+	rec.Response = mask(rec.Response)
+	for _, msgs := range [][]CaptureMessage{rec.Messages, rec.Output} {
+		for i := range msgs {
+			for j := range msgs[i].Content {
+				p := &msgs[i].Content[j]
+				p.Text = mask(p.Text)
+				p.Input = mask(p.Input)
+				p.Output = mask(p.Output)
+			}
+		}
+	}
+}
+
+// captureTurn records a successful turn when capture is on and the turn is
+// sampled. msgs are the request messages, including the system prompt.
+// Failures are reported in debug mode and never fail the turn.
+func (r *Runner) captureTurn(ctx context.Context, ag agent.Agent, msgs []fantasy.Message, tools []fantasy.AgentTool, result *fantasy.AgentResult, response string) {
+	if r.capture == nil || !r.capture.sample() {
+		return
+	}
+
+	rec := CaptureRecord{
+		Version:   CaptureVersion,
+		Time:      time.Now().UTC(),
+		SessionID: GetSessionIDFromContext(ctx),
+		Agent:     ag.Handle,
+		Provider:  string(r.config.Provider.ID),
+		Model:     ag.Model,
+		Messages:  []CaptureMessage{},
+		Tools:     make([]CaptureTool, 0, len(tools)),
+		Output:    []CaptureMessage{},
+		Response:  strings.TrimSpace(response),
+	}
+
+	var system []string
+	for _, msg := range msgs {
+		if msg.Role == fantasy.MessageRoleSystem {
+			for _, part := range msg.Content {
+				if tp, ok := part.(fantasy.TextPart); ok && tp.Text != "" {
+					system = append(system, tp.Text)
+				}
+			}
+			continue
+		}
+		rec.Messages = append(rec.Messages, captureMessage(msg))
+	}
+	rec.System = strings.Join(system, "\n\n")
+
+	if skills, ok := turnSkillsFromContext(ctx); ok && len(skills.InPrompt)+len(skills.Skipped) > 0 {
+		rec.Skills = &skills
+	}
+
+	for _, t := range tools {
+		info := t.Info()
+		params := map[string]any{"type": "object", "properties": info.Parameters}
+		if len(info.Required) > 0 {
+			params["required"] = info.Required
+		}
+		rec.Tools = append(rec.Tools, CaptureTool{Name: info.Name, Description: info.Description, Parameters: params})
+	}
+
+	if result != nil {
+		for _, step := range result.Steps {
+			for _, msg := range step.Messages {
+				rec.Output = append(rec.Output, captureMessage(msg))
+			}
+		}
+		u := result.TotalUsage
+		rec.Usage = CaptureUsage{
+			InputTokens:         u.InputTokens,
+			OutputTokens:        u.OutputTokens,
+			ReasoningTokens:     u.ReasoningTokens,
+			CacheCreationTokens: u.CacheCreationTokens,
+			CacheReadTokens:     u.CacheReadTokens,
+			CostUSD:             stepUsage(r.config.Provider, ag.Model, u).CostUSD,
+		}
+	}
+
+	if err := r.capture.write(rec); err != nil && r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: capture turn: %v\n", err)
+	}
+}
+
+// captureMessage converts a fantasy message to its captured form.
+func captureMessage(msg fantasy.Message) CaptureMessage {
+	out := CaptureMessage{Role: string(msg.Role), Content: []CapturePart{}}
+	for _, part := range msg.Content {
+		switch p := part.(type) {
+		case fantasy.TextPart:
+			out.Content = append(out.Content, CapturePart{Type: "text", Text: p.Text})
+		case fantasy.ReasoningPart:
+			out.Content = append(out.Content, CapturePart{Type: "reasoning", Text: p.Text})
+		case fantasy.FilePart:
+			out.Content = append(out.Content, CapturePart{Type: "file", Filename: p.Filename, MediaType: p.MediaType})
+		case fantasy.ToolCallPart:
+			out.Content = append(out.Content, CapturePart{Type: "tool_call", ToolCallID: p.ToolCallID, ToolName: p.ToolName, Input: p.Input})
+		case fantasy.ToolResultPart:
+			cp := CapturePart{Type: "tool_result", ToolCallID: p.ToolCallID}
+			switch o := p.Output.(type) {
+			case fantasy.ToolResultOutputContentText:
+				cp.Output = o.Text
+			case fantasy.ToolResultOutputContentError:
+				cp.IsError = true
+				if o.Error != nil {
+					cp.Output = o.Error.Error()
+				}
+			case fantasy.ToolResultOutputContentMedia:
+				cp.Output = o.Text
+				cp.MediaType = o.MediaType
+			}
+			out.Content = append(out.Content, cp)
+		}
+	}
+	return out
+}
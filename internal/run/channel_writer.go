@@ -19,6 +19,10 @@ const (
 	EventMemory
 	EventError
 	EventDone
+	EventUsage
+	EventStructuredDelta
+	EventStats
+	EventToolInput
 )
 
 // StreamEvent is a unified event type for all streaming events.
@@ -31,7 +35,7 @@ type StreamEvent struct {
 	Content  string
 	Duration time.Duration
 
-	// Tool events
+	// Tool events. EventToolInput carries a call whose Input is partial.
 	Call   *ToolCall
 	Result *ToolResult
 
@@ -46,6 +50,16 @@ type StreamEvent struct {
 
 	// Final response
 	Response string
+
+	// Usage events
+	Usage *Usage
+
+	// Stats events
+	Stats *RunStats
+
+	// Structured output events (partial JSON is in Delta)
+	Attempt     int
+	MaxAttempts int
 }
 
 // ChannelWriter implements StreamWriter by sending events to a channel.
@@ -80,6 +94,10 @@ func (w *ChannelWriter) WriteToolStart(call ToolCall) {
 	w.events <- StreamEvent{Type: EventToolStart, Call: &call}
 }
 
+func (w *ChannelWriter) WriteToolInput(call ToolCall) {
+	w.events <- StreamEvent{Type: EventToolInput, Call: &call}
+}
+
 func (w *ChannelWriter) WriteToolResult(result ToolResult) {
 	w.events <- StreamEvent{Type: EventToolResult, Result: &result}
 }
@@ -104,5 +122,24 @@ func (w *ChannelWriter) WriteDone(response string) {
 	w.events <- StreamEvent{Type: EventDone, Response: response}
 }
 
-// Verify ChannelWriter implements StreamWriter
-var _ StreamWriter = (*ChannelWriter)(nil)
+func (w *ChannelWriter) WriteUsage(usage Usage) {
+	w.events <- StreamEvent{Type: EventUsage, Usage: &usage}
+}
+
+func (w *ChannelWriter) WriteStats(stats RunStats) {
+	w.events <- StreamEvent{Type: EventStats, Stats: &stats}
+}
+
+func (w *ChannelWriter) WriteStructuredDelta(partial string, attempt, maxAttempts int) {
+	w.events <- StreamEvent{Type: EventStructuredDelta, Delta: partial, Attempt: attempt, MaxAttempts: maxAttempts}
+}
+
+// Verify ChannelWriter implements StreamWriter, UsageWriter, StatsWriter,
+// StructuredOutputWriter, and ToolInputWriter
+var (
+	_ StreamWriter           = (*ChannelWriter)(nil)
+	_ UsageWriter            = (*ChannelWriter)(nil)
+	_ StatsWriter            = (*ChannelWriter)(nil)
+	_ StructuredOutputWriter = (*ChannelWriter)(nil)
+	_ ToolInputWriter        = (*ChannelWriter)(nil)
+)
@@ -0,0 +1,256 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// ContextStrategy selects how much chat history is sent with each message.
+type ContextStrategy string
+
+const (
+	ContextTokens ContextStrategy = "tokens" // Recent turns within a token budget (default)
+	ContextTurns  ContextStrategy = "turns"  // The last N turns
+	ContextFull   ContextStrategy = "full"   // All history
+)
+
+const (
+	defaultMaxTurns = 20
+
+	// defaultContextShare is the share of the model's context window the
+	// "tokens" strategy fills, leaving room for the response
+	defaultContextShare = 0.75
+
+	// fileTokenEstimate is the estimated cost of a binary attachment, whose
+	// size says little about its token count
+	fileTokenEstimate = 1000
+)
+
+// ContextOptions controls trimming of chat history before each request.
+type ContextOptions struct {
+	Strategy  ContextStrategy
+	MaxTurns  int
+	MaxTokens int // 0 = the model's context window decides
+	Summarize bool
+}
+
+// NewContextOptions validates chat context settings from config.
+// Empty values select the defaults.
+func NewContextOptions(cfg config.ChatConfig) (ContextOptions, error) {
+	opts := ContextOptions{
+		Strategy:  ContextStrategy(cfg.ContextStrategy),
+		MaxTurns:  cfg.MaxTurns,
+		MaxTokens: cfg.MaxTokens,
+		Summarize: cfg.Summarize,
+	}
+	switch opts.Strategy {
+	case "":
+		opts.Strategy = ContextTokens
+	case ContextTokens, ContextTurns, ContextFull:
+	default:
+		return ContextOptions{}, fmt.Errorf("unknown context strategy %q (use tokens, turns, or full)", cfg.ContextStrategy)
+	}
+	if opts.MaxTurns < 0 || opts.MaxTokens < 0 {
+		return ContextOptions{}, fmt.Errorf("max_turns and max_tokens must not be negative")
+	}
+	if opts.MaxTurns == 0 {
+		opts.MaxTurns = defaultMaxTurns
+	}
+	return opts, nil
+}
+
+// trimStart returns how many of the leading history messages to leave out
+// of a request. History excludes the leading system messages, which cost
+// systemTokens. The most recent turn is always kept.
+func (o ContextOptions) trimStart(history []fantasy.Message, systemTokens, contextWindow int) int {
+	// Each turn starts at a user message, so tool calls stay with their
+	// results
+	var turns []int
+	for i, msg := range history {
+		if msg.Role == fantasy.MessageRoleUser {
+			turns = append(turns, i)
+		}
+	}
+	if len(turns) <= 1 {
+		return 0
+	}
+
+	switch o.Strategy {
+	case ContextTurns:
+		if len(turns) <= o.MaxTurns {
+			return 0
+		}
+		return turns[len(turns)-o.MaxTurns]
+
+	case ContextTokens:
+		budget := o.MaxTokens
+		if budget == 0 {
+			budget = int(float64(contextWindow) * defaultContextShare)
+		}
+		if budget == 0 {
+			return 0
+		}
+		budget -= systemTokens
+
+		start := len(history)
+		used := 0
+		for t := len(turns) - 1; t >= 0; t-- {
+			cost := 0
+			for _, msg := range history[turns[t]:start] {
+				cost += estimateTokens(msg)
+			}
+			if used+cost > budget && start < len(history) {
+				break
+			}
+			used += cost
+			start = turns[t]
+		}
+		// Messages before the first user message belong to no turn
+		if start == turns[0] {
+			return 0
+		}
+		return start
+	}
+
+	return 0
+}
+
+// estimateTokens roughly estimates the tokens in a message at four
+// characters per token.
+func estimateTokens(msg fantasy.Message) int {
+	chars := 0
+	files := 0
+	for _, part := range msg.Content {
+		switch p := part.(type) {
+		case fantasy.TextPart:
+			chars += len(p.Text)
+		case fantasy.ReasoningPart:
+			chars += len(p.Text)
+		case fantasy.ToolCallPart:
+			chars += len(p.ToolName) + len(p.Input)
+		case fantasy.ToolResultPart:
+			switch out := p.Output.(type) {
+			case fantasy.ToolResultOutputContentText:
+				chars += len(out.Text)
+			case fantasy.ToolResultOutputContentError:
+				if out.Error != nil {
+					chars += len(out.Error.Error())
+				}
+			case fantasy.ToolResultOutputContentMedia:
+				chars += len(out.Text)
+				files++
+			}
+		case fantasy.FilePart:
+			if strings.HasPrefix(p.MediaType, "text/") {
+				chars += len(p.Data)
+			} else {
+				files++
+			}
+		}
+	}
+	return chars/4 + files*fileTokenEstimate
+}
+
+// contextWindow returns the model's context window, or 0 when the
+// provider doesn't publish it.
+func contextWindow(p catwalk.Provider, modelID string) int {
+	for _, m := range p.Models {
+		if m.ID == modelID {
+			return int(m.ContextWindow)
+		}
+	}
+	return 0
+}
+
+// requestMessages returns the messages to send for the session's next
+// request. Older turns beyond the context strategy are left out, or
+// replaced with a summary when configured. The session keeps its full
+// history.
+func (r *Runner) requestMessages(ctx context.Context, cs *ChatSession, modelID string) []fantasy.Message {
+	if r.chatContext.Strategy == ContextFull {
+		return cs.Messages
+	}
+
+	split := 0
+	for split < len(cs.Messages) && cs.Messages[split].Role == fantasy.MessageRoleSystem {
+		split++
+	}
+	system, history := cs.Messages[:split], cs.Messages[split:]
+
+	systemTokens := 0
+	for _, msg := range system {
+		systemTokens += estimateTokens(msg)
+	}
+	// The agent's context files are sent with every request, so they count
+	// against the budget like the system prompts. While the turn that first
+	// sent them is kept, they are counted twice, erring toward trimming.
+	pinContext := cs.ContextSent && cs.contextAt >= split && len(cs.context.Content) > 0
+	if pinContext {
+		systemTokens += estimateTokens(cs.context)
+	}
+	drop := r.chatContext.trimStart(history, systemTokens, contextWindow(r.config.Provider, modelID))
+	if drop == 0 && cs.summarized == 0 {
+		return cs.Messages
+	}
+
+	if r.chatContext.Summarize && r.smallModel != nil {
+		// Never send turns the summary already covers
+		drop = max(drop, cs.summarized)
+		if drop > cs.summarized {
+			r.updateSummary(ctx, cs, history[cs.summarized:drop], drop)
+		}
+	}
+
+	if r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: sending %d of %d history messages (%s strategy)\n", len(history)-drop, len(history), r.chatContext.Strategy)
+	}
+
+	msgs := slices.Clone(system)
+	if cs.summary != "" {
+		msgs = append(msgs, fantasy.NewSystemMessage("<conversation_summary>\nSummary of earlier turns in this conversation:\n"+cs.summary+"\n</conversation_summary>"))
+	}
+	kept := history[drop:]
+	if pinContext && drop > cs.contextAt-split && len(kept) > 0 {
+		// The turn that sent the context files was left out, so they go
+		// with the first message kept, which starts a turn
+		first := kept[0]
+		first.Content = append(slices.Clone(cs.context.Content), first.Content...)
+		msgs = append(msgs, first)
+		kept = kept[1:]
+	}
+	return append(msgs, kept...)
+}
+
+// updateSummary folds newly dropped messages into the session's summary.
+// On failure the summary is left as is and the messages are omitted.
+func (r *Runner) updateSummary(ctx context.Context, cs *ChatSession, dropped []fantasy.Message, summarized int) {
+	var b strings.Builder
+	if cs.summary != "" {
+		b.WriteString("Summary of earlier turns: " + cs.summary + "\n\n")
+	}
+	for _, msg := range dropped {
+		for _, part := range msg.Content {
+			if tp, ok := part.(fantasy.TextPart); ok && strings.TrimSpace(tp.Text) != "" {
+				fmt.Fprintf(&b, "%s: %s\n", msg.Role, tp.Text)
+			}
+		}
+	}
+
+	summary, err := r.smallModel.SummarizeConversation(ctx, b.String())
+	if err != nil || summary == "" {
+		if r.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: summarize dropped turns: %v\n", err)
+		}
+		return
+	}
+	cs.summary = summary
+	cs.summarized = summarized
+}
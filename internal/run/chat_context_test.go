@@ -0,0 +1,166 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+)
+
+// longSession builds a session with a system message and the given number
+// of user/assistant turns of about 100 tokens each.
+func longSession(turns int) *ChatSession {
+	msgs := []fantasy.Message{fantasy.NewSystemMessage("You are helpful.")}
+	body := strings.Repeat("word ", 40)
+	for i := 0; i < turns; i++ {
+		msgs = append(msgs,
+			fantasy.NewUserMessage(body),
+			fantasy.Message{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.TextPart{Text: body}}},
+		)
+	}
+	return &ChatSession{Messages: msgs}
+}
+
+func TestNewContextOptions(t *testing.T) {
+	opts, err := NewContextOptions(config.ChatConfig{})
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if opts.Strategy != ContextTokens || opts.MaxTurns != defaultMaxTurns {
+		t.Errorf("defaults = %+v", opts)
+	}
+
+	if _, err := NewContextOptions(config.ChatConfig{ContextStrategy: "oldest"}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if _, err := NewContextOptions(config.ChatConfig{MaxTurns: -1}); err == nil {
+		t.Error("expected error for negative max_turns")
+	}
+}
+
+func TestRequestMessagesTurns(t *testing.T) {
+	r := &Runner{chatContext: ContextOptions{Strategy: ContextTurns, MaxTurns: 3}}
+	cs := longSession(100)
+
+	msgs := r.requestMessages(context.Background(), cs, "model")
+	if len(msgs) != 1+3*2 {
+		t.Fatalf("sent %d messages, want 7", len(msgs))
+	}
+	if msgs[0].Role != fantasy.MessageRoleSystem || msgs[1].Role != fantasy.MessageRoleUser {
+		t.Errorf("should keep the system message then start at a user message, got %s, %s", msgs[0].Role, msgs[1].Role)
+	}
+	if len(cs.Messages) != 201 {
+		t.Errorf("session history should stay full, got %d messages", len(cs.Messages))
+	}
+}
+
+func TestRequestMessagesTokensUsesContextWindow(t *testing.T) {
+	r := &Runner{
+		config: config.Config{Provider: catwalk.Provider{Models: []catwalk.Model{
+			{ID: "model", ContextWindow: 2000},
+		}}},
+		chatContext: ContextOptions{Strategy: ContextTokens, MaxTurns: defaultMaxTurns},
+	}
+	cs := longSession(100)
+
+	msgs := r.requestMessages(context.Background(), cs, "model")
+	total := 0
+	for _, msg := range msgs {
+		total += estimateTokens(msg)
+	}
+	if total > 1500 {
+		t.Errorf("sent about %d tokens, want at most 1500", total)
+	}
+	if len(msgs) < 3 || msgs[1].Role != fantasy.MessageRoleUser {
+		t.Errorf("should send whole recent turns, got %d messages", len(msgs))
+	}
+
+	// An unknown context window disables the default budget
+	if got := r.requestMessages(context.Background(), cs, "unknown"); len(got) != len(cs.Messages) {
+		t.Errorf("sent %d messages without a known window, want all %d", len(got), len(cs.Messages))
+	}
+}
+
+func TestRequestMessagesKeepsLatestTurn(t *testing.T) {
+	r := &Runner{chatContext: ContextOptions{Strategy: ContextTokens, MaxTokens: 10}}
+	cs := longSession(5)
+
+	msgs := r.requestMessages(context.Background(), cs, "model")
+	if len(msgs) != 3 {
+		t.Errorf("sent %d messages, want the system message and the latest turn", len(msgs))
+	}
+}
+
+func TestRequestMessagesSummarizesDroppedTurns(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": map[string]string{"role": "assistant", "content": "Earlier: the user set up the project."},
+			"done":    true,
+		})
+	}))
+	defer server.Close()
+
+	r := &Runner{
+		chatContext: ContextOptions{Strategy: ContextTurns, MaxTurns: 2, Summarize: true},
+		smallModel:  smallmodel.NewService(smallmodel.Config{Host: server.URL}),
+	}
+	cs := longSession(10)
+
+	msgs := r.requestMessages(context.Background(), cs, "model")
+	if len(msgs) != 1+1+2*2 {
+		t.Fatalf("sent %d messages, want system, summary, and 2 turns", len(msgs))
+	}
+	summary := msgs[1].Content[0].(fantasy.TextPart).Text
+	if msgs[1].Role != fantasy.MessageRoleSystem || !strings.Contains(summary, "set up the project") {
+		t.Errorf("second message should be the summary, got %s: %q", msgs[1].Role, summary)
+	}
+
+	// The summary is reused until more turns are dropped
+	r.requestMessages(context.Background(), cs, "model")
+	if calls != 1 {
+		t.Errorf("small model called %d times, want 1", calls)
+	}
+}
+
+func TestRequestMessagesKeepsContextFiles(t *testing.T) {
+	r := &Runner{chatContext: ContextOptions{Strategy: ContextTokens, MaxTokens: 1000}}
+	cs := longSession(10)
+	files := strings.Repeat("style ", 300) // about 450 tokens
+	cs.Messages[1] = fantasy.NewUserMessage(strings.Repeat("word ", 40) + "\n\n" + files)
+	cs.context = fantasy.NewUserMessage(files)
+	cs.contextAt = 1
+	cs.ContextSent = true
+
+	msgs := r.requestMessages(context.Background(), cs, "model")
+
+	// The context files count against the budget like the system prompt
+	total := 0
+	for _, msg := range msgs {
+		total += estimateTokens(msg)
+	}
+	if total > 1000 {
+		t.Errorf("sent about %d tokens, want at most 1000", total)
+	}
+	// Without them nine turns of about 100 tokens fit
+	if len(msgs) != 1+5*2 {
+		t.Errorf("sent %d messages, want the system message and 5 turns", len(msgs))
+	}
+	// They stay in the request after the turn that sent them is dropped
+	first := msgs[1]
+	if first.Role != fantasy.MessageRoleUser || len(first.Content) != 2 || first.Content[0].(fantasy.TextPart).Text != files {
+		t.Errorf("first kept message should start with the context files, got %+v", first)
+	}
+	if len(cs.Messages[11].Content) != 1 {
+		t.Error("session history should not be changed")
+	}
+}
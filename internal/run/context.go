@@ -10,8 +10,9 @@ import (
 type ctxKey string
 
 const (
-	sessionIDKey ctxKey = "session_id"
-	servicesKey  ctxKey = "services"
+	sessionIDKey  ctxKey = "session_id"
+	servicesKey   ctxKey = "services"
+	turnSkillsKey ctxKey = "turn_skills"
 )
 
 // WithSessionID adds the session ID to the context.
@@ -35,3 +36,15 @@ func GetServicesFromContext(ctx context.Context) *session.Services {
 	svc, _ := ctx.Value(servicesKey).(*session.Services)
 	return svc
 }
+
+// withTurnSkills adds the skills in the turn's prompt to the context.
+func withTurnSkills(ctx context.Context, skills TurnSkills) context.Context {
+	return context.WithValue(ctx, turnSkillsKey, skills)
+}
+
+// turnSkillsFromContext retrieves the skills in the turn's prompt from the
+// context.
+func turnSkillsFromContext(ctx context.Context) (TurnSkills, bool) {
+	skills, ok := ctx.Value(turnSkillsKey).(TurnSkills)
+	return skills, ok
+}
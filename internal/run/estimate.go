@@ -0,0 +1,103 @@
+package run
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/catwalk/pkg/embedded"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+// DefaultEstimatePrompt is the sample prompt of a cost estimate when none
+// is given.
+const DefaultEstimatePrompt = "Explain what this project does and suggest one improvement."
+
+const (
+	// Typical completion lengths in tokens, from a short answer to a
+	// detailed one, bounding a cost estimate
+	estimateCompletionLow  = 200
+	estimateCompletionHigh = 1500
+)
+
+// CostEstimate is the rough cost of one call to an agent.
+type CostEstimate struct {
+	Model          string
+	SystemTokens   int64 // System, tools, skills, and model context prompts
+	PromptTokens   int64 // The sample prompt and the agent's context files
+	CompletionLow  int64
+	CompletionHigh int64
+	CostLowUSD     float64
+	CostHighUSD    float64
+	Priced         bool // The provider publishes the model's pricing
+}
+
+// InputTokens returns the estimated tokens sent with the call.
+func (e CostEstimate) InputTokens() int64 {
+	return e.SystemTokens + e.PromptTokens
+}
+
+// EstimateCost estimates the cost of one call to ag with prompt, before
+// tool calls, memories, and skill selection. Tokens are estimated at four
+// characters per token, as for chat context, and priced with the
+// provider's published rates for the model, or those of another catwalk
+// provider that lists it.
+func EstimateCost(p catwalk.Provider, ag agent.Agent, prompt string) CostEstimate {
+	var system []fantasy.Message
+	for _, s := range []string{ag.CombinedSystem, ag.ToolsPrompt, ag.SkillsPrompt} {
+		if strings.TrimSpace(s) != "" {
+			system = append(system, fantasy.NewSystemMessage(s))
+		}
+	}
+	if ag.Model != "" {
+		system = append(system, fantasy.NewSystemMessage(ModelContext(ag.Model)))
+	}
+	prompt, fileParts := attachFiles(prompt, ag.ContextFiles)
+
+	e := CostEstimate{
+		Model:          ag.Model,
+		PromptTokens:   int64(estimateTokens(fantasy.NewUserMessage(prompt, fileParts...))),
+		CompletionLow:  estimateCompletionLow,
+		CompletionHigh: estimateCompletionHigh,
+	}
+	for _, msg := range system {
+		e.SystemTokens += int64(estimateTokens(msg))
+	}
+
+	p = pricedProvider(p, ag.Model)
+	for _, m := range p.Models {
+		if m.ID != ag.Model {
+			continue
+		}
+		e.Priced = m.CostPer1MIn > 0 || m.CostPer1MOut > 0
+		if m.DefaultMaxTokens > 0 {
+			e.CompletionHigh = min(e.CompletionHigh, m.DefaultMaxTokens)
+			e.CompletionLow = min(e.CompletionLow, e.CompletionHigh)
+		}
+		break
+	}
+	e.CostLowUSD = stepUsage(p, ag.Model, fantasy.Usage{InputTokens: e.InputTokens(), OutputTokens: e.CompletionLow}).CostUSD
+	e.CostHighUSD = stepUsage(p, ag.Model, fantasy.Usage{InputTokens: e.InputTokens(), OutputTokens: e.CompletionHigh}).CostUSD
+	return e
+}
+
+// pricedProvider returns p if it lists modelID, otherwise the catwalk
+// provider that does, preferring the one with p's ID.
+func pricedProvider(p catwalk.Provider, modelID string) catwalk.Provider {
+	lists := func(p catwalk.Provider) bool {
+		return slices.ContainsFunc(p.Models, func(m catwalk.Model) bool { return m.ID == modelID })
+	}
+	if lists(p) {
+		return p
+	}
+	known := embedded.GetAll()
+	if i := slices.IndexFunc(known, func(k catwalk.Provider) bool { return k.ID == p.ID && lists(k) }); i >= 0 {
+		return known[i]
+	}
+	if i := slices.IndexFunc(known, lists); i >= 0 {
+		return known[i]
+	}
+	return p
+}
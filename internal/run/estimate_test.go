@@ -0,0 +1,84 @@
+package run
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/catwalk/pkg/embedded"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+func TestEstimateCost(t *testing.T) {
+	p := catwalk.Provider{Models: []catwalk.Model{
+		{ID: "model", CostPer1MIn: 3, CostPer1MOut: 15},
+	}}
+	ag := agent.Agent{
+		Model:          "model",
+		CombinedSystem: strings.Repeat("s", 4000),
+		ToolsPrompt:    strings.Repeat("t", 400),
+	}
+
+	got := EstimateCost(p, ag, strings.Repeat("p", 40))
+
+	// The system prompts, plus the model context
+	wantSystem := int64(1000 + 100 + len(ModelContext("model"))/4)
+	if got.SystemTokens != wantSystem || got.PromptTokens != 10 {
+		t.Errorf("tokens = %d system, %d prompt; want %d, 10", got.SystemTokens, got.PromptTokens, wantSystem)
+	}
+	if !got.Priced {
+		t.Error("Priced = false, want true")
+	}
+	input := float64(got.InputTokens())
+	wantLow := (input*3 + estimateCompletionLow*15) / 1_000_000
+	wantHigh := (input*3 + estimateCompletionHigh*15) / 1_000_000
+	if math.Abs(got.CostLowUSD-wantLow) > 1e-12 || math.Abs(got.CostHighUSD-wantHigh) > 1e-12 {
+		t.Errorf("cost = %v to %v, want %v to %v", got.CostLowUSD, got.CostHighUSD, wantLow, wantHigh)
+	}
+}
+
+func TestEstimateCostCapsCompletion(t *testing.T) {
+	p := catwalk.Provider{Models: []catwalk.Model{
+		{ID: "model", CostPer1MIn: 1, CostPer1MOut: 1, DefaultMaxTokens: 100},
+	}}
+
+	got := EstimateCost(p, agent.Agent{Model: "model"}, "hi")
+
+	if got.CompletionLow != 100 || got.CompletionHigh != 100 {
+		t.Errorf("completion = %d to %d, want 100 to 100", got.CompletionLow, got.CompletionHigh)
+	}
+}
+
+func TestEstimateCostUnpriced(t *testing.T) {
+	got := EstimateCost(catwalk.Provider{ID: "local"}, agent.Agent{Model: "llama"}, "hi")
+
+	if got.Priced || got.CostLowUSD != 0 || got.CostHighUSD != 0 {
+		t.Errorf("estimate = %+v, want no cost", got)
+	}
+	if got.InputTokens() == 0 {
+		t.Error("want tokens estimated without pricing")
+	}
+}
+
+func TestEstimateCostUsesCatwalkPrices(t *testing.T) {
+	for _, known := range embedded.GetAll() {
+		for _, m := range known.Models {
+			if m.CostPer1MIn == 0 {
+				continue
+			}
+			// Under its own provider, and under one that doesn't list it
+			got := EstimateCost(catwalk.Provider{ID: known.ID}, agent.Agent{Model: m.ID}, "hi")
+			if !got.Priced || got.CostHighUSD <= got.CostLowUSD {
+				t.Errorf("%s/%s: estimate = %+v, want catwalk's pricing", known.ID, m.ID, got)
+			}
+			got = EstimateCost(catwalk.Provider{ID: "elsewhere"}, agent.Agent{Model: m.ID}, "hi")
+			if !got.Priced || got.CostHighUSD <= got.CostLowUSD {
+				t.Errorf("%s/%s: estimate = %+v, want catwalk's pricing", known.ID, m.ID, got)
+			}
+			return
+		}
+	}
+	t.Skip("no priced model in catwalk's providers")
+}
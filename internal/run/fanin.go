@@ -0,0 +1,87 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+// FanInResult is one source agent's part of a fan-in.
+type FanInResult struct {
+	Handle   string
+	Key      string          // Key of the output in the merged object; see agent.MergeKey
+	Output   json.RawMessage // Validated JSON for agents with an output schema, else a JSON string
+	Err      error
+	Duration time.Duration
+}
+
+// FanIn runs every source agent on input at once and returns their
+// results in the order of sources. Input is validated against each
+// source's input schema, and output against its output schema; a source
+// that fails either, or fails to run, has Err set. onDone, if set, is
+// called as each source finishes.
+func (r *Runner) FanIn(ctx context.Context, sources []agent.Agent, input string, onDone func(FanInResult)) []FanInResult {
+	results := make([]FanInResult, len(sources))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			output, err := r.fanInSource(ctx, src, input)
+			res := FanInResult{
+				Handle:   src.Handle,
+				Key:      agent.MergeKey(src.Handle),
+				Output:   output,
+				Err:      err,
+				Duration: time.Since(start),
+			}
+			results[i] = res
+			if onDone != nil {
+				mu.Lock()
+				onDone(res)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// fanInSource runs one source and returns its output as JSON.
+func (r *Runner) fanInSource(ctx context.Context, src agent.Agent, input string) (json.RawMessage, error) {
+	if err := src.ValidateInput(input); err != nil {
+		return nil, err
+	}
+	resp, err := r.Text(ctx, src, input, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !src.HasOutputSchema() {
+		return json.Marshal(resp)
+	}
+	if err := src.ValidateOutput(resp); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(resp), nil
+}
+
+// MergeInput builds the merge agent's input from fan-in results: a JSON
+// object of the successful sources' outputs by key.
+func MergeInput(results []FanInResult) (string, error) {
+	merged := make(map[string]json.RawMessage, len(results))
+	for _, res := range results {
+		if res.Err == nil {
+			merged[res.Key] = res.Output
+		}
+	}
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,93 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"charm.land/fantasy/schema"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestMergeInput(t *testing.T) {
+	got, err := MergeInput([]FanInResult{
+		{Key: "research", Output: json.RawMessage(`{"findings": ["a"]}`)},
+		{Key: "critic", Output: json.RawMessage(`"looks fine"`)},
+		{Key: "broken", Err: errors.New("timeout")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged map[string]any
+	if err := json.Unmarshal([]byte(got), &merged); err != nil {
+		t.Fatalf("merged input is not JSON: %v\n%s", err, got)
+	}
+	if len(merged) != 2 || merged["critic"] != "looks fine" {
+		t.Errorf("merged = %v", merged)
+	}
+	if _, ok := merged["broken"]; ok {
+		t.Error("failed source should be left out")
+	}
+}
+
+func TestRunnerFanIn(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, choice := range []string{
+			`{"index":0,"delta":{"role":"assistant","content":"Looks fine."}}`,
+			`{"index":0,"delta":{},"finish_reason":"stop"}`,
+		} {
+			io.WriteString(w, `data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"test","choices":[`+choice+"]}\n\n")
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	r := &Runner{config: config.Config{
+		Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+	}}
+	r.SetStreamWriter(NullWriter{})
+
+	sources := []agent.Agent{
+		{Handle: "@critic", Model: "test"},
+		{Handle: "@strict", Model: "test", InputSchema: &schema.Schema{
+			Type:     "object",
+			Required: []string{"topic"},
+		}},
+		{Handle: "@reviewer", Model: "test"},
+	}
+	var done []string
+	results := r.FanIn(context.Background(), sources, "plain text", func(res FanInResult) {
+		done = append(done, res.Handle)
+	})
+
+	if len(results) != 3 || len(done) != 3 {
+		t.Fatalf("results = %d, onDone calls = %d", len(results), len(done))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("@critic failed: %v", results[0].Err)
+	}
+	if results[0].Key != "critic" || string(results[0].Output) != `"Looks fine."` {
+		t.Errorf("@critic = %s %s", results[0].Key, results[0].Output)
+	}
+	var inputErr *agent.InputValidationError
+	if !errors.As(results[1].Err, &inputErr) {
+		t.Errorf("@strict err = %v, want an input validation error", results[1].Err)
+	}
+	if results[2].Err != nil || results[2].Key != "reviewer" {
+		t.Errorf("@reviewer = %s %v", results[2].Key, results[2].Err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d, want @strict not to reach the provider", n)
+	}
+}
@@ -81,6 +81,31 @@ func (a *FantasyAdapter) OnToolCall(tc fantasy.ToolCallContent) error {
 	return nil
 }
 
+// OnToolCallDelta is called while a tool call's arguments stream. It is
+// forwarded to writers that display partial tool calls.
+func (a *FantasyAdapter) OnToolCallDelta(id, name, input string) error {
+	w, ok := a.writer.(ToolInputWriter)
+	if !ok {
+		return nil
+	}
+	call := ToolCall{ID: id, Name: name, Input: input}
+	if name == "bash" {
+		call.Command = partialJSONString(input, "command")
+		call.Description = partialJSONString(input, "description")
+	}
+	w.WriteToolInput(call)
+	return nil
+}
+
+// OnPhase is called when the turn starts waiting in a new phase. It is
+// forwarded to writers that display the phase.
+func (a *FantasyAdapter) OnPhase(phase string) error {
+	if w, ok := a.writer.(PhaseWriter); ok {
+		w.SetPhase(phase)
+	}
+	return nil
+}
+
 // OnToolResult is called by Fantasy when a tool completes.
 func (a *FantasyAdapter) OnToolResult(result fantasy.ToolResultContent, duration time.Duration) error {
 	output := ""
@@ -137,5 +162,10 @@ func (a *FantasyAdapter) OnError(err error) error {
 	return nil
 }
 
-// Verify FantasyAdapter implements StreamHandler (for backward compatibility during transition)
-var _ StreamHandler = (*FantasyAdapter)(nil)
+// Verify FantasyAdapter implements StreamHandler (for backward compatibility
+// during transition), ToolCallDeltaHandler, and PhaseHandler
+var (
+	_ StreamHandler        = (*FantasyAdapter)(nil)
+	_ ToolCallDeltaHandler = (*FantasyAdapter)(nil)
+	_ PhaseHandler         = (*FantasyAdapter)(nil)
+)
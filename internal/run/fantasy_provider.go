@@ -13,17 +13,29 @@ import (
 	"charm.land/fantasy/providers/openaicompat"
 	"charm.land/fantasy/providers/openrouter"
 	"github.com/charmbracelet/catwalk/pkg/catwalk"
+	"github.com/openai/openai-go/v2/option"
+
+	"github.com/alexcabrera/ayo/internal/config"
 )
 
 // NewFantasyProvider creates a Fantasy provider from Catwalk configuration.
 func NewFantasyProvider(p catwalk.Provider) (fantasy.Provider, error) {
+	return newFantasyProvider(p, nil)
+}
+
+// newFantasyProvider creates a Fantasy provider whose requests carry seed,
+// if it is set and the provider supports one.
+func newFantasyProvider(p catwalk.Provider, seed *int64) (fantasy.Provider, error) {
 	apiKey := getProviderAPIKey(p)
 
 	switch p.Type {
 	case catwalk.TypeOpenAI:
-		opts := []openai.Option{
-			openai.WithAPIKey(apiKey),
-			openai.WithUseResponsesAPI(),
+		opts := []openai.Option{openai.WithAPIKey(apiKey)}
+		if seed != nil {
+			// The Responses API has no seed; Chat Completions does
+			opts = append(opts, openai.WithSDKOptions(option.WithJSONSet("seed", *seed)))
+		} else {
+			opts = append(opts, openai.WithUseResponsesAPI())
 		}
 		if p.APIEndpoint != "" {
 			opts = append(opts, openai.WithBaseURL(p.APIEndpoint))
@@ -43,6 +55,9 @@ func NewFantasyProvider(p catwalk.Provider) (fantasy.Provider, error) {
 		if len(p.DefaultHeaders) > 0 {
 			opts = append(opts, openaicompat.WithHeaders(p.DefaultHeaders))
 		}
+		if seed != nil {
+			opts = append(opts, openaicompat.WithSDKOptions(option.WithJSONSet("seed", *seed)))
+		}
 		return openaicompat.New(opts...)
 
 	case catwalk.TypeAnthropic:
@@ -67,6 +82,9 @@ func NewFantasyProvider(p catwalk.Provider) (fantasy.Provider, error) {
 				openaicompat.WithAPIKey(apiKey),
 				openaicompat.WithBaseURL(p.APIEndpoint),
 			}
+			if seed != nil {
+				opts = append(opts, openaicompat.WithSDKOptions(option.WithJSONSet("seed", *seed)))
+			}
 			return openaicompat.New(opts...)
 		}
 		return nil, fmt.Errorf("unsupported provider type: %s", p.Type)
@@ -84,9 +102,38 @@ func getProviderAPIKey(p catwalk.Provider) string {
 
 // NewLanguageModel creates a Fantasy language model from provider and model ID.
 func NewLanguageModel(ctx context.Context, p catwalk.Provider, modelID string) (fantasy.LanguageModel, error) {
-	provider, err := NewFantasyProvider(p)
+	return newLanguageModel(ctx, p, modelID, nil)
+}
+
+// newLanguageModel creates a language model that samples with seed, if set.
+func newLanguageModel(ctx context.Context, p catwalk.Provider, modelID string, seed *int64) (fantasy.LanguageModel, error) {
+	provider, err := newFantasyProvider(p, seed)
 	if err != nil {
 		return nil, fmt.Errorf("create provider: %w", err)
 	}
 	return provider.LanguageModel(ctx, modelID)
 }
+
+// IsLocalProvider reports whether p runs on this machine, such as Ollama
+// serving its OpenAI-compatible API on localhost. Providers without an
+// endpoint are the hosted APIs.
+func IsLocalProvider(p catwalk.Provider) bool {
+	return p.APIEndpoint != "" && config.IsLocalEndpoint(p.APIEndpoint)
+}
+
+// checkOffline returns an error naming the agent and model when offline
+// mode is on and p is not a local provider.
+func checkOffline(cfg config.Config, handle, modelID string) error {
+	if !cfg.Offline || IsLocalProvider(cfg.Provider) {
+		return nil
+	}
+	provider := cfg.Provider.Name
+	if provider == "" {
+		provider = string(cfg.Provider.ID)
+	}
+	where := provider
+	if cfg.Provider.APIEndpoint != "" {
+		where += " at " + cfg.Provider.APIEndpoint
+	}
+	return fmt.Errorf("offline mode: %s uses model %s from %s, which is not on this machine; configure a local provider such as Ollama, or turn off offline mode (--offline, %s, or the offline config setting)", handle, modelID, where, config.OfflineEnvVar)
+}
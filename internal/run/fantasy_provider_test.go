@@ -0,0 +1,37 @@
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestCheckOffline(t *testing.T) {
+	remote := catwalk.Provider{Name: "openai", APIEndpoint: "https://api.openai.com/v1"}
+	local := catwalk.Provider{Name: "ollama", APIEndpoint: "http://localhost:11434/v1"}
+
+	if err := checkOffline(config.Config{Provider: remote}, "@reviewer", "gpt-5.2"); err != nil {
+		t.Errorf("online: %v", err)
+	}
+	if err := checkOffline(config.Config{Provider: local, Offline: true}, "@reviewer", "llama3"); err != nil {
+		t.Errorf("offline with a local provider: %v", err)
+	}
+
+	err := checkOffline(config.Config{Provider: remote, Offline: true}, "@reviewer", "gpt-5.2")
+	if err == nil {
+		t.Fatal("offline with a remote provider succeeded")
+	}
+	for _, want := range []string{"@reviewer", "gpt-5.2", "openai at https://api.openai.com/v1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// Hosted providers have no endpoint
+	if IsLocalProvider(catwalk.Provider{Name: "anthropic"}) {
+		t.Error("IsLocalProvider = true for a provider without an endpoint")
+	}
+}
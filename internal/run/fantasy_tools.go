@@ -18,6 +18,7 @@ import (
 	"github.com/alexcabrera/ayo/internal/memory"
 	"github.com/alexcabrera/ayo/internal/plugins"
 	"github.com/alexcabrera/ayo/internal/tools"
+	uipkg "github.com/alexcabrera/ayo/internal/ui"
 )
 
 // Tool parameter types for Fantasy
@@ -178,6 +179,9 @@ func NewFantasyToolSetWithOptions(allowed []string, baseDir string, memQueue *me
 		case "memory":
 			fantasyTools = append(fantasyTools, NewMemoryToolWithQueue(memQueue))
 			loadedTools[resolvedName] = true
+		case HTTPToolName:
+			fantasyTools = append(fantasyTools, NewHTTPTool(cfg.HTTP))
+			loadedTools[resolvedName] = true
 		// agent_call is added separately when needed
 		default:
 			// Try to load as external tool from plugins
@@ -213,6 +217,16 @@ func (ts FantasyToolSet) HasTool(name string) bool {
 	return false
 }
 
+// Loaded checks if a tool with the given name is in the set.
+func (ts FantasyToolSet) Loaded(name string) bool {
+	for _, t := range ts.tools {
+		if t.Info().Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Close releases resources held by stateful tools.
 func (ts *FantasyToolSet) Close() error {
 	var lastErr error
@@ -228,7 +242,7 @@ func (ts *FantasyToolSet) Close() error {
 func (ts *FantasyToolSet) AddAgentCallTool(executor func(ctx context.Context, params AgentCallParams, call fantasy.ToolCall) (fantasy.ToolResponse, error)) {
 	ts.tools = append(ts.tools, fantasy.NewAgentTool(
 		"agent_call",
-		"Call a builtin agent as a subprocess and get its response. Use this to delegate specialized tasks to other agents.",
+		"Call a builtin agent as a subprocess and get its response. Use this to delegate specialized tasks to other agents. Agents with an output schema respond with JSON matching the schema.",
 		executor,
 	))
 }
@@ -450,6 +464,9 @@ func loadExternalTool(toolName string, baseDir string, depth int) fantasy.AgentT
 					continue
 				}
 
+				// A broken template falls back to the generic output
+				_ = uipkg.LoadToolRendererTemplate(resolvedName, filepath.Join(plugin.Path, "tools", resolvedName, plugins.ToolRenderTemplate))
+
 				return NewExternalTool(def, plugin.Path, baseDir, depth)
 			}
 		}
@@ -0,0 +1,165 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// HTTPToolName is the name of the built-in http tool.
+const HTTPToolName = "http"
+
+const (
+	httpDefaultTimeout     = 30 * time.Second
+	httpDefaultMaxResponse = 64 * 1024
+	httpMaxRedirects       = 10
+)
+
+// HTTPParams defines the parameters for the http tool.
+type HTTPParams struct {
+	Method  string            `json:"method,omitempty" description:"HTTP method: GET (default) or POST"`
+	URL     string            `json:"url" description:"Absolute http or https URL to request"`
+	Headers map[string]string `json:"headers,omitempty" description:"Optional request headers"`
+	Body    string            `json:"body,omitempty" description:"Optional request body for POST"`
+}
+
+// httpToolResult is the JSON returned to the agent.
+type httpToolResult struct {
+	Status    int               `json:"status"`
+	Headers   map[string]string `json:"headers"`
+	Body      string            `json:"body"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+// hostNotAllowedError is returned when a request or redirect targets a host
+// outside the allowlist.
+type hostNotAllowedError struct {
+	host string
+}
+
+func (e hostNotAllowedError) Error() string {
+	return fmt.Sprintf("host %s is not allowed: add it to http.allowed_hosts in ayo.json to let agents reach it", e.host)
+}
+
+// NewHTTPTool creates the http tool. Requests are limited to the hosts in
+// cfg.AllowedHosts, including the targets of redirects.
+func NewHTTPTool(cfg config.HTTPToolConfig) fantasy.AgentTool {
+	timeout := httpDefaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	maxResponse := httpDefaultMaxResponse
+	if cfg.MaxResponseBytes > 0 {
+		maxResponse = cfg.MaxResponseBytes
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= httpMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", httpMaxRedirects)
+			}
+			if !httpHostAllowed(req.URL.Hostname(), cfg.AllowedHosts) {
+				return hostNotAllowedError{host: req.URL.Hostname()}
+			}
+			return nil
+		},
+	}
+
+	return fantasy.NewAgentTool(
+		HTTPToolName,
+		"Make an HTTP GET or POST request to an allowed host and return the status, headers, and body as JSON. Only hosts in the user's http.allowed_hosts config can be reached.",
+		func(ctx context.Context, params HTTPParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			method := strings.ToUpper(strings.TrimSpace(params.Method))
+			if method == "" {
+				method = http.MethodGet
+			}
+			if method != http.MethodGet && method != http.MethodPost {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("unsupported method %q: use GET or POST", params.Method)), nil
+			}
+
+			target, err := url.Parse(params.URL)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("invalid url %q: provide an absolute http or https URL", params.URL)), nil
+			}
+			if !httpHostAllowed(target.Hostname(), cfg.AllowedHosts) {
+				return fantasy.NewTextErrorResponse(hostNotAllowedError{host: target.Hostname()}.Error()), nil
+			}
+
+			var body io.Reader
+			if params.Body != "" {
+				body = strings.NewReader(params.Body)
+			}
+			req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("invalid request: %v", err)), nil
+			}
+			for k, v := range params.Headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				var blocked hostNotAllowedError
+				if errors.As(err, &blocked) {
+					return fantasy.NewTextErrorResponse("redirect blocked: " + blocked.Error()), nil
+				}
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("request failed: %v", err)), nil
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxResponse)+1))
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("read response: %v", err)), nil
+			}
+
+			result := httpToolResult{
+				Status:  resp.StatusCode,
+				Headers: make(map[string]string, len(resp.Header)),
+				Body:    string(data),
+			}
+			if len(data) > maxResponse {
+				result.Body = string(data[:maxResponse])
+				result.Truncated = true
+			}
+			for k := range resp.Header {
+				result.Headers[k] = resp.Header.Get(k)
+			}
+
+			out, err := json.Marshal(result)
+			if err != nil {
+				return fantasy.ToolResponse{}, err
+			}
+			return fantasy.NewTextResponse(string(out)), nil
+		},
+	)
+}
+
+// httpHostAllowed reports whether host matches an allowlist entry: the same
+// host, or a subdomain for entries of the form "*.example.com".
+func httpHostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if suffix, ok := strings.CutPrefix(entry, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == entry {
+			return true
+		}
+	}
+	return false
+}
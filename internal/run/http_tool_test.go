@@ -0,0 +1,122 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func runHTTPTool(t *testing.T, tool fantasy.AgentTool, params HTTPParams) fantasy.ToolResponse {
+	t.Helper()
+	input, _ := json.Marshal(params)
+	resp, err := tool.Run(context.Background(), fantasy.ToolCall{ID: "1", Name: HTTPToolName, Input: string(input)})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return resp
+}
+
+func TestHTTPTool(t *testing.T) {
+	var gotMethod, gotBody, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotMethod, gotBody, gotHeader = r.Method, string(data), r.Header.Get("X-Test")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+
+	tool := NewHTTPTool(config.HTTPToolConfig{AllowedHosts: []string{"127.0.0.1"}})
+	resp := runHTTPTool(t, tool, HTTPParams{Method: "post", URL: server.URL, Headers: map[string]string{"X-Test": "yes"}, Body: "hello"})
+	if resp.IsError {
+		t.Fatalf("unexpected error: %s", resp.Content)
+	}
+
+	var result httpToolResult
+	if err := json.Unmarshal([]byte(resp.Content), &result); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if result.Status != http.StatusCreated || result.Body != `{"id": 7}` || result.Headers["Content-Type"] != "application/json" {
+		t.Errorf("result = %+v", result)
+	}
+	if gotMethod != "POST" || gotBody != "hello" || gotHeader != "yes" {
+		t.Errorf("server saw %s %q with X-Test %q", gotMethod, gotBody, gotHeader)
+	}
+}
+
+func TestHTTPToolBlocksHosts(t *testing.T) {
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a blocked host")
+	}))
+	defer blocked.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(blocked.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	// No allowlist blocks everything
+	resp := runHTTPTool(t, NewHTTPTool(config.HTTPToolConfig{}), HTTPParams{URL: blocked.URL})
+	if !resp.IsError || !strings.Contains(resp.Content, "http.allowed_hosts") {
+		t.Errorf("empty allowlist: %+v", resp)
+	}
+
+	// Redirects are checked against the allowlist too
+	tool := NewHTTPTool(config.HTTPToolConfig{AllowedHosts: []string{"127.0.0.1"}})
+	resp = runHTTPTool(t, tool, HTTPParams{URL: redirect.URL})
+	if !resp.IsError || !strings.Contains(resp.Content, "redirect blocked: host localhost") {
+		t.Errorf("redirect: %+v", resp)
+	}
+
+	resp = runHTTPTool(t, tool, HTTPParams{Method: "DELETE", URL: redirect.URL})
+	if !resp.IsError || !strings.Contains(resp.Content, "unsupported method") {
+		t.Errorf("DELETE: %+v", resp)
+	}
+	resp = runHTTPTool(t, tool, HTTPParams{URL: "file:///etc/passwd"})
+	if !resp.IsError || !strings.Contains(resp.Content, "invalid url") {
+		t.Errorf("file URL: %+v", resp)
+	}
+}
+
+func TestHTTPToolTruncatesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+
+	tool := NewHTTPTool(config.HTTPToolConfig{AllowedHosts: []string{"127.0.0.1"}, MaxResponseBytes: 10})
+	resp := runHTTPTool(t, tool, HTTPParams{URL: server.URL})
+
+	var result httpToolResult
+	json.Unmarshal([]byte(resp.Content), &result)
+	if len(result.Body) != 10 || !result.Truncated {
+		t.Errorf("body = %d bytes, truncated = %v; want 10, true", len(result.Body), result.Truncated)
+	}
+}
+
+func TestHTTPHostAllowed(t *testing.T) {
+	allowed := []string{"api.github.com", "*.Example.com"}
+	tests := map[string]bool{
+		"api.github.com":      true,
+		"API.GitHub.com":      true,
+		"github.com":          false,
+		"docs.example.com":    true,
+		"a.b.example.com":     true,
+		"example.com":         false,
+		"notexample.com":      false,
+		"example.com.evil.io": false,
+	}
+	for host, want := range tests {
+		if got := httpHostAllowed(host, allowed); got != want {
+			t.Errorf("httpHostAllowed(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package run
+
+// PhaseHandler is implemented by stream handlers that show what a turn is
+// waiting on while nothing else is being written, such as the model
+// thinking or retrying a failed call.
+type PhaseHandler interface {
+	// OnPhase is called with one of the ui.Phase constants when the turn
+	// starts waiting in that phase, and with an empty phase when it stops
+	// waiting.
+	OnPhase(phase string) error
+}
+
+// PhaseWriter is implemented by stream writers that display the current
+// phase, like PrintWriter's spinner.
+type PhaseWriter interface {
+	SetPhase(phase string)
+}
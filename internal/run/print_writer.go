@@ -2,6 +2,7 @@ package run
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/alexcabrera/ayo/internal/ui"
@@ -14,13 +15,14 @@ type PrintWriter struct {
 	spinner       *ui.Spinner
 	spinnerActive bool
 	textStarted   bool
+	midLine       bool // Streamed text or reasoning doesn't end in a newline
 	agentHandle   string
 }
 
 // NewPrintWriter creates a writer for non-interactive output.
 func NewPrintWriter(agentHandle string, debug bool, depth int) *PrintWriter {
 	u := ui.NewWithDepth(debug, depth)
-	spinner := ui.NewSpinnerWithDepth("thinking...", depth)
+	spinner := ui.NewPhaseSpinner(ui.PhaseThinking, depth)
 	spinner.Start()
 	return &PrintWriter{
 		ui:            u,
@@ -32,7 +34,7 @@ func NewPrintWriter(agentHandle string, debug bool, depth int) *PrintWriter {
 
 // NewPrintWriterWithUI creates a writer with an existing UI instance.
 func NewPrintWriterWithUI(u *ui.UI, agentHandle string) *PrintWriter {
-	spinner := ui.NewSpinnerWithDepth("thinking...", u.Depth())
+	spinner := ui.NewPhaseSpinner(ui.PhaseThinking, u.Depth())
 	spinner.Start()
 	return &PrintWriter{
 		ui:            u,
@@ -43,41 +45,37 @@ func NewPrintWriterWithUI(u *ui.UI, agentHandle string) *PrintWriter {
 }
 
 func (w *PrintWriter) WriteText(delta string) {
-	if w.spinnerActive {
-		w.spinner.Stop()
-		w.spinnerActive = false
-	}
+	w.stopSpinner()
 	if !w.textStarted {
 		w.textStarted = true
 		w.ui.PrintAgentResponseHeader(w.agentHandle)
 	}
 	w.ui.PrintTextDelta(delta)
+	w.trackLine(delta)
 }
 
 func (w *PrintWriter) WriteTextDone(content string) {
+	w.stopSpinner()
 	w.ui.PrintTextEnd()
+	w.midLine = false
 }
 
 func (w *PrintWriter) WriteReasoning(delta string) {
-	if w.spinnerActive {
-		w.spinner.Stop()
-		w.spinnerActive = false
-	}
+	w.stopSpinner()
 	w.ui.PrintReasoningDelta(delta)
+	w.trackLine(delta)
 }
 
 func (w *PrintWriter) WriteReasoningDone(content string, duration time.Duration) {
 	w.ui.PrintReasoningEnd()
+	w.midLine = false
 	if duration > 0 {
 		w.ui.PrintThinkingDone(formatDuration(duration))
 	}
 }
 
 func (w *PrintWriter) WriteToolStart(call ToolCall) {
-	if w.spinnerActive {
-		w.spinner.Stop()
-		w.spinnerActive = false
-	}
+	w.stopSpinner()
 	info := ui.ToolCallInfo{
 		Name:        call.Name,
 		Description: call.Description,
@@ -88,6 +86,7 @@ func (w *PrintWriter) WriteToolStart(call ToolCall) {
 }
 
 func (w *PrintWriter) WriteToolResult(result ToolResult) {
+	w.stopSpinner()
 	info := ui.ToolCallInfo{
 		Name:     result.Name,
 		Output:   result.Output,
@@ -99,14 +98,17 @@ func (w *PrintWriter) WriteToolResult(result ToolResult) {
 }
 
 func (w *PrintWriter) WriteAgentStart(handle, prompt string) {
+	w.stopSpinner()
 	w.ui.PrintSubAgentStart(handle, prompt)
 }
 
 func (w *PrintWriter) WriteAgentEnd(handle string, duration time.Duration, err error) {
+	w.stopSpinner()
 	w.ui.PrintSubAgentEnd(handle, formatDuration(duration), err != nil)
 }
 
 func (w *PrintWriter) WriteMemoryEvent(event string, count int) {
+	w.stopSpinner()
 	w.ui.PrintMemoryEvent(ui.MemoryEventType(event))
 }
 
@@ -119,11 +121,54 @@ func (w *PrintWriter) WriteError(err error) {
 }
 
 func (w *PrintWriter) WriteDone(response string) {
-	// Nothing special to do for print mode - output is already written
+	w.stopSpinner()
+}
+
+// SetPhase shows phase on the spinner, starting a new spinner if output
+// stopped the last one. An empty phase stops it. A new spinner is only
+// started on a terminal, and not in the middle of a line of streamed text,
+// which redrawing the spinner would erase.
+func (w *PrintWriter) SetPhase(phase string) {
+	if phase == "" {
+		w.stopSpinner()
+		return
+	}
+	if w.spinnerActive {
+		w.spinner.SetPhase(phase)
+		return
+	}
+	if w.midLine {
+		return
+	}
+	spinner := ui.NewPhaseSpinner(phase, w.ui.Depth())
+	if !spinner.Animated() {
+		return
+	}
+	spinner.Start()
+	w.spinner = spinner
+	w.spinnerActive = true
 }
 
-// Verify PrintWriter implements StreamWriter
-var _ StreamWriter = (*PrintWriter)(nil)
+// stopSpinner clears the spinner before other output is written.
+func (w *PrintWriter) stopSpinner() {
+	if w.spinnerActive {
+		w.spinner.Stop()
+		w.spinnerActive = false
+	}
+}
+
+// trackLine records whether streamed output left the cursor mid-line.
+func (w *PrintWriter) trackLine(delta string) {
+	if delta != "" {
+		w.midLine = !strings.HasSuffix(delta, "\n")
+	}
+}
+
+// Verify PrintWriter implements StreamWriter and PhaseWriter
+var (
+	_ StreamWriter = (*PrintWriter)(nil)
+	_ PhaseWriter  = (*PrintWriter)(nil)
+)
 
 // extractBashParams extracts command and description from bash tool input JSON.
 func extractBashParams(input string) (command, description string) {
@@ -0,0 +1,82 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// ProbeStatus is the outcome of probing a provider.
+type ProbeStatus string
+
+const (
+	ProbeOK          ProbeStatus = "ok"          // The provider answered
+	ProbeAuthFailed  ProbeStatus = "auth_failed" // Reachable, but the key was rejected
+	ProbeUnreachable ProbeStatus = "unreachable" // No response from the endpoint
+	ProbeError       ProbeStatus = "error"       // Reachable, but the request failed otherwise
+	ProbeOffline     ProbeStatus = "offline"     // Not probed: offline mode and the provider is remote
+)
+
+// ProbeResult is the result of probing a provider.
+type ProbeResult struct {
+	Status     ProbeStatus
+	StatusCode int // HTTP status of a failed request
+	Detail     string
+	Latency    time.Duration
+}
+
+// ProbeProvider checks that p is reachable and accepts its API key by
+// asking modelID for a single token, the cheapest request every provider
+// type supports. In offline mode only local providers are probed. ctx
+// should carry a timeout.
+func ProbeProvider(ctx context.Context, p catwalk.Provider, modelID string, offline bool) ProbeResult {
+	if err := checkOffline(config.Config{Offline: offline, Provider: p}, "the provider test", modelID); err != nil {
+		return ProbeResult{Status: ProbeOffline, Detail: err.Error()}
+	}
+	start := time.Now()
+	model, err := NewLanguageModel(ctx, p, modelID)
+	if err != nil {
+		return ProbeResult{Status: ProbeError, Detail: err.Error()}
+	}
+	maxTokens := int64(1)
+	_, err = model.Generate(ctx, fantasy.Call{
+		Prompt:          fantasy.Prompt{fantasy.NewUserMessage("ping")},
+		MaxOutputTokens: &maxTokens,
+	})
+	result := classifyProbeError(err)
+	result.Latency = time.Since(start)
+	return result
+}
+
+// classifyProbeError maps a probe request's error to a result. Any HTTP
+// response other than 401 or 403 shows the key was accepted, so a rate
+// limit or unknown model is reported as an error rather than an auth
+// failure.
+func classifyProbeError(err error) ProbeResult {
+	if err == nil {
+		return ProbeResult{Status: ProbeOK}
+	}
+	result := ProbeResult{Status: ProbeError, Detail: err.Error()}
+
+	var providerErr *fantasy.ProviderError
+	if errors.As(err, &providerErr) && providerErr.StatusCode != 0 {
+		result.StatusCode = providerErr.StatusCode
+		if providerErr.StatusCode == http.StatusUnauthorized || providerErr.StatusCode == http.StatusForbidden {
+			result.Status = ProbeAuthFailed
+		}
+		return result
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		result.Status = ProbeUnreachable
+	}
+	return result
+}
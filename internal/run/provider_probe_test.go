@@ -0,0 +1,68 @@
+package run
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+)
+
+func TestProbeProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+			io.WriteString(w, `{"id":"c1","object":"chat.completion","created":1,"model":"test",
+				"choices":[{"index":0,"message":{"role":"assistant","content":"p"},"finish_reason":"length"}],
+				"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+		case "Bearer limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"error":{"message":"slow down","type":"rate_limit"}}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":{"message":"invalid api key","type":"invalid_request_error"}}`)
+		}
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		key      string
+		want     ProbeStatus
+		wantCode int
+	}{
+		{"valid key", server.URL, "good", ProbeOK, 0},
+		{"rejected key", server.URL, "bad", ProbeAuthFailed, http.StatusUnauthorized},
+		{"rate limited", server.URL, "limited", ProbeError, http.StatusTooManyRequests},
+		{"nothing listening", closedURL, "good", ProbeUnreachable, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			p := catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: tt.endpoint, APIKey: tt.key}
+			got := ProbeProvider(ctx, p, "test", false)
+			if got.Status != tt.want || got.StatusCode != tt.wantCode {
+				t.Errorf("ProbeProvider() = %s (%d, %q), want %s (%d)", got.Status, got.StatusCode, got.Detail, tt.want, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestProbeProviderOffline(t *testing.T) {
+	p := catwalk.Provider{ID: "anthropic", Type: catwalk.TypeAnthropic, APIEndpoint: "https://api.anthropic.com", APIKey: "key"}
+	got := ProbeProvider(context.Background(), p, "claude-haiku-4-5", true)
+	if got.Status != ProbeOffline || !strings.Contains(got.Detail, "offline mode") {
+		t.Errorf("ProbeProvider() = %s (%q), want offline", got.Status, got.Detail)
+	}
+}
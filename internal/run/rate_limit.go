@@ -0,0 +1,273 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+// rateLimiter throttles calls to a provider with two token buckets, one
+// for requests and one for tokens. Each bucket holds a minute's allowance
+// and refills continuously, so bursts up to the limit go through at once
+// and sustained load is spread out.
+type rateLimiter struct {
+	mu                sync.Mutex
+	requestsPerMinute float64 // 0 = unlimited
+	tokensPerMinute   float64 // 0 = unlimited
+	requests          float64 // Requests available now
+	tokens            float64 // Tokens available now; negative after underestimates
+	updated           time.Time
+	now               func() time.Time
+}
+
+// rateLimitState is a limiter's state after a call was let through.
+type rateLimitState struct {
+	Waited            time.Duration
+	Requests          int
+	RequestsPerMinute int
+	Tokens            int
+	TokensPerMinute   int
+}
+
+// String describes the state for verbose output.
+func (s rateLimitState) String() string {
+	var parts []string
+	if s.RequestsPerMinute > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d requests", s.Requests, s.RequestsPerMinute))
+	}
+	if s.TokensPerMinute > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d tokens", s.Tokens, s.TokensPerMinute))
+	}
+	status := "Rate limit: " + strings.Join(parts, ", ") + " left this minute"
+	if s.Waited > 0 {
+		status += fmt.Sprintf(" (waited %s)", s.Waited.Round(100*time.Millisecond))
+	}
+	return status
+}
+
+// rateLimiters holds one limiter per provider, shared by every runner in the
+// process so parallel sub-agents, batch items, and title generation are
+// throttled together.
+var rateLimiters = struct {
+	sync.Mutex
+	byProvider map[string]*rateLimiter
+}{byProvider: make(map[string]*rateLimiter)}
+
+// newRateLimit validates cfg and returns the shared limiter for provider,
+// or nil when cfg sets no limits.
+func newRateLimit(provider string, cfg config.RateLimitConfig) (*rateLimiter, error) {
+	if cfg.RequestsPerMinute < 0 {
+		return nil, fmt.Errorf("rate_limit config: requests_per_minute must be 0 or more, got %d", cfg.RequestsPerMinute)
+	}
+	if cfg.TokensPerMinute < 0 {
+		return nil, fmt.Errorf("rate_limit config: tokens_per_minute must be 0 or more, got %d", cfg.TokensPerMinute)
+	}
+	if cfg.RequestsPerMinute == 0 && cfg.TokensPerMinute == 0 {
+		return nil, nil
+	}
+
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+	l := rateLimiters.byProvider[provider]
+	if l == nil {
+		l = newRateLimiter(cfg, time.Now)
+		rateLimiters.byProvider[provider] = l
+		return l, nil
+	}
+	l.setLimits(cfg)
+	return l, nil
+}
+
+// newRateLimiter returns a limiter with full buckets.
+func newRateLimiter(cfg config.RateLimitConfig, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		requestsPerMinute: float64(cfg.RequestsPerMinute),
+		tokensPerMinute:   float64(cfg.TokensPerMinute),
+		requests:          float64(cfg.RequestsPerMinute),
+		tokens:            float64(cfg.TokensPerMinute),
+		updated:           now(),
+		now:               now,
+	}
+}
+
+// setLimits changes the limits, keeping what has been used this minute.
+func (l *rateLimiter) setLimits(cfg config.RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.requestsPerMinute = float64(cfg.RequestsPerMinute)
+	l.tokensPerMinute = float64(cfg.TokensPerMinute)
+	l.requests = min(l.requests, l.requestsPerMinute)
+	l.tokens = min(l.tokens, l.tokensPerMinute)
+}
+
+// refill adds the allowance accrued since the last update. The caller must
+// hold l.mu.
+func (l *rateLimiter) refill() {
+	now := l.now()
+	minutes := now.Sub(l.updated).Minutes()
+	l.updated = now
+	if minutes <= 0 {
+		return
+	}
+	l.requests = min(l.requests+minutes*l.requestsPerMinute, l.requestsPerMinute)
+	l.tokens = min(l.tokens+minutes*l.tokensPerMinute, l.tokensPerMinute)
+}
+
+// reserve takes one request and tokens from the buckets if both have
+// enough, and otherwise returns how long to wait before trying again. A
+// call larger than the token limit only needs a full bucket.
+func (l *rateLimiter) reserve(tokens int) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+
+	need := min(float64(tokens), l.tokensPerMinute)
+	var wait float64 // Minutes
+	if l.requestsPerMinute > 0 && l.requests < 1 {
+		wait = max(wait, (1-l.requests)/l.requestsPerMinute)
+	}
+	if l.tokensPerMinute > 0 && l.tokens < need {
+		wait = max(wait, (need-l.tokens)/l.tokensPerMinute)
+	}
+	if wait > 0 {
+		return max(time.Duration(wait*float64(time.Minute)), time.Millisecond), false
+	}
+
+	if l.requestsPerMinute > 0 {
+		l.requests--
+	}
+	if l.tokensPerMinute > 0 {
+		l.tokens -= float64(tokens)
+	}
+	return 0, true
+}
+
+// wait blocks until a call of tokens estimated tokens may proceed.
+func (l *rateLimiter) wait(ctx context.Context, tokens int) (rateLimitState, error) {
+	start := l.now()
+	for {
+		delay, ok := l.reserve(tokens)
+		if ok {
+			break
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return rateLimitState{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return rateLimitState{
+		Waited:            l.now().Sub(start),
+		Requests:          int(max(l.requests, 0)),
+		RequestsPerMinute: int(l.requestsPerMinute),
+		Tokens:            int(max(l.tokens, 0)),
+		TokensPerMinute:   int(l.tokensPerMinute),
+	}, nil
+}
+
+// settle corrects the token bucket once a call's actual usage is known.
+func (l *rateLimiter) settle(estimated int, usage fantasy.Usage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tokensPerMinute == 0 || usage.TotalTokens == 0 {
+		return
+	}
+	l.tokens = min(l.tokens-float64(usage.TotalTokens)+float64(estimated), l.tokensPerMinute)
+}
+
+// wrap returns model throttled by l, calling onThrottle, if set, as each
+// call is let through. A nil limiter returns model unchanged.
+func (l *rateLimiter) wrap(model fantasy.LanguageModel, onThrottle func(rateLimitState)) fantasy.LanguageModel {
+	if l == nil {
+		return model
+	}
+	return &rateLimitedModel{LanguageModel: model, limiter: l, onThrottle: onThrottle}
+}
+
+// rateLimitedModel is a language model whose calls wait for the provider's
+// rate limiter.
+type rateLimitedModel struct {
+	fantasy.LanguageModel
+	limiter    *rateLimiter
+	onThrottle func(rateLimitState)
+}
+
+// acquire waits for the limiter and returns the prompt's estimated tokens.
+func (m *rateLimitedModel) acquire(ctx context.Context, prompt fantasy.Prompt) (int, error) {
+	estimated := 0
+	for _, msg := range prompt {
+		estimated += estimateTokens(msg)
+	}
+	state, err := m.limiter.wait(ctx, estimated)
+	if err != nil {
+		return 0, err
+	}
+	if m.onThrottle != nil {
+		m.onThrottle(state)
+	}
+	return estimated, nil
+}
+
+func (m *rateLimitedModel) Generate(ctx context.Context, call fantasy.Call) (*fantasy.Response, error) {
+	estimated, err := m.acquire(ctx, call.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.LanguageModel.Generate(ctx, call)
+	if resp != nil {
+		m.limiter.settle(estimated, resp.Usage)
+	}
+	return resp, err
+}
+
+func (m *rateLimitedModel) Stream(ctx context.Context, call fantasy.Call) (fantasy.StreamResponse, error) {
+	estimated, err := m.acquire(ctx, call.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := m.LanguageModel.Stream(ctx, call)
+	if err != nil || stream == nil {
+		return stream, err
+	}
+	return func(yield func(fantasy.StreamPart) bool) {
+		for part := range stream {
+			if part.Type == fantasy.StreamPartTypeFinish {
+				m.limiter.settle(estimated, part.Usage)
+			}
+			if !yield(part) {
+				return
+			}
+		}
+	}, nil
+}
+
+func (m *rateLimitedModel) GenerateObject(ctx context.Context, call fantasy.ObjectCall) (*fantasy.ObjectResponse, error) {
+	estimated, err := m.acquire(ctx, call.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.LanguageModel.GenerateObject(ctx, call)
+	if resp != nil {
+		m.limiter.settle(estimated, resp.Usage)
+	}
+	return resp, err
+}
+
+func (m *rateLimitedModel) StreamObject(ctx context.Context, call fantasy.ObjectCall) (fantasy.ObjectStreamResponse, error) {
+	if _, err := m.acquire(ctx, call.Prompt); err != nil {
+		return nil, err
+	}
+	return m.LanguageModel.StreamObject(ctx, call)
+}
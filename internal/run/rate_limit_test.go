@@ -0,0 +1,108 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestRateLimiterRequests(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(config.RateLimitConfig{RequestsPerMinute: 2}, func() time.Time { return now })
+
+	for i := range 2 {
+		if _, ok := l.reserve(0); !ok {
+			t.Fatalf("request %d was throttled within the limit", i+1)
+		}
+	}
+	delay, ok := l.reserve(0)
+	if ok || delay != 30*time.Second {
+		t.Fatalf("third request: reserve = %v, %v, want a 30s wait", delay, ok)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, ok := l.reserve(0); !ok {
+		t.Error("request was throttled after the bucket refilled")
+	}
+}
+
+func TestRateLimiterTokens(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(config.RateLimitConfig{TokensPerMinute: 600}, func() time.Time { return now })
+
+	if _, ok := l.reserve(500); !ok {
+		t.Fatal("call within the token limit was throttled")
+	}
+	delay, ok := l.reserve(200)
+	if ok || delay != 10*time.Second {
+		t.Fatalf("reserve = %v, %v, want a 10s wait for 100 more tokens", delay, ok)
+	}
+
+	// A call larger than the limit waits for a full bucket, not forever
+	now = now.Add(time.Minute)
+	if _, ok := l.reserve(5000); !ok {
+		t.Error("oversized call was throttled with a full bucket")
+	}
+}
+
+func TestRateLimiterSettle(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(config.RateLimitConfig{TokensPerMinute: 600}, func() time.Time { return now })
+
+	if _, ok := l.reserve(100); !ok {
+		t.Fatal("call within the token limit was throttled")
+	}
+	// The call used the whole minute's tokens, not the 100 estimated
+	l.settle(100, fantasy.Usage{TotalTokens: 600})
+	if _, ok := l.reserve(1); ok {
+		t.Error("reserve succeeded after actual usage exhausted the bucket")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := newRateLimiter(config.RateLimitConfig{RequestsPerMinute: 1200}, time.Now)
+	l.requests = 0
+
+	state, err := l.wait(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if state.Waited < 40*time.Millisecond || state.RequestsPerMinute != 1200 {
+		t.Errorf("state = %+v, want a wait of about 50ms", state)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.requests = 0
+	if _, err := l.wait(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait with canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimitStateString(t *testing.T) {
+	state := rateLimitState{Waited: 1234 * time.Millisecond, Requests: 4, RequestsPerMinute: 60, Tokens: 900, TokensPerMinute: 1000}
+	want := "Rate limit: 4/60 requests, 900/1000 tokens left this minute (waited 1.2s)"
+	if got := state.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestNewRateLimit(t *testing.T) {
+	if l, err := newRateLimit("test", config.RateLimitConfig{}); l != nil || err != nil {
+		t.Errorf("newRateLimit with no limits = %v, %v, want nil, nil", l, err)
+	}
+	if _, err := newRateLimit("test", config.RateLimitConfig{TokensPerMinute: -1}); err == nil {
+		t.Error("newRateLimit accepted a negative limit")
+	}
+
+	a, _ := newRateLimit("shared-test", config.RateLimitConfig{RequestsPerMinute: 10})
+	b, _ := newRateLimit("shared-test", config.RateLimitConfig{RequestsPerMinute: 20})
+	if a != b || b.requestsPerMinute != 20 {
+		t.Error("runners for the same provider don't share one limiter")
+	}
+}
@@ -0,0 +1,104 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/redact"
+)
+
+// newRedactor builds the secret redactor from config, or nil when disabled.
+func newRedactor(cfg config.RedactionConfig) (*redact.Redactor, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	redactor, err := redact.New(cfg.Patterns, cfg.DisableBuiltin)
+	if err != nil {
+		return nil, fmt.Errorf("redaction config: %w", err)
+	}
+	return redactor, nil
+}
+
+// redact masks secrets in text bound for the model and records the count.
+// source describes the text for debug output.
+func (r *Runner) redact(source, text string) string {
+	redacted, n := r.redactor.Redact(text)
+	if n > 0 {
+		r.redactions.Add(int64(n))
+		if r.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: redacted %d secret(s) from %s\n", n, source)
+		}
+	}
+	return redacted
+}
+
+// Redactions returns the number of secrets redacted from messages and tool
+// output sent to the model by this runner.
+func (r *Runner) Redactions() int {
+	return int(r.redactions.Load())
+}
+
+// redactMessage masks secrets in the text of a persisted user message or
+// tool result being replayed to the model. Other messages are unchanged.
+func (r *Runner) redactMessage(msg fantasy.Message) fantasy.Message {
+	if r.redactor == nil {
+		return msg
+	}
+	switch msg.Role {
+	case fantasy.MessageRoleUser, fantasy.MessageRoleTool:
+	default:
+		return msg
+	}
+
+	content := make([]fantasy.MessagePart, len(msg.Content))
+	for i, part := range msg.Content {
+		switch p := part.(type) {
+		case fantasy.TextPart:
+			p.Text = r.redact("session history", p.Text)
+			content[i] = p
+		case fantasy.ToolResultPart:
+			if out, ok := p.Output.(fantasy.ToolResultOutputContentText); ok {
+				out.Text = r.redact("session history", out.Text)
+				p.Output = out
+			}
+			content[i] = p
+		default:
+			content[i] = part
+		}
+	}
+	msg.Content = content
+	return msg
+}
+
+// redactingTool wraps a tool so secrets in its output are masked before the
+// result is returned to the model.
+type redactingTool struct {
+	fantasy.AgentTool
+	runner *Runner
+}
+
+// withRedaction wraps each tool with output redaction. Tools are returned
+// unchanged when redaction is disabled.
+func (r *Runner) withRedaction(tools []fantasy.AgentTool) []fantasy.AgentTool {
+	if r.redactor == nil {
+		return tools
+	}
+	wrapped := make([]fantasy.AgentTool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &redactingTool{AgentTool: t, runner: r}
+	}
+	return wrapped
+}
+
+func (t *redactingTool) Run(ctx context.Context, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+	resp, err := t.AgentTool.Run(ctx, call)
+	if err != nil {
+		return resp, err
+	}
+	resp.Content = t.runner.redact(call.Name+" output", resp.Content)
+	return resp, nil
+}
@@ -0,0 +1,139 @@
+package run
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	uipkg "github.com/alexcabrera/ayo/internal/ui"
+)
+
+// DefaultCacheTTL is how long a cached response is reused unless configured
+// otherwise.
+const DefaultCacheTTL = 24 * time.Hour
+
+// newCacheTTL returns how long responses are cached, or 0 when the cache is
+// disabled or bypassed.
+func newCacheTTL(cfg config.CacheConfig, bypass bool) (time.Duration, error) {
+	if cfg.TTLSeconds < 0 {
+		return 0, fmt.Errorf("cache config: ttl_seconds must be 0 or more, got %d", cfg.TTLSeconds)
+	}
+	if !cfg.Enabled || bypass {
+		return 0, nil
+	}
+	if cfg.TTLSeconds == 0 {
+		return DefaultCacheTTL, nil
+	}
+	return time.Duration(cfg.TTLSeconds) * time.Second, nil
+}
+
+// envDatetime matches the datetime line of the environment context. Only
+// its date is part of the cache key, so the clock doesn't defeat the cache.
+var envDatetime = regexp.MustCompile(`datetime: (\d{4}-\d{2}-\d{2}) \d{2}:\d{2}:\d{2} [^\s"\\]*`)
+
+// cacheKey returns the response cache key for a request, or "" when the
+// response must not be cached. Only agents with temperature 0 are cached.
+func (r *Runner) cacheKey(ag agent.Agent, msgs []fantasy.Message, tools []fantasy.AgentTool) string {
+	if r.cacheTTL <= 0 || r.services == nil || r.services.Cache == nil {
+		return ""
+	}
+	if t := ag.Config.Temperature; t == nil || *t != 0 {
+		return ""
+	}
+
+	toolNames := make([]string, len(tools))
+	for i, t := range tools {
+		toolNames[i] = t.Info().Name
+	}
+	raw, err := json.Marshal(struct {
+		Provider     string            `json:"provider"`
+		Model        string            `json:"model"`
+		Temperature  float64           `json:"temperature"`
+		Seed         *int64            `json:"seed,omitempty"`
+		Tools        []string          `json:"tools"`
+		OutputSchema any               `json:"output_schema,omitempty"`
+		Messages     []fantasy.Message `json:"messages"`
+	}{
+		Provider:     string(r.config.Provider.ID),
+		Model:        ag.Model,
+		Temperature:  *ag.Config.Temperature,
+		Seed:         r.seed,
+		Tools:        toolNames,
+		OutputSchema: ag.OutputSchema,
+		Messages:     msgs,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(envDatetime.ReplaceAll(raw, []byte("datetime: $1")))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedResponse returns the cached response for key. Lookup errors count as
+// a miss.
+func (r *Runner) cachedResponse(ctx context.Context, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	resp, ok, err := r.services.Cache.Get(ctx, key)
+	if err != nil {
+		if r.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: response cache: %v\n", err)
+		}
+		return "", false
+	}
+	return resp, ok
+}
+
+// cacheResponse stores a response under key. Turns that called tools are
+// not cached, since their tools have effects a cached response would skip.
+func (r *Runner) cacheResponse(ctx context.Context, key, model, response string, result *fantasy.AgentResult) {
+	if key == "" || response == "" {
+		return
+	}
+	if result != nil {
+		for _, step := range result.Steps {
+			if len(step.Content.ToolCalls()) > 0 {
+				return
+			}
+		}
+	}
+	if err := r.services.Cache.Put(ctx, key, model, response, r.cacheTTL); err != nil && r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: response cache: %v\n", err)
+	}
+}
+
+// replayCachedResponse returns a cached response the way a model response
+// is returned: text is streamed to the handler and structured output is
+// rendered unless the caller consumes it raw.
+func (r *Runner) replayCachedResponse(ag agent.Agent, msgs []fantasy.Message, handler StreamHandler, response string) (string, []fantasy.Message) {
+	msgs = append(msgs, fantasy.Message{
+		Role:    fantasy.MessageRoleAssistant,
+		Content: []fantasy.MessagePart{fantasy.TextPart{Text: response}},
+	})
+
+	ui := uipkg.NewWithDepth(r.debug, r.depth)
+	if ag.HasOutputSchema() {
+		if r.rawOutput || ui.IsPiped() {
+			return strings.TrimSpace(response), msgs
+		}
+		return strings.TrimSpace(ui.RenderJSON(response)), msgs
+	}
+
+	handler.OnTextDelta("", response)
+	handler.OnTextEnd("")
+	if r.rawOutput || ui.IsPiped() {
+		return strings.TrimSpace(response), msgs
+	}
+	return "", msgs
+}
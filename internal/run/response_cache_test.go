@@ -0,0 +1,129 @@
+package run
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func cacheRunner(t *testing.T, serverURL string, noCache bool) *Runner {
+	t.Helper()
+	services, err := session.Connect(context.Background(), filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { services.Close() })
+
+	cfg := config.Config{
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: serverURL, APIKey: "key"},
+		Redaction: config.RedactionConfig{Disabled: true},
+		Titles:    config.TitlesConfig{Disabled: true},
+		Cache:     config.CacheConfig{Enabled: true},
+	}
+	r, err := NewRunner(cfg, false, RunnerOptions{
+		Services:     services,
+		StreamWriter: NewChannelWriter(make(chan StreamEvent, 100)),
+		NoCache:      noCache,
+	})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	return r
+}
+
+func TestResponseCache(t *testing.T) {
+	var calls atomic.Int32
+	backend := completionServer(t, "cached answer")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	zero, warm := 0.0, 0.7
+	tests := []struct {
+		name        string
+		temperature *float64
+		noCache     bool
+		wantCalls   int32
+	}{
+		{"temperature 0 is cached", &zero, false, 1},
+		{"default temperature is not cached", nil, false, 2},
+		{"nonzero temperature is not cached", &warm, false, 2},
+		{"no-cache bypasses the cache", &zero, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			r := cacheRunner(t, server.URL, tt.noCache)
+			ag := agent.Agent{
+				Handle:         "@ayo",
+				Model:          "test",
+				BuiltIn:        true,
+				CombinedSystem: "You are helpful.",
+				Config:         agent.Config{Temperature: tt.temperature},
+			}
+			for range 2 {
+				result, err := r.TextWithSession(context.Background(), ag, "question", nil)
+				if err != nil {
+					t.Fatalf("TextWithSession: %v", err)
+				}
+				if result.Response != "cached answer" {
+					t.Errorf("Response = %q", result.Response)
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	r := &Runner{cacheTTL: DefaultCacheTTL, services: &session.Services{Cache: &session.CacheService{}}}
+	zero := 0.0
+	ag := agent.Agent{Model: "test", Config: agent.Config{Temperature: &zero}}
+	key := func(system string) string {
+		return r.cacheKey(ag, []fantasy.Message{fantasy.NewSystemMessage(system), fantasy.NewUserMessage("hi")}, nil)
+	}
+
+	morning := key("<environment>\ndatetime: 2026-10-15 09:00:00 UTC\n</environment>")
+	evening := key("<environment>\ndatetime: 2026-10-15 21:30:12 UTC\n</environment>")
+	tomorrow := key("<environment>\ndatetime: 2026-10-16 09:00:00 UTC\n</environment>")
+	if morning == "" || morning != evening {
+		t.Errorf("keys differ by time of day: %q, %q", morning, evening)
+	}
+	if morning == tomorrow {
+		t.Error("keys match across days")
+	}
+
+	ag.Model = "other"
+	if key("<environment>\ndatetime: 2026-10-15 09:00:00 UTC\n</environment>") == morning {
+		t.Error("keys match across models")
+	}
+}
+
+func TestNewCacheTTL(t *testing.T) {
+	if ttl, _ := newCacheTTL(config.CacheConfig{}, false); ttl != 0 {
+		t.Errorf("disabled ttl = %v, want 0", ttl)
+	}
+	if ttl, _ := newCacheTTL(config.CacheConfig{Enabled: true}, false); ttl != DefaultCacheTTL {
+		t.Errorf("default ttl = %v, want %v", ttl, DefaultCacheTTL)
+	}
+	if ttl, _ := newCacheTTL(config.CacheConfig{Enabled: true, TTLSeconds: 60}, true); ttl != 0 {
+		t.Errorf("bypassed ttl = %v, want 0", ttl)
+	}
+	if _, err := newCacheTTL(config.CacheConfig{Enabled: true, TTLSeconds: -1}, false); err == nil {
+		t.Error("negative ttl accepted")
+	}
+}
@@ -1,24 +1,33 @@
 package run
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"charm.land/fantasy"
+	"github.com/google/uuid"
 
 	"github.com/alexcabrera/ayo/internal/agent"
 	"github.com/alexcabrera/ayo/internal/config"
 	"github.com/alexcabrera/ayo/internal/memory"
 	"github.com/alexcabrera/ayo/internal/plugins"
+	"github.com/alexcabrera/ayo/internal/redact"
 	"github.com/alexcabrera/ayo/internal/session"
 	"github.com/alexcabrera/ayo/internal/smallmodel"
 	uipkg "github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // Runner executes agents using Fantasy's Agent abstraction.
@@ -26,7 +35,9 @@ type Runner struct {
 	config           config.Config
 	debug            bool
 	depth            int // 0 = top-level, 1+ = sub-agent calls
-	sessions         map[string]*ChatSession
+	maxDepth         int // Deepest allowed sub-agent call; 0 = no delegation
+	sessions         map[string]*ChatSession // Chat sessions by agent handle; guarded by sessionsMu
+	sessionsMu       sync.Mutex
 	services         *session.Services        // nil = no persistence
 	memoryService    *memory.Service          // nil = no memory
 	formationService *memory.FormationService // nil = no async formation
@@ -35,35 +46,61 @@ type Runner struct {
 	memoryQueue      *memory.Queue            // nil = sync memory operations
 	streamHandler    StreamHandler            // nil = use default UI handler (deprecated)
 	streamWriter     StreamWriter             // nil = use streamHandler or default PrintWriter
+	redactor         *redact.Redactor         // nil = redaction disabled
+	toolOutput       uipkg.ToolOutputOptions  // Truncation of tool output in print mode
+	verbose          bool                     // Show tool input and output in full in print mode
+	redactions       atomic.Int64             // Secrets redacted by this runner
+	usage            usageTracker             // Tokens and cost across turns
+	stats            statsTracker             // Tool calls and time across turns
+	chatContext      ContextOptions           // Trimming of chat history per request
+	rawOutput        bool                     // Return output unrendered, for sub-agent calls
+	capture          *captureSink             // nil = turns are not captured
+	guard            *commandGuard            // nil = bash commands run without approval
+	approver         CommandApprover          // nil = ask on the terminal
+	cacheTTL         time.Duration            // 0 = responses are not cached
+	seed             *int64                   // nil = the provider samples without a seed
+	rateLimiter      *rateLimiter             // nil = calls are not throttled
+	toolProvider     ToolProvider             // nil = built-in and plugin tools only
+	forming          sync.WaitGroup           // Memory formations of turns in progress
+	background       backgroundWork           // Title generation; drained or cancelled by Shutdown
 }
 
 // ChatSession maintains conversation state for interactive chat.
+// Its fields are only changed during a turn, and turns of one session run
+// one at a time.
 type ChatSession struct {
 	Agent          agent.Agent
 	Messages       []fantasy.Message
 	SessionID      string // Database session ID (empty if no persistence)
 	TitleGenerated bool   // Whether title generation has been triggered
+	ContextSent    bool   // Whether the agent's context files have been attached
+
+	summary    string          // Summary of history left out of requests
+	summarized int             // Non-system messages covered by summary
+	context    fantasy.Message // The agent's context files, kept in every request
+	contextAt  int             // Index in Messages of the message first sending context
+	skills     TurnSkills      // Skills in the prompt, selected for the first message
+	mu         sync.Mutex      // Held for the length of a turn
+	created    chan struct{}   // Closed once SessionID is set; nil if it was set up front
+}
+
+// waitCreated waits until the session's database session was created.
+func (cs *ChatSession) waitCreated() {
+	if cs.created != nil {
+		<-cs.created
+	}
 }
 
 const maxOutputCastRetries = 3
 
 // NewRunnerFromConfig creates a new runner from the given configuration.
 func NewRunnerFromConfig(cfg config.Config, debug bool) (*Runner, error) {
-	return &Runner{
-		config:   cfg,
-		debug:    debug,
-		sessions: make(map[string]*ChatSession),
-	}, nil
+	return NewRunner(cfg, debug, RunnerOptions{})
 }
 
 // NewRunnerWithServices creates a runner with session persistence.
 func NewRunnerWithServices(cfg config.Config, debug bool, services *session.Services) (*Runner, error) {
-	return &Runner{
-		config:   cfg,
-		debug:    debug,
-		sessions: make(map[string]*ChatSession),
-		services: services,
-	}, nil
+	return NewRunner(cfg, debug, RunnerOptions{Services: services})
 }
 
 // RunnerOptions provides optional configuration for the runner.
@@ -72,14 +109,63 @@ type RunnerOptions struct {
 	MemoryService    *memory.Service
 	FormationService *memory.FormationService
 	SmallModel       *smallmodel.Service
-	OnAsyncStatus    func(uipkg.AsyncStatusMsg) // Callback for async operation status updates
+	OnAsyncStatus    func(uipkg.AsyncStatusMsg) // Callback for async operation status and memory formation decisions; must not block
 	MemoryQueue      *memory.Queue              // Queue for async memory operations
 	StreamHandler    StreamHandler              // Custom stream handler for TUI mode (deprecated)
 	StreamWriter     StreamWriter               // Preferred: unified stream writer interface
+	MaxDepth         *int                       // Delegation depth limit; nil = config or DefaultMaxDepth, 0 disables agent_call
+	RawOutput        bool                       // Return responses unrendered, for callers that consume them (flow steps)
+	Verbose          bool                       // Show tool input and output without truncation in print mode
+	ApproveCommand   CommandApprover            // Approves dangerous bash commands; nil = ask on the terminal
+	NoCache          bool                       // Bypass the response cache
+	Seed             *int64                     // Sampling seed for providers that support one; see SeedSupported
+	ToolProvider     ToolProvider               // Extra tools from an embedding application
 }
 
 // NewRunner creates a runner with all options.
 func NewRunner(cfg config.Config, debug bool, opts RunnerOptions) (*Runner, error) {
+	redactor, err := newRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	toolOutput, err := uipkg.NewToolOutputOptions(cfg.UI.ToolOutput.Strategy, cfg.UI.ToolOutput.MaxLines, cfg.UI.ToolOutput.ErrorPattern)
+	if err != nil {
+		return nil, fmt.Errorf("ui config: %w", err)
+	}
+	chatContext, err := NewContextOptions(cfg.Chat)
+	if err != nil {
+		return nil, fmt.Errorf("chat config: %w", err)
+	}
+	capture, err := newCaptureSink(cfg.Capture, cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	guard, err := newCommandGuard(cfg.Bash)
+	if err != nil {
+		return nil, err
+	}
+	cacheTTL, err := newCacheTTL(cfg.Cache, opts.NoCache)
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := newRateLimit(string(cfg.Provider.ID), cfg.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ToolProvider != nil {
+		if err := checkToolProvider(opts.ToolProvider); err != nil {
+			return nil, err
+		}
+	}
+	maxDepth := DefaultMaxDepth
+	if opts.MaxDepth != nil {
+		maxDepth = *opts.MaxDepth
+	} else if cfg.MaxDelegationDepth != nil {
+		maxDepth = *cfg.MaxDelegationDepth
+	}
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("max_delegation_depth must be 0 or more, got %d", maxDepth)
+	}
 	return &Runner{
 		config:           cfg,
 		debug:            debug,
@@ -92,6 +178,19 @@ func NewRunner(cfg config.Config, debug bool, opts RunnerOptions) (*Runner, erro
 		memoryQueue:      opts.MemoryQueue,
 		streamHandler:    opts.StreamHandler,
 		streamWriter:     opts.StreamWriter,
+		redactor:         redactor,
+		toolOutput:       toolOutput,
+		chatContext:      chatContext,
+		maxDepth:         maxDepth,
+		rawOutput:        opts.RawOutput,
+		verbose:          opts.Verbose,
+		capture:          capture,
+		guard:            guard,
+		approver:         opts.ApproveCommand,
+		cacheTTL:         cacheTTL,
+		seed:             opts.Seed,
+		rateLimiter:      limiter,
+		toolProvider:     opts.ToolProvider,
 	}, nil
 }
 
@@ -107,15 +206,34 @@ func (r *Runner) SetStreamWriter(w StreamWriter) {
 	r.streamWriter = w
 }
 
+// SetAsyncStatus sets the callback for async status updates and memory
+// formation decisions, replacing RunnerOptions.OnAsyncStatus. The TUI uses
+// this to show them in its sidebar.
+func (r *Runner) SetAsyncStatus(fn func(uipkg.AsyncStatusMsg)) {
+	r.onAsyncStatus = fn
+}
+
 // MemoryService returns the memory service, or nil if not configured.
 func (r *Runner) MemoryService() *memory.Service {
 	return r.memoryService
 }
 
-// WaitForFormations waits for any pending memory formations to complete.
+// WaitForFormations waits for any pending memory formations to complete,
+// including one a turn is making when it is called.
 func (r *Runner) WaitForFormations(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	done := make(chan struct{})
+	go func() {
+		r.forming.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		return
+	}
 	if r.formationService != nil {
-		r.formationService.Wait(timeout)
+		r.formationService.Wait(time.Until(deadline))
 	}
 }
 
@@ -134,51 +252,29 @@ func (r *Runner) StartMemoryQueue() {
 }
 
 
+// ErrInterrupted is returned by Chat when the turn was cancelled after part
+// of the response had streamed. The partial response is returned with it
+// and kept in the session history.
+var ErrInterrupted = errors.New("response interrupted")
+
 // Chat sends a message in an interactive session, maintaining conversation history.
 func (r *Runner) Chat(ctx context.Context, ag agent.Agent, input string) (string, error) {
-	chatSession, ok := r.sessions[ag.Handle]
-	if !ok {
-		// Initialize new session with system messages
-		var msgs []fantasy.Message
-		
-		// Build combined system prompt with memory context
-		systemPrompt := ag.CombinedSystem
-		if r.memoryService != nil && ag.Config.Memory.Enabled {
-			memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, "", input, ag.Config.Memory)
-			if err == nil && memCtx != nil {
-				systemPrompt = agent.InjectMemoryContext(systemPrompt, memCtx)
-			}
-		}
-		
-		if strings.TrimSpace(systemPrompt) != "" {
-			msgs = append(msgs, fantasy.NewSystemMessage(systemPrompt))
-		}
-		if strings.TrimSpace(ag.ToolsPrompt) != "" {
-			msgs = append(msgs, fantasy.NewSystemMessage(ag.ToolsPrompt))
-		}
-		if strings.TrimSpace(ag.SkillsPrompt) != "" {
-			msgs = append(msgs, fantasy.NewSystemMessage(ag.SkillsPrompt))
-		}
-		if strings.TrimSpace(ag.DelegateContext) != "" {
-			msgs = append(msgs, fantasy.NewSystemMessage(ag.DelegateContext))
-		}
-		chatSession = &ChatSession{Agent: ag, Messages: msgs}
-		r.sessions[ag.Handle] = chatSession
-
-		// Create database session if services available
-		if r.services != nil {
-			dbSession, err := r.services.Sessions.Create(ctx, session.CreateParams{
-				AgentHandle: ag.Handle,
-				Title:       generateSessionTitle(input),
-			})
-			if err == nil {
-				chatSession.SessionID = dbSession.ID
-			}
-		}
-	}
-
-	// Add user message
-	chatSession.Messages = append(chatSession.Messages, fantasy.NewUserMessage(input))
+	// Turns of one agent's session run one at a time, so concurrent calls
+	// for different agents don't wait on each other
+	chatSession := r.chatSession(ctx, ag, input)
+	chatSession.mu.Lock()
+	defer chatSession.mu.Unlock()
+
+	// Add user message, attaching the agent's context files to the first one
+	userMsg := fantasy.NewUserMessage(r.redact("user message", input))
+	if !chatSession.ContextSent && len(ag.ContextFiles) > 0 {
+		text, fileParts := attachFiles(input, ag.ContextFiles)
+		userMsg = fantasy.NewUserMessage(r.redact("user message", text), fileParts...)
+		text, fileParts = attachFiles("", ag.ContextFiles)
+		chatSession.context = fantasy.NewUserMessage(r.redact("user message", strings.TrimSpace(text)), fileParts...)
+		chatSession.contextAt = len(chatSession.Messages)
+	}
+	chatSession.Messages = append(chatSession.Messages, userMsg)
 
 	// Persist user message
 	if r.services != nil && chatSession.SessionID != "" {
@@ -191,57 +287,137 @@ func (r *Runner) Chat(ctx context.Context, ag agent.Agent, input string) (string
 	}
 
 	// Inject session context for tools
-	toolCtx := ctx
+	toolCtx := withTurnSkills(ctx, chatSession.skills)
 	if chatSession.SessionID != "" && r.services != nil {
 		toolCtx = WithSessionID(toolCtx, chatSession.SessionID)
 		toolCtx = WithServices(toolCtx, r.services)
 	}
 
-	// Run the chat and get response
-	resp, newMsgs, err := r.runChatWithHistory(toolCtx, ag, chatSession.Messages)
-	if err != nil {
+	// Run the chat and get response, sending only the history that fits
+	// the context strategy
+	sent := r.requestMessages(ctx, chatSession, ag.Model)
+	resp, newMsgs, cached, err := r.runChatWithHistory(toolCtx, ag, sent)
+	interrupted := errors.Is(err, ErrInterrupted)
+	if err != nil && !interrupted {
 		// Remove the failed user message
 		chatSession.Messages = chatSession.Messages[:len(chatSession.Messages)-1]
 		return "", err
 	}
 
+	// An interrupted turn is still saved, after ctx was cancelled
+	if interrupted {
+		ctx = context.WithoutCancel(ctx)
+	}
+
 	// Update session with full message history
-	chatSession.Messages = newMsgs
+	turn := newMsgs[len(sent):]
+	chatSession.Messages = append(chatSession.Messages, turn...)
+	chatSession.ContextSent = true
 
-	// Persist assistant response
+	// Persist the turn's tool calls, tool results, and assistant response
 	if r.services != nil && chatSession.SessionID != "" {
-		// Get the last assistant message from newMsgs
-		for i := len(newMsgs) - 1; i >= 0; i-- {
-			if newMsgs[i].Role == fantasy.MessageRoleAssistant {
-				parts := r.fantasyPartsToSessionParts(newMsgs[i].Content)
-				r.services.Messages.Create(ctx, session.CreateMessageParams{
-					SessionID: chatSession.SessionID,
-					Role:      session.RoleAssistant,
-					Parts:     parts,
-					Model:     ag.Model,
-				})
-				break
-			}
-		}
+		r.persistTurn(ctx, chatSession.SessionID, ag.Model, turn, interrupted)
 
 		// Generate title async after first exchange
-		if !chatSession.TitleGenerated {
+		if !chatSession.TitleGenerated && !cached {
 			chatSession.TitleGenerated = true
-			go r.generateTitleAsync(ag.Model, chatSession.SessionID, input, resp)
+			r.generateTitleAsync(ag.Model, chatSession.SessionID, input, resp)
 		}
 	}
 
-	// Async memory formation: detect triggers and queue formation
-	if r.formationService != nil && ag.Config.Memory.Enabled {
+	if interrupted {
+		return resp, ErrInterrupted
+	}
+
+	// Async memory formation: detect triggers and queue formation. A cached
+	// response repeats an earlier turn, which already had its chance.
+	if r.formationService != nil && ag.Config.Memory.Enabled && !cached {
 		r.maybeFormMemory(ctx, ag, input, chatSession.SessionID)
 	}
 
 	return resp, nil
 }
 
+// chatSession returns the agent's chat session, starting one with a system
+// prompt built for input if the agent has none. When two calls start a
+// session for the same agent at once, both get the first one stored.
+func (r *Runner) chatSession(ctx context.Context, ag agent.Agent, input string) *ChatSession {
+	if s, ok := r.lookupSession(ag.Handle); ok {
+		return s
+	}
+
+	// Initialize new session with system messages. Building them can call
+	// the small model and the embedder, so it is done without the lock.
+	var msgs []fantasy.Message
+	ag, turnSkills := r.selectSkills(ctx, ag, input)
+
+	// Build combined system prompt with memory context
+	systemPrompt := ag.CombinedSystem
+	if r.memoryService != nil && ag.Config.Memory.Enabled {
+		cwd, _ := os.Getwd()
+		memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, cwd, input, ag.Config.Memory)
+		if err == nil && memCtx != nil {
+			systemPrompt = agent.InjectMemoryContext(systemPrompt, memCtx)
+		}
+	}
+
+	if strings.TrimSpace(systemPrompt) != "" {
+		msgs = append(msgs, fantasy.NewSystemMessage(systemPrompt))
+	}
+	if strings.TrimSpace(ag.ToolsPrompt) != "" {
+		msgs = append(msgs, fantasy.NewSystemMessage(ag.ToolsPrompt))
+	}
+	if strings.TrimSpace(ag.SkillsPrompt) != "" {
+		msgs = append(msgs, fantasy.NewSystemMessage(ag.SkillsPrompt))
+	}
+	if strings.TrimSpace(ag.DelegateContext) != "" {
+		msgs = append(msgs, fantasy.NewSystemMessage(ag.DelegateContext))
+	}
+
+	// Claim the handle, then create the database session without the lock,
+	// so other agents' sessions aren't held up by the write. Callers that
+	// find the session meanwhile wait until it is created.
+	r.sessionsMu.Lock()
+	if s, ok := r.sessions[ag.Handle]; ok {
+		r.sessionsMu.Unlock()
+		s.waitCreated()
+		return s
+	}
+	chatSession := &ChatSession{Agent: ag, Messages: msgs, skills: turnSkills, created: make(chan struct{})}
+	r.sessions[ag.Handle] = chatSession
+	r.sessionsMu.Unlock()
+	defer close(chatSession.created)
+
+	// Create database session if services available
+	if r.services != nil {
+		wd, _ := os.Getwd()
+		dbSession, err := r.services.Sessions.Create(ctx, session.CreateParams{
+			AgentHandle: ag.Handle,
+			Title:       generateSessionTitle(input),
+			WorkingDir:  wd,
+		})
+		if err == nil {
+			chatSession.SessionID = dbSession.ID
+		}
+	}
+	return chatSession
+}
+
+// lookupSession returns the agent's chat session, if it has one, once its
+// database session was created.
+func (r *Runner) lookupSession(agentHandle string) (*ChatSession, bool) {
+	r.sessionsMu.Lock()
+	s, ok := r.sessions[agentHandle]
+	r.sessionsMu.Unlock()
+	if ok {
+		s.waitCreated()
+	}
+	return s, ok
+}
+
 // GetSessionID returns the current session ID for an agent (empty if no session).
 func (r *Runner) GetSessionID(agentHandle string) string {
-	if s, ok := r.sessions[agentHandle]; ok {
+	if s, ok := r.lookupSession(agentHandle); ok {
 		return s.SessionID
 	}
 	return ""
@@ -250,27 +426,57 @@ func (r *Runner) GetSessionID(agentHandle string) string {
 // ResumeSession restores a chat session from persisted messages.
 // This allows continuing a previous conversation.
 func (r *Runner) ResumeSession(ctx context.Context, ag agent.Agent, sessionID string, messages []session.Message) error {
-	// Build system prompt with memory context
-	systemPrompt := ag.CombinedSystem
-	if r.memoryService != nil && ag.Config.Memory.Enabled && ag.Config.Memory.Retrieval.AutoInject {
-		// Use last user message as query for memory retrieval
-		var query string
-		for i := len(messages) - 1; i >= 0; i-- {
-			if messages[i].Role == session.RoleUser {
-				for _, part := range messages[i].Parts {
-					if textPart, ok := part.(session.TextContent); ok {
-						query = textPart.Text
-						break
-					}
+	ag, turnSkills, msgs := r.resumeMessages(ctx, ag, messages)
+
+	// Create the chat session
+	// Sessions with history were titled after their first exchange
+	chatSession := &ChatSession{
+		Agent:          ag,
+		Messages:       msgs,
+		SessionID:      sessionID,
+		TitleGenerated: len(messages) > 0,
+		skills:         turnSkills,
+	}
+	r.sessionsMu.Lock()
+	r.sessions[ag.Handle] = chatSession
+	r.sessionsMu.Unlock()
+
+	return nil
+}
+
+// ResumeMessages returns the messages a resumed session sends to the
+// provider: the agent's system messages followed by the stored messages.
+func (r *Runner) ResumeMessages(ctx context.Context, ag agent.Agent, messages []session.Message) []fantasy.Message {
+	_, _, msgs := r.resumeMessages(ctx, ag, messages)
+	return msgs
+}
+
+// resumeMessages rebuilds the history of a stored session, selecting skills
+// and memories for its last user message. It returns the agent with the
+// selected skills and the skills in the prompt.
+func (r *Runner) resumeMessages(ctx context.Context, ag agent.Agent, messages []session.Message) (agent.Agent, TurnSkills, []fantasy.Message) {
+	// Use last user message as query for memory retrieval and skill selection
+	var query string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == session.RoleUser {
+			for _, part := range messages[i].Parts {
+				if textPart, ok := part.(session.TextContent); ok {
+					query = textPart.Text
+					break
 				}
-				break
 			}
+			break
 		}
-		if query != "" {
-			memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, "", query, ag.Config.Memory)
-			if err == nil && memCtx != nil {
-				systemPrompt = agent.InjectMemoryContext(systemPrompt, memCtx)
-			}
+	}
+	ag, turnSkills := r.selectSkills(ctx, ag, query)
+
+	// Build system prompt with memory context
+	systemPrompt := ag.CombinedSystem
+	if r.memoryService != nil && ag.Config.Memory.Enabled && ag.Config.Memory.Retrieval.AutoInject && query != "" {
+		cwd, _ := os.Getwd()
+		memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, cwd, query, ag.Config.Memory)
+		if err == nil && memCtx != nil {
+			systemPrompt = agent.InjectMemoryContext(systemPrompt, memCtx)
 		}
 	}
 
@@ -292,18 +498,9 @@ func (r *Runner) ResumeSession(ctx context.Context, ag agent.Agent, sessionID st
 		if msg.Role == session.RoleSystem {
 			continue
 		}
-		msgs = append(msgs, msg.ToFantasyMessage())
+		msgs = append(msgs, r.redactMessage(msg.ToFantasyMessage()))
 	}
-
-	// Create the chat session
-	chatSession := &ChatSession{
-		Agent:     ag,
-		Messages:  msgs,
-		SessionID: sessionID,
-	}
-	r.sessions[ag.Handle] = chatSession
-
-	return nil
+	return ag, turnSkills, msgs
 }
 
 // GetSessionMessages retrieves messages for the current session from the database.
@@ -313,7 +510,7 @@ func (r *Runner) GetSessionMessages(ctx context.Context, agentHandle string) ([]
 		return nil, nil
 	}
 
-	chatSession, ok := r.sessions[agentHandle]
+	chatSession, ok := r.lookupSession(agentHandle)
 	if !ok || chatSession.SessionID == "" {
 		return nil, nil
 	}
@@ -338,15 +535,17 @@ func (r *Runner) Text(ctx context.Context, ag agent.Agent, prompt string, attach
 
 // TextWithSession runs a single prompt and returns the session ID.
 func (r *Runner) TextWithSession(ctx context.Context, ag agent.Agent, prompt string, attachments []string) (TextResult, error) {
-	msgs := r.buildMessagesWithAttachments(ctx, ag, prompt, attachments)
+	msgs, turnSkills := r.buildTurnMessages(ctx, ag, prompt, attachments)
 
 	var sessionID string
 
 	// Create database session if services available
 	if r.services != nil {
+		wd, _ := os.Getwd()
 		dbSession, err := r.services.Sessions.Create(ctx, session.CreateParams{
 			AgentHandle: ag.Handle,
 			Title:       generateSessionTitle(prompt),
+			WorkingDir:  wd,
 		})
 		if err == nil {
 			sessionID = dbSession.ID
@@ -362,49 +561,62 @@ func (r *Runner) TextWithSession(ctx context.Context, ag agent.Agent, prompt str
 	}
 
 	// Inject session context for tools
-	toolCtx := ctx
+	toolCtx := withTurnSkills(ctx, turnSkills)
 	if sessionID != "" && r.services != nil {
 		toolCtx = WithSessionID(toolCtx, sessionID)
 		toolCtx = WithServices(toolCtx, r.services)
 	}
 
-	resp, err := r.runChat(toolCtx, ag, msgs)
+	sent := len(msgs)
+	resp, newMsgs, cached, err := r.runChatWithHistory(toolCtx, ag, msgs)
 	if err != nil {
 		return TextResult{}, err
 	}
 
-	// Persist assistant response and generate title
+	// Persist the turn and generate title
 	if r.services != nil && sessionID != "" {
-		r.services.Messages.Create(ctx, session.CreateMessageParams{
-			SessionID: sessionID,
-			Role:      session.RoleAssistant,
-			Parts:     []session.ContentPart{session.TextContent{Text: resp}},
-			Model:     ag.Model,
-		})
+		r.persistTurn(ctx, sessionID, ag.Model, newMsgs[sent:], false)
 
 		// Generate title async
-		go r.generateTitleAsync(ag.Model, sessionID, prompt, resp)
+		if !cached {
+			r.generateTitleAsync(ag.Model, sessionID, prompt, resp)
+		}
 	}
 
 	// Async memory formation: detect triggers and queue formation
-	if r.formationService != nil && ag.Config.Memory.Enabled {
+	if r.formationService != nil && ag.Config.Memory.Enabled && !cached {
 		r.maybeFormMemory(ctx, ag, prompt, sessionID)
 	}
 
 	return TextResult{Response: resp, SessionID: sessionID}, nil
 }
 
+// ModelContext returns the system message that tells an agent which model it
+// is running on, so it can pass the model through to external tools.
+func ModelContext(model string) string {
+	return fmt.Sprintf("<model_context>\nYou are running with model: %s\nWhen delegating to external tools that accept a model parameter (like crush run --model), use this model.\n</model_context>", model)
+}
+
 func (r *Runner) buildMessages(ctx context.Context, ag agent.Agent, prompt string) []fantasy.Message {
 	return r.buildMessagesWithAttachments(ctx, ag, prompt, nil)
 }
 
 func (r *Runner) buildMessagesWithAttachments(ctx context.Context, ag agent.Agent, prompt string, attachments []string) []fantasy.Message {
+	msgs, _ := r.buildTurnMessages(ctx, ag, prompt, attachments)
+	return msgs
+}
+
+// buildTurnMessages builds the messages of a one-shot turn and returns them
+// with the skills in the prompt.
+func (r *Runner) buildTurnMessages(ctx context.Context, ag agent.Agent, prompt string, attachments []string) ([]fantasy.Message, TurnSkills) {
 	var msgs []fantasy.Message
+	ag, turnSkills := r.selectSkills(ctx, ag, prompt)
 	
 	// Build combined system prompt with memory context
 	systemPrompt := ag.CombinedSystem
 	if r.memoryService != nil && ag.Config.Memory.Enabled {
-		memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, "", prompt, ag.Config.Memory)
+		cwd, _ := os.Getwd()
+		memCtx, err := agent.BuildMemoryContext(ctx, r.memoryService, ag.Handle, cwd, prompt, ag.Config.Memory)
 		if err == nil && memCtx != nil {
 			systemPrompt = agent.InjectMemoryContext(systemPrompt, memCtx)
 		}
@@ -422,16 +634,44 @@ func (r *Runner) buildMessagesWithAttachments(ctx context.Context, ag agent.Agen
 
 	// Add model context for sub-agents that need to pass the model through
 	if ag.Model != "" {
-		modelContext := fmt.Sprintf("<model_context>\nYou are running with model: %s\nWhen delegating to external tools that accept a model parameter (like crush run --model), use this model.\n</model_context>", ag.Model)
-		msgs = append(msgs, fantasy.NewSystemMessage(modelContext))
+		msgs = append(msgs, fantasy.NewSystemMessage(ModelContext(ag.Model)))
 	}
 
-	// Build file parts from attachments
-	// Text files are inlined into the prompt; binary files use FilePart
+	// Agent context files come first, then attachments for this run
+	files := append(slices.Clone(ag.ContextFiles), attachments...)
+	prompt, fileParts := attachFiles(prompt, files)
+
+	msgs = append(msgs, fantasy.NewUserMessage(r.redact("user message", prompt), fileParts...))
+	return msgs, turnSkills
+}
+
+// attachFiles reads files for a user message.
+// Text files are inlined into the prompt; binary files use FilePart.
+// Directories attach their files, named by their path within the directory.
+func attachFiles(prompt string, paths []string) (string, []fantasy.FilePart) {
 	var fileParts []fantasy.FilePart
 	var textAttachments []string
 
-	for _, path := range attachments {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dir, err := CollectDir(path)
+			if err != nil {
+				prompt = fmt.Sprintf("%s\n\n[Error reading %s: %v]", prompt, path, err)
+				continue
+			}
+			for _, f := range dir.Files {
+				if f.Text {
+					textAttachments = append(textAttachments, fmt.Sprintf("<file path=%q>\n%s\n</file>", f.Name, string(f.Data)))
+				} else {
+					fileParts = append(fileParts, fantasy.FilePart{Filename: f.Name, Data: f.Data, MediaType: f.MediaType})
+				}
+			}
+			if dir.Omitted > 0 {
+				prompt = fmt.Sprintf("%s\n\n[%d more files in %s were not attached]", prompt, dir.Omitted, path)
+			}
+			continue
+		}
+
 		data, err := os.ReadFile(path)
 		if err != nil {
 			// Skip files that can't be read, but include error in prompt
@@ -464,8 +704,7 @@ func (r *Runner) buildMessagesWithAttachments(ctx context.Context, ag agent.Agen
 		prompt = strings.Join(textAttachments, "\n\n") + "\n\n" + prompt
 	}
 
-	msgs = append(msgs, fantasy.NewUserMessage(prompt, fileParts...))
-	return msgs
+	return prompt, fileParts
 }
 
 // isTextMediaType returns true if the media type represents text content
@@ -498,13 +737,15 @@ func isTextMediaType(mediaType string) bool {
 }
 
 func (r *Runner) runChat(ctx context.Context, ag agent.Agent, msgs []fantasy.Message) (string, error) {
-	resp, _, err := r.runChatWithHistory(ctx, ag, msgs)
+	resp, _, _, err := r.runChatWithHistory(ctx, ag, msgs)
 	return resp, err
 }
 
-func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []fantasy.Message) (string, []fantasy.Message, error) {
+// runChatWithHistory runs one turn and returns the response, the history
+// with the turn appended, and whether the response came from the cache.
+func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []fantasy.Message) (string, []fantasy.Message, bool, error) {
 	if strings.TrimSpace(ag.Model) == "" {
-		return "", nil, fmt.Errorf("model is required")
+		return "", nil, false, fmt.Errorf("model is required")
 	}
 
 	// Extract the last user message as the prompt for Fantasy
@@ -526,10 +767,14 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 	}
 
 	// Create language model from config
-	model, err := NewLanguageModel(ctx, r.config.Provider, ag.Model)
+	if err := checkOffline(r.config, ag.Handle, ag.Model); err != nil {
+		return "", nil, false, err
+	}
+	model, err := newLanguageModel(ctx, r.config.Provider, ag.Model, r.seed)
 	if err != nil {
-		return "", nil, fmt.Errorf("create language model: %w", err)
+		return "", nil, false, fmt.Errorf("create language model: %w", err)
 	}
+	r.recordSeed(ctx)
 
 	// Build tool set with memory queue and depth for proper UI nesting
 	baseDir, _ := os.Getwd()
@@ -538,18 +783,24 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 	// Add agent_call if explicitly allowed in config (for any agent)
 	// or if it's a non-builtin agent (user agents get it by default)
 	if tools.HasTool("agent_call") || !ag.BuiltIn {
-		tools.AddAgentCallTool(r.agentCallExecutor(ag.Handle))
+		tools.AddAgentCallTool(r.agentCallExecutor(ag.Handle, ag.Config.CallableAgents))
 	}
+	r.addProvidedTools(&tools, ag, baseDir)
 
-	// Create Fantasy agent
-	fantasyAgent := fantasy.NewAgent(
-		model,
-		fantasy.WithSystemPrompt(""), // System prompt already in messages
-		fantasy.WithTools(tools.Tools()...),
-	)
+	// Reject malformed tool arguments with a precise error so the model can
+	// correct them on its next step. The error is returned as the tool
+	// result, which the session stores with the rest of the turn, and the
+	// rejection is counted in the run stats.
+	recordArgumentError := func(argErr *ToolArgumentError) {
+		r.stats.addInvalidToolCall()
+		if r.debug {
+			fmt.Fprintf(os.Stderr, "DEBUG: %v\n", argErr)
+		}
+	}
 
 	// Use custom stream writer/handler if provided, otherwise use default print writer
 	var handler StreamHandler
+	var printUI *uipkg.UI
 	if r.streamWriter != nil {
 		// Wrap StreamWriter with FantasyAdapter to get a StreamHandler
 		handler = NewFantasyAdapter(r.streamWriter)
@@ -558,17 +809,60 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 		handler = r.streamHandler
 	} else {
 		// Default: create PrintWriter which implements StreamWriter
-		handler = NewFantasyAdapter(NewPrintWriter(ag.Handle, r.debug, r.depth))
+		u := uipkg.NewWithDepth(r.debug, r.depth)
+		u.SetToolOutput(r.toolOutput)
+		u.SetVerbose(r.verbose)
+		handler = NewFantasyAdapter(NewPrintWriterWithUI(u, ag.Handle))
+		printUI = u
+	}
+
+	// Create Fantasy agent, throttled with every other caller of the provider
+	agentTools := r.withRedaction(r.withCommandApproval(withArgumentValidation(tools.Tools(), recordArgumentError)))
+	model = r.rateLimiter.wrap(model, func(state rateLimitState) {
+		if r.verbose && printUI != nil {
+			printUI.PrintRateLimitStatus(state.String())
+		}
+	})
+	fantasyAgent := fantasy.NewAgent(
+		model,
+		fantasy.WithSystemPrompt(""), // System prompt already in messages
+		fantasy.WithTools(agentTools...),
+	)
+
+	turnStart := time.Now()
+
+	// Deterministic agents reuse an earlier response to the same request
+	cacheKey := r.cacheKey(ag, msgs, agentTools)
+	cachedResp, hit := r.cachedResponse(ctx, cacheKey)
+	if cacheKey != "" && r.verbose && printUI != nil {
+		printUI.PrintCacheStatus(hit)
+	}
+	if hit {
+		resp, msgs := r.replayCachedResponse(ag, msgs, handler, cachedResp)
+		r.recordTurn(time.Since(turnStart))
+		return resp, msgs, true, nil
 	}
 
 	var content strings.Builder
 	var reasoningStartTime time.Time
 	var toolStartTime time.Time
 
+	// Tool arguments are shown as they stream when the handler supports it
+	deltaHandler, _ := handler.(ToolCallDeltaHandler)
+	streamingTools := streamingToolCalls{}
+
+	// Handlers that show what the turn is waiting on, such as the print
+	// spinner, are told when that changes
+	setPhase := func(string) {}
+	if ph, ok := handler.(PhaseHandler); ok {
+		setPhase = func(phase string) { ph.OnPhase(phase) }
+	}
+
 	// Stream the response with all callbacks
 	result, err := fantasyAgent.Stream(ctx, fantasy.AgentStreamCall{
-		Prompt:   prompt,
-		Messages: historyMsgs,
+		Prompt:      prompt,
+		Messages:    historyMsgs,
+		Temperature: ag.Config.Temperature,
 
 		// Reasoning streams (for models like Claude that expose thinking)
 		OnReasoningDelta: func(id, text string) error {
@@ -585,9 +879,40 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 			return handler.OnReasoningEnd(id, duration)
 		},
 
+		// Tool arguments stream before the call is complete, for providers
+		// that stream them
+		OnToolInputStart: func(id, toolName string) error {
+			setPhase(uipkg.PhaseCallingTool)
+			if deltaHandler == nil {
+				return nil
+			}
+			streamingTools.start(id, toolName)
+			return deltaHandler.OnToolCallDelta(id, toolName, "")
+		},
+
+		OnToolInputDelta: func(id, delta string) error {
+			if deltaHandler == nil {
+				return nil
+			}
+			if name, input, ok := streamingTools.add(id, delta); ok {
+				return deltaHandler.OnToolCallDelta(id, name, input)
+			}
+			return nil
+		},
+
+		OnToolInputEnd: func(id string) error {
+			delete(streamingTools, id)
+			return nil
+		},
+
 		// Tool call complete - show the command we're about to run
 		OnToolCall: func(tc fantasy.ToolCallContent) error {
 			toolStartTime = time.Now()
+			// Fantasy rejects unparseable arguments and missing required
+			// fields before the tool runs
+			if tc.Invalid && tc.ValidationError != nil {
+				recordArgumentError(&ToolArgumentError{Tool: tc.ToolName, Problem: tc.ValidationError.Error()})
+			}
 			return handler.OnToolCall(tc)
 		},
 
@@ -595,7 +920,13 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 		OnToolResult: func(result fantasy.ToolResultContent) error {
 			duration := time.Since(toolStartTime)
 			toolStartTime = time.Time{}
-			return handler.OnToolResult(result, duration)
+			r.stats.addTool(result.ToolName, duration)
+			if err := handler.OnToolResult(result, duration); err != nil {
+				return err
+			}
+			// The model is called again with the result
+			setPhase(uipkg.PhaseThinking)
+			return nil
 		},
 
 		// Text response streams
@@ -603,17 +934,39 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 			content.WriteString(text)
 			return handler.OnTextDelta(id, text)
 		},
+
+		// Usage is reported per step so totals update during long turns
+		OnStepFinish: func(step fantasy.StepResult) error {
+			r.stats.addStep()
+			r.recordUsage(ctx, ag.Model, step.Usage)
+			return nil
+		},
+
+		// Failed provider calls are retried after a delay
+		OnRetry: func(err *fantasy.ProviderError, delay time.Duration) {
+			setPhase(uipkg.PhaseRetrying)
+		},
 	})
+	r.recordTurn(time.Since(turnStart))
+	setPhase("")
 
 	// Notify handler of text completion
 	if content.Len() > 0 {
 		handler.OnTextEnd("")
 	}
 
-	// Handle errors
+	// Handle errors. A cancelled turn keeps the text streamed so far.
 	if err != nil {
+		if errors.Is(err, context.Canceled) && content.Len() > 0 {
+			partial := strings.TrimSpace(content.String())
+			msgs = append(msgs, fantasy.Message{
+				Role:    fantasy.MessageRoleAssistant,
+				Content: []fantasy.MessagePart{fantasy.TextPart{Text: partial}},
+			})
+			return partial, msgs, false, ErrInterrupted
+		}
 		handler.OnError(err)
-		return "", nil, err
+		return "", nil, false, err
 	}
 
 	// Get final content
@@ -634,43 +987,80 @@ func (r *Runner) runChatWithHistory(ctx context.Context, ag agent.Agent, msgs []
 		ui := uipkg.NewWithDepth(r.debug, r.depth)
 		structuredOutput, err := r.castToStructuredOutput(ctx, model, ag, finalContent, ui)
 		if err != nil {
-			return "", nil, fmt.Errorf("structured output: %w", err)
+			return "", nil, false, fmt.Errorf("structured output: %w", err)
 		}
 		finalContent = structuredOutput
+		r.captureTurn(ctx, ag, msgs, agentTools, result, finalContent)
+		r.logSkills(ctx, ag)
+		r.cacheResponse(ctx, cacheKey, ag.Model, finalContent, result)
 
-		// Append assistant message to history
+		// Append the turn's tool calls and results, then the assistant
+		// message, to history
+		msgs = append(msgs, turnMessages(result.Steps)...)
 		msgs = append(msgs, fantasy.Message{
 			Role:    fantasy.MessageRoleAssistant,
 			Content: []fantasy.MessagePart{fantasy.TextPart{Text: finalContent}},
 		})
 
-		// When piped, return raw JSON for downstream consumption
-		if ui.IsPiped() {
-			return strings.TrimSpace(finalContent), msgs, nil
+		// When piped or called by another agent, return raw JSON for
+		// downstream consumption
+		if r.rawOutput || ui.IsPiped() {
+			return strings.TrimSpace(finalContent), msgs, false, nil
 		}
 
 		// Render JSON with syntax highlighting for terminal
 		rendered := ui.RenderJSON(finalContent)
-		return strings.TrimSpace(rendered), msgs, nil
+		return strings.TrimSpace(rendered), msgs, false, nil
 	}
 
-	// Append assistant message to history
+	r.captureTurn(ctx, ag, msgs, agentTools, result, finalContent)
+	r.logSkills(ctx, ag)
+	r.cacheResponse(ctx, cacheKey, ag.Model, finalContent, result)
+
+	// Append the turn's tool calls and results, then the assistant message,
+	// to history
+	msgs = append(msgs, turnMessages(result.Steps)...)
 	msgs = append(msgs, fantasy.Message{
 		Role:    fantasy.MessageRoleAssistant,
 		Content: []fantasy.MessagePart{fantasy.TextPart{Text: finalContent}},
 	})
 
-	// When piped with no output schema, return raw content
+	// When piped or called by another agent with no output schema, return
+	// raw content
 	ui := uipkg.NewWithDepth(r.debug, r.depth)
-	if ui.IsPiped() {
-		return strings.TrimSpace(finalContent), msgs, nil
+	if r.rawOutput || ui.IsPiped() {
+		return strings.TrimSpace(finalContent), msgs, false, nil
 	}
 
-	// Text was already streamed to output, return empty to avoid duplicate
-	return "", msgs, nil
+	// Text was already streamed to output, return empty to avoid duplicate.
+	// It is not paged, which would show it twice.
+	return "", msgs, false, nil
 }
 
-func (r *Runner) agentCallExecutor(currentAgentHandle string) func(ctx context.Context, params AgentCallParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+// DefaultMaxDepth is how deeply agent_call invocations can nest unless
+// configured otherwise. The limit also stops agents that call each other
+// from looping forever.
+const DefaultMaxDepth = 3
+
+// agentCallAllowed reports whether agent_call may invoke handle. Builtin and
+// plugin agents are always allowed; other agents only when they match an
+// entry in callable.
+func agentCallAllowed(handle string, callable []string) bool {
+	if agent.IsReservedNamespace(handle) || plugins.IsPluginAgent(handle) {
+		return true
+	}
+	for _, pattern := range callable {
+		if pattern == "*" {
+			return true
+		}
+		if agent.MatchHandle(pattern, handle) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Runner) agentCallExecutor(currentAgentHandle string, callable []string) func(ctx context.Context, params AgentCallParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
 	return func(ctx context.Context, params AgentCallParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
 		// Normalize handle
 		agentHandle := agent.NormalizeHandle(params.Agent)
@@ -680,9 +1070,12 @@ func (r *Runner) agentCallExecutor(currentAgentHandle string) func(ctx context.C
 			return fantasy.NewTextErrorResponse(fmt.Sprintf("cannot delegate to self (%s) - use bash or other tools directly", agentHandle)), nil
 		}
 
-		// Only allow calling builtin agents or plugin agents
-		if !agent.IsReservedNamespace(agentHandle) && !plugins.IsPluginAgent(agentHandle) {
-			return fantasy.NewTextErrorResponse("agent_call can only invoke builtin or plugin agents"), nil
+		if !agentCallAllowed(agentHandle, callable) {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is not callable: agent_call can invoke builtin and plugin agents, and agents listed in callable_agents", agentHandle)), nil
+		}
+
+		if r.depth >= r.maxDepth {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("maximum delegation depth reached (%d): cannot call %s", r.maxDepth, agentHandle)), nil
 		}
 
 		// Load the target agent
@@ -713,20 +1106,44 @@ func (r *Runner) agentCallExecutor(currentAgentHandle string) func(ctx context.C
 		startTime := time.Now()
 		ui.PrintSubAgentStart(agentHandle, params.Prompt)
 
-		// Create sub-runner at increased depth
+		// Create sub-runner at increased depth. It shares the parent's
+		// services and provider rate limit, but doesn't form memories: its
+		// prompt was written by the calling agent, not the user.
 		subRunner := &Runner{
-			config:   r.config,
-			debug:    r.debug,
-			depth:    r.depth + 1,
-			sessions: make(map[string]*ChatSession),
-			services: r.services, // Pass services through for persistence
+			config:        r.config,
+			debug:         r.debug,
+			depth:         r.depth + 1,
+			maxDepth:      r.maxDepth,
+			sessions:      make(map[string]*ChatSession),
+			services:      r.services, // Pass services through for persistence
+			memoryService: r.memoryService,
+			smallModel:    r.smallModel,
+			memoryQueue:   r.memoryQueue,
+			onAsyncStatus: r.onAsyncStatus,
+			redactor:      r.redactor,
+			toolOutput:    r.toolOutput,
+			verbose:       r.verbose,
+			chatContext:   r.chatContext,
+			rawOutput:     true,
+			capture:       r.capture,
+			guard:         r.guard,
+			approver:      r.approver,
+			cacheTTL:      r.cacheTTL,
+			seed:          r.seed,
+			rateLimiter:   r.rateLimiter,
+			toolProvider:  r.toolProvider,
 		}
 
 		// Run the agent
 		response, err := subRunner.Text(execCtx, targetAgent, params.Prompt, nil)
 
+		// Sub-agent usage counts toward this session's totals
+		r.usage.add(subRunner.Usage())
+
 		// Show sub-agent completion
-		duration := formatElapsed(time.Since(startTime))
+		elapsed := time.Since(startTime)
+		r.stats.addSubAgent(agentHandle, elapsed)
+		duration := formatElapsed(elapsed)
 		hasError := err != nil
 		if execCtx.Err() == context.DeadlineExceeded {
 			hasError = true
@@ -740,14 +1157,35 @@ func (r *Runner) agentCallExecutor(currentAgentHandle string) func(ctx context.C
 			return fantasy.NewTextErrorResponse(fmt.Sprintf("agent %s error: %v", agentHandle, err)), nil
 		}
 
-		// Truncate if too long
-		const maxOutput = 128 * 1024
-		if len(response) > maxOutput {
-			response = response[:maxOutput]
-		}
+		return agentCallResponse(agentHandle, strings.TrimSpace(response), targetAgent.HasOutputSchema()), nil
+	}
+}
 
-		return fantasy.NewTextResponse(strings.TrimSpace(response)), nil
+// maxAgentCallOutput limits the sub-agent output returned by agent_call.
+const maxAgentCallOutput = 128 * 1024
+
+// agentCallResponse builds the agent_call result for a sub-agent's output.
+// Structured output is returned as compact JSON so the parent can use it
+// directly; if it exceeds the output limit it is truncated as plain text, at
+// a character boundary.
+func agentCallResponse(handle, output string, structured bool) fantasy.ToolResponse {
+	meta := shared.AgentCallResponseMetadata{Agent: handle}
+	if structured {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(output)); err == nil && buf.Len() <= maxAgentCallOutput {
+			output = buf.String()
+			meta.Structured = true
+		}
 	}
+	if len(output) > maxAgentCallOutput {
+		cut := maxAgentCallOutput
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+		output = output[:cut]
+		meta.Truncated = true
+	}
+	return fantasy.WithResponseMetadata(fantasy.NewTextResponse(output), meta)
 }
 
 // formatToolResultContent converts a Fantasy tool result to a string for display.
@@ -800,27 +1238,38 @@ func generateSessionTitle(prompt string) string {
 	return title[:maxLen-1] + "…"
 }
 
-// generateTitleAsync uses an LLM to generate a concise title for the session.
-// Runs in a goroutine so it doesn't block the conversation.
+// defaultTitlePrompt is the title generation prompt used when config.Titles.Prompt is unset.
+const defaultTitlePrompt = "Generate a short, descriptive title (max 50 chars) for this conversation. The title should capture the main topic or intent. Return ONLY the title, no quotes or explanation.\n\nUser: {{user}}\n\nAssistant: {{assistant}}"
+
+// generateTitleAsync uses an LLM to generate a concise title for the session
+// in the background, so it doesn't block the conversation. Shutdown cancels it.
 func (r *Runner) generateTitleAsync(modelID, sessionID, userMessage, assistantResponse string) {
-	if r.services == nil || sessionID == "" {
+	if r.services == nil || sessionID == "" || r.config.Titles.Disabled || r.config.Offline {
 		return
 	}
+	r.goBackground(func(ctx context.Context) {
+		r.generateTitle(ctx, modelID, sessionID, userMessage, assistantResponse)
+	})
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// generateTitle generates and stores the session title.
+func (r *Runner) generateTitle(ctx context.Context, modelID, sessionID, userMessage, assistantResponse string) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Prefer the configured title model over the agent's model
+	if r.config.Titles.Model != "" {
+		modelID = r.config.Titles.Model
+	}
+
 	// Create a model for title generation
 	model, err := NewLanguageModel(ctx, r.config.Provider, modelID)
 	if err != nil {
 		return // Silent fail - title stays as default
 	}
+	model = r.rateLimiter.wrap(model, nil)
 
-	// Truncate messages to avoid excessive token usage
-	userMsg := truncateForTitle(userMessage, 500)
-	assistantMsg := truncateForTitle(assistantResponse, 500)
-
-	titlePrompt := fmt.Sprintf("Generate a short, descriptive title (max 50 chars) for this conversation. The title should capture the main topic or intent. Return ONLY the title, no quotes or explanation.\n\nUser: %s\n\nAssistant: %s", userMsg, assistantMsg)
+	titlePrompt := buildTitlePrompt(r.config.Titles.Prompt, r.redact("title prompt", userMessage), assistantResponse)
 
 	agent := fantasy.NewAgent(model)
 	result, err := agent.Generate(ctx, fantasy.AgentCall{
@@ -845,6 +1294,25 @@ func (r *Runner) generateTitleAsync(modelID, sessionID, userMessage, assistantRe
 	r.services.Sessions.UpdateTitle(ctx, sessionID, title)
 }
 
+// buildTitlePrompt fills the title prompt template with the first exchange.
+// An empty template uses defaultTitlePrompt. Templates without placeholders
+// get the exchange appended so the model always sees the conversation.
+func buildTitlePrompt(template, userMessage, assistantResponse string) string {
+	if strings.TrimSpace(template) == "" {
+		template = defaultTitlePrompt
+	}
+
+	// Truncate messages to avoid excessive token usage
+	userMsg := truncateForTitle(userMessage, 500)
+	assistantMsg := truncateForTitle(assistantResponse, 500)
+
+	if !strings.Contains(template, "{{user}}") && !strings.Contains(template, "{{assistant}}") {
+		return fmt.Sprintf("%s\n\nUser: %s\n\nAssistant: %s", template, userMsg, assistantMsg)
+	}
+
+	return strings.NewReplacer("{{user}}", userMsg, "{{assistant}}", assistantMsg).Replace(template)
+}
+
 // truncateForTitle truncates a string to maxLen for title generation prompts.
 func truncateForTitle(s string, maxLen int) string {
 	s = strings.TrimSpace(s)
@@ -857,6 +1325,17 @@ func truncateForTitle(s string, maxLen int) string {
 
 // maybeFormMemory uses small model to extract memorable content from user messages.
 func (r *Runner) maybeFormMemory(ctx context.Context, ag agent.Agent, userMessage, sessionID string) {
+	r.forming.Add(1)
+	defer r.forming.Done()
+
+	ctx, stop := r.withShutdown(ctx)
+	defer stop()
+
+	// Formation calls the small model
+	if r.config.Offline {
+		return
+	}
+
 	// Need memory service with embedder for deduplication and embedding generation
 	if r.memoryService == nil || !r.memoryService.HasEmbedder() {
 		return
@@ -887,32 +1366,37 @@ func (r *Runner) maybeFormMemory(ctx context.Context, ag agent.Agent, userMessag
 		return
 	}
 
-	// Nothing to remember
-	if !extraction.ShouldRemember || extraction.Content == "" {
-		return
-	}
-
 	// Filter based on agent config
-	switch extraction.Category {
-	case "correction":
-		if !cfg.OnCorrection {
-			return
-		}
-	case "preference":
-		if !cfg.OnPreference {
-			return
-		}
-	case "fact":
-		if !cfg.OnProjectFact {
-			return
+	var items []smallmodel.ExtractedMemory
+	for _, item := range extraction.Items() {
+		switch item.Category {
+		case "correction":
+			if !cfg.OnCorrection {
+				continue
+			}
+		case "preference":
+			if !cfg.OnPreference {
+				continue
+			}
+		case "fact":
+			if !cfg.OnProjectFact {
+				continue
+			}
 		}
+		items = append(items, item)
 	}
 
-	// Map category string to memory.Category
-	category := categoryFromString(extraction.Category)
+	// Nothing to remember
+	if len(items) == 0 {
+		return
+	}
 
-	// Check for duplicates using small model if we have similar memories
-	existing, err := r.memoryService.Search(ctx, extraction.Content, memory.SearchOptions{
+	// Find similar memories for every item with one embedding call
+	contents := make([]string, len(items))
+	for i, item := range items {
+		contents[i] = item.Content
+	}
+	similar, err := r.memoryService.SearchBatch(ctx, contents, memory.SearchOptions{
 		AgentHandle: ag.Handle,
 		Threshold:   memory.SupersedeThreshold,
 		Limit:       5,
@@ -922,84 +1406,237 @@ func (r *Runner) maybeFormMemory(ctx context.Context, ag agent.Agent, userMessag
 			fmt.Fprintf(os.Stderr, "DEBUG: memory search failed: %v\n", err)
 		}
 		// Continue with creation anyway
-		existing = nil
+		similar = nil
 	}
 
-	// If we have similar memories, use small model to decide what to do
-	if len(existing) > 0 {
-		existingList := make([]smallmodel.ExistingMemory, len(existing))
-		for i, m := range existing {
-			existingList[i] = smallmodel.ExistingMemory{
-				ID:      m.Memory.ID,
-				Content: m.Memory.Content,
+	// Decide duplicate/supersede/new for each item concurrently
+	decisions := r.checkDuplicates(ctx, contents, similar)
+
+	// Apply decisions in extraction order so notifications stay ordered.
+	// Track superseded targets so two items never supersede the same memory.
+	superseded := make(map[string]bool)
+	for i, item := range items {
+		mem := memory.Memory{
+			Content:         item.Content,
+			Category:        categoryFromString(item.Category),
+			AgentHandle:     ag.Handle,
+			SourceSessionID: sessionID,
+		}
+		if i < len(similar) {
+			mem.Embedding = similar[i].Embedding
+		}
+
+		d := decisions[i]
+		var match memory.BatchSearchResult
+		if i < len(similar) {
+			match = similar[i]
+		}
+		decision := formationDecision(item, d, match)
+		switch d.action {
+		case "duplicate":
+			// Already have this memory, skip
+			if r.formationService != nil {
+				r.formationService.NotifySkipped(item.Content, d.targetID)
 			}
+			decision.Action = uipkg.MemoryActionSkipped
+			r.reportFormation(decision)
+			continue
+		case "supersede":
+			if superseded[d.targetID] {
+				if r.debug {
+					fmt.Fprintf(os.Stderr, "DEBUG: memory %s already superseded, storing as new\n", d.targetID)
+				}
+				decision = formationDecision(item, dedupDecision{action: "new"}, match)
+				break
+			}
+			superseded[d.targetID] = true
+			if cfg.Review {
+				r.proposeMemory(ctx, mem, d.targetID, d.reason, decision)
+				continue
+			}
+			created, err := r.memoryService.Supersede(ctx, d.targetID, mem, d.reason)
+			if err != nil {
+				if r.debug {
+					fmt.Fprintf(os.Stderr, "DEBUG: memory supersede failed: %v\n", err)
+				}
+				if r.formationService != nil {
+					r.formationService.NotifyFailed(item.Content, err)
+				}
+				decision.Action, decision.Reason = uipkg.MemoryActionFailed, err.Error()
+			} else {
+				if r.formationService != nil {
+					r.formationService.NotifySuperseded(created, d.targetID)
+				}
+				decision.Action, decision.MemoryID = uipkg.MemoryActionSuperseded, created.ID
+			}
+			r.reportFormation(decision)
+			continue
 		}
 
-		decision, err := r.smallModel.CheckDuplicate(ctx, extraction.Content, existingList)
+		if cfg.Review {
+			r.proposeMemory(ctx, mem, "", "", decision)
+			continue
+		}
+
+		// Create the memory
+		created, err := r.memoryService.Create(ctx, mem)
 		if err != nil {
 			if r.debug {
-				fmt.Fprintf(os.Stderr, "DEBUG: dedup check failed: %v\n", err)
+				fmt.Fprintf(os.Stderr, "DEBUG: memory creation failed: %v\n", err)
+			}
+			if r.formationService != nil {
+				r.formationService.NotifyFailed(item.Content, err)
 			}
-			// Continue with creation anyway
+			decision.Action, decision.Reason = uipkg.MemoryActionFailed, err.Error()
 		} else {
-			switch decision.Action {
-			case "duplicate":
-				// Already have this memory, skip
-				if r.formationService != nil {
-					r.formationService.NotifySkipped(extraction.Content, existingList[0].ID)
-				}
-				return
-			case "supersede":
-				// Find the memory to supersede
-				targetID := decision.TargetID
-				if targetID == "" && len(existingList) > 0 {
-					targetID = existingList[0].ID
-				}
-				if targetID != "" {
-					mem, err := r.memoryService.Supersede(ctx, targetID, memory.Memory{
-						Content:         extraction.Content,
-						Category:        category,
-						AgentHandle:     ag.Handle,
-						SourceSessionID: sessionID,
-					}, decision.Reason)
-					if err != nil {
-						if r.debug {
-							fmt.Fprintf(os.Stderr, "DEBUG: memory supersede failed: %v\n", err)
-						}
-						if r.formationService != nil {
-							r.formationService.NotifyFailed(extraction.Content, err)
-						}
-					} else {
-						if r.formationService != nil {
-							r.formationService.NotifySuperseded(mem, targetID)
-						}
-					}
-					return
-				}
+			if r.formationService != nil {
+				r.formationService.NotifyCreated(created)
 			}
-			// action == "new", fall through to create
+			decision.Action, decision.MemoryID = uipkg.MemoryActionCreated, created.ID
 		}
+		r.reportFormation(decision)
 	}
+}
 
-	// Create the memory
-	mem, err := r.memoryService.Create(ctx, memory.Memory{
-		Content:         extraction.Content,
-		Category:        category,
-		AgentHandle:     ag.Handle,
-		SourceSessionID: sessionID,
+// proposeMemory queues mem for "ayo memory review" instead of storing it.
+// Accepting the proposal supersedes targetID when set.
+func (r *Runner) proposeMemory(ctx context.Context, mem memory.Memory, targetID, reason string, decision uipkg.MemoryDecision) {
+	proposal, err := r.memoryService.Propose(ctx, memory.Proposal{
+		AgentHandle:        mem.AgentHandle,
+		Content:            mem.Content,
+		Category:           mem.Category,
+		Embedding:          mem.Embedding,
+		SourceSessionID:    mem.SourceSessionID,
+		SupersedesID:       targetID,
+		SupersessionReason: reason,
 	})
 	if err != nil {
 		if r.debug {
-			fmt.Fprintf(os.Stderr, "DEBUG: memory creation failed: %v\n", err)
-		}
-		if r.formationService != nil {
-			r.formationService.NotifyFailed(extraction.Content, err)
+			fmt.Fprintf(os.Stderr, "DEBUG: memory proposal failed: %v\n", err)
 		}
+		decision.Action, decision.Reason = uipkg.MemoryActionFailed, err.Error()
 	} else {
-		if r.formationService != nil {
-			r.formationService.NotifyCreated(mem)
+		decision.Action, decision.MemoryID = uipkg.MemoryActionProposed, proposal.ID
+	}
+	r.reportFormation(decision)
+}
+
+// formationDecision describes an extracted item and the existing memory its
+// duplicate check matched, if any. The caller fills in the action taken.
+func formationDecision(item smallmodel.ExtractedMemory, d dedupDecision, similar memory.BatchSearchResult) uipkg.MemoryDecision {
+	decision := uipkg.MemoryDecision{
+		Content:  item.Content,
+		Category: string(categoryFromString(item.Category)),
+	}
+	if d.action == "new" || d.targetID == "" {
+		return decision
+	}
+	decision.TargetID = d.targetID
+	decision.Reason = d.reason
+	for _, res := range similar.Results {
+		if res.Memory.ID == d.targetID {
+			decision.TargetContent = res.Memory.Content
+			decision.Similarity = res.Similarity
+			break
 		}
 	}
+	return decision
+}
+
+// reportFormation sends a formation decision to the async status callback.
+// It runs on the turn's goroutine, so the callback must not block.
+func (r *Runner) reportFormation(d uipkg.MemoryDecision) {
+	if r.onAsyncStatus == nil {
+		return
+	}
+	status := uipkg.AsyncStatusCompleted
+	if d.Action == uipkg.MemoryActionFailed {
+		status = uipkg.AsyncStatusFailed
+	}
+	r.onAsyncStatus(uipkg.AsyncStatusMsg{
+		ID:        uuid.New().String()[:8],
+		Operation: uipkg.AsyncOpMemoryFormation,
+		Status:    status,
+		Message:   d.Summary(),
+		Decision:  &d,
+	})
+}
+
+// formationDedupConcurrency bounds concurrent small-model duplicate checks.
+const formationDedupConcurrency = 3
+
+// dedupDecision is the resolved outcome of a duplicate check for one item.
+type dedupDecision struct {
+	action   string // "new", "duplicate", or "supersede"
+	targetID string // Full ID of the matched memory for duplicate/supersede
+	reason   string
+}
+
+// checkDuplicates asks the small model whether each item duplicates or
+// supersedes one of its similar memories. Checks are independent, so they
+// run concurrently; decisions are returned in item order. Items with no
+// similar memories, or whose check fails, are treated as new.
+func (r *Runner) checkDuplicates(ctx context.Context, contents []string, similar []memory.BatchSearchResult) []dedupDecision {
+	decisions := make([]dedupDecision, len(contents))
+	for i := range decisions {
+		decisions[i].action = "new"
+	}
+
+	sem := make(chan struct{}, formationDedupConcurrency)
+	var wg sync.WaitGroup
+	for i := range contents {
+		if i >= len(similar) || len(similar[i].Results) == 0 {
+			continue
+		}
+
+		existingList := make([]smallmodel.ExistingMemory, len(similar[i].Results))
+		for j, m := range similar[i].Results {
+			existingList[j] = smallmodel.ExistingMemory{
+				ID:      m.Memory.ID,
+				Content: m.Memory.Content,
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, existingList []smallmodel.ExistingMemory) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			decision, err := r.smallModel.CheckDuplicate(ctx, contents[i], existingList)
+			if err != nil {
+				if r.debug {
+					fmt.Fprintf(os.Stderr, "DEBUG: dedup check failed: %v\n", err)
+				}
+				return
+			}
+
+			switch decision.Action {
+			case "duplicate", "supersede":
+				decisions[i] = dedupDecision{
+					action:   decision.Action,
+					targetID: resolveDedupTarget(decision.TargetID, existingList),
+					reason:   decision.Reason,
+				}
+			}
+		}(i, existingList)
+	}
+	wg.Wait()
+
+	return decisions
+}
+
+// resolveDedupTarget maps the small model's target (often the short ID shown
+// in the prompt) to a full memory ID, defaulting to the closest match.
+func resolveDedupTarget(target string, existing []smallmodel.ExistingMemory) string {
+	if target != "" {
+		for _, m := range existing {
+			if strings.HasPrefix(m.ID, target) {
+				return m.ID
+			}
+		}
+	}
+	return existing[0].ID
 }
 
 // categoryFromString converts a category string to memory.Category.
@@ -1021,45 +1658,69 @@ func categoryFromString(s string) memory.Category {
 // castToStructuredOutput takes the agent's response and casts it to the required output schema.
 // It uses GenerateObject to produce structured output, then validates against the schema.
 // If validation fails, it retries by providing error feedback to the model.
+// When the stream writer displays structured output, the object is streamed
+// with StreamObject instead; only the final object is validated.
 func (r *Runner) castToStructuredOutput(ctx context.Context, model fantasy.LanguageModel, ag agent.Agent, agentOutput string, ui *uipkg.UI) (string, error) {
 	if ag.OutputSchema == nil {
 		return agentOutput, nil
 	}
 
+	sw, _ := r.streamWriter.(StructuredOutputWriter)
+	streamObjects := true
+
+	system := castSystemPrompt(ag.OutputSchema)
+
 	var lastError error
 	for attempt := 0; attempt < maxOutputCastRetries; attempt++ {
 		// Build prompt for structured output casting
 		var prompt fantasy.Prompt
 		if attempt == 0 {
 			prompt = fantasy.Prompt{
-				fantasy.NewSystemMessage("You are a data extraction assistant. Extract and format the information from the provided content into the required JSON structure. Output only valid JSON matching the schema."),
+				fantasy.NewSystemMessage(system),
 				fantasy.NewUserMessage(fmt.Sprintf("Extract and format the following content into the required JSON structure:\n\n%s", agentOutput)),
 			}
 		} else {
 			// Retry with error feedback
 			prompt = fantasy.Prompt{
-				fantasy.NewSystemMessage("You are a data extraction assistant. Extract and format the information from the provided content into the required JSON structure. Output only valid JSON matching the schema."),
+				fantasy.NewSystemMessage(system),
 				fantasy.NewUserMessage(fmt.Sprintf("Extract and format the following content into the required JSON structure:\n\n%s\n\nPrevious attempt failed validation with error: %v\n\nPlease fix the output to match the schema requirements.", agentOutput, lastError)),
 			}
 		}
 
-		// Show spinner for casting
-		var spinner *uipkg.Spinner
-		if attempt == 0 {
-			spinner = uipkg.NewSpinnerWithDepth("formatting output...", r.depth)
-		} else {
-			spinner = uipkg.NewSpinnerWithDepth(fmt.Sprintf("reformatting output (attempt %d/%d)...", attempt+1, maxOutputCastRetries), r.depth)
-		}
-		spinner.Start()
-
-		response, err := model.GenerateObject(ctx, fantasy.ObjectCall{
+		call := fantasy.ObjectCall{
 			Prompt:            prompt,
 			Schema:            *ag.OutputSchema,
 			SchemaName:        "Output",
-			SchemaDescription: "Required output format for the agent response",
-		})
+			SchemaDescription: castSchemaDescription(ag.OutputSchema),
+		}
 
-		spinner.Stop()
+		// Writers that display structured output show the partial object as
+		// it streams in, or the active attempt when the model can't stream
+		var object any
+		var err error
+		if sw != nil {
+			sw.WriteStructuredDelta("", attempt+1, maxOutputCastRetries)
+			if streamObjects {
+				object, err = streamObject(ctx, model, call, sw, attempt+1)
+				if errors.Is(err, errObjectStreamUnavailable) {
+					streamObjects = false
+				}
+			}
+			if !streamObjects {
+				object, err = generateObject(ctx, model, call)
+			}
+		} else {
+			// Show spinner for casting
+			var spinner *uipkg.Spinner
+			if attempt == 0 {
+				spinner = uipkg.NewPhaseSpinner(uipkg.PhaseFormatting, r.depth)
+			} else {
+				spinner = uipkg.NewPhaseSpinner(fmt.Sprintf("reformatting output (attempt %d/%d)", attempt+1, maxOutputCastRetries), r.depth)
+			}
+			spinner.Start()
+			object, err = generateObject(ctx, model, call)
+			spinner.Stop()
+		}
 
 		if err != nil {
 			lastError = err
@@ -1067,7 +1728,7 @@ func (r *Runner) castToStructuredOutput(ctx context.Context, model fantasy.Langu
 		}
 
 		// Convert object back to JSON string with pretty formatting
-		jsonBytes, err := json.MarshalIndent(response.Object, "", "  ")
+		jsonBytes, err := json.MarshalIndent(object, "", "  ")
 		if err != nil {
 			lastError = fmt.Errorf("failed to marshal structured output: %w", err)
 			continue
@@ -1087,6 +1748,15 @@ func (r *Runner) castToStructuredOutput(ctx context.Context, model fantasy.Langu
 	return "", fmt.Errorf("failed to produce valid structured output after %d attempts: %w", maxOutputCastRetries, lastError)
 }
 
+// generateObject generates an object in a single, non-streaming call.
+func generateObject(ctx context.Context, model fantasy.LanguageModel, call fantasy.ObjectCall) (any, error) {
+	response, err := model.GenerateObject(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	return response.Object, nil
+}
+
 // fantasyPartsToSessionParts converts Fantasy message parts to session content parts.
 func (r *Runner) fantasyPartsToSessionParts(parts []fantasy.MessagePart) []session.ContentPart {
 	var result []session.ContentPart
@@ -2,13 +2,33 @@ package run
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
 
 	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+	uipkg "github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 func TestBuildMessagesOmitsEmpty(t *testing.T) {
@@ -108,6 +128,57 @@ func TestBuildMessagesWithTextAttachment(t *testing.T) {
 	}
 }
 
+func TestBuildMessagesIncludesContextFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	styleGuide := tmpDir + "/style.md"
+	if err := os.WriteFile(styleGuide, []byte("Use tabs"), 0644); err != nil {
+		t.Fatalf("failed to create context file: %v", err)
+	}
+	attachment := tmpDir + "/main.go"
+	if err := os.WriteFile(attachment, []byte("package main"), 0644); err != nil {
+		t.Fatalf("failed to create attachment: %v", err)
+	}
+
+	r := &Runner{}
+	ag := agent.Agent{CombinedSystem: "SYS", ContextFiles: []string{styleGuide}}
+	msgs := r.buildMessagesWithAttachments(context.Background(), ag, "review", []string{attachment})
+
+	content := getTextContent(msgs[len(msgs)-1])
+	styleIdx := strings.Index(content, `<file path="style.md">`)
+	mainIdx := strings.Index(content, `<file path="main.go">`)
+	if styleIdx < 0 || mainIdx < 0 {
+		t.Fatalf("expected context file and attachment inlined, got %q", content)
+	}
+	if styleIdx > mainIdx {
+		t.Error("context files should come before run attachments")
+	}
+}
+
+func TestChatKeepsContextUnsentOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	styleGuide := tmpDir + "/style.md"
+	if err := os.WriteFile(styleGuide, []byte("Use tabs"), 0644); err != nil {
+		t.Fatalf("failed to create context file: %v", err)
+	}
+
+	ag := agent.Agent{Handle: "@test", ContextFiles: []string{styleGuide}}
+	r := &Runner{sessions: map[string]*ChatSession{
+		"@test": {Agent: ag},
+	}}
+
+	// An empty model fails the run after the user message is built,
+	// so the message must be rolled back without marking context as sent.
+	if _, err := r.Chat(context.Background(), ag, "hello"); err == nil {
+		t.Fatal("expected error for empty model")
+	}
+	if r.sessions["@test"].ContextSent {
+		t.Error("context should not be marked sent after a failed run")
+	}
+	if len(r.sessions["@test"].Messages) != 0 {
+		t.Errorf("failed user message should be removed, got %d messages", len(r.sessions["@test"].Messages))
+	}
+}
+
 func TestBuildMessagesWithBinaryAttachment(t *testing.T) {
 	// Create a temp PNG file for testing (binary file should use FilePart)
 	tmpDir := t.TempDir()
@@ -206,3 +277,675 @@ func TestGetSessionMessages_EmptySessionID(t *testing.T) {
 		t.Errorf("expected nil messages, got %v", msgs)
 	}
 }
+
+func TestResumeSessionKeepsExistingTitle(t *testing.T) {
+	r := &Runner{sessions: make(map[string]*ChatSession)}
+	ag := agent.Agent{Handle: "@test", CombinedSystem: "system"}
+
+	history := []session.Message{
+		{Role: session.RoleUser, Parts: []session.ContentPart{session.TextContent{Text: "hello"}}},
+		{Role: session.RoleAssistant, Parts: []session.ContentPart{session.TextContent{Text: "hi"}}},
+	}
+	if err := r.ResumeSession(context.Background(), ag, "sess-1", history); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	resumed := r.sessions["@test"]
+	if resumed.SessionID != "sess-1" {
+		t.Errorf("SessionID = %q, want sess-1", resumed.SessionID)
+	}
+	if !resumed.TitleGenerated {
+		t.Error("resumed session with history should not regenerate its title")
+	}
+	if len(resumed.Messages) != 3 {
+		t.Errorf("expected system prompt plus 2 history messages, got %d", len(resumed.Messages))
+	}
+
+	if err := r.ResumeSession(context.Background(), ag, "sess-2", nil); err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if r.sessions["@test"].TitleGenerated {
+		t.Error("empty session should still get a generated title")
+	}
+}
+
+func TestResumeMessages(t *testing.T) {
+	r := &Runner{sessions: make(map[string]*ChatSession)}
+	ag := agent.Agent{Handle: "@test", CombinedSystem: "system", ToolsPrompt: "tools"}
+
+	history := []session.Message{
+		{Role: session.RoleSystem, Parts: []session.ContentPart{session.TextContent{Text: "stale system"}}},
+		{Role: session.RoleUser, Parts: []session.ContentPart{session.TextContent{Text: "list files"}}},
+		{Role: session.RoleAssistant, Parts: []session.ContentPart{session.ToolCall{ID: "c1", Name: "bash", Input: `{"command":"ls"}`}}},
+		{Role: session.RoleTool, Parts: []session.ContentPart{session.ToolResult{ToolCallID: "c1", Name: "bash", Content: "a.go"}}},
+		{Role: session.RoleAssistant, Parts: []session.ContentPart{session.TextContent{Text: "a.go"}, session.Finish{Reason: session.FinishReasonStop}}},
+	}
+	msgs := r.ResumeMessages(context.Background(), ag, history)
+
+	var roles []string
+	for _, m := range msgs {
+		roles = append(roles, string(m.Role))
+	}
+	if got := strings.Join(roles, ","); got != "system,system,user,assistant,tool,assistant" {
+		t.Fatalf("roles = %s", got)
+	}
+	if getTextContent(msgs[0]) != "system" {
+		t.Errorf("first system message = %q, want the agent's current prompt", getTextContent(msgs[0]))
+	}
+	if call, ok := msgs[3].Content[0].(fantasy.ToolCallPart); !ok || call.ToolCallID != "c1" || call.Input != `{"command":"ls"}` {
+		t.Errorf("tool call = %+v", msgs[3].Content[0])
+	}
+	if len(msgs[5].Content) != 1 {
+		t.Errorf("finish parts should be dropped, got %+v", msgs[5].Content)
+	}
+
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"tool-call"`) {
+		t.Errorf("JSON should tag part types, got %s", data)
+	}
+	if len(r.sessions) != 0 {
+		t.Error("ResumeMessages should not start a chat session")
+	}
+}
+
+func TestBuildTitlePrompt(t *testing.T) {
+	// Default template includes both sides of the exchange
+	got := buildTitlePrompt("", "How do I sort a slice?", "Use sort.Slice.")
+	if !strings.Contains(got, "User: How do I sort a slice?") || !strings.Contains(got, "Assistant: Use sort.Slice.") {
+		t.Errorf("default prompt missing exchange: %q", got)
+	}
+	if strings.Contains(got, "{{") {
+		t.Errorf("default prompt has unreplaced placeholders: %q", got)
+	}
+
+	// Custom template with placeholders
+	got = buildTitlePrompt("Title for: {{user}} / {{assistant}}", "hello", "hi")
+	if got != "Title for: hello / hi" {
+		t.Errorf("custom prompt = %q", got)
+	}
+
+	// Custom template without placeholders gets the exchange appended
+	got = buildTitlePrompt("Give a three word title.", "hello", "hi")
+	if !strings.HasPrefix(got, "Give a three word title.") || !strings.Contains(got, "User: hello") {
+		t.Errorf("custom prompt without placeholders = %q", got)
+	}
+}
+
+func TestCheckDuplicatesConcurrent(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		body, _ := io.ReadAll(r.Body)
+		content := `{"action": "new", "reason": "different"}`
+		switch {
+		case strings.Contains(string(body), "New memory: dup"):
+			content = `{"action": "duplicate", "reason": "same", "target_id": "bbbbbbbb"}`
+		case strings.Contains(string(body), "New memory: update"):
+			content = `{"action": "supersede", "reason": "newer", "target_id": "aaaaaaaa"}`
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"model":   "test",
+			"message": map[string]string{"role": "assistant", "content": content},
+			"done":    true,
+		})
+	}))
+	defer server.Close()
+
+	r := &Runner{smallModel: smallmodel.NewService(smallmodel.Config{Host: server.URL})}
+
+	similarTo := func(ids ...string) memory.BatchSearchResult {
+		var b memory.BatchSearchResult
+		for _, id := range ids {
+			b.Results = append(b.Results, memory.SearchResult{Memory: memory.Memory{ID: id, Content: "existing " + id}})
+		}
+		return b
+	}
+	contents := []string{"update", "dup", "fresh", "update", "other", "unmatched"}
+	similar := []memory.BatchSearchResult{
+		similarTo("aaaaaaaa-1111", "bbbbbbbb-2222"),
+		similarTo("aaaaaaaa-1111", "bbbbbbbb-2222"),
+		similarTo("cccccccc-3333"),
+		similarTo("aaaaaaaa-1111"),
+		similarTo("dddddddd-4444"),
+		{}, // No similar memories; no check needed
+	}
+
+	decisions := r.checkDuplicates(context.Background(), contents, similar)
+
+	want := []dedupDecision{
+		{action: "supersede", targetID: "aaaaaaaa-1111", reason: "newer"},
+		{action: "duplicate", targetID: "bbbbbbbb-2222", reason: "same"},
+		{action: "new"},
+		{action: "supersede", targetID: "aaaaaaaa-1111", reason: "newer"},
+		{action: "new"},
+		{action: "new"},
+	}
+	for i := range want {
+		if decisions[i] != want[i] {
+			t.Errorf("decisions[%d] = %+v, want %+v", i, decisions[i], want[i])
+		}
+	}
+
+	if got := maxInFlight.Load(); got > formationDedupConcurrency {
+		t.Errorf("max concurrent checks = %d, want <= %d", got, formationDedupConcurrency)
+	}
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("expected checks to run concurrently, max in flight = %d", got)
+	}
+}
+
+func TestResolveDedupTarget(t *testing.T) {
+	existing := []smallmodel.ExistingMemory{{ID: "aaaaaaaa-1111"}, {ID: "bbbbbbbb-2222"}}
+
+	if got := resolveDedupTarget("bbbbbbbb", existing); got != "bbbbbbbb-2222" {
+		t.Errorf("short ID: got %q", got)
+	}
+	if got := resolveDedupTarget("bbbbbbbb-2222", existing); got != "bbbbbbbb-2222" {
+		t.Errorf("full ID: got %q", got)
+	}
+	if got := resolveDedupTarget("", existing); got != "aaaaaaaa-1111" {
+		t.Errorf("empty target should default to closest match, got %q", got)
+	}
+	if got := resolveDedupTarget("zzzz", existing); got != "aaaaaaaa-1111" {
+		t.Errorf("unknown target should default to closest match, got %q", got)
+	}
+}
+
+func TestWaitForFormationsWaitsForTurn(t *testing.T) {
+	r := &Runner{}
+	r.forming.Add(1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		r.forming.Done()
+	}()
+
+	start := time.Now()
+	r.WaitForFormations(time.Second)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("WaitForFormations returned after %v, want once the formation finished", elapsed)
+	}
+
+	r.forming.Add(1)
+	defer r.forming.Done()
+	start = time.Now()
+	r.WaitForFormations(20 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("WaitForFormations ignored its timeout, returned after %v", elapsed)
+	}
+}
+
+func TestFormationDecision(t *testing.T) {
+	item := smallmodel.ExtractedMemory{Content: "uses pnpm", Category: "preference"}
+	similar := memory.BatchSearchResult{Results: []memory.SearchResult{
+		{Memory: memory.Memory{ID: "aaaaaaaa-1111", Content: "uses npm"}, Similarity: 0.88},
+		{Memory: memory.Memory{ID: "bbbbbbbb-2222", Content: "prefers pnpm"}, Similarity: 0.91},
+	}}
+
+	got := formationDecision(item, dedupDecision{action: "duplicate", targetID: "bbbbbbbb-2222", reason: "same"}, similar)
+	want := uipkg.MemoryDecision{
+		Content:       "uses pnpm",
+		Category:      "preference",
+		TargetID:      "bbbbbbbb-2222",
+		TargetContent: "prefers pnpm",
+		Similarity:    0.91,
+		Reason:        "same",
+	}
+	if got != want {
+		t.Errorf("duplicate decision = %+v, want %+v", got, want)
+	}
+
+	got = formationDecision(item, dedupDecision{action: "new"}, similar)
+	if got.TargetID != "" || got.Similarity != 0 {
+		t.Errorf("new decision should have no target, got %+v", got)
+	}
+}
+
+func TestReportFormation(t *testing.T) {
+	(&Runner{}).reportFormation(uipkg.MemoryDecision{Action: uipkg.MemoryActionCreated}) // No callback: no-op
+
+	var got []uipkg.AsyncStatusMsg
+	r := &Runner{}
+	r.SetAsyncStatus(func(msg uipkg.AsyncStatusMsg) { got = append(got, msg) })
+	r.reportFormation(uipkg.MemoryDecision{Action: uipkg.MemoryActionSkipped, TargetContent: "prefers pnpm"})
+	r.reportFormation(uipkg.MemoryDecision{Action: uipkg.MemoryActionFailed, Reason: "boom"})
+
+	if len(got) != 2 {
+		t.Fatalf("got %d status messages, want 2", len(got))
+	}
+	if got[0].Operation != uipkg.AsyncOpMemoryFormation || got[0].Status != uipkg.AsyncStatusCompleted ||
+		got[0].Decision == nil || got[0].Message != `Already remembered as "prefers pnpm"` {
+		t.Errorf("skipped status = %+v", got[0])
+	}
+	if got[1].Status != uipkg.AsyncStatusFailed || got[1].Message != "Failed to remember: boom" {
+		t.Errorf("failed status = %+v", got[1])
+	}
+}
+
+func TestProposeMemory(t *testing.T) {
+	ctx := context.Background()
+	conn, queries, err := db.ConnectWithQueries(ctx, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var got []uipkg.AsyncStatusMsg
+	r := &Runner{memoryService: memory.NewService(queries, nil)}
+	r.SetAsyncStatus(func(msg uipkg.AsyncStatusMsg) { got = append(got, msg) })
+	r.proposeMemory(ctx, memory.Memory{Content: "prefers pnpm", Category: memory.CategoryPreference, AgentHandle: "@ayo"},
+		"old-id", "newer preference", uipkg.MemoryDecision{Content: "prefers pnpm"})
+
+	proposals, err := r.memoryService.ListProposals(ctx, "@ayo")
+	if err != nil || len(proposals) != 1 {
+		t.Fatalf("ListProposals = %+v, %v; want one proposal", proposals, err)
+	}
+	if p := proposals[0]; p.SupersedesID != "old-id" || p.SupersessionReason != "newer preference" {
+		t.Errorf("proposal = %+v", p)
+	}
+	if n, _ := r.memoryService.Count(ctx, ""); n != 0 {
+		t.Errorf("%d memories stored, want none until review", n)
+	}
+	if len(got) != 1 || got[0].Decision.Action != uipkg.MemoryActionProposed || got[0].Decision.MemoryID != proposals[0].ID {
+		t.Errorf("status = %+v, want a proposed decision", got)
+	}
+}
+
+func TestAgentCallResponse(t *testing.T) {
+	var meta shared.AgentCallResponseMetadata
+
+	resp := agentCallResponse("@summarizer", "{\n  \"title\": \"Hello\"\n}", true)
+	if resp.Content != `{"title":"Hello"}` {
+		t.Errorf("structured content = %q, want compact JSON", resp.Content)
+	}
+	if err := json.Unmarshal([]byte(resp.Metadata), &meta); err != nil || !meta.Structured || meta.Agent != "@summarizer" {
+		t.Errorf("structured metadata = %q", resp.Metadata)
+	}
+
+	// Output that isn't JSON falls back to text
+	meta = shared.AgentCallResponseMetadata{}
+	resp = agentCallResponse("@summarizer", "not json", true)
+	json.Unmarshal([]byte(resp.Metadata), &meta)
+	if resp.Content != "not json" || meta.Structured {
+		t.Errorf("invalid JSON: content = %q, metadata = %q", resp.Content, resp.Metadata)
+	}
+
+	// Oversized output is truncated as text, even when structured
+	meta = shared.AgentCallResponseMetadata{}
+	big := `{"text":"` + strings.Repeat("x", maxAgentCallOutput) + `"}`
+	resp = agentCallResponse("@summarizer", big, true)
+	json.Unmarshal([]byte(resp.Metadata), &meta)
+	if len(resp.Content) != maxAgentCallOutput || meta.Structured || !meta.Truncated {
+		t.Errorf("oversized: %d bytes, metadata = %q", len(resp.Content), resp.Metadata)
+	}
+
+	// The cut doesn't split a multi-byte character
+	resp = agentCallResponse("@summarizer", strings.Repeat("x", maxAgentCallOutput-1)+"é", false)
+	if !utf8.ValidString(resp.Content) || len(resp.Content) != maxAgentCallOutput-1 {
+		t.Errorf("multi-byte cut: %d bytes, valid UTF-8 = %v", len(resp.Content), utf8.ValidString(resp.Content))
+	}
+}
+
+func TestAgentCallAllowed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		handle   string
+		callable []string
+		want     bool
+	}{
+		{"@ayo", nil, true},
+		{"@ayo.research", nil, true},
+		{"@writer", nil, false},
+		{"@writer", []string{"@writer"}, true},
+		{"@writer", []string{"writer"}, true},
+		{"@writer", []string{"@editor"}, false},
+		{"@team.writer", []string{"@team.*"}, true},
+		{"@writer", []string{"*"}, true},
+	}
+	for _, tt := range tests {
+		if got := agentCallAllowed(tt.handle, tt.callable); got != tt.want {
+			t.Errorf("agentCallAllowed(%q, %v) = %v, want %v", tt.handle, tt.callable, got, tt.want)
+		}
+	}
+}
+
+func TestAgentCallExecutorGuards(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx := context.Background()
+
+	r := &Runner{}
+	call := r.agentCallExecutor("@writer", []string{"*"})
+	resp, _ := call(ctx, AgentCallParams{Agent: "writer", Prompt: "hi"}, fantasy.ToolCall{})
+	if !resp.IsError || !strings.Contains(resp.Content, "self") {
+		t.Errorf("self call: %+v, want self-delegation error", resp)
+	}
+
+	call = r.agentCallExecutor("@orchestrator", nil)
+	resp, _ = call(ctx, AgentCallParams{Agent: "@writer", Prompt: "hi"}, fantasy.ToolCall{})
+	if !resp.IsError || !strings.Contains(resp.Content, "callable_agents") {
+		t.Errorf("user agent without allowlist: %+v, want not callable", resp)
+	}
+
+	r = &Runner{depth: 2, maxDepth: 2}
+	call = r.agentCallExecutor("@orchestrator", []string{"*"})
+	resp, _ = call(ctx, AgentCallParams{Agent: "@writer", Prompt: "hi"}, fantasy.ToolCall{})
+	if !resp.IsError || !strings.Contains(resp.Content, "maximum delegation depth reached (2)") {
+		t.Errorf("at max depth: %+v, want depth limit error", resp)
+	}
+}
+
+func TestAgentCallSharesRateLimiter(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(home)
+
+	server := completionServer(t, "done")
+	cfg := config.Config{
+		AgentsDir: filepath.Join(home, "agents"),
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Redaction: config.RedactionConfig{Disabled: true},
+	}
+	agentDir := filepath.Join(cfg.AgentsDir, "@writer")
+	if err := os.MkdirAll(agentDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "system.md"), []byte("You write."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "config.json"), []byte(`{"model": "test"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRunner(cfg, false, RunnerOptions{StreamWriter: NewChannelWriter(make(chan StreamEvent, 100))})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	// The parent's allowance is used up, so the next request waits about 50ms
+	r.rateLimiter = newRateLimiter(config.RateLimitConfig{RequestsPerMinute: 1200}, time.Now)
+	r.rateLimiter.requests = 0
+
+	start := time.Now()
+	call := r.agentCallExecutor("@orchestrator", []string{"*"})
+	resp, _ := call(context.Background(), AgentCallParams{Agent: "@writer", Prompt: "hi"}, fantasy.ToolCall{})
+	if resp.IsError || resp.Content != "done" {
+		t.Fatalf("agent_call = %+v, want the sub-agent's response", resp)
+	}
+	if waited := time.Since(start); waited < 40*time.Millisecond {
+		t.Errorf("agent_call took %s, want it to wait on the parent's rate limiter", waited)
+	}
+}
+
+func TestNewRunnerMaxDepth(t *testing.T) {
+	zero, five := 0, 5
+
+	r, _ := NewRunner(config.Config{}, false, RunnerOptions{})
+	if r.maxDepth != DefaultMaxDepth {
+		t.Errorf("default maxDepth = %d, want %d", r.maxDepth, DefaultMaxDepth)
+	}
+	r, _ = NewRunner(config.Config{MaxDelegationDepth: &zero}, false, RunnerOptions{})
+	if r.maxDepth != 0 {
+		t.Errorf("config maxDepth = %d, want 0", r.maxDepth)
+	}
+	r, _ = NewRunner(config.Config{MaxDelegationDepth: &zero}, false, RunnerOptions{MaxDepth: &five})
+	if r.maxDepth != 5 {
+		t.Errorf("option maxDepth = %d, want the option to override config", r.maxDepth)
+	}
+
+	negative := -1
+	if _, err := NewRunner(config.Config{MaxDelegationDepth: &negative}, false, RunnerOptions{}); err == nil {
+		t.Error("expected an error for a negative depth")
+	}
+}
+
+func TestChatKeepsPartialResponseOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, text := range []string{"The answer ", "is"} {
+			chunk, _ := json.Marshal(map[string]any{
+				"id": "c1", "object": "chat.completion.chunk", "created": 1, "model": "test",
+				"choices": []map[string]any{{"index": 0, "delta": map[string]string{"role": "assistant", "content": text}}},
+			})
+			io.WriteString(w, "data: "+string(chunk)+"\n\n")
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	services, err := session.Connect(ctx, t.TempDir()+"/ayo.db")
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	events := make(chan StreamEvent, 100)
+	cfg := config.Config{Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"}}
+	r, err := NewRunner(cfg, false, RunnerOptions{Services: services, StreamWriter: NewChannelWriter(events)})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	// Cancel once the second delta has streamed
+	chatCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		deltas := 0
+		for e := range events {
+			if e.Type == EventTextDelta {
+				if deltas++; deltas == 2 {
+					cancel()
+				}
+			}
+		}
+	}()
+
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true}
+	resp, err := r.Chat(chatCtx, ag, "question")
+	close(events)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("Chat error = %v, want ErrInterrupted", err)
+	}
+	if resp != "The answer is" {
+		t.Errorf("partial response = %q", resp)
+	}
+
+	history := r.sessions["@ayo"].Messages
+	last := history[len(history)-1]
+	if last.Role != fantasy.MessageRoleAssistant || getTextContent(last) != "The answer is" {
+		t.Errorf("last history message = %s %q, want the partial response", last.Role, getTextContent(last))
+	}
+	if prev := history[len(history)-2]; prev.Role != fantasy.MessageRoleUser {
+		t.Errorf("user message should be kept, got %s", prev.Role)
+	}
+
+	stored, err := services.Messages.List(ctx, r.GetSessionID("@ayo"))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d messages, want 2", len(stored))
+	}
+	if f := stored[1].FinishPart(); f == nil || f.Reason != session.FinishReasonCanceled {
+		t.Errorf("stored finish = %+v, want canceled", f)
+	}
+}
+
+func TestChatConcurrentAgents(t *testing.T) {
+	r := &Runner{sessions: make(map[string]*ChatSession)}
+	history := []session.Message{
+		{Role: session.RoleUser, Parts: []session.ContentPart{session.TextContent{Text: "earlier"}}},
+	}
+
+	// Agents without a model fail each turn right after its session is
+	// started, so only the runner's own locking orders the goroutines
+	const workers, agents = 8, 10
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range agents {
+				ag := agent.Agent{Handle: fmt.Sprintf("@worker%d-%d", w, i), CombinedSystem: "system"}
+				if i%2 == 0 {
+					r.ResumeSession(context.Background(), ag, "", history)
+				}
+				r.Chat(context.Background(), ag, "hello")
+				r.GetSessionID(ag.Handle)
+				r.GetSessionMessages(context.Background(), ag.Handle)
+			}
+		})
+	}
+	wg.Wait()
+
+	if len(r.sessions) != workers*agents {
+		t.Fatalf("%d sessions, want %d", len(r.sessions), workers*agents)
+	}
+	for handle, cs := range r.sessions {
+		// Failed turns are rolled back, leaving the system prompt and any
+		// resumed history
+		if len(cs.Messages) != 1 && len(cs.Messages) != 1+len(history) {
+			t.Errorf("%s has %d messages", handle, len(cs.Messages))
+		}
+	}
+}
+
+func TestChatSessionCreatesOutsideLock(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir() + "/ayo.db"
+	services, err := session.Connect(ctx, path)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	r, err := NewRunner(config.Config{}, false, RunnerOptions{Services: services, StreamWriter: NullWriter{}})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	other := agent.Agent{Handle: "@other", CombinedSystem: "system"}
+	r.ResumeSession(ctx, other, "existing", nil)
+
+	// Hold the write lock so creating a database session waits for it
+	conn, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM sessions WHERE id = ''"); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan *ChatSession)
+	go func() {
+		done <- r.chatSession(ctx, agent.Agent{Handle: "@new", CombinedSystem: "system"}, "hello")
+	}()
+	for {
+		r.sessionsMu.Lock()
+		_, claimed := r.sessions["@new"]
+		r.sessionsMu.Unlock()
+		if claimed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Other agents' sessions are available while @new's is being created
+	start := time.Now()
+	if id := r.GetSessionID("@other"); id != "existing" {
+		t.Errorf("GetSessionID(@other) = %q", id)
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		t.Errorf("GetSessionID(@other) took %s while another session was created", waited)
+	}
+
+	tx.Rollback()
+	cs := <-done
+	if cs.SessionID == "" || r.GetSessionID("@new") != cs.SessionID {
+		t.Errorf("@new session ID = %q, GetSessionID = %q", cs.SessionID, r.GetSessionID("@new"))
+	}
+}
+
+func TestChatConcurrentSessions(t *testing.T) {
+	server := completionServer(t, "ok")
+
+	ctx := context.Background()
+	services, err := session.Connect(ctx, t.TempDir()+"/ayo.db")
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	cfg := config.Config{Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"}}
+	r, err := NewRunner(cfg, false, RunnerOptions{Services: services, StreamWriter: NullWriter{}})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	defer r.Shutdown(false, time.Second)
+
+	chat := func(handle, input string) {
+		ag := agent.Agent{Handle: handle, Model: "test", CombinedSystem: "system", BuiltIn: true}
+		if _, err := r.Chat(ctx, ag, input); err != nil {
+			t.Errorf("%s: Chat: %v", handle, err)
+		}
+	}
+
+	// Workers chat with distinct agents at once, while two callers take
+	// turns in the same session
+	const workers, agents, turns = 4, 3, 3
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range agents {
+				chat(fmt.Sprintf("@worker%d-%d", w, i), "hello")
+			}
+		})
+	}
+	for range 2 {
+		wg.Go(func() {
+			for i := range turns {
+				chat("@shared", fmt.Sprintf("turn %d", i))
+			}
+		})
+	}
+	wg.Wait()
+
+	for handle, cs := range r.sessions {
+		// The system message, then a user message and a reply per turn
+		want := 2
+		if handle == "@shared" {
+			want = 2 * 2 * turns
+		}
+		if got := len(cs.Messages); got != 1+want {
+			t.Errorf("%s has %d messages, want %d", handle, got, 1+want)
+		}
+		stored, err := services.Messages.List(ctx, cs.SessionID)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(stored) != want {
+			t.Errorf("%s stored %d messages, want %d", handle, len(stored), want)
+		}
+	}
+
+	sessions, err := services.Sessions.List(ctx, 100)
+	if err != nil {
+		t.Fatalf("List sessions: %v", err)
+	}
+	if len(r.sessions) != workers*agents+1 || len(sessions) != len(r.sessions) {
+		t.Errorf("%d chat sessions and %d stored, want one per agent", len(r.sessions), len(sessions))
+	}
+}
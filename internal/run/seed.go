@@ -0,0 +1,34 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+)
+
+// SeedSupported reports whether requests to p can carry a sampling seed.
+// Other providers ignore the seed.
+func SeedSupported(p catwalk.Provider) bool {
+	switch p.Type {
+	case catwalk.TypeOpenAI, catwalk.TypeOpenAICompat:
+		return true
+	case catwalk.TypeAnthropic, catwalk.TypeGoogle, catwalk.TypeOpenRouter:
+		return false
+	default:
+		return p.APIEndpoint != ""
+	}
+}
+
+// recordSeed saves the runner's sampling seed to the session in ctx so the
+// run can be reproduced. Seeds the provider ignores are not recorded.
+func (r *Runner) recordSeed(ctx context.Context) {
+	sessionID := GetSessionIDFromContext(ctx)
+	if r.seed == nil || !SeedSupported(r.config.Provider) || r.services == nil || sessionID == "" {
+		return
+	}
+	if err := r.services.Sessions.SetSeed(ctx, sessionID, *r.seed); err != nil && r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: save seed: %v\n", err)
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func TestSeedSupported(t *testing.T) {
+	tests := []struct {
+		provider catwalk.Provider
+		want     bool
+	}{
+		{catwalk.Provider{Type: catwalk.TypeOpenAI}, true},
+		{catwalk.Provider{Type: catwalk.TypeOpenAICompat}, true},
+		{catwalk.Provider{Type: catwalk.TypeAnthropic}, false},
+		{catwalk.Provider{Type: catwalk.TypeGoogle}, false},
+		{catwalk.Provider{Type: catwalk.TypeOpenRouter}, false},
+		{catwalk.Provider{Type: "custom", APIEndpoint: "http://localhost:1234/v1"}, true},
+		{catwalk.Provider{Type: "custom"}, false},
+	}
+	for _, tt := range tests {
+		if got := SeedSupported(tt.provider); got != tt.want {
+			t.Errorf("SeedSupported(%+v) = %v, want %v", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestSeedIsSentAndRecorded(t *testing.T) {
+	var seeds []any
+	backend := completionServer(t, "answer")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		json.Unmarshal(body, &req)
+		seeds = append(seeds, req["seed"])
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	services, err := session.Connect(context.Background(), filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { services.Close() })
+
+	cfg := config.Config{
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Redaction: config.RedactionConfig{Disabled: true},
+		Titles:    config.TitlesConfig{Disabled: true},
+	}
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true, CombinedSystem: "You are helpful."}
+
+	seed := int64(42)
+	for _, s := range []*int64{&seed, nil} {
+		r, err := NewRunner(cfg, false, RunnerOptions{
+			Services:     services,
+			StreamWriter: NewChannelWriter(make(chan StreamEvent, 100)),
+			Seed:         s,
+		})
+		if err != nil {
+			t.Fatalf("NewRunner: %v", err)
+		}
+		result, err := r.TextWithSession(context.Background(), ag, "question", nil)
+		if err != nil {
+			t.Fatalf("TextWithSession: %v", err)
+		}
+
+		sess, err := services.Sessions.Get(context.Background(), result.SessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s == nil && sess.Seed != nil {
+			t.Errorf("session seed = %d, want none", *sess.Seed)
+		}
+		if s != nil && (sess.Seed == nil || *sess.Seed != 42) {
+			t.Errorf("session seed = %v, want 42", sess.Seed)
+		}
+	}
+
+	if len(seeds) != 2 || seeds[0] != float64(42) || seeds[1] != nil {
+		t.Errorf("request seeds = %v, want [42 <nil>]", seeds)
+	}
+}
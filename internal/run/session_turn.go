@@ -0,0 +1,116 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+// DefaultMaxToolResultBytes caps each tool result stored in a session unless
+// configured otherwise.
+const DefaultMaxToolResultBytes = 32 * 1024
+
+// turnMessages returns the assistant tool calls and tool results produced by
+// the steps of a turn. The final assistant reply is left out, since callers
+// append it with the complete response text.
+func turnMessages(steps []fantasy.StepResult) []fantasy.Message {
+	var msgs []fantasy.Message
+	for _, step := range steps {
+		msgs = append(msgs, step.Messages...)
+	}
+	if n := len(msgs); n > 0 && msgs[n-1].Role == fantasy.MessageRoleAssistant && !hasToolCall(msgs[n-1]) {
+		msgs = msgs[:n-1]
+	}
+	return msgs
+}
+
+func hasToolCall(msg fantasy.Message) bool {
+	for _, part := range msg.Content {
+		if _, ok := part.(fantasy.ToolCallPart); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// persistTurn stores the messages a turn added after the user message: each
+// assistant message with its tool calls, and each tool message with its
+// results. Tool results are truncated to the configured size. An interrupted
+// turn marks its last assistant message as canceled.
+func (r *Runner) persistTurn(ctx context.Context, sessionID, model string, turn []fantasy.Message, interrupted bool) {
+	limit := r.maxToolResultBytes()
+	toolNames := make(map[string]string)
+
+	lastAssistant := -1
+	for i, msg := range turn {
+		if msg.Role == fantasy.MessageRoleAssistant {
+			lastAssistant = i
+		}
+	}
+
+	for i, msg := range turn {
+		var role session.MessageRole
+		switch msg.Role {
+		case fantasy.MessageRoleAssistant:
+			role = session.RoleAssistant
+		case fantasy.MessageRoleTool:
+			role = session.RoleTool
+		default:
+			continue
+		}
+
+		parts := r.fantasyPartsToSessionParts(msg.Content)
+		for j, part := range parts {
+			switch p := part.(type) {
+			case session.ToolCall:
+				toolNames[p.ID] = p.Name
+			case session.ToolResult:
+				p.Name = toolNames[p.ToolCallID]
+				p.Content = truncateToolResult(p.Content, limit)
+				parts[j] = p
+			}
+		}
+		if interrupted && i == lastAssistant {
+			parts = append(parts, session.Finish{Reason: session.FinishReasonCanceled, Time: time.Now().Unix()})
+		}
+
+		r.services.Messages.Create(ctx, session.CreateMessageParams{
+			SessionID: sessionID,
+			Role:      role,
+			Parts:     parts,
+			Model:     model,
+		})
+	}
+}
+
+// maxToolResultBytes returns the configured cap on stored tool results, or
+// zero for no cap.
+func (r *Runner) maxToolResultBytes() int {
+	switch limit := r.config.Sessions.MaxToolResultBytes; {
+	case limit < 0:
+		return 0
+	case limit == 0:
+		return DefaultMaxToolResultBytes
+	default:
+		return limit
+	}
+}
+
+// truncateToolResult shortens content to at most limit bytes, cut at a
+// character boundary, and notes how much was dropped. A zero limit keeps
+// content whole.
+func truncateToolResult(content string, limit int) string {
+	if limit <= 0 || len(content) <= limit {
+		return content
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + fmt.Sprintf("\n[truncated %d of %d bytes]", len(content)-cut, len(content))
+}
@@ -0,0 +1,226 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+// toolCallServer streams a lookup tool call on the first request and a text
+// reply on every later one.
+func toolCallServer(t *testing.T) *httptest.Server {
+	return toolCallServerFor(t, "lookup", `{"q":"x"}`)
+}
+
+// toolCallServerFor streams a call to the named tool with arguments on the
+// first request and a text reply on every later one.
+func toolCallServerFor(t *testing.T, name, arguments string) *httptest.Server {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		delta := map[string]any{"role": "assistant", "content": "Done."}
+		finish := "stop"
+		if requests.Add(1) == 1 {
+			delta = map[string]any{"role": "assistant", "tool_calls": []map[string]any{{
+				"index": 0, "id": "call_1", "type": "function",
+				"function": map[string]string{"name": name, "arguments": arguments},
+			}}}
+			finish = "tool_calls"
+		}
+		for _, choice := range []map[string]any{
+			{"index": 0, "delta": delta},
+			{"index": 0, "delta": map[string]any{}, "finish_reason": finish},
+		} {
+			chunk, _ := json.Marshal(map[string]any{
+				"id": "c1", "object": "chat.completion.chunk", "created": 1, "model": "test",
+				"choices": []map[string]any{choice},
+			})
+			io.WriteString(w, "data: "+string(chunk)+"\n\n")
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChatPersistsToolCallsAndResults(t *testing.T) {
+	server := toolCallServer(t)
+
+	ctx := context.Background()
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	cfg := config.Config{
+		Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Sessions: config.SessionsConfig{MaxToolResultBytes: 10},
+	}
+	events := make(chan StreamEvent, 100)
+	go func() {
+		for range events {
+		}
+	}()
+	r, err := NewRunner(cfg, false, RunnerOptions{Services: services, StreamWriter: NewChannelWriter(events)})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true}
+	if _, err := r.Chat(ctx, ag, "look it up"); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	close(events)
+
+	stored, err := services.Messages.List(ctx, r.GetSessionID("@ayo"))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var roles []string
+	for _, msg := range stored {
+		roles = append(roles, string(msg.Role))
+	}
+	if got := strings.Join(roles, ","); got != "user,assistant,tool,assistant" {
+		t.Fatalf("stored roles = %s, want user,assistant,tool,assistant", got)
+	}
+
+	calls := stored[1].ToolCalls()
+	if len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Name != "lookup" {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	results := stored[2].ToolResults()
+	if len(results) != 1 || results[0].ToolCallID != "call_1" || results[0].Name != "lookup" {
+		t.Fatalf("tool results = %+v", results)
+	}
+	if !strings.Contains(results[0].Content, "[truncated") {
+		t.Errorf("tool result should be truncated, got %q", results[0].Content)
+	}
+	if got := stored[3].TextContent(); got != "Done." {
+		t.Errorf("final reply = %q, want Done.", got)
+	}
+
+	// The in-memory history keeps the tool call and result for the next turn
+	history := r.sessions["@ayo"].Messages
+	if n := len(history); n < 3 || history[n-2].Role != fantasy.MessageRoleTool {
+		t.Errorf("history should keep the tool result before the reply")
+	}
+}
+
+func TestChatRecordsInvalidToolArguments(t *testing.T) {
+	server := toolCallServerFor(t, "bash", `{"command": 42, "description": "Broken"}`)
+
+	ctx := context.Background()
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer services.Close()
+
+	cfg := config.Config{
+		Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Titles:   config.TitlesConfig{Disabled: true},
+	}
+	events := make(chan StreamEvent, 100)
+	go func() {
+		for range events {
+		}
+	}()
+	r, err := NewRunner(cfg, false, RunnerOptions{Services: services, StreamWriter: NewChannelWriter(events)})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true, Config: agent.Config{AllowedTools: []string{"bash"}}}
+	if _, err := r.Chat(ctx, ag, "run it"); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	close(events)
+
+	if got := r.Stats().InvalidToolCalls; got != 1 {
+		t.Errorf("InvalidToolCalls = %d, want 1", got)
+	}
+
+	stored, err := services.Messages.List(ctx, r.GetSessionID("@ayo"))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var results []session.ToolResult
+	for _, msg := range stored {
+		results = append(results, msg.ToolResults()...)
+	}
+	if len(results) != 1 || results[0].ToolCallID != "call_1" || !results[0].IsError {
+		t.Fatalf("tool results = %+v, want one stored error for call_1", results)
+	}
+	if !strings.Contains(results[0].Content, `field "command": expected string, got integer`) {
+		t.Errorf("stored result = %q, want the argument error", results[0].Content)
+	}
+}
+
+func TestTurnMessages(t *testing.T) {
+	call := fantasy.Message{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.ToolCallPart{ToolCallID: "1", ToolName: "bash"}}}
+	result := fantasy.Message{Role: fantasy.MessageRoleTool, Content: []fantasy.MessagePart{fantasy.ToolResultPart{ToolCallID: "1"}}}
+	reply := fantasy.Message{Role: fantasy.MessageRoleAssistant, Content: []fantasy.MessagePart{fantasy.TextPart{Text: "done"}}}
+
+	got := turnMessages([]fantasy.StepResult{{Messages: []fantasy.Message{call, result}}, {Messages: []fantasy.Message{reply}}})
+	if len(got) != 2 || got[0].Role != fantasy.MessageRoleAssistant || got[1].Role != fantasy.MessageRoleTool {
+		t.Errorf("turnMessages = %+v, want the tool call and result without the reply", got)
+	}
+
+	// A turn that stopped on a tool call keeps it
+	if got := turnMessages([]fantasy.StepResult{{Messages: []fantasy.Message{call}}}); len(got) != 1 {
+		t.Errorf("turnMessages dropped a trailing tool call: %+v", got)
+	}
+	if got := turnMessages(nil); len(got) != 0 {
+		t.Errorf("turnMessages(nil) = %+v", got)
+	}
+}
+
+func TestTruncateToolResult(t *testing.T) {
+	tests := []struct {
+		content string
+		limit   int
+		want    string
+	}{
+		{"short", 10, "short"},
+		{"unlimited output", 0, "unlimited output"},
+		{"0123456789abc", 10, "0123456789\n[truncated 3 of 13 bytes]"},
+		{"abécd", 3, "ab\n[truncated 4 of 6 bytes]"},
+	}
+	for _, tt := range tests {
+		if got := truncateToolResult(tt.content, tt.limit); got != tt.want {
+			t.Errorf("truncateToolResult(%q, %d) = %q, want %q", tt.content, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMaxToolResultBytes(t *testing.T) {
+	tests := []struct {
+		configured int
+		want       int
+	}{
+		{0, DefaultMaxToolResultBytes},
+		{100, 100},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		r := &Runner{config: config.Config{Sessions: config.SessionsConfig{MaxToolResultBytes: tt.configured}}}
+		if got := r.maxToolResultBytes(); got != tt.want {
+			t.Errorf("maxToolResultBytes with %d = %d, want %d", tt.configured, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+// TurnSkills are the skills in a turn's prompt. They are saved to the
+// session for agents with log_skills, and included in captured turns.
+type TurnSkills struct {
+	InPrompt []string `json:"in_prompt"`
+	Skipped  []string `json:"skipped,omitempty"` // Left out by skill selection
+	Selected bool     `json:"selected"`          // Skill selection chose InPrompt
+}
+
+// selectSkills narrows the agent's skills to those relevant to query, using
+// the memory service's embedder, and reports the skills in the prompt. The
+// agent is returned unchanged when selection does not apply.
+func (r *Runner) selectSkills(ctx context.Context, ag agent.Agent, query string) (agent.Agent, TurnSkills) {
+	all := skillNames(ag)
+	if !ag.Config.SkillSelection.Enabled {
+		return ag, TurnSkills{InPrompt: all}
+	}
+	selected, sel := ag.SelectSkills(ctx, r.memoryService.Embedder(), query)
+	names := skillNames(selected)
+	if r.debug {
+		if sel.Selected {
+			fmt.Fprintf(os.Stderr, "DEBUG: selected %d of %d skills: %s\n", len(sel.Skills), sel.Total, strings.Join(names, ", "))
+		} else {
+			fmt.Fprintf(os.Stderr, "DEBUG: including all %d skills: %s\n", sel.Total, sel.Reason)
+		}
+	}
+
+	skills := TurnSkills{InPrompt: names, Selected: sel.Selected}
+	for _, name := range all {
+		if !slices.Contains(names, name) {
+			skills.Skipped = append(skills.Skipped, name)
+		}
+	}
+	return selected, skills
+}
+
+// skillNames returns the names of the agent's skills.
+func skillNames(ag agent.Agent) []string {
+	names := make([]string, len(ag.Skills))
+	for i, m := range ag.Skills {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// logSkills saves the skills of a turn to the session in ctx, for agents
+// with log_skills.
+func (r *Runner) logSkills(ctx context.Context, ag agent.Agent) {
+	sessionID := GetSessionIDFromContext(ctx)
+	if !ag.Config.LogSkills || r.services == nil || sessionID == "" {
+		return
+	}
+	skills, ok := turnSkillsFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := r.services.Sessions.AddSkillUsage(ctx, sessionID, skills.InPrompt, skills.Skipped, skills.Selected); err != nil && r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: save skill usage: %v\n", err)
+	}
+}
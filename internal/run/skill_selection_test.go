@@ -0,0 +1,74 @@
+package run
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/skills"
+)
+
+func TestLogSkills(t *testing.T) {
+	ctx := context.Background()
+	server := completionServer(t, "Done.")
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+
+	capturePath := filepath.Join(t.TempDir(), "dataset.jsonl")
+	cfg := config.Config{
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Redaction: config.RedactionConfig{Disabled: true},
+		Capture:   config.CaptureConfig{Path: capturePath},
+	}
+	r, err := NewRunner(cfg, false, RunnerOptions{Services: services, StreamWriter: NullWriter{}})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	withSkills := []skills.Metadata{{Name: "git"}, {Name: "debugging"}}
+	logged := agent.Agent{Handle: "@logged", Model: "test", Skills: withSkills}
+	logged.Config.LogSkills = true
+	quiet := agent.Agent{Handle: "@quiet", Model: "test", Skills: withSkills}
+
+	for _, prompt := range []string{"first", "second"} {
+		if _, err := r.Chat(ctx, logged, prompt); err != nil {
+			t.Fatalf("Chat: %v", err)
+		}
+	}
+	if _, err := r.Text(ctx, quiet, "hello", nil); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+
+	report, err := services.Sessions.SkillUsageReport(ctx, session.UsageFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Skills) != 2 {
+		t.Fatalf("skills = %+v, want only @logged's two skills", report.Skills)
+	}
+	for _, s := range report.Skills {
+		if s.Agent != "@logged" || s.Sessions != 1 || s.Turns != 2 || s.Selected != 0 || s.Skipped != 0 {
+			t.Errorf("skill usage = %+v, want two turns in one session", s)
+		}
+	}
+
+	// Captured turns list the skills whether or not they are logged
+	records := readCaptures(t, capturePath)
+	if len(records) != 3 {
+		t.Fatalf("captured %d records, want 3", len(records))
+	}
+	for _, rec := range records {
+		if rec.Skills == nil || !slices.Equal(rec.Skills.InPrompt, []string{"git", "debugging"}) || rec.Skills.Selected {
+			t.Errorf("%s record skills = %+v", rec.Agent, rec.Skills)
+		}
+	}
+}
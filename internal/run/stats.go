@@ -0,0 +1,155 @@
+package run
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ToolStats is the number of calls to one tool and the time they took.
+type ToolStats struct {
+	Name     string
+	Calls    int
+	Duration time.Duration
+}
+
+// SubAgentStats is the number of agent_call invocations of one agent and
+// the time they took.
+type SubAgentStats struct {
+	Handle   string
+	Calls    int
+	Duration time.Duration
+}
+
+// RunStats summarizes where the time of a runner's turns went. Model time
+// is turn time not spent in tools, so it includes streaming and overhead.
+type RunStats struct {
+	Turns     int
+	Steps     int // Model requests, one per tool-calling iteration
+	Duration  time.Duration
+	ModelTime time.Duration
+	ToolTime  time.Duration
+	ToolCalls int
+	// InvalidToolCalls counts tool calls rejected for malformed arguments
+	InvalidToolCalls int
+	Tools            []ToolStats     // Most time first
+	SubAgents        []SubAgentStats // Most time first
+}
+
+// StatsWriter is implemented by stream writers that consume run stats.
+// The runner calls WriteStats with the running totals after each turn.
+type StatsWriter interface {
+	WriteStats(stats RunStats)
+}
+
+// statsTracker accumulates run stats across turns.
+type statsTracker struct {
+	mu        sync.Mutex
+	turns     int
+	steps     int
+	invalid   int
+	duration  time.Duration
+	tools     map[string]*ToolStats
+	subAgents map[string]*SubAgentStats
+}
+
+func (t *statsTracker) addTurn(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.turns++
+	t.duration += d
+}
+
+func (t *statsTracker) addStep() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps++
+}
+
+func (t *statsTracker) addInvalidToolCall() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.invalid++
+}
+
+func (t *statsTracker) addTool(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tools == nil {
+		t.tools = make(map[string]*ToolStats)
+	}
+	s, ok := t.tools[name]
+	if !ok {
+		s = &ToolStats{Name: name}
+		t.tools[name] = s
+	}
+	s.Calls++
+	s.Duration += d
+}
+
+func (t *statsTracker) addSubAgent(handle string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.subAgents == nil {
+		t.subAgents = make(map[string]*SubAgentStats)
+	}
+	s, ok := t.subAgents[handle]
+	if !ok {
+		s = &SubAgentStats{Handle: handle}
+		t.subAgents[handle] = s
+	}
+	s.Calls++
+	s.Duration += d
+}
+
+func (t *statsTracker) get() RunStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := RunStats{
+		Turns:            t.turns,
+		Steps:            t.steps,
+		InvalidToolCalls: t.invalid,
+		Duration:         t.duration,
+		Tools:            []ToolStats{},
+		SubAgents:        []SubAgentStats{},
+	}
+	for _, s := range t.tools {
+		stats.Tools = append(stats.Tools, *s)
+		stats.ToolCalls += s.Calls
+		stats.ToolTime += s.Duration
+	}
+	for _, s := range t.subAgents {
+		stats.SubAgents = append(stats.SubAgents, *s)
+	}
+	sort.Slice(stats.Tools, func(i, j int) bool {
+		a, b := stats.Tools[i], stats.Tools[j]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(stats.SubAgents, func(i, j int) bool {
+		a, b := stats.SubAgents[i], stats.SubAgents[j]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return a.Handle < b.Handle
+	})
+	stats.ModelTime = max(stats.Duration-stats.ToolTime, 0)
+	return stats
+}
+
+// Stats returns where the time of every turn run so far went.
+func (r *Runner) Stats() RunStats {
+	return r.stats.get()
+}
+
+// recordTurn adds a finished turn to the stats and sends the running totals
+// to the stream writer if it consumes them.
+func (r *Runner) recordTurn(d time.Duration) {
+	r.stats.addTurn(d)
+	if w, ok := r.streamWriter.(StatsWriter); ok {
+		w.WriteStats(r.stats.get())
+	}
+}
@@ -0,0 +1,73 @@
+package run
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestStatsTracker(t *testing.T) {
+	var tracker statsTracker
+	tracker.addTurn(10 * time.Second)
+	tracker.addStep()
+	tracker.addStep()
+	tracker.addTool("bash", 3*time.Second)
+	tracker.addTool("view", time.Second)
+	tracker.addTool("bash", 4*time.Second)
+	tracker.addSubAgent("@research", 2*time.Second)
+
+	got := tracker.get()
+	if got.Turns != 1 || got.Steps != 2 || got.ToolCalls != 3 {
+		t.Errorf("counts = %d turns, %d steps, %d tool calls; want 1, 2, 3", got.Turns, got.Steps, got.ToolCalls)
+	}
+	if got.ToolTime != 8*time.Second || got.ModelTime != 2*time.Second {
+		t.Errorf("ToolTime = %v, ModelTime = %v; want 8s and 2s", got.ToolTime, got.ModelTime)
+	}
+	if len(got.Tools) != 2 || got.Tools[0] != (ToolStats{Name: "bash", Calls: 2, Duration: 7 * time.Second}) {
+		t.Errorf("Tools = %+v, want bash first with 2 calls", got.Tools)
+	}
+	if len(got.SubAgents) != 1 || got.SubAgents[0].Handle != "@research" || got.SubAgents[0].Calls != 1 {
+		t.Errorf("SubAgents = %+v", got.SubAgents)
+	}
+}
+
+func TestRunEmitsStats(t *testing.T) {
+	server := completionServer(t, "answer")
+	events := make(chan StreamEvent, 100)
+	cfg := config.Config{
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"},
+		Redaction: config.RedactionConfig{Disabled: true},
+		Titles:    config.TitlesConfig{Disabled: true},
+	}
+	r, err := NewRunner(cfg, false, RunnerOptions{StreamWriter: NewChannelWriter(events)})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true, CombinedSystem: "You are helpful."}
+
+	if _, err := r.Text(context.Background(), ag, "question", nil); err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	close(events)
+
+	var last *RunStats
+	for e := range events {
+		if e.Type == EventStats {
+			last = e.Stats
+		}
+	}
+	if last == nil {
+		t.Fatal("no stats event")
+	}
+	if last.Turns != 1 || last.Steps != 1 || last.ToolCalls != 0 || last.Duration <= 0 {
+		t.Errorf("stats = %+v, want one turn of one step without tools", last)
+	}
+	if got := r.Stats(); got.Turns != 1 {
+		t.Errorf("Stats().Turns = %d, want 1", got.Turns)
+	}
+}
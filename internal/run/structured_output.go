@@ -0,0 +1,169 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"charm.land/fantasy"
+	"charm.land/fantasy/schema"
+)
+
+// StructuredOutputWriter is implemented by stream writers that display
+// structured output while it is generated.
+type StructuredOutputWriter interface {
+	// WriteStructuredDelta is called with an empty partial when an attempt
+	// starts, then with the object generated so far as indented JSON each
+	// time it grows. Partial objects are not validated.
+	WriteStructuredDelta(partial string, attempt, maxAttempts int)
+}
+
+// errObjectStreamUnavailable means the model could not start an object
+// stream, so the caller should generate the object in one call instead.
+var errObjectStreamUnavailable = errors.New("object streaming unavailable")
+
+// streamObject generates an object with StreamObject, sending each partial
+// object to w. It returns the final object.
+func streamObject(ctx context.Context, model fantasy.LanguageModel, call fantasy.ObjectCall, w StructuredOutputWriter, attempt int) (any, error) {
+	stream, err := model.StreamObject(ctx, call)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errObjectStreamUnavailable, err)
+	}
+	if stream == nil {
+		return nil, errObjectStreamUnavailable
+	}
+
+	var object any
+	var last string
+	for part := range stream {
+		switch part.Type {
+		case fantasy.ObjectStreamPartTypeObject:
+			if part.Object == nil {
+				continue
+			}
+			object = part.Object
+			data, err := json.MarshalIndent(part.Object, "", "  ")
+			if err != nil || string(data) == last {
+				continue
+			}
+			last = string(data)
+			w.WriteStructuredDelta(last, attempt, maxOutputCastRetries)
+		case fantasy.ObjectStreamPartTypeError:
+			return nil, part.Error
+		}
+	}
+
+	if object == nil {
+		return nil, fmt.Errorf("no object generated in stream")
+	}
+	return object, nil
+}
+
+// castSystemPrompt is the system prompt for casting agent output to s. It
+// spells out each field's description and allowed values, which models
+// otherwise tend to skim over in the schema, so fewer attempts fail
+// validation.
+func castSystemPrompt(s *schema.Schema) string {
+	var b strings.Builder
+	b.WriteString("You are a data extraction assistant. Extract and format the information from the provided content into the required JSON structure. Output only valid JSON matching the schema.")
+
+	var fields []string
+	describeFields(&fields, s, "")
+	if len(fields) > 0 {
+		b.WriteString("\n\nFill each field as its description says. Fields with allowed values must use exactly one of them.\n\nFields:\n")
+		b.WriteString(strings.Join(fields, "\n"))
+	}
+	return b.String()
+}
+
+// castSchemaDescription is the description sent with the output schema: the
+// schema's own description when it has one.
+func castSchemaDescription(s *schema.Schema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+	return "Required output format for the agent response"
+}
+
+// describeFields appends a line for each field under path in s. Array items
+// are listed as "path[]" when they are objects or have a description or
+// allowed values of their own.
+func describeFields(fields *[]string, s *schema.Schema, path string) {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := s.Properties[name]
+		if child == nil {
+			continue
+		}
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+		*fields = append(*fields, describeField(child, childPath, slices.Contains(s.Required, name)))
+		describeItems(fields, child, childPath)
+		describeFields(fields, child, childPath)
+	}
+}
+
+// describeItems describes the items of s, an array at path.
+func describeItems(fields *[]string, s *schema.Schema, path string) {
+	if s.Items == nil {
+		return
+	}
+	itemPath := path + "[]"
+	if s.Items.Description != "" || len(s.Items.Enum) > 0 {
+		*fields = append(*fields, describeField(s.Items, itemPath, false))
+	}
+	describeItems(fields, s.Items, itemPath)
+	describeFields(fields, s.Items, itemPath)
+}
+
+// describeField returns the prompt line for one field, such as
+// "- severity (string, required): How urgent the issue is; allowed values:
+// "low", "high"".
+func describeField(s *schema.Schema, path string, required bool) string {
+	var attrs []string
+	if s.Type != "" {
+		attrs = append(attrs, s.Type)
+	}
+	if s.Format != "" {
+		attrs = append(attrs, "format "+s.Format)
+	}
+	if required {
+		attrs = append(attrs, "required")
+	}
+
+	line := "- " + path
+	if len(attrs) > 0 {
+		line += " (" + strings.Join(attrs, ", ") + ")"
+	}
+
+	var details []string
+	if s.Description != "" {
+		details = append(details, strings.TrimSpace(s.Description))
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			data, err := json.Marshal(v)
+			if err != nil {
+				data = []byte(fmt.Sprint(v))
+			}
+			values[i] = string(data)
+		}
+		details = append(details, "allowed values: "+strings.Join(values, ", "))
+	}
+	if len(details) > 0 {
+		line += ": " + strings.Join(details, "; ")
+	}
+	return line
+}
@@ -0,0 +1,182 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+	"charm.land/fantasy/schema"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+)
+
+// objectModel is a fake model that streams the given partial objects, or
+// fails to stream when streamErr is set. Object calls are recorded in calls
+// when it is set.
+type objectModel struct {
+	partials  []any
+	streamErr error
+	generated any
+	calls     *[]fantasy.ObjectCall
+}
+
+func (m objectModel) Generate(context.Context, fantasy.Call) (*fantasy.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m objectModel) Stream(context.Context, fantasy.Call) (fantasy.StreamResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m objectModel) GenerateObject(_ context.Context, call fantasy.ObjectCall) (*fantasy.ObjectResponse, error) {
+	if m.calls != nil {
+		*m.calls = append(*m.calls, call)
+	}
+	return &fantasy.ObjectResponse{Object: m.generated}, nil
+}
+
+func (m objectModel) StreamObject(context.Context, fantasy.ObjectCall) (fantasy.ObjectStreamResponse, error) {
+	if m.streamErr != nil {
+		return nil, m.streamErr
+	}
+	return func(yield func(fantasy.ObjectStreamPart) bool) {
+		for _, p := range m.partials {
+			if !yield(fantasy.ObjectStreamPart{Type: fantasy.ObjectStreamPartTypeObject, Object: p}) {
+				return
+			}
+		}
+		yield(fantasy.ObjectStreamPart{Type: fantasy.ObjectStreamPartTypeFinish})
+	}, nil
+}
+
+func (m objectModel) Provider() string { return "fake" }
+func (m objectModel) Model() string    { return "fake" }
+
+type structuredRecorder struct {
+	NullWriter
+	deltas []string
+}
+
+func (w *structuredRecorder) WriteStructuredDelta(partial string, attempt, maxAttempts int) {
+	w.deltas = append(w.deltas, partial)
+}
+
+func structuredAgent() agent.Agent {
+	return agent.Agent{OutputSchema: &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{"title": {Type: "string"}},
+		Required:   []string{"title"},
+	}}
+}
+
+func TestCastToStructuredOutputStreamsPartials(t *testing.T) {
+	w := &structuredRecorder{}
+	r := &Runner{streamWriter: w}
+	model := objectModel{partials: []any{
+		map[string]any{},
+		map[string]any{"title": "Hel"},
+		map[string]any{"title": "Hello"},
+	}}
+
+	out, err := r.castToStructuredOutput(context.Background(), model, structuredAgent(), "Hello", nil)
+	if err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if !strings.Contains(out, `"title": "Hello"`) {
+		t.Errorf("output = %s", out)
+	}
+
+	// The attempt start, then each partial object; the empty object is
+	// not valid but is still shown
+	if len(w.deltas) != 4 || w.deltas[0] != "" || !strings.Contains(w.deltas[3], "Hello") {
+		t.Errorf("deltas = %q", w.deltas)
+	}
+}
+
+func TestCastToStructuredOutputFallsBackWithoutStreaming(t *testing.T) {
+	w := &structuredRecorder{}
+	r := &Runner{streamWriter: w}
+	model := objectModel{
+		streamErr: errors.New("streaming not supported"),
+		generated: map[string]any{"title": "Hello"},
+	}
+
+	out, err := r.castToStructuredOutput(context.Background(), model, structuredAgent(), "Hello", nil)
+	if err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if !strings.Contains(out, `"title": "Hello"`) {
+		t.Errorf("output = %s", out)
+	}
+	if len(w.deltas) != 1 || w.deltas[0] != "" {
+		t.Errorf("deltas = %q, want only the attempt start", w.deltas)
+	}
+}
+
+func TestCastToStructuredOutputSendsFieldGuide(t *testing.T) {
+	ag := agent.Agent{OutputSchema: &schema.Schema{
+		Type:        "object",
+		Description: "A code review",
+		Properties: map[string]*schema.Schema{
+			"summary": {Type: "string", Description: "One sentence on the overall change"},
+			"findings": {
+				Type: "array",
+				Items: &schema.Schema{
+					Type: "object",
+					Properties: map[string]*schema.Schema{
+						"severity": {Type: "string", Description: "How urgent the fix is", Enum: []any{"low", "high"}},
+						"line":     {Type: "integer"},
+					},
+					Required: []string{"severity"},
+				},
+			},
+		},
+		Required: []string{"summary"},
+	}}
+
+	var calls []fantasy.ObjectCall
+	r := &Runner{streamWriter: NullWriter{}}
+	model := objectModel{
+		generated: map[string]any{"summary": "Fine", "findings": []any{}},
+		calls:     &calls,
+	}
+	if _, err := r.castToStructuredOutput(context.Background(), model, ag, "Looks fine", nil); err != nil {
+		t.Fatalf("cast: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("calls = %d, want 1", len(calls))
+	}
+
+	call := calls[0]
+	if call.SchemaDescription != "A code review" {
+		t.Errorf("schema description = %q", call.SchemaDescription)
+	}
+	if got := call.Schema.Properties["summary"].Description; got != "One sentence on the overall change" {
+		t.Errorf("schema lost property description: %q", got)
+	}
+
+	system := call.Prompt[0].Content[0].(fantasy.TextPart).Text
+	for _, want := range []string{
+		"Fill each field as its description says",
+		"- findings (array)",
+		"- findings[].line (integer)",
+		`- findings[].severity (string, required): How urgent the fix is; allowed values: "low", "high"`,
+		"- summary (string, required): One sentence on the overall change",
+	} {
+		if !strings.Contains(system, want) {
+			t.Errorf("system prompt missing %q:\n%s", want, system)
+		}
+	}
+}
+
+func TestCastSystemPromptWithoutFields(t *testing.T) {
+	system := castSystemPrompt(&schema.Schema{Type: "string"})
+	if strings.Contains(system, "Fields:") {
+		t.Errorf("system prompt lists fields for a schema without any:\n%s", system)
+	}
+	if got := castSchemaDescription(&schema.Schema{}); got != "Required output format for the agent response" {
+		t.Errorf("default schema description = %q", got)
+	}
+}
@@ -0,0 +1,39 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/session"
+	"github.com/alexcabrera/ayo/internal/smallmodel"
+)
+
+// Summarizer returns a function that summarizes conversation transcripts
+// with modelID from the configured provider, using the small model's
+// summary prompt. Stored messages are kept as written, so secrets are masked
+// before the transcript leaves the process. Calls are throttled with the
+// runner's other calls.
+func (r *Runner) Summarizer(modelID string) (session.SummarizeFunc, error) {
+	if err := checkOffline(r.config, "the summary", modelID); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, transcript string) (string, error) {
+		model, err := NewLanguageModel(ctx, r.config.Provider, modelID)
+		if err != nil {
+			return "", err
+		}
+		model = r.rateLimiter.wrap(model, nil)
+
+		resp, err := model.Generate(ctx, fantasy.Call{
+			Prompt: fantasy.Prompt{fantasy.NewUserMessage(smallmodel.SummarizePrompt(r.redact("summary transcript", transcript)))},
+		})
+		if err != nil {
+			return "", fmt.Errorf("summarize conversation: %w", err)
+		}
+		r.recordUsage(ctx, modelID, resp.Usage)
+		return strings.TrimSpace(resp.Content.Text()), nil
+	}, nil
+}
@@ -0,0 +1,113 @@
+package run
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ToolCallDeltaHandler is implemented by stream handlers that show a tool
+// call's arguments while the model is still writing them. Providers that
+// don't stream tool arguments never call it; the tool call is then shown
+// only when it is complete, through OnToolCall.
+type ToolCallDeltaHandler interface {
+	// OnToolCallDelta is called when a tool call starts, with empty input,
+	// and each time its arguments grow, with the arguments so far. They
+	// are usually not valid JSON until the call is complete.
+	OnToolCallDelta(id, name, input string) error
+}
+
+// ToolInputWriter is implemented by stream writers that display tool
+// arguments as they stream.
+type ToolInputWriter interface {
+	// WriteToolInput is called with the tool call so far. Input holds the
+	// partial arguments; Command and Description are filled in for bash
+	// as far as they have arrived.
+	WriteToolInput(call ToolCall)
+}
+
+// streamingToolCalls accumulates streamed tool arguments by tool call ID.
+type streamingToolCalls map[string]*streamingToolCall
+
+type streamingToolCall struct {
+	name  string
+	input strings.Builder
+}
+
+// start records a new tool call.
+func (s streamingToolCalls) start(id, name string) {
+	s[id] = &streamingToolCall{name: name}
+}
+
+// add appends delta to a tool call's arguments and returns the call's name
+// and arguments so far. ok is false for a call that was never started.
+func (s streamingToolCalls) add(id, delta string) (name, input string, ok bool) {
+	tc, ok := s[id]
+	if !ok {
+		return "", "", false
+	}
+	tc.input.WriteString(delta)
+	return tc.name, tc.input.String(), true
+}
+
+// partialJSONString returns the value of the string field key in a JSON
+// object that may be cut off, such as streamed tool arguments. A value that
+// has not finished arriving is returned as far as it goes; an absent key
+// returns "".
+func partialJSONString(input, key string) string {
+	quoted := strconv.Quote(key)
+	i := strings.Index(input, quoted)
+	if i < 0 {
+		return ""
+	}
+	rest := strings.TrimLeft(input[i+len(quoted):], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return ""
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, `"`) {
+		return ""
+	}
+	rest = rest[1:]
+
+	var b strings.Builder
+	for len(rest) > 0 {
+		c := rest[0]
+		switch {
+		case c == '"':
+			return b.String()
+		case c != '\\':
+			b.WriteByte(c)
+			rest = rest[1:]
+			continue
+		case len(rest) < 2:
+			// An escape cut off mid-way
+			return b.String()
+		}
+		switch rest[1] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if len(rest) < 6 {
+				return b.String()
+			}
+			if r, err := strconv.ParseUint(rest[2:6], 16, 32); err == nil {
+				b.WriteRune(rune(r))
+			}
+			rest = rest[6:]
+			continue
+		default:
+			// \" \\ and \/
+			b.WriteByte(rest[1])
+		}
+		rest = rest[2:]
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+func TestPartialJSONString(t *testing.T) {
+	tests := []struct {
+		input, key, want string
+	}{
+		{`{"command": "ls -la", "description": "List"}`, "command", "ls -la"},
+		{`{"command": "echo \"hi\"\nls`, "command", "echo \"hi\"\nls"},
+		{`{"command":"café`, "command", "café"},
+		{`{"command":"a\u00`, "command", "a"},
+		{`{"command":"a\`, "command", "a"},
+		{`{"command":`, "command", ""},
+		{`{"description": "List files", "comm`, "command", ""},
+		{`{"timeout": 30}`, "command", ""},
+	}
+	for _, tt := range tests {
+		if got := partialJSONString(tt.input, tt.key); got != tt.want {
+			t.Errorf("partialJSONString(%q, %q) = %q, want %q", tt.input, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestChatStreamsToolInput(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+		send := func(delta map[string]any, finish any) {
+			chunk, _ := json.Marshal(map[string]any{
+				"id": "c1", "object": "chat.completion.chunk", "created": 1, "model": "test",
+				"choices": []map[string]any{{"index": 0, "delta": delta, "finish_reason": finish}},
+			})
+			io.WriteString(w, "data: "+string(chunk)+"\n\n")
+		}
+		if requests > 1 {
+			send(map[string]any{"role": "assistant", "content": "done"}, "stop")
+			io.WriteString(w, "data: [DONE]\n\n")
+			return
+		}
+		toolDelta := func(fn map[string]any, id string) map[string]any {
+			call := map[string]any{"index": 0, "function": fn}
+			if id != "" {
+				call["id"], call["type"] = id, "function"
+			}
+			return map[string]any{"role": "assistant", "tool_calls": []map[string]any{call}}
+		}
+		send(toolDelta(map[string]any{"name": "bash", "arguments": ""}, "call_1"), nil)
+		send(toolDelta(map[string]any{"arguments": `{"command": "ls `}, ""), nil)
+		send(toolDelta(map[string]any{"arguments": `-la"}`}, ""), nil)
+		send(map[string]any{}, "tool_calls")
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	events := make(chan StreamEvent, 100)
+	cfg := config.Config{Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: server.URL, APIKey: "key"}}
+	r, err := NewRunner(cfg, false, RunnerOptions{StreamWriter: NewChannelWriter(events)})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	ag := agent.Agent{Handle: "@ayo", Model: "test", BuiltIn: true}
+	if _, err := r.Chat(context.Background(), ag, "list files"); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	close(events)
+
+	var commands []string
+	sawStart := false
+	for e := range events {
+		switch e.Type {
+		case EventToolInput:
+			if sawStart {
+				t.Error("tool input streamed after the call was complete")
+			}
+			commands = append(commands, e.Call.Command)
+		case EventToolStart:
+			sawStart = true
+		}
+	}
+	if len(commands) == 0 || commands[len(commands)-1] != "ls -la" {
+		t.Errorf("streamed commands = %q, want them to grow to %q", commands, "ls -la")
+	}
+	if !sawStart {
+		t.Error("the complete tool call should still be reported")
+	}
+}
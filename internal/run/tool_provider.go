@@ -0,0 +1,74 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/tools"
+)
+
+// ToolProvider supplies tools of an application that embeds the runner,
+// alongside the built-in and plugin tools. A provided tool is offered to an
+// agent only when its name is in the agent's allowed_tools.
+type ToolProvider interface {
+	// Names lists every tool the provider can return. They are checked
+	// against the built-in tools when the runner is created.
+	Names() []string
+
+	// Tools returns the tools for a turn of ag. baseDir is the directory
+	// the built-in tools run in.
+	Tools(ag agent.Agent, baseDir string) []fantasy.AgentTool
+}
+
+// BuiltinToolNames returns the names of the tools that ship with ayo.
+func BuiltinToolNames() []string {
+	return []string{"bash", "todo", "memory", HTTPToolName, "agent_call"}
+}
+
+// checkToolProvider reports provided tool names that collide with a
+// built-in tool or category, or with each other.
+func checkToolProvider(p ToolProvider) error {
+	builtins := BuiltinToolNames()
+	seen := make(map[string]bool)
+	for _, name := range p.Names() {
+		switch {
+		case name == "":
+			return fmt.Errorf("tool provider: empty tool name")
+		case slices.Contains(builtins, name):
+			return fmt.Errorf("tool provider: %q collides with a built-in tool", name)
+		case tools.IsCategory(name):
+			return fmt.Errorf("tool provider: %q collides with a built-in tool category", name)
+		case seen[name]:
+			return fmt.Errorf("tool provider: %q is provided twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// addProvidedTools adds the provider's tools for ag to ts. Tools that are
+// not allowed, not declared in Names, or already loaded (e.g. a plugin tool
+// of the same name) are left out.
+func (r *Runner) addProvidedTools(ts *FantasyToolSet, ag agent.Agent, baseDir string) {
+	if r.toolProvider == nil {
+		return
+	}
+	declared := r.toolProvider.Names()
+	for _, t := range r.toolProvider.Tools(ag, baseDir) {
+		name := t.Info().Name
+		if !ts.HasTool(name) || !slices.Contains(declared, name) {
+			continue
+		}
+		if ts.Loaded(name) {
+			if r.debug {
+				fmt.Fprintf(os.Stderr, "DEBUG: provided tool %q skipped: a tool of that name is already loaded\n", name)
+			}
+			continue
+		}
+		ts.tools = append(ts.tools, t)
+	}
+}
@@ -0,0 +1,72 @@
+package run
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+)
+
+type testToolProvider struct {
+	names []string
+	tools []string
+}
+
+func (p testToolProvider) Names() []string { return p.names }
+
+func (p testToolProvider) Tools(ag agent.Agent, baseDir string) []fantasy.AgentTool {
+	var out []fantasy.AgentTool
+	for _, name := range p.tools {
+		out = append(out, fantasy.NewAgentTool(name, "test tool",
+			func(ctx context.Context, params struct{}, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+				return fantasy.NewTextResponse("ok"), nil
+			}))
+	}
+	return out
+}
+
+func TestNewRunnerToolProviderCollisions(t *testing.T) {
+	tests := []struct {
+		names   []string
+		wantErr string
+	}{
+		{[]string{"jira", "billing"}, ""},
+		{[]string{"bash"}, `"bash" collides with a built-in tool`},
+		{[]string{"planning"}, `"planning" collides with a built-in tool category`},
+		{[]string{"jira", "jira"}, `"jira" is provided twice`},
+	}
+	for _, tt := range tests {
+		_, err := NewRunner(config.Config{}, false, RunnerOptions{ToolProvider: testToolProvider{names: tt.names}})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("NewRunner(%v) error = %v", tt.names, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewRunner(%v) error = %v, want %q", tt.names, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAddProvidedTools(t *testing.T) {
+	r := &Runner{toolProvider: testToolProvider{
+		names: []string{"jira", "billing"},
+		tools: []string{"jira", "billing", "undeclared"},
+	}}
+	ag := agent.Agent{Config: agent.Config{AllowedTools: []string{"bash", "jira", "undeclared"}}}
+	ts := NewFantasyToolSetWithOptions(ag.Config.AllowedTools, t.TempDir(), nil, 0)
+	r.addProvidedTools(&ts, ag, t.TempDir())
+
+	var names []string
+	for _, tool := range ts.Tools() {
+		names = append(names, tool.Info().Name)
+	}
+	if got := strings.Join(names, ","); got != "bash,jira" {
+		t.Errorf("tools = %s, want bash,jira (billing is not allowed, undeclared is not declared)", got)
+	}
+}
@@ -0,0 +1,228 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"charm.land/fantasy"
+)
+
+// ToolArgumentError describes tool call arguments that don't match the
+// tool's input schema.
+type ToolArgumentError struct {
+	Tool    string
+	Field   string // Empty when the arguments as a whole are malformed
+	Problem string
+}
+
+func (e *ToolArgumentError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid arguments for %s: %s", e.Tool, e.Problem)
+	}
+	return fmt.Sprintf("invalid arguments for %s: field %q: %s", e.Tool, e.Field, e.Problem)
+}
+
+// validateToolArguments checks raw tool call arguments against the tool's
+// declared parameters. It reports the first problem found, checking JSON
+// syntax, required fields, unknown fields, types, and enum values in that order.
+func validateToolArguments(info fantasy.ToolInfo, input string) *ToolArgumentError {
+	argErr := func(field, format string, a ...any) *ToolArgumentError {
+		return &ToolArgumentError{Tool: info.Name, Field: field, Problem: fmt.Sprintf(format, a...)}
+	}
+
+	if strings.TrimSpace(input) == "" {
+		input = "{}"
+	}
+
+	var raw any
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return argErr("", "malformed JSON at byte %d: %v", syntaxErr.Offset, err)
+		}
+		return argErr("", "malformed JSON: %v", err)
+	}
+	if dec.More() {
+		return argErr("", "malformed JSON: unexpected data after the arguments object")
+	}
+
+	args, ok := raw.(map[string]any)
+	if !ok {
+		return argErr("", "arguments must be a JSON object, got %s", jsonTypeName(raw))
+	}
+
+	for _, name := range info.Required {
+		if _, exists := args[name]; !exists {
+			return argErr(name, "required field is missing")
+		}
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, known := info.Parameters[name]
+		if !known {
+			return argErr(name, "unknown field")
+		}
+		schema, _ := prop.(map[string]any)
+		if schema == nil {
+			continue
+		}
+
+		value := args[name]
+		// Some models send null for optional fields they don't use
+		if value == nil && !slices.Contains(info.Required, name) {
+			continue
+		}
+		if want, _ := schema["type"].(string); want != "" && !jsonTypeMatches(want, value) {
+			return argErr(name, "expected %s, got %s", want, jsonTypeName(value))
+		}
+		if allowed := enumValues(schema["enum"]); len(allowed) > 0 {
+			if s, isString := value.(string); isString && !slices.Contains(allowed, s) {
+				return argErr(name, "must be one of %s, got %q", strings.Join(allowed, ", "), s)
+			}
+		}
+	}
+
+	return nil
+}
+
+// describeToolArguments summarizes the tool's parameters for error messages,
+// e.g. "command (string, required), timeout_seconds (integer)".
+func describeToolArguments(info fantasy.ToolInfo) string {
+	names := make([]string, 0, len(info.Parameters))
+	for name := range info.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		typ := "any"
+		if schema, ok := info.Parameters[name].(map[string]any); ok {
+			if t, _ := schema["type"].(string); t != "" {
+				typ = t
+			}
+		}
+		if slices.Contains(info.Required, name) {
+			parts = append(parts, fmt.Sprintf("%s (%s, required)", name, typ))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s (%s)", name, typ))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// jsonTypeMatches reports whether a decoded JSON value has the given schema type.
+// Values must be decoded with UseNumber so integers can be told apart.
+func jsonTypeMatches(want string, value any) bool {
+	switch want {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// jsonTypeName names the JSON type of a decoded value.
+func jsonTypeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumValues extracts string enum values from a schema property.
+func enumValues(v any) []string {
+	switch e := v.(type) {
+	case []string:
+		return e
+	case []any:
+		out := make([]string, 0, len(e))
+		for _, item := range e {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// validatingTool wraps a tool so arguments are checked against its schema
+// before it runs. Fantasy only verifies that arguments parse and that required
+// fields exist; wrong types and unknown fields otherwise reach the tool as
+// opaque unmarshal errors or are silently dropped.
+type validatingTool struct {
+	fantasy.AgentTool
+	onInvalid func(*ToolArgumentError)
+}
+
+// withArgumentValidation wraps each tool with argument validation.
+// onInvalid, if non-nil, is called for every rejected tool call.
+func withArgumentValidation(tools []fantasy.AgentTool, onInvalid func(*ToolArgumentError)) []fantasy.AgentTool {
+	wrapped := make([]fantasy.AgentTool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = &validatingTool{AgentTool: t, onInvalid: onInvalid}
+	}
+	return wrapped
+}
+
+func (v *validatingTool) Run(ctx context.Context, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+	info := v.Info()
+	if argErr := validateToolArguments(info, call.Input); argErr != nil {
+		if v.onInvalid != nil {
+			v.onInvalid(argErr)
+		}
+		return fantasy.NewTextErrorResponse(fmt.Sprintf("%s\nExpected arguments: %s\nFix the arguments and call %s again.",
+			argErr.Error(), describeToolArguments(info), info.Name)), nil
+	}
+	return v.AgentTool.Run(ctx, call)
+}
@@ -0,0 +1,174 @@
+package run
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+)
+
+func TestValidateToolArgumentsBash(t *testing.T) {
+	info := NewBashTool(t.TempDir()).Info()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantField   string
+		wantProblem string
+	}{
+		{
+			name:        "truncated JSON",
+			input:       `{"command": "ls", "description": "List"`,
+			wantProblem: "malformed JSON",
+		},
+		{
+			name:        "trailing garbage",
+			input:       `{"command": "ls", "description": "List"} extra`,
+			wantProblem: "unexpected data",
+		},
+		{
+			name:        "array instead of object",
+			input:       `["ls"]`,
+			wantProblem: "must be a JSON object, got array",
+		},
+		{
+			name:        "missing command",
+			input:       `{"description": "List files"}`,
+			wantField:   "command",
+			wantProblem: "required field is missing",
+		},
+		{
+			name:        "command is a number",
+			input:       `{"command": 42, "description": "List"}`,
+			wantField:   "command",
+			wantProblem: "expected string, got integer",
+		},
+		{
+			name:        "command is an array",
+			input:       `{"command": ["ls", "-la"], "description": "List"}`,
+			wantField:   "command",
+			wantProblem: "expected string, got array",
+		},
+		{
+			name:        "null command",
+			input:       `{"command": null, "description": "List"}`,
+			wantField:   "command",
+			wantProblem: "expected string, got null",
+		},
+		{
+			name:        "timeout as string",
+			input:       `{"command": "ls", "description": "List", "timeout_seconds": "30"}`,
+			wantField:   "timeout_seconds",
+			wantProblem: "expected integer, got string",
+		},
+		{
+			name:        "fractional timeout",
+			input:       `{"command": "ls", "description": "List", "timeout_seconds": 1.5}`,
+			wantField:   "timeout_seconds",
+			wantProblem: "expected integer, got number",
+		},
+		{
+			name:        "misspelled field",
+			input:       `{"command": "ls", "description": "List", "workdir": "/tmp"}`,
+			wantField:   "workdir",
+			wantProblem: "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argErr := validateToolArguments(info, tt.input)
+			if argErr == nil {
+				t.Fatal("expected validation error")
+			}
+			if argErr.Tool != "bash" {
+				t.Errorf("Tool = %q, want bash", argErr.Tool)
+			}
+			if argErr.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", argErr.Field, tt.wantField)
+			}
+			if !strings.Contains(argErr.Problem, tt.wantProblem) {
+				t.Errorf("Problem = %q, want it to contain %q", argErr.Problem, tt.wantProblem)
+			}
+		})
+	}
+}
+
+func TestValidateToolArgumentsValid(t *testing.T) {
+	info := NewBashTool(t.TempDir()).Info()
+
+	inputs := []string{
+		`{"command": "ls", "description": "List"}`,
+		`{"command": "ls", "description": "List", "timeout_seconds": 10, "working_dir": "."}`,
+		`{"command": "ls", "description": "List", "working_dir": null}`,
+	}
+	for _, input := range inputs {
+		if argErr := validateToolArguments(info, input); argErr != nil {
+			t.Errorf("validateToolArguments(%s) = %v, want nil", input, argErr)
+		}
+	}
+}
+
+func TestValidateToolArgumentsEnum(t *testing.T) {
+	info := fantasy.ToolInfo{
+		Name: "memory",
+		Parameters: map[string]any{
+			"operation": map[string]any{"type": "string", "enum": []any{"search", "store"}},
+		},
+		Required: []string{"operation"},
+	}
+
+	argErr := validateToolArguments(info, `{"operation": "delete"}`)
+	if argErr == nil || argErr.Field != "operation" || !strings.Contains(argErr.Problem, "search, store") {
+		t.Errorf("expected enum error for operation, got %v", argErr)
+	}
+}
+
+func TestValidatingToolRejectsMalformedBashArguments(t *testing.T) {
+	var recorded []*ToolArgumentError
+	tools := withArgumentValidation([]fantasy.AgentTool{NewBashTool(t.TempDir())}, func(e *ToolArgumentError) {
+		recorded = append(recorded, e)
+	})
+	bash := tools[0]
+
+	resp, err := bash.Run(context.Background(), fantasy.ToolCall{
+		ID:    "call-1",
+		Name:  "bash",
+		Input: `{"command": 42, "description": "Broken"}`,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if !resp.IsError {
+		t.Fatal("expected error response")
+	}
+	if !strings.Contains(resp.Content, `field "command": expected string, got integer`) {
+		t.Errorf("response should name the field and problem, got %q", resp.Content)
+	}
+	if !strings.Contains(resp.Content, "command (string, required)") {
+		t.Errorf("response should describe expected arguments, got %q", resp.Content)
+	}
+	if len(recorded) != 1 || recorded[0].Field != "command" {
+		t.Errorf("expected one recorded error for command, got %v", recorded)
+	}
+
+	// Valid arguments pass through to the tool
+	resp, err = bash.Run(context.Background(), fantasy.ToolCall{
+		ID:    "call-2",
+		Name:  "bash",
+		Input: `{"command": "echo hello", "description": "Say hello"}`,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if resp.IsError {
+		t.Errorf("unexpected error response: %q", resp.Content)
+	}
+	if !strings.Contains(resp.Content, "hello") {
+		t.Errorf("expected command output, got %q", resp.Content)
+	}
+	if len(recorded) != 1 {
+		t.Errorf("valid call should not be recorded, got %d errors", len(recorded))
+	}
+}
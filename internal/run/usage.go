@@ -0,0 +1,91 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+)
+
+// Usage is the token usage and estimated cost accumulated by a runner.
+type Usage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	CostUSD          float64 // 0 when the provider publishes no pricing
+}
+
+// UsageWriter is implemented by stream writers that display usage.
+// The runner calls WriteUsage with the running totals after each step.
+type UsageWriter interface {
+	WriteUsage(usage Usage)
+}
+
+// usageTracker accumulates usage across turns.
+type usageTracker struct {
+	mu    sync.Mutex
+	total Usage
+}
+
+// add records one step's usage and returns the new totals.
+func (t *usageTracker) add(u Usage) Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total.PromptTokens += u.PromptTokens
+	t.total.CompletionTokens += u.CompletionTokens
+	t.total.CostUSD += u.CostUSD
+	return t.total
+}
+
+func (t *usageTracker) get() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Usage returns the tokens and estimated cost of every turn run so far.
+func (r *Runner) Usage() Usage {
+	return r.usage.get()
+}
+
+// recordUsage adds a step's usage to the running totals, sends them to the
+// stream writer if it displays usage, and saves the step's usage to the
+// session in ctx.
+func (r *Runner) recordUsage(ctx context.Context, modelID string, u fantasy.Usage) {
+	step := stepUsage(r.config.Provider, modelID, u)
+	total := r.usage.add(step)
+	if w, ok := r.streamWriter.(UsageWriter); ok {
+		w.WriteUsage(total)
+	}
+
+	sessionID := GetSessionIDFromContext(ctx)
+	if r.services == nil || sessionID == "" {
+		return
+	}
+	if err := r.services.Sessions.AddUsage(ctx, sessionID, modelID, step.PromptTokens, step.CompletionTokens, step.CostUSD); err != nil && r.debug {
+		fmt.Fprintf(os.Stderr, "DEBUG: save usage: %v\n", err)
+	}
+}
+
+// stepUsage prices a step's usage with the provider's published rates.
+// Catwalk lists cache writes under CostPer1MInCached and cache reads under
+// CostPer1MOutCached.
+func stepUsage(p catwalk.Provider, modelID string, u fantasy.Usage) Usage {
+	usage := Usage{
+		PromptTokens:     u.InputTokens + u.CacheCreationTokens + u.CacheReadTokens,
+		CompletionTokens: u.OutputTokens,
+	}
+	for _, m := range p.Models {
+		if m.ID != modelID {
+			continue
+		}
+		usage.CostUSD = (float64(u.InputTokens)*m.CostPer1MIn +
+			float64(u.OutputTokens)*m.CostPer1MOut +
+			float64(u.CacheCreationTokens)*m.CostPer1MInCached +
+			float64(u.CacheReadTokens)*m.CostPer1MOutCached) / 1_000_000
+		break
+	}
+	return usage
+}
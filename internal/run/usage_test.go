@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/session"
+)
+
+func TestStepUsageCost(t *testing.T) {
+	p := catwalk.Provider{Models: []catwalk.Model{
+		{ID: "other", CostPer1MIn: 100},
+		{ID: "model", CostPer1MIn: 3, CostPer1MOut: 15, CostPer1MInCached: 3.75, CostPer1MOutCached: 0.3},
+	}}
+
+	got := stepUsage(p, "model", fantasy.Usage{
+		InputTokens:         1_000_000,
+		OutputTokens:        100_000,
+		CacheCreationTokens: 200_000,
+		CacheReadTokens:     500_000,
+	})
+
+	if got.PromptTokens != 1_700_000 || got.CompletionTokens != 100_000 {
+		t.Errorf("tokens = %d/%d, want 1700000/100000", got.PromptTokens, got.CompletionTokens)
+	}
+	// 3 + 1.5 + 0.75 + 0.15
+	if math.Abs(got.CostUSD-5.4) > 1e-9 {
+		t.Errorf("CostUSD = %v, want 5.4", got.CostUSD)
+	}
+}
+
+func TestStepUsageUnknownModel(t *testing.T) {
+	got := stepUsage(catwalk.Provider{}, "model", fantasy.Usage{InputTokens: 10, OutputTokens: 5})
+	if got.PromptTokens != 10 || got.CompletionTokens != 5 || got.CostUSD != 0 {
+		t.Errorf("stepUsage = %+v, want tokens without cost", got)
+	}
+}
+
+func TestRecordUsageAccumulatesAndEmits(t *testing.T) {
+	events := make(chan StreamEvent, 2)
+	r := &Runner{streamWriter: NewChannelWriter(events)}
+
+	r.recordUsage(context.Background(), "model", fantasy.Usage{InputTokens: 10, OutputTokens: 5})
+	r.recordUsage(context.Background(), "model", fantasy.Usage{InputTokens: 20, OutputTokens: 7})
+
+	if u := r.Usage(); u.PromptTokens != 30 || u.CompletionTokens != 12 {
+		t.Errorf("Usage() = %+v, want 30/12", u)
+	}
+	<-events
+	last := <-events
+	if last.Type != EventUsage || last.Usage == nil || last.Usage.PromptTokens != 30 {
+		t.Errorf("expected running totals in usage event, got %+v", last)
+	}
+}
+
+func TestRecordUsageSavesToSession(t *testing.T) {
+	ctx := context.Background()
+	services, err := session.Connect(ctx, filepath.Join(t.TempDir(), "ayo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer services.Close()
+	sess, err := services.Sessions.Create(ctx, session.CreateParams{AgentHandle: "@ayo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Runner{services: services}
+	stepCtx := WithSessionID(ctx, sess.ID)
+	r.recordUsage(stepCtx, "model", fantasy.Usage{InputTokens: 10, OutputTokens: 5})
+	r.recordUsage(stepCtx, "model", fantasy.Usage{InputTokens: 20, OutputTokens: 7})
+
+	report, err := services.Sessions.UsageReport(ctx, session.UsageFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Models) != 1 || report.Models[0].Model != "model" || report.Models[0].PromptTokens != 30 || report.Models[0].CompletionTokens != 12 {
+		t.Errorf("saved usage = %+v, want 30/12 on model", report.Models)
+	}
+}
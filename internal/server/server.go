@@ -0,0 +1,456 @@
+// Package server exposes agents and memories over a local HTTP API, for
+// `ayo serve`.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/agent"
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// DefaultAddr is the address the server listens on unless configured.
+const DefaultAddr = "127.0.0.1:7878"
+
+// DefaultTimeout bounds each agent run unless configured.
+const DefaultTimeout = 5 * time.Minute
+
+// drainTimeout bounds how long a finished run's background work, such as
+// title generation and memory formation, may continue.
+const drainTimeout = 30 * time.Second
+
+// maxRequestBytes caps the body of a run request.
+const maxRequestBytes = 1 << 20
+
+// Options configures a Server.
+type Options struct {
+	Config  config.Config
+	Token   string        // Bearer token every request must carry; empty = no auth
+	Timeout time.Duration // Bounds each run; 0 = DefaultTimeout
+
+	// AnyHost accepts requests whatever their Host header, for a server
+	// listening beyond this machine. Otherwise only localhost and loopback
+	// hosts are accepted, so a DNS-rebound page can't reach the server.
+	AnyHost bool
+
+	// Dir is the directory attachments must be in; "" = the working
+	// directory.
+	Dir string
+
+	// NewRunner creates the runner for one run, streaming its output to w.
+	// Each run gets its own runner so concurrent runs stream separately.
+	NewRunner func(w run.StreamWriter) (*run.Runner, error)
+
+	Memory *memory.Service // nil = the memory endpoints are unavailable
+}
+
+// Server handles the HTTP API:
+//
+//	GET  /agents                   List agents
+//	POST /agents/{handle}/run      Run an agent once; streams with Accept: text/event-stream
+//	GET  /memories                 List memories (?agent=, ?limit=)
+//	GET  /memories/search?q=       Search memories (?agent=, ?limit=)
+type Server struct {
+	opts     Options
+	mux      *http.ServeMux
+	draining sync.WaitGroup // Background work of finished runs
+}
+
+// New creates a server.
+func New(opts Options) *Server {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /agents", s.handleAgents)
+	s.mux.HandleFunc("POST /agents/{handle}/run", s.handleRun)
+	s.mux.HandleFunc("GET /memories", s.handleMemories)
+	s.mux.HandleFunc("GET /memories/search", s.handleMemorySearch)
+	return s
+}
+
+// ServeHTTP refuses browser requests from other sites and unexpected hosts,
+// checks the bearer token, then routes the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") != "" {
+		writeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+		return
+	}
+	if !s.opts.AnyHost && !isLoopbackHost(hostOnly(r.Host)) {
+		writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+		return
+	}
+	if s.opts.Token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Wait waits up to timeout for the background work of finished runs.
+func (s *Server) Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.draining.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// CheckAddr refuses to listen beyond this machine without a token.
+func CheckAddr(addr, token string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if token != "" || isLoopbackHost(host) {
+		return nil
+	}
+	return fmt.Errorf("refusing to listen on %s without a token: set serve.token or AYO_SERVE_TOKEN", addr)
+}
+
+// IsLoopback reports whether addr only listens on this machine.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && isLoopbackHost(host)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hostOnly strips the port from a Host header.
+func hostOnly(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+}
+
+// agentInfo describes an agent in GET /agents.
+type agentInfo struct {
+	Handle      string `json:"handle"`
+	Description string `json:"description,omitempty"`
+	Model       string `json:"model,omitempty"`
+	BuiltIn     bool   `json:"builtin"`
+	Error       string `json:"error,omitempty"` // Why the agent failed to load
+}
+
+func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
+	handles, err := agent.ListHandles(s.opts.Config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	agents := make([]agentInfo, 0, len(handles))
+	for _, h := range handles {
+		info := agentInfo{Handle: h}
+		if ag, err := agent.Load(s.opts.Config, h); err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Description = ag.Config.Description
+			info.Model = ag.Model
+			info.BuiltIn = ag.BuiltIn
+		}
+		agents = append(agents, info)
+	}
+	writeJSON(w, http.StatusOK, agents)
+}
+
+// runRequest is the body of POST /agents/{handle}/run.
+type runRequest struct {
+	Prompt      string   `json:"prompt"`
+	Attachments []string `json:"attachments,omitempty"` // Paths in the server's directory
+}
+
+// runResponse is the result of a run, and the data of the final "done"
+// event when streaming.
+type runResponse struct {
+	Response  string `json:"response"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	handle := agent.NormalizeHandle(r.PathValue("handle"))
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("request body must be sent with Content-Type: application/json"))
+		return
+	}
+	var req runRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if strings.TrimSpace(req.Prompt) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("prompt is required"))
+		return
+	}
+	attachments, err := s.resolveAttachments(req.Attachments)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	handles, err := agent.ListHandles(s.opts.Config)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !slices.Contains(handles, handle) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("agent not found: %s", handle))
+		return
+	}
+	ag, err := agent.Load(s.opts.Config, handle)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := ag.ValidateInput(req.Prompt); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var sse *sseWriter
+	var writer run.StreamWriter = run.NullWriter{}
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		sse = newSSEWriter(w)
+		writer = sse
+	}
+	runner, err := s.opts.NewRunner(writer)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer s.drain(runner)
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.Timeout)
+	defer cancel()
+	result, err := runner.TextWithSession(ctx, ag, req.Prompt, attachments)
+	timedOut := err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if timedOut {
+		err = fmt.Errorf("run timed out after %s", s.opts.Timeout)
+	}
+
+	if sse != nil {
+		if err != nil {
+			sse.send("error", errorResponse{Error: err.Error()})
+		} else {
+			sse.send("done", runResponse{Response: result.Response, SessionID: result.SessionID})
+		}
+		sse.close()
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if timedOut {
+			status = http.StatusGatewayTimeout
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, runResponse{Response: result.Response, SessionID: result.SessionID})
+}
+
+// resolveAttachments resolves attachment paths against the server's
+// directory, following symlinks, and refuses any outside it.
+func (s *Server) resolveAttachments(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	dir := s.opts.Dir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		real, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %q: %w", paths[i], err)
+		}
+		rel, err := filepath.Rel(root, real)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("attachment %q is outside the server's directory", paths[i])
+		}
+		resolved[i] = real
+	}
+	return resolved, nil
+}
+
+// drain lets a finished run's background work, such as title generation
+// and memory formation, continue after its response is sent.
+func (s *Server) drain(runner *run.Runner) {
+	s.draining.Add(1)
+	go func() {
+		defer s.draining.Done()
+		runner.Shutdown(true, drainTimeout)
+	}()
+}
+
+// memoryInfo describes a memory in the memory endpoints, with the fields
+// of `ayo memory list --json`.
+type memoryInfo struct {
+	ID          string    `json:"id"`
+	AgentHandle string    `json:"agent_handle,omitempty"`
+	PathScope   string    `json:"path_scope,omitempty"`
+	Content     string    `json:"content"`
+	Category    string    `json:"category"`
+	Status      string    `json:"status"`
+	Confidence  float64   `json:"confidence"`
+	AccessCount int64     `json:"access_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// searchResult is a memory found by GET /memories/search.
+type searchResult struct {
+	Memory     memoryInfo `json:"memory"`
+	Similarity float32    `json:"similarity"`
+	Score      float32    `json:"score"`
+	Mode       string     `json:"mode"`
+}
+
+func newMemoryInfo(m memory.Memory) memoryInfo {
+	return memoryInfo{
+		ID:          m.ID,
+		AgentHandle: m.AgentHandle,
+		PathScope:   m.PathScope,
+		Content:     m.Content,
+		Category:    string(m.Category),
+		Status:      string(m.Status),
+		Confidence:  m.Confidence,
+		AccessCount: m.AccessCount,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (s *Server) handleMemories(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Memory == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("memory is unavailable"))
+		return
+	}
+	limit, err := limitParam(r, 50)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var agentHandle string
+	if a := r.URL.Query().Get("agent"); a != "" {
+		agentHandle = agent.NormalizeHandle(a)
+	}
+	memories, err := s.opts.Memory.List(r.Context(), agentHandle, int64(limit), 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	infos := make([]memoryInfo, len(memories))
+	for i, m := range memories {
+		infos[i] = newMemoryInfo(m)
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *Server) handleMemorySearch(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Memory == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("memory is unavailable"))
+		return
+	}
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+	limit, err := limitParam(r, 10)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var agentHandle string
+	if a := r.URL.Query().Get("agent"); a != "" {
+		agentHandle = agent.NormalizeHandle(a)
+	}
+	results, err := s.opts.Memory.Search(r.Context(), query, memory.SearchOptions{
+		AgentHandle: agentHandle,
+		Limit:       limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	found := make([]searchResult, len(results))
+	for i, res := range results {
+		found[i] = searchResult{
+			Memory:     newMemoryInfo(res.Memory),
+			Similarity: res.Similarity,
+			Score:      res.Score,
+			Mode:       string(res.Mode),
+		}
+	}
+	writeJSON(w, http.StatusOK, found)
+}
+
+// limitParam parses the limit query parameter.
+func limitParam(r *http.Request, def int) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("limit must be a positive integer, got %q", v)
+	}
+	return n, nil
+}
+
+// errorResponse is the body of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
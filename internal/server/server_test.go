@@ -0,0 +1,434 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/catwalk/pkg/catwalk"
+
+	"github.com/alexcabrera/ayo/internal/config"
+	"github.com/alexcabrera/ayo/internal/db"
+	"github.com/alexcabrera/ayo/internal/memory"
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// completionServer fakes an OpenAI-compatible endpoint that streams reply.
+func completionServer(t *testing.T, reply string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []map[string]any{
+			{"choices": []map[string]any{{"index": 0, "delta": map[string]string{"role": "assistant", "content": reply}}}},
+			{"choices": []map[string]any{{"index": 0, "delta": map[string]string{}, "finish_reason": "stop"}}},
+		}
+		for _, chunk := range chunks {
+			chunk["id"], chunk["object"], chunk["created"], chunk["model"] = "c1", "chat.completion.chunk", 1, "test"
+			data, _ := json.Marshal(chunk)
+			io.WriteString(w, "data: "+string(data)+"\n\n")
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestServer serves @alice, whose replies come from a fake endpoint.
+func newTestServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(home)
+
+	completions := completionServer(t, "hello there")
+	cfg := config.Config{
+		AgentsDir: filepath.Join(home, "agents"),
+		Provider:  catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: completions.URL, APIKey: "key"},
+	}
+	agentDir := filepath.Join(cfg.AgentsDir, "@alice")
+	if err := os.MkdirAll(agentDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "system.md"), []byte("You are Alice."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agentDir, "config.json"), []byte(`{"model": "test", "description": "Test agent"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Config = cfg
+	if opts.NewRunner == nil {
+		opts.NewRunner = func(w run.StreamWriter) (*run.Runner, error) {
+			return run.NewRunner(cfg, false, run.RunnerOptions{StreamWriter: w, RawOutput: true})
+		}
+	}
+	srv := New(opts)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Wait(time.Second)
+	})
+	return ts
+}
+
+func do(t *testing.T, req *http.Request) *http.Response {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func runRequestFor(t *testing.T, url, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestAuth(t *testing.T) {
+	ts := newTestServer(t, Options{Token: "secret"})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/agents", nil)
+	if resp := do(t, req); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/agents", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if resp := do(t, req); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong token: status = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/agents", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if resp := do(t, req); resp.StatusCode != http.StatusOK {
+		t.Errorf("right token: status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestBrowserRequestsRefused(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	req := runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`)
+	req.Header.Set("Origin", "https://example.com")
+	if resp := do(t, req); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("with Origin: status = %d, want 403", resp.StatusCode)
+	}
+
+	req = runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`)
+	req.Host = "rebound.example.com:7878"
+	if resp := do(t, req); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("non-local Host: status = %d, want 403", resp.StatusCode)
+	}
+
+	req = runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`)
+	req.Header.Set("Content-Type", "text/plain")
+	if resp := do(t, req); resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain body: status = %d, want 415", resp.StatusCode)
+	}
+
+	req = runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if resp := do(t, req); resp.StatusCode != http.StatusOK {
+		t.Errorf("JSON with charset: status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestAnyHost(t *testing.T) {
+	ts := newTestServer(t, Options{AnyHost: true, Token: "secret"})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/agents", nil)
+	req.Host = "ayo.example.com"
+	req.Header.Set("Authorization", "Bearer secret")
+	if resp := do(t, req); resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestListAgents(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/agents", nil)
+	resp := do(t, req)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var agents []agentInfo
+	if err := json.NewDecoder(resp.Body).Decode(&agents); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, a := range agents {
+		if a.Handle == "@alice" {
+			found = true
+			if a.Description != "Test agent" || a.Model != "test" || a.BuiltIn {
+				t.Errorf("@alice = %+v", a)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("@alice missing from %+v", agents)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	resp := do(t, runRequestFor(t, ts.URL+"/agents/alice/run", `{"prompt": "hi"}`))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d, want 200: %s", resp.StatusCode, body)
+	}
+	var got runResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Response != "hello there" {
+		t.Errorf("response = %q, want %q", got.Response, "hello there")
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	req := runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`)
+	req.Header.Set("Accept", "text/event-stream")
+	resp := do(t, req)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var events []string
+	var text strings.Builder
+	var done runResponse
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			event = name
+			events = append(events, name)
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		switch event {
+		case "text":
+			var d deltaEvent
+			json.Unmarshal([]byte(data), &d)
+			text.WriteString(d.Delta)
+		case "done":
+			json.Unmarshal([]byte(data), &done)
+		case "error":
+			t.Fatalf("error event: %s", data)
+		}
+	}
+
+	if len(events) == 0 || events[len(events)-1] != "done" {
+		t.Fatalf("events = %v, want to end with done", events)
+	}
+	if text.String() != "hello there" {
+		t.Errorf("streamed text = %q, want %q", text.String(), "hello there")
+	}
+	if done.Response != "hello there" {
+		t.Errorf("done response = %q, want %q", done.Response, "hello there")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown agent", "/agents/@nobody/run", `{"prompt": "hi"}`, http.StatusNotFound},
+		{"missing prompt", "/agents/@alice/run", `{}`, http.StatusBadRequest},
+		{"invalid body", "/agents/@alice/run", `not json`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do(t, runRequestFor(t, ts.URL+tt.path, tt.body))
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			var e errorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+				t.Errorf("want an error body, got %+v (%v)", e, err)
+			}
+		})
+	}
+}
+
+func TestRunAttachments(t *testing.T) {
+	ts := newTestServer(t, Options{})
+	dir, _ := os.Getwd()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		attachment string
+		status     int
+	}{
+		{"notes.txt", http.StatusOK},
+		{filepath.Join(dir, "notes.txt"), http.StatusOK},
+		{outside, http.StatusBadRequest},
+		{"../" + filepath.Base(filepath.Dir(outside)) + "/secret.txt", http.StatusBadRequest},
+		{"link.txt", http.StatusBadRequest},
+		{"missing.txt", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(runRequest{Prompt: "hi", Attachments: []string{tt.attachment}})
+		resp := do(t, runRequestFor(t, ts.URL+"/agents/@alice/run", string(body)))
+		if resp.StatusCode != tt.status {
+			msg, _ := io.ReadAll(resp.Body)
+			t.Errorf("attachment %q: status = %d, want %d: %s", tt.attachment, resp.StatusCode, tt.status, msg)
+		}
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	release := make(chan struct{})
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(hang.Close)
+	t.Cleanup(func() { close(release) })
+
+	ts := newTestServer(t, Options{
+		Timeout: 50 * time.Millisecond,
+		NewRunner: func(w run.StreamWriter) (*run.Runner, error) {
+			cfg := config.Config{Provider: catwalk.Provider{ID: "test", Type: catwalk.TypeOpenAICompat, APIEndpoint: hang.URL, APIKey: "key"}}
+			return run.NewRunner(cfg, false, run.RunnerOptions{StreamWriter: w, RawOutput: true})
+		},
+	})
+
+	resp := do(t, runRequestFor(t, ts.URL+"/agents/@alice/run", `{"prompt": "hi"}`))
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504", resp.StatusCode)
+	}
+}
+
+func TestMemories(t *testing.T) {
+	ctx := context.Background()
+	conn, queries, err := db.ConnectWithQueries(ctx, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	svc := memory.NewService(queries, nil)
+	for _, m := range []memory.Memory{
+		{Content: "prefers pnpm over npm", Category: memory.CategoryPreference, AgentHandle: "@alice"},
+		{Content: "works in Go", Category: memory.CategoryFact},
+	} {
+		if _, err := svc.Create(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts := newTestServer(t, Options{Memory: svc})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/memories?agent=alice", nil)
+	resp := do(t, req)
+	var listed []memoryInfo
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].Content != "prefers pnpm over npm" {
+		t.Errorf("listed = %+v, want @alice's memory", listed)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/memories/search?q=pnpm", nil)
+	resp = do(t, req)
+	var found []searchResult
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Memory.Content != "prefers pnpm over npm" {
+		t.Errorf("found = %+v, want the pnpm memory", found)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/memories/search", nil)
+	if resp := do(t, req); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("search without q: status = %d, want 400", resp.StatusCode)
+	}
+	req, _ = http.NewRequest(http.MethodGet, ts.URL+"/memories?limit=0", nil)
+	if resp := do(t, req); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("limit=0: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestMemoriesUnavailable(t *testing.T) {
+	ts := newTestServer(t, Options{})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/memories", nil)
+	if resp := do(t, req); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestCheckAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		token   string
+		wantErr bool
+	}{
+		{"127.0.0.1:7878", "", false},
+		{"localhost:7878", "", false},
+		{"[::1]:7878", "", false},
+		{"0.0.0.0:7878", "", true},
+		{":7878", "", true},
+		{"0.0.0.0:7878", "secret", false},
+		{"nope", "", true},
+	}
+	for _, tt := range tests {
+		err := CheckAddr(tt.addr, tt.token)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckAddr(%q, %q) = %v, wantErr %v", tt.addr, tt.token, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:7878": true,
+		"localhost:7878": true,
+		"[::1]:7878":     true,
+		"0.0.0.0:7878":   false,
+		":7878":          false,
+		"nope":           false,
+	} {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
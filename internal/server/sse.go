@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// sseWriter streams a run's output as server-sent events. Each event has a
+// name and JSON data:
+//
+//	text         {"delta"}
+//	reasoning    {"delta"}
+//	tool_start   {"id", "name", "input"}
+//	tool_result  {"id", "name", "output", "error", "duration_ms"}
+//	agent_start  {"handle", "prompt"}
+//	agent_end    {"handle", "duration_ms", "error"}
+//	memory       {"event", "count"}
+//	usage        {"prompt_tokens", "completion_tokens", "cost_usd"}
+//	done         {"response", "session_id"}
+//	error        {"error"}
+//
+// The stream ends after "done" or "error".
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool // The response is finished; later events are dropped
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	s := &sseWriter{w: w, flusher: flusher}
+	s.flush()
+	return s
+}
+
+// send writes one event.
+func (s *sseWriter) send(event string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload)
+	s.flush()
+}
+
+// close drops events sent after the response is finished.
+func (s *sseWriter) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (s *sseWriter) flush() {
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+type deltaEvent struct {
+	Delta string `json:"delta"`
+}
+
+type toolStartEvent struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Input string `json:"input,omitempty"`
+}
+
+type toolResultEvent struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Output     string `json:"output"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type agentStartEvent struct {
+	Handle string `json:"handle"`
+	Prompt string `json:"prompt"`
+}
+
+type agentEndEvent struct {
+	Handle     string `json:"handle"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type memoryEvent struct {
+	Event string `json:"event"`
+	Count int    `json:"count"`
+}
+
+type usageEvent struct {
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+func (s *sseWriter) WriteText(delta string) {
+	s.send("text", deltaEvent{Delta: delta})
+}
+
+func (s *sseWriter) WriteTextDone(content string) {}
+
+func (s *sseWriter) WriteReasoning(delta string) {
+	s.send("reasoning", deltaEvent{Delta: delta})
+}
+
+func (s *sseWriter) WriteReasoningDone(content string, duration time.Duration) {}
+
+func (s *sseWriter) WriteToolStart(call run.ToolCall) {
+	s.send("tool_start", toolStartEvent{ID: call.ID, Name: call.Name, Input: call.Input})
+}
+
+func (s *sseWriter) WriteToolResult(result run.ToolResult) {
+	s.send("tool_result", toolResultEvent{
+		ID:         result.ID,
+		Name:       result.Name,
+		Output:     result.Output,
+		Error:      result.Error,
+		DurationMS: result.Duration.Milliseconds(),
+	})
+}
+
+func (s *sseWriter) WriteAgentStart(handle, prompt string) {
+	s.send("agent_start", agentStartEvent{Handle: handle, Prompt: prompt})
+}
+
+func (s *sseWriter) WriteAgentEnd(handle string, duration time.Duration, err error) {
+	event := agentEndEvent{Handle: handle, DurationMS: duration.Milliseconds()}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.send("agent_end", event)
+}
+
+func (s *sseWriter) WriteMemoryEvent(event string, count int) {
+	s.send("memory", memoryEvent{Event: event, Count: count})
+}
+
+// WriteError is a no-op: the handler sends the run's error as the final
+// event.
+func (s *sseWriter) WriteError(err error) {}
+
+// WriteDone is a no-op: the handler sends "done" with the session ID.
+func (s *sseWriter) WriteDone(response string) {}
+
+func (s *sseWriter) WriteUsage(usage run.Usage) {
+	s.send("usage", usageEvent{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          usage.CostUSD,
+	})
+}
+
+// Verify sseWriter implements StreamWriter and UsageWriter
+var (
+	_ run.StreamWriter = (*sseWriter)(nil)
+	_ run.UsageWriter  = (*sseWriter)(nil)
+)
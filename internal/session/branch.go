@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"fmt"
+)
+
+// Branch creates a session that copies the first n messages of the parent
+// session, so the conversation can continue differently from that point.
+// n <= 0 copies every message. The parent is left unchanged and linked to
+// the branch with a branch edge whose trigger message is the last message
+// copied.
+func (s *Services) Branch(ctx context.Context, parentID string, n int) (Session, error) {
+	parent, err := s.Sessions.Get(ctx, parentID)
+	if err != nil {
+		return Session{}, err
+	}
+	messages, err := s.Messages.List(ctx, parent.ID)
+	if err != nil {
+		return Session{}, err
+	}
+	if n <= 0 {
+		n = len(messages)
+	}
+	if n == 0 {
+		return Session{}, fmt.Errorf("session %s has no messages to branch from", parent.ID[:8])
+	}
+	if n > len(messages) {
+		return Session{}, fmt.Errorf("message %d is out of range: session %s has %d messages", n, parent.ID[:8], len(messages))
+	}
+
+	branch, err := s.Sessions.Create(ctx, CreateParams{
+		AgentHandle:     parent.AgentHandle,
+		Title:           parent.Title,
+		Source:          parent.Source,
+		InputSchema:     parent.InputSchema,
+		OutputSchema:    parent.OutputSchema,
+		StructuredInput: parent.StructuredInput,
+		ChainDepth:      parent.ChainDepth,
+		ChainSource:     parent.ChainSource,
+		WorkingDir:      parent.WorkingDir,
+	})
+	if err != nil {
+		return Session{}, err
+	}
+
+	copyMessages := func() error {
+		for _, msg := range messages[:n] {
+			if _, err := s.Messages.Create(ctx, CreateMessageParams{
+				SessionID: branch.ID,
+				Role:      msg.Role,
+				Parts:     msg.Parts,
+				Model:     msg.Model,
+				Provider:  msg.Provider,
+			}); err != nil {
+				return err
+			}
+		}
+		return s.Edges.Create(ctx, parent.ID, branch.ID, EdgeTypeBranch, messages[n-1].ID)
+	}
+	if err := copyMessages(); err != nil {
+		s.Sessions.Delete(ctx, branch.ID)
+		return Session{}, fmt.Errorf("copy messages: %w", err)
+	}
+	return s.Sessions.Get(ctx, branch.ID)
+}
+
+// BranchParent returns the edge to the session a branch was created from.
+// ok is false when the session is not a branch.
+func (s *Services) BranchParent(ctx context.Context, sessionID string) (edge Edge, ok bool, err error) {
+	parents, err := s.Edges.GetParents(ctx, sessionID)
+	if err != nil {
+		return Edge{}, false, err
+	}
+	for _, e := range parents {
+		if e.EdgeType == EdgeTypeBranch {
+			return e, true, nil
+		}
+	}
+	return Edge{}, false, nil
+}
+
+// Branches returns the edges to sessions branched from a session.
+func (s *Services) Branches(ctx context.Context, sessionID string) ([]Edge, error) {
+	children, err := s.Edges.GetChildren(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var branches []Edge
+	for _, e := range children {
+		if e.EdgeType == EdgeTypeBranch {
+			branches = append(branches, e)
+		}
+	}
+	return branches, nil
+}
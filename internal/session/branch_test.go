@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBranch(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	parent, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo", Title: "Naming"})
+	texts := []string{"first question", "first answer", "second question", "second answer"}
+	for i, text := range texts {
+		role := RoleUser
+		if i%2 == 1 {
+			role = RoleAssistant
+		}
+		svc.Messages.Create(ctx, CreateMessageParams{SessionID: parent.ID, Role: role, Parts: []ContentPart{TextContent{Text: text}}})
+	}
+	parentMsgs, _ := svc.Messages.List(ctx, parent.ID)
+
+	branch, err := svc.Branch(ctx, parent.ID, 2)
+	if err != nil {
+		t.Fatalf("Branch: %v", err)
+	}
+	if branch.ID == parent.ID || branch.AgentHandle != "@ayo" || branch.Title != "Naming" {
+		t.Errorf("branch = %+v", branch)
+	}
+
+	msgs, _ := svc.Messages.List(ctx, branch.ID)
+	if len(msgs) != 2 || msgs[0].TextContent() != "first question" || msgs[1].TextContent() != "first answer" {
+		t.Fatalf("branch messages = %+v, want the first 2", msgs)
+	}
+	if n, _ := svc.Messages.Count(ctx, parent.ID); n != 4 {
+		t.Errorf("parent has %d messages after branching, want 4", n)
+	}
+
+	edge, ok, err := svc.BranchParent(ctx, branch.ID)
+	if err != nil || !ok {
+		t.Fatalf("BranchParent = %v, %v", ok, err)
+	}
+	if edge.ParentID != parent.ID || edge.TriggerMessageID != parentMsgs[1].ID {
+		t.Errorf("edge = %+v, want parent %s at message %s", edge, parent.ID, parentMsgs[1].ID)
+	}
+	if branches, _ := svc.Branches(ctx, parent.ID); len(branches) != 1 || branches[0].ChildID != branch.ID {
+		t.Errorf("Branches = %+v", branches)
+	}
+	if _, ok, _ := svc.BranchParent(ctx, parent.ID); ok {
+		t.Error("the original session should not be a branch")
+	}
+}
+
+func TestBranchAllMessages(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	parent, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo"})
+	svc.Messages.Create(ctx, CreateMessageParams{SessionID: parent.ID, Role: RoleUser, Parts: []ContentPart{TextContent{Text: "hi"}}})
+	svc.Messages.Create(ctx, CreateMessageParams{SessionID: parent.ID, Role: RoleAssistant, Parts: []ContentPart{TextContent{Text: "hello"}}})
+
+	branch, err := svc.Branch(ctx, parent.ID, 0)
+	if err != nil {
+		t.Fatalf("Branch: %v", err)
+	}
+	if branch.MessageCount != 2 {
+		t.Errorf("MessageCount = %d, want 2", branch.MessageCount)
+	}
+}
+
+func TestBranchOutOfRange(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	parent, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo"})
+	if _, err := svc.Branch(ctx, parent.ID, 0); err == nil || !strings.Contains(err.Error(), "no messages") {
+		t.Errorf("Branch(empty) error = %v", err)
+	}
+
+	svc.Messages.Create(ctx, CreateMessageParams{SessionID: parent.ID, Role: RoleUser, Parts: []ContentPart{TextContent{Text: "hi"}}})
+	if _, err := svc.Branch(ctx, parent.ID, 3); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("Branch(3) error = %v", err)
+	}
+	if sessions, _ := svc.Sessions.List(ctx, 10); len(sessions) != 1 {
+		t.Errorf("failed branches should not create sessions, got %d", len(sessions))
+	}
+}
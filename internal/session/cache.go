@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+// CacheService stores agent responses by request key.
+type CacheService struct {
+	q *db.Queries
+}
+
+// NewCacheService creates a response cache service.
+func NewCacheService(q *db.Queries) *CacheService {
+	return &CacheService{q: q}
+}
+
+// Get returns the cached response for key. ok is false when there is no
+// entry or it has expired.
+func (s *CacheService) Get(ctx context.Context, key string) (response string, ok bool, err error) {
+	response, err = s.q.GetCachedResponse(ctx, db.GetCachedResponseParams{
+		Key: key,
+		Now: time.Now().Unix(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return response, true, nil
+}
+
+// Put caches response under key for ttl, replacing any earlier entry, and
+// removes expired entries.
+func (s *CacheService) Put(ctx context.Context, key, model, response string, ttl time.Duration) error {
+	now := time.Now()
+	if err := s.q.DeleteExpiredResponses(ctx, now.Unix()); err != nil {
+		return err
+	}
+	return s.q.PutCachedResponse(ctx, db.PutCachedResponseParams{
+		Key:       key,
+		Model:     model,
+		Response:  response,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+}
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheService(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if _, ok, err := svc.Cache.Get(ctx, "k1"); ok || err != nil {
+		t.Fatalf("Get on empty cache = %v, %v", ok, err)
+	}
+
+	if err := svc.Cache.Put(ctx, "k1", "gpt-4", "first", time.Hour); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := svc.Cache.Put(ctx, "k1", "gpt-4", "second", time.Hour); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	resp, ok, err := svc.Cache.Get(ctx, "k1")
+	if err != nil || !ok || resp != "second" {
+		t.Errorf("Get = %q, %v, %v; want the replaced response", resp, ok, err)
+	}
+
+	// An expired entry is a miss
+	if err := svc.Cache.Put(ctx, "k2", "gpt-4", "stale", -time.Second); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok, err := svc.Cache.Get(ctx, "k2"); ok || err != nil {
+		t.Errorf("Get expired = %v, %v; want a miss", ok, err)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	EdgeTypeChain        EdgeType = "chain"
 	EdgeTypeContinuation EdgeType = "continuation"
 	EdgeTypeTitleGen     EdgeType = "title_gen"
+	EdgeTypeBranch       EdgeType = "branch"
 )
 
 // Edge represents a relationship between two sessions.
@@ -14,6 +14,7 @@ type Services struct {
 	Sessions *SessionService
 	Messages *MessageService
 	Edges    *EdgeService
+	Cache    *CacheService
 }
 
 // NewServices creates a new Services instance from a database connection.
@@ -24,6 +25,7 @@ func NewServices(database *sql.DB, queries *db.Queries) *Services {
 		Sessions: NewSessionService(queries),
 		Messages: NewMessageService(queries),
 		Edges:    NewEdgeService(queries),
+		Cache:    NewCacheService(queries),
 	}
 }
 
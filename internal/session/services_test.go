@@ -169,6 +169,38 @@ func TestSessionServiceListByAgent(t *testing.T) {
 	}
 }
 
+func TestSessionServiceListByAgents(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@team.foo"})
+	svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@team.bar"})
+	svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@team.bar"})
+	svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@other"})
+
+	handles, err := svc.Sessions.AgentHandles(ctx)
+	if err != nil {
+		t.Fatalf("AgentHandles failed: %v", err)
+	}
+	if len(handles) != 3 || handles[0] != "@other" || handles[2] != "@team.foo" {
+		t.Errorf("AgentHandles = %v", handles)
+	}
+
+	sessions, err := svc.Sessions.ListByAgents(ctx, []string{"@team.foo", "@team.bar"}, 10)
+	if err != nil {
+		t.Fatalf("ListByAgents failed: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("got %d sessions, want 3", len(sessions))
+	}
+
+	sessions, _ = svc.Sessions.ListByAgents(ctx, []string{"@team.foo", "@team.bar"}, 2)
+	if len(sessions) != 2 {
+		t.Errorf("got %d sessions with limit 2, want 2", len(sessions))
+	}
+}
+
 func TestSessionServiceSearch(t *testing.T) {
 	svc, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -1,8 +1,10 @@
 package session
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"slices"
 	"time"
 
 	"github.com/alexcabrera/ayo/internal/db"
@@ -32,6 +34,8 @@ type Session struct {
 	CreatedAt        int64
 	UpdatedAt        int64
 	FinishedAt       int64
+	Seed             *int64 // Seed of the latest run started with --seed; nil if none
+	WorkingDir       string // Directory the session was created in; "" if not recorded
 }
 
 // SessionService provides operations on sessions.
@@ -54,6 +58,7 @@ type CreateParams struct {
 	StructuredInput  string
 	ChainDepth       int64
 	ChainSource      string
+	WorkingDir       string
 }
 
 // Create creates a new session.
@@ -78,6 +83,7 @@ func (s *SessionService) Create(ctx context.Context, params CreateParams) (Sessi
 		StructuredInput: toNullString(params.StructuredInput),
 		ChainDepth:      params.ChainDepth,
 		ChainSource:     toNullString(params.ChainSource),
+		WorkingDir:      toNullString(params.WorkingDir),
 	})
 	if err != nil {
 		return Session{}, err
@@ -130,6 +136,50 @@ func (s *SessionService) ListByAgent(ctx context.Context, agentHandle string, li
 	return sessionsFromDB(dbSessions), nil
 }
 
+// ListByAgentInDir returns an agent's sessions created in dir.
+func (s *SessionService) ListByAgentInDir(ctx context.Context, agentHandle, dir string, limit int64) ([]Session, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	dbSessions, err := s.q.ListSessionsByAgentInDir(ctx, db.ListSessionsByAgentInDirParams{
+		AgentHandle: agentHandle,
+		WorkingDir:  toNullString(dir),
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionsFromDB(dbSessions), nil
+}
+
+// AgentHandles returns the handles of every agent with a stored session.
+func (s *SessionService) AgentHandles(ctx context.Context) ([]string, error) {
+	return s.q.ListSessionAgents(ctx)
+}
+
+// ListByAgents returns the most recently updated sessions of the given
+// agents.
+func (s *SessionService) ListByAgents(ctx context.Context, agentHandles []string, limit int64) ([]Session, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var sessions []Session
+	for _, handle := range agentHandles {
+		page, err := s.ListByAgent(ctx, handle, limit)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, page...)
+	}
+	slices.SortStableFunc(sessions, func(a, b Session) int {
+		return cmp.Compare(b.UpdatedAt, a.UpdatedAt)
+	})
+	if int64(len(sessions)) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
 // Search finds sessions by title substring.
 func (s *SessionService) Search(ctx context.Context, query string, limit int64) ([]Session, error) {
 	if limit <= 0 {
@@ -153,6 +203,14 @@ func (s *SessionService) UpdateTitle(ctx context.Context, id, title string) erro
 	})
 }
 
+// SetSeed records the sampling seed a run in the session used.
+func (s *SessionService) SetSeed(ctx context.Context, id string, seed int64) error {
+	return s.q.UpdateSessionSeed(ctx, db.UpdateSessionSeedParams{
+		ID:   id,
+		Seed: sql.NullInt64{Int64: seed, Valid: true},
+	})
+}
+
 // Finish marks a session as finished.
 func (s *SessionService) Finish(ctx context.Context, id string, structuredOutput string) (Session, error) {
 	now := time.Now().Unix()
@@ -219,6 +277,8 @@ func sessionFromDB(d db.Session) Session {
 		CreatedAt:        d.CreatedAt,
 		UpdatedAt:        d.UpdatedAt,
 		FinishedAt:       d.FinishedAt.Int64,
+		Seed:             nullInt64Ptr(d.Seed),
+		WorkingDir:       d.WorkingDir.String,
 	}
 }
 
@@ -230,6 +290,13 @@ func sessionsFromDB(ds []db.Session) []Session {
 	return sessions
 }
 
+func nullInt64Ptr(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
 func toNullString(s string) sql.NullString {
 	if s == "" {
 		return sql.NullString{}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+// SkillUsage is how often one agent had a skill in its prompt.
+type SkillUsage struct {
+	Agent    string `json:"agent"`
+	Skill    string `json:"skill"`
+	Sessions int64  `json:"sessions"`
+	Turns    int64  `json:"turns"`    // Turns with the skill in the prompt
+	Selected int64  `json:"selected"` // Of those, turns where skill selection chose it
+	Skipped  int64  `json:"skipped"`  // Turns where skill selection left it out
+}
+
+// SkillUsageReport lists skill usage per agent and skill, each agent's
+// skills ranked by the turns they were in the prompt.
+type SkillUsageReport struct {
+	Since  time.Time    `json:"since,omitzero"` // Zero = all time
+	Until  time.Time    `json:"until"`
+	Skills []SkillUsage `json:"skills"`
+}
+
+// AddSkillUsage records a turn of a session: the skills in the prompt and
+// the skills skill selection left out. selected means skill selection chose
+// the skills in the prompt.
+func (s *SessionService) AddSkillUsage(ctx context.Context, id string, inPrompt, skipped []string, selected bool) error {
+	chosen := int64(0)
+	if selected {
+		chosen = 1
+	}
+	for _, skill := range inPrompt {
+		if err := s.q.AddSkillUsage(ctx, db.AddSkillUsageParams{SessionID: id, Skill: skill, Turns: 1, Selected: chosen}); err != nil {
+			return err
+		}
+	}
+	for _, skill := range skipped {
+		if err := s.q.AddSkillUsage(ctx, db.AddSkillUsageParams{SessionID: id, Skill: skill, Skipped: 1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SkillUsageReport totals skill usage for the sessions matching the filter.
+func (s *SessionService) SkillUsageReport(ctx context.Context, filter UsageFilter) (SkillUsageReport, error) {
+	until := filter.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	report := SkillUsageReport{Since: filter.Since, Until: until}
+
+	rows, err := s.q.SumSkillUsage(ctx, db.SumSkillUsageParams{
+		Since:       filter.Since.Unix(),
+		Until:       until.Unix(),
+		AgentHandle: filter.AgentHandle,
+	})
+	if err != nil {
+		return SkillUsageReport{}, err
+	}
+	report.Skills = make([]SkillUsage, len(rows))
+	for i, row := range rows {
+		report.Skills[i] = SkillUsage{
+			Agent:    row.AgentHandle,
+			Skill:    row.Skill,
+			Sessions: row.Sessions,
+			Turns:    row.Turns,
+			Selected: row.Selected,
+			Skipped:  row.Skipped,
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSkillUsageReport(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	first, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@coder", Title: "First"})
+	second, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@coder", Title: "Second"})
+	other, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@writer", Title: "Other"})
+
+	// Two selected turns and one turn with every skill
+	if err := svc.Sessions.AddSkillUsage(ctx, first.ID, []string{"git"}, []string{"debugging"}, true); err != nil {
+		t.Fatalf("AddSkillUsage failed: %v", err)
+	}
+	svc.Sessions.AddSkillUsage(ctx, first.ID, []string{"git"}, []string{"debugging"}, true)
+	svc.Sessions.AddSkillUsage(ctx, second.ID, []string{"git", "debugging"}, nil, false)
+	svc.Sessions.AddSkillUsage(ctx, other.ID, []string{"style"}, nil, false)
+
+	report, err := svc.Sessions.SkillUsageReport(ctx, UsageFilter{Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("SkillUsageReport failed: %v", err)
+	}
+	want := []SkillUsage{
+		{Agent: "@coder", Skill: "git", Sessions: 2, Turns: 3, Selected: 2},
+		{Agent: "@coder", Skill: "debugging", Sessions: 2, Turns: 1, Skipped: 2},
+		{Agent: "@writer", Skill: "style", Sessions: 1, Turns: 1},
+	}
+	if len(report.Skills) != len(want) {
+		t.Fatalf("skills = %+v, want %+v", report.Skills, want)
+	}
+	for i := range want {
+		if report.Skills[i] != want[i] {
+			t.Errorf("skill %d = %+v, want %+v", i, report.Skills[i], want[i])
+		}
+	}
+
+	report, _ = svc.Sessions.SkillUsageReport(ctx, UsageFilter{AgentHandle: "@writer"})
+	if len(report.Skills) != 1 || report.Skills[0].Skill != "style" {
+		t.Errorf("agent filter: %+v", report.Skills)
+	}
+
+	// Usage is removed with its session
+	svc.Sessions.Delete(ctx, other.ID)
+	report, _ = svc.Sessions.SkillUsageReport(ctx, UsageFilter{AgentHandle: "@writer"})
+	if len(report.Skills) != 0 {
+		t.Errorf("after delete: %+v", report.Skills)
+	}
+}
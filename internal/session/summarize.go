@@ -0,0 +1,126 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SummarizeFunc condenses a conversation transcript into a summary. The
+// transcript is "role: text" lines, or summaries of consecutive parts of a
+// conversation that is too long to summarize at once.
+type SummarizeFunc func(ctx context.Context, transcript string) (string, error)
+
+// ErrNothingToSummarize means a session has no text messages.
+var ErrNothingToSummarize = errors.New("no messages to summarize")
+
+// Transcript renders the text of messages as "role: text" lines. Tool calls,
+// tool results, and reasoning are left out.
+func Transcript(messages []Message) []string {
+	var lines []string
+	for _, msg := range messages {
+		if msg.Role == RoleSystem || msg.Role == RoleTool {
+			continue
+		}
+		if text := strings.TrimSpace(msg.TextContent()); text != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", msg.Role, text))
+		}
+	}
+	return lines
+}
+
+// SummaryResult is the outcome of Summarize.
+type SummaryResult struct {
+	Summary string
+	Chunks  int // Parts the transcript was split into
+	Calls   int // Calls made to the summarize function
+}
+
+// Summarize summarizes the text of messages. A transcript longer than
+// chunkChars is split into parts that are summarized separately, and the
+// part summaries are then summarized together the same way until one
+// summary remains.
+func Summarize(ctx context.Context, messages []Message, chunkChars int, summarize SummarizeFunc) (SummaryResult, error) {
+	lines := Transcript(messages)
+	if len(lines) == 0 {
+		return SummaryResult{}, ErrNothingToSummarize
+	}
+
+	var result SummaryResult
+	for round := 0; ; round++ {
+		// Part summaries are packed at least two to a chunk, so each round
+		// halves them even when they come back longer than expected
+		minLines := 2
+		if round == 0 {
+			minLines = 1
+		}
+		chunks := chunkLines(lines, chunkChars, minLines)
+		if round == 0 {
+			result.Chunks = len(chunks)
+		}
+		summaries := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			summary, err := summarize(ctx, chunk)
+			result.Calls++
+			if err != nil {
+				return result, err
+			}
+			if strings.TrimSpace(summary) == "" {
+				return result, fmt.Errorf("summary of part %d of %d is empty", i+1, len(chunks))
+			}
+			summaries[i] = strings.TrimSpace(summary)
+		}
+		if len(summaries) == 1 {
+			result.Summary = summaries[0]
+			return result, nil
+		}
+
+		lines = make([]string, len(summaries))
+		for i, s := range summaries {
+			lines[i] = fmt.Sprintf("Summary of part %d of %d: %s", i+1, len(summaries), s)
+		}
+	}
+}
+
+// chunkLines packs lines, in order, into chunks of at most limit bytes,
+// except that a chunk takes at least minLines lines. With minLines of 1, a
+// line longer than limit is split across chunks.
+func chunkLines(lines []string, limit, minLines int) []string {
+	var chunks []string
+	var b strings.Builder
+	n := 0 // Lines in b
+	for _, line := range lines {
+		for minLines <= 1 && len(line) > limit {
+			if b.Len() > 0 {
+				chunks = append(chunks, b.String())
+				b.Reset()
+				n = 0
+			}
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				cut = limit
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+		if b.Len() > 0 && b.Len()+1+len(line) > limit && n >= minLines {
+			chunks = append(chunks, b.String())
+			b.Reset()
+			n = 0
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+		n++
+	}
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+	return chunks
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func textMessage(role MessageRole, text string) Message {
+	return Message{Role: role, Parts: []ContentPart{TextContent{Text: text}}}
+}
+
+func TestTranscript(t *testing.T) {
+	lines := Transcript([]Message{
+		textMessage(RoleSystem, "You are helpful."),
+		textMessage(RoleUser, "move billing to Postgres"),
+		{Role: RoleAssistant, Parts: []ContentPart{ToolCall{ID: "t1", Name: "bash"}}},
+		{Role: RoleTool, Parts: []ContentPart{ToolResult{ToolCallID: "t1", Content: "ok"}}},
+		textMessage(RoleAssistant, "Done."),
+	})
+	want := []string{"user: move billing to Postgres", "assistant: Done."}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Transcript = %q, want %q", lines, want)
+	}
+}
+
+func TestSummarizeShortConversation(t *testing.T) {
+	calls := 0
+	result, err := Summarize(context.Background(), []Message{
+		textMessage(RoleUser, "hi"),
+		textMessage(RoleAssistant, "hello"),
+	}, 1000, func(ctx context.Context, transcript string) (string, error) {
+		calls++
+		return "greetings were exchanged", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary != "greetings were exchanged" || result.Chunks != 1 || calls != 1 {
+		t.Errorf("result = %+v after %d calls, want one call", result, calls)
+	}
+}
+
+func TestSummarizeMapReduce(t *testing.T) {
+	var messages []Message
+	for i := range 20 {
+		messages = append(messages, textMessage(RoleUser, fmt.Sprintf("question %d %s", i, strings.Repeat("x", 80))))
+	}
+
+	var transcripts []string
+	result, err := Summarize(context.Background(), messages, 300, func(ctx context.Context, transcript string) (string, error) {
+		if len(transcript) > 300 {
+			t.Errorf("transcript of %d bytes exceeds the chunk size", len(transcript))
+		}
+		transcripts = append(transcripts, transcript)
+		return fmt.Sprintf("summary %d", len(transcripts)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Chunks < 2 {
+		t.Fatalf("chunks = %d, want the transcript split", result.Chunks)
+	}
+	if result.Calls != len(transcripts) || result.Calls <= result.Chunks {
+		t.Errorf("calls = %d for %d chunks, want the part summaries combined", result.Calls, result.Chunks)
+	}
+	last := transcripts[len(transcripts)-1]
+	if !strings.Contains(last, "Summary of part 1 of") {
+		t.Errorf("final transcript = %q, want the part summaries", last)
+	}
+	if result.Summary != fmt.Sprintf("summary %d", len(transcripts)) {
+		t.Errorf("summary = %q, want the last call's", result.Summary)
+	}
+}
+
+func TestSummarizeErrors(t *testing.T) {
+	fail := func(ctx context.Context, transcript string) (string, error) {
+		return "", errors.New("model unavailable")
+	}
+	if _, err := Summarize(context.Background(), nil, 100, fail); !errors.Is(err, ErrNothingToSummarize) {
+		t.Errorf("err = %v, want ErrNothingToSummarize", err)
+	}
+	if _, err := Summarize(context.Background(), []Message{textMessage(RoleUser, "hi")}, 100, fail); err == nil {
+		t.Error("a failing summarizer should fail Summarize")
+	}
+}
+
+func TestChunkLinesSplitsLongLines(t *testing.T) {
+	chunks := chunkLines([]string{"short", strings.Repeat("é", 50)}, 30, 1)
+	for _, c := range chunks {
+		if len(c) > 30 {
+			t.Errorf("chunk of %d bytes exceeds the limit", len(c))
+		}
+		if !strings.HasPrefix(c, "short") && !strings.HasPrefix(c, "é") {
+			t.Errorf("chunk %q was split inside a rune", c)
+		}
+	}
+	if got := strings.Join(chunks, ""); got != "short"+strings.Repeat("é", 50) {
+		t.Errorf("chunks lost text: %q", got)
+	}
+}
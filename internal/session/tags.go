@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+// TagCount is a tag and the number of sessions carrying it.
+type TagCount struct {
+	Tag   string
+	Count int64
+}
+
+// NormalizeTag trims and lowercases a tag and checks that it is usable.
+// Tags may not be empty, contain whitespace or commas, or start with + or -.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag is empty")
+	}
+	if strings.HasPrefix(tag, "+") || strings.HasPrefix(tag, "-") {
+		return "", fmt.Errorf("invalid tag %q: must not start with + or -", tag)
+	}
+	if strings.ContainsFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+		return "", fmt.Errorf("invalid tag %q: must not contain spaces or commas", tag)
+	}
+	return tag, nil
+}
+
+// AddTags adds tags to a session. Tags already present are ignored.
+func (s *SessionService) AddTags(ctx context.Context, id string, tags ...string) error {
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+		if err := s.q.AddSessionTag(ctx, db.AddSessionTagParams{SessionID: id, Tag: tag}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveTags removes tags from a session. Tags not present are ignored.
+func (s *SessionService) RemoveTags(ctx context.Context, id string, tags ...string) error {
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+		if err := s.q.RemoveSessionTag(ctx, db.RemoveSessionTagParams{SessionID: id, Tag: tag}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tags returns a session's tags in alphabetical order.
+func (s *SessionService) Tags(ctx context.Context, id string) ([]string, error) {
+	return s.q.ListSessionTags(ctx, id)
+}
+
+// ListByTag returns sessions carrying a tag, most recent first.
+func (s *SessionService) ListByTag(ctx context.Context, tag string, limit int64) ([]Session, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	dbSessions, err := s.q.ListSessionsByTag(ctx, db.ListSessionsByTagParams{
+		Tag:   tag,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionsFromDB(dbSessions), nil
+}
+
+// TagCounts returns every tag in use with its session count, most used first.
+func (s *SessionService) TagCounts(ctx context.Context) ([]TagCount, error) {
+	rows, err := s.q.CountSessionTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]TagCount, len(rows))
+	for i, row := range rows {
+		counts[i] = TagCount{Tag: row.Tag, Count: row.Count}
+	}
+	return counts, nil
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "work", want: "work"},
+		{input: "  Work ", want: "work"},
+		{input: "project/ayo", want: "project/ayo"},
+		{input: "", wantErr: true},
+		{input: "+work", wantErr: true},
+		{input: "-draft", wantErr: true},
+		{input: "two words", wantErr: true},
+		{input: "a,b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeTag(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeTag(%q) = %q, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSessionTags(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	first, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo", Title: "First"})
+	second, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo", Title: "Second"})
+
+	if err := svc.Sessions.AddTags(ctx, first.ID, "work", "Draft"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+	// Adding an existing tag is a no-op
+	if err := svc.Sessions.AddTags(ctx, first.ID, "work"); err != nil {
+		t.Fatalf("AddTags (duplicate) failed: %v", err)
+	}
+	if err := svc.Sessions.AddTags(ctx, second.ID, "work"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+
+	tags, err := svc.Sessions.Tags(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Tags failed: %v", err)
+	}
+	if !slices.Equal(tags, []string{"draft", "work"}) {
+		t.Errorf("Tags = %v, want [draft work]", tags)
+	}
+
+	if err := svc.Sessions.RemoveTags(ctx, first.ID, "draft", "missing"); err != nil {
+		t.Fatalf("RemoveTags failed: %v", err)
+	}
+	tags, _ = svc.Sessions.Tags(ctx, first.ID)
+	if !slices.Equal(tags, []string{"work"}) {
+		t.Errorf("Tags after remove = %v, want [work]", tags)
+	}
+
+	sessions, err := svc.Sessions.ListByTag(ctx, "WORK", 10)
+	if err != nil {
+		t.Fatalf("ListByTag failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Errorf("ListByTag returned %d sessions, want 2", len(sessions))
+	}
+
+	if err := svc.Sessions.AddTags(ctx, second.ID, "urgent"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+	counts, err := svc.Sessions.TagCounts(ctx)
+	if err != nil {
+		t.Fatalf("TagCounts failed: %v", err)
+	}
+	want := []TagCount{{Tag: "work", Count: 2}, {Tag: "urgent", Count: 1}}
+	if !slices.Equal(counts, want) {
+		t.Errorf("TagCounts = %v, want %v", counts, want)
+	}
+
+	if err := svc.Sessions.AddTags(ctx, first.ID, "-bad"); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+}
+
+func TestSessionTagsDeletedWithSession(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	sess, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@ayo", Title: "Tagged"})
+	if err := svc.Sessions.AddTags(ctx, sess.ID, "work"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+	if err := svc.Sessions.Delete(ctx, sess.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	counts, err := svc.Sessions.TagCounts(ctx)
+	if err != nil {
+		t.Fatalf("TagCounts failed: %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("expected no tags after delete, got %v", counts)
+	}
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/db"
+)
+
+// UnknownModel labels usage from sessions created before usage tracking.
+const UnknownModel = "unknown"
+
+// UsageFilter selects the sessions a usage report covers.
+type UsageFilter struct {
+	Since       time.Time // Sessions created at or after this time
+	Until       time.Time // Sessions created at or before this time; zero = now
+	AgentHandle string    // Empty = all agents
+}
+
+// AgentUsage is the usage of one agent's sessions.
+type AgentUsage struct {
+	Agent             string  `json:"agent"`
+	Sessions          int64   `json:"sessions"`
+	UntrackedSessions int64   `json:"untracked_sessions"` // Created before usage tracking
+	PromptTokens      int64   `json:"prompt_tokens"`
+	CompletionTokens  int64   `json:"completion_tokens"`
+	CostUSD           float64 `json:"cost_usd"`
+}
+
+// ModelUsage is the usage of one model across agents.
+type ModelUsage struct {
+	Model            string  `json:"model"` // UnknownModel for untracked sessions
+	Sessions         int64   `json:"sessions"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// UsageReport totals usage per agent and per model, each ranked by cost
+// and then by tokens.
+type UsageReport struct {
+	Since  time.Time    `json:"since,omitzero"` // Zero = all time
+	Until  time.Time    `json:"until"`
+	Agents []AgentUsage `json:"agents"`
+	Models []ModelUsage `json:"models"`
+}
+
+// AddUsage adds a step's token usage and estimated cost to a session's
+// totals for the model.
+func (s *SessionService) AddUsage(ctx context.Context, id, model string, promptTokens, completionTokens int64, costUSD float64) error {
+	return s.q.AddSessionUsage(ctx, db.AddSessionUsageParams{
+		SessionID:        id,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		CostUsd:          costUSD,
+	})
+}
+
+// UsageReport totals usage for the sessions matching the filter.
+func (s *SessionService) UsageReport(ctx context.Context, filter UsageFilter) (UsageReport, error) {
+	until := filter.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	report := UsageReport{Since: filter.Since, Until: until}
+
+	agents, err := s.q.SumUsageByAgent(ctx, db.SumUsageByAgentParams{
+		Since:       filter.Since.Unix(),
+		Until:       until.Unix(),
+		AgentHandle: filter.AgentHandle,
+	})
+	if err != nil {
+		return UsageReport{}, err
+	}
+	report.Agents = make([]AgentUsage, len(agents))
+	for i, a := range agents {
+		report.Agents[i] = AgentUsage{
+			Agent:             a.AgentHandle,
+			Sessions:          a.Sessions,
+			UntrackedSessions: a.UntrackedSessions,
+			PromptTokens:      a.PromptTokens,
+			CompletionTokens:  a.CompletionTokens,
+			CostUSD:           a.CostUsd,
+		}
+	}
+
+	models, err := s.q.SumUsageByModel(ctx, db.SumUsageByModelParams{
+		Since:       filter.Since.Unix(),
+		Until:       until.Unix(),
+		AgentHandle: filter.AgentHandle,
+	})
+	if err != nil {
+		return UsageReport{}, err
+	}
+	report.Models = make([]ModelUsage, len(models))
+	for i, m := range models {
+		model := m.Model
+		if model == "" {
+			model = UnknownModel
+		}
+		report.Models[i] = ModelUsage{
+			Model:            model,
+			Sessions:         m.Sessions,
+			PromptTokens:     m.PromptTokens,
+			CompletionTokens: m.CompletionTokens,
+			CostUSD:          m.CostUsd,
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUsageReport(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	cheap, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@cheap", Title: "Cheap"})
+	costly, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@costly", Title: "Costly"})
+	svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@costly", Title: "Before tracking"})
+
+	svc.Sessions.AddUsage(ctx, cheap.ID, "small", 100, 20, 0.001)
+	svc.Sessions.AddUsage(ctx, costly.ID, "large", 1000, 200, 0.05)
+	// Steps on the same model accumulate
+	if err := svc.Sessions.AddUsage(ctx, costly.ID, "large", 500, 100, 0.02); err != nil {
+		t.Fatalf("AddUsage failed: %v", err)
+	}
+
+	report, err := svc.Sessions.UsageReport(ctx, UsageFilter{Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("UsageReport failed: %v", err)
+	}
+
+	if len(report.Agents) != 2 {
+		t.Fatalf("got %d agents, want 2: %+v", len(report.Agents), report.Agents)
+	}
+	top := report.Agents[0]
+	if top.Agent != "@costly" || top.Sessions != 2 || top.UntrackedSessions != 1 || top.PromptTokens != 1500 || top.CompletionTokens != 300 {
+		t.Errorf("top agent = %+v", top)
+	}
+	if top.CostUSD < 0.0699 || top.CostUSD > 0.0701 {
+		t.Errorf("top agent cost = %v, want 0.07", top.CostUSD)
+	}
+
+	var models []string
+	for _, m := range report.Models {
+		models = append(models, m.Model)
+	}
+	if len(models) != 3 || models[0] != "large" || models[1] != "small" || models[2] != UnknownModel {
+		t.Errorf("models = %v, want [large small unknown]", models)
+	}
+}
+
+func TestUsageReportFilters(t *testing.T) {
+	svc, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@a", Title: "A"})
+	b, _ := svc.Sessions.Create(ctx, CreateParams{AgentHandle: "@b", Title: "B"})
+	svc.Sessions.AddUsage(ctx, a.ID, "model", 10, 5, 0)
+	svc.Sessions.AddUsage(ctx, b.ID, "model", 10, 5, 0)
+
+	report, err := svc.Sessions.UsageReport(ctx, UsageFilter{AgentHandle: "@a"})
+	if err != nil {
+		t.Fatalf("UsageReport failed: %v", err)
+	}
+	if len(report.Agents) != 1 || report.Agents[0].Agent != "@a" || report.Models[0].PromptTokens != 10 {
+		t.Errorf("agent filter: %+v", report)
+	}
+
+	report, _ = svc.Sessions.UsageReport(ctx, UsageFilter{Since: time.Now().Add(time.Hour)})
+	if len(report.Agents) != 0 || len(report.Models) != 0 {
+		t.Errorf("expected no usage after the time range, got %+v", report)
+	}
+
+	// Usage is removed with its session
+	svc.Sessions.Delete(ctx, a.ID)
+	report, _ = svc.Sessions.UsageReport(ctx, UsageFilter{})
+	if len(report.Agents) != 1 || report.Agents[0].Agent != "@b" {
+		t.Errorf("after delete: %+v", report.Agents)
+	}
+}
@@ -75,3 +75,22 @@ func GetToolRequirementsForTools(tools []string) []ToolRequirementInfo {
 	}
 	return result
 }
+
+// GetToolsRequiringSkill returns requirement info for the tools that need
+// the named skill.
+func GetToolsRequiringSkill(name string) []ToolRequirementInfo {
+	var result []ToolRequirementInfo
+	for _, req := range toolRequirements {
+		for _, s := range req.RequiredSkills {
+			if s == name {
+				result = append(result, ToolRequirementInfo{
+					ToolName:       req.ToolName,
+					RequiredSkills: req.RequiredSkills,
+					Reason:         req.Reason,
+				})
+				break
+			}
+		}
+	}
+	return result
+}
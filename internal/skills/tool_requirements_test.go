@@ -136,3 +136,13 @@ func TestGetToolSkillRequirements(t *testing.T) {
 		t.Error("GetToolSkillRequirements() missing agent_call requirement")
 	}
 }
+
+func TestGetToolsRequiringSkill(t *testing.T) {
+	reqs := GetToolsRequiringSkill("agent-discovery")
+	if len(reqs) != 1 || reqs[0].ToolName != "agent_call" || reqs[0].Reason == "" {
+		t.Errorf("GetToolsRequiringSkill(agent-discovery) = %+v, want agent_call", reqs)
+	}
+	if reqs := GetToolsRequiringSkill("debugging"); len(reqs) != 0 {
+		t.Errorf("GetToolsRequiringSkill(debugging) = %+v, want none", reqs)
+	}
+}
@@ -23,9 +23,26 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
+// ValidateOptions enables checks against the skills and tools installed
+// alongside the skill being validated.
+type ValidateOptions struct {
+	// Discovered skills the name must not collide with. A discovered skill
+	// at the validated directory itself is not a collision.
+	Discovered []Metadata
+
+	// KnownTool reports whether a tool named in allowed-tools resolves.
+	// nil skips the check.
+	KnownTool func(name string) bool
+}
+
 // Validate checks a skill directory for compliance with the agentskills spec.
 // Returns a list of validation errors (empty if valid).
 func Validate(skillDir string) []ValidationError {
+	return ValidateWithOptions(skillDir, ValidateOptions{})
+}
+
+// ValidateWithOptions is Validate with the additional checks in opts.
+func ValidateWithOptions(skillDir string, opts ValidateOptions) []ValidationError {
 	var errors []ValidationError
 
 	// Check directory exists
@@ -76,6 +93,8 @@ func Validate(skillDir string) []ValidationError {
 	errors = append(errors, validateCompatibility(raw)...)
 	errors = append(errors, validateMetadataField(raw)...)
 	errors = append(errors, validateAllowedFields(raw)...)
+	errors = append(errors, validateAllowedTools(raw, opts.KnownTool)...)
+	errors = append(errors, validateUniqueName(raw, skillDir, opts.Discovered)...)
 
 	return errors
 }
@@ -229,3 +248,73 @@ func validateAllowedFields(raw map[string]interface{}) []ValidationError {
 
 	return errors
 }
+
+// validateAllowedTools checks that allowed-tools is a space-delimited string
+// or a list of strings, and that each tool resolves when known is set.
+func validateAllowedTools(raw map[string]interface{}, known func(string) bool) []ValidationError {
+	toolsAny, ok := raw["allowed-tools"]
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	switch v := toolsAny.(type) {
+	case string:
+		names = strings.Fields(v)
+	case []interface{}:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok || strings.TrimSpace(name) == "" {
+				return []ValidationError{{Field: "allowed-tools", Message: "list entries must be non-empty strings"}}
+			}
+			names = append(names, strings.TrimSpace(name))
+		}
+	default:
+		return []ValidationError{{Field: "allowed-tools", Message: "must be a string or a list of strings"}}
+	}
+
+	if known == nil {
+		return nil
+	}
+	var errors []ValidationError
+	for _, name := range names {
+		if !known(name) {
+			errors = append(errors, ValidationError{
+				Field:   "allowed-tools",
+				Message: fmt.Sprintf("unknown tool '%s' (not built in or provided by an enabled plugin)", name),
+			})
+		}
+	}
+	return errors
+}
+
+// validateUniqueName checks that no other discovered skill has the same
+// name, since only one of them would be loaded.
+func validateUniqueName(raw map[string]interface{}, skillDir string, discovered []Metadata) []ValidationError {
+	name, ok := raw["name"].(string)
+	if !ok {
+		return nil
+	}
+	name = strings.TrimSpace(name)
+
+	for _, other := range discovered {
+		if other.Name != name || sameDir(filepath.Dir(other.Path), skillDir) {
+			continue
+		}
+		return []ValidationError{{
+			Field:   "name",
+			Message: fmt.Sprintf("'%s' is already used by the %s skill at %s", name, other.Source, filepath.Dir(other.Path)),
+		}}
+	}
+	return nil
+}
+
+// sameDir reports whether a and b refer to the same directory.
+func sameDir(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(ai, bi)
+}
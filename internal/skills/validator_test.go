@@ -278,3 +278,65 @@ func TestValidationErrorString(t *testing.T) {
 		t.Errorf("unexpected error string: %s", err2.Error())
 	}
 }
+
+func TestValidateAllowedToolsResolve(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "tool-skill")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: tool-skill\ndescription: Needs tools\nallowed-tools:\n  - bash\n  - missing-tool\n---\nbody"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if errors := Validate(skillDir); len(errors) > 0 {
+		t.Fatalf("expected no errors without a tool check, got: %v", errors)
+	}
+
+	known := func(name string) bool { return name == "bash" }
+	errors := ValidateWithOptions(skillDir, ValidateOptions{KnownTool: known})
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+	}
+	if !containsHelper(errors[0].Message, "missing-tool") {
+		t.Errorf("expected unknown tool error, got: %s", errors[0].Message)
+	}
+}
+
+func TestValidateAllowedToolsType(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "tool-skill")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: tool-skill\ndescription: Needs tools\nallowed-tools: 3\n---\nbody"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	errors := Validate(skillDir)
+	if len(errors) != 1 || errors[0].Field != "allowed-tools" {
+		t.Fatalf("expected an allowed-tools error, got: %v", errors)
+	}
+}
+
+func TestValidateNameUniqueness(t *testing.T) {
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "draft", "my-skill")
+	mustWriteSkill(t, skillDir, "my-skill", "A valid skill description")
+	sharedDir := filepath.Join(root, "shared", "my-skill")
+	mustWriteSkill(t, sharedDir, "my-skill", "Another skill")
+
+	discovered := []Metadata{{Name: "my-skill", Path: filepath.Join(sharedDir, "SKILL.md"), Source: SourceUserShared}}
+	errors := ValidateWithOptions(skillDir, ValidateOptions{Discovered: discovered})
+	if len(errors) != 1 || !containsHelper(errors[0].Message, sharedDir) {
+		t.Fatalf("expected a name collision error, got: %v", errors)
+	}
+
+	// The validated skill itself is not a collision
+	errors = ValidateWithOptions(sharedDir, ValidateOptions{Discovered: discovered})
+	if len(errors) > 0 {
+		t.Errorf("expected no errors for the discovered skill itself, got: %v", errors)
+	}
+}
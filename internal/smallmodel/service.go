@@ -47,12 +47,48 @@ func (s *Service) IsAvailable(ctx context.Context) bool {
 }
 
 // MemoryExtraction represents the result of extracting memorable content.
+// A message may contain several memorable items; the first is mirrored in
+// Content/Category for callers that only handle one.
 type MemoryExtraction struct {
 	ShouldRemember bool   `json:"should_remember"`
 	Content        string `json:"content,omitempty"`
 	Category       string `json:"category,omitempty"` // preference, fact, correction
 	Confidence     float64 `json:"confidence,omitempty"`
 	Reason         string `json:"reason,omitempty"`
+
+	Memories []ExtractedMemory `json:"memories,omitempty"`
+}
+
+// ExtractedMemory is a single memorable item from a message.
+type ExtractedMemory struct {
+	Content    string  `json:"content"`
+	Category   string  `json:"category,omitempty"` // preference, fact, correction
+	Confidence float64 `json:"confidence,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// Items returns every memorable item in the extraction, in order.
+// Extractions in the single-item format yield one item.
+func (e *MemoryExtraction) Items() []ExtractedMemory {
+	if e == nil || !e.ShouldRemember {
+		return nil
+	}
+
+	var items []ExtractedMemory
+	for _, m := range e.Memories {
+		if strings.TrimSpace(m.Content) != "" {
+			items = append(items, m)
+		}
+	}
+	if len(items) == 0 && strings.TrimSpace(e.Content) != "" {
+		items = append(items, ExtractedMemory{
+			Content:    e.Content,
+			Category:   e.Category,
+			Confidence: e.Confidence,
+			Reason:     e.Reason,
+		})
+	}
+	return items
 }
 
 const memoryExtractionPrompt = `Analyze this user message and determine if it contains information worth remembering for future conversations.
@@ -64,9 +100,10 @@ Look for:
 - Corrections ("actually...", "no, I meant...", "that's wrong...", "I said...")
 
 If memorable, extract the CORE information in THIRD PERSON (e.g., "User prefers TypeScript" not "I prefer TypeScript").
+If the message contains several independent items, list each one separately.
 
 Respond with valid JSON only:
-{"should_remember": true/false, "content": "distilled memory in third person", "category": "preference|fact|correction", "confidence": 0.0-1.0, "reason": "why this is memorable"}
+{"should_remember": true/false, "memories": [{"content": "distilled memory in third person", "category": "preference|fact|correction", "confidence": 0.0-1.0, "reason": "why this is memorable"}]}
 
 User message: %s`
 
@@ -88,6 +125,14 @@ func (s *Service) ExtractMemory(ctx context.Context, userMessage string) (*Memor
 		return nil, fmt.Errorf("parse extraction: %w", err)
 	}
 
+	// Mirror the first item for single-item callers
+	if items := extraction.Items(); len(items) > 0 && extraction.Content == "" {
+		extraction.Content = items[0].Content
+		extraction.Category = items[0].Category
+		extraction.Confidence = items[0].Confidence
+		extraction.Reason = items[0].Reason
+	}
+
 	return &extraction, nil
 }
 
@@ -182,6 +227,124 @@ func (s *Service) GenerateTitle(ctx context.Context, firstMessage string) (strin
 	return title, nil
 }
 
+const summarizePrompt = `Summarize the earlier part of a conversation between a user and an assistant so the assistant can continue it without the full transcript.
+
+Keep:
+- Decisions made and their reasons
+- Facts, names, file paths, and values the user provided
+- Open questions and unfinished tasks
+
+Write at most 200 words of plain prose. Do not add anything that is not in the conversation.
+
+Conversation:
+%s
+
+Respond with just the summary.`
+
+// SummarizePrompt returns the prompt SummarizeConversation sends, for
+// summarizing with another model.
+func SummarizePrompt(transcript string) string {
+	return fmt.Sprintf(summarizePrompt, transcript)
+}
+
+// SummarizeConversation condenses a conversation transcript into a short
+// summary. The transcript may begin with the summary of even earlier turns.
+func (s *Service) SummarizeConversation(ctx context.Context, transcript string) (string, error) {
+	prompt := SummarizePrompt(transcript)
+
+	resp, err := s.client.Chat(ctx, s.model, []ollama.Message{
+		{Role: "user", Content: prompt},
+	}, &ollama.Options{
+		Temperature: 0.2,
+		NumPredict:  400,
+	})
+	if err != nil {
+		return "", fmt.Errorf("summarize conversation: %w", err)
+	}
+
+	return strings.TrimSpace(resp.Message.Content), nil
+}
+
+// MemoryRelevance is a judgment of how well a memory matches a query.
+type MemoryRelevance struct {
+	ID     string  `json:"id"`
+	Score  float64 `json:"score"`  // 0.0-1.0
+	Reason string  `json:"reason"` // One-line rationale
+}
+
+const relevancePrompt = `Judge how relevant each memory is to a search query.
+
+Query: %s
+
+Memories:
+%s
+
+For each memory, give a relevance score from 0.0 (unrelated) to 1.0 (directly answers the query) and a one-line reason explaining why it matches or doesn't.
+
+Respond with valid JSON only:
+{"results": [{"id": "memory id", "score": 0.0-1.0, "reason": "one line"}]}`
+
+// JudgeRelevance scores each candidate memory against a search query, with
+// a one-line reason per memory. Memories the model leaves out are omitted
+// from the result.
+func (s *Service) JudgeRelevance(ctx context.Context, query string, candidates []ExistingMemory) ([]MemoryRelevance, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var list strings.Builder
+	for _, m := range candidates {
+		fmt.Fprintf(&list, "- [%s] %s\n", shortID(m.ID), m.Content)
+	}
+
+	prompt := fmt.Sprintf(relevancePrompt, query, list.String())
+
+	result, err := s.client.ChatJSON(ctx, s.model, []ollama.Message{
+		{Role: "user", Content: prompt},
+	}, &ollama.Options{
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("judge relevance: %w", err)
+	}
+
+	var parsed struct {
+		Results []MemoryRelevance `json:"results"`
+	}
+	if err := json.Unmarshal(result, &parsed); err != nil {
+		return nil, fmt.Errorf("parse relevance: %w", err)
+	}
+
+	// Map short IDs back to the candidates, keeping the first judgment
+	var judged []MemoryRelevance
+	seen := make(map[string]bool)
+	for _, r := range parsed.Results {
+		id := strings.Trim(strings.TrimSpace(r.ID), "[]")
+		if id == "" {
+			continue
+		}
+		for _, m := range candidates {
+			if strings.HasPrefix(m.ID, id) && !seen[m.ID] {
+				seen[m.ID] = true
+				r.ID = m.ID
+				r.Score = min(max(r.Score, 0), 1)
+				r.Reason = strings.TrimSpace(r.Reason)
+				judged = append(judged, r)
+				break
+			}
+		}
+	}
+	return judged, nil
+}
+
+// shortID returns the first 8 characters of a memory ID.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 // Model returns the model name being used.
 func (s *Service) Model() string {
 	return s.model
@@ -147,3 +147,131 @@ func TestService_GenerateTitle(t *testing.T) {
 		t.Errorf("unexpected title: %s", title)
 	}
 }
+
+func TestService_SummarizeConversation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/chat" {
+			w.WriteHeader(http.StatusOK)
+			resp := map[string]any{
+				"model": "granite4:3b",
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": "  The user is migrating the billing service to Postgres.\n",
+				},
+				"done": true,
+			}
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{Host: server.URL})
+	summary, err := svc.SummarizeConversation(context.Background(), "user: we're moving billing to Postgres")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary != "The user is migrating the billing service to Postgres." {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+}
+
+func TestService_ExtractMemory_MultipleItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/chat" {
+			w.WriteHeader(http.StatusOK)
+			resp := map[string]any{
+				"model": "granite4:3b",
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": `{"should_remember": true, "memories": [{"content": "User prefers TypeScript", "category": "preference"}, {"content": "Project uses PostgreSQL", "category": "fact"}]}`,
+				},
+				"done": true,
+			}
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{Host: server.URL})
+	result, err := svc.ExtractMemory(context.Background(), "I prefer TypeScript and the project uses PostgreSQL")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items := result.Items()
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].Content != "User prefers TypeScript" || items[1].Content != "Project uses PostgreSQL" {
+		t.Errorf("items out of order: %+v", items)
+	}
+
+	// First item is mirrored for single-item callers
+	if result.Content != "User prefers TypeScript" || result.Category != "preference" {
+		t.Errorf("expected first item mirrored, got content=%q category=%q", result.Content, result.Category)
+	}
+}
+
+func TestMemoryExtraction_Items(t *testing.T) {
+	single := &MemoryExtraction{ShouldRemember: true, Content: "User likes Go", Category: "preference"}
+	if items := single.Items(); len(items) != 1 || items[0].Content != "User likes Go" {
+		t.Errorf("single-item extraction: got %+v", items)
+	}
+
+	none := &MemoryExtraction{ShouldRemember: false, Content: "ignored"}
+	if items := none.Items(); len(items) != 0 {
+		t.Errorf("expected no items when should_remember is false, got %+v", items)
+	}
+
+	blank := &MemoryExtraction{ShouldRemember: true, Memories: []ExtractedMemory{{Content: " "}, {Content: "User uses vim"}}}
+	if items := blank.Items(); len(items) != 1 || items[0].Content != "User uses vim" {
+		t.Errorf("expected blank items dropped, got %+v", items)
+	}
+}
+
+func TestService_JudgeRelevance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/chat" {
+			w.WriteHeader(http.StatusOK)
+			resp := map[string]any{
+				"model": "granite4:3b",
+				"message": map[string]string{
+					"role":    "assistant",
+					"content": `{"results": [{"id": "[bbbbbbbb]", "score": 1.4, "reason": " names the editor "}, {"id": "aaaaaaaa", "score": 0.2, "reason": "about languages"}, {"id": "bbbbbbbb", "score": 0.1}, {"id": "zzzzzzzz", "score": 0.9}]}`,
+				},
+				"done": true,
+			}
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{Host: server.URL})
+	judged, err := svc.JudgeRelevance(context.Background(), "which editor", []ExistingMemory{
+		{ID: "aaaaaaaa-1111", Content: "User prefers Go"},
+		{ID: "bbbbbbbb-2222", Content: "User uses vim"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []MemoryRelevance{
+		{ID: "bbbbbbbb-2222", Score: 1, Reason: "names the editor"},
+		{ID: "aaaaaaaa-1111", Score: 0.2, Reason: "about languages"},
+	}
+	if len(judged) != len(want) {
+		t.Fatalf("expected %d judgments, got %+v", len(want), judged)
+	}
+	for i := range want {
+		if judged[i] != want[i] {
+			t.Errorf("judgment %d = %+v, want %+v", i, judged[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package chat
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alexcabrera/ayo/internal/run"
+)
+
+// NewCommandApprover returns a run.CommandApprover that asks for approval in
+// the chat view of program.
+func NewCommandApprover(program *tea.Program) run.CommandApprover {
+	return func(ctx context.Context, command, description string) (bool, error) {
+		reply := make(chan bool, 1)
+		program.Send(CommandApprovalMsg{Command: command, Description: description, Reply: reply})
+		select {
+		case approved := <-reply:
+			return approved, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package chat
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alexcabrera/ayo/internal/ui"
+)
+
+// NewAsyncStatusHandler returns a runner async status callback that shows
+// memory formation decisions in the sidebar of program. Messages are sent
+// from a new goroutine so the runner never waits on the TUI.
+func NewAsyncStatusHandler(program *tea.Program) func(ui.AsyncStatusMsg) {
+	return func(msg ui.AsyncStatusMsg) {
+		if msg.Decision == nil {
+			return
+		}
+		go program.Send(MemoryFormationMsg{
+			Summary: msg.Decision.Summary(),
+			Action:  msg.Decision.Action,
+			Failed:  msg.Decision.Action == ui.MemoryActionFailed,
+		})
+	}
+}
@@ -4,6 +4,7 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/alexcabrera/ayo/internal/run"
 	"github.com/alexcabrera/ayo/internal/ui/chat/messages"
 	"github.com/alexcabrera/ayo/internal/ui/chat/panels"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // Global tick ID counter for ID-scoped tick messages
@@ -81,10 +83,16 @@ type Model struct {
 
 	// Tool/reasoning state
 	currentToolCall   *ToolCallStartMsg
+	approval          *CommandApprovalMsg // Bash command awaiting approval
 	toolCallTree      *messages.ToolCallTree // B.07: Tree-based tool rendering
 	reasoningBuffer   strings.Builder
 	thinkingStartTime time.Time
 
+	// Structured output state, while an output schema is being filled
+	structuredPartial     string
+	structuredAttempt     int
+	structuredMaxAttempts int
+
 	// Spinner animation
 	spinnerFrame   int
 	spinnerTick    bool
@@ -102,12 +110,22 @@ type Model struct {
 
 	// Focus state - true means textarea has focus, false means viewport
 	textareaFocused bool
+
+	// @mention autocomplete for agent handles in the input
+	mention mention
+
+	// Idle timeout while waiting for input
+	idle idle
+
+	// Tallies for the recap printed on exit
+	summary summary
 }
 
 // message represents a single message in the conversation.
 type message struct {
-	Role    string // "user" or "assistant"
-	Content string
+	Role        string // "user" or "assistant"
+	Content     string
+	Interrupted bool // Assistant response cut short by cancelling the turn
 }
 
 // KeyMap defines the keybindings for the chat.
@@ -176,7 +194,7 @@ func DefaultKeyMap() KeyMap {
 }
 
 // New creates a new chat model.
-func New(ag agent.Agent, sessionID string, sendFn SendMessageFunc) Model {
+func New(ag agent.Agent, sessionID string, sendFn SendMessageFunc, opts ...Option) Model {
 	ta := textarea.New()
 	ta.Placeholder = "Type a message..."
 	ta.Focus()
@@ -187,7 +205,7 @@ func New(ag agent.Agent, sessionID string, sendFn SendMessageFunc) Model {
 	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
 	ta.KeyMap.InsertNewline.SetEnabled(false) // We handle newlines ourselves
 
-	return Model{
+	m := Model{
 		agentHandle:     ag.Handle,
 		skillCount:      len(ag.Skills),
 		sessionID:       sessionID,
@@ -200,7 +218,13 @@ func New(ag agent.Agent, sessionID string, sendFn SendMessageFunc) Model {
 		state:           StateInput,
 		messages:        []message{},
 		textareaFocused: true, // Start with textarea focused
+		summary:         newSummary(),
 	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.idle.reset()
+	return m
 }
 
 // Init initializes the model.
@@ -212,6 +236,7 @@ func (m Model) Init() tea.Cmd {
 		tea.Tick(time.Millisecond*80, func(t time.Time) tea.Msg {
 			return tickMsg{id: id}
 		}),
+		m.idle.check(),
 	)
 }
 
@@ -268,8 +293,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleResize(), nil
 
 	case tea.KeyMsg:
+		// Any keystroke restarts the idle clock and clears its warning
+		warned := m.idle.warned
+		m.idle.reset()
+		if warned {
+			m.updateStatusBarHints()
+		}
 		return m.handleKey(msg)
 
+	case idleCheckMsg:
+		return m.handleIdleCheck()
+
 	case tickMsg:
 		// If this is the first tick or ID matches, process it
 		// The first tick from Init will have a new ID that we accept
@@ -293,12 +327,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case TextEndMsg:
 		return m.handleTextEnd()
 
+	case StructuredDeltaMsg:
+		return m.handleStructuredDelta(msg)
+
 	case ToolCallStartMsg:
 		return m.handleToolCallStart(msg)
 
 	case ToolCallResultMsg:
 		return m.handleToolCallResult(msg)
 
+	case CommandApprovalMsg:
+		m.approval = &msg
+		m.updateStatusBarHints()
+		m.updateViewportContent()
+		m.viewport.GotoBottom()
+		return m, nil
+
 	case SubAgentStartMsg:
 		return m.handleSubAgentStart(msg)
 
@@ -349,6 +393,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.sidebar.SetMemories(msg.Memories)
 		m.statusBar.SetMemoryCount(len(msg.Memories))
 		return m, nil
+
+	case MemoryFormationMsg:
+		m.sidebar.SetFormation(msg.Summary, msg.Failed)
+		m.summary.memories[msg.Action]++
+		return m, nil
 	}
 
 	// Update textarea if in input state and sidebar not focused
@@ -377,6 +426,9 @@ var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "
 // setState updates the state and refreshes status bar hints.
 func (m *Model) setState(state State) {
 	m.state = state
+	if state == StateInput {
+		m.idle.reset()
+	}
 	m.updateStatusBarHints()
 }
 
@@ -385,7 +437,9 @@ func (m *Model) updateStatusBarHints() {
 	var hints string
 	switch m.state {
 	case StateInput:
-		if m.textareaFocused {
+		if m.textareaFocused && m.mention.open() {
+			hints = "up/down select · tab insert · esc dismiss"
+		} else if m.textareaFocused {
 			// Show line indicator for multiline input
 			content := m.textarea.Value()
 			lineCount := strings.Count(content, "\n") + 1
@@ -400,8 +454,14 @@ func (m *Model) updateStatusBarHints() {
 		if m.sidebar.IsVisible() {
 			hints += " · ctrl+p plan · ctrl+m memory"
 		}
+		if m.idle.warned {
+			hints = m.idle.warning()
+		}
 	case StateWaiting, StateStreaming:
 		hints = "ctrl+c interrupt"
+		if m.approval != nil {
+			hints = "y run · n deny · ctrl+c interrupt"
+		}
 	}
 	m.statusBar.SetHints(hints)
 }
@@ -460,6 +520,23 @@ func (m Model) handleTextEnd() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleStructuredDelta shows structured output as it is generated. A new
+// attempt discards the previous attempt's partial object.
+func (m Model) handleStructuredDelta(msg StructuredDeltaMsg) (tea.Model, tea.Cmd) {
+	m.structuredAttempt = msg.Attempt
+	m.structuredMaxAttempts = msg.MaxAttempts
+	m.structuredPartial = msg.Partial
+	if msg.Partial == "" {
+		// Show the spinner with the active attempt
+		m.setState(StateWaiting)
+	} else {
+		m.setState(StateStreaming)
+	}
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+	return m, nil
+}
+
 // handleStreamEvent handles unified stream events from the ChannelWriter.
 // This dispatches to the appropriate handler based on event type.
 func (m Model) handleStreamEvent(event run.StreamEvent) (tea.Model, tea.Cmd) {
@@ -496,6 +573,18 @@ func (m Model) handleStreamEvent(event run.StreamEvent) (tea.Model, tea.Cmd) {
 			return m.handleToolCallStart(msg)
 		}
 
+	case run.EventToolInput:
+		if event.Call != nil {
+			return m.handleToolCallDelta(ToolCallStartMsg{
+				ID:          event.Call.ID,
+				Name:        event.Call.Name,
+				Description: event.Call.Description,
+				Command:     event.Call.Command,
+				Input:       event.Call.Input,
+				Partial:     true,
+			})
+		}
+
 	case run.EventToolResult:
 		if event.Result != nil {
 			msg := ToolCallResultMsg{
@@ -526,6 +615,25 @@ func (m Model) handleStreamEvent(event run.StreamEvent) (tea.Model, tea.Cmd) {
 		// Memory events are handled by the memory panel
 		return m, nil
 
+	case run.EventStructuredDelta:
+		return m.handleStructuredDelta(StructuredDeltaMsg{
+			Partial:     event.Delta,
+			Attempt:     event.Attempt,
+			MaxAttempts: event.MaxAttempts,
+		})
+
+	case run.EventUsage:
+		if event.Usage != nil {
+			m.statusBar.SetUsage(event.Usage.PromptTokens, event.Usage.CompletionTokens, event.Usage.CostUSD)
+		}
+		return m, nil
+
+	case run.EventStats:
+		if event.Stats != nil {
+			m.statusBar.SetStats(event.Stats.ToolCalls, event.Stats.ToolTime, event.Stats.Duration)
+		}
+		return m, nil
+
 	case run.EventError:
 		if event.Err != nil {
 			m.err = event.Err
@@ -537,8 +645,24 @@ func (m Model) handleStreamEvent(event run.StreamEvent) (tea.Model, tea.Cmd) {
 
 	case run.EventDone:
 		// Final response received - streaming is complete
+		m.approval = nil
 		m.textareaFocused = true
 		m.setState(StateInput)
+
+		// The last partial of a successful attempt is the validated output
+		if m.structuredPartial != "" && event.Err == nil {
+			m.messages = append(m.messages, message{
+				Role:    "assistant",
+				Content: "```json\n" + m.structuredPartial + "\n```",
+			})
+		}
+		m.structuredPartial = ""
+		m.structuredAttempt = 0
+		m.structuredMaxAttempts = 0
+
+		if errors.Is(event.Err, run.ErrInterrupted) {
+			m.markInterrupted(event.Response)
+		}
 		
 		// Render with glamour
 		m.updateViewportContent()
@@ -580,8 +704,19 @@ func (m Model) handleToolCallStart(msg ToolCallStartMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleToolCallDelta shows a tool call while the model writes its
+// arguments. It is replaced by the complete call in handleToolCallStart.
+func (m Model) handleToolCallDelta(msg ToolCallStartMsg) (tea.Model, tea.Cmd) {
+	m.currentToolCall = &msg
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+	return m, nil
+}
+
 // handleToolCallResult handles the completion of a tool call.
 func (m Model) handleToolCallResult(msg ToolCallResultMsg) (tea.Model, tea.Cmd) {
+	m.summary.tools[msg.Name]++
+
 	// Update ToolCallCmp in tree (B.07)
 	if cmp := m.toolCallTree.Get(msg.ID); cmp != nil {
 		result := messages.ToolResult{
@@ -707,8 +842,29 @@ func (m *Model) warmupGlamour() {
 
 // handleKey processes keyboard input.
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.approval != nil {
+		switch msg.String() {
+		case "y", "Y":
+			m.answerApproval(true)
+			return m, nil
+		case "n", "N", "esc":
+			m.answerApproval(false)
+			return m, nil
+		}
+	}
+
+	// The @mention popup takes navigation keys while it is open
+	if m.state == StateInput && m.textareaFocused && m.mention.open() && m.handleMentionKey(msg) {
+		m.updateStatusBarHints()
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keyMap.Quit):
+		// Interrupting denies a pending command
+		if m.approval != nil {
+			m.answerApproval(false)
+		}
 		if m.state == StateInput {
 			m.scrollbackContent = m.renderScrollback()
 			return m, tea.Quit
@@ -768,12 +924,22 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.state == StateInput && m.textareaFocused {
 		var cmd tea.Cmd
 		m.textarea, cmd = m.textarea.Update(msg)
+		m.updateMention()
+		m.updateStatusBarHints()
 		return m, cmd
 	}
 
 	return m, nil
 }
 
+// answerApproval replies to the pending command approval.
+func (m *Model) answerApproval(approved bool) {
+	m.approval.Reply <- approved
+	m.approval = nil
+	m.updateStatusBarHints()
+	m.updateViewportContent()
+}
+
 // sendMessage sends the current input to the agent.
 func (m Model) sendMessage() (tea.Model, tea.Cmd) {
 	text := strings.TrimSpace(m.textarea.Value())
@@ -822,10 +988,16 @@ func (m Model) sendMessage() (tea.Model, tea.Cmd) {
 
 // handleAgentResponse processes the agent's response.
 func (m Model) handleAgentResponse(msg AgentResponseMsg) (tea.Model, tea.Cmd) {
+	m.approval = nil // The turn ended before the user answered
 	m.setState(StateInput)
 	m.textareaFocused = true
 	m.cancelFn = nil
 
+	if errors.Is(msg.Err, run.ErrInterrupted) {
+		m.markInterrupted(msg.Response)
+		m.updateViewportContent()
+		return m, m.textarea.Focus()
+	}
 	if msg.Err != nil {
 		if msg.Err != context.Canceled {
 			m.err = msg.Err
@@ -885,6 +1057,9 @@ func (m *Model) updateViewportContent() {
 			content.WriteString(m.renderUserMessage(msg.Content))
 		case "assistant":
 			content.WriteString(m.renderAssistantMessage(msg.Content))
+			if msg.Interrupted {
+				content.WriteString("\n" + interruptedStyle().Render("interrupted"))
+			}
 		case "tool":
 			content.WriteString(m.renderToolMessage(msg.Content))
 		}
@@ -903,11 +1078,22 @@ func (m *Model) updateViewportContent() {
 		content.WriteString("\n")
 	}
 
+	// Add pending command approval if any
+	if m.approval != nil {
+		content.WriteString(m.renderApproval(*m.approval))
+		content.WriteString("\n")
+	}
+
 	// Add streaming content if any - use simple rendering during streaming for performance
 	if m.streamBuffer.Len() > 0 {
 		content.WriteString(m.renderStreamingMessage(m.streamBuffer.String()))
 	}
 
+	// Add partial structured output if any
+	if m.structuredPartial != "" {
+		content.WriteString(m.renderStructuredPartial(m.structuredPartial))
+	}
+
 	// Add waiting indicator
 	if m.state == StateWaiting && m.currentToolCall == nil && m.reasoningBuffer.Len() == 0 {
 		content.WriteString(m.renderWaiting())
@@ -919,7 +1105,7 @@ func (m *Model) updateViewportContent() {
 // renderUserMessage styles a user message.
 func (m Model) renderUserMessage(content string) string {
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#67e8f9")).
+		Foreground(shared.ColorSecondary).
 		Bold(true)
 
 	return labelStyle.Render("> ") + content
@@ -928,7 +1114,7 @@ func (m Model) renderUserMessage(content string) string {
 // renderAssistantMessage styles an assistant message.
 func (m *Model) renderAssistantMessage(content string) string {
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa")).
+		Foreground(shared.ColorPrimary).
 		Bold(true)
 
 	// Use glamour for markdown rendering
@@ -937,18 +1123,58 @@ func (m *Model) renderAssistantMessage(content string) string {
 	return labelStyle.Render(m.agentHandle) + "\n" + rendered
 }
 
+// interruptedStyle marks a response cut short by cancelling the turn.
+func interruptedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(shared.ColorMuted).Italic(true)
+}
+
+// markInterrupted flags the response of a cancelled turn. Text still in the
+// stream buffer becomes the last message; without streamed text, response is
+// added.
+func (m *Model) markInterrupted(response string) {
+	if m.streamBuffer.Len() > 0 {
+		m.messages = append(m.messages, message{Role: "assistant", Content: m.streamBuffer.String()})
+		m.streamBuffer.Reset()
+	}
+	if n := len(m.messages); n > 0 && m.messages[n-1].Role == "assistant" {
+		m.messages[n-1].Interrupted = true
+		return
+	}
+	if response != "" {
+		m.messages = append(m.messages, message{Role: "assistant", Content: response, Interrupted: true})
+	}
+}
+
 // renderStreamingMessage renders streaming content without glamour for performance.
 // Glamour rendering is deferred until the message is complete.
 func (m *Model) renderStreamingMessage(content string) string {
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa")).
+		Foreground(shared.ColorPrimary).
 		Bold(true)
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#e4e4e7"))
+		Foreground(shared.ColorText)
 
 	return labelStyle.Render(m.agentHandle) + "\n" + contentStyle.Render(content)
 }
 
+// renderStructuredPartial renders partial structured output as highlighted
+// JSON. The object is incomplete, so it is shown below the agent's reply
+// with the active attempt.
+func (m *Model) renderStructuredPartial(partial string) string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(shared.ColorPrimary).
+		Bold(true)
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(shared.ColorMuted).
+		Italic(true)
+
+	label := labelStyle.Render(m.agentHandle) + " " + mutedStyle.Render("formatting output")
+	if m.structuredAttempt > 1 {
+		label += mutedStyle.Render(fmt.Sprintf(" (attempt %d/%d)", m.structuredAttempt, m.structuredMaxAttempts))
+	}
+	return label + "\n" + m.renderMarkdown("```json\n"+partial+"\n```") + "\n"
+}
+
 // renderMarkdown renders markdown content using glamour with a cached renderer.
 func (m *Model) renderMarkdown(content string) string {
 	width := m.width - 4
@@ -980,7 +1206,7 @@ func (m *Model) renderMarkdown(content string) string {
 // renderToolMessage renders a completed tool call.
 func (m *Model) renderToolMessage(content string) string {
 	toolStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fbbf24"))
+		Foreground(shared.ColorTertiary)
 
 	return toolStyle.Render("  ") + m.renderMarkdown(content)
 }
@@ -988,15 +1214,15 @@ func (m *Model) renderToolMessage(content string) string {
 // renderToolInProgress renders a tool that is currently executing.
 func (m Model) renderToolInProgress(tc ToolCallStartMsg) string {
 	iconStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fbbf24")).
+		Foreground(shared.ColorTertiary).
 		Bold(true)
 	nameStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fbbf24")).
+		Foreground(shared.ColorTertiary).
 		Bold(true)
 	descStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9ca3af"))
+		Foreground(shared.ColorTextDim)
 	spinnerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa"))
+		Foreground(shared.ColorPrimary)
 
 	spinner := spinnerStyle.Render(spinnerFrames[m.spinnerFrame])
 
@@ -1008,7 +1234,7 @@ func (m Model) renderToolInProgress(tc ToolCallStartMsg) string {
 			descStyle.Render(tc.Description),
 			spinner)
 		if tc.Command != "" {
-			cmdStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+			cmdStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
 			line += "\n    " + cmdStyle.Render("$ "+tc.Command)
 		}
 	} else {
@@ -1016,18 +1242,51 @@ func (m Model) renderToolInProgress(tc ToolCallStartMsg) string {
 			iconStyle.Render("▶"),
 			nameStyle.Render(tc.Name),
 			spinner)
+		if tc.Partial && tc.Input != "" {
+			inputStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+			line += "\n    " + inputStyle.Render(streamingInputTail(tc.Input, m.viewport.Width-4))
+		}
 	}
 
 	return line
 }
 
+// streamingInputTail returns the end of streaming tool arguments on one
+// line, at most width characters wide, so the newest part stays in view.
+func streamingInputTail(input string, width int) string {
+	input = strings.Join(strings.Fields(input), " ")
+	runes := []rune(input)
+	if width < 4 || len(runes) <= width {
+		return input
+	}
+	return "..." + string(runes[len(runes)-width+3:])
+}
+
+// renderApproval renders a bash command awaiting approval.
+func (m Model) renderApproval(req CommandApprovalMsg) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(shared.ColorError).
+		Bold(true)
+	cmdStyle := lipgloss.NewStyle().
+		Foreground(shared.ColorText)
+	descStyle := lipgloss.NewStyle().
+		Foreground(shared.ColorTextDim)
+
+	line := "  " + titleStyle.Render("Run this command?") + " " + descStyle.Render("[y/n]")
+	line += "\n    " + cmdStyle.Render("$ "+req.Command)
+	if req.Description != "" {
+		line += "\n    " + descStyle.Render(req.Description)
+	}
+	return line
+}
+
 // renderReasoning renders thinking/reasoning content.
 func (m Model) renderReasoning(content string) string {
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6b7280")).
+		Foreground(shared.ColorMuted).
 		Italic(true)
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9ca3af")).
+		Foreground(shared.ColorTextDim).
 		Italic(true)
 
 	// Truncate reasoning to last few lines
@@ -1043,12 +1302,20 @@ func (m Model) renderReasoning(content string) string {
 // renderWaiting shows a waiting indicator.
 func (m Model) renderWaiting() string {
 	spinnerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa"))
+		Foreground(shared.ColorPrimary)
 	textStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6b7280")).
+		Foreground(shared.ColorMuted).
 		Italic(true)
 
-	return spinnerStyle.Render(spinnerFrames[m.spinnerFrame]) + " " + textStyle.Render("Thinking...")
+	label := "Thinking..."
+	switch {
+	case m.structuredAttempt > 1:
+		label = fmt.Sprintf("Formatting output (attempt %d/%d)...", m.structuredAttempt, m.structuredMaxAttempts)
+	case m.structuredAttempt == 1:
+		label = "Formatting output..."
+	}
+
+	return spinnerStyle.Render(spinnerFrames[m.spinnerFrame]) + " " + textStyle.Render(label)
 }
 
 // View renders the model.
@@ -1061,7 +1328,7 @@ func (m Model) View() string {
 	mainContent := lipgloss.JoinVertical(
 		lipgloss.Left,
 		m.headerView(),
-		m.viewport.View(),
+		m.overlayMention(m.viewport.View()),
 		m.inputView(),
 		m.footerView(),
 	)
@@ -1077,14 +1344,14 @@ func (m Model) View() string {
 // headerView renders the header bar.
 func (m Model) headerView() string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa")).
+		Foreground(shared.ColorPrimary).
 		Bold(true)
 
 	skillStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#fbbf24"))
+		Foreground(shared.ColorTertiary)
 
 	lineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#3f3f46"))
+		Foreground(shared.ColorSubtle)
 
 	title := titleStyle.Render(fmt.Sprintf("Chat with %s", m.agentHandle))
 
@@ -1112,9 +1379,9 @@ func (m Model) inputView() string {
 
 	// Show focused style when textarea has focus and we're in input state
 	if m.state == StateInput && m.textareaFocused {
-		inputStyle = inputStyle.BorderForeground(lipgloss.Color("#a78bfa"))
+		inputStyle = inputStyle.BorderForeground(shared.ColorPrimary)
 	} else {
-		inputStyle = inputStyle.BorderForeground(lipgloss.Color("#3f3f46"))
+		inputStyle = inputStyle.BorderForeground(shared.ColorSubtle)
 	}
 
 	return inputStyle.Width(m.width - 4).Render(m.textarea.View())
@@ -1139,6 +1406,10 @@ func (m Model) renderScrollback() string {
 		case "user":
 			sb.WriteString(fmt.Sprintf("> %s\n\n", msg.Content))
 		case "assistant":
+			if msg.Interrupted {
+				sb.WriteString(fmt.Sprintf("%s:\n%s\n[interrupted]\n\n", m.agentHandle, msg.Content))
+				continue
+			}
 			sb.WriteString(fmt.Sprintf("%s:\n%s\n\n", m.agentHandle, msg.Content))
 		}
 	}
@@ -1146,10 +1417,21 @@ func (m Model) renderScrollback() string {
 	sb.WriteString(strings.Repeat("─", 60))
 	sb.WriteString("\n")
 
+	turns := 0
+	for _, msg := range m.messages {
+		if msg.Role == "user" {
+			turns++
+		}
+	}
+	sb.WriteString(m.summary.render(turns, m.statusBar, time.Now()))
+
 	if m.sessionID != "" {
 		sb.WriteString(fmt.Sprintf("Session: %s\n", m.sessionID))
 		sb.WriteString(fmt.Sprintf("To review: ayo sessions show %s\n", m.sessionID))
 	}
+	if m.idle.exited {
+		sb.WriteString(fmt.Sprintf("Closed after %s without input\n", formatIdle(m.idle.timeout+idleGrace)))
+	}
 
 	return sb.String()
 }
@@ -1160,8 +1442,8 @@ func (m Model) ScrollbackContent() string {
 }
 
 // Run starts the chat TUI.
-func Run(ctx context.Context, ag agent.Agent, sessionID string, sendFn SendMessageFunc) (Result, string, error) {
-	model := New(ag, sessionID, sendFn)
+func Run(ctx context.Context, ag agent.Agent, sessionID string, sendFn SendMessageFunc, opts ...Option) (Result, string, error) {
+	model := New(ag, sessionID, sendFn, opts...)
 	model.ctx = ctx
 
 	p := tea.NewProgram(
@@ -1183,11 +1465,11 @@ func Run(ctx context.Context, ag agent.Agent, sessionID string, sendFn SendMessa
 // It creates an event channel, sets up the EventAggregator, and returns a ChannelWriter
 // that should be passed to the Runner.
 // This is the preferred way to run the TUI as it prevents tick chain disruption.
-func RunWithChannel(ctx context.Context, ag agent.Agent, sessionID string, sendFn SendMessageFunc) (*tea.Program, Model, *run.ChannelWriter) {
+func RunWithChannel(ctx context.Context, ag agent.Agent, sessionID string, sendFn SendMessageFunc, opts ...Option) (*tea.Program, Model, *run.ChannelWriter) {
 	// Create event channel with buffer to prevent blocking
 	eventChan := make(chan run.StreamEvent, 64)
 
-	model := New(ag, sessionID, sendFn)
+	model := New(ag, sessionID, sendFn, opts...)
 	model.ctx = ctx
 	model.eventChan = eventChan
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -546,3 +547,195 @@ func TestStatusBar_HasHints(t *testing.T) {
 		t.Error("view should contain hint about enter key")
 	}
 }
+
+func TestUpdate_ToolInputStreams(t *testing.T) {
+	ag := mockAgent("@test")
+	m := New(ag, "session-123", mockSendFn("", nil))
+	m = initModel(m, 100, 40)
+
+	model, _ := m.Update(run.StreamEvent{Type: run.EventToolInput, Call: &run.ToolCall{ID: "1", Name: "bash", Command: "go test ./inter"}})
+	m = model.(Model)
+	if !strings.Contains(m.viewport.View(), "$ go test ./inter") {
+		t.Errorf("viewport should show the partial command, got: %s", m.viewport.View())
+	}
+
+	model, _ = m.Update(run.StreamEvent{Type: run.EventToolInput, Call: &run.ToolCall{ID: "2", Name: "write", Input: `{"path": "notes.md", "content": "# No`}})
+	m = model.(Model)
+	if !strings.Contains(m.viewport.View(), `"content": "# No`) {
+		t.Errorf("viewport should show the partial arguments, got: %s", m.viewport.View())
+	}
+
+	// The complete call replaces the streaming one
+	model, _ = m.Update(run.StreamEvent{Type: run.EventToolStart, Call: &run.ToolCall{ID: "2", Name: "write", Input: `{"path": "notes.md", "content": "# Notes"}`}})
+	m = model.(Model)
+	if m.currentToolCall == nil || m.currentToolCall.Partial {
+		t.Errorf("currentToolCall = %+v, want the complete call", m.currentToolCall)
+	}
+}
+
+func TestStreamingInputTail(t *testing.T) {
+	if got := streamingInputTail("{\n  \"a\": 1}", 40); got != `{ "a": 1}` {
+		t.Errorf("short input = %q", got)
+	}
+	if got := streamingInputTail(strings.Repeat("x", 50)+"end", 10); got != "...xxxxend" {
+		t.Errorf("long input = %q", got)
+	}
+}
+
+func TestUpdate_StructuredDeltaMsg(t *testing.T) {
+	ag := mockAgent("@test")
+	m := New(ag, "session-123", mockSendFn("", nil))
+	m = initModel(m, 100, 40)
+
+	// A retry without streaming shows the attempt in the waiting indicator
+	model, _ := m.Update(StructuredDeltaMsg{Attempt: 2, MaxAttempts: 3})
+	m = model.(Model)
+	if m.state != StateWaiting {
+		t.Errorf("state should be StateWaiting without a partial, got %v", m.state)
+	}
+	if !strings.Contains(m.renderWaiting(), "attempt 2/3") {
+		t.Errorf("waiting indicator should show the attempt, got %q", m.renderWaiting())
+	}
+
+	model, _ = m.Update(run.StreamEvent{Type: run.EventStructuredDelta, Delta: "{\n  \"title\": \"Hel\"\n}", Attempt: 2, MaxAttempts: 3})
+	m = model.(Model)
+	if m.state != StateStreaming {
+		t.Errorf("state should be StateStreaming with a partial, got %v", m.state)
+	}
+	if !strings.Contains(m.viewport.View(), "Hel") {
+		t.Error("viewport should show the partial object")
+	}
+
+	model, _ = m.Update(run.StreamEvent{Type: run.EventDone})
+	m = model.(Model)
+	if len(m.messages) != 1 || !strings.Contains(m.messages[0].Content, `"title": "Hel"`) {
+		t.Errorf("final object should be kept as a message, got %+v", m.messages)
+	}
+	if m.structuredPartial != "" || m.structuredAttempt != 0 {
+		t.Error("structured state should be reset after EventDone")
+	}
+}
+
+func TestUpdate_DoneInterrupted(t *testing.T) {
+	ag := mockAgent("@test")
+	m := New(ag, "session-123", mockSendFn("", nil))
+	m = initModel(m, 100, 40)
+
+	// Cancelled mid-stream, before the text was completed
+	model, _ := m.Update(TextDeltaMsg{Delta: "Partial ans"})
+	m = model.(Model)
+	model, _ = m.Update(run.StreamEvent{Type: run.EventDone, Response: "Partial ans", Err: run.ErrInterrupted})
+	m = model.(Model)
+
+	if m.state != StateInput {
+		t.Errorf("state = %v, want StateInput", m.state)
+	}
+	if len(m.messages) != 1 {
+		t.Fatalf("messages count = %d, want 1", len(m.messages))
+	}
+	if !m.messages[0].Interrupted || m.messages[0].Content != "Partial ans" {
+		t.Errorf("message = %+v, want the partial text marked interrupted", m.messages[0])
+	}
+	if m.streamBuffer.Len() != 0 {
+		t.Error("streamBuffer should be reset")
+	}
+}
+
+func TestUpdate_CommandApproval(t *testing.T) {
+	ag := mockAgent("@test")
+	m := New(ag, "session-123", mockSendFn("", nil))
+	m = initModel(m, 100, 40)
+	m.state = StateWaiting
+
+	reply := make(chan bool, 1)
+	model, _ := m.Update(CommandApprovalMsg{Command: "rm -rf build", Description: "Clean build", Reply: reply})
+	m = model.(Model)
+	if m.approval == nil {
+		t.Fatal("approval should be pending")
+	}
+	if !strings.Contains(m.viewport.View(), "rm -rf build") {
+		t.Error("viewport should show the command awaiting approval")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = model.(Model)
+	if m.approval != nil {
+		t.Error("approval should be cleared after answering")
+	}
+	select {
+	case approved := <-reply:
+		if !approved {
+			t.Error("y should approve the command")
+		}
+	default:
+		t.Error("no reply sent")
+	}
+	if m.state != StateWaiting {
+		t.Errorf("state = %v, want StateWaiting", m.state)
+	}
+}
+
+func TestUpdate_IdleTimeout(t *testing.T) {
+	m := New(mockAgent("@test"), "sess-1", mockSendFn("", nil), WithIdleTimeout(time.Minute))
+	m = initModel(m, 100, 40)
+
+	// Not idle long enough yet
+	model, cmd := m.Update(idleCheckMsg{})
+	m = model.(Model)
+	if m.idle.warned || cmd == nil {
+		t.Fatalf("warned = %v, cmd = %v; want a rescheduled check", m.idle.warned, cmd)
+	}
+
+	m.idle.last = time.Now().Add(-time.Minute)
+	model, _ = m.Update(idleCheckMsg{})
+	m = model.(Model)
+	if !m.idle.warned || !strings.Contains(m.statusBar.hints, "closing in 1m") {
+		t.Fatalf("warned = %v, hints = %q; want the idle warning", m.idle.warned, m.statusBar.hints)
+	}
+
+	// A keystroke clears the warning
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m = model.(Model)
+	if m.idle.warned || strings.Contains(m.statusBar.hints, "closing") {
+		t.Fatalf("keystroke should clear the warning, hints = %q", m.statusBar.hints)
+	}
+
+	// Still idle after the warning exits with the scrollback
+	m.idle.warned = true
+	m.idle.last = time.Now().Add(-time.Minute - idleGrace)
+	model, cmd = m.Update(idleCheckMsg{})
+	m = model.(Model)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Fatal("expected the chat to quit")
+	}
+	if !strings.Contains(m.ScrollbackContent(), "Closed after 2m without input") {
+		t.Errorf("scrollback = %q", m.ScrollbackContent())
+	}
+}
+
+func TestUpdate_IdleTimeoutWaitsForTurn(t *testing.T) {
+	m := New(mockAgent("@test"), "", mockSendFn("", nil), WithIdleTimeout(time.Minute))
+	m = initModel(m, 100, 40)
+	m.setState(StateStreaming)
+	m.idle.last = time.Now().Add(-time.Hour)
+
+	model, cmd := m.Update(idleCheckMsg{})
+	m = model.(Model)
+	if m.idle.warned || m.idle.exited || cmd == nil {
+		t.Fatal("a turn in progress should not be interrupted")
+	}
+	if time.Since(m.idle.last) > time.Second {
+		t.Error("a turn in progress should restart the idle clock")
+	}
+}
+
+func TestIdleTimeoutDisabled(t *testing.T) {
+	m := New(mockAgent("@test"), "", mockSendFn("", nil))
+	if cmd := m.idle.check(); cmd != nil {
+		t.Error("no idle check should be scheduled without a timeout")
+	}
+	m.idle.last = time.Now().Add(-time.Hour)
+	if _, cmd := m.Update(idleCheckMsg{}); cmd != nil {
+		t.Error("a disabled timeout should not quit")
+	}
+}
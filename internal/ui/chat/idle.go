@@ -0,0 +1,97 @@
+package chat
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleGrace is how long the chat waits after the idle warning before
+// exiting.
+const idleGrace = time.Minute
+
+// WithIdleTimeout closes the chat after d without input. A warning is shown
+// first, and the chat exits if there is still no input idleGrace later.
+// Zero disables the timeout.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(m *Model) {
+		m.idle.timeout = d
+	}
+}
+
+// idle tracks time without input while the chat waits for the user. It only
+// counts in StateInput, so a running turn is never interrupted.
+type idle struct {
+	timeout time.Duration // Zero disables the timeout
+	last    time.Time     // Last keystroke, or the end of the last turn
+	warned  bool
+	exited  bool
+}
+
+// idleCheckMsg asks the model to check whether the idle timeout passed.
+type idleCheckMsg struct{}
+
+// reset restarts the idle clock.
+func (i *idle) reset() {
+	i.last = time.Now()
+	i.warned = false
+}
+
+// deadline is when the next warning or exit is due.
+func (i idle) deadline() time.Time {
+	if i.warned {
+		return i.last.Add(i.timeout + idleGrace)
+	}
+	return i.last.Add(i.timeout)
+}
+
+// check schedules the next idle check, or returns nil when the timeout is
+// disabled.
+func (i idle) check() tea.Cmd {
+	if i.timeout <= 0 {
+		return nil
+	}
+	wait := time.Until(i.deadline())
+	if wait <= 0 {
+		wait = time.Second
+	}
+	return tea.Tick(wait, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
+// warning is shown in the status bar once the chat has been idle for the
+// timeout.
+func (i idle) warning() string {
+	return fmt.Sprintf("idle for %s, closing in %s unless you type", formatIdle(i.timeout), formatIdle(idleGrace))
+}
+
+// handleIdleCheck warns, then exits with the scrollback, once the chat has
+// been idle long enough. Turns in progress restart the clock instead.
+func (m Model) handleIdleCheck() (tea.Model, tea.Cmd) {
+	if m.idle.timeout <= 0 {
+		return m, nil
+	}
+	if m.state != StateInput {
+		m.idle.reset()
+		return m, m.idle.check()
+	}
+	if time.Now().Before(m.idle.deadline()) {
+		return m, m.idle.check()
+	}
+	if !m.idle.warned {
+		m.idle.warned = true
+		m.updateStatusBarHints()
+		return m, m.idle.check()
+	}
+	m.idle.exited = true
+	m.scrollbackContent = m.renderScrollback()
+	return m, tea.Quit
+}
+
+// formatIdle formats d in whole minutes where possible.
+func formatIdle(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+	return d.String()
+}
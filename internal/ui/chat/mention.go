@@ -0,0 +1,207 @@
+package chat
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// maxMentionItems caps how many handles the @mention popup lists at once.
+const maxMentionItems = 5
+
+// Option configures a chat model.
+type Option func(*Model)
+
+// WithMentionHandles sets the agent handles offered when typing "@" in the
+// input.
+func WithMentionHandles(handles []string) Option {
+	return func(m *Model) {
+		m.mention.handles = handles
+	}
+}
+
+// mention is the @mention autocomplete for the input. It is open while the
+// cursor is at the end of an "@handle" token that matches a known handle.
+type mention struct {
+	handles  []string // Known agent handles, with "@"
+	query    string   // Text typed after "@"
+	matches  []string
+	selected int
+
+	// Position of the "@" whose popup was dismissed with esc
+	dismissed         bool
+	dismissedRow      int
+	dismissedStartCol int
+}
+
+// open reports whether the popup is showing.
+func (mn *mention) open() bool {
+	return len(mn.matches) > 0
+}
+
+// close hides the popup until a different "@" is typed.
+func (mn *mention) close(row, col int) {
+	mn.matches = nil
+	mn.dismissed = true
+	mn.dismissedRow = row
+	mn.dismissedStartCol = col
+}
+
+// mentionToken finds the "@handle" token ending at col in line. The "@" must
+// start the line or follow whitespace, so addresses like user@host don't
+// trigger completion.
+func mentionToken(line []rune, col int) (start int, query string, ok bool) {
+	if col > len(line) {
+		return 0, "", false
+	}
+	i := col
+	for i > 0 && isHandleRune(line[i-1]) {
+		i--
+	}
+	if i == 0 || line[i-1] != '@' {
+		return 0, "", false
+	}
+	start = i - 1
+	if start > 0 && !unicode.IsSpace(line[start-1]) {
+		return 0, "", false
+	}
+	return start, string(line[i:col]), true
+}
+
+// isHandleRune reports whether r can appear in an agent handle after "@".
+func isHandleRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' || r == '/'
+}
+
+// filterHandles returns the handles that start with "@"+query, ignoring case.
+func filterHandles(handles []string, query string) []string {
+	prefix := "@" + strings.ToLower(query)
+	var matches []string
+	for _, h := range handles {
+		if strings.HasPrefix(strings.ToLower(h), prefix) {
+			matches = append(matches, h)
+		}
+	}
+	return matches
+}
+
+// cursor returns the textarea cursor's row and rune column, and the text of
+// its line.
+func (m *Model) cursor() (row, col int, line []rune) {
+	row = m.textarea.Line()
+	lines := strings.Split(m.textarea.Value(), "\n")
+	if row < len(lines) {
+		line = []rune(lines[row])
+	}
+	info := m.textarea.LineInfo()
+	col = min(info.StartColumn+info.ColumnOffset, len(line))
+	return row, col, line
+}
+
+// updateMention reopens, refilters, or closes the popup after the input
+// changed.
+func (m *Model) updateMention() {
+	mn := &m.mention
+	if len(mn.handles) == 0 {
+		return
+	}
+
+	row, col, line := m.cursor()
+	start, query, ok := mentionToken(line, col)
+	if !ok {
+		mn.matches = nil
+		mn.dismissed = false
+		return
+	}
+	if mn.dismissed && mn.dismissedRow == row && mn.dismissedStartCol == start {
+		return
+	}
+	mn.dismissed = false
+
+	if query != mn.query || !mn.open() {
+		mn.selected = 0
+	}
+	mn.query = query
+	mn.matches = filterHandles(mn.handles, query)
+	mn.selected = min(mn.selected, max(len(mn.matches)-1, 0))
+}
+
+// handleMentionKey handles keys while the popup is open: up/down move the
+// selection, tab/enter insert it, and esc dismisses it. It returns false
+// for keys the popup doesn't use.
+func (m *Model) handleMentionKey(msg tea.KeyMsg) bool {
+	mn := &m.mention
+	switch msg.String() {
+	case "up", "ctrl+p":
+		mn.selected = (mn.selected - 1 + len(mn.matches)) % len(mn.matches)
+	case "down", "ctrl+n":
+		mn.selected = (mn.selected + 1) % len(mn.matches)
+	case "tab", "enter":
+		m.insertMention(mn.matches[mn.selected])
+	case "esc":
+		row, col, line := m.cursor()
+		start, _, _ := mentionToken(line, col)
+		mn.close(row, start)
+	default:
+		return false
+	}
+	return true
+}
+
+// insertMention replaces the typed "@query" with handle and a space.
+func (m *Model) insertMention(handle string) {
+	for range []rune(m.mention.query) {
+		m.textarea, _ = m.textarea.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m.textarea.InsertString(strings.TrimPrefix(handle, "@") + " ")
+	m.mention.matches = nil
+	m.mention.query = ""
+	m.updateTextareaHeight()
+}
+
+// mentionView renders the popup: up to maxMentionItems handles around the
+// selection.
+func (m Model) mentionView() []string {
+	mn := m.mention
+	if !mn.open() {
+		return nil
+	}
+
+	first := 0
+	if mn.selected >= maxMentionItems {
+		first = mn.selected - maxMentionItems + 1
+	}
+	last := min(first+maxMentionItems, len(mn.matches))
+
+	selectedStyle := lipgloss.NewStyle().Foreground(shared.ColorPrimary).Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+
+	var lines []string
+	for i := first; i < last; i++ {
+		if i == mn.selected {
+			lines = append(lines, "  "+selectedStyle.Render("> "+mn.matches[i]))
+		} else {
+			lines = append(lines, "  "+itemStyle.Render("  "+mn.matches[i]))
+		}
+	}
+	return lines
+}
+
+// overlayMention draws the popup over the bottom lines of the viewport, just
+// above the input.
+func (m Model) overlayMention(view string) string {
+	popup := m.mentionView()
+	if len(popup) == 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	if len(popup) > len(lines) {
+		popup = popup[len(popup)-len(lines):]
+	}
+	copy(lines[len(lines)-len(popup):], popup)
+	return strings.Join(lines, "\n")
+}
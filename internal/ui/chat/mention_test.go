@@ -0,0 +1,147 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeText sends each rune of s to the model as a key press.
+func typeText(m Model, s string) Model {
+	for _, r := range s {
+		var msg tea.KeyMsg
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		} else {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		}
+		model, _ := m.Update(msg)
+		m = model.(Model)
+	}
+	return m
+}
+
+func pressKey(m Model, t tea.KeyType) Model {
+	model, _ := m.Update(tea.KeyMsg{Type: t})
+	return model.(Model)
+}
+
+func mentionModel(t *testing.T) Model {
+	t.Helper()
+	m := New(mockAgent("@ayo"), "session-123", mockSendFn("", nil),
+		WithMentionHandles([]string{"@ayo", "@code-reviewer", "@crush", "@researcher"}))
+	return initModel(m, 100, 40)
+}
+
+func TestMentionToken(t *testing.T) {
+	tests := []struct {
+		line      string
+		col       int
+		wantStart int
+		wantQuery string
+		wantOK    bool
+	}{
+		{"@", 1, 0, "", true},
+		{"ask @cr", 7, 4, "cr", true},
+		{"ask @cr now", 7, 4, "cr", true},
+		{"mail me@host", 12, 0, "", false},
+		{"ask @cr now", 11, 0, "", false},
+		{"no mention", 10, 0, "", false},
+	}
+	for _, tt := range tests {
+		start, query, ok := mentionToken([]rune(tt.line), tt.col)
+		if ok != tt.wantOK || (ok && (start != tt.wantStart || query != tt.wantQuery)) {
+			t.Errorf("mentionToken(%q, %d) = %d, %q, %v; want %d, %q, %v",
+				tt.line, tt.col, start, query, ok, tt.wantStart, tt.wantQuery, tt.wantOK)
+		}
+	}
+}
+
+func TestMention_FiltersAndInserts(t *testing.T) {
+	m := mentionModel(t)
+
+	m = typeText(m, "ask @")
+	if got := len(m.mention.matches); got != 4 {
+		t.Fatalf("matches after @ = %d, want all 4 handles", got)
+	}
+
+	m = typeText(m, "CR")
+	if strings.Join(m.mention.matches, " ") != "@crush" {
+		t.Fatalf("matches after @CR = %v, want [@crush]", m.mention.matches)
+	}
+	if !strings.Contains(m.View(), "> @crush") {
+		t.Error("popup not shown in view")
+	}
+
+	m = pressKey(m, tea.KeyTab)
+	if got := m.textarea.Value(); got != "ask @crush " {
+		t.Errorf("input after tab = %q, want %q", got, "ask @crush ")
+	}
+	if m.mention.open() {
+		t.Error("popup still open after inserting")
+	}
+	if !m.textareaFocused {
+		t.Error("tab toggled focus instead of completing")
+	}
+}
+
+func TestMention_NavigateAndEnter(t *testing.T) {
+	m := mentionModel(t)
+
+	m = typeText(m, "@c")
+	m = pressKey(m, tea.KeyDown)
+	m = pressKey(m, tea.KeyEnter)
+
+	if got := m.textarea.Value(); got != "@crush " {
+		t.Errorf("input after down+enter = %q, want %q", got, "@crush ")
+	}
+	if len(m.messages) != 0 {
+		t.Error("enter sent the message instead of completing")
+	}
+}
+
+func TestMention_DismissOnSpaceAndEscape(t *testing.T) {
+	m := mentionModel(t)
+
+	m = typeText(m, "@re ")
+	if m.mention.open() {
+		t.Error("popup open after space")
+	}
+
+	m = typeText(m, "@r")
+	if !m.mention.open() {
+		t.Fatal("popup not open after @r")
+	}
+	m = pressKey(m, tea.KeyEsc)
+	if m.mention.open() {
+		t.Error("popup open after esc")
+	}
+	m = typeText(m, "e")
+	if m.mention.open() {
+		t.Error("dismissed popup reopened while typing the same mention")
+	}
+
+	m = typeText(m, " @")
+	if !m.mention.open() {
+		t.Error("popup not open for a new mention")
+	}
+}
+
+func TestMention_NotInsideWord(t *testing.T) {
+	m := mentionModel(t)
+
+	m = typeText(m, "user@a")
+	if m.mention.open() {
+		t.Error("popup opened inside a word")
+	}
+}
+
+func TestMention_NoHandles(t *testing.T) {
+	m := initModel(New(mockAgent("@ayo"), "session-123", mockSendFn("", nil)), 100, 40)
+
+	m = typeText(m, "@")
+	if m.mention.open() {
+		t.Error("popup opened without handles")
+	}
+}
@@ -12,6 +12,7 @@ type ToolCallStartMsg struct {
 	Command     string // For bash tools
 	Input       string // JSON input parameters
 	ParentID    string // For nested tool calls (B.08)
+	Partial     bool   // Arguments are still streaming; Input is incomplete
 }
 
 // ToolCallResultMsg indicates a tool has completed.
@@ -45,6 +46,15 @@ type TextDeltaMsg struct {
 // TextEndMsg indicates text streaming has completed.
 type TextEndMsg struct{}
 
+// StructuredDeltaMsg contains structured output as it is generated for an
+// agent with an output schema. Partial is empty when an attempt starts or
+// when the provider can't stream objects.
+type StructuredDeltaMsg struct {
+	Partial     string // Indented JSON of the object so far
+	Attempt     int
+	MaxAttempts int
+}
+
 // ErrorMsg indicates an error occurred.
 type ErrorMsg struct {
 	Error error
@@ -63,11 +73,27 @@ type SubAgentEndMsg struct {
 	Error    bool
 }
 
+// CommandApprovalMsg asks the user to approve a dangerous bash command.
+// The answer is sent on Reply.
+type CommandApprovalMsg struct {
+	Command     string
+	Description string
+	Reply       chan<- bool
+}
+
 // MemoryEventMsg indicates a memory operation.
 type MemoryEventMsg struct {
 	Type string // "created", "skipped", "superseded", "failed"
 }
 
+// MemoryFormationMsg explains what became of a memory extracted from the
+// conversation.
+type MemoryFormationMsg struct {
+	Summary string // e.g. `Already remembered as "uses pnpm" (97% similar)`
+	Action  string // One of the ui.MemoryAction constants
+	Failed  bool
+}
+
 // Cmd helpers for sending messages to the TUI from callbacks.
 
 // SendToolCallStart creates a command to signal tool start.
@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // MessageComponent defines the interface for message display components.
@@ -160,7 +162,7 @@ func (m *UserMessageCmp) Render(width int) string {
 
 	// Simple styled output for user messages
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7dd3fc")).
+		Foreground(shared.ColorSecondary).
 		Bold(true)
 
 	label := style.Render("You")
@@ -212,7 +214,7 @@ func (m *AssistantMessageCmp) Render(width int) string {
 
 	// Render label
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#a78bfa")).
+		Foreground(shared.ColorPrimary).
 		Bold(true)
 	label := labelStyle.Render(m.agentHandle)
 
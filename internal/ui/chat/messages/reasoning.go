@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // ReasoningCmp displays reasoning/thinking content with truncation and caching.
@@ -107,10 +109,10 @@ func (r *ReasoningCmp) Render(width int) string {
 
 	// Style the output
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6b7280")).
+		Foreground(shared.ColorMuted).
 		Italic(true)
 	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9ca3af")).
+		Foreground(shared.ColorTextDim).
 		Italic(true).
 		Width(width - 12). // Account for label
 		MaxWidth(width - 12)
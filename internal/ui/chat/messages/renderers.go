@@ -156,6 +156,9 @@ type AgentParams struct {
 	Prompt string `json:"prompt"`
 }
 
+// AgentCallResponseMetadata is an alias for shared.AgentCallResponseMetadata.
+type AgentCallResponseMetadata = shared.AgentCallResponseMetadata
+
 // Render displays agent call with nested tool calls using lipgloss/tree.
 func (ar agentRenderer) Render(t *toolCallCmp) string {
 	var params AgentParams
@@ -200,26 +203,68 @@ func (ar agentRenderer) Render(t *toolCallCmp) string {
 
 	// Add collapse indicator if there are nested calls
 	if len(t.nestedToolCalls) > 0 && !t.expanded {
-		collapseStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+		collapseStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
 		result = lipgloss.JoinVertical(lipgloss.Left, result, "",
 			collapseStyle.Render(fmt.Sprintf("  [%d nested tool calls collapsed]", len(t.nestedToolCalls))))
 	}
 
-	// Add result content when completed
+	// Add result content when completed; structured output is shown as JSON
 	if t.result.ToolCallID != "" && t.result.Content != "" {
-		body := renderMarkdownContent(t, t.result.Content, 10)
+		var meta AgentCallResponseMetadata
+		if t.result.Metadata != "" {
+			_ = ar.unmarshalParams(t.result.Metadata, &meta)
+		}
+
+		var body string
+		if meta.Structured {
+			body = RenderToolCallJSON(t, t.result.Content)
+		} else {
+			body = renderMarkdownContent(t, t.result.Content, 10)
+		}
 		result = joinHeaderBody(result, body)
 	}
 
 	return result
 }
 
+// httpRenderer handles http tool requests, showing the response as JSON.
+type httpRenderer struct {
+	baseRenderer
+}
+
+// HTTPParams represents http tool parameters.
+type HTTPParams struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Render displays the request line and the JSON response.
+func (hr httpRenderer) Render(t *toolCallCmp) string {
+	var params HTTPParams
+	_ = hr.unmarshalParams(t.call.Input, &params)
+
+	method := strings.ToUpper(params.Method)
+	if method == "" {
+		method = "GET"
+	}
+	args := newParamBuilder().addMain(method + " " + params.URL).build()
+
+	return hr.renderWithParams(t, "HTTP", args, func() string {
+		if t.result.Content == "" {
+			return ""
+		}
+		return RenderToolCallJSON(t, t.result.Content)
+	})
+}
+
 // init registers all built-in renderers.
 func init() {
 	registry.register("bash", func() renderer { return bashRenderer{} })
 	registry.register("todo", func() renderer { return todosRenderer{} })
 	registry.register("todos", func() renderer { return todosRenderer{} })
 	registry.register("agent", func() renderer { return agentRenderer{} })
+	registry.register("agent_call", func() renderer { return agentRenderer{} })
+	registry.register("http", func() renderer { return httpRenderer{} })
 }
 
 // RegisterRenderer allows external packages to register custom renderers.
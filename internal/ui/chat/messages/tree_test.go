@@ -285,3 +285,34 @@ func TestToolCallTree_RenderAsTree(t *testing.T) {
 		// Note: may not have branches if rendering differently
 	}
 }
+
+func TestAgentCallRendersStructuredResultAsJSON(t *testing.T) {
+	tc := ToolCall{ID: "tc1", Name: "agent_call", Input: `{"agent": "@summarizer", "prompt": "Summarize"}`, Finished: true}
+	cmp := NewToolCallCmp("msg1", tc, WithToolCallResult(ToolResult{
+		ToolCallID: "tc1",
+		Name:       "agent_call",
+		Content:    `{"title":"Hello","tags":["a"]}`,
+		Metadata:   `{"agent":"@summarizer","structured":true}`,
+	}))
+	cmp.SetSize(80, 0)
+
+	view := cmp.View()
+	if !strings.Contains(view, `"title": "Hello"`) {
+		t.Errorf("expected indented JSON in view, got:\n%s", view)
+	}
+}
+
+func TestHTTPRendersResponseAsJSON(t *testing.T) {
+	tc := ToolCall{ID: "tc1", Name: "http", Input: `{"url": "https://api.example.com/items"}`, Finished: true}
+	cmp := NewToolCallCmp("msg1", tc, WithToolCallResult(ToolResult{
+		ToolCallID: "tc1",
+		Name:       "http",
+		Content:    `{"status":200,"headers":{},"body":"ok"}`,
+	}))
+	cmp.SetSize(80, 0)
+
+	view := cmp.View()
+	if !strings.Contains(view, "GET https://api.example.com/items") || !strings.Contains(view, `"status": 200`) {
+		t.Errorf("expected request line and indented JSON in view, got:\n%s", view)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // MemoryItem represents a single memory in the memory panel.
@@ -24,6 +26,10 @@ type MemoryPanel struct {
 	height   int
 	visible  bool
 	focused  bool
+
+	// Outcome of the latest memory formation, shown under the header
+	formation       string
+	formationFailed bool
 }
 
 // NewMemoryPanel creates a new memory panel.
@@ -56,6 +62,13 @@ func (p *MemoryPanel) SetMemories(memories []MemoryItem) {
 	p.updateContent()
 }
 
+// SetFormation sets the outcome of the latest memory formation, such as
+// which existing memory an extracted one matched.
+func (p *MemoryPanel) SetFormation(summary string, failed bool) {
+	p.formation = summary
+	p.formationFailed = failed
+}
+
 // Toggle shows or hides the panel.
 func (p *MemoryPanel) Toggle() {
 	p.visible = !p.visible
@@ -98,14 +111,14 @@ func (p *MemoryPanel) View() string {
 	}
 
 	// Styles
-	borderColor := lipgloss.Color("#6b7280")
+	borderColor := shared.ColorMuted
 	if p.focused {
-		borderColor = lipgloss.Color("#a78bfa")
+		borderColor = shared.ColorPrimary
 	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#a78bfa"))
+		Foreground(shared.ColorPrimary)
 
 	containerStyle := lipgloss.NewStyle().
 		Width(p.width).
@@ -117,14 +130,28 @@ func (p *MemoryPanel) View() string {
 	// Header
 	title := titleStyle.Render("Memory")
 	count := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#6b7280")).
+		Foreground(shared.ColorMuted).
 		Render(fmt.Sprintf("%d", len(p.memories)))
 	header := lipgloss.JoinHorizontal(lipgloss.Left, title, "  ", count)
 
+	// The latest formation outcome takes the spacer line under the header
+	formation := ""
+	if p.formation != "" {
+		color := shared.ColorMuted
+		if p.formationFailed {
+			color = shared.ColorError
+		}
+		formation = lipgloss.NewStyle().
+			Foreground(color).
+			Italic(true).
+			MaxWidth(p.width - 4).
+			Render(p.formation)
+	}
+
 	// Content
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		header,
-		"",
+		formation,
 		p.viewport.View(),
 	)
 
@@ -135,7 +162,7 @@ func (p *MemoryPanel) View() string {
 func (p *MemoryPanel) updateContent() {
 	if len(p.memories) == 0 {
 		p.viewport.SetContent(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6b7280")).
+			Foreground(shared.ColorMuted).
 			Render("No relevant memories"))
 		return
 	}
@@ -159,25 +186,25 @@ func (p *MemoryPanel) renderMemoryItem(mem MemoryItem) string {
 	switch mem.Category {
 	case "preference":
 		icon = "★"
-		iconColor = lipgloss.Color("#f59e0b") // amber
+		iconColor = shared.ColorTertiary
 	case "fact":
 		icon = "◆"
-		iconColor = lipgloss.Color("#3b82f6") // blue
+		iconColor = shared.ColorInfo
 	case "correction":
 		icon = "!"
-		iconColor = lipgloss.Color("#ef4444") // red
+		iconColor = shared.ColorError
 	case "pattern":
 		icon = "~"
-		iconColor = lipgloss.Color("#22c55e") // green
+		iconColor = shared.ColorSuccess
 	default:
 		icon = "·"
-		iconColor = lipgloss.Color("#6b7280") // gray
+		iconColor = shared.ColorMuted
 	}
 
 	iconStyled := lipgloss.NewStyle().Foreground(iconColor).Render(icon)
 
 	// Content
-	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#e5e7eb"))
+	textStyle := lipgloss.NewStyle().Foreground(shared.ColorText)
 
 	// Truncate content if needed
 	content := mem.Content
@@ -190,7 +217,7 @@ func (p *MemoryPanel) renderMemoryItem(mem MemoryItem) string {
 	scopeBadge := ""
 	if mem.Scope != "" && mem.Scope != "global" {
 		scopeStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6b7280")).
+			Foreground(shared.ColorMuted).
 			Italic(true)
 		scopeBadge = scopeStyle.Render(" [" + mem.Scope + "]")
 	}
@@ -219,3 +219,22 @@ func TestMemoryPanel_Scroll(t *testing.T) {
 	p.ScrollDown(5)
 	p.ScrollUp(2)
 }
+
+func TestMemoryPanel_SetFormation(t *testing.T) {
+	p := NewMemoryPanel()
+	p.SetSize(60, 10)
+	p.Show()
+
+	before := p.View()
+	if strings.Contains(before, "Already remembered") {
+		t.Fatal("Expected no formation line before SetFormation")
+	}
+	p.SetFormation(`Already remembered as "uses pnpm"`, false)
+	view := p.View()
+	if !strings.Contains(view, `Already remembered as "uses pnpm"`) {
+		t.Errorf("Expected the formation summary in the view, got:\n%s", view)
+	}
+	if strings.Count(view, "\n") != strings.Count(before, "\n") {
+		t.Error("Expected the formation line not to change the panel height")
+	}
+}
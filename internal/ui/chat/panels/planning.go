@@ -7,6 +7,8 @@ import (
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // TodoItem represents a single task in the planning panel.
@@ -98,14 +100,14 @@ func (p *PlanningPanel) View() string {
 	}
 
 	// Styles
-	borderColor := lipgloss.Color("#6b7280")
+	borderColor := shared.ColorMuted
 	if p.focused {
-		borderColor = lipgloss.Color("#a78bfa")
+		borderColor = shared.ColorPrimary
 	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#a78bfa"))
+		Foreground(shared.ColorPrimary)
 
 	containerStyle := lipgloss.NewStyle().
 		Width(p.width).
@@ -133,7 +135,7 @@ func (p *PlanningPanel) View() string {
 func (p *PlanningPanel) updateContent() {
 	if len(p.todos) == 0 {
 		p.viewport.SetContent(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6b7280")).
+			Foreground(shared.ColorMuted).
 			Render("No tasks"))
 		return
 	}
@@ -155,14 +157,14 @@ func (p *PlanningPanel) renderTodoItem(todo TodoItem) string {
 
 	switch todo.Status {
 	case "completed":
-		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e")).Render("✓")
-		textStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280")).Strikethrough(true)
+		icon = lipgloss.NewStyle().Foreground(shared.ColorSuccess).Render("✓")
+		textStyle = lipgloss.NewStyle().Foreground(shared.ColorMuted).Strikethrough(true)
 	case "in_progress":
-		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("#3b82f6")).Render("▸")
-		textStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#e5e7eb"))
+		icon = lipgloss.NewStyle().Foreground(shared.ColorInfo).Render("▸")
+		textStyle = lipgloss.NewStyle().Foreground(shared.ColorText)
 	default: // pending
-		icon = lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280")).Render("○")
-		textStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#9ca3af"))
+		icon = lipgloss.NewStyle().Foreground(shared.ColorMuted).Render("○")
+		textStyle = lipgloss.NewStyle().Foreground(shared.ColorTextDim)
 	}
 
 	// Use active form if in progress, otherwise use content
@@ -196,7 +198,7 @@ func (p *PlanningPanel) renderStats() string {
 		}
 	}
 
-	statsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
+	statsStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
 	return statsStyle.Render(fmt.Sprintf("%d/%d", completed, len(p.todos)))
 }
 
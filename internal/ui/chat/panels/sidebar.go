@@ -4,6 +4,8 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
 // Position indicates where the sidebar is placed.
@@ -206,6 +208,11 @@ func (s *Sidebar) SetMemories(memories []MemoryItem) {
 	s.memory.SetMemories(memories)
 }
 
+// SetFormation shows the outcome of the latest memory formation.
+func (s *Sidebar) SetFormation(summary string, failed bool) {
+	s.memory.SetFormation(summary, failed)
+}
+
 // Update handles sidebar-related messages.
 func (s *Sidebar) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
@@ -293,7 +300,7 @@ func (s *Sidebar) RenderWithContent(content string, termWidth, termHeight int) s
 	if s.position == PositionRight {
 		// Side by side
 		separator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#374151")).
+			Foreground(shared.ColorSubtle).
 			Render("│")
 
 		// Ensure content fills available width
@@ -309,7 +316,7 @@ func (s *Sidebar) RenderWithContent(content string, termWidth, termHeight int) s
 
 	// Stacked vertically
 	separator := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#374151")).
+		Foreground(shared.ColorSubtle).
 		Width(termWidth).
 		Render("─")
 
@@ -3,11 +3,14 @@ package chat
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
 )
 
-// StatusBar displays memory count, task progress, and keyboard hints.
+// StatusBar displays memory count, task progress, usage, and keyboard hints.
 type StatusBar struct {
 	width int
 
@@ -19,6 +22,19 @@ type StatusBar struct {
 	completedTasks int
 	totalTasks    int
 
+	// Usage state, shown once the runner reports usage
+	hasUsage         bool
+	promptTokens     int64
+	completionTokens int64
+	costUSD          float64
+
+	// Run stats, shown with WithStats once a turn finishes
+	showStats bool
+	hasStats  bool
+	toolCalls int
+	toolTime  time.Duration
+	runTime   time.Duration
+
 	// Keyboard hints based on current focus
 	hints string
 }
@@ -47,6 +63,31 @@ func (s *StatusBar) SetTaskProgress(current string, completed, total int) {
 	s.totalTasks = total
 }
 
+// SetUsage updates the session token count and cost display.
+func (s *StatusBar) SetUsage(promptTokens, completionTokens int64, costUSD float64) {
+	s.hasUsage = true
+	s.promptTokens = promptTokens
+	s.completionTokens = completionTokens
+	s.costUSD = costUSD
+}
+
+// WithStats shows the number of tool calls and the share of time spent in
+// tools in the status bar, as ayo --stats does after the run.
+func WithStats() Option {
+	return func(m *Model) {
+		m.statusBar.showStats = true
+	}
+}
+
+// SetStats updates the tool call count and the time spent in tools out of
+// the time of all turns. It is shown only with WithStats.
+func (s *StatusBar) SetStats(toolCalls int, toolTime, runTime time.Duration) {
+	s.hasStats = true
+	s.toolCalls = toolCalls
+	s.toolTime = toolTime
+	s.runTime = runTime
+}
+
 // SetHints updates the keyboard hints.
 func (s *StatusBar) SetHints(hints string) {
 	s.hints = hints
@@ -58,8 +99,8 @@ func (s *StatusBar) Render() string {
 		s.width = 80
 	}
 
-	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#6b7280"))
-	highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#a78bfa"))
+	style := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	highlightStyle := lipgloss.NewStyle().Foreground(shared.ColorPrimary)
 
 	var parts []string
 
@@ -72,11 +113,11 @@ func (s *StatusBar) Render() string {
 
 	// Task progress
 	if s.totalTasks > 0 {
-		progressStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#3b82f6"))
+		progressStyle := lipgloss.NewStyle().Foreground(shared.ColorInfo)
 		progress := progressStyle.Render(fmt.Sprintf("%d/%d", s.completedTasks, s.totalTasks))
 
 		if s.currentTask != "" {
-			taskStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e"))
+			taskStyle := lipgloss.NewStyle().Foreground(shared.ColorSuccess)
 			arrow := taskStyle.Render("▸")
 			task := style.Render(" " + s.truncateTask(s.currentTask, 30))
 			parts = append(parts, progress+" "+arrow+task)
@@ -85,6 +126,24 @@ func (s *StatusBar) Render() string {
 		}
 	}
 
+	// Usage, with cost only when the provider publishes pricing
+	if s.hasUsage {
+		usage := formatTokens(s.promptTokens+s.completionTokens) + " tokens"
+		if s.costUSD > 0 {
+			usage += fmt.Sprintf(" · $%.3f", s.costUSD)
+		}
+		parts = append(parts, style.Render(usage))
+	}
+
+	// Tool calls and where the time went
+	if s.showStats && s.hasStats {
+		stats := fmt.Sprintf("%d tool calls", s.toolCalls)
+		if s.runTime > 0 {
+			stats += fmt.Sprintf(" · %.0f%% in tools", 100*s.toolTime.Seconds()/s.runTime.Seconds())
+		}
+		parts = append(parts, style.Render(stats))
+	}
+
 	left := strings.Join(parts, " · ")
 
 	// Right side: hints
@@ -112,6 +171,18 @@ func (s *StatusBar) truncateTask(task string, maxLen int) string {
 	return task[:maxLen-3] + "..."
 }
 
+// formatTokens abbreviates large token counts, e.g. 12345 as "12.3k".
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
 // Update processes status bar messages.
 func (s *StatusBar) Update(msg interface{}) {
 	switch m := msg.(type) {
@@ -119,6 +190,8 @@ func (s *StatusBar) Update(msg interface{}) {
 		s.SetMemoryCount(m.Count)
 	case TaskProgressMsg:
 		s.SetTaskProgress(m.Current, m.Completed, m.Total)
+	case UsageMsg:
+		s.SetUsage(m.PromptTokens, m.CompletionTokens, m.CostUSD)
 	case HintsMsg:
 		s.SetHints(m.Hints)
 	}
@@ -136,6 +209,13 @@ type TaskProgressMsg struct {
 	Total     int
 }
 
+// UsageMsg updates session token usage and cost.
+type UsageMsg struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	CostUSD          float64
+}
+
 // HintsMsg updates keyboard hints.
 type HintsMsg struct {
 	Hints string
@@ -3,6 +3,7 @@ package chat
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewStatusBar(t *testing.T) {
@@ -83,6 +84,43 @@ func TestStatusBar_SetTaskProgress_ZeroTotal(t *testing.T) {
 	}
 }
 
+func TestStatusBar_SetUsage(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(120)
+	sb.SetUsage(12000, 345, 0.0421)
+
+	rendered := sb.Render()
+	if !strings.Contains(rendered, "12.3k tokens") {
+		t.Errorf("render should contain token count, got: %s", rendered)
+	}
+	if !strings.Contains(rendered, "$0.042") {
+		t.Errorf("render should contain cost, got: %s", rendered)
+	}
+}
+
+func TestStatusBar_SetUsage_NoPricing(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(120)
+	sb.SetUsage(800, 50, 0)
+
+	rendered := sb.Render()
+	if !strings.Contains(rendered, "850 tokens") {
+		t.Errorf("render should contain token count, got: %s", rendered)
+	}
+	if strings.Contains(rendered, "$") {
+		t.Errorf("render should not show cost without pricing, got: %s", rendered)
+	}
+}
+
+func TestStatusBar_NoUsage(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(120)
+
+	if strings.Contains(sb.Render(), "tokens") {
+		t.Error("render should not show usage before any is reported")
+	}
+}
+
 func TestStatusBar_SetHints(t *testing.T) {
 	sb := NewStatusBar()
 	sb.SetWidth(100)
@@ -149,6 +187,17 @@ func TestStatusBar_Update_TaskProgressMsg(t *testing.T) {
 	}
 }
 
+func TestStatusBar_Update_UsageMsg(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(100)
+
+	sb.Update(UsageMsg{PromptTokens: 100, CompletionTokens: 20, CostUSD: 0.5})
+
+	if !sb.hasUsage || sb.promptTokens != 100 || sb.completionTokens != 20 || sb.costUSD != 0.5 {
+		t.Errorf("usage not applied: %+v", sb)
+	}
+}
+
 func TestStatusBar_Update_HintsMsg(t *testing.T) {
 	sb := NewStatusBar()
 	sb.SetWidth(100)
@@ -222,3 +271,17 @@ func TestStatusBar_CombinedState(t *testing.T) {
 		t.Error("missing hints")
 	}
 }
+
+func TestStatusBar_SetStats(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(120)
+	sb.SetStats(3, time.Second, 4*time.Second)
+	if strings.Contains(sb.Render(), "tool calls") {
+		t.Error("render should not show stats without WithStats")
+	}
+
+	sb.showStats = true
+	if rendered := sb.Render(); !strings.Contains(rendered, "3 tool calls · 25% in tools") {
+		t.Errorf("render should contain stats, got: %s", rendered)
+	}
+}
@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/alexcabrera/ayo/internal/ui"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// summary tallies the chat for the recap printed with the scrollback on
+// exit. The maps are shared by copies of the model, like the status bar.
+type summary struct {
+	started  time.Time
+	tools    map[string]int // Finished tool calls by tool name
+	memories map[string]int // Memory formation outcomes by MemoryAction
+}
+
+func newSummary() summary {
+	return summary{
+		started:  time.Now(),
+		tools:    make(map[string]int),
+		memories: make(map[string]int),
+	}
+}
+
+// render returns the recap: turns and duration, then tools, memories, and
+// usage when there are any. Labels are styled with lipgloss, so NO_COLOR and
+// output that isn't a terminal get plain text.
+func (s summary) render(turns int, status *StatusBar, now time.Time) string {
+	labelStyle := lipgloss.NewStyle().Foreground(shared.ColorMuted)
+	var sb strings.Builder
+	line := func(label, value string) {
+		sb.WriteString(labelStyle.Render(fmt.Sprintf("%-10s", label)))
+		sb.WriteString(value)
+		sb.WriteString("\n")
+	}
+
+	line("Turns", fmt.Sprintf("%d in %s", turns, now.Sub(s.started).Round(time.Second)))
+
+	if len(s.tools) > 0 {
+		names := make([]string, 0, len(s.tools))
+		calls := 0
+		for name, n := range s.tools {
+			names = append(names, name)
+			calls += n
+		}
+		slices.SortFunc(names, func(a, b string) int {
+			return cmp.Or(cmp.Compare(s.tools[b], s.tools[a]), strings.Compare(a, b))
+		})
+		counts := make([]string, len(names))
+		for i, name := range names {
+			counts[i] = fmt.Sprintf("%s %d", name, s.tools[name])
+		}
+		noun := "calls"
+		if calls == 1 {
+			noun = "call"
+		}
+		line("Tools", fmt.Sprintf("%d %s: %s", calls, noun, strings.Join(counts, ", ")))
+	}
+
+	var memories []string
+	if n := s.memories[ui.MemoryActionCreated]; n > 0 {
+		memories = append(memories, fmt.Sprintf("%d formed", n))
+	}
+	if n := s.memories[ui.MemoryActionSuperseded]; n > 0 {
+		memories = append(memories, fmt.Sprintf("%d updated", n))
+	}
+	if n := s.memories[ui.MemoryActionProposed]; n > 0 {
+		memories = append(memories, fmt.Sprintf("%d proposed for review", n))
+	}
+	if len(memories) > 0 {
+		line("Memories", strings.Join(memories, ", "))
+	}
+
+	// Cost only when the provider publishes pricing, as in the status bar
+	if status != nil && status.hasUsage {
+		usage := fmt.Sprintf("%s (%s in, %s out)", formatTokens(status.promptTokens+status.completionTokens),
+			formatTokens(status.promptTokens), formatTokens(status.completionTokens))
+		if status.costUSD > 0 {
+			usage += fmt.Sprintf(" · $%.3f", status.costUSD)
+		}
+		line("Tokens", usage)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexcabrera/ayo/internal/ui"
+)
+
+func TestSummaryRender(t *testing.T) {
+	s := newSummary()
+	status := NewStatusBar()
+	now := s.started.Add(4*time.Minute + 12*time.Second)
+
+	// A chat without tools, memories, or usage only reports turns
+	got := s.render(0, status, now)
+	if !strings.Contains(got, "0 in 4m12s") || strings.Count(got, "\n") != 1 {
+		t.Errorf("empty summary = %q", got)
+	}
+
+	s.tools["view"]++
+	s.tools["bash"] += 3
+	s.tools["edit"]++
+	s.memories[ui.MemoryActionCreated] += 2
+	s.memories[ui.MemoryActionSkipped]++
+	s.memories[ui.MemoryActionProposed]++
+	status.SetUsage(10_100, 2_200, 0.0421)
+
+	got = s.render(3, status, now)
+	for _, want := range []string{
+		"3 in 4m12s",
+		"5 calls: bash 3, edit 1, view 1",
+		"2 formed, 1 proposed for review",
+		"12.3k (10.1k in, 2.2k out) · $0.042",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestScrollbackSummary(t *testing.T) {
+	m := New(mockAgent("@test"), "sess-1", mockSendFn("", nil))
+	m = initModel(m, 100, 40)
+	m.messages = []message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+		{Role: "user", Content: "list files"},
+		{Role: "assistant", Content: "done"},
+	}
+
+	model, _ := m.Update(ToolCallResultMsg{ID: "1", Name: "bash"})
+	m = model.(Model)
+	model, _ = m.Update(MemoryFormationMsg{Summary: "Remembered", Action: ui.MemoryActionCreated})
+	m = model.(Model)
+
+	got := m.renderScrollback()
+	for _, want := range []string{"2 in ", "1 call: bash 1", "1 formed", "Session: sess-1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("scrollback missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Tokens") {
+		t.Errorf("scrollback shows usage without any:\n%s", got)
+	}
+}
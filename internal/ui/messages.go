@@ -1,5 +1,7 @@
 package ui
 
+import "fmt"
+
 // AsyncOperation identifies the type of async operation
 type AsyncOperation int
 
@@ -8,6 +10,9 @@ const (
 	AsyncOpMemoryStore AsyncOperation = iota
 	// AsyncOpMemoryEmbed is for generating embeddings
 	AsyncOpMemoryEmbed
+	// AsyncOpMemoryFormation is for deciding what to do with a memory
+	// extracted from a conversation
+	AsyncOpMemoryFormation
 )
 
 func (op AsyncOperation) String() string {
@@ -16,6 +21,8 @@ func (op AsyncOperation) String() string {
 		return "memory store"
 	case AsyncOpMemoryEmbed:
 		return "memory embed"
+	case AsyncOpMemoryFormation:
+		return "memory formation"
 	default:
 		return "unknown"
 	}
@@ -52,8 +59,65 @@ func (s AsyncStatus) String() string {
 
 // AsyncStatusMsg is sent to the UI when an async operation status changes
 type AsyncStatusMsg struct {
-	ID        string         // Unique ID for this operation
-	Operation AsyncOperation // Type of operation
-	Status    AsyncStatus    // Current status
-	Message   string         // Human-readable message (e.g., "Storing memory..." or error text)
+	ID        string          // Unique ID for this operation
+	Operation AsyncOperation  // Type of operation
+	Status    AsyncStatus     // Current status
+	Message   string          // Human-readable message (e.g., "Storing memory..." or error text)
+	Decision  *MemoryDecision // Why a memory was or wasn't formed; nil for other operations
+}
+
+// Memory formation actions reported in MemoryDecision.
+const (
+	MemoryActionCreated    = "created"
+	MemoryActionSkipped    = "skipped"
+	MemoryActionSuperseded = "superseded"
+	MemoryActionFailed     = "failed"
+	MemoryActionProposed   = "proposed"
+)
+
+// MemoryDecision explains the outcome of forming one extracted memory.
+type MemoryDecision struct {
+	Content       string  // The extracted memory
+	Category      string  // "preference", "fact", "correction", or "pattern"
+	Action        string  // One of the MemoryAction constants
+	MemoryID      string  // The memory created, the one replacing the target, or the proposal
+	TargetID      string  // Existing memory matched (skipped) or replaced (superseded)
+	TargetContent string  // Content of the target memory
+	Similarity    float32 // Similarity to the target, 0 when unknown
+	Reason        string  // Why the target was matched, or the failure
+}
+
+// Summary describes the decision in one line, naming the matched memory
+// for skipped and superseded memories.
+func (d MemoryDecision) Summary() string {
+	target := func() string {
+		s := fmt.Sprintf("%q", d.TargetContent)
+		if d.Similarity > 0 {
+			s += fmt.Sprintf(" (%.0f%% similar)", d.Similarity*100)
+		}
+		return s
+	}
+	switch d.Action {
+	case MemoryActionCreated:
+		return "Remembered"
+	case MemoryActionSkipped:
+		if d.TargetContent == "" {
+			return "Already remembered"
+		}
+		return "Already remembered as " + target()
+	case MemoryActionSuperseded:
+		if d.TargetContent == "" {
+			return "Memory updated"
+		}
+		return "Memory updated, replacing " + target()
+	case MemoryActionFailed:
+		if d.Reason == "" {
+			return "Failed to remember"
+		}
+		return "Failed to remember: " + d.Reason
+	case MemoryActionProposed:
+		return "Saved for review with \"ayo memory review\""
+	default:
+		return d.Action
+	}
 }
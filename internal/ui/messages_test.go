@@ -0,0 +1,28 @@
+package ui
+
+import "testing"
+
+func TestMemoryDecisionSummary(t *testing.T) {
+	tests := []struct {
+		d    MemoryDecision
+		want string
+	}{
+		{MemoryDecision{Action: MemoryActionCreated}, "Remembered"},
+		{MemoryDecision{Action: MemoryActionSkipped}, "Already remembered"},
+		{MemoryDecision{Action: MemoryActionProposed}, `Saved for review with "ayo memory review"`},
+		{
+			MemoryDecision{Action: MemoryActionSkipped, TargetContent: "uses pnpm", Similarity: 0.934},
+			`Already remembered as "uses pnpm" (93% similar)`,
+		},
+		{
+			MemoryDecision{Action: MemoryActionSuperseded, TargetContent: "uses npm"},
+			`Memory updated, replacing "uses npm"`,
+		},
+		{MemoryDecision{Action: MemoryActionFailed, Reason: "no embedder"}, "Failed to remember: no embedder"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Summary(); got != tt.want {
+			t.Errorf("Summary(%+v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// PagerNever turns paging off.
+const PagerNever = "never"
+
+var (
+	pagerMu      sync.Mutex
+	pagerSetting string // ui.pager: "" or "auto", "never", or a command
+)
+
+// SetPager sets how output taller than the terminal is paged: "" or "auto"
+// uses $PAGER, then less, then more; "never" turns paging off; anything
+// else is the pager command.
+func SetPager(setting string) {
+	pagerMu.Lock()
+	defer pagerMu.Unlock()
+	pagerSetting = strings.TrimSpace(setting)
+}
+
+func currentPager() string {
+	pagerMu.Lock()
+	defer pagerMu.Unlock()
+	return pagerSetting
+}
+
+// resolvePager returns the command line of the pager for setting, or nil
+// when paging is off or no pager is installed. Like git, a $PAGER that is
+// empty or "cat" means no pager.
+func resolvePager(setting string, lookupEnv func(string) (string, bool), lookPath func(string) (string, error)) []string {
+	switch setting {
+	case PagerNever:
+		return nil
+	case "", "auto":
+		env, ok := lookupEnv("PAGER")
+		if !ok {
+			for _, name := range []string{"less", "more"} {
+				if _, err := lookPath(name); err == nil {
+					return []string{name}
+				}
+			}
+			return nil
+		}
+		setting = env
+	}
+	args := strings.Fields(setting)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+	if _, err := lookPath(args[0]); err != nil {
+		return nil
+	}
+	return args
+}
+
+// tallerThan reports whether text fills more than a screen of width by
+// height, counting lines that wrap.
+func tallerThan(text string, width, height int) bool {
+	if width <= 0 || height <= 0 {
+		return false
+	}
+	rows := 0
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		rows += max(1, (ansi.StringWidth(line)+width-1)/width)
+		if rows >= height {
+			return true
+		}
+	}
+	return false
+}
+
+// pagerFor returns the pager command for text, or nil when text should be
+// written directly: stdout isn't a terminal, text fits on the screen,
+// paging is off, or no pager is installed. less is given the options in
+// less, unless $LESS is set.
+func pagerFor(text, less string) *exec.Cmd {
+	fd := os.Stdout.Fd()
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	width, height, err := term.GetSize(fd)
+	if err != nil || !tallerThan(text, width, height) {
+		return nil
+	}
+	args := resolvePager(currentPager(), os.LookupEnv, exec.LookPath)
+	if args == nil {
+		return nil
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	if args[0] == "less" && os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS="+less)
+	}
+	return cmd
+}
+
+// Page writes text to stdout, through the pager when stdout is a terminal
+// and text is taller than it. Piped output is never paged.
+func Page(text string) error {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	// Keep colors, and leave the output on the screen, as git does
+	if cmd := pagerFor(text, "FRX"); cmd != nil && runPager(cmd, text) {
+		return nil
+	}
+	_, err := io.WriteString(os.Stdout, text)
+	return err
+}
+
+// runPager shows text in the pager and reports whether it started.
+// Quitting the pager early is not an error.
+func runPager(cmd *exec.Cmd, text string) bool {
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	cmd.Wait()
+	return true
+}
+
+// Pager collects output, then pages it on Close.
+type Pager struct {
+	buf bytes.Buffer
+}
+
+// NewPager returns a writer whose output is written to stdout on Close,
+// through the pager when it is taller than the terminal.
+func NewPager() *Pager {
+	return &Pager{}
+}
+
+func (p *Pager) Write(b []byte) (int, error) {
+	return p.buf.Write(b)
+}
+
+// Close writes the collected output.
+func (p *Pager) Close() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+	return Page(p.buf.String())
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolvePager(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(names, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+	env := func(vars map[string]string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			v, ok := vars[key]
+			return v, ok
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setting string
+		env     map[string]string
+		path    []string
+		want    []string
+	}{
+		{"less first", "", nil, []string{"less", "more"}, []string{"less"}},
+		{"more without less", "auto", nil, []string{"more"}, []string{"more"}},
+		{"no pager installed", "", nil, nil, nil},
+		{"PAGER", "", map[string]string{"PAGER": "most -s"}, []string{"most", "less"}, []string{"most", "-s"}},
+		{"empty PAGER", "", map[string]string{"PAGER": ""}, []string{"less"}, nil},
+		{"PAGER cat", "", map[string]string{"PAGER": "cat"}, []string{"cat", "less"}, nil},
+		{"PAGER not installed", "", map[string]string{"PAGER": "most"}, []string{"less"}, nil},
+		{"never", "never", map[string]string{"PAGER": "less"}, []string{"less"}, nil},
+		{"command", "less -R", map[string]string{"PAGER": "more"}, []string{"less", "more"}, []string{"less", "-R"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolvePager(tt.setting, env(tt.env), installed(tt.path...))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("resolvePager(%q) = %q, want %q", tt.setting, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTallerThan(t *testing.T) {
+	lines := func(n int) string {
+		var b strings.Builder
+		for i := range n {
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		width  int
+		height int
+		want   bool
+	}{
+		{"fits", lines(10), 80, 24, false},
+		{"one short of the screen", lines(23), 80, 24, false},
+		{"fills the screen", lines(24), 80, 24, true},
+		{"wrapped lines", strings.Repeat(strings.Repeat("x", 200)+"\n", 10), 80, 24, true},
+		{"styled lines are measured without escapes", strings.Repeat("\x1b[31m"+strings.Repeat("x", 70)+"\x1b[0m\n", 10), 80, 24, false},
+		{"unknown size", lines(100), 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tallerThan(tt.text, tt.width, tt.height); got != tt.want {
+				t.Errorf("tallerThan = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagerWritesWhenNotATerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	p := NewPager()
+	fmt.Fprintln(p, "first")
+	fmt.Fprint(p, "second")
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	os.Stdout = stdout
+
+	got, _ := io.ReadAll(r)
+	if string(got) != "first\nsecond\n" {
+		t.Errorf("output = %q, want %q", got, "first\nsecond\n")
+	}
+}
@@ -4,30 +4,131 @@ package shared
 
 import "github.com/charmbracelet/lipgloss"
 
-// Color palette - a cohesive dark theme inspired by popular terminal themes.
-// These colors are used consistently across both TUI and non-interactive modes.
+// Color palette used consistently across both TUI and non-interactive modes.
+// The colors come from the active theme (see SetTheme); they default to the
+// dark theme.
 var (
 	// Primary colors
-	ColorPrimary   = lipgloss.Color("#a78bfa") // Purple - main accent
-	ColorSecondary = lipgloss.Color("#67e8f9") // Cyan - secondary accent
-	ColorTertiary  = lipgloss.Color("#fbbf24") // Amber - warnings/tool labels
-	ColorSuccess   = lipgloss.Color("#22c55e") // Green - success states (Tailwind green-500)
-	ColorError     = lipgloss.Color("#ef4444") // Red - errors (Tailwind red-500)
-	ColorMuted     = lipgloss.Color("#6b7280") // Gray - muted text
-	ColorSubtle    = lipgloss.Color("#374151") // Dark gray - borders/backgrounds
+	ColorPrimary   lipgloss.Color // Main accent
+	ColorSecondary lipgloss.Color // Secondary accent
+	ColorTertiary  lipgloss.Color // Warnings/tool labels
+	ColorSuccess   lipgloss.Color // Success states
+	ColorError     lipgloss.Color // Errors
+	ColorMuted     lipgloss.Color // Muted text
+	ColorSubtle    lipgloss.Color // Borders/backgrounds
+	ColorInfo      lipgloss.Color // In-progress states
 
 	// Text colors
-	ColorText       = lipgloss.Color("#e5e7eb") // Light gray - main text
-	ColorTextDim    = lipgloss.Color("#9ca3af") // Medium gray - dim text
-	ColorTextBright = lipgloss.Color("#f9fafb") // White - bright text
+	ColorText       lipgloss.Color // Main text
+	ColorTextDim    lipgloss.Color // Dim text
+	ColorTextBright lipgloss.Color // Bright text
 
 	// Background colors
-	ColorBgDark   = lipgloss.Color("#1f2937") // Dark background
-	ColorBgSubtle = lipgloss.Color("#111827") // Darker background
-	ColorBgAccent = lipgloss.Color("#312e81") // Purple tinted background
+	ColorBgDark   lipgloss.Color // Code and command background
+	ColorBgSubtle lipgloss.Color // Darker background
+	ColorBgAccent lipgloss.Color // Accent tinted background
 
 	// Tool-specific colors
-	ColorToolName    = lipgloss.Color("#3b82f6") // Blue - tool names
-	ColorToolPending = lipgloss.Color("#6b7280") // Gray - pending state
-	ColorToolRunning = lipgloss.Color("#a78bfa") // Purple - running state
+	ColorToolName    lipgloss.Color // Tool names
+	ColorToolPending lipgloss.Color // Pending state
+	ColorToolRunning lipgloss.Color // Running state
 )
+
+func init() {
+	SetTheme(DarkTheme())
+}
+
+// Theme maps the semantic color roles of the palette to colors. A color is
+// a hex value ("#a78bfa") or an ANSI color number ("141"); an empty color
+// leaves text unstyled.
+type Theme struct {
+	Primary    lipgloss.Color `json:"primary,omitempty"`
+	Secondary  lipgloss.Color `json:"secondary,omitempty"`
+	Tertiary   lipgloss.Color `json:"tertiary,omitempty"`
+	Success    lipgloss.Color `json:"success,omitempty"`
+	Error      lipgloss.Color `json:"error,omitempty"`
+	Muted      lipgloss.Color `json:"muted,omitempty"`
+	Subtle     lipgloss.Color `json:"subtle,omitempty"`
+	Text       lipgloss.Color `json:"text,omitempty"`
+	TextDim    lipgloss.Color `json:"text_dim,omitempty"`
+	TextBright lipgloss.Color `json:"text_bright,omitempty"`
+	BgDark     lipgloss.Color `json:"bg_dark,omitempty"`
+	BgSubtle   lipgloss.Color `json:"bg_subtle,omitempty"`
+	BgAccent   lipgloss.Color `json:"bg_accent,omitempty"`
+	Info       lipgloss.Color `json:"info,omitempty"`
+}
+
+// DarkTheme returns the default theme, for dark terminal backgrounds.
+func DarkTheme() Theme {
+	return Theme{
+		Primary:    "#a78bfa", // Purple
+		Secondary:  "#67e8f9", // Cyan
+		Tertiary:   "#fbbf24", // Amber
+		Success:    "#22c55e", // Green (Tailwind green-500)
+		Error:      "#ef4444", // Red (Tailwind red-500)
+		Muted:      "#6b7280", // Gray
+		Subtle:     "#374151", // Dark gray
+		Text:       "#e5e7eb", // Light gray
+		TextDim:    "#9ca3af", // Medium gray
+		TextBright: "#f9fafb", // White
+		BgDark:     "#1f2937",
+		BgSubtle:   "#111827",
+		BgAccent:   "#312e81", // Purple tinted
+		Info:       "#3b82f6", // Blue
+	}
+}
+
+// LightTheme returns a theme for light terminal backgrounds.
+func LightTheme() Theme {
+	return Theme{
+		Primary:    "#7c3aed", // Purple
+		Secondary:  "#0891b2", // Cyan
+		Tertiary:   "#d97706", // Amber
+		Success:    "#16a34a", // Green
+		Error:      "#dc2626", // Red
+		Muted:      "#6b7280", // Gray
+		Subtle:     "#d1d5db", // Light gray
+		Text:       "#1f2937", // Dark gray
+		TextDim:    "#4b5563", // Medium gray
+		TextBright: "#111827", // Near black
+		BgDark:     "#f3f4f6",
+		BgSubtle:   "#f9fafb",
+		BgAccent:   "#ede9fe", // Purple tinted
+		Info:       "#2563eb", // Blue
+	}
+}
+
+// MonoTheme returns a theme without colors.
+func MonoTheme() Theme {
+	return Theme{}
+}
+
+// BuiltinThemes maps the names of the built-in themes to their constructors.
+var BuiltinThemes = map[string]func() Theme{
+	"dark":  DarkTheme,
+	"light": LightTheme,
+	"mono":  MonoTheme,
+}
+
+// SetTheme makes t the palette. Styles built before the call keep their
+// colors, so it should be called before anything is rendered.
+func SetTheme(t Theme) {
+	ColorPrimary = t.Primary
+	ColorSecondary = t.Secondary
+	ColorTertiary = t.Tertiary
+	ColorSuccess = t.Success
+	ColorError = t.Error
+	ColorMuted = t.Muted
+	ColorSubtle = t.Subtle
+	ColorInfo = t.Info
+	ColorText = t.Text
+	ColorTextDim = t.TextDim
+	ColorTextBright = t.TextBright
+	ColorBgDark = t.BgDark
+	ColorBgSubtle = t.BgSubtle
+	ColorBgAccent = t.BgAccent
+	ColorToolName = t.Info
+	ColorToolPending = t.Muted
+	ColorToolRunning = t.Primary
+	ClearRendererCache()
+}
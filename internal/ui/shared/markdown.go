@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // rendererCache stores glamour renderers by width to avoid recreating them.
@@ -90,13 +91,13 @@ func GlamourStyleConfig() ansi.StyleConfig {
 			StylePrimitive: ansi.StylePrimitive{
 				BlockPrefix: "",
 				BlockSuffix: "",
-				Color:       strPtr("#e5e7eb"),
+				Color:       colorPtr(ColorText),
 			},
 			Margin: &margin,
 		},
 		BlockQuote: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color:  strPtr("#67e8f9"),
+				Color:  colorPtr(ColorSecondary),
 				Italic: boolPtr(true),
 			},
 			Indent:      uintPtr(1),
@@ -106,67 +107,67 @@ func GlamourStyleConfig() ansi.StyleConfig {
 			LevelIndent: 2,
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
-					Color: strPtr("#e5e7eb"),
+					Color: colorPtr(ColorText),
 				},
 			},
 		},
 		Heading: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockSuffix: "\n",
-				Color:       strPtr("#a78bfa"),
+				Color:       colorPtr(ColorPrimary),
 				Bold:        boolPtr(true),
 			},
 		},
 		H1: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "# ",
-				Color:  strPtr("#a78bfa"),
+				Color:  colorPtr(ColorPrimary),
 				Bold:   boolPtr(true),
 			},
 		},
 		H2: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "## ",
-				Color:  strPtr("#a78bfa"),
+				Color:  colorPtr(ColorPrimary),
 			},
 		},
 		H3: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "### ",
-				Color:  strPtr("#c4b5fd"),
+				Color:  colorPtr(ColorPrimary),
 			},
 		},
 		H4: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "#### ",
-				Color:  strPtr("#c4b5fd"),
+				Color:  colorPtr(ColorPrimary),
 			},
 		},
 		H5: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "##### ",
-				Color:  strPtr("#ddd6fe"),
+				Color:  colorPtr(ColorPrimary),
 			},
 		},
 		H6: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "###### ",
-				Color:  strPtr("#ddd6fe"),
+				Color:  colorPtr(ColorPrimary),
 			},
 		},
 		Strikethrough: ansi.StylePrimitive{
 			CrossedOut: boolPtr(true),
 		},
 		Emph: ansi.StylePrimitive{
-			Color:  strPtr("#fbbf24"),
+			Color:  colorPtr(ColorTertiary),
 			Italic: boolPtr(true),
 		},
 		Strong: ansi.StylePrimitive{
 			Bold:  boolPtr(true),
-			Color: strPtr("#f9fafb"),
+			Color: colorPtr(ColorTextBright),
 		},
 		HorizontalRule: ansi.StylePrimitive{
-			Color:  strPtr("#374151"),
+			Color:  colorPtr(ColorSubtle),
 			Format: "\n────────────────────────────────\n",
 		},
 		Item: ansi.StylePrimitive{
@@ -174,7 +175,7 @@ func GlamourStyleConfig() ansi.StyleConfig {
 		},
 		Enumeration: ansi.StylePrimitive{
 			BlockPrefix: ". ",
-			Color:       strPtr("#67e8f9"),
+			Color:       colorPtr(ColorSecondary),
 		},
 		Task: ansi.StyleTask{
 			StylePrimitive: ansi.StylePrimitive{},
@@ -182,24 +183,24 @@ func GlamourStyleConfig() ansi.StyleConfig {
 			Unticked:       "[ ] ",
 		},
 		Link: ansi.StylePrimitive{
-			Color:     strPtr("#67e8f9"),
+			Color:     colorPtr(ColorSecondary),
 			Underline: boolPtr(true),
 		},
 		LinkText: ansi.StylePrimitive{
-			Color: strPtr("#a78bfa"),
+			Color: colorPtr(ColorPrimary),
 		},
 		Image: ansi.StylePrimitive{
-			Color:     strPtr("#67e8f9"),
+			Color:     colorPtr(ColorSecondary),
 			Underline: boolPtr(true),
 		},
 		ImageText: ansi.StylePrimitive{
-			Color:  strPtr("#a78bfa"),
+			Color:  colorPtr(ColorPrimary),
 			Format: "Image: {{.text}}",
 		},
 		Code: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
-				Color:           strPtr("#4ade80"),
-				BackgroundColor: strPtr("#1f2937"),
+				Color:           colorPtr(ColorSuccess),
+				BackgroundColor: colorPtr(ColorBgDark),
 				Prefix:          " ",
 				Suffix:          " ",
 			},
@@ -207,94 +208,94 @@ func GlamourStyleConfig() ansi.StyleConfig {
 		CodeBlock: ansi.StyleCodeBlock{
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
-					Color: strPtr("#e5e7eb"),
+					Color: colorPtr(ColorText),
 				},
 				Margin: uintPtr(2),
 			},
 			Chroma: &ansi.Chroma{
 				Text: ansi.StylePrimitive{
-					Color: strPtr("#e5e7eb"),
+					Color: colorPtr(ColorText),
 				},
 				Error: ansi.StylePrimitive{
-					Color:           strPtr("#f9fafb"),
-					BackgroundColor: strPtr("#f87171"),
+					Color:           colorPtr(ColorTextBright),
+					BackgroundColor: colorPtr(ColorError),
 				},
 				Comment: ansi.StylePrimitive{
-					Color: strPtr("#6b7280"),
+					Color: colorPtr(ColorMuted),
 				},
 				CommentPreproc: ansi.StylePrimitive{
-					Color: strPtr("#fbbf24"),
+					Color: colorPtr(ColorTertiary),
 				},
 				Keyword: ansi.StylePrimitive{
-					Color: strPtr("#a78bfa"),
+					Color: colorPtr(ColorPrimary),
 				},
 				KeywordReserved: ansi.StylePrimitive{
-					Color: strPtr("#c084fc"),
+					Color: colorPtr(ColorPrimary),
 				},
 				KeywordNamespace: ansi.StylePrimitive{
-					Color: strPtr("#f472b6"),
+					Color: colorPtr(ColorError),
 				},
 				KeywordType: ansi.StylePrimitive{
-					Color: strPtr("#67e8f9"),
+					Color: colorPtr(ColorSecondary),
 				},
 				Operator: ansi.StylePrimitive{
-					Color: strPtr("#f87171"),
+					Color: colorPtr(ColorError),
 				},
 				Punctuation: ansi.StylePrimitive{
-					Color: strPtr("#9ca3af"),
+					Color: colorPtr(ColorTextDim),
 				},
 				Name: ansi.StylePrimitive{
-					Color: strPtr("#e5e7eb"),
+					Color: colorPtr(ColorText),
 				},
 				NameBuiltin: ansi.StylePrimitive{
-					Color: strPtr("#67e8f9"),
+					Color: colorPtr(ColorSecondary),
 				},
 				NameTag: ansi.StylePrimitive{
-					Color: strPtr("#a78bfa"),
+					Color: colorPtr(ColorPrimary),
 				},
 				NameAttribute: ansi.StylePrimitive{
-					Color: strPtr("#4ade80"),
+					Color: colorPtr(ColorSuccess),
 				},
 				NameClass: ansi.StylePrimitive{
-					Color:     strPtr("#f9fafb"),
+					Color:     colorPtr(ColorTextBright),
 					Underline: boolPtr(true),
 					Bold:      boolPtr(true),
 				},
 				NameConstant: ansi.StylePrimitive{
-					Color: strPtr("#c084fc"),
+					Color: colorPtr(ColorPrimary),
 				},
 				NameDecorator: ansi.StylePrimitive{
-					Color: strPtr("#fbbf24"),
+					Color: colorPtr(ColorTertiary),
 				},
 				NameFunction: ansi.StylePrimitive{
-					Color: strPtr("#4ade80"),
+					Color: colorPtr(ColorSuccess),
 				},
 				LiteralNumber: ansi.StylePrimitive{
-					Color: strPtr("#67e8f9"),
+					Color: colorPtr(ColorSecondary),
 				},
 				LiteralString: ansi.StylePrimitive{
-					Color: strPtr("#fbbf24"),
+					Color: colorPtr(ColorTertiary),
 				},
 				LiteralStringEscape: ansi.StylePrimitive{
-					Color: strPtr("#f472b6"),
+					Color: colorPtr(ColorError),
 				},
 				GenericDeleted: ansi.StylePrimitive{
-					Color: strPtr("#f87171"),
+					Color: colorPtr(ColorError),
 				},
 				GenericEmph: ansi.StylePrimitive{
 					Italic: boolPtr(true),
 				},
 				GenericInserted: ansi.StylePrimitive{
-					Color: strPtr("#4ade80"),
+					Color: colorPtr(ColorSuccess),
 				},
 				GenericStrong: ansi.StylePrimitive{
 					Bold: boolPtr(true),
 				},
 				GenericSubheading: ansi.StylePrimitive{
-					Color: strPtr("#67e8f9"),
+					Color: colorPtr(ColorSecondary),
 				},
 				Background: ansi.StylePrimitive{
-					BackgroundColor: strPtr("#111827"),
+					BackgroundColor: colorPtr(ColorBgSubtle),
 				},
 			},
 		},
@@ -316,3 +317,11 @@ func GlamourStyleConfig() ansi.StyleConfig {
 func strPtr(s string) *string   { return &s }
 func boolPtr(b bool) *bool      { return &b }
 func uintPtr(u uint) *uint      { return &u }
+
+// colorPtr returns c for a glamour style, or nil when c is unset.
+func colorPtr(c lipgloss.Color) *string {
+	if c == "" {
+		return nil
+	}
+	return strPtr(string(c))
+}
@@ -48,6 +48,13 @@ type TodosResponseMetadata struct {
 	Total         int      `json:"total"`
 }
 
+// AgentCallResponseMetadata represents agent_call tool response metadata.
+type AgentCallResponseMetadata struct {
+	Agent      string `json:"agent"`
+	Structured bool   `json:"structured,omitempty"` // Output is JSON matching the agent's output schema
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
 // FormatTodos formats a list of todos for display.
 // Uses the shared color palette for consistent styling.
 func FormatTodos(todos []Todo, width int) string {
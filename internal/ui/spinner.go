@@ -53,6 +53,18 @@ var (
 // SpinnerFrames defines the animation frames for the spinner (default/agent)
 var SpinnerFrames = framesAgent
 
+// Phases shown by a phase spinner while an agent waits on the model.
+const (
+	PhaseThinking    = "thinking"
+	PhaseCallingTool = "calling tool"
+	PhaseFormatting  = "formatting output"
+	PhaseRetrying    = "retrying"
+)
+
+// elapsedAfter is how long a phase runs before a phase spinner shows its
+// elapsed time, so quick phases don't flash a timer.
+const elapsedAfter = time.Second
+
 // Spinner displays an animated spinner with a message
 type Spinner struct {
 	message   string
@@ -67,6 +79,9 @@ type Spinner struct {
 	msgStyle  lipgloss.Style
 	isTTY     bool
 	quiet     bool
+
+	showElapsed bool      // Show the time spent in the current phase
+	phaseStart  time.Time // When the current phase began
 }
 
 // NewSpinner creates a new spinner with the given message (agent type)
@@ -145,6 +160,16 @@ func NewSpinnerWithTypeAndDepth(message string, spinnerType SpinnerType, depth i
 	}
 }
 
+// NewPhaseSpinner creates a spinner for an agent waiting in phase, such as
+// PhaseThinking. It shows how long the phase has run once that passes a
+// second, and SetPhase moves it to another phase.
+func NewPhaseSpinner(phase string, depth int) *Spinner {
+	s := NewSpinnerWithDepth(phase+"...", depth)
+	s.showElapsed = true
+	s.phaseStart = s.startTime
+	return s
+}
+
 // NewQuietSpinner creates a spinner that produces no output
 func NewQuietSpinner() *Spinner {
 	return &Spinner{
@@ -242,6 +267,30 @@ func (s *Spinner) Elapsed() time.Duration {
 	return time.Since(s.startTime)
 }
 
+// SetPhase shows phase in place of the spinner's message and restarts the
+// phase's elapsed time. Without a terminal nothing is printed, so piped
+// output keeps the single line Start wrote.
+func (s *Spinner) SetPhase(phase string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = phase + "..."
+	s.phaseStart = time.Now()
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	if s.isTTY {
+		s.render()
+	}
+}
+
+// Animated reports whether the spinner redraws itself in place, which it
+// does only on a terminal.
+func (s *Spinner) Animated() bool {
+	return s.isTTY && !s.quiet
+}
+
 // render draws the current spinner state (must be called with lock held)
 func (s *Spinner) render() {
 	if s.quiet {
@@ -249,9 +298,24 @@ func (s *Spinner) render() {
 	}
 	frame := s.style.Render(s.frames[s.frame%len(s.frames)])
 	msg := s.msgStyle.Render(s.message)
+	if s.showElapsed {
+		if elapsed := time.Since(s.phaseStart); elapsed >= elapsedAfter {
+			msg += " " + s.msgStyle.Render(formatElapsed(elapsed))
+		}
+	}
 	fmt.Fprintf(os.Stderr, "\r\033[K%s%s %s", s.indent, frame, msg)
 }
 
+// formatElapsed formats a phase's elapsed time in whole seconds, so the
+// timer changes at most once a second as the spinner redraws.
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
 // formatDuration formats a duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	if d < time.Second {
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"testing"
+	"time"
 )
 
 func TestSpinnerFrames(t *testing.T) {
@@ -49,3 +50,35 @@ func TestNewSpinnerWithDepth(t *testing.T) {
 		t.Error("depth 2 indent should be longer than depth 1")
 	}
 }
+
+func TestPhaseSpinnerSetPhase(t *testing.T) {
+	s := NewPhaseSpinner(PhaseThinking, 0)
+	if s.message != "thinking..." || !s.showElapsed {
+		t.Fatalf("message = %q, showElapsed = %v", s.message, s.showElapsed)
+	}
+
+	s.phaseStart = s.phaseStart.Add(-time.Minute)
+	s.SetPhase(PhaseRetrying)
+	if s.message != "retrying..." {
+		t.Errorf("message = %q, want the new phase", s.message)
+	}
+	if time.Since(s.phaseStart) > time.Second {
+		t.Error("SetPhase should restart the phase's elapsed time")
+	}
+
+	s.Stop()
+	s.SetPhase(PhaseThinking) // Must not draw after Stop
+}
+
+func TestFormatElapsed(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		1500 * time.Millisecond: "1s",
+		59 * time.Second:        "59s",
+		61 * time.Second:        "1m01s",
+		10 * time.Minute:        "10m00s",
+	} {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -12,26 +12,48 @@ import (
 )
 
 // Color palette - re-exported from shared package for backward compatibility.
+// syncPalette refreshes it when the theme changes.
 var (
 	// Primary colors
-	colorPrimary   = shared.ColorPrimary
-	colorSecondary = shared.ColorSecondary
-	colorTertiary  = shared.ColorTertiary
-	colorSuccess   = shared.ColorSuccess
-	colorError     = shared.ColorError
-	colorMuted     = shared.ColorMuted
-	colorSubtle    = shared.ColorSubtle
+	colorPrimary   lipgloss.Color
+	colorSecondary lipgloss.Color
+	colorTertiary  lipgloss.Color
+	colorSuccess   lipgloss.Color
+	colorError     lipgloss.Color
+	colorMuted     lipgloss.Color
+	colorSubtle    lipgloss.Color
 
 	// Text colors
-	colorText       = shared.ColorText
-	colorTextDim    = shared.ColorTextDim
-	colorTextBright = shared.ColorTextBright
+	colorText       lipgloss.Color
+	colorTextDim    lipgloss.Color
+	colorTextBright lipgloss.Color
 
 	// Background colors
-	colorBgDark   = shared.ColorBgDark
+	colorBgDark   lipgloss.Color
+	colorBgSubtle lipgloss.Color
+	colorBgAccent lipgloss.Color
+)
+
+func init() {
+	syncPalette()
+}
+
+// syncPalette copies the shared palette.
+func syncPalette() {
+	colorPrimary = shared.ColorPrimary
+	colorSecondary = shared.ColorSecondary
+	colorTertiary = shared.ColorTertiary
+	colorSuccess = shared.ColorSuccess
+	colorError = shared.ColorError
+	colorMuted = shared.ColorMuted
+	colorSubtle = shared.ColorSubtle
+	colorText = shared.ColorText
+	colorTextDim = shared.ColorTextDim
+	colorTextBright = shared.ColorTextBright
+	colorBgDark = shared.ColorBgDark
 	colorBgSubtle = shared.ColorBgSubtle
 	colorBgAccent = shared.ColorBgAccent
-)
+}
 
 // Styles holds all the application styles.
 type Styles struct {
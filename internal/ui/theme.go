@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/alexcabrera/ayo/internal/paths"
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "dark"
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(shared.BuiltinThemes))
+	for name := range shared.BuiltinThemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// themeFile is a JSON theme file. Roles it leaves out come from the
+// built-in theme it extends, dark by default.
+type themeFile struct {
+	Extends string `json:"extends,omitempty"`
+	shared.Theme
+}
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// LoadTheme returns the theme called name: a built-in theme, or the path of
+// a JSON theme file. "~/" expands to the home directory and relative paths
+// are resolved against the config directory. An empty name is the default
+// theme.
+func LoadTheme(name string) (shared.Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	if builtin, ok := shared.BuiltinThemes[name]; ok {
+		return builtin(), nil
+	}
+	if !strings.HasSuffix(name, ".json") {
+		return shared.Theme{}, fmt.Errorf("unknown theme %q (use %s, or the path of a .json theme file)", name, strings.Join(ThemeNames(), ", "))
+	}
+
+	path := name
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, path[2:])
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(paths.ConfigDir(), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return shared.Theme{}, fmt.Errorf("read theme: %w", err)
+	}
+
+	var file themeFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return shared.Theme{}, fmt.Errorf("parse theme %s: %w", path, err)
+	}
+	base := DefaultTheme
+	if file.Extends != "" {
+		base = file.Extends
+	}
+	builtin, ok := shared.BuiltinThemes[base]
+	if !ok {
+		return shared.Theme{}, fmt.Errorf("theme %s: unknown theme %q in extends (use %s)", path, base, strings.Join(ThemeNames(), ", "))
+	}
+	theme, err := mergeTheme(builtin(), file.Theme)
+	if err != nil {
+		return shared.Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// mergeTheme returns base with the colors set in override, which must be
+// hex colors or ANSI color numbers.
+func mergeTheme(base, override shared.Theme) (shared.Theme, error) {
+	roles := []struct {
+		name string
+		dst  *lipgloss.Color
+		src  lipgloss.Color
+	}{
+		{"primary", &base.Primary, override.Primary},
+		{"secondary", &base.Secondary, override.Secondary},
+		{"tertiary", &base.Tertiary, override.Tertiary},
+		{"success", &base.Success, override.Success},
+		{"error", &base.Error, override.Error},
+		{"muted", &base.Muted, override.Muted},
+		{"subtle", &base.Subtle, override.Subtle},
+		{"text", &base.Text, override.Text},
+		{"text_dim", &base.TextDim, override.TextDim},
+		{"text_bright", &base.TextBright, override.TextBright},
+		{"bg_dark", &base.BgDark, override.BgDark},
+		{"bg_subtle", &base.BgSubtle, override.BgSubtle},
+		{"bg_accent", &base.BgAccent, override.BgAccent},
+		{"info", &base.Info, override.Info},
+	}
+	for _, r := range roles {
+		if r.src == "" {
+			continue
+		}
+		if !validColor(string(r.src)) {
+			return shared.Theme{}, fmt.Errorf("%s: invalid color %q (use #rrggbb or an ANSI color number 0-255)", r.name, r.src)
+		}
+		*r.dst = r.src
+	}
+	return base, nil
+}
+
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// ApplyTheme makes the theme called name the palette for everything
+// rendered afterwards. NO_COLOR forces the mono theme, which also strips the
+// colors that aren't part of the palette.
+func ApplyTheme(name string) error {
+	if os.Getenv("NO_COLOR") != "" {
+		name = "mono"
+	}
+	theme, err := LoadTheme(name)
+	if err != nil {
+		return err
+	}
+	shared.SetTheme(theme)
+	syncPalette()
+	if theme == shared.MonoTheme() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	return nil
+}
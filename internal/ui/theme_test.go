@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexcabrera/ayo/internal/ui/shared"
+)
+
+func writeTheme(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTheme(t *testing.T) {
+	if theme, err := LoadTheme(""); err != nil || theme != shared.DarkTheme() {
+		t.Errorf("LoadTheme(\"\") = %+v, %v; want the dark theme", theme, err)
+	}
+	if theme, err := LoadTheme("light"); err != nil || theme != shared.LightTheme() {
+		t.Errorf("LoadTheme(light) = %+v, %v", theme, err)
+	}
+	if _, err := LoadTheme("solarized"); err == nil || !strings.Contains(err.Error(), "dark, light, mono") {
+		t.Errorf("LoadTheme(solarized) error = %v, want the built-in names", err)
+	}
+
+	path := writeTheme(t, `{"extends": "light", "primary": "#ff00ff", "muted": "244"}`)
+	theme, err := LoadTheme(path)
+	if err != nil {
+		t.Fatalf("LoadTheme(file): %v", err)
+	}
+	want := shared.LightTheme()
+	want.Primary, want.Muted = "#ff00ff", "244"
+	if theme != want {
+		t.Errorf("LoadTheme(file) = %+v, want %+v", theme, want)
+	}
+
+	// Roles left out of a file come from the dark theme
+	theme, err = LoadTheme(writeTheme(t, `{"error": "#f00"}`))
+	if err != nil || theme.Primary != shared.DarkTheme().Primary || theme.Error != "#f00" {
+		t.Errorf("LoadTheme(partial file) = %+v, %v", theme, err)
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr string
+	}{
+		{`{"primary": "purple"}`, `primary: invalid color "purple"`},
+		{`{"primary": "256"}`, `primary: invalid color "256"`},
+		{`{"extends": "sepia"}`, `unknown theme "sepia" in extends`},
+		{`{"primry": "#fff"}`, `unknown field "primry"`},
+	}
+	for _, tt := range tests {
+		_, err := LoadTheme(writeTheme(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("LoadTheme(%s) error = %v, want %q", tt.content, err, tt.wantErr)
+		}
+	}
+	if _, err := LoadTheme(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadTheme(missing file) succeeded")
+	}
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ToolRenderer formats the output of a completed tool call in print mode.
+// Render returns false to fall back to the generic output.
+type ToolRenderer interface {
+	Render(tc ToolCallInfo, width int) (string, bool)
+}
+
+// ToolRendererFunc adapts a function to the ToolRenderer interface.
+type ToolRendererFunc func(tc ToolCallInfo, width int) (string, bool)
+
+// Render calls f.
+func (f ToolRendererFunc) Render(tc ToolCallInfo, width int) (string, bool) {
+	return f(tc, width)
+}
+
+var toolRenderers = struct {
+	sync.RWMutex
+	byName map[string]ToolRenderer
+}{byName: make(map[string]ToolRenderer)}
+
+// RegisterToolRenderer registers the renderer for a tool, replacing any
+// previous one.
+func RegisterToolRenderer(name string, r ToolRenderer) {
+	toolRenderers.Lock()
+	defer toolRenderers.Unlock()
+	toolRenderers.byName[name] = r
+}
+
+// LookupToolRenderer returns the renderer registered for a tool.
+func LookupToolRenderer(name string) (ToolRenderer, bool) {
+	toolRenderers.RLock()
+	defer toolRenderers.RUnlock()
+	r, ok := toolRenderers.byName[name]
+	return r, ok
+}
+
+// ToolTemplateData is the data passed to a tool's print template.
+type ToolTemplateData struct {
+	Name     string
+	Params   map[string]any // Parsed tool input
+	Output   string         // Raw tool output
+	JSON     any            // Output parsed as JSON; nil when it isn't JSON
+	Error    string
+	Duration string
+	Width    int
+}
+
+// LoadToolRendererTemplate registers a renderer for a tool from a
+// text/template file. A missing file is not an error.
+func LoadToolRendererTemplate(name, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	tmpl, err := template.New(name).Funcs(toolTemplateFuncs()).Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	RegisterToolRenderer(name, ToolRendererFunc(func(tc ToolCallInfo, width int) (string, bool) {
+		td := ToolTemplateData{
+			Name:     tc.Name,
+			Output:   tc.Output,
+			Error:    tc.Error,
+			Duration: tc.Duration,
+			Width:    width,
+		}
+		_ = json.Unmarshal([]byte(tc.Input), &td.Params)
+		_ = json.Unmarshal([]byte(tc.Output), &td.JSON)
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, td); err != nil {
+			return "", false
+		}
+		return strings.TrimRight(buf.String(), "\n"), true
+	}))
+	return nil
+}
+
+// toolTemplateFuncs returns the functions available to print templates.
+func toolTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"style": func(color, text string) string {
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(text)
+		},
+		"truncate": func(maxLen int, text string) string {
+			if len(text) <= maxLen {
+				return text
+			}
+			if maxLen <= 3 {
+				return "..."
+			}
+			return text[:maxLen-3] + "..."
+		},
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"jsonPretty": func(v any) string {
+			data, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return fmt.Sprint(v)
+			}
+			return string(data)
+		},
+		"table": renderTable,
+	}
+}
+
+// renderTable formats rows as aligned columns under a header. Rows are JSON
+// objects or arrays. For objects, columns default to the sorted keys of the
+// first row.
+func renderTable(rows any, columns ...string) string {
+	list, ok := rows.([]any)
+	if !ok || len(list) == 0 {
+		return ""
+	}
+
+	if first, ok := list[0].(map[string]any); ok && len(columns) == 0 {
+		for key := range first {
+			columns = append(columns, key)
+		}
+		sort.Strings(columns)
+	}
+
+	var cells [][]string
+	for _, row := range list {
+		var line []string
+		switch r := row.(type) {
+		case map[string]any:
+			for _, col := range columns {
+				line = append(line, tableCell(r[col]))
+			}
+		case []any:
+			for _, v := range r {
+				line = append(line, tableCell(v))
+			}
+		default:
+			line = []string{tableCell(r)}
+		}
+		cells = append(cells, line)
+	}
+
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = len(col)
+	}
+	for _, line := range cells {
+		for i, cell := range line {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	format := func(line []string) string {
+		parts := make([]string, len(line))
+		for i, cell := range line {
+			parts[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ")
+	}
+
+	var out []string
+	if len(columns) > 0 {
+		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(colorMuted)
+		out = append(out, headerStyle.Render(format(columns)))
+	}
+	for _, line := range cells {
+		out = append(out, format(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+// tableCell formats a JSON value for a table cell.
+func tableCell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintToolCallResultUsesRegisteredRenderer(t *testing.T) {
+	RegisterToolRenderer("test_custom", ToolRendererFunc(func(tc ToolCallInfo, width int) (string, bool) {
+		return "custom: " + tc.Output, true
+	}))
+
+	var buf bytes.Buffer
+	u := NewWithWriter(false, &buf)
+	u.PrintToolCallResult(ToolCallInfo{Name: "test_custom", Output: "hello", Duration: "1ms"})
+
+	if !strings.Contains(buf.String(), "custom: hello") {
+		t.Errorf("output = %q, want the custom rendering", buf.String())
+	}
+}
+
+func TestPrintToolCallResultFallsBack(t *testing.T) {
+	RegisterToolRenderer("test_decline", ToolRendererFunc(func(tc ToolCallInfo, width int) (string, bool) {
+		return "unused", false
+	}))
+
+	var buf bytes.Buffer
+	u := NewWithWriter(false, &buf)
+	u.PrintToolCallResult(ToolCallInfo{Name: "test_decline", Output: "plain output", Duration: "1ms"})
+
+	if strings.Contains(buf.String(), "unused") {
+		t.Errorf("output = %q, declined rendering should not be printed", buf.String())
+	}
+	if !strings.Contains(buf.String(), "plain output") {
+		t.Errorf("output = %q, want the generic output", buf.String())
+	}
+}
+
+func TestLoadToolRendererTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "print.tmpl")
+	tmpl := `{{.Params.query}}
+{{table .JSON "id" "name"}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadToolRendererTemplate("test_sql", path); err != nil {
+		t.Fatalf("LoadToolRendererTemplate: %v", err)
+	}
+
+	r, ok := LookupToolRenderer("test_sql")
+	if !ok {
+		t.Fatal("renderer not registered")
+	}
+	out, ok := r.Render(ToolCallInfo{
+		Name:   "test_sql",
+		Input:  `{"query":"select * from users"}`,
+		Output: `[{"id":1,"name":"ada"},{"id":20,"name":"grace"}]`,
+	}, 80)
+	if !ok {
+		t.Fatal("Render returned false")
+	}
+	for _, want := range []string{"select * from users", "1   ada", "20  grace"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output = %q, want it to contain %q", out, want)
+		}
+	}
+}
+
+func TestLoadToolRendererTemplateMissingFile(t *testing.T) {
+	if err := LoadToolRendererTemplate("test_missing", filepath.Join(t.TempDir(), "print.tmpl")); err != nil {
+		t.Fatalf("LoadToolRendererTemplate: %v", err)
+	}
+	if _, ok := LookupToolRenderer("test_missing"); ok {
+		t.Error("renderer registered for a missing template")
+	}
+}
+
+func TestLoadToolRendererTemplateExecuteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "print.tmpl")
+	if err := os.WriteFile(path, []byte(`{{index .JSON 5}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadToolRendererTemplate("test_broken", path); err != nil {
+		t.Fatalf("LoadToolRendererTemplate: %v", err)
+	}
+	r, _ := LookupToolRenderer("test_broken")
+	if _, ok := r.Render(ToolCallInfo{Output: `[1]`}, 80); ok {
+		t.Error("Render returned true for a failing template")
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	rows := []any{
+		map[string]any{"b": "x", "a": 1.5},
+		map[string]any{"b": nil, "a": true},
+	}
+	lines := strings.Split(renderTable(rows), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q, want header and 2 rows", lines)
+	}
+	if !strings.Contains(lines[0], "a     b") {
+		t.Errorf("header = %q, want sorted keys", lines[0])
+	}
+	if lines[1] != "1.5   x" || lines[2] != "true" {
+		t.Errorf("rows = %q", lines[1:])
+	}
+
+	if got := renderTable("not rows"); got != "" {
+		t.Errorf("renderTable(string) = %q, want empty", got)
+	}
+}
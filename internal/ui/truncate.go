@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// TruncateStrategy selects which lines of long tool output are displayed.
+type TruncateStrategy string
+
+const (
+	TruncateHeadTail TruncateStrategy = "head_tail" // First and last lines
+	TruncateHead     TruncateStrategy = "head"      // First lines only
+	TruncateTail     TruncateStrategy = "tail"      // Last lines only
+	TruncateSmart    TruncateStrategy = "smart"     // Error lines first, then head and tail
+)
+
+// defaultErrorPattern matches lines the smart strategy keeps.
+var defaultErrorPattern = regexp.MustCompile(`(?i)\b(error|errors|fail|failed|failure|fatal|panic|exception|traceback|denied|cannot|undefined)\b`)
+
+// ToolOutputOptions controls how long tool output is truncated for display.
+type ToolOutputOptions struct {
+	Strategy     TruncateStrategy // "" = the display's own default
+	MaxLines     int              // 0 = the display's own default
+	ErrorPattern *regexp.Regexp   // Lines the smart strategy keeps; nil = built-in pattern
+}
+
+// NewToolOutputOptions validates tool output settings from config.
+// Empty values leave each display's own defaults in place.
+func NewToolOutputOptions(strategy string, maxLines int, errorPattern string) (ToolOutputOptions, error) {
+	opts := ToolOutputOptions{Strategy: TruncateStrategy(strategy), MaxLines: maxLines}
+	switch opts.Strategy {
+	case "", TruncateHeadTail, TruncateHead, TruncateTail, TruncateSmart:
+	default:
+		return ToolOutputOptions{}, fmt.Errorf("unknown tool output strategy %q (use head_tail, head, tail, or smart)", strategy)
+	}
+	if maxLines < 0 {
+		return ToolOutputOptions{}, fmt.Errorf("tool output max_lines must not be negative")
+	}
+	if errorPattern != "" {
+		re, err := regexp.Compile(errorPattern)
+		if err != nil {
+			return ToolOutputOptions{}, fmt.Errorf("invalid tool output error_pattern: %w", err)
+		}
+		opts.ErrorPattern = re
+	}
+	return opts, nil
+}
+
+// displayLine is a line of truncated output. Omitted is non-zero for the
+// marker that stands in for a run of hidden lines.
+type displayLine struct {
+	Text    string
+	Omitted int
+}
+
+// truncateLines picks the lines of long output to display under opts,
+// falling back to defaultMax and defaultStrategy when opts leaves them
+// unset. Each run of hidden lines is replaced with a marker. keep, if
+// non-nil, marks lines the smart strategy should prefer, such as stderr.
+func truncateLines(lines []string, opts ToolOutputOptions, defaultMax int, defaultStrategy TruncateStrategy, keep func(i int) bool) []displayLine {
+	maxLines := opts.MaxLines
+	if maxLines <= 0 {
+		maxLines = defaultMax
+	}
+	strategy := opts.Strategy
+	if strategy == "" {
+		strategy = defaultStrategy
+	}
+
+	selected := make([]bool, len(lines))
+	selectRange := func(from, to int) {
+		for i := max(from, 0); i < min(to, len(lines)); i++ {
+			selected[i] = true
+		}
+	}
+
+	switch {
+	case len(lines) <= maxLines:
+		selectRange(0, len(lines))
+	case strategy == TruncateHead:
+		selectRange(0, maxLines)
+	case strategy == TruncateTail:
+		selectRange(len(lines)-maxLines, len(lines))
+	case strategy == TruncateSmart:
+		pattern := opts.ErrorPattern
+		if pattern == nil {
+			pattern = defaultErrorPattern
+		}
+		// Preferred lines take up to half the budget, latest first, since
+		// the final errors usually explain a failure
+		budget := maxLines / 2
+		kept := 0
+		for i := len(lines) - 1; i >= 0 && kept < budget; i-- {
+			if (keep != nil && keep(i)) || pattern.MatchString(lines[i]) {
+				selected[i] = true
+				kept++
+			}
+		}
+		rest := maxLines - kept
+		selectRange(0, rest/2)
+		selectRange(len(lines)-(rest-rest/2), len(lines))
+	default:
+		// Half the budget from the start and a quarter from the end
+		selectRange(0, maxLines/2)
+		selectRange(len(lines)-maxLines/4, len(lines))
+	}
+
+	var out []displayLine
+	omitted := 0
+	for i, line := range lines {
+		if !selected[i] {
+			omitted++
+			continue
+		}
+		if omitted > 0 {
+			out = append(out, displayLine{Text: omittedMarker(omitted), Omitted: omitted})
+			omitted = 0
+		}
+		out = append(out, displayLine{Text: line})
+	}
+	if omitted > 0 {
+		out = append(out, displayLine{Text: moreMarker(omitted), Omitted: omitted})
+	}
+	return out
+}
+
+// allLines displays every line, for verbose output.
+func allLines(lines []string) []displayLine {
+	out := make([]displayLine, len(lines))
+	for i, line := range lines {
+		out[i] = displayLine{Text: line}
+	}
+	return out
+}
+
+func omittedMarker(n int) string {
+	return fmt.Sprintf("... (%d lines omitted) ...", n)
+}
+
+// moreMarker stands in for hidden lines at the end of the output.
+func moreMarker(n int) string {
+	return fmt.Sprintf("… (%d more lines)", n)
+}
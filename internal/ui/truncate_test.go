@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func displayText(lines []displayLine) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+func TestTruncateLinesShortOutputUnchanged(t *testing.T) {
+	lines := numberedLines(5)
+	got := truncateLines(lines, ToolOutputOptions{}, 20, TruncateHeadTail, nil)
+	if displayText(got) != strings.Join(lines, "\n") {
+		t.Errorf("short output should be unchanged, got %q", displayText(got))
+	}
+}
+
+func TestTruncateLinesStrategies(t *testing.T) {
+	lines := numberedLines(30)
+
+	tests := []struct {
+		name     string
+		strategy TruncateStrategy
+		want     string
+	}{
+		{
+			name:     "head_tail keeps half from the start and a quarter from the end",
+			strategy: TruncateHeadTail,
+			want:     "line 1\nline 2\nline 3\nline 4\nline 5\n... (23 lines omitted) ...\nline 29\nline 30",
+		},
+		{
+			name:     "head",
+			strategy: TruncateHead,
+			want:     strings.Join(numberedLines(10), "\n") + "\n… (20 more lines)",
+		},
+		{
+			name:     "tail",
+			strategy: TruncateTail,
+			want:     "... (20 lines omitted) ...\n" + strings.Join(lines[20:], "\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := displayText(truncateLines(lines, ToolOutputOptions{Strategy: tt.strategy, MaxLines: 10}, 20, TruncateHeadTail, nil))
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateLinesDefaultStrategy(t *testing.T) {
+	lines := numberedLines(30)
+
+	// An unset strategy uses the display's own
+	got := displayText(truncateLines(lines, ToolOutputOptions{MaxLines: 10}, 20, TruncateHead, nil))
+	if want := strings.Join(numberedLines(10), "\n") + "\n… (20 more lines)"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	got = displayText(truncateLines(lines, ToolOutputOptions{Strategy: TruncateHeadTail, MaxLines: 10}, 20, TruncateHead, nil))
+	if !strings.HasSuffix(got, "line 30") {
+		t.Errorf("a configured strategy should override the default, got:\n%s", got)
+	}
+}
+
+func TestTruncateLinesDefaultMax(t *testing.T) {
+	// Without max_lines the display's own limit applies: 20 keeps 10 + 5
+	got := truncateLines(numberedLines(30), ToolOutputOptions{}, 20, TruncateHeadTail, nil)
+	if len(got) != 16 || got[10].Omitted != 15 {
+		t.Errorf("expected 10 head lines, a 15-line gap, and 5 tail lines, got:\n%s", displayText(got))
+	}
+}
+
+func TestTruncateLinesSmartKeepsErrors(t *testing.T) {
+	lines := numberedLines(40)
+	lines[19] = "main.go:12: undefined: foo"
+
+	got := displayText(truncateLines(lines, ToolOutputOptions{Strategy: TruncateSmart, MaxLines: 10}, 20, TruncateHeadTail, nil))
+	if !strings.Contains(got, "undefined: foo") {
+		t.Errorf("smart strategy should keep the error line, got:\n%s", got)
+	}
+	if !strings.Contains(got, "line 1\n") || !strings.HasSuffix(got, "line 40") {
+		t.Errorf("smart strategy should keep head and tail context, got:\n%s", got)
+	}
+	if strings.Count(got, "lines omitted") != 2 {
+		t.Errorf("expected a marker on each side of the error, got:\n%s", got)
+	}
+}
+
+func TestTruncateLinesSmartPrefersKeptLines(t *testing.T) {
+	lines := numberedLines(40)
+	lines[14] = "warning: deprecated flag"
+
+	keep := func(i int) bool { return i == 14 }
+	got := displayText(truncateLines(lines, ToolOutputOptions{Strategy: TruncateSmart, MaxLines: 10}, 20, TruncateHeadTail, keep))
+	if !strings.Contains(got, "warning: deprecated flag") {
+		t.Errorf("smart strategy should keep preferred lines, got:\n%s", got)
+	}
+}
+
+func TestTruncateLinesSmartCustomPattern(t *testing.T) {
+	lines := numberedLines(40)
+	lines[20] = "WARN disk almost full"
+
+	opts := ToolOutputOptions{Strategy: TruncateSmart, MaxLines: 10, ErrorPattern: regexp.MustCompile(`^WARN`)}
+	got := displayText(truncateLines(lines, opts, 20, TruncateHeadTail, nil))
+	if !strings.Contains(got, "WARN disk almost full") {
+		t.Errorf("smart strategy should use the custom pattern, got:\n%s", got)
+	}
+}
+
+func TestNewToolOutputOptions(t *testing.T) {
+	opts, err := NewToolOutputOptions("", 0, "")
+	if err != nil || opts.Strategy != "" {
+		t.Errorf("empty settings should leave the display defaults, got %+v, %v", opts, err)
+	}
+
+	if _, err := NewToolOutputOptions("middle", 0, ""); err == nil || !strings.Contains(err.Error(), `unknown tool output strategy "middle"`) {
+		t.Errorf("expected unknown strategy error, got %v", err)
+	}
+	if _, err := NewToolOutputOptions("smart", -1, ""); err == nil {
+		t.Error("expected error for negative max_lines")
+	}
+	if _, err := NewToolOutputOptions("smart", 0, "("); err == nil || !strings.Contains(err.Error(), "error_pattern") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestPrintToolCallResultSmartShowsStderr(t *testing.T) {
+	stdout := strings.Join(numberedLines(40), "\n")
+	output := fmt.Sprintf(`{"stdout": %q, "stderr": "boom: disk quota exceeded", "exit_code": 1}`, stdout)
+
+	var buf strings.Builder
+	u := NewWithWriter(false, &buf)
+	u.SetToolOutput(ToolOutputOptions{Strategy: TruncateSmart, MaxLines: 10})
+	u.PrintToolCallResult(ToolCallInfo{Name: "bash", Output: output, Duration: "1s"})
+
+	got := buf.String()
+	if !strings.Contains(got, "boom: disk quota exceeded") {
+		t.Errorf("smart strategy should keep stderr, got:\n%s", got)
+	}
+	if !strings.Contains(got, "line 1") {
+		t.Errorf("smart strategy should show stdout alongside stderr, got:\n%s", got)
+	}
+}
+
+func TestPrintToolCallResultVerbose(t *testing.T) {
+	lines := numberedLines(100)
+	lines[50] = strings.Repeat("x", 300)
+	output := fmt.Sprintf(`{"stdout": %q, "exit_code": 0}`, strings.Join(lines, "\n"))
+
+	var buf strings.Builder
+	u := NewWithWriter(false, &buf)
+	u.SetVerbose(true)
+	u.PrintToolCallResult(ToolCallInfo{Name: "bash", Output: output, Duration: "1s"})
+
+	got := buf.String()
+	if strings.Contains(got, "omitted") || strings.Contains(got, "truncated") {
+		t.Errorf("verbose output should not elide lines, got:\n%s", got)
+	}
+	for _, want := range []string{"line 1\n", "line 50\n", "line 100\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("verbose output missing %q", want)
+		}
+	}
+	if strings.Contains(got, strings.Repeat("x", 300)) || strings.Count(got, "x") != 300 {
+		t.Errorf("long lines should wrap without losing text, got:\n%s", got)
+	}
+}
+
+func TestPrintToolCallStartVerboseShowsInput(t *testing.T) {
+	input := `{"query": "select * from users", "limit": 500}`
+
+	var buf strings.Builder
+	u := NewWithWriter(false, &buf)
+	u.PrintToolCallStart(ToolCallInfo{Name: "sql", Input: input})
+	if strings.Contains(buf.String(), "select") {
+		t.Errorf("input should only be shown in verbose mode, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	u.SetVerbose(true)
+	u.PrintToolCallStart(ToolCallInfo{Name: "sql", Input: input})
+	if !strings.Contains(buf.String(), `"query": "select * from users"`) {
+		t.Errorf("verbose mode should show the indented input, got:\n%s", buf.String())
+	}
+}
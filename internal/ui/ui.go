@@ -24,12 +24,14 @@ type SelectAgentResult struct {
 
 type UI struct {
 	debug       bool
-	depth       int       // 0 = top-level, 1+ = sub-agent calls
+	depth       int // 0 = top-level, 1+ = sub-agent calls
 	styles      Styles
 	renderer    *markdownRenderer
-	out         io.Writer // Where to write UI output (stdout or stderr)
-	piped       bool      // Whether output is being piped
-	atLineStart bool      // Track if we're at the start of a line (for streaming indent)
+	out         io.Writer         // Where to write UI output (stdout or stderr)
+	piped       bool              // Whether output is being piped
+	atLineStart bool              // Track if we're at the start of a line (for streaming indent)
+	toolOutput  ToolOutputOptions // How long tool output is truncated
+	verbose     bool              // Show tool input and output without truncation
 }
 
 // markdownRenderer wraps glamour rendering with fallback.
@@ -96,6 +98,22 @@ func NewWithWriter(debug bool, out io.Writer) *UI {
 	}
 }
 
+// SetToolOutput sets how long tool output is truncated for display.
+func (u *UI) SetToolOutput(opts ToolOutputOptions) {
+	u.toolOutput = opts
+}
+
+// SetVerbose shows tool input and output in full. Long lines are wrapped to
+// the terminal width instead of truncated.
+func (u *UI) SetVerbose(verbose bool) {
+	u.verbose = verbose
+}
+
+// wrapWidth returns the width available to a line printed after indent.
+func wrapWidth(indent string) int {
+	return max(getTerminalWidth()-lipgloss.Width(indent), 20)
+}
+
 // IsPiped returns true if output is being piped.
 func (u *UI) IsPiped() bool {
 	return u.piped
@@ -174,15 +192,15 @@ func renderJSONFence(content string) string {
 
 // renderPlainOutput renders plain text output with line and width truncation.
 // It normalizes line endings, replaces tabs with spaces, and truncates both
-// vertically (by line count) and horizontally (by terminal width).
-func renderPlainOutput(out string, maxLines int) string {
+// vertically (by opts, the first maxLines by default) and horizontally (by
+// terminal width). In verbose mode every line is kept and long lines wrap.
+func renderPlainOutput(out string, maxLines int, opts ToolOutputOptions, verbose bool) string {
 	// Normalize line endings and tabs
 	out = strings.ReplaceAll(out, "\r\n", "\n")
 	out = strings.ReplaceAll(out, "\t", "    ")
 	out = strings.TrimSpace(out)
 
 	lines := strings.Split(out, "\n")
-	totalLines := len(lines)
 
 	// Get max width for line truncation
 	maxWidth := getTerminalWidth() - 4 // Account for box padding
@@ -193,23 +211,27 @@ func renderPlainOutput(out string, maxLines int) string {
 	lineStyle := lipgloss.NewStyle().Foreground(colorTextDim)
 	truncStyle := lipgloss.NewStyle().Foreground(colorMuted).Italic(true)
 
-	var result []string
-	displayLines := min(len(lines), maxLines)
+	display := truncateLines(lines, opts, maxLines, TruncateHead, nil)
+	if verbose {
+		display = allLines(lines)
+	}
 
-	for i := 0; i < displayLines; i++ {
-		line := lines[i]
+	var result []string
+	for _, dl := range display {
+		if dl.Omitted > 0 {
+			result = append(result, truncStyle.Render(dl.Text))
+			continue
+		}
+		line := dl.Text
 		// Truncate long lines with ellipsis
-		if lipgloss.Width(line) > maxWidth {
+		if verbose {
+			line = ansi.Wrap(line, maxWidth, "")
+		} else if lipgloss.Width(line) > maxWidth {
 			line = ansi.Truncate(line, maxWidth, "…")
 		}
 		result = append(result, lineStyle.Render(line))
 	}
 
-	// Add truncation indicator if needed
-	if totalLines > maxLines {
-		result = append(result, truncStyle.Render(fmt.Sprintf("… (%d more lines)", totalLines-maxLines)))
-	}
-
 	return strings.Join(result, "\n")
 }
 
@@ -316,8 +338,14 @@ func (u *UI) SelectAgent(ctx context.Context, handles []string) (SelectAgentResu
 	return SelectAgentResult{Handle: selected}, nil
 }
 
+// PrintResult renders text as markdown. On a terminal, a result taller than
+// the screen is shown in the pager.
 func (u *UI) PrintResult(text string) {
 	rendered := u.renderer.render(text)
+	if u.out == os.Stdout && u.depth == 0 {
+		Page(rendered)
+		return
+	}
 	u.println(rendered)
 }
 
@@ -373,10 +401,10 @@ func (u *UI) renderBashResult(label, command, content, status string) {
 				parts = append(parts, rendered)
 			} else {
 				// Not valid JSON, render as plain text
-				parts = append(parts, renderPlainOutput(out, 50))
+				parts = append(parts, renderPlainOutput(out, 50, u.toolOutput, u.verbose))
 			}
 		} else {
-			parts = append(parts, renderPlainOutput(out, 50))
+			parts = append(parts, renderPlainOutput(out, 50, u.toolOutput, u.verbose))
 		}
 	}
 
@@ -409,7 +437,7 @@ func (u *UI) renderToolResult(label, typ, content, status string) {
 	default:
 		// Limit output display for very long outputs
 		lines := strings.Split(plain, "\n")
-		if len(lines) > 50 {
+		if len(lines) > 50 && !u.verbose {
 			truncated := strings.Join(lines[:25], "\n")
 			truncated += fmt.Sprintf("\n\n... (%d lines omitted) ...\n\n", len(lines)-50)
 			truncated += strings.Join(lines[len(lines)-25:], "\n")
@@ -593,6 +621,20 @@ func (u *UI) PrintToolCallStart(tc ToolCallInfo) {
 		cmdStyle := lipgloss.NewStyle().Foreground(colorMuted)
 		u.printf("%s  %s\n", indent, cmdStyle.Render("$ "+tc.Command))
 	}
+
+	// Verbose mode shows the full input of other tools
+	if u.verbose && tc.Name != "bash" && strings.TrimSpace(tc.Input) != "" && tc.Input != "{}" {
+		input := tc.Input
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(input), "", "  "); err == nil {
+			input = pretty.String()
+		}
+		inputStyle := lipgloss.NewStyle().Foreground(colorMuted)
+		wrapped := ansi.Wrap(cleanText(input), wrapWidth(indent+"  "), "")
+		for _, line := range strings.Split(wrapped, "\n") {
+			u.printf("%s  %s\n", indent, inputStyle.Render(line))
+		}
+	}
 }
 
 // PrintToolCallResult prints the result of a tool call.
@@ -629,6 +671,19 @@ func (u *UI) PrintToolCallResult(tc ToolCallInfo) {
 		statusStyle.Render(statusText),
 		durationStyle.Render("("+tc.Duration+")"))
 
+	// Tools with a registered renderer format their own output
+	if r, ok := LookupToolRenderer(tc.Name); ok {
+		if rendered, ok := r.Render(tc, getTerminalWidth()-len(indent)-2); ok {
+			if rendered != "" {
+				for _, line := range strings.Split(rendered, "\n") {
+					u.printf("%s  %s\n", indent, line)
+				}
+			}
+			u.println()
+			return
+		}
+	}
+
 	// Parse bash tool JSON output to extract stdout/stderr
 	output := tc.Output
 	isError := tc.Error != ""
@@ -639,11 +694,17 @@ func (u *UI) PrintToolCallResult(tc ToolCallInfo) {
 			ExitCode int    `json:"exit_code"`
 			Error    string `json:"error"`
 		}
+		var stderr string
 		if err := json.Unmarshal([]byte(output), &bashResult); err == nil {
 			// Successfully parsed bash JSON - use stdout/stderr
 			if bashResult.Error != "" {
 				output = bashResult.Error
 				isError = true
+			} else if bashResult.Stderr != "" && u.toolOutput.Strategy == TruncateSmart {
+				// Show both streams so stderr lines can be kept preferentially
+				output = bashResult.Stdout
+				stderr = bashResult.Stderr
+				isError = bashResult.ExitCode != 0
 			} else if bashResult.Stderr != "" {
 				output = bashResult.Stderr
 				isError = bashResult.ExitCode != 0
@@ -651,7 +712,7 @@ func (u *UI) PrintToolCallResult(tc ToolCallInfo) {
 				output = bashResult.Stdout
 			}
 		}
-		u.printCommandOutput(output, isError)
+		u.printCommandOutput(output, stderr, isError)
 	}
 
 	u.println() // Blank line after each tool call
@@ -853,17 +914,25 @@ func (u *UI) formatTaskList(tasks []taskItem, width int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (u *UI) printCommandOutput(output string, isError bool) {
+// printCommandOutput prints tool output, truncated per the UI's tool output
+// options. stderr, if set, is printed after output and preferred by the
+// smart strategy.
+func (u *UI) printCommandOutput(output, stderr string, isError bool) {
 	indent := u.indent()
 	clean := cleanText(output)
-	if strings.TrimSpace(clean) == "" {
+	cleanStderr := cleanText(stderr)
+	if strings.TrimSpace(clean) == "" && strings.TrimSpace(cleanStderr) == "" {
 		return
 	}
 
-	// Check if output is JSON and render with lipgloss components
+	// Check if output is JSON and render with lipgloss components. The
+	// rendering summarizes nested values, so verbose mode indents it instead.
 	trimmed := strings.TrimSpace(clean)
-	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
-		if rendered, ok := JSONToRenderedOutput(trimmed); ok {
+	if cleanStderr == "" && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+		var pretty bytes.Buffer
+		if u.verbose && json.Indent(&pretty, []byte(trimmed), "", "  ") == nil {
+			clean = pretty.String()
+		} else if rendered, ok := JSONToRenderedOutput(trimmed); ok {
 			for _, line := range strings.Split(rendered, "\n") {
 				fmt.Println("  " + line)
 			}
@@ -871,31 +940,52 @@ func (u *UI) printCommandOutput(output string, isError bool) {
 		}
 	}
 
-	lines := strings.Split(clean, "\n")
+	var lines []string
+	if strings.TrimSpace(clean) != "" {
+		lines = strings.Split(strings.TrimRight(clean, "\n"), "\n")
+	}
+	stderrFrom := len(lines)
+	if strings.TrimSpace(cleanStderr) != "" {
+		lines = append(lines, strings.Split(strings.TrimRight(cleanStderr, "\n"), "\n")...)
+	}
 
 	// Truncate long output
-	maxLines := 20
-	truncated := false
-	if len(lines) > maxLines {
-		truncated = true
-		// Show first 10 and last 5
-		head := lines[:10]
-		tail := lines[len(lines)-5:]
-		lines = append(head, fmt.Sprintf("  ... (%d lines omitted) ...", len(lines)-15))
-		lines = append(lines, tail...)
+	display := truncateLines(lines, u.toolOutput, 20, TruncateHeadTail, func(i int) bool { return i >= stderrFrom })
+	if u.verbose {
+		display = allLines(lines)
 	}
 
 	outputStyle := lipgloss.NewStyle().Foreground(colorTextDim)
 	if isError {
 		outputStyle = lipgloss.NewStyle().Foreground(colorError)
 	}
+	stderrStyle := lipgloss.NewStyle().Foreground(colorError)
+	hintStyle := lipgloss.NewStyle().Foreground(colorMuted).Italic(true)
 
-	for _, line := range lines {
-		u.println(outputStyle.Render(indent + "  " + line))
+	truncated := false
+	i := 0
+	for _, dl := range display {
+		if dl.Omitted > 0 {
+			truncated = true
+			u.println(hintStyle.Render(indent + "  " + dl.Text))
+			i += dl.Omitted
+			continue
+		}
+		style := outputStyle
+		if i >= stderrFrom {
+			style = stderrStyle
+		}
+		text := dl.Text
+		if u.verbose {
+			text = ansi.Wrap(text, wrapWidth(indent+"  "), "")
+		}
+		for _, line := range strings.Split(text, "\n") {
+			u.println(style.Render(indent + "  " + line))
+		}
+		i++
 	}
 
 	if truncated {
-		hintStyle := lipgloss.NewStyle().Foreground(colorMuted).Italic(true)
 		u.println(hintStyle.Render(indent + "  (output truncated)"))
 	}
 }
@@ -958,6 +1048,24 @@ func (u *UI) PrintThinkingDone(duration string) {
 	u.println()
 }
 
+// PrintCacheStatus prints whether a response was served from the response
+// cache.
+func (u *UI) PrintCacheStatus(hit bool) {
+	style := lipgloss.NewStyle().Foreground(colorMuted)
+	status := "Response cache miss"
+	if hit {
+		status = "Response cache hit"
+	}
+	u.println(style.Render(u.indent() + status))
+}
+
+// PrintRateLimitStatus prints the provider rate limiter's state as a call
+// is let through.
+func (u *UI) PrintRateLimitStatus(status string) {
+	style := lipgloss.NewStyle().Foreground(colorMuted)
+	u.println(style.Render(u.indent() + status))
+}
+
 // PrintSubAgentStart prints the header for a sub-agent call.
 func (u *UI) PrintSubAgentStart(agentHandle, prompt string) {
 	indent := u.indent()
@@ -973,8 +1081,14 @@ func (u *UI) PrintSubAgentStart(agentHandle, prompt string) {
 		handleStyle.Render(agentHandle),
 		labelStyle.Render("sub-agent"))
 
-	// Show truncated prompt
-	if prompt != "" {
+	// Show the prompt, truncated unless verbose
+	if prompt != "" && u.verbose {
+		promptStyle := lipgloss.NewStyle().Foreground(colorTextDim).Italic(true)
+		wrapped := ansi.Wrap(cleanText(prompt), wrapWidth(indent+"  "), "")
+		for _, line := range strings.Split(wrapped, "\n") {
+			u.printf("%s  %s\n", indent, promptStyle.Render(line))
+		}
+	} else if prompt != "" {
 		promptStyle := lipgloss.NewStyle().Foreground(colorTextDim).Italic(true)
 		displayPrompt := prompt
 		if len(displayPrompt) > 80 {